@@ -145,6 +145,9 @@ type DeliveryResponse struct {
 	StatusCode   int       `json:"statusCode"`
 	Success      bool      `json:"success"`
 	DispatchedAt time.Time `json:"dispatchedAt"`
+	// TicketKey and TicketURL identify the issue created by ticketing outputs (Jira, Github)
+	TicketKey string `json:"ticketKey,omitempty"`
+	TicketURL string `json:"ticketUrl,omitempty"`
 }
 
 // UpdateAlertStatusOutput is an alias for an alert summary
@@ -71,6 +71,8 @@ type SendTestAlertOutput struct {
 	StatusCode   int       `json:"statusCode"`
 	Success      bool      `json:"success"`
 	DispatchedAt time.Time `json:"dispatchedAt"`
+	// Payload is the rendered payload template of the output (if any) so users can preview it
+	Payload string `json:"payload,omitempty"`
 }
 
 // DeliverAlertInput sends an alert to the specified destinations
@@ -186,4 +188,23 @@ type Alert struct {
 
 	// IsResent is a flag set to indicate the alert is not new
 	IsResent bool `json:"isResent,omitempty"`
+
+	// IsDigest is a flag set to indicate the alert summarizes the alerts batched by an output's digest policy
+	IsDigest bool `json:"isDigest,omitempty"`
+
+	// DigestAlertIDs are the ids of the alerts a digest summarizes
+	DigestAlertIDs []string `json:"digestAlertIds,omitempty"`
+
+	// DeliveryOutputID is the only output of an alert deferred or batched by the output's delivery policy
+	DeliveryOutputID string `json:"deliveryOutputId,omitempty" validate:"omitempty,uuid4"`
+}
+
+// DigestFlushMessage is queued to deliver the digest of an output once its window closes.
+//
+// Example:
+// {
+//     "digestOutputId": "1954ae35-f896-4d55-941f-f596ea80da86"
+// }
+type DigestFlushMessage struct {
+	DigestOutputID string `json:"digestOutputId" validate:"required,uuid4"`
 }
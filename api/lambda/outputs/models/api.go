@@ -18,6 +18,10 @@ package models
  * along with this program.  If not, see <https://www.gnu.org/licenses/>.
  */
 
+import (
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+)
+
 // LambdaInput is the invocation event expected by the Lambda function.
 //
 // Exactly one action must be specified.
@@ -28,6 +32,7 @@ type LambdaInput struct {
 	DeleteOutput          *DeleteOutputInput          `json:"deleteOutput"`
 	GetOutputs            *GetOutputsInput            `json:"getOutputs"`
 	GetOutputsWithSecrets *GetOutputsWithSecretsInput `json:"getOutputsWithSecrets"`
+	RouteAlert            *RouteAlertInput            `json:"routeAlert"`
 }
 
 // AddOutputInput adds a new encrypted alert output to DynamoDB.
@@ -45,8 +50,10 @@ type LambdaInput struct {
 type AddOutputInput struct {
 	UserID             *string       `json:"userId" validate:"required,uuid4"`
 	DisplayName        *string       `json:"displayName" validate:"required,min=1,excludesall='<>&\""`
-	OutputConfig       *OutputConfig `json:"outputConfig" validate:"required"`
-	DefaultForSeverity []*string     `json:"defaultForSeverity"`
+	OutputConfig       *OutputConfig       `json:"outputConfig" validate:"required"`
+	DefaultForSeverity []*string           `json:"defaultForSeverity"`
+	RoutingRules       []*AlertRoutingRule `json:"routingRules,omitempty" validate:"omitempty,dive,required"`
+	DeliveryPolicy     *DeliveryPolicy     `json:"deliveryPolicy,omitempty"`
 }
 
 // AddOutputOutput returns a randomly generated UUID for the output.
@@ -84,8 +91,10 @@ type UpdateOutputInput struct {
 	UserID             *string       `json:"userId" validate:"required,uuid4"`
 	DisplayName        *string       `json:"displayName" validate:"omitempty,min=1,excludesall='<>&\""`
 	OutputID           *string       `json:"outputId" validate:"required,uuid4"`
-	OutputConfig       *OutputConfig `json:"outputConfig"`
-	DefaultForSeverity []*string     `json:"defaultForSeverity"`
+	OutputConfig       *OutputConfig       `json:"outputConfig"`
+	DefaultForSeverity []*string           `json:"defaultForSeverity"`
+	RoutingRules       []*AlertRoutingRule `json:"routingRules" validate:"omitempty,dive,required"`
+	DeliveryPolicy     *DeliveryPolicy     `json:"deliveryPolicy"`
 }
 
 // UpdateOutputOutput returns the new updated output
@@ -131,6 +140,52 @@ type GetOutputsWithSecretsInput struct {
 // }
 type GetOutputsOutput = []*AlertOutput
 
+// RouteAlertInput returns the outputs an alert would be delivered to without delivering it.
+//
+// Example:
+// {
+//     "routeAlert": {
+//         "alert": {
+//             "analysisId": "AWS.CloudTrail.IAMChange",
+//             "type": "RULE",
+//             "severity": "HIGH",
+//             "logTypes": ["AWS.CloudTrail"],
+//             "tags": ["iam"]
+//         }
+//     }
+// }
+type RouteAlertInput struct {
+	Alert *deliveryModels.Alert `json:"alert" validate:"required,structonly"`
+}
+
+// RouteAlertOutput is the list of outputs an alert would be delivered to
+type RouteAlertOutput = []*AlertRoute
+
+// The reasons an alert is routed to an output
+const (
+	// RouteReasonDestinations is set when the detection sets the destinations of the alert dynamically
+	RouteReasonDestinations = "DESTINATIONS"
+	// RouteReasonOutputIds is set when the detection overrides the outputs of its alerts
+	RouteReasonOutputIds = "OUTPUT_IDS"
+	// RouteReasonRoutingRule is set when a routing rule of the output matches the alert
+	RouteReasonRoutingRule = "ROUTING_RULE"
+	// RouteReasonDefaultForSeverity is set when the output is the default for the severity of the alert
+	RouteReasonDefaultForSeverity = "DEFAULT_FOR_SEVERITY"
+	// RouteReasonDeliveryPolicy is set when the delivery policy of the output deferred or batched the alert
+	RouteReasonDeliveryPolicy = "DELIVERY_POLICY"
+)
+
+// AlertRoute is an output an alert is routed to
+type AlertRoute struct {
+	OutputID    *string `json:"outputId"`
+	DisplayName *string `json:"displayName"`
+	OutputType  *string `json:"outputType"`
+	// Reason is why the alert is routed to the output
+	Reason string `json:"reason"`
+	// RoutingRule is the index of the matching routing rule of the output
+	RoutingRule *int `json:"routingRule,omitempty"`
+}
+
 // AlertOutput contains the information for alert output configuration
 type AlertOutput struct {
 
@@ -161,6 +216,86 @@ type AlertOutput struct {
 
 	// DefaultForSeverity defines the alert severities that will be forwarded through this output
 	DefaultForSeverity []*string `json:"defaultForSeverity"`
+
+	// RoutingRules is an ordered list of conditions that route alerts to this output.
+	// The first matching rule decides if an alert is delivered, DefaultForSeverity applies if no rule matches.
+	RoutingRules []*AlertRoutingRule `json:"routingRules,omitempty"`
+
+	// DeliveryPolicy throttles, batches or defers the alerts delivered through this output
+	DeliveryPolicy *DeliveryPolicy `json:"deliveryPolicy,omitempty"`
+}
+
+// DeliveryPolicy controls the pace of alert delivery to an output.
+//
+// Policies apply in order: quiet hours defer alerts, digests collapse the remaining alerts into summaries
+// and rate limits defer alerts that exceed the rate of the output.
+//
+// Example:
+// {
+//     "rateLimit": {"burst": 10, "perMinute": 2},
+//     "digest": {"windowSeconds": 600},
+//     "quietHours": {"timezone": "Europe/Athens", "start": "22:00", "end": "08:00", "bypassSeverities": ["CRITICAL"]}
+// }
+type DeliveryPolicy struct {
+	RateLimit  *RateLimitPolicy  `json:"rateLimit,omitempty"`
+	Digest     *DigestPolicy     `json:"digest,omitempty"`
+	QuietHours *QuietHoursPolicy `json:"quietHours,omitempty"`
+}
+
+// RateLimitPolicy is a token bucket limiting the rate of alerts delivered to an output
+type RateLimitPolicy struct {
+	// Burst is the number of alerts that can be delivered at once
+	Burst int `json:"burst" validate:"min=1"`
+	// PerMinute is the number of alerts per minute the output can sustain
+	PerMinute float64 `json:"perMinute" validate:"gt=0"`
+}
+
+// DigestPolicy collapses the alerts of an output within a time window into a single summary alert
+type DigestPolicy struct {
+	// WindowSeconds is the duration of a digest window
+	WindowSeconds int `json:"windowSeconds" validate:"min=60,max=86400"`
+}
+
+// QuietHoursPolicy defers alerts delivered to an output during a daily time range
+type QuietHoursPolicy struct {
+	// Timezone is the IANA time zone of the schedule (defaults to UTC)
+	Timezone string `json:"timezone,omitempty" validate:"omitempty,timezone"`
+	// Start is the time quiet hours start (i.e. "22:00")
+	Start string `json:"start" validate:"required,clock"`
+	// End is the time quiet hours end (i.e. "08:00"). If End is before Start quiet hours end on the next day.
+	End string `json:"end" validate:"required,clock"`
+	// Days are the days of the week quiet hours start (defaults to every day)
+	Days []string `json:"days,omitempty" validate:"omitempty,dive,oneof=Sunday Monday Tuesday Wednesday Thursday Friday Saturday"`
+	// BypassSeverities are alert severities that are delivered during quiet hours (defaults to CRITICAL)
+	BypassSeverities []string `json:"bypassSeverities,omitempty" validate:"omitempty,dive,oneof=INFO LOW MEDIUM HIGH CRITICAL"`
+}
+
+// AlertRoutingRule matches alerts to route to an output.
+//
+// An alert matches a rule if it matches all of the non-empty conditions of the rule.
+// A condition matches if any of its values matches the alert.
+//
+// Example:
+// {
+//     "severities": ["HIGH", "CRITICAL"],
+//     "logTypes": ["AWS.CloudTrail"],
+//     "tags": ["iam"]
+// }
+type AlertRoutingRule struct {
+	// Severities of the alert
+	Severities []string `json:"severities,omitempty" validate:"omitempty,dive,oneof=INFO LOW MEDIUM HIGH CRITICAL"`
+	// LogTypes of the rule that triggered the alert
+	LogTypes []string `json:"logTypes,omitempty" validate:"omitempty,dive,required"`
+	// ResourceTypes of the policy that triggered the alert
+	ResourceTypes []string `json:"resourceTypes,omitempty" validate:"omitempty,dive,required"`
+	// Tags of the rule or policy that triggered the alert (case insensitive)
+	Tags []string `json:"tags,omitempty" validate:"omitempty,dive,required"`
+	// AnalysisIDs are patterns for the ID of the rule or policy that triggered the alert (i.e. "AWS.CloudTrail.*")
+	AnalysisIDs []string `json:"analysisIds,omitempty" validate:"omitempty,dive,required,globPattern"`
+	// AlertTypes of the alert
+	AlertTypes []string `json:"alertTypes,omitempty" validate:"omitempty,dive,oneof=RULE POLICY RULE_ERROR"`
+	// Exclude prevents matching alerts from being delivered to the output
+	Exclude bool `json:"exclude,omitempty"`
 }
 
 // OutputConfig contains the configuration for the output
@@ -194,11 +329,24 @@ type OutputConfig struct {
 
 	// CustomWebhook contains the configuration for a Custom Webhook alert output
 	CustomWebhook *CustomWebhookConfig `json:"customWebhook,omitempty"`
+
+	// SMTP contains the configuration for email alert output
+	SMTP *SMTPConfig `json:"smtp,omitempty"`
+
+	// GoogleChat contains the configuration for Google Chat alert output
+	GoogleChat *GoogleChatConfig `json:"googleChat,omitempty"`
+
+	// Mattermost contains the configuration for Mattermost alert output
+	Mattermost *MattermostConfig `json:"mattermost,omitempty"`
+
+	// Discord contains the configuration for Discord alert output
+	Discord *DiscordConfig `json:"discord,omitempty"`
 }
 
 // SlackConfig defines options for each Slack output.
 type SlackConfig struct {
-	WebhookURL string `json:"webhookURL" validate:"omitempty,url"` // https://hooks.slack.com/services/...
+	WebhookURL string           `json:"webhookURL" validate:"omitempty,url"` // https://hooks.slack.com/services/...
+	Template   *PayloadTemplate `json:"template,omitempty"`
 }
 
 // SnsConfig defines options for each SNS topic output
@@ -236,7 +384,8 @@ type OpsgenieConfig struct {
 
 // MsTeamsConfig defines options for each MsTeams output
 type MsTeamsConfig struct {
-	WebhookURL string `json:"webhookURL" validate:"omitempty,url"`
+	WebhookURL string           `json:"webhookURL" validate:"omitempty,url"`
+	Template   *PayloadTemplate `json:"template,omitempty"`
 }
 
 // SqsConfig defines options for each Sqs topic output
@@ -252,5 +401,58 @@ type AsanaConfig struct {
 
 // CustomWebhookConfig defines options for each CustomWebhook output
 type CustomWebhookConfig struct {
-	WebhookURL string `json:"webhookURL" validate:"omitempty,url"`
+	WebhookURL string           `json:"webhookURL" validate:"omitempty,url"`
+	Template   *PayloadTemplate `json:"template,omitempty"`
+}
+
+// SMTPConfig defines options for each email output
+type SMTPConfig struct {
+	Host string `json:"host" validate:"omitempty,hostname_rfc1123|ip"`
+	// Port defaults to 587 for STARTTLS and NONE and 465 for TLS
+	Port int `json:"port,omitempty" validate:"omitempty,min=1,max=65535"`
+	// Security is the encryption of the connection (defaults to STARTTLS)
+	Security string `json:"security,omitempty" validate:"omitempty,oneof=NONE STARTTLS TLS"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	From     string `json:"from" validate:"omitempty,email"`
+	// To and Cc are the recipients of the alert emails
+	To []string `json:"to" validate:"omitempty,dive,email"`
+	Cc []string `json:"cc,omitempty" validate:"omitempty,dive,email"`
+}
+
+// GoogleChatConfig defines options for each Google Chat output
+type GoogleChatConfig struct {
+	WebhookURL string           `json:"webhookURL" validate:"omitempty,url"` // https://chat.googleapis.com/v1/spaces/...
+	Template   *PayloadTemplate `json:"template,omitempty"`
+}
+
+// MattermostConfig defines options for each Mattermost output
+type MattermostConfig struct {
+	WebhookURL string           `json:"webhookURL" validate:"omitempty,url"` // https://mattermost.example.com/hooks/...
+	Template   *PayloadTemplate `json:"template,omitempty"`
+}
+
+// DiscordConfig defines options for each Discord output
+type DiscordConfig struct {
+	WebhookURL string           `json:"webhookURL" validate:"omitempty,url"` // https://discord.com/api/webhooks/...
+	Template   *PayloadTemplate `json:"template,omitempty"`
+}
+
+// PayloadTemplate customizes the HTTP request sent by webhook outputs
+type PayloadTemplate struct {
+	// Body is a Go text/template rendered with the alert notification.
+	// If empty the default payload of the output is sent.
+	Body string `json:"body,omitempty"`
+	// Headers are added to the HTTP request
+	Headers []*PayloadHeader `json:"headers,omitempty" validate:"omitempty,dive,required"`
+	// Method is the HTTP method of the request (defaults to POST)
+	Method string `json:"method,omitempty" validate:"omitempty,oneof=POST PUT PATCH"`
+	// ContentType is the content type of the request body (defaults to application/json)
+	ContentType string `json:"contentType,omitempty"`
+}
+
+// PayloadHeader is an HTTP header added to the requests of an output
+type PayloadHeader struct {
+	Key   string `json:"key" validate:"required,excludesall= :"`
+	Value string `json:"value"`
 }
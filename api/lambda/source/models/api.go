@@ -90,6 +90,8 @@ type PutIntegrationSettings struct {
 	KmsKey                  string           `json:"kmsKey" validate:"omitempty,kmsKeyArn"`
 
 	SqsConfig *SqsConfig `json:"sqsConfig,omitempty"`
+
+	EventFilters EventFilters `json:"eventFilters,omitempty" validate:"omitempty,dive"`
 }
 
 //
@@ -117,6 +119,8 @@ type UpdateIntegrationSettingsInput struct {
 	KmsKey                  string           `json:"kmsKey" validate:"omitempty,kmsKeyArn"`
 
 	SqsConfig *SqsConfig `json:"sqsConfig,omitempty"`
+
+	EventFilters EventFilters `json:"eventFilters,omitempty" validate:"omitempty,dive"`
 }
 
 // DeleteIntegrationInput is used to delete a specific item from the database.
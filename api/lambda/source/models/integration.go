@@ -20,11 +20,15 @@ package models
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"github.com/panther-labs/panther/internal/compliance/snapshotlogs"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/pkg/stringset"
 )
 
@@ -76,12 +80,19 @@ type SourceIntegrationMetadata struct {
 	StackName string `json:"stackName,omitempty"`
 
 	SqsConfig *SqsConfig `json:"sqsConfig,omitempty"`
+
+	// optional filters applied to log events before they are stored
+	EventFilters EventFilters `json:"eventFilters,omitempty"`
 }
 
 // S3PrefixLogtypesMapping contains the logtypes Panther should parse for this s3 prefix.
 type S3PrefixLogtypesMapping struct {
 	S3Prefix string   `json:"prefix"`
 	LogTypes []string `json:"logTypes" validate:"required,min=1"`
+	// Framing overrides how objects under this prefix are split into log events.
+	// If not set, events are read one per line unless a log type requires a specific framing.
+	// Prefixes with log types that require different framings must set a framing.
+	Framing *pantherlog.FramingConfig `json:"framing,omitempty"`
 }
 
 type S3PrefixLogtypes []S3PrefixLogtypesMapping
@@ -94,6 +105,16 @@ func (pl S3PrefixLogtypes) LogTypes() []string {
 	return logTypes
 }
 
+// Validate checks the framing configuration of all prefixes
+func (pl S3PrefixLogtypes) Validate() error {
+	for _, m := range pl {
+		if err := m.Framing.Validate(); err != nil {
+			return errors.WithMessagef(err, "invalid framing for prefix %q", m.S3Prefix)
+		}
+	}
+	return nil
+}
+
 func (pl S3PrefixLogtypes) S3Prefixes() []string {
 	prefixes := make([]string, len(pl))
 	for i, m := range pl {
@@ -113,6 +134,71 @@ func (pl S3PrefixLogtypes) LongestPrefixMatch(objectKey string) (bestMatch S3Pre
 	return bestMatch, matched
 }
 
+// EventFilters configures which events of a source are dropped or trimmed before they are stored.
+type EventFilters []LogTypeEventFilters
+
+// LogTypeEventFilters configures the filters for the events of a log type.
+type LogTypeEventFilters struct {
+	LogType string `json:"logType" validate:"required"`
+	// Events matching any of the drop filters are not stored
+	DropFilters []DropFilter `json:"dropFilters,omitempty"`
+	// Paths of fields removed from events before they are stored (ie `request.headers`).
+	// Their values are removed from the indicator fields (p_any_*) too, unless other fields have the same value.
+	ExcludeFields []string `json:"excludeFields,omitempty"`
+}
+
+// DropFilter matches events by the value of a field.
+// The filter matches if the value is any of Values or if it matches Regex.
+// If the field is an array the filter matches if any of its elements matches.
+type DropFilter struct {
+	// Path of the field in the event JSON (ie `action` or `request.method`)
+	Field  string   `json:"field" validate:"required"`
+	Values []string `json:"values,omitempty"`
+	Regex  string   `json:"regex,omitempty"`
+}
+
+// ForLogType returns the filters for a log type or nil if there are none
+func (filters EventFilters) ForLogType(logType string) *LogTypeEventFilters {
+	for i := range filters {
+		if filters[i].LogType == logType {
+			return &filters[i]
+		}
+	}
+	return nil
+}
+
+// Validate checks that filters are unique for each log type and that all patterns compile
+func (filters EventFilters) Validate() error {
+	var logTypes []string
+	for _, f := range filters {
+		if f.LogType == "" {
+			return errors.New("event filters without a log type")
+		}
+		if stringset.Contains(logTypes, f.LogType) {
+			return errors.Errorf("duplicate event filters for log type %q", f.LogType)
+		}
+		logTypes = append(logTypes, f.LogType)
+		for _, drop := range f.DropFilters {
+			if drop.Field == "" {
+				return errors.Errorf("drop filter without a field for log type %q", f.LogType)
+			}
+			if len(drop.Values) == 0 && drop.Regex == "" {
+				return errors.Errorf("drop filter for field %q of log type %q has no values or regex", drop.Field, f.LogType)
+			}
+			if _, err := regexp.Compile(drop.Regex); err != nil {
+				return errors.Wrapf(err, "invalid drop filter regex for field %q of log type %q", drop.Field, f.LogType)
+			}
+		}
+		for _, field := range f.ExcludeFields {
+			// Panther fields are required to store and query events
+			if field == "" || strings.HasPrefix(field, "p_") {
+				return errors.Errorf("invalid excluded field %q for log type %q", field, f.LogType)
+			}
+		}
+	}
+	return nil
+}
+
 // Note: Don't use this for classification as the S3 source has different
 // log types per prefix defined.
 func (s *SourceIntegration) RequiredLogTypes() (logTypes []string) {
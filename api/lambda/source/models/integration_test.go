@@ -22,13 +22,15 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 )
 
 func TestS3PrefixLogtypes_LongestPrefixMatch(t *testing.T) {
 	pl := S3PrefixLogtypes{
-		{"prefixA/", []string{"Log.A"}},
-		{"prefixA/prefixB", []string{"Log.B"}},
-		{"", []string{"Log.C"}},
+		{S3Prefix: "prefixA/", LogTypes: []string{"Log.A"}},
+		{S3Prefix: "prefixA/prefixB", LogTypes: []string{"Log.B"}},
+		{S3Prefix: "", LogTypes: []string{"Log.C"}},
 	}
 
 	testcases := []struct {
@@ -50,8 +52,8 @@ func TestS3PrefixLogtypes_LongestPrefixMatch(t *testing.T) {
 
 func TestS3PrefixLogtypes_LongestPrefixMatch_ReturnNil(t *testing.T) {
 	pl := S3PrefixLogtypes{
-		{"prefixA/", []string{"Log.A"}},
-		{"prefixA/prefixB", []string{"Log.B"}},
+		{S3Prefix: "prefixA/", LogTypes: []string{"Log.A"}},
+		{S3Prefix: "prefixA/prefixB", LogTypes: []string{"Log.B"}},
 	}
 
 	_, matched := pl.LongestPrefixMatch("logs/log.json")
@@ -59,3 +61,55 @@ func TestS3PrefixLogtypes_LongestPrefixMatch_ReturnNil(t *testing.T) {
 	// No prefix matched
 	require.False(t, matched)
 }
+
+func TestS3PrefixLogtypes_Validate(t *testing.T) {
+	pl := S3PrefixLogtypes{
+		{S3Prefix: "prefixA/", LogTypes: []string{"Log.A"}},
+		{S3Prefix: "prefixB/", LogTypes: []string{"Log.B"}, Framing: &pantherlog.FramingConfig{
+			Mode: pantherlog.FramingJSON,
+		}},
+	}
+	require.NoError(t, pl.Validate())
+
+	pl = append(pl, S3PrefixLogtypesMapping{
+		S3Prefix: "prefixC/",
+		LogTypes: []string{"Log.C"},
+		Framing: &pantherlog.FramingConfig{
+			Mode:         pantherlog.FramingRegex,
+			StartPattern: "[",
+		},
+	})
+	require.Error(t, pl.Validate())
+}
+
+func TestEventFilters_Validate(t *testing.T) {
+	filters := EventFilters{
+		{
+			LogType: "AWS.VPCFlow",
+			DropFilters: []DropFilter{
+				{Field: "action", Values: []string{"ACCEPT"}},
+			},
+		},
+		{
+			LogType: "Nginx.Access",
+			DropFilters: []DropFilter{
+				{Field: "request", Regex: "^GET /healthz"},
+			},
+			ExcludeFields: []string{"httpUserAgent"},
+		},
+	}
+	require.NoError(t, filters.Validate())
+	require.Equal(t, &filters[1], filters.ForLogType("Nginx.Access"))
+	require.Nil(t, filters.ForLogType("AWS.S3ServerAccess"))
+
+	invalid := []EventFilters{
+		append(filters, LogTypeEventFilters{LogType: "AWS.VPCFlow"}),
+		{{LogType: "AWS.VPCFlow", DropFilters: []DropFilter{{Field: "action"}}}},
+		{{LogType: "AWS.VPCFlow", DropFilters: []DropFilter{{Field: "action", Regex: "["}}}},
+		{{LogType: "AWS.VPCFlow", ExcludeFields: []string{"p_event_time"}}},
+		{{ExcludeFields: []string{"action"}}},
+	}
+	for _, f := range invalid {
+		require.Error(t, f.Validate())
+	}
+}
@@ -35,10 +35,12 @@ import (
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/customlogs"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logschema"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/preprocessors"
 )
 
 type InferOpts struct {
 	SkipTest *bool
+	Parser   *string
 }
 
 var inferJsoniter = jsoniter.Config{
@@ -53,10 +55,14 @@ func Infer(logger *zap.Logger, opts *InferOpts) {
 		flag.Usage()
 	}
 
+	parser, err := inferParser(*opts.Parser)
+	if err != nil {
+		logger.Fatal("invalid parser", zap.Error(err))
+	}
+
 	var valueSchema *logschema.ValueSchema
-	var err error
 	for _, file := range inputFiles {
-		valueSchema, err = inferFromFile(valueSchema, file)
+		valueSchema, err = inferFromFile(valueSchema, parser, file)
 		if err != nil {
 			logger.Fatal("failed to generate schema", zap.Error(err))
 		}
@@ -69,26 +75,49 @@ func Infer(logger *zap.Logger, opts *InferOpts) {
 		// In order to validate that the schema generated is correct,
 		// run the parser against the logs, fail in case of error
 		for _, file := range inputFiles {
-			if err = validateSchema(valueSchema, file); err != nil {
+			if err = validateSchema(valueSchema, parser, file); err != nil {
 				logger.Fatal("failed while testing schema with file. You can specify '-skip-test' argument to skip this step", zap.Error(err))
 			}
 		}
 	}
 
-	schema, err := yaml.Marshal(logschema.Schema{Version: 0, Fields: valueSchema.Fields})
+	schema, err := yaml.Marshal(logschema.Schema{Version: 0, Parser: parser, Fields: valueSchema.Fields})
 	if err != nil {
 		logger.Fatal("failed to marshal schema", zap.Error(err))
 	}
 	fmt.Println(string(schema))
 }
 
-func inferFromFile(root *logschema.ValueSchema, file string) (*logschema.ValueSchema, error) {
+// inferParser returns the parser configuration for text logs that are not JSON
+func inferParser(name string) (*logschema.Parser, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case "kv":
+		return &logschema.Parser{KV: &preprocessors.KVConfig{}}, nil
+	case "logfmt":
+		return &logschema.Parser{Logfmt: &preprocessors.LogfmtConfig{}}, nil
+	case "cef":
+		return &logschema.Parser{CEF: &preprocessors.CEFConfig{}}, nil
+	case "leef":
+		return &logschema.Parser{LEEF: &preprocessors.LEEFConfig{}}, nil
+	default:
+		return nil, errors.Errorf("unsupported parser %q", name)
+	}
+}
+
+func inferFromFile(root *logschema.ValueSchema, parser *logschema.Parser, file string) (*logschema.ValueSchema, error) {
 	f, err := os.Open(file)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close() // nolint: errcheck
 
+	preProcessor, err := buildInferPreprocessor(parser)
+	if err != nil {
+		return nil, err
+	}
+
 	reader := bufio.NewReader(f)
 	lineNum := 0
 	run := true
@@ -107,6 +136,16 @@ func inferFromFile(root *logschema.ValueSchema, file string) (*logschema.ValueSc
 		if len(line) == 0 {
 			continue
 		}
+		if preProcessor != nil {
+			log, err := preProcessor.PreProcessLog(string(line))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse line [%d]", lineNum)
+			}
+			if log == "" {
+				continue
+			}
+			line = []byte(log)
+		}
 
 		var data map[string]interface{}
 		if err = inferJsoniter.Unmarshal(line, &data); err != nil {
@@ -122,15 +161,32 @@ func inferFromFile(root *logschema.ValueSchema, file string) (*logschema.ValueSc
 	return root, nil
 }
 
+func buildInferPreprocessor(parser *logschema.Parser) (preprocessors.Interface, error) {
+	switch {
+	case parser == nil:
+		return nil, nil
+	case parser.KV != nil:
+		return parser.KV.BuildPreprocessor()
+	case parser.Logfmt != nil:
+		return parser.Logfmt.BuildPreprocessor()
+	case parser.CEF != nil:
+		return parser.CEF.BuildPreprocessor()
+	case parser.LEEF != nil:
+		return parser.LEEF.BuildPreprocessor()
+	default:
+		return nil, nil
+	}
+}
+
 // Validates the schema. It generates a parser of the provided schema
 // and tries to parse the contents of the file.
-func validateSchema(valueSchema *logschema.ValueSchema, file string) error {
+func validateSchema(valueSchema *logschema.ValueSchema, parser *logschema.Parser, file string) error {
 	desc := logtypes.Desc{
 		Name:         "Custom.Test",
 		Description:  "Custom log test schema",
 		ReferenceURL: "-",
 	}
-	schema := &logschema.Schema{Version: 0, Fields: valueSchema.Fields}
+	schema := &logschema.Schema{Version: 0, Parser: parser, Fields: valueSchema.Fields}
 	entry, err := customlogs.Build(desc, schema)
 	if err != nil {
 		validationErrors := logschema.ValidationErrors(err)
@@ -139,7 +195,7 @@ func validateSchema(valueSchema *logschema.ValueSchema, file string) error {
 		}
 		return err
 	}
-	parser, err := entry.NewParser(nil)
+	logParser, err := entry.NewParser(nil)
 	if err != nil {
 		return err
 	}
@@ -166,7 +222,7 @@ func validateSchema(valueSchema *logschema.ValueSchema, file string) error {
 			continue
 		}
 
-		if _, err = parser.ParseLog(line); err != nil {
+		if _, err = logParser.ParseLog(line); err != nil {
 			return err
 		}
 	}
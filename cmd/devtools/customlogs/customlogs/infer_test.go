@@ -28,7 +28,7 @@ import (
 )
 
 func TestProcessLine(t *testing.T) {
-	schema, err := inferFromFile(nil, "./testdata/sample_1.jsonl")
+	schema, err := inferFromFile(nil, nil, "./testdata/sample_1.jsonl")
 	schema = schema.NonEmpty()
 	assert.NoError(t, err)
 	fd, err := ioutil.ReadFile("./testdata/schema_1.yml")
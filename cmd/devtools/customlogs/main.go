@@ -67,6 +67,7 @@ func main() {
 		opstools.SetUsage(`[INPUT_FILES...]`)
 		opts := &customlogs.InferOpts{
 			SkipTest: flag.Bool("skip-test", false, "Skips testing the schema against the logs"),
+			Parser:   flag.String("parser", "", "Parser for text logs that are not JSON (kv, logfmt, cef, leef)"),
 		}
 		if err := flag.CommandLine.Parse(os.Args[2:]); err != nil {
 			logger.Fatalf("failed to parse command line arguments")
@@ -0,0 +1,288 @@
+package main
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// This tool replays the log lines that failed classification through the current parsers.
+// It reads dead letter files from the panther_logs.panther_dead_letters table, classifies each line
+// against the log types it failed to parse as and writes the resulting JSON events to `stdout`.
+// Files can be local paths, S3 objects or S3 prefixes (ending in '/').
+// Custom log types are resolved with the log types API of the deployment in the current AWS session.
+// Lines that fail again can be written to a new dead letters file with the `-failed` flag.
+// Example usage:
+// $ deadletters s3://processed-bucket/logs/panther_dead_letters/year=2020/month=11/day=05/
+// $ deadletters -source-id 1234 -failed failed.json dead_letters.json.gz
+// $ deadletters -logtypes AWS.CloudTrail dead_letters.json.gz
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/pkg/errors"
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/panther-labs/panther/internal/compliance/snapshotlogs"
+	"github.com/panther-labs/panther/internal/core/logtypesapi"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/deadletters"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/enrichment"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
+)
+
+var (
+	debug      = flag.Bool("debug", false, "Log debug to stderr")
+	sourceID   = flag.String("source-id", "", "Only replay the lines of this source")
+	logTypes   = flag.String("logtypes", "", "Comma separated log types to classify lines with instead of the log types of each line")
+	failedFile = flag.String("failed", "", "Write the lines that fail classification again to this file")
+	region     = flag.String("region", "", "The AWS region of the S3 bucket (optional, defaults to the session region)")
+)
+
+type replay struct {
+	ctx         context.Context
+	s3Client    s3iface.S3API
+	resolver    logtypes.Resolver
+	classifiers map[string]classification.ClassifierAPI
+	out         *bufio.Writer
+	failed      *bufio.Writer
+	debugLog    *log.Logger
+
+	numLines  int
+	numEvents int
+	numFailed int
+}
+
+func main() {
+	flag.Parse()
+
+	var stderr io.Writer
+	if *debug {
+		w := bufio.NewWriter(os.Stderr)
+		defer w.Flush()
+		stderr = w
+	} else {
+		stderr = ioutil.Discard
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	sess, err := session.NewSession()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *region != "" {
+		sess.Config.Region = region
+	}
+	r := replay{
+		ctx:      context.Background(),
+		s3Client: s3.New(sess),
+		// Resolve native, snapshot and custom log types the same way the log processor does
+		resolver: logtypes.ChainResolvers(
+			registry.NativeLogTypesResolver(),
+			snapshotlogs.Resolver(),
+			&logtypesapi.Resolver{
+				LogTypesAPI: &logtypesapi.LogTypesAPILambdaClient{
+					LambdaName: logtypesapi.LambdaName,
+					LambdaAPI:  lambda.New(sess),
+					Validate:   validator.New().Struct,
+				},
+			},
+		),
+		classifiers: make(map[string]classification.ClassifierAPI),
+		out:         out,
+		debugLog:    log.New(stderr, "[DEBUG] ", log.LstdFlags),
+	}
+	if *failedFile != "" {
+		f, err := os.Create(*failedFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close() // nolint: errcheck
+		r.failed = bufio.NewWriter(f)
+		defer r.failed.Flush()
+	}
+
+	files := flag.Args()
+	if len(files) == 0 {
+		log.Fatal("no dead letter files provided")
+	}
+	for _, file := range files {
+		if err := r.replayLocation(file); err != nil {
+			log.Fatal(err)
+		}
+	}
+	log.Printf("Replayed %d lines, %d events, %d lines failed\n", r.numLines, r.numEvents, r.numFailed)
+}
+
+// replayLocation replays a local file, an S3 object or all the objects under an S3 prefix
+func (r *replay) replayLocation(location string) error {
+	if !strings.HasPrefix(location, "s3://") || !strings.HasSuffix(location, "/") {
+		return r.replayFile(location)
+	}
+	bucket, prefix := splitS3URL(location)
+	var keys []string
+	err := r.s3Client.ListObjectsV2PagesWithContext(r.ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.StringValue(obj.Key))
+		}
+		return true
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list %s", location)
+	}
+	for _, key := range keys {
+		if err := r.replayFile("s3://" + bucket + "/" + key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *replay) replayFile(location string) error {
+	r.debugLog.Printf("Replaying %s\n", location)
+	f, err := enrichment.OpenLocation(r.ctx, r.s3Client, location)
+	if err != nil {
+		return err
+	}
+	defer f.Close() // nolint: errcheck
+	return deadletters.ReadRecords(f, r.replayRecord)
+}
+
+func (r *replay) replayRecord(record *deadletters.Record) error {
+	if *sourceID != "" && record.SourceID != *sourceID {
+		return nil
+	}
+	r.numLines++
+	if *logTypes == "" && len(record.LogTypes()) == 0 {
+		// Lines that were not passed to any parser can only be replayed with the log types set by the user
+		r.numFailed++
+		err := errors.New("dead letter record has no log types, use -logtypes to replay it")
+		r.debugLog.Printf("Failed to classify line %d of %s: %s\n", record.LineNumber, record.S3ObjectKey, err)
+		return r.writeFailed(record, nil, err)
+	}
+	classifier, err := r.classifier(record)
+	if err != nil {
+		return err
+	}
+	result, err := classifier.Classify(record.Line)
+	if err != nil {
+		r.numFailed++
+		r.debugLog.Printf("Failed to classify line %d of %s: %s\n", record.LineNumber, record.S3ObjectKey, err)
+		return r.writeFailed(record, result, err)
+	}
+	jsonAPI := common.ConfigForDataLakeWriters()
+	for _, event := range result.Events {
+		event.PantherSourceID = record.SourceID
+		event.PantherSourceLabel = record.SourceLabel
+		data, err := jsonAPI.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := r.out.Write(data); err != nil {
+			return err
+		}
+		if err := r.out.WriteByte('\n'); err != nil {
+			return err
+		}
+		r.numEvents++
+	}
+	return nil
+}
+
+// writeFailed writes the record of a line that failed classification again with the new parser errors
+func (r *replay) writeFailed(record *deadletters.Record, result *classification.ClassifierResult, err error) error {
+	if r.failed == nil {
+		return nil
+	}
+	failed := deadletters.NewRecord(record.Line, result, err)
+	failed.Timestamp = record.Timestamp
+	failed.SourceID = record.SourceID
+	failed.SourceLabel = record.SourceLabel
+	failed.S3Bucket = record.S3Bucket
+	failed.S3ObjectKey = record.S3ObjectKey
+	failed.LineNumber = record.LineNumber
+	data, err := pantherlog.ConfigJSON().Marshal(failed)
+	if err != nil {
+		return err
+	}
+	if _, err := r.failed.Write(data); err != nil {
+		return err
+	}
+	return r.failed.WriteByte('\n')
+}
+
+// classifier returns a classifier for the log types of a record, classifiers are shared by records with the same log types
+func (r *replay) classifier(record *deadletters.Record) (classification.ClassifierAPI, error) {
+	var names []string
+	if *logTypes != "" {
+		names = strings.Split(*logTypes, ",")
+	} else {
+		names = record.LogTypes()
+	}
+	sort.Strings(names)
+	key := strings.Join(names, ",")
+	if c, ok := r.classifiers[key]; ok {
+		return c, nil
+	}
+	available := make(map[string]parsers.Interface, len(names))
+	for _, name := range names {
+		entry, err := r.resolver.Resolve(r.ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			r.debugLog.Printf("Skipping unknown log type %q\n", name)
+			continue
+		}
+		parser, err := entry.NewParser(nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create %q parser", name)
+		}
+		available[name] = parser
+	}
+	c := classification.NewClassifier(available)
+	r.classifiers[key] = c
+	return c, nil
+}
+
+func splitS3URL(location string) (bucket, key string) {
+	location = strings.TrimPrefix(location, "s3://")
+	if pos := strings.IndexByte(location, '/'); pos != -1 {
+		return location[:pos], location[pos+1:]
+	}
+	return location, ""
+}
@@ -22,20 +22,25 @@ import (
 	"compress/gzip"
 	"context"
 	"flag"
+	"io/ioutil"
 	"log"
 	"os"
 	"runtime"
 	"runtime/pprof"
 	"strconv"
 
+	jsoniter "github.com/json-iterator/go"
 	"go.uber.org/zap"
 
 	"github.com/panther-labs/panther/api/lambda/source/models"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/geoip"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/masking"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
 )
 
@@ -55,6 +60,13 @@ var (
 	FILE            = flag.String("file", "", "The file to process (assumed to be gzipped).")
 	LOGTYPE         = flag.String("logtype", "", "The logType.")
 	MEMORYSIZE      = flag.Int("lambdaSize", 1024, "The memory size of the lambda")
+	OUTPUTDIR       = flag.String("output-dir", "", "Write processed events to files in this directory instead of S3.")
+	GEOIPCOUNTRY    = flag.String("geoip-country-db", "", "MaxMind database used to resolve the country of ip addresses.")
+	GEOIPASN        = flag.String("geoip-asn-db", "", "MaxMind database used to resolve the ASN of ip addresses.")
+	EVENTFILTERS    = flag.String("event-filters", "", "JSON file with the event filters of the source.")
+	MASKINGCONFIG   = flag.String("masking-config", "", "JSON file with the masking rules.")
+	MASKINGKEY      = flag.String("masking-key", "", "Key used to hash masked values.")
+	STICKYLINES     = flag.Int("sticky-lines", 0, "Lock the log types of the file after classifying this many lines.")
 
 	VERBOSE = flag.Bool("verbose", false, "verbose logging")
 
@@ -65,10 +77,10 @@ var (
 func main() {
 	flag.Parse()
 
-	if *BUCKET == "" {
+	if *BUCKET == "" && *OUTPUTDIR == "" {
 		log.Fatal("-bucket not set")
 	}
-	if *TOPICARN == "" {
+	if *TOPICARN == "" && *OUTPUTDIR == "" {
 		log.Fatal("-topic not set")
 	}
 	if *QUEUEURL == "" {
@@ -102,6 +114,20 @@ func main() {
 		logTypes = logtypes.CollectNames(registry.NativeLogTypes())
 	}
 
+	var eventFilters models.EventFilters
+	if *EVENTFILTERS != "" {
+		data, err := ioutil.ReadFile(*EVENTFILTERS)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := jsoniter.Unmarshal(data, &eventFilters); err != nil {
+			log.Fatal(err)
+		}
+		if err := eventFilters.Validate(); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	dataStream := &common.DataStream{
 		Stream: logstream.NewLineStream(gzipReader, logstream.DefaultBufferSize),
 		Source: &models.SourceIntegration{
@@ -110,6 +136,7 @@ func main() {
 				IntegrationType:  models.IntegrationTypeAWS3,
 				IntegrationLabel: *flagSourceLabel,
 				S3PrefixLogTypes: models.S3PrefixLogtypes{{S3Prefix: "", LogTypes: logTypes}},
+				EventFilters:     eventFilters,
 			},
 		},
 	}
@@ -142,11 +169,42 @@ func main() {
 	zap.ReplaceGlobals(logger)
 	// Use a properly configured JSON instance with AWS Glue quirks
 	jsonAPI := common.ConfigForDataLakeWriters()
+	geoResolver, err := geoip.Load(geoip.Config{
+		CountryDatabase: *GEOIPCOUNTRY,
+		ASNDatabase:     *GEOIPASN,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if geoResolver != nil {
+		jsonAPI.RegisterExtension(pantherlog.NewValueResolverExtension(geoResolver))
+	}
 
 	// Use the global registry
-	dest := destinations.CreateS3Destination(jsonAPI)
+	resolver := registry.NativeLogTypesResolver()
+	if *OUTPUTDIR != "" {
+		common.Config.Destinations = []string{destinations.DestinationFile}
+		common.Config.FileDestinationDir = *OUTPUTDIR
+	}
+	dest, err := destinations.CreateDestination(jsonAPI, resolver)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	newProcessor := processor.NewFactory(registry.NativeLogTypesResolver())
+	newProcessor := processor.NewStickyFactory(resolver, *STICKYLINES).
+		WithNormalization(resolver, jsonAPI).
+		WithEventFilters(jsonAPI)
+	if *MASKINGCONFIG != "" {
+		config, err := masking.LoadConfig(context.Background(), nil, *MASKINGCONFIG)
+		if err != nil {
+			log.Fatal(err)
+		}
+		masker, err := masking.New(config, []byte(*MASKINGKEY))
+		if err != nil {
+			log.Fatal(err)
+		}
+		newProcessor = newProcessor.WithMasker(masker, jsonAPI)
+	}
 	err = processor.Process(context.Background(), streamChan, dest, newProcessor)
 	if err != nil {
 		log.Fatal(err)
@@ -0,0 +1,257 @@
+package parserbench
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logsamples"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// Result is the benchmark result for a log type
+type Result struct {
+	LogType string `json:"logType"`
+	Samples int    `json:"samples"`
+	// Parsing cost per log entry
+	NsPerEntry     int64   `json:"nsPerEntry"`
+	BytesPerEntry  int64   `json:"bytesPerEntry"`
+	AllocsPerEntry int64   `json:"allocsPerEntry"`
+	MBPerSec       float64 `json:"mbPerSec"`
+	// Cost of trying a log entry with the parsers of all other log types
+	FallThroughNsPerEntry     int64 `json:"fallThroughNsPerEntry"`
+	FallThroughAllocsPerEntry int64 `json:"fallThroughAllocsPerEntry"`
+}
+
+// Run benchmarks the parser of a log type with samples, running each benchmark for the duration d.
+// If fallThrough is not empty the cost of trying the samples with the parsers of these log types is also measured.
+func Run(entry logtypes.Entry, samples []string, fallThrough []logtypes.Entry, d time.Duration) (*Result, error) {
+	if len(samples) == 0 {
+		return nil, errors.Errorf("no samples for log type %q", entry.String())
+	}
+	parser, err := entry.NewParser(nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create %q parser", entry.String())
+	}
+	var parseErr error
+	parse := measure(d, func(i int) {
+		if _, err := parser.ParseLog(samples[i%len(samples)]); err != nil && parseErr == nil {
+			parseErr = errors.Wrapf(err, "failed to parse %q sample %d", entry.String(), i%len(samples))
+		}
+	})
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	size := logsamples.AverageSize(samples)
+	result := Result{
+		LogType:        entry.String(),
+		Samples:        len(samples),
+		NsPerEntry:     parse.nsPerOp(),
+		BytesPerEntry:  parse.bytesPerOp(),
+		AllocsPerEntry: parse.allocsPerOp(),
+		MBPerSec:       parse.mbPerSec(size),
+	}
+	var others []pantherlog.LogParser
+	for _, other := range fallThrough {
+		if other.String() == entry.String() {
+			continue
+		}
+		parser, err := other.NewParser(nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create %q parser", other.String())
+		}
+		others = append(others, parser)
+	}
+	if len(others) == 0 {
+		return &result, nil
+	}
+	miss := measure(d, func(i int) {
+		sample := samples[i%len(samples)]
+		for _, parser := range others {
+			_, _ = logsamples.TryParse(parser, sample)
+		}
+	})
+	result.FallThroughNsPerEntry = miss.nsPerOp()
+	result.FallThroughAllocsPerEntry = miss.allocsPerOp()
+	return &result, nil
+}
+
+// stats are the totals of a benchmark run
+type stats struct {
+	n       int
+	elapsed time.Duration
+	bytes   uint64
+	allocs  uint64
+}
+
+// measure calls fn with an increasing index for at least d and reports the totals.
+// Allocations are read from the runtime memory statistics, so benchmarks must not run concurrently.
+func measure(d time.Duration, fn func(i int)) stats {
+	// Warm up caches and lazily initialized state
+	fn(0)
+	const batchSize = 64
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	n := 0
+	for time.Since(start) < d {
+		for i := 0; i < batchSize; i++ {
+			fn(n)
+			n++
+		}
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	return stats{
+		n:       n,
+		elapsed: elapsed,
+		bytes:   after.TotalAlloc - before.TotalAlloc,
+		allocs:  after.Mallocs - before.Mallocs,
+	}
+}
+
+func (s *stats) nsPerOp() int64 {
+	return s.elapsed.Nanoseconds() / int64(s.n)
+}
+
+func (s *stats) bytesPerOp() int64 {
+	return int64(s.bytes) / int64(s.n)
+}
+
+func (s *stats) allocsPerOp() int64 {
+	return int64(s.allocs) / int64(s.n)
+}
+
+func (s *stats) mbPerSec(size int64) float64 {
+	if size <= 0 || s.elapsed <= 0 {
+		return 0
+	}
+	return (float64(size) * float64(s.n) / 1e6) / s.elapsed.Seconds()
+}
+
+// Baseline holds benchmark results by log type
+type Baseline map[string]*Result
+
+// ReadBaseline reads a baseline from a JSON file
+func ReadBaseline(filename string) (Baseline, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read baseline %q", filename)
+	}
+	baseline := Baseline{}
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, errors.Wrapf(err, "invalid baseline %q", filename)
+	}
+	return baseline, nil
+}
+
+// WriteBaseline writes benchmark results to a JSON file
+func WriteBaseline(filename string, results []*Result) error {
+	baseline := Baseline{}
+	for _, r := range results {
+		baseline[r.LogType] = r
+	}
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// Regression is a metric of a log type that exceeds its baseline value
+type Regression struct {
+	LogType  string
+	Metric   string
+	Baseline int64
+	Current  int64
+}
+
+// Change returns the relative change of the metric from the baseline
+func (r *Regression) Change() float64 {
+	return float64(r.Current-r.Baseline) / float64(r.Baseline)
+}
+
+func (r *Regression) String() string {
+	return fmt.Sprintf("%s %s: %d -> %d (%+.1f%%)", r.LogType, r.Metric, r.Baseline, r.Current, 100*r.Change())
+}
+
+// Compare checks results against a baseline.
+// A metric regresses if it exceeds its baseline value by more than threshold (i.e. 0.2 for 20%).
+// Log types missing from the baseline are ignored.
+func Compare(baseline Baseline, results []*Result, threshold float64) []*Regression {
+	var regressions []*Regression
+	for _, r := range results {
+		base, ok := baseline[r.LogType]
+		if !ok {
+			continue
+		}
+		metrics := []struct {
+			name          string
+			base, current int64
+		}{
+			{"ns/entry", base.NsPerEntry, r.NsPerEntry},
+			{"allocs/entry", base.AllocsPerEntry, r.AllocsPerEntry},
+			{"B/entry", base.BytesPerEntry, r.BytesPerEntry},
+			{"fall-through ns/entry", base.FallThroughNsPerEntry, r.FallThroughNsPerEntry},
+		}
+		for _, m := range metrics {
+			// Metrics that were not measured are skipped
+			if m.base <= 0 || m.current <= 0 {
+				continue
+			}
+			if float64(m.current) > float64(m.base)*(1+threshold) {
+				regressions = append(regressions, &Regression{
+					LogType:  r.LogType,
+					Metric:   m.name,
+					Baseline: m.base,
+					Current:  m.current,
+				})
+			}
+		}
+	}
+	sort.SliceStable(regressions, func(i, j int) bool {
+		return regressions[i].LogType < regressions[j].LogType
+	})
+	return regressions
+}
+
+// Missing returns the log types of a baseline that have no results (i.e. log types that lost their samples).
+func Missing(baseline Baseline, results []*Result) []string {
+	benchmarked := make(map[string]bool, len(results))
+	for _, r := range results {
+		benchmarked[r.LogType] = true
+	}
+	var missing []string
+	for logType := range baseline {
+		if !benchmarked[logType] {
+			missing = append(missing, logType)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,306 @@
+package main
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// This tool benchmarks the parsers of all native log types.
+// Samples are loaded from the `testdata` directories of the parsers, from files named after a log type
+// in the `-dir` directory (i.e. `AWS.VPCFlow.log`) and from the synthetic log generators of `filegen`.
+// Log types that still have no samples get samples generated from their schema if their parser accepts them.
+// Log types without any samples are skipped and reported.
+// For each log type it measures parsing throughput and allocations per log entry and the cost of a log entry
+// falling through the parsers of all other log types during classification.
+// Results can be stored as a baseline and later runs compared against it; the tool exits with an error on regressions.
+// Example usage:
+// $ parserbench -write-baseline cmd/devtools/parserbench/baseline.json
+// $ parserbench -baseline cmd/devtools/parserbench/baseline.json -threshold 0.2
+// $ parserbench -logtypes AWS.CloudTrail,Okta.SystemLog -benchtime 2s
+
+import (
+	"bufio"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/cmd/devtools/filegen"
+	"github.com/panther-labs/panther/cmd/devtools/filegen/logtype"
+	"github.com/panther-labs/panther/cmd/devtools/parserbench"
+	"github.com/panther-labs/panther/cmd/opstools"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logsamples"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
+)
+
+var (
+	debug      = flag.Bool("debug", false, "Log debug to stderr")
+	parsersDir = flag.String("parsers", "internal/log_analysis/log_processor/parsers",
+		"Load samples from the testdata of parsers in this directory")
+	samplesDir    = flag.String("dir", "", "Load samples from files named after a log type in this directory")
+	logTypes      = flag.String("logtypes", "", "Comma separated log types to benchmark (defaults to all native log types)")
+	maxSamples    = flag.Int("max-samples", 100, "Maximum number of samples per log type")
+	generate      = flag.Bool("generate", true, "Generate samples for log types with a filegen generator or from the log type schema")
+	fallThrough   = flag.Bool("fallthrough", true, "Measure the cost of samples falling through the parsers of other log types")
+	benchTime     = flag.Duration("benchtime", time.Second, "Run each benchmark for this duration")
+	baselineFile  = flag.String("baseline", "", "Compare results against the baseline in this file")
+	writeBaseline = flag.String("write-baseline", "", "Write results as a baseline to this file")
+	threshold     = flag.Float64("threshold", 0.2, "Report metrics exceeding the baseline by more than this ratio as regressions")
+)
+
+var generators = []filegen.Generator{
+	logtype.NewAWSS3ServerAccess(),
+	logtype.NewAWSCloudTrail(),
+	logtype.NewGravitationalTeleportAudit(),
+}
+
+func main() {
+	opstools.SetUsage("benchmarks log parsers and compares the results against a baseline")
+	flag.Parse()
+
+	log := opstools.MustBuildLogger(*debug)
+
+	group := registry.NativeLogTypes()
+	entries := group.Entries()
+	if *logTypes != "" {
+		entries = nil
+		for _, name := range strings.Split(*logTypes, ",") {
+			entry := group.Find(strings.TrimSpace(name))
+			if entry == nil {
+				log.Fatalf("unknown log type %q", name)
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	samples, err := logsamples.LoadSamples(*parsersDir, group)
+	if err != nil {
+		log.Fatalf("failed to load samples from %q: %s", *parsersDir, err)
+	}
+	if *samplesDir != "" {
+		if err := loadSamplesDir(samples, *samplesDir, group); err != nil {
+			log.Fatalf("failed to load samples from %q: %s", *samplesDir, err)
+		}
+	}
+	if *generate {
+		generateSamples(samples, group, *maxSamples, log)
+		generateSchemaSamples(samples, entries, *maxSamples, log)
+	}
+	samples.Limit(*maxSamples)
+
+	var others []logtypes.Entry
+	if *fallThrough {
+		others = group.Entries()
+	}
+
+	var results []*parserbench.Result
+	var skipped []string
+	for _, entry := range entries {
+		entrySamples := samples[entry.String()]
+		if len(entrySamples) == 0 {
+			skipped = append(skipped, entry.String())
+			continue
+		}
+		log.Debugf("benchmarking %s with %d samples", entry.String(), len(entrySamples))
+		result, err := parserbench.Run(entry, entrySamples, others, *benchTime)
+		if err != nil {
+			log.Fatal(err)
+		}
+		results = append(results, result)
+	}
+	sort.Strings(skipped)
+	printResults(results, skipped)
+	if len(skipped) > 0 {
+		log.Warnf("skipped %d of %d log types without samples: %s", len(skipped), len(entries), strings.Join(skipped, ", "))
+	}
+
+	if *writeBaseline != "" {
+		if err := parserbench.WriteBaseline(*writeBaseline, results); err != nil {
+			log.Fatalf("failed to write baseline: %s", err)
+		}
+		log.Infof("wrote baseline for %d log types to %s", len(results), *writeBaseline)
+	}
+
+	if *baselineFile != "" {
+		baseline, err := parserbench.ReadBaseline(*baselineFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		regressions := parserbench.Compare(baseline, results, *threshold)
+		for _, r := range regressions {
+			log.Error(r.String())
+		}
+		// Only log types selected with -logtypes are expected to have results
+		missing := parserbench.Missing(baseline, results)
+		if *logTypes != "" {
+			missing = nil
+		}
+		if len(missing) > 0 {
+			log.Errorf("no results for %d log types of the baseline: %s", len(missing), strings.Join(missing, ", "))
+		}
+		if len(regressions) > 0 || len(missing) > 0 {
+			log.Fatalf("%d regressions above %.0f%% of the baseline and %d missing log types",
+				len(regressions), 100**threshold, len(missing))
+		}
+		log.Infof("no regressions above %.0f%% of the baseline", 100**threshold)
+	}
+}
+
+func printResults(results []*parserbench.Result, skipped []string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "LOG TYPE\tSAMPLES\tNS/ENTRY\tB/ENTRY\tALLOCS/ENTRY\tMB/S\tFALL-THROUGH NS/ENTRY\tFALL-THROUGH ALLOCS/ENTRY\t")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%.2f\t%d\t%d\t\n", r.LogType, r.Samples,
+			r.NsPerEntry, r.BytesPerEntry, r.AllocsPerEntry, r.MBPerSec, r.FallThroughNsPerEntry, r.FallThroughAllocsPerEntry)
+	}
+	for _, logType := range skipped {
+		fmt.Fprintf(w, "%s\t0\t-\t-\t-\t-\t-\t-\t\n", logType)
+	}
+	_ = w.Flush()
+}
+
+// loadSamplesDir loads samples from files named after a log type (i.e. `AWS.VPCFlow.log`).
+// Each non-empty line of a file is a sample log entry.
+func loadSamplesDir(samples logsamples.Samples, dir string, group logtypes.Group) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, info := range files {
+		if info.IsDir() {
+			continue
+		}
+		name := strings.TrimSuffix(info.Name(), ".gz")
+		logType := strings.TrimSuffix(name, filepath.Ext(name))
+		if group.Find(logType) == nil {
+			continue
+		}
+		lines, err := readLines(filepath.Join(dir, info.Name()))
+		if err != nil {
+			return err
+		}
+		samples.Add(logType, lines...)
+	}
+	return nil
+}
+
+func readLines(filename string) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	if strings.HasSuffix(filename, ".gz") {
+		r, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		scanner = bufio.NewScanner(r)
+	}
+	scanner.Buffer(nil, 1024*1024)
+	var lines []string
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// generateSamples adds samples from the synthetic log generators.
+// Generated lines that the parser of the log type rejects are skipped.
+func generateSamples(samples logsamples.Samples, group logtypes.Group, rows int, log *zap.SugaredLogger) {
+	for _, gen := range generators {
+		entry := group.Find(gen.LogType())
+		if entry == nil {
+			continue
+		}
+		parser, err := entry.NewParser(nil)
+		if err != nil {
+			log.Fatalf("failed to create %q parser: %s", entry.String(), err)
+		}
+		// Files with a single row keep samples of log types that wrap records (i.e. CloudTrail) small
+		gen.WithRows(1)
+		hour := time.Now().UTC().Truncate(time.Hour)
+		n := 0
+		for i := 0; i < rows; i++ {
+			lines, err := readGenerated(gen.NewFile(hour))
+			if err != nil {
+				log.Fatalf("failed to read generated %q file: %s", gen.LogType(), err)
+			}
+			for _, line := range lines {
+				if _, err := parser.ParseLog(line); err != nil {
+					log.Debugf("generated %s sample failed to parse: %s", entry.String(), err)
+					continue
+				}
+				samples.Add(entry.String(), line)
+				n++
+			}
+		}
+		if n == 0 {
+			log.Warnf("generated no valid samples for %s", entry.String())
+			continue
+		}
+		log.Debugf("generated %d samples for %s", n, entry.String())
+	}
+}
+
+func readGenerated(f *filegen.File) ([]string, error) {
+	r, err := gzip.NewReader(f.Data)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// generateSchemaSamples generates samples from the schema of log types that have no samples.
+func generateSchemaSamples(samples logsamples.Samples, entries []logtypes.Entry, n int, log *zap.SugaredLogger) {
+	for _, entry := range entries {
+		if len(samples[entry.String()]) > 0 {
+			continue
+		}
+		generated, err := logsamples.Generate(entry, n)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(generated) == 0 {
+			log.Debugf("generated no valid samples for %s from its schema", entry.String())
+			continue
+		}
+		log.Debugf("generated %d samples for %s from its schema", len(generated), entry.String())
+		samples.Add(entry.String(), generated...)
+	}
+}
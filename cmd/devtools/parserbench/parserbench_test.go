@@ -0,0 +1,134 @@
+package parserbench
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
+)
+
+func TestCompare(t *testing.T) {
+	baseline := Baseline{
+		"Foo.Bar": {
+			LogType:               "Foo.Bar",
+			NsPerEntry:            1000,
+			AllocsPerEntry:        10,
+			BytesPerEntry:         100,
+			FallThroughNsPerEntry: 5000,
+		},
+		"Foo.Baz": {
+			LogType:        "Foo.Baz",
+			NsPerEntry:     1000,
+			AllocsPerEntry: 10,
+			BytesPerEntry:  100,
+		},
+	}
+	results := []*Result{
+		{
+			LogType:               "Foo.Bar",
+			NsPerEntry:            1300,
+			AllocsPerEntry:        12,
+			BytesPerEntry:         90,
+			FallThroughNsPerEntry: 7000,
+		},
+		{
+			// Fall-through was not measured in the baseline
+			LogType:               "Foo.Baz",
+			NsPerEntry:            1100,
+			AllocsPerEntry:        10,
+			BytesPerEntry:         100,
+			FallThroughNsPerEntry: 9000,
+		},
+		{
+			// Not in the baseline
+			LogType:    "Foo.Qux",
+			NsPerEntry: 9000,
+		},
+	}
+	regressions := Compare(baseline, results, 0.2)
+	require.Equal(t, []*Regression{
+		{LogType: "Foo.Bar", Metric: "ns/entry", Baseline: 1000, Current: 1300},
+		{LogType: "Foo.Bar", Metric: "fall-through ns/entry", Baseline: 5000, Current: 7000},
+	}, regressions)
+	require.Equal(t, "Foo.Bar ns/entry: 1000 -> 1300 (+30.0%)", regressions[0].String())
+	require.Empty(t, Compare(baseline, results, 0.5))
+}
+
+func TestBaseline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "parserbench")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "baseline.json")
+	results := []*Result{
+		{
+			LogType:        "Foo.Bar",
+			Samples:        2,
+			NsPerEntry:     1000,
+			AllocsPerEntry: 10,
+			BytesPerEntry:  100,
+			MBPerSec:       42.5,
+		},
+	}
+	require.NoError(t, WriteBaseline(filename, results))
+	baseline, err := ReadBaseline(filename)
+	require.NoError(t, err)
+	require.Equal(t, Baseline{"Foo.Bar": results[0]}, baseline)
+	require.Empty(t, Compare(baseline, results, 0))
+
+	_, err = ReadBaseline(filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+}
+
+func TestMissing(t *testing.T) {
+	baseline := Baseline{
+		"Foo.Bar": {LogType: "Foo.Bar"},
+		"Foo.Baz": {LogType: "Foo.Baz"},
+		"Foo.Qux": {LogType: "Foo.Qux"},
+	}
+	results := []*Result{{LogType: "Foo.Bar"}, {LogType: "Foo.New"}}
+	require.Equal(t, []string{"Foo.Baz", "Foo.Qux"}, Missing(baseline, results))
+	require.Empty(t, Missing(Baseline{}, results))
+}
+
+func TestRun(t *testing.T) {
+	group := registry.NativeLogTypes()
+	entry := group.Find("Nginx.Access")
+	samples := []string{
+		`10.0.1.5 - - [08/Oct/2020:12:45:00 +0000] "GET /healthz HTTP/1.1" 200 2 "-" "kube-probe/1.18"`,
+	}
+	result, err := Run(entry, samples, []logtypes.Entry{entry, group.Find("AWS.VPCFlow")}, time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, "Nginx.Access", result.LogType)
+	require.Equal(t, 1, result.Samples)
+	require.Greater(t, result.NsPerEntry, int64(0))
+	require.Greater(t, result.FallThroughNsPerEntry, int64(0))
+
+	_, err = Run(entry, []string{"foo"}, nil, time.Millisecond)
+	require.Error(t, err)
+	_, err = Run(entry, nil, nil, time.Millisecond)
+	require.Error(t, err)
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/kelseyhightower/envconfig"
 
 	"github.com/panther-labs/panther/internal/core/alert_delivery/outputs"
+	"github.com/panther-labs/panther/internal/core/alert_delivery/policy"
 	alertTable "github.com/panther-labs/panther/internal/log_analysis/alerts_api/table"
 	"github.com/panther-labs/panther/pkg/gatewayapi"
 )
@@ -48,6 +49,7 @@ type envConfig struct {
 	AlertQueueURL          string        `required:"true" split_words:"true"`
 	AlertsAPI              string        `required:"true" split_words:"true"`
 	OutputsAPI             string        `required:"true" split_words:"true"`
+	DeliveryStateTableName string        `required:"true" split_words:"true"`
 }
 
 // Globals
@@ -59,6 +61,7 @@ var (
 	outputClient         outputs.API
 	sqsClient            sqsiface.SQSAPI
 	outputsCache         *alertOutputsCache
+	deliveryStore        policy.Store
 	analysisClient       gatewayapi.API
 	softDeadlineDuration time.Duration
 )
@@ -79,6 +82,10 @@ func Setup() {
 		RuleIDCreationTimeIndexName:        env.RuleIndexName,
 		TimePartitionCreationTimeIndexName: env.TimeIndexName,
 	}
+	deliveryStore = &policy.DynamoStore{
+		TableName: env.DeliveryStateTableName,
+		Client:    dynamodb.New(awsSession),
+	}
 	analysisClient = gatewayapi.NewClient(lambdaClient, "panther-analysis-api")
 	softDeadlineDuration = 10 * time.Second
 }
@@ -20,6 +20,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-playground/validator"
 	jsoniter "github.com/json-iterator/go"
@@ -44,6 +45,15 @@ func (API) DispatchAlerts(ctx context.Context, input []*deliveryModels.DispatchA
 		return nil, err
 	}
 
+	// Queue the summaries of closed digests and defer or batch alerts according to the delivery policies of their outputs
+	now := time.Now().UTC()
+	delayed := flushDigests(getDigestFlushes(input), now)
+	delayed = append(delayed, applyDeliveryPolicies(alertOutputMap, now)...)
+	if err := requeue(delayed, env.AlertQueueURL); err != nil {
+		// Fail before delivering anything so the input is received again instead of losing the delayed messages
+		return nil, err
+	}
+
 	// Send alerts to the specified destination(s) and obtain each response status
 	dispatchStatuses := sendAlerts(ctx, alertOutputMap, outputClient)
 
@@ -70,6 +80,9 @@ func getAlerts(input []*deliveryModels.DispatchAlertsInput) []*deliveryModels.Al
 	validate := validator.New()
 
 	for _, record := range input {
+		if isDigestFlush(record.Body) {
+			continue
+		}
 		alert := &deliveryModels.Alert{}
 		if err := jsoniter.UnmarshalFromString(record.Body, alert); err != nil {
 			zap.L().Error("Failed to unmarshal item", zap.Error(err))
@@ -26,12 +26,12 @@ import (
 
 	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
 	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+	"github.com/panther-labs/panther/internal/core/alert_delivery/routing"
 	"github.com/panther-labs/panther/pkg/genericapi"
 )
 
-const alertOutputSkip = "SKIP"
-
-// getAlertOutputs - Get output ids for an alert by dynmaic destinations, destination overrides, or default severity
+// getAlertOutputs - Get output ids for an alert by dynmaic destinations, destination overrides,
+// routing rules or default severity
 func getAlertOutputs(alert *deliveryModels.Alert) ([]*outputModels.AlertOutput, error) {
 	// fetch available panther outputs
 	outputs, err := getOutputs()
@@ -39,25 +39,7 @@ func getAlertOutputs(alert *deliveryModels.Alert) ([]*outputModels.AlertOutput,
 		return nil, err
 	}
 
-	alertOutputs := []*outputModels.AlertOutput{}
-
-	// First, check if we have an override to SKIP dispatching this alert
-	if shouldSkip(alert) {
-		return alertOutputs, nil
-	}
-
-	// Next, prioritize dynamic destinations (set in the detection's python body)
-	if alertOutputs, ok := getDynamicDestinations(alert, outputs); ok {
-		return alertOutputs, nil
-	}
-
-	// Then, destination overrides (set in the detection's form)
-	if alertOutputs, ok := getDestinationOverrides(alert, outputs); ok {
-		return alertOutputs, nil
-	}
-
-	// Finally, the use the severity rating (default)
-	return getDefaultOutputs(alert, outputs), nil
+	return routing.Outputs(routing.Route(alert, outputs), outputs), nil
 }
 
 // getOutputs - Gets a list of outputs from panther (using a cache)
@@ -73,61 +55,6 @@ func getOutputs() ([]*outputModels.AlertOutput, error) {
 	return outputsCache.getOutputs(), nil
 }
 
-func shouldSkip(alert *deliveryModels.Alert) bool {
-	for _, outputID := range alert.OutputIds {
-		if outputID == alertOutputSkip {
-			return true
-		}
-	}
-	return false
-}
-
-func getDynamicDestinations(alert *deliveryModels.Alert, outputs []*outputModels.AlertOutput) ([]*outputModels.AlertOutput, bool) {
-	alertOutputs := []*outputModels.AlertOutput{}
-	if len(alert.Destinations) == 0 {
-		return alertOutputs, false
-	}
-
-	for _, output := range outputs {
-		for _, outputID := range alert.Destinations {
-			if *output.OutputID == outputID {
-				alertOutputs = append(alertOutputs, output)
-			}
-		}
-	}
-
-	return alertOutputs, len(alertOutputs) > 0
-}
-
-func getDestinationOverrides(alert *deliveryModels.Alert, outputs []*outputModels.AlertOutput) ([]*outputModels.AlertOutput, bool) {
-	alertOutputs := []*outputModels.AlertOutput{}
-	if len(alert.OutputIds) == 0 {
-		return alertOutputs, false
-	}
-
-	for _, output := range outputs {
-		for _, outputID := range alert.OutputIds {
-			if *output.OutputID == outputID {
-				alertOutputs = append(alertOutputs, output)
-			}
-		}
-	}
-
-	return alertOutputs, len(alertOutputs) > 0
-}
-
-func getDefaultOutputs(alert *deliveryModels.Alert, outputs []*outputModels.AlertOutput) []*outputModels.AlertOutput {
-	alertOutputs := []*outputModels.AlertOutput{}
-	for _, output := range outputs {
-		for _, outputSeverity := range output.DefaultForSeverity {
-			if alert.Severity == *outputSeverity {
-				alertOutputs = append(alertOutputs, output)
-			}
-		}
-	}
-	return alertOutputs
-}
-
 // fetchOutputs - performs an API query to get a list of outputs
 func fetchOutputs() ([]*outputModels.AlertOutput, error) {
 	zap.L().Debug("getting default outputs")
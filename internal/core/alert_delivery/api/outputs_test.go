@@ -82,6 +82,48 @@ func TestGetAlertOutputsFromDefaultSeverity(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
+func TestGetAlertOutputsFromRoutingRules(t *testing.T) {
+	mockClient := &testutils.LambdaMock{}
+	lambdaClient = mockClient
+	output := &outputModels.GetOutputsOutput{
+		{
+			OutputID:           aws.String("default-info"),
+			DefaultForSeverity: aws.StringSlice([]string{"INFO"}),
+			RoutingRules: []*outputModels.AlertRoutingRule{
+				{AnalysisIDs: []string{"test-*"}, Exclude: true},
+			},
+		},
+		{
+			OutputID: aws.String("routed-tag"),
+			RoutingRules: []*outputModels.AlertRoutingRule{
+				{Tags: []string{"iam"}},
+			},
+		},
+		{
+			OutputID:           aws.String("default-medium"),
+			DefaultForSeverity: aws.StringSlice([]string{"MEDIUM"}),
+		},
+	}
+	payload, err := jsoniter.Marshal(output)
+	require.NoError(t, err)
+	mockLambdaResponse := &lambda.InvokeOutput{Payload: payload}
+	// Need to expire the cache because other tests mutate this global when run in parallel
+	outputsCache = &alertOutputsCache{
+		RefreshInterval: time.Second * time.Duration(30),
+		Expiry:          time.Now().Add(time.Minute * time.Duration(-5)),
+	}
+	mockClient.On("Invoke", mock.Anything).Return(mockLambdaResponse, nil).Once()
+	alert := sampleAlert()
+	alert.OutputIds = nil
+	alert.Tags = []string{"iam"}
+
+	result, err := getAlertOutputs(alert)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "routed-tag", *result[0].OutputID)
+	mockClient.AssertExpectations(t)
+}
+
 func TestGetAlertOutputsFromOutputIds(t *testing.T) {
 	mockClient := &testutils.LambdaMock{}
 	lambdaClient = mockClient
@@ -0,0 +1,164 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"time"
+
+	"github.com/go-playground/validator"
+	jsoniter "github.com/json-iterator/go"
+	"go.uber.org/zap"
+
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+	"github.com/panther-labs/panther/internal/core/alert_delivery/policy"
+)
+
+// digestRetryDelay is the delay before flushing a digest again after a failure
+const digestRetryDelay = time.Minute
+
+// applyDeliveryPolicies - removes the outputs an alert should not be delivered to now
+//
+// Alerts deferred by quiet hours or rate limits are returned to be queued again for their output only.
+// Alerts batched in a digest are stored and the first alert of a window returns a message to flush the digest
+// when the window closes. If the delivery state cannot be updated the alert is delivered.
+func applyDeliveryPolicies(alertOutputs AlertOutputMap, now time.Time) []*delayedMessage {
+	delayed := []*delayedMessage{}
+	for alert, outputs := range alertOutputs {
+		deliverTo := []*outputModels.AlertOutput{}
+		for _, output := range outputs {
+			deliver, message := applyDeliveryPolicy(alert, output, now)
+			if deliver {
+				deliverTo = append(deliverTo, output)
+			}
+			if message != nil {
+				delayed = append(delayed, message)
+			}
+		}
+		alertOutputs[alert] = deliverTo
+	}
+	return delayed
+}
+
+func applyDeliveryPolicy(alert *deliveryModels.Alert, output *outputModels.AlertOutput, now time.Time) (bool, *delayedMessage) {
+	if !policy.Applies(output.DeliveryPolicy, alert) {
+		return true, nil
+	}
+	deliveryPolicy, outputID := output.DeliveryPolicy, *output.OutputID
+	commonFields := []zap.Field{
+		zap.Stringp("alertID", alert.AlertID),
+		zap.String("outputID", outputID),
+	}
+
+	if wait := policy.QuietHoursDelay(deliveryPolicy.QuietHours, alert.Severity, now); wait > 0 {
+		zap.L().Debug("deferring alert until quiet hours end", append(commonFields, zap.Duration("wait", wait))...)
+		return false, deferAlert(alert, outputID, wait)
+	}
+
+	if digest := deliveryPolicy.Digest; digest != nil {
+		window := time.Duration(digest.WindowSeconds) * time.Second
+		opened, err := deliveryStore.AddToDigest(outputID, policy.NewDigestEntry(alert), window, now)
+		if err != nil {
+			zap.L().Error("failed to add alert to digest", append(commonFields, zap.Error(err))...)
+			return true, nil
+		}
+		if opened {
+			return false, &delayedMessage{
+				body:  &deliveryModels.DigestFlushMessage{DigestOutputID: outputID},
+				delay: window,
+			}
+		}
+		return false, nil
+	}
+
+	if rateLimit := deliveryPolicy.RateLimit; rateLimit != nil {
+		wait, err := deliveryStore.TakeToken(outputID, rateLimit, now)
+		if err != nil {
+			zap.L().Error("failed to take rate limit token", append(commonFields, zap.Error(err))...)
+			return true, nil
+		}
+		if wait > 0 {
+			zap.L().Debug("deferring rate limited alert", append(commonFields, zap.Duration("wait", wait))...)
+			return false, deferAlert(alert, outputID, wait)
+		}
+	}
+	return true, nil
+}
+
+// deferAlert - queues a copy of an alert to be delivered to a single output later
+func deferAlert(alert *deliveryModels.Alert, outputID string, wait time.Duration) *delayedMessage {
+	// Create a shallow copy to mutate, the copy is dropped if the output is deleted before it is delivered
+	deferred := *alert
+	deferred.DeliveryOutputID = outputID
+	return &delayedMessage{body: &deferred, delay: wait}
+}
+
+// flushDigests - queues the summaries of digests whose windows have closed
+//
+// Digests with open windows are flushed again when their windows close.
+func flushDigests(outputIds []string, now time.Time) []*delayedMessage {
+	delayed := []*delayedMessage{}
+	for _, outputID := range outputIds {
+		flush := &deliveryModels.DigestFlushMessage{DigestOutputID: outputID}
+		digest, err := deliveryStore.TakeDigest(outputID, now)
+		if err != nil {
+			zap.L().Error("failed to take digest", zap.String("outputID", outputID), zap.Error(err))
+			delayed = append(delayed, &delayedMessage{body: flush, delay: digestRetryDelay})
+			continue
+		}
+		if digest == nil {
+			continue
+		}
+		if digest.WindowEnd.After(now) {
+			delayed = append(delayed, &delayedMessage{body: flush, delay: digest.WindowEnd.Sub(now)})
+			continue
+		}
+		if summary := policy.Summarize(digest, now); summary != nil {
+			delayed = append(delayed, &delayedMessage{body: summary})
+		}
+	}
+	return delayed
+}
+
+// getDigestFlushes - extracts the output ids of the digests to flush from an DispatchAlertsInput (SQSMessage)
+func getDigestFlushes(input []*deliveryModels.DispatchAlertsInput) []string {
+	outputIds := []string{}
+	validate := validator.New()
+
+	for _, record := range input {
+		if !isDigestFlush(record.Body) {
+			continue
+		}
+		flush := &deliveryModels.DigestFlushMessage{}
+		if err := jsoniter.UnmarshalFromString(record.Body, flush); err != nil {
+			zap.L().Error("Failed to unmarshal item", zap.Error(err))
+			continue
+		}
+		if err := validate.Struct(flush); err != nil {
+			zap.L().Error("invalid message received", zap.Error(err))
+			continue
+		}
+		outputIds = append(outputIds, flush.DigestOutputID)
+	}
+	return outputIds
+}
+
+func isDigestFlush(body string) bool {
+	return jsoniter.Get([]byte(body), "digestOutputId").ValueType() != jsoniter.InvalidValue
+}
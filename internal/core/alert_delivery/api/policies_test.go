@@ -0,0 +1,200 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+	"github.com/panther-labs/panther/internal/core/alert_delivery/policy"
+)
+
+const policyOutputID = "1954ae35-f896-4d55-941f-f596ea80da86"
+
+type mockDeliveryStore struct {
+	mock.Mock
+}
+
+func (m *mockDeliveryStore) TakeToken(outputID string, limit *outputModels.RateLimitPolicy, now time.Time) (time.Duration, error) {
+	args := m.Called(outputID, limit, now)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
+
+func (m *mockDeliveryStore) AddToDigest(outputID string, entry *policy.DigestEntry, window time.Duration, now time.Time) (bool, error) {
+	args := m.Called(outputID, entry, window, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockDeliveryStore) TakeDigest(outputID string, now time.Time) (*policy.Digest, error) {
+	args := m.Called(outputID, now)
+	return args.Get(0).(*policy.Digest), args.Error(1)
+}
+
+func policyOutput(deliveryPolicy *outputModels.DeliveryPolicy) *outputModels.AlertOutput {
+	return &outputModels.AlertOutput{
+		OutputID:       aws.String(policyOutputID),
+		OutputType:     aws.String("slack"),
+		DeliveryPolicy: deliveryPolicy,
+	}
+}
+
+func TestApplyDeliveryPoliciesQuietHours(t *testing.T) {
+	store := &mockDeliveryStore{}
+	deliveryStore = store
+	now := time.Date(2020, 1, 1, 23, 0, 0, 0, time.UTC)
+	output := policyOutput(&outputModels.DeliveryPolicy{
+		QuietHours: &outputModels.QuietHoursPolicy{Start: "22:00", End: "08:00"},
+	})
+	alert := sampleAlert()
+	alert.Destinations = []string{policyOutputID}
+	critical := sampleAlert()
+	critical.Severity = "CRITICAL"
+	alertOutputs := AlertOutputMap{
+		alert:    {output},
+		critical: {output},
+	}
+
+	delayed := applyDeliveryPolicies(alertOutputs, now)
+	assert.Empty(t, alertOutputs[alert])
+	assert.Equal(t, []*outputModels.AlertOutput{output}, alertOutputs[critical])
+	require.Len(t, delayed, 1)
+	assert.Equal(t, 9*time.Hour, delayed[0].delay)
+	deferred := delayed[0].body.(*deliveryModels.Alert)
+	assert.Equal(t, policyOutputID, deferred.DeliveryOutputID)
+	assert.Equal(t, []string{policyOutputID}, deferred.Destinations)
+	assert.Zero(t, deferred.RetryCount)
+	store.AssertExpectations(t)
+}
+
+func TestApplyDeliveryPoliciesDigest(t *testing.T) {
+	store := &mockDeliveryStore{}
+	deliveryStore = store
+	now := time.Now().UTC()
+	output := policyOutput(&outputModels.DeliveryPolicy{
+		Digest: &outputModels.DigestPolicy{WindowSeconds: 600},
+	})
+	alert := sampleAlert()
+
+	store.On("AddToDigest", policyOutputID, policy.NewDigestEntry(alert), 10*time.Minute, now).Return(true, nil).Once()
+	alertOutputs := AlertOutputMap{alert: {output}}
+	delayed := applyDeliveryPolicies(alertOutputs, now)
+	assert.Empty(t, alertOutputs[alert])
+	expected := []*delayedMessage{{
+		body:  &deliveryModels.DigestFlushMessage{DigestOutputID: policyOutputID},
+		delay: 10 * time.Minute,
+	}}
+	assert.Equal(t, expected, delayed)
+
+	// Only the first alert of a window flushes the digest
+	store.On("AddToDigest", policyOutputID, mock.Anything, 10*time.Minute, now).Return(false, nil).Once()
+	alertOutputs = AlertOutputMap{alert: {output}}
+	assert.Empty(t, applyDeliveryPolicies(alertOutputs, now))
+	assert.Empty(t, alertOutputs[alert])
+
+	// Alerts are delivered if the digest cannot be stored
+	store.On("AddToDigest", policyOutputID, mock.Anything, 10*time.Minute, now).Return(false, errors.New("error")).Once()
+	alertOutputs = AlertOutputMap{alert: {output}}
+	assert.Empty(t, applyDeliveryPolicies(alertOutputs, now))
+	assert.Equal(t, []*outputModels.AlertOutput{output}, alertOutputs[alert])
+
+	// Test alerts are not batched
+	testAlert := sampleAlert()
+	testAlert.IsTest = true
+	alertOutputs = AlertOutputMap{testAlert: {output}}
+	assert.Empty(t, applyDeliveryPolicies(alertOutputs, now))
+	assert.Equal(t, []*outputModels.AlertOutput{output}, alertOutputs[testAlert])
+	store.AssertExpectations(t)
+}
+
+func TestApplyDeliveryPoliciesRateLimit(t *testing.T) {
+	store := &mockDeliveryStore{}
+	deliveryStore = store
+	now := time.Now().UTC()
+	limit := &outputModels.RateLimitPolicy{Burst: 1, PerMinute: 1}
+	output := policyOutput(&outputModels.DeliveryPolicy{RateLimit: limit})
+	first, second := sampleAlert(), sampleAlert()
+
+	store.On("TakeToken", policyOutputID, limit, now).Return(time.Duration(0), nil).Once()
+	store.On("TakeToken", policyOutputID, limit, now).Return(time.Minute, nil).Once()
+	alertOutputs := AlertOutputMap{first: {output}}
+	assert.Empty(t, applyDeliveryPolicies(alertOutputs, now))
+	assert.Equal(t, []*outputModels.AlertOutput{output}, alertOutputs[first])
+
+	alertOutputs = AlertOutputMap{second: {output}}
+	delayed := applyDeliveryPolicies(alertOutputs, now)
+	assert.Empty(t, alertOutputs[second])
+	require.Len(t, delayed, 1)
+	assert.Equal(t, time.Minute, delayed[0].delay)
+	store.AssertExpectations(t)
+}
+
+func TestFlushDigests(t *testing.T) {
+	store := &mockDeliveryStore{}
+	deliveryStore = store
+	now := time.Now().UTC()
+	closed := &policy.Digest{
+		OutputID:  policyOutputID,
+		Entries:   []*policy.DigestEntry{policy.NewDigestEntry(sampleAlert())},
+		WindowEnd: now.Add(-time.Second),
+	}
+	open := &policy.Digest{
+		OutputID:  "open-output-id",
+		Entries:   []*policy.DigestEntry{policy.NewDigestEntry(sampleAlert())},
+		WindowEnd: now.Add(time.Hour),
+	}
+
+	store.On("TakeDigest", policyOutputID, now).Return(closed, nil).Once()
+	store.On("TakeDigest", "open-output-id", now).Return(open, nil).Once()
+	store.On("TakeDigest", "missing-output-id", now).Return((*policy.Digest)(nil), nil).Once()
+	store.On("TakeDigest", "failed-output-id", now).Return((*policy.Digest)(nil), errors.New("error")).Once()
+
+	delayed := flushDigests([]string{policyOutputID, "open-output-id", "missing-output-id", "failed-output-id"}, now)
+	expected := []*delayedMessage{
+		{body: policy.Summarize(closed, now)},
+		{body: &deliveryModels.DigestFlushMessage{DigestOutputID: "open-output-id"}, delay: time.Hour},
+		{body: &deliveryModels.DigestFlushMessage{DigestOutputID: "failed-output-id"}, delay: digestRetryDelay},
+	}
+	assert.Equal(t, expected, delayed)
+	store.AssertExpectations(t)
+}
+
+func TestGetDigestFlushes(t *testing.T) {
+	input := []*deliveryModels.DispatchAlertsInput{
+		{Body: `{"digestOutputId": "` + policyOutputID + `"}`},
+		{Body: `{"digestOutputId": "not-a-uuid"}`},
+		{Body: `{"analysisId": "test-rule-id"}`},
+	}
+	assert.Equal(t, []string{policyOutputID}, getDigestFlushes(input))
+	// Digest flushes are not alerts
+	assert.Empty(t, getAlerts(input[:2]))
+}
+
+func TestDelaySeconds(t *testing.T) {
+	assert.Equal(t, int64(0), delaySeconds(-time.Second))
+	assert.Equal(t, int64(2), delaySeconds(1500*time.Millisecond))
+	assert.Equal(t, int64(900), delaySeconds(time.Hour))
+}
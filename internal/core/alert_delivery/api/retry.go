@@ -26,13 +26,24 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
 	"github.com/panther-labs/panther/pkg/awsbatch/sqsbatch"
 )
 
-const maxSQSBackoff = 30 * time.Second
+const (
+	maxSQSBackoff = 30 * time.Second
+	// maxSQSDelay is the longest delay of an SQS message
+	maxSQSDelay = 15 * time.Minute
+)
+
+// delayedMessage is a message sent back to the queue after a delay
+type delayedMessage struct {
+	body  interface{}
+	delay time.Duration
+}
 
 // retry - sends a list of alerts back to the queue with random delays.
 func retry(alerts []*deliveryModels.Alert, queueURL string, minDelaySecs int, maxDelaySecs int) {
@@ -47,6 +58,43 @@ func retry(alerts []*deliveryModels.Alert, queueURL string, minDelaySecs int, ma
 	sendToSQS(input)
 }
 
+// requeue - sends messages back to the queue with their delays.
+//
+// Delays longer than SQS allows are capped, the policies of the message are evaluated again when it is received.
+func requeue(messages []*delayedMessage, queueURL string) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	entries := []*sqs.SendMessageBatchRequestEntry{}
+	for i, message := range messages {
+		body, err := jsoniter.MarshalToString(message.body)
+		if err != nil {
+			zap.L().Panic("error encoding message as JSON", zap.Error(err))
+		}
+		entries = append(entries, createEntry(body, i, delaySeconds(message.delay)))
+	}
+	input := &sqs.SendMessageBatchInput{
+		Entries:  entries,
+		QueueUrl: aws.String(queueURL),
+	}
+	if _, err := sqsbatch.SendMessageBatch(sqsClient, maxSQSBackoff, input); err != nil {
+		return errors.Wrap(err, "failed to requeue delayed messages")
+	}
+	return nil
+}
+
+func delaySeconds(delay time.Duration) int64 {
+	if delay > maxSQSDelay {
+		delay = maxSQSDelay
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	// Round up so messages are not received before their delay
+	return int64((delay + time.Second - 1) / time.Second)
+}
+
 func createInput(alerts []*deliveryModels.Alert, queueURL string, minDelaySecs int, maxDelaySecs int) *sqs.SendMessageBatchInput {
 	return &sqs.SendMessageBatchInput{
 		Entries:  createEntries(alerts, minDelaySecs, maxDelaySecs),
@@ -20,10 +20,13 @@ package api
 
 import (
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
@@ -66,3 +69,54 @@ func TestRetry(t *testing.T) {
 	retry(alerts, queueURL, 5, 6)
 	mockSQS.AssertExpectations(t)
 }
+
+func TestRequeue(t *testing.T) {
+	mockSQS := &testutils.SqsMock{}
+	sqsClient = mockSQS
+
+	alert := sampleAlert()
+	flush := &deliveryModels.DigestFlushMessage{DigestOutputID: "output-id"}
+	queueURL := "sqs-url"
+
+	alertBody, err := jsoniter.MarshalToString(alert)
+	require.NoError(t, err)
+	flushBody, err := jsoniter.MarshalToString(flush)
+	require.NoError(t, err)
+
+	input := &sqs.SendMessageBatchInput{
+		Entries: []*sqs.SendMessageBatchRequestEntry{
+			{
+				DelaySeconds: aws.Int64(int64(60)),
+				Id:           aws.String("0"),
+				MessageBody:  aws.String(alertBody),
+			},
+			{
+				DelaySeconds: aws.Int64(int64(900)),
+				Id:           aws.String("1"),
+				MessageBody:  aws.String(flushBody),
+			},
+		},
+		QueueUrl: aws.String(queueURL),
+	}
+
+	mockSQS.On("SendMessageBatch", input).Return(&sqs.SendMessageBatchOutput{}, nil).Once()
+	require.NoError(t, requeue([]*delayedMessage{
+		{body: alert, delay: time.Minute},
+		{body: flush, delay: time.Hour},
+	}, queueURL))
+	mockSQS.AssertExpectations(t)
+}
+
+func TestRequeueError(t *testing.T) {
+	mockSQS := &testutils.SqsMock{}
+	sqsClient = mockSQS
+
+	mockSQS.On("SendMessageBatch", mock.Anything).Return(&sqs.SendMessageBatchOutput{}, errors.New("sqs error")).Once()
+	err := requeue([]*delayedMessage{
+		{body: &deliveryModels.DigestFlushMessage{DigestOutputID: "output-id"}, delay: time.Minute},
+	}, "queue-url")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "sqs error")
+	mockSQS.AssertExpectations(t)
+	require.NoError(t, requeue(nil, "queue-url"))
+}
@@ -41,6 +41,8 @@ type DispatchStatus struct {
 	Success      bool
 	NeedsRetry   bool
 	DispatchedAt time.Time
+	TicketKey    string
+	TicketURL    string
 }
 
 // sendAlerts - dispatches alerts to their associated outputIds in parallel
@@ -160,6 +162,14 @@ func sendAlert(
 		response = outputClient.Asana(ctx, alert, output.OutputConfig.Asana)
 	case "customwebhook":
 		response = outputClient.CustomWebhook(ctx, alert, output.OutputConfig.CustomWebhook)
+	case "smtp":
+		response = outputClient.SMTP(ctx, alert, output.OutputConfig.SMTP)
+	case "googlechat":
+		response = outputClient.GoogleChat(ctx, alert, output.OutputConfig.GoogleChat)
+	case "mattermost":
+		response = outputClient.Mattermost(ctx, alert, output.OutputConfig.Mattermost)
+	case "discord":
+		response = outputClient.Discord(ctx, alert, output.OutputConfig.Discord)
 	default:
 		zap.L().Warn("unsupported output type", commonFields...)
 		statusChannel <- DispatchStatus{
@@ -197,5 +207,7 @@ func sendAlert(
 		Message:      response.Message,
 		NeedsRetry:   !response.Success && !response.Permanent,
 		DispatchedAt: dispatchedAt,
+		TicketKey:    response.TicketKey,
+		TicketURL:    response.TicketURL,
 	}
 }
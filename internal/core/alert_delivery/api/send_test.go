@@ -207,6 +207,8 @@ func TestSendSuccess(t *testing.T) {
 		Success:    true,
 		Message:    "successful response payload",
 		Permanent:  false,
+		TicketKey:  "QR-24",
+		TicketURL:  "https://panther-labs.atlassian.net/browse/QR-24",
 	}
 	expectedResponse := DispatchStatus{
 		Alert:        *alert,
@@ -216,6 +218,8 @@ func TestSendSuccess(t *testing.T) {
 		Message:      "successful response payload",
 		NeedsRetry:   false,
 		DispatchedAt: dispatchedAt,
+		TicketKey:    "QR-24",
+		TicketURL:    "https://panther-labs.atlassian.net/browse/QR-24",
 	}
 	ctx := context.Background()
 	mockClient.On("Slack", ctx, mock.Anything, mock.Anything).Return(response)
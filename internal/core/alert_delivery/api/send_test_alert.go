@@ -26,6 +26,8 @@ import (
 	"go.uber.org/zap"
 
 	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+	"github.com/panther-labs/panther/internal/core/alert_delivery/outputs"
 )
 
 // SendTestAlert sends a dummy alert to the specified destinations.
@@ -42,6 +44,9 @@ func (API) SendTestAlert(ctx context.Context, input *deliveryModels.SendTestAler
 		return nil, err
 	}
 
+	// Render the payload templates of the outputs so users can preview them
+	payloads := renderPayloads(alertOutputMap)
+
 	// Send alerts to the specified destination(s) and obtain each response status
 	dispatchStatuses := sendAlerts(ctx, alertOutputMap, outputClient)
 
@@ -54,12 +59,55 @@ func (API) SendTestAlert(ctx context.Context, input *deliveryModels.SendTestAler
 			StatusCode:   status.StatusCode,
 			Success:      status.Success,
 			DispatchedAt: status.DispatchedAt,
+			Payload:      payloads[status.OutputID],
 		})
 	}
 
 	return responseStatuses, nil
 }
 
+// renderPayloads renders the payload templates of outputs by output id.
+// Outputs whose template fails to render are skipped, their delivery status reports the error.
+func renderPayloads(alertOutputs AlertOutputMap) map[string]string {
+	payloads := make(map[string]string)
+	for alert, outputIds := range alertOutputs {
+		for _, output := range outputIds {
+			tpl := payloadTemplate(output.OutputConfig)
+			if tpl == nil || tpl.Body == "" {
+				continue
+			}
+			payload, err := outputs.RenderPayloadTemplate(tpl, alert)
+			if err != nil {
+				continue
+			}
+			payloads[*output.OutputID] = string(payload)
+		}
+	}
+	return payloads
+}
+
+// payloadTemplate returns the payload template of an output config
+func payloadTemplate(config *outputModels.OutputConfig) *outputModels.PayloadTemplate {
+	switch {
+	case config == nil:
+		return nil
+	case config.Slack != nil:
+		return config.Slack.Template
+	case config.MsTeams != nil:
+		return config.MsTeams.Template
+	case config.CustomWebhook != nil:
+		return config.CustomWebhook.Template
+	case config.GoogleChat != nil:
+		return config.GoogleChat.Template
+	case config.Mattermost != nil:
+		return config.Mattermost.Template
+	case config.Discord != nil:
+		return config.Discord.Template
+	default:
+		return nil
+	}
+}
+
 // generateTestAlert - genreates an alert with dummy values
 func generateTestAlert() *deliveryModels.Alert {
 	return &deliveryModels.Alert{
@@ -0,0 +1,70 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+func TestRenderPayloads(t *testing.T) {
+	alert := generateTestAlert()
+	alertOutputs := AlertOutputMap{
+		alert: {
+			{
+				OutputID:   aws.String("output-templated"),
+				OutputType: aws.String("customwebhook"),
+				OutputConfig: &outputModels.OutputConfig{
+					CustomWebhook: &outputModels.CustomWebhookConfig{
+						WebhookURL: "https://example.com/hook",
+						Template: &outputModels.PayloadTemplate{
+							Body: `{"summary":{{ json .Title }},"severity":"{{ .Severity }}"}`,
+						},
+					},
+				},
+			},
+			{
+				OutputID:   aws.String("output-invalid"),
+				OutputType: aws.String("msteams"),
+				OutputConfig: &outputModels.OutputConfig{
+					MsTeams: &outputModels.MsTeamsConfig{
+						WebhookURL: "https://example.com/teams",
+						Template: &outputModels.PayloadTemplate{
+							Body: `{"text":{{ .Title }}}`,
+						},
+					},
+				},
+			},
+			{
+				OutputID:   aws.String("output-default"),
+				OutputType: aws.String("slack"),
+				OutputConfig: &outputModels.OutputConfig{
+					Slack: &outputModels.SlackConfig{WebhookURL: "https://hooks.slack.com/services/..."},
+				},
+			},
+		},
+	}
+	require.Equal(t, map[string]string{
+		"output-templated": `{"summary":"New Alert: This is a Test Alert","severity":"INFO"}`,
+	}, renderPayloads(alertOutputs))
+}
@@ -30,6 +30,11 @@ func updateAlerts(statuses []DispatchStatus) []*alertModels.AlertSummary {
 	// create a relational mapping for alertID to a list of delivery statuses
 	alertMap := make(map[string][]*alertModels.DeliveryResponse)
 	for _, status := range statuses {
+		// Digests have no alert of their own, their delivery is recorded for each alert they summarize
+		alertIDs := status.Alert.DigestAlertIDs
+		if !status.Alert.IsDigest {
+			alertIDs = []string{*status.Alert.AlertID}
+		}
 		// convert to the response type the lambda expects
 		deliveryResponse := &alertModels.DeliveryResponse{
 			OutputID:     status.OutputID,
@@ -37,8 +42,12 @@ func updateAlerts(statuses []DispatchStatus) []*alertModels.AlertSummary {
 			StatusCode:   status.StatusCode,
 			Success:      status.Success,
 			DispatchedAt: status.DispatchedAt,
+			TicketKey:    status.TicketKey,
+			TicketURL:    status.TicketURL,
+		}
+		for _, alertID := range alertIDs {
+			alertMap[alertID] = append(alertMap[alertID], deliveryResponse)
 		}
-		alertMap[*status.Alert.AlertID] = append(alertMap[*status.Alert.AlertID], deliveryResponse)
 	}
 
 	// Init a channel
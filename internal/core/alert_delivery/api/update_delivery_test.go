@@ -134,6 +134,51 @@ func TestUpdateAlerts(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
+func TestUpdateAlertsDigest(t *testing.T) {
+	mockClient := &testutils.LambdaMock{}
+	lambdaClient = mockClient
+
+	dispatchedAt := time.Now().UTC()
+	statuses := []DispatchStatus{
+		{
+			Alert: deliveryModels.Alert{
+				Type:           deliveryModels.RuleType,
+				Severity:       "HIGH",
+				IsDigest:       true,
+				DigestAlertIDs: []string{"alert-1", "alert-2"},
+			},
+			OutputID:     "output-id",
+			Message:      "success",
+			StatusCode:   200,
+			Success:      true,
+			DispatchedAt: dispatchedAt,
+		},
+	}
+
+	mockClient.On("Invoke", mock.Anything).Return(&lambda.InvokeOutput{Payload: []byte("{}")}, nil).Times(2)
+
+	assert.Len(t, updateAlerts(statuses), 2)
+	mockClient.AssertExpectations(t)
+
+	// Each alert of the digest is updated with the delivery of the digest
+	updated := []string{}
+	for _, call := range mockClient.Calls {
+		input := alertModels.LambdaInput{}
+		require.NoError(t, jsoniter.Unmarshal(call.Arguments.Get(0).(*lambda.InvokeInput).Payload, &input))
+		updated = append(updated, input.UpdateAlertDelivery.AlertID)
+		assert.Equal(t, []*alertModels.DeliveryResponse{
+			{
+				OutputID:     "output-id",
+				Message:      "success",
+				StatusCode:   200,
+				Success:      true,
+				DispatchedAt: dispatchedAt,
+			},
+		}, input.UpdateAlertDelivery.DeliveryResponses)
+	}
+	assert.ElementsMatch(t, []string{"alert-1", "alert-2"}, updated)
+}
+
 func TestUpdateAlert(t *testing.T) {
 	mockClient := &testutils.LambdaMock{}
 	lambdaClient = mockClient
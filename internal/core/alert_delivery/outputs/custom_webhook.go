@@ -33,5 +33,8 @@ func (client *OutputClient) CustomWebhook(
 		url:  config.WebhookURL,
 		body: generateNotificationFromAlert(alert),
 	}
+	if response := postInput.applyTemplate(config.Template, alert); response != nil {
+		return response
+	}
 	return client.httpWrapper.post(ctx, postInput)
 }
@@ -0,0 +1,105 @@
+package outputs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+// Discord limits the length of embed fields
+const (
+	maxDiscordTitleSize       = 256
+	maxDiscordDescriptionSize = 4096
+	maxDiscordFieldSize       = 1024
+)
+
+// Discord sends an alert to a Discord channel through a webhook.
+func (client *OutputClient) Discord(
+	ctx context.Context,
+	alert *alertModels.Alert,
+	config *outputModels.DiscordConfig,
+) *AlertDeliveryResponse {
+
+	fields := []map[string]interface{}{
+		{
+			"name":   "Severity",
+			"value":  alert.Severity,
+			"inline": true,
+		},
+	}
+	if alert.Runbook != "" {
+		fields = append(fields, map[string]interface{}{
+			"name":   "Runbook",
+			"value":  truncate(alert.Runbook, maxDiscordFieldSize),
+			"inline": false,
+		})
+	}
+
+	payload := map[string]interface{}{
+		"username": "Panther",
+		"embeds": []map[string]interface{}{
+			{
+				"title":       truncate(generateAlertTitle(alert), maxDiscordTitleSize),
+				"url":         generateURL(alert),
+				"description": truncate(alert.AnalysisDescription, maxDiscordDescriptionSize),
+				"color":       discordColor(alert.Severity),
+				"fields":      fields,
+				"timestamp":   alert.CreatedAt.Format(time.RFC3339),
+			},
+		},
+	}
+	postInput := &PostInput{
+		url:  config.WebhookURL,
+		body: payload,
+	}
+	if response := postInput.applyTemplate(config.Template, alert); response != nil {
+		return response
+	}
+	return client.httpWrapper.post(ctx, postInput)
+}
+
+// discordColor converts the severity color to the decimal value Discord expects
+func discordColor(severity string) int64 {
+	color, err := strconv.ParseInt(strings.TrimPrefix(severityColors[severity], "#"), 16, 32)
+	if err != nil {
+		return 0
+	}
+	return color
+}
+
+// truncate shortens a string to at most size bytes without splitting characters
+func truncate(s string, size int) string {
+	if len(s) <= size {
+		return s
+	}
+	const ellipsis = "..."
+	cut := size - len(ellipsis)
+	// Do not split a multi-byte character
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + ellipsis
+}
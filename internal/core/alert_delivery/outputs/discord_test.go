@@ -0,0 +1,66 @@
+package outputs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+func TestDiscordAlert(t *testing.T) {
+	// Discord webhooks reply with no content
+	server, received := newWebhookServer(t, http.StatusNoContent)
+	client := &OutputClient{httpWrapper: &HTTPWrapper{httpClient: server.Client()}}
+
+	response := client.Discord(context.Background(), chatAlert(), &outputModels.DiscordConfig{WebhookURL: server.URL})
+	require.NotNil(t, response)
+	assert.True(t, response.Success)
+
+	expected := map[string]interface{}{
+		"username": "Panther",
+		"embeds": []interface{}{
+			map[string]interface{}{
+				"title":       "New Alert: ruleName",
+				"url":         "https://panther.io/alerts/alertId",
+				"description": "description",
+				"color":       float64(0xcb2e2e),
+				"timestamp":   "2019-08-03T11:40:13Z",
+				"fields": []interface{}{
+					map[string]interface{}{"name": "Severity", "value": "HIGH", "inline": true},
+					map[string]interface{}{"name": "Runbook", "value": "runbook", "inline": false},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, *received)
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "abc", truncate("abc", 3))
+	assert.Equal(t, "a...", truncate("abcde", 4))
+	// Multi-byte characters are not split
+	assert.Equal(t, "a...", truncate("aé"+strings.Repeat("b", 10), 5))
+}
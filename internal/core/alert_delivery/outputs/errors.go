@@ -33,6 +33,11 @@ type AlertDeliveryResponse struct {
 
 	// Success is true if we determine the request executed successfully. False otherwise.
 	Success bool
+
+	// TicketKey and TicketURL identify the issue created by ticketing outputs.
+	// They are set only if the delivery was successful.
+	TicketKey string
+	TicketURL string
 }
 
 func (e *AlertDeliveryResponse) Error() string { return e.Message }
@@ -20,6 +20,7 @@ package outputs
 
 import (
 	"context"
+	"strconv"
 	"strings"
 
 	jsoniter "github.com/json-iterator/go"
@@ -63,5 +64,17 @@ func (client *OutputClient) Github(
 		body:    githubRequest,
 		headers: requestHeader,
 	}
-	return client.httpWrapper.post(ctx, postInput)
+	response := client.httpWrapper.post(ctx, postInput)
+	if response != nil && response.Success {
+		// Record the created issue so its status can be synced with the alert
+		var issue struct {
+			Number  int    `json:"number"`
+			HTMLURL string `json:"html_url"`
+		}
+		if err := jsoniter.UnmarshalFromString(response.Message, &issue); err == nil && issue.Number != 0 {
+			response.TicketKey = strconv.Itoa(issue.Number)
+			response.TicketURL = issue.HTMLURL
+		}
+	}
+	return response
 }
@@ -25,6 +25,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
 	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
@@ -72,3 +73,25 @@ func TestGithubAlert(t *testing.T) {
 	assert.Nil(t, client.Github(ctx, alert, githubConfig))
 	httpWrapper.AssertExpectations(t)
 }
+
+func TestGithubAlertTicket(t *testing.T) {
+	httpWrapper := &mockHTTPWrapper{}
+	client := &OutputClient{httpWrapper: httpWrapper}
+	alert := &alertModels.Alert{
+		AlertID:    aws.String("alertId"),
+		AnalysisID: "policyId",
+		Type:       alertModels.PolicyType,
+		Severity:   "INFO",
+	}
+	ctx := context.Background()
+	httpWrapper.On("post", ctx, mock.Anything).Return(&AlertDeliveryResponse{
+		StatusCode: 201,
+		Success:    true,
+		Message:    `{"id":1,"number":1347,"html_url":"https://github.com/profile/reponame/issues/1347"}`,
+	})
+
+	response := client.Github(ctx, alert, githubConfig)
+	assert.Equal(t, "1347", response.TicketKey)
+	assert.Equal(t, "https://github.com/profile/reponame/issues/1347", response.TicketURL)
+	httpWrapper.AssertExpectations(t)
+}
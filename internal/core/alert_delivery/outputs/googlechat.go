@@ -0,0 +1,57 @@
+package outputs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+// GoogleChat sends an alert to a Google Chat space through an incoming webhook.
+func (client *OutputClient) GoogleChat(
+	ctx context.Context,
+	alert *alertModels.Alert,
+	config *outputModels.GoogleChatConfig,
+) *AlertDeliveryResponse {
+
+	// Google Chat formats text with *bold* and <url|link> markup
+	lines := []string{
+		"*" + generateAlertTitle(alert) + "*",
+		"Severity: " + alert.Severity,
+	}
+	if alert.Runbook != "" {
+		lines = append(lines, "Runbook: "+alert.Runbook)
+	}
+	lines = append(lines, fmt.Sprintf("<%s|Click here to view in the Panther UI>", generateURL(alert)))
+
+	postInput := &PostInput{
+		url: config.WebhookURL,
+		body: map[string]interface{}{
+			"text": strings.Join(lines, "\n"),
+		},
+	}
+	if response := postInput.applyTemplate(config.Template, alert); response != nil {
+		return response
+	}
+	return client.httpWrapper.post(ctx, postInput)
+}
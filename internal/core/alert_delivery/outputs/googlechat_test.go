@@ -0,0 +1,86 @@
+package outputs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+func chatAlert() *alertModels.Alert {
+	createdAt, _ := time.Parse(time.RFC3339, "2019-08-03T11:40:13Z")
+	return &alertModels.Alert{
+		AlertID:             aws.String("alertId"),
+		AnalysisID:          "ruleId",
+		AnalysisName:        aws.String("ruleName"),
+		AnalysisDescription: "description",
+		Type:                alertModels.RuleType,
+		CreatedAt:           createdAt,
+		Severity:            "HIGH",
+		Runbook:             "runbook",
+	}
+}
+
+func TestGoogleChatAlert(t *testing.T) {
+	server, received := newWebhookServer(t, http.StatusOK)
+	client := &OutputClient{httpWrapper: &HTTPWrapper{httpClient: server.Client()}}
+
+	response := client.GoogleChat(context.Background(), chatAlert(), &outputModels.GoogleChatConfig{WebhookURL: server.URL})
+	require.NotNil(t, response)
+	assert.True(t, response.Success)
+
+	expected := map[string]interface{}{
+		"text": "*New Alert: ruleName*\nSeverity: HIGH\nRunbook: runbook\n" +
+			"<https://panther.io/alerts/alertId|Click here to view in the Panther UI>",
+	}
+	assert.Equal(t, expected, *received)
+}
+
+func TestGoogleChatAlertFailure(t *testing.T) {
+	server, _ := newWebhookServer(t, http.StatusBadRequest)
+	client := &OutputClient{httpWrapper: &HTTPWrapper{httpClient: server.Client()}}
+
+	response := client.GoogleChat(context.Background(), chatAlert(), &outputModels.GoogleChatConfig{WebhookURL: server.URL})
+	require.NotNil(t, response)
+	assert.False(t, response.Success)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestGoogleChatAlertTemplate(t *testing.T) {
+	server, received := newWebhookServer(t, http.StatusOK)
+	client := &OutputClient{httpWrapper: &HTTPWrapper{httpClient: server.Client()}}
+	config := &outputModels.GoogleChatConfig{
+		WebhookURL: server.URL,
+		Template:   &outputModels.PayloadTemplate{Body: `{"text": {{ json .Title }}}`},
+	}
+
+	response := client.GoogleChat(context.Background(), chatAlert(), config)
+	require.NotNil(t, response)
+	assert.True(t, response.Success)
+	assert.Equal(t, map[string]interface{}{"text": "New Alert: ruleName"}, *received)
+}
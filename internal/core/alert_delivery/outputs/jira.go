@@ -83,5 +83,16 @@ func (client *OutputClient) Jira(
 		body:    jiraRequest,
 		headers: requestHeader,
 	}
-	return client.httpWrapper.post(ctx, postInput)
+	response := client.httpWrapper.post(ctx, postInput)
+	if response != nil && response.Success {
+		// Record the created issue so its status can be synced with the alert
+		var issue struct {
+			Key string `json:"key"`
+		}
+		if err := jsoniter.UnmarshalFromString(response.Message, &issue); err == nil && issue.Key != "" {
+			response.TicketKey = issue.Key
+			response.TicketURL = strings.TrimSuffix(config.OrgDomain, "/") + "/browse/" + issue.Key
+		}
+	}
+	return response
 }
@@ -26,6 +26,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
 	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
@@ -91,3 +92,25 @@ func TestJiraAlert(t *testing.T) {
 	assert.Nil(t, client.Jira(ctx, alert, jiraConfig))
 	httpWrapper.AssertExpectations(t)
 }
+
+func TestJiraAlertTicket(t *testing.T) {
+	httpWrapper := &mockHTTPWrapper{}
+	client := &OutputClient{httpWrapper: httpWrapper}
+	alert := &alertModels.Alert{
+		AlertID:    aws.String("alertId"),
+		AnalysisID: "policyId",
+		Type:       alertModels.PolicyType,
+		Severity:   "INFO",
+	}
+	ctx := context.Background()
+	httpWrapper.On("post", ctx, mock.Anything).Return(&AlertDeliveryResponse{
+		StatusCode: 201,
+		Success:    true,
+		Message:    `{"id":"10000","key":"QR-24","self":"https://panther-labs.atlassian.net/rest/api/2/issue/10000"}`,
+	})
+
+	response := client.Jira(ctx, alert, jiraConfig)
+	assert.Equal(t, "QR-24", response.TicketKey)
+	assert.Equal(t, "https://panther-labs.atlassian.net/browse/QR-24", response.TicketURL)
+	httpWrapper.AssertExpectations(t)
+}
@@ -0,0 +1,71 @@
+package outputs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+// Mattermost sends an alert to a Mattermost channel through an incoming webhook.
+//
+// Mattermost accepts Slack compatible message attachments.
+func (client *OutputClient) Mattermost(
+	ctx context.Context,
+	alert *alertModels.Alert,
+	config *outputModels.MattermostConfig,
+) *AlertDeliveryResponse {
+
+	fields := []map[string]interface{}{
+		{
+			"title": "Runbook",
+			"value": alert.Runbook,
+			"short": false,
+		},
+		{
+			"title": "Severity",
+			"value": alert.Severity,
+			"short": true,
+		},
+	}
+
+	payload := map[string]interface{}{
+		"username": "Panther",
+		"attachments": []map[string]interface{}{
+			{
+				"fallback":   generateAlertTitle(alert),
+				"color":      severityColors[alert.Severity],
+				"title":      generateAlertTitle(alert),
+				"title_link": generateURL(alert),
+				"text":       alert.AnalysisDescription,
+				"fields":     fields,
+			},
+		},
+	}
+	postInput := &PostInput{
+		url:  config.WebhookURL,
+		body: payload,
+	}
+	if response := postInput.applyTemplate(config.Template, alert); response != nil {
+		return response
+	}
+	return client.httpWrapper.post(ctx, postInput)
+}
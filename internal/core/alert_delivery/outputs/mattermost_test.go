@@ -0,0 +1,57 @@
+package outputs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+func TestMattermostAlert(t *testing.T) {
+	server, received := newWebhookServer(t, http.StatusOK)
+	client := &OutputClient{httpWrapper: &HTTPWrapper{httpClient: server.Client()}}
+
+	response := client.Mattermost(context.Background(), chatAlert(), &outputModels.MattermostConfig{WebhookURL: server.URL})
+	require.NotNil(t, response)
+	assert.True(t, response.Success)
+
+	expected := map[string]interface{}{
+		"username": "Panther",
+		"attachments": []interface{}{
+			map[string]interface{}{
+				"fallback":   "New Alert: ruleName",
+				"color":      "#cb2e2e",
+				"title":      "New Alert: ruleName",
+				"title_link": "https://panther.io/alerts/alertId",
+				"text":       "description",
+				"fields": []interface{}{
+					map[string]interface{}{"title": "Runbook", "value": "runbook", "short": false},
+					map[string]interface{}{"title": "Severity", "value": "HIGH", "short": true},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, *received)
+}
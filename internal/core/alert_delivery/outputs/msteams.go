@@ -71,5 +71,8 @@ func (client *OutputClient) MsTeams(
 		url:  config.WebhookURL,
 		body: msTeamsRequestBody,
 	}
+	if response := postInput.applyTemplate(config.Template, alert); response != nil {
+		return response
+	}
 	return client.httpWrapper.post(ctx, postInput)
 }
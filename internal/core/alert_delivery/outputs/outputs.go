@@ -20,6 +20,7 @@ package outputs
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"os"
@@ -49,6 +50,10 @@ type PostInput struct {
 	url     string
 	body    interface{}
 	headers map[string]string
+	// payload is sent instead of the JSON encoded body if set
+	payload     []byte
+	method      string
+	contentType string
 }
 
 // HTTPWrapperiface is the interface for our wrapper around Golang's http client
@@ -73,6 +78,10 @@ type API interface {
 	Sns(context.Context, *alertModels.Alert, *outputModels.SnsConfig) *AlertDeliveryResponse
 	Asana(context.Context, *alertModels.Alert, *outputModels.AsanaConfig) *AlertDeliveryResponse
 	CustomWebhook(context.Context, *alertModels.Alert, *outputModels.CustomWebhookConfig) *AlertDeliveryResponse
+	SMTP(context.Context, *alertModels.Alert, *outputModels.SMTPConfig) *AlertDeliveryResponse
+	GoogleChat(context.Context, *alertModels.Alert, *outputModels.GoogleChatConfig) *AlertDeliveryResponse
+	Mattermost(context.Context, *alertModels.Alert, *outputModels.MattermostConfig) *AlertDeliveryResponse
+	Discord(context.Context, *alertModels.Alert, *outputModels.DiscordConfig) *AlertDeliveryResponse
 }
 
 // OutputClient encapsulates the clients that allow sending alerts to multiple outputs
@@ -81,6 +90,8 @@ type OutputClient struct {
 	// Do not mutate any fields in the goroutines, and do not use maps without proper locking.
 	session     *session.Session // safe for concurrent reads, not writes
 	httpWrapper HTTPWrapperiface
+	// tlsConfig is the base TLS configuration of SMTP connections, nil uses the root CAs of the host
+	tlsConfig *tls.Config
 }
 
 // OutputClient must satisfy the API interface.
@@ -162,6 +173,9 @@ func generateNotificationFromAlert(alert *alertModels.Alert) Notification {
 }
 
 func generateAlertMessage(alert *alertModels.Alert) string {
+	if alert.IsDigest {
+		return alert.Title
+	}
 	switch alert.Type {
 	case alertModels.RuleType:
 		return getDisplayName(alert) + " triggered"
@@ -196,6 +210,9 @@ func generateAlertTitle(alert *alertModels.Alert) string {
 	if alert.IsResent {
 		return "[Re-sent]: " + alert.Title
 	}
+	if alert.IsDigest {
+		return "Alert Digest: " + alert.Title
+	}
 	switch alert.Type {
 	case alertModels.RuleType:
 		if alert.Title != "" {
@@ -222,5 +239,9 @@ func generateURL(alert *alertModels.Alert) string {
 	if alert.IsTest {
 		return appDomainURL
 	}
+	// Digests summarize many alerts, link to the list of alerts
+	if alert.IsDigest {
+		return alertURLPrefix
+	}
 	return alertURLPrefix + *alert.AlertID
 }
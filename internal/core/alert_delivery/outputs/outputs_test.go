@@ -20,11 +20,16 @@ package outputs
 
 import (
 	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	alertModel "github.com/panther-labs/panther/api/lambda/delivery/models"
 )
@@ -43,6 +48,22 @@ func (m *mockHTTPWrapper) post(cxt context.Context, postInput *PostInput) *Alert
 	return args.Get(0).(*AlertDeliveryResponse)
 }
 
+// newWebhookServer starts a stand-in webhook server that decodes the JSON body of the last request
+func newWebhookServer(t *testing.T, status int) (*httptest.Server, *map[string]interface{}) {
+	received := map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		received = map[string]interface{}{}
+		require.NoError(t, jsoniter.Unmarshal(body, &received))
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
 func TestGenerateAlertTitleReturnGivenTitle(t *testing.T) {
 	alert := &alertModel.Alert{
 		Title: "my title",
@@ -76,6 +97,17 @@ func TestGenerateAlertTitlePolicyName(t *testing.T) {
 	assert.Equal(t, "Policy Failure: policy name", generateAlertTitle(alert))
 }
 
+func TestGenerateAlertTitleDigest(t *testing.T) {
+	alert := &alertModel.Alert{
+		Type:     alertModel.RuleType,
+		Title:    "5 alerts from rule name",
+		IsDigest: true,
+	}
+	assert.Equal(t, "Alert Digest: 5 alerts from rule name", generateAlertTitle(alert))
+	assert.Equal(t, "5 alerts from rule name", generateAlertMessage(alert))
+	assert.Equal(t, alertURLPrefix, generateURL(alert))
+}
+
 func TestGenerateAlertTitlePolicyId(t *testing.T) {
 	alert := &alertModel.Alert{
 		Type:         alertModel.PolicyType,
@@ -32,20 +32,29 @@ const (
 )
 
 // post sends a JSON body to an endpoint.
+// Templated outputs can override the payload, the HTTP method and the content type of the request.
 func (client *HTTPWrapper) post(ctx context.Context, input *PostInput) *AlertDeliveryResponse {
-	payload, err := jsoniter.Marshal(input.body)
-
-	// If there was an error marshaling the input
-	if err != nil {
-		return &AlertDeliveryResponse{
-			StatusCode: 500, // Internal server error
-			Success:    false,
-			Message:    "json marshal error: " + err.Error(),
-			Permanent:  true,
+	payload := input.payload
+	if payload == nil {
+		body, err := jsoniter.Marshal(input.body)
+
+		// If there was an error marshaling the input
+		if err != nil {
+			return &AlertDeliveryResponse{
+				StatusCode: 500, // Internal server error
+				Success:    false,
+				Message:    "json marshal error: " + err.Error(),
+				Permanent:  true,
+			}
 		}
+		payload = body
 	}
 
-	request, err := http.NewRequestWithContext(ctx, "POST", input.url, bytes.NewBuffer(payload))
+	method := input.method
+	if method == "" {
+		method = defaultPayloadMethod
+	}
+	request, err := http.NewRequestWithContext(ctx, method, input.url, bytes.NewBuffer(payload))
 
 	// If there was an error creating the request
 	if err != nil {
@@ -57,7 +66,11 @@ func (client *HTTPWrapper) post(ctx context.Context, input *PostInput) *AlertDel
 		}
 	}
 
-	request.Header.Set("Content-Type", "application/json")
+	contentType := input.contentType
+	if contentType == "" {
+		contentType = defaultPayloadContentType
+	}
+	request.Header.Set("Content-Type", contentType)
 	request.Header.Set("Accept", "application/json")
 
 	//Adding dynamic headers
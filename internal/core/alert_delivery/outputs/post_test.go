@@ -34,6 +34,7 @@ type mockHTTPClient struct {
 	statusCode   int
 	requestError bool
 	requestBody  string // Request body is saved here for tests to verify
+	request      *http.Request
 }
 
 const requestEndpoint = "https://runpanther.io"
@@ -47,6 +48,7 @@ func (m *mockHTTPClient) Do(request *http.Request) (*http.Response, error) {
 		panic(err)
 	}
 	m.requestBody = string(requestBytes)
+	m.request = request
 
 	responseBody := ioutil.NopCloser(bytes.NewReader([]byte("response")))
 	return &http.Response{Body: responseBody, StatusCode: m.statusCode}, nil
@@ -112,3 +114,22 @@ func TestPostCreated(t *testing.T) {
 		Permanent:  false,
 	}, c.post(ctx, postInput))
 }
+
+func TestPostPayload(t *testing.T) {
+	client := &mockHTTPClient{statusCode: http.StatusOK}
+	c := &HTTPWrapper{httpClient: client}
+	postInput := &PostInput{
+		url:         requestEndpoint,
+		body:        map[string]interface{}{"abc": 123},
+		payload:     []byte("abc=123"),
+		method:      http.MethodPut,
+		contentType: "application/x-www-form-urlencoded",
+		headers:     map[string]string{"X-Token": "secret"},
+	}
+	ctx := context.Background()
+	assert.True(t, c.post(ctx, postInput).Success)
+	assert.Equal(t, "abc=123", client.requestBody)
+	assert.Equal(t, http.MethodPut, client.request.Method)
+	assert.Equal(t, "application/x-www-form-urlencoded", client.request.Header.Get("Content-Type"))
+	assert.Equal(t, "secret", client.request.Header.Get("X-Token"))
+}
@@ -77,5 +77,8 @@ func (client *OutputClient) Slack(
 		body: payload,
 	}
 
+	if response := postInput.applyTemplate(config.Template, alert); response != nil {
+		return response
+	}
 	return client.httpWrapper.post(ctx, postInput)
 }
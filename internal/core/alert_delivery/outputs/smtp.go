@@ -0,0 +1,322 @@
+package outputs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+const (
+	SMTPSecurityNone     = "NONE"
+	SMTPSecuritySTARTTLS = "STARTTLS"
+	SMTPSecurityTLS      = "TLS"
+
+	defaultSMTPPort    = 587
+	defaultSMTPTLSPort = 465
+	smtpSenderName     = "Panther"
+)
+
+var emailTemplate = template.Must(template.New("email").Parse(`<html>
+<body>
+<h2>{{ .Title }}</h2>
+<p><a href="{{ .Link }}">Click here to view in the Panther UI</a></p>
+<table>
+<tr><td><b>Severity</b></td><td>{{ .Severity }}</td></tr>
+{{- if .Runbook }}
+<tr><td><b>Runbook</b></td><td>{{ .Runbook }}</td></tr>
+{{- end }}
+{{- if .Reference }}
+<tr><td><b>Reference</b></td><td>{{ .Reference }}</td></tr>
+{{- end }}
+{{- if .Description }}
+<tr><td><b>Description</b></td><td>{{ .Description }}</td></tr>
+{{- end }}
+{{- if .Tags }}
+<tr><td><b>Tags</b></td><td>{{ .Tags }}</td></tr>
+{{- end }}
+</table>
+{{- if .Context }}
+<h3>Alert Context</h3>
+<pre>{{ .Context }}</pre>
+{{- end }}
+</body>
+</html>
+`))
+
+type emailTemplateInput struct {
+	Title       string
+	Link        string
+	Severity    string
+	Runbook     string
+	Reference   string
+	Description string
+	Tags        string
+	Context     string
+}
+
+// smtpConfigError is an SMTP delivery failure caused by the output configuration
+type smtpConfigError struct {
+	message string
+}
+
+func (e *smtpConfigError) Error() string { return e.message }
+
+// SMTP sends an alert by email.
+//
+// The email has a plaintext and an HTML body and is sent to all the recipients of the output at once.
+func (client *OutputClient) SMTP(ctx context.Context, alert *alertModels.Alert, config *outputModels.SMTPConfig) *AlertDeliveryResponse {
+	message, err := buildEmail(alert, config, time.Now().UTC())
+	if err != nil {
+		errorMsg := "Failed to build email"
+		zap.L().Error(errorMsg, zap.Error(errors.WithStack(err)))
+		return &AlertDeliveryResponse{
+			StatusCode: 500,
+			Message:    errorMsg,
+			Permanent:  true,
+			Success:    false,
+		}
+	}
+
+	recipients := append(append([]string{}, config.To...), config.Cc...)
+	if err := client.sendMail(ctx, config, recipients, message); err != nil {
+		return getAlertResponseFromSMTPError(err)
+	}
+	return &AlertDeliveryResponse{
+		StatusCode: 200,
+		Message:    "email sent",
+		Permanent:  false,
+		Success:    true,
+	}
+}
+
+// sendMail delivers a message to an SMTP server
+func (client *OutputClient) sendMail(ctx context.Context, config *outputModels.SMTPConfig, recipients []string, message []byte) error {
+	security := config.Security
+	if security == "" {
+		security = SMTPSecuritySTARTTLS
+	}
+	// PlainAuth refuses to send credentials over unencrypted connections, outputs saved before they were validated
+	// would fail every retry
+	if config.Username != "" && security == SMTPSecurityNone {
+		return &smtpConfigError{message: "SMTP credentials require TLS or STARTTLS"}
+	}
+	port := config.Port
+	if port == 0 {
+		port = defaultSMTPPort
+		if security == SMTPSecurityTLS {
+			port = defaultSMTPTLSPort
+		}
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if client.tlsConfig != nil {
+		tlsConfig = client.tlsConfig.Clone()
+	}
+	tlsConfig.ServerName = config.Host
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(config.Host, strconv.Itoa(port)))
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to SMTP server")
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return errors.Wrap(err, "failed to set SMTP connection deadline")
+		}
+	}
+	if security == SMTPSecurityTLS {
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return errors.Wrap(err, "TLS handshake with SMTP server failed")
+		}
+		conn = tlsConn
+	}
+
+	smtpClient, err := smtp.NewClient(conn, config.Host)
+	if err != nil {
+		conn.Close()
+		return errors.Wrap(err, "failed to start SMTP session")
+	}
+	defer smtpClient.Close()
+
+	if security == SMTPSecuritySTARTTLS {
+		if ok, _ := smtpClient.Extension("STARTTLS"); !ok {
+			return &smtpConfigError{message: "SMTP server does not support STARTTLS"}
+		}
+		if err := smtpClient.StartTLS(tlsConfig); err != nil {
+			return errors.Wrap(err, "STARTTLS with SMTP server failed")
+		}
+	}
+	if config.Username != "" {
+		auth := smtp.PlainAuth("", config.Username, config.Password, config.Host)
+		if err := smtpClient.Auth(auth); err != nil {
+			return errors.Wrap(err, "SMTP authentication failed")
+		}
+	}
+
+	if err := smtpClient.Mail(config.From); err != nil {
+		return errors.Wrap(err, "SMTP server rejected sender")
+	}
+	for _, recipient := range recipients {
+		if err := smtpClient.Rcpt(recipient); err != nil {
+			return errors.Wrapf(err, "SMTP server rejected recipient %s", recipient)
+		}
+	}
+	writer, err := smtpClient.Data()
+	if err != nil {
+		return errors.Wrap(err, "SMTP server rejected message")
+	}
+	if _, err := writer.Write(message); err != nil {
+		return errors.Wrap(err, "failed to send message to SMTP server")
+	}
+	if err := writer.Close(); err != nil {
+		return errors.Wrap(err, "SMTP server rejected message")
+	}
+	return smtpClient.Quit()
+}
+
+// getAlertResponseFromSMTPError maps SMTP reply codes to delivery responses, 5xx replies are permanent failures
+func getAlertResponseFromSMTPError(err error) *AlertDeliveryResponse {
+	response := &AlertDeliveryResponse{
+		StatusCode: 500,
+		Message:    "email delivery failed: " + err.Error(),
+		Permanent:  false,
+		Success:    false,
+	}
+
+	var protocolErr *textproto.Error
+	var configErr *smtpConfigError
+	switch {
+	case errors.As(err, &protocolErr):
+		response.StatusCode = protocolErr.Code
+		response.Permanent = protocolErr.Code >= 500
+	case errors.As(err, &configErr):
+		response.StatusCode = 400
+		response.Permanent = true
+	}
+	return response
+}
+
+// buildEmail builds a multipart email with a plaintext and an HTML body
+func buildEmail(alert *alertModels.Alert, config *outputModels.SMTPConfig, now time.Time) ([]byte, error) {
+	// Best effort to format the alert context
+	alertContext, _ := json.MarshalIndent(alert.Context, "", "  ")
+	html := bytes.Buffer{}
+	err := emailTemplate.Execute(&html, &emailTemplateInput{
+		Title:       generateAlertTitle(alert),
+		Link:        generateURL(alert),
+		Severity:    alert.Severity,
+		Runbook:     alert.Runbook,
+		Reference:   alert.Reference,
+		Description: alert.AnalysisDescription,
+		Tags:        strings.Join(alert.Tags, ", "),
+		Context:     string(alertContext),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messageID, err := newMessageID(config.From, now)
+	if err != nil {
+		return nil, err
+	}
+
+	message := bytes.Buffer{}
+	body := multipart.NewWriter(&message)
+	// Header values can't contain line breaks
+	subject := strings.Join(strings.Fields(generateAlertTitle(alert)), " ")
+	headers := []string{
+		"From: " + (&mail.Address{Name: smtpSenderName, Address: config.From}).String(),
+		"To: " + strings.Join(config.To, ", "),
+	}
+	if len(config.Cc) > 0 {
+		headers = append(headers, "Cc: "+strings.Join(config.Cc, ", "))
+	}
+	headers = append(headers,
+		"Subject: "+mime.QEncoding.Encode("utf-8", subject),
+		"Date: "+now.Format(time.RFC1123Z),
+		"Message-ID: "+messageID,
+		"MIME-Version: 1.0",
+		fmt.Sprintf("Content-Type: multipart/alternative; boundary=%q", body.Boundary()),
+	)
+	message.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	if err := writeEmailPart(body, "text/plain", generateDetailedAlertMessage(alert)); err != nil {
+		return nil, err
+	}
+	if err := writeEmailPart(body, "text/html", html.String()); err != nil {
+		return nil, err
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return message.Bytes(), nil
+}
+
+// newMessageID generates a unique message id in the domain of the sender
+func newMessageID(from string, now time.Time) (string, error) {
+	domain := "panther"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = from[at+1:]
+	}
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("<%d.%s@%s>", now.UnixNano(), hex.EncodeToString(random), domain), nil
+}
+
+func writeEmailPart(body *multipart.Writer, contentType, content string) error {
+	part, err := body.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType + "; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	writer := quotedprintable.NewWriter(part)
+	if _, err := writer.Write([]byte(content)); err != nil {
+		return err
+	}
+	return writer.Close()
+}
@@ -0,0 +1,370 @@
+package outputs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io/ioutil"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+// smtpServer is a stand-in SMTP server that records the messages it receives
+type smtpServer struct {
+	listener    net.Listener
+	tlsConfig   *tls.Config
+	implicitTLS bool
+	startTLS    bool
+
+	mu         sync.Mutex
+	auth       string
+	from       string
+	recipients []string
+	data       string
+}
+
+// newSMTPServer starts a stand-in SMTP server and returns it with a client TLS config that trusts it
+func newSMTPServer(t *testing.T, implicitTLS, startTLS bool) (*smtpServer, *tls.Config) {
+	serverTLS, clientTLS := newTestTLSConfigs(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &smtpServer{
+		listener:    listener,
+		tlsConfig:   serverTLS,
+		implicitTLS: implicitTLS,
+		startTLS:    startTLS,
+	}
+	t.Cleanup(func() { listener.Close() })
+	go server.serve()
+	return server, clientTLS
+}
+
+func (s *smtpServer) config() *outputModels.SMTPConfig {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return &outputModels.SMTPConfig{
+		Host: addr.IP.String(),
+		Port: addr.Port,
+		From: "panther@example.com",
+		To:   []string{"oncall@example.com", "security@example.com"},
+		Cc:   []string{"audit@example.com"},
+	}
+}
+
+func (s *smtpServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpServer) handle(conn net.Conn) {
+	defer conn.Close()
+	isTLS := s.implicitTLS
+	if s.implicitTLS {
+		conn = tls.Server(conn, s.tlsConfig)
+	}
+	text := textproto.NewConn(conn)
+	reply := func(line string) { _ = text.PrintfLine("%s", line) }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command, arg := line, ""
+		if i := strings.IndexByte(line, ' '); i > 0 {
+			command, arg = line[:i], line[i+1:]
+		}
+		switch strings.ToUpper(command) {
+		case "EHLO":
+			reply("250-localhost")
+			if s.startTLS && !isTLS {
+				reply("250-STARTTLS")
+			}
+			reply("250 AUTH PLAIN")
+		case "STARTTLS":
+			reply("220 ready to start TLS")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, isTLS = tlsConn, true
+			text = textproto.NewConn(conn)
+		case "AUTH":
+			credentials, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(arg, "PLAIN "))
+			s.record(func() { s.auth = string(credentials) })
+			reply("235 authenticated")
+		case "MAIL":
+			s.record(func() { s.from = strings.TrimPrefix(arg, "FROM:") })
+			reply("250 ok")
+		case "RCPT":
+			recipient := strings.TrimPrefix(arg, "TO:")
+			if strings.Contains(recipient, "unknown") {
+				reply("550 no such user")
+				continue
+			}
+			s.record(func() { s.recipients = append(s.recipients, recipient) })
+			reply("250 ok")
+		case "DATA":
+			reply("354 send the message")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.record(func() { s.data = string(data) })
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 command not implemented")
+		}
+	}
+}
+
+func (s *smtpServer) record(update func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	update()
+}
+
+// newTestTLSConfigs creates a self-signed certificate for 127.0.0.1
+func newTestTLSConfigs(t *testing.T) (*tls.Config, *tls.Config) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	serverTLS := &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		MinVersion:   tls.VersionTLS12,
+	}
+	return serverTLS, &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+}
+
+func smtpAlert() *alertModels.Alert {
+	createdAt, _ := time.Parse(time.RFC3339, "2019-08-03T11:40:13Z")
+	return &alertModels.Alert{
+		AlertID:             aws.String("alertId"),
+		AnalysisID:          "ruleId",
+		AnalysisName:        aws.String("ruleName"),
+		AnalysisDescription: "description",
+		Type:                alertModels.RuleType,
+		CreatedAt:           createdAt,
+		Severity:            "HIGH",
+		Runbook:             "<b>runbook</b>",
+		Context:             map[string]interface{}{"key": "value"},
+	}
+}
+
+func TestSMTPStartTLS(t *testing.T) {
+	server, clientTLS := newSMTPServer(t, false, true)
+	client := &OutputClient{tlsConfig: clientTLS}
+	config := server.config()
+	config.Username = "user"
+	config.Password = "password"
+
+	response := client.SMTP(context.Background(), smtpAlert(), config)
+	require.NotNil(t, response)
+	assert.True(t, response.Success, response.Message)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Equal(t, "\x00user\x00password", server.auth)
+	assert.Equal(t, "<panther@example.com>", server.from)
+	assert.Equal(t, []string{"<oncall@example.com>", "<security@example.com>", "<audit@example.com>"}, server.recipients)
+	assert.Contains(t, server.data, "Subject: New Alert: ruleName\n")
+	assert.Contains(t, server.data, "Cc: audit@example.com\n")
+}
+
+func TestSMTPImplicitTLS(t *testing.T) {
+	server, clientTLS := newSMTPServer(t, true, false)
+	client := &OutputClient{tlsConfig: clientTLS}
+	config := server.config()
+	config.Security = SMTPSecurityTLS
+
+	response := client.SMTP(context.Background(), smtpAlert(), config)
+	require.NotNil(t, response)
+	assert.True(t, response.Success, response.Message)
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Contains(t, server.data, "Subject: New Alert: ruleName\n")
+}
+
+func TestSMTPUntrustedCertificate(t *testing.T) {
+	server, _ := newSMTPServer(t, true, false)
+	client := &OutputClient{}
+	config := server.config()
+	config.Security = SMTPSecurityTLS
+
+	response := client.SMTP(context.Background(), smtpAlert(), config)
+	require.NotNil(t, response)
+	assert.False(t, response.Success)
+	assert.False(t, response.Permanent)
+}
+
+func TestSMTPNoStartTLS(t *testing.T) {
+	server, clientTLS := newSMTPServer(t, false, false)
+	client := &OutputClient{tlsConfig: clientTLS}
+
+	response := client.SMTP(context.Background(), smtpAlert(), server.config())
+	require.NotNil(t, response)
+	assert.False(t, response.Success)
+	assert.True(t, response.Permanent)
+	assert.Equal(t, 400, response.StatusCode)
+
+	// Unencrypted connections must be allowed explicitly
+	config := server.config()
+	config.Security = SMTPSecurityNone
+	response = client.SMTP(context.Background(), smtpAlert(), config)
+	require.NotNil(t, response)
+	assert.True(t, response.Success, response.Message)
+
+	// Credentials are never sent unencrypted
+	config.Username = "panther"
+	response = client.SMTP(context.Background(), smtpAlert(), config)
+	require.NotNil(t, response)
+	assert.False(t, response.Success)
+	assert.True(t, response.Permanent)
+	assert.Equal(t, 400, response.StatusCode)
+}
+
+func TestSMTPRejectedRecipient(t *testing.T) {
+	server, clientTLS := newSMTPServer(t, false, true)
+	client := &OutputClient{tlsConfig: clientTLS}
+	config := server.config()
+	config.To = []string{"unknown@example.com"}
+
+	response := client.SMTP(context.Background(), smtpAlert(), config)
+	require.NotNil(t, response)
+	assert.False(t, response.Success)
+	assert.True(t, response.Permanent)
+	assert.Equal(t, 550, response.StatusCode)
+}
+
+func TestSMTPConnectionFailure(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+	client := &OutputClient{}
+	config := &outputModels.SMTPConfig{Host: "127.0.0.1", Port: port, From: "panther@example.com", To: []string{"oncall@example.com"}}
+
+	response := client.SMTP(context.Background(), smtpAlert(), config)
+	require.NotNil(t, response)
+	assert.False(t, response.Success)
+	assert.False(t, response.Permanent)
+	assert.Equal(t, 500, response.StatusCode)
+}
+
+func TestBuildEmail(t *testing.T) {
+	config := &outputModels.SMTPConfig{
+		From: "panther@example.com",
+		To:   []string{"oncall@example.com"},
+	}
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	alert := smtpAlert()
+	alert.Title = "Übersicht\nof events"
+	raw, err := buildEmail(alert, config, now)
+	require.NoError(t, err)
+
+	message, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	require.NoError(t, err)
+	assert.Equal(t, `"Panther" <panther@example.com>`, message.Header.Get("From"))
+	assert.Equal(t, "oncall@example.com", message.Header.Get("To"))
+	assert.Empty(t, message.Header.Get("Cc"))
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "New Alert: Übersicht of events", subject)
+	date, err := message.Header.Date()
+	require.NoError(t, err)
+	assert.True(t, now.Equal(date))
+	assert.Regexp(t, `^<\d+\.[0-9a-f]{32}@example\.com>$`, message.Header.Get("Message-ID"))
+	// Every email has its own message id
+	other, err := buildEmail(alert, config, now)
+	require.NoError(t, err)
+	otherMessage, err := mail.ReadMessage(strings.NewReader(string(other)))
+	require.NoError(t, err)
+	assert.NotEqual(t, message.Header.Get("Message-ID"), otherMessage.Header.Get("Message-ID"))
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	parts := map[string]string{}
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			break
+		}
+		body, err := ioutil.ReadAll(part)
+		require.NoError(t, err)
+		parts[part.Header.Get("Content-Type")] = string(body)
+	}
+	require.Len(t, parts, 2)
+	// Line breaks are encoded as CRLF
+	assert.Equal(t, strings.ReplaceAll(generateDetailedAlertMessage(alert), "\n", "\r\n"), parts["text/plain; charset=UTF-8"])
+	html := parts["text/html; charset=UTF-8"]
+	assert.Contains(t, html, `<a href="https://panther.io/alerts/alertId">`)
+	// Alert fields are escaped
+	assert.Contains(t, html, "&lt;b&gt;runbook&lt;/b&gt;")
+	assert.Contains(t, html, "&#34;key&#34;: &#34;value&#34;")
+}
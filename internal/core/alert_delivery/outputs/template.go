@@ -0,0 +1,128 @@
+package outputs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+const (
+	defaultPayloadMethod      = "POST"
+	defaultPayloadContentType = "application/json"
+)
+
+// payloadTemplateFuncs are the functions available to payload templates.
+// The `json` function should be used to embed values in JSON payloads so they are properly escaped.
+var payloadTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		return jsoniter.MarshalToString(v)
+	},
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// ParsePayloadTemplate parses the body of a payload template
+func ParsePayloadTemplate(body string) (*template.Template, error) {
+	return template.New("payload").Funcs(payloadTemplateFuncs).Parse(body)
+}
+
+// RenderPayloadTemplate renders the body of a payload template for an alert.
+// The template is executed with the default `Notification` payload of the alert.
+func RenderPayloadTemplate(tpl *outputModels.PayloadTemplate, alert *alertModels.Alert) ([]byte, error) {
+	t, err := ParsePayloadTemplate(tpl.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid payload template")
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, generateNotificationFromAlert(alert)); err != nil {
+		return nil, errors.Wrap(err, "failed to render payload template")
+	}
+	payload := buf.Bytes()
+	if isJSONContentType(tpl.ContentType) && !jsoniter.Valid(payload) {
+		return nil, errors.New("payload template did not render valid JSON")
+	}
+	return payload, nil
+}
+
+func isJSONContentType(contentType string) bool {
+	return contentType == "" || strings.Contains(contentType, "json")
+}
+
+// applyTemplate overrides the request of an output with a payload template.
+func (input *PostInput) applyTemplate(tpl *outputModels.PayloadTemplate, alert *alertModels.Alert) *AlertDeliveryResponse {
+	if tpl == nil {
+		return nil
+	}
+	if tpl.Body != "" {
+		payload, err := RenderPayloadTemplate(tpl, alert)
+		if err != nil {
+			return &AlertDeliveryResponse{
+				StatusCode: 400,
+				Success:    false,
+				Message:    err.Error(),
+				Permanent:  true,
+			}
+		}
+		input.payload = payload
+	}
+	input.method = tpl.Method
+	input.contentType = tpl.ContentType
+	if len(tpl.Headers) > 0 {
+		headers := make(map[string]string, len(input.headers)+len(tpl.Headers))
+		for key, value := range input.headers {
+			headers[key] = value
+		}
+		for _, h := range tpl.Headers {
+			headers[h.Key] = h.Value
+		}
+		input.headers = headers
+	}
+	return nil
+}
+
+// ValidatePayloadTemplate checks that a payload template renders for a sample alert
+func ValidatePayloadTemplate(tpl *outputModels.PayloadTemplate) error {
+	if tpl.Body == "" {
+		return nil
+	}
+	_, err := RenderPayloadTemplate(tpl, &alertModels.Alert{
+		AlertID:             aws.String("6ba4cd6c1c1b4e2e9e1f4a5d2b0d7c8f"),
+		AnalysisID:          "Sample.Rule",
+		AnalysisName:        aws.String("Sample Rule"),
+		AnalysisDescription: "Sample description",
+		Type:                alertModels.RuleType,
+		Severity:            "INFO",
+		Title:               "Sample alert",
+		Runbook:             "Sample runbook",
+		Tags:                []string{"sample"},
+		Version:             aws.String("abcdefg"),
+		Context:             map[string]interface{}{"key": "value"},
+	})
+	return err
+}
@@ -0,0 +1,134 @@
+package outputs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+var templateAlert = &alertModels.Alert{
+	AlertID:      aws.String("alertId"),
+	AnalysisID:   "ruleId",
+	AnalysisName: aws.String("Rule \"Name\""),
+	Type:         alertModels.RuleType,
+	CreatedAt:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	Severity:     "HIGH",
+	Tags:         []string{"a", "b"},
+	Context:      map[string]interface{}{"key": "value"},
+}
+
+func TestRenderPayloadTemplate(t *testing.T) {
+	payload, err := RenderPayloadTemplate(&outputModels.PayloadTemplate{
+		Body: `{"summary":{{ json .Name }},"severity":"{{ lower .Severity }}","tags":"{{ join .Tags "," }}",` +
+			`"key":{{ json .AlertContext.key }},"link":"{{ .Link }}"}`,
+	}, templateAlert)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"summary": "Rule \"Name\"",
+		"severity": "high",
+		"tags": "a,b",
+		"key": "value",
+		"link": "https://panther.io/alerts/alertId"
+	}`, string(payload))
+
+	payload, err = RenderPayloadTemplate(&outputModels.PayloadTemplate{
+		Body:        `{{ .Severity }}: {{ .Title }}`,
+		ContentType: "text/plain",
+	}, templateAlert)
+	require.NoError(t, err)
+	require.Equal(t, `HIGH: New Alert: Rule "Name"`, string(payload))
+
+	_, err = RenderPayloadTemplate(&outputModels.PayloadTemplate{
+		Body: `{{ .Severity }}: {{ .Title }}`,
+	}, templateAlert)
+	require.Error(t, err)
+
+	_, err = RenderPayloadTemplate(&outputModels.PayloadTemplate{
+		Body: `{"foo":{{ .Foo }}}`,
+	}, templateAlert)
+	require.Error(t, err)
+
+	_, err = RenderPayloadTemplate(&outputModels.PayloadTemplate{
+		Body: `{{ if }}`,
+	}, templateAlert)
+	require.Error(t, err)
+}
+
+func TestValidatePayloadTemplate(t *testing.T) {
+	require.NoError(t, ValidatePayloadTemplate(&outputModels.PayloadTemplate{}))
+	require.NoError(t, ValidatePayloadTemplate(&outputModels.PayloadTemplate{
+		Body: `{"text":{{ json .Title }}}`,
+	}))
+	require.Error(t, ValidatePayloadTemplate(&outputModels.PayloadTemplate{
+		Body: `{"text":{{ .Title }}}`,
+	}))
+}
+
+func TestCustomWebhookTemplate(t *testing.T) {
+	httpWrapper := &mockHTTPWrapper{}
+	client := &OutputClient{httpWrapper: httpWrapper}
+	config := &outputModels.CustomWebhookConfig{
+		WebhookURL: "custom-webhook-url",
+		Template: &outputModels.PayloadTemplate{
+			Body:   `{"id":{{ json .AlertID }}}`,
+			Method: "PUT",
+			Headers: []*outputModels.PayloadHeader{
+				{Key: "Authorization", Value: "Bearer token"},
+			},
+		},
+	}
+
+	expectedPostInput := &PostInput{
+		url:     "custom-webhook-url",
+		body:    generateNotificationFromAlert(templateAlert),
+		payload: []byte(`{"id":"alertId"}`),
+		method:  "PUT",
+		headers: map[string]string{"Authorization": "Bearer token"},
+	}
+	ctx := context.Background()
+	httpWrapper.On("post", ctx, expectedPostInput).Return((*AlertDeliveryResponse)(nil))
+
+	require.Nil(t, client.CustomWebhook(ctx, templateAlert, config))
+	httpWrapper.AssertExpectations(t)
+}
+
+func TestSlackTemplateError(t *testing.T) {
+	httpWrapper := &mockHTTPWrapper{}
+	client := &OutputClient{httpWrapper: httpWrapper}
+	config := &outputModels.SlackConfig{
+		WebhookURL: "slack-channel-url",
+		Template: &outputModels.PayloadTemplate{
+			Body: `{"text":{{ .Title }}}`,
+		},
+	}
+
+	response := client.Slack(context.Background(), templateAlert, config)
+	require.NotNil(t, response)
+	require.False(t, response.Success)
+	require.True(t, response.Permanent)
+	httpWrapper.AssertNotCalled(t, "post")
+}
@@ -0,0 +1,145 @@
+package policy
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"fmt"
+	"time"
+
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+)
+
+// MaxDigestEntries is the number of alerts kept in a digest, later alerts are counted as dropped
+const MaxDigestEntries = 100
+
+const digestAnalysisID = "Panther.Alert.Digest"
+
+var severityRanks = map[string]int{
+	"INFO":     0,
+	"LOW":      1,
+	"MEDIUM":   2,
+	"HIGH":     3,
+	"CRITICAL": 4,
+}
+
+// DigestEntry is the summary of an alert batched in a digest
+type DigestEntry struct {
+	AlertID      string    `json:"alertId,omitempty" dynamodbav:"alertId,omitempty"`
+	AnalysisID   string    `json:"analysisId" dynamodbav:"analysisId"`
+	AnalysisName string    `json:"analysisName,omitempty" dynamodbav:"analysisName,omitempty"`
+	Title        string    `json:"title,omitempty" dynamodbav:"title,omitempty"`
+	Severity     string    `json:"severity" dynamodbav:"severity"`
+	Type         string    `json:"type" dynamodbav:"type"`
+	CreatedAt    time.Time `json:"createdAt" dynamodbav:"createdAt"`
+}
+
+// Digest is the batch of alerts of an output within a window
+type Digest struct {
+	OutputID  string         `dynamodbav:"-"`
+	Entries   []*DigestEntry `dynamodbav:"entries"`
+	Dropped   int            `dynamodbav:"dropped"`
+	WindowEnd time.Time      `dynamodbav:"windowEnd,unixtime"`
+}
+
+// NewDigestEntry summarizes an alert for a digest
+func NewDigestEntry(alert *deliveryModels.Alert) *DigestEntry {
+	entry := &DigestEntry{
+		AnalysisID: alert.AnalysisID,
+		Title:      alert.Title,
+		Severity:   alert.Severity,
+		Type:       alert.Type,
+		CreatedAt:  alert.CreatedAt,
+	}
+	if alert.AlertID != nil {
+		entry.AlertID = *alert.AlertID
+	}
+	if alert.AnalysisName != nil {
+		entry.AnalysisName = *alert.AnalysisName
+	}
+	return entry
+}
+
+// Summarize builds the alert delivered for a digest.
+//
+// The summary has the highest severity of the batched alerts and lists them in its context.
+// Alerts batched again when their delivery is retried are listed once.
+func Summarize(digest *Digest, now time.Time) *deliveryModels.Alert {
+	entries := uniqueEntries(digest.Entries)
+	if len(entries) == 0 {
+		return nil
+	}
+	first := entries[0]
+	summary := &deliveryModels.Alert{
+		AnalysisID: digestAnalysisID,
+		Type:       first.Type,
+		CreatedAt:  now,
+		Severity:   first.Severity,
+		Context: map[string]interface{}{
+			"alerts":  entries,
+			"dropped": digest.Dropped,
+		},
+		IsDigest:         true,
+		DeliveryOutputID: digest.OutputID,
+	}
+	analysisIDs := map[string]struct{}{}
+	for _, entry := range entries {
+		analysisIDs[entry.AnalysisID] = struct{}{}
+		if entry.AlertID != "" {
+			summary.DigestAlertIDs = append(summary.DigestAlertIDs, entry.AlertID)
+		}
+		if severityRanks[entry.Severity] > severityRanks[summary.Severity] {
+			summary.Severity = entry.Severity
+		}
+	}
+	// Digests of a single detection keep its identity
+	if len(analysisIDs) == 1 {
+		summary.AnalysisID = first.AnalysisID
+		if first.AnalysisName != "" {
+			summary.AnalysisName = &first.AnalysisName
+		}
+	}
+	count := len(entries) + digest.Dropped
+	summary.Title = fmt.Sprintf("%d alerts from %d detections", count, len(analysisIDs))
+	if len(analysisIDs) == 1 {
+		summary.Title = fmt.Sprintf("%d alerts from %s", count, displayName(first))
+	}
+	return summary
+}
+
+func uniqueEntries(entries []*DigestEntry) []*DigestEntry {
+	unique := make([]*DigestEntry, 0, len(entries))
+	alertIDs := map[string]struct{}{}
+	for _, entry := range entries {
+		if entry.AlertID != "" {
+			if _, ok := alertIDs[entry.AlertID]; ok {
+				continue
+			}
+			alertIDs[entry.AlertID] = struct{}{}
+		}
+		unique = append(unique, entry)
+	}
+	return unique
+}
+
+func displayName(entry *DigestEntry) string {
+	if entry.AnalysisName != "" {
+		return entry.AnalysisName
+	}
+	return entry.AnalysisID
+}
@@ -0,0 +1,85 @@
+package policy
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+)
+
+func TestSummarize(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []*DigestEntry{
+		NewDigestEntry(&deliveryModels.Alert{
+			AlertID:      aws.String("alert-1"),
+			AnalysisID:   "Rule.One",
+			AnalysisName: aws.String("Rule One"),
+			Severity:     "LOW",
+			Type:         "RULE",
+		}),
+		NewDigestEntry(&deliveryModels.Alert{
+			AlertID:    aws.String("alert-2"),
+			AnalysisID: "Rule.One",
+			Severity:   "HIGH",
+			Type:       "RULE",
+		}),
+	}
+	digest := &Digest{
+		OutputID: "output-id",
+		Entries:  entries,
+		Dropped:  3,
+	}
+
+	summary := Summarize(digest, now)
+	require.NotNil(t, summary)
+	assert.True(t, summary.IsDigest)
+	assert.Nil(t, summary.AlertID)
+	assert.Equal(t, "Rule.One", summary.AnalysisID)
+	assert.Equal(t, "Rule One", aws.StringValue(summary.AnalysisName))
+	assert.Equal(t, "HIGH", summary.Severity)
+	assert.Equal(t, "RULE", summary.Type)
+	assert.Equal(t, now, summary.CreatedAt)
+	assert.Equal(t, "output-id", summary.DeliveryOutputID)
+	assert.Equal(t, []string{"alert-1", "alert-2"}, summary.DigestAlertIDs)
+	assert.Equal(t, "5 alerts from Rule One", summary.Title)
+	assert.Equal(t, entries, summary.Context["alerts"])
+	assert.Equal(t, 3, summary.Context["dropped"])
+
+	digest.Entries = append(digest.Entries, &DigestEntry{AnalysisID: "Rule.Two", Severity: "CRITICAL", Type: "RULE"})
+	summary = Summarize(digest, now)
+	assert.Equal(t, digestAnalysisID, summary.AnalysisID)
+	assert.Nil(t, summary.AnalysisName)
+	assert.Equal(t, "CRITICAL", summary.Severity)
+	assert.Equal(t, "6 alerts from 2 detections", summary.Title)
+
+	assert.Nil(t, Summarize(&Digest{OutputID: "output-id"}, now))
+
+	// Alerts batched twice are listed once
+	digest.Entries = append(entries, entries[0])
+	summary = Summarize(digest, now)
+	assert.Equal(t, "5 alerts from Rule One", summary.Title)
+	assert.Equal(t, entries, summary.Context["alerts"])
+	assert.Equal(t, []string{"alert-1", "alert-2"}, summary.DigestAlertIDs)
+}
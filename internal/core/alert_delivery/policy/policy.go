@@ -0,0 +1,143 @@
+package policy
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"math"
+	"time"
+	// Quiet hours schedules are evaluated in IANA time zones, embed the database so lambdas don't depend on the OS
+	_ "time/tzdata"
+
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+const clockLayout = "15:04"
+
+// defaultBypassSeverities are delivered during quiet hours if the policy does not specify any
+var defaultBypassSeverities = []string{"CRITICAL"}
+
+// Bucket is the state of a token bucket rate limit
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// Take takes a token from the bucket.
+//
+// It returns zero if a token was available or the time to wait until one will be.
+// A zero bucket is full.
+func (b *Bucket) Take(limit *outputModels.RateLimitPolicy, now time.Time) time.Duration {
+	burst := float64(limit.Burst)
+	perSecond := limit.PerMinute / 60
+	if b.UpdatedAt.IsZero() {
+		b.Tokens = burst
+	} else if elapsed := now.Sub(b.UpdatedAt); elapsed > 0 {
+		b.Tokens = math.Min(burst, b.Tokens+elapsed.Seconds()*perSecond)
+	}
+	b.UpdatedAt = now
+	if b.Tokens >= 1 {
+		b.Tokens--
+		return 0
+	}
+	wait := (1 - b.Tokens) / perSecond
+	return time.Duration(math.Ceil(wait * float64(time.Second)))
+}
+
+// QuietHoursDelay returns the time until quiet hours end or zero if an alert can be delivered now
+func QuietHoursDelay(quietHours *outputModels.QuietHoursPolicy, severity string, now time.Time) time.Duration {
+	if quietHours == nil || bypassesQuietHours(quietHours, severity) {
+		return 0
+	}
+	loc, err := LoadLocation(quietHours.Timezone)
+	if err != nil {
+		return 0
+	}
+	start, err := time.Parse(clockLayout, quietHours.Start)
+	if err != nil {
+		return 0
+	}
+	end, err := time.Parse(clockLayout, quietHours.End)
+	if err != nil {
+		return 0
+	}
+
+	local := now.In(loc)
+	// Quiet hours that started yesterday can still be in effect if they end on the next day
+	for _, days := range []int{0, -1} {
+		day := local.AddDate(0, 0, days)
+		if !startsOn(quietHours.Days, day.Weekday()) {
+			continue
+		}
+		from := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, loc)
+		until := time.Date(day.Year(), day.Month(), day.Day(), end.Hour(), end.Minute(), 0, 0, loc)
+		if !until.After(from) {
+			until = until.AddDate(0, 0, 1)
+		}
+		if !local.Before(from) && local.Before(until) {
+			return until.Sub(local)
+		}
+	}
+	return 0
+}
+
+// LoadLocation returns the location of a quiet hours time zone, an empty time zone is UTC
+func LoadLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(timezone)
+}
+
+// ParseClock checks that a quiet hours time of day is in the HH:MM format
+func ParseClock(clock string) error {
+	_, err := time.Parse(clockLayout, clock)
+	return err
+}
+
+func bypassesQuietHours(quietHours *outputModels.QuietHoursPolicy, severity string) bool {
+	severities := quietHours.BypassSeverities
+	if len(severities) == 0 {
+		severities = defaultBypassSeverities
+	}
+	for _, s := range severities {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
+func startsOn(days []string, weekday time.Weekday) bool {
+	if len(days) == 0 {
+		return true
+	}
+	for _, day := range days {
+		if day == weekday.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// Applies reports if a delivery policy applies to an alert.
+// Test alerts and digest summaries are delivered immediately.
+func Applies(deliveryPolicy *outputModels.DeliveryPolicy, alert *deliveryModels.Alert) bool {
+	return deliveryPolicy != nil && !alert.IsTest && !alert.IsDigest
+}
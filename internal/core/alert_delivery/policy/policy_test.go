@@ -0,0 +1,101 @@
+package policy
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+func TestBucketTake(t *testing.T) {
+	limit := &outputModels.RateLimitPolicy{Burst: 2, PerMinute: 6}
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	bucket := Bucket{}
+
+	// A new bucket is full
+	require.Zero(t, bucket.Take(limit, now))
+	require.Zero(t, bucket.Take(limit, now))
+	// A token is added every 10 seconds
+	require.Equal(t, 10*time.Second, bucket.Take(limit, now))
+	require.Equal(t, 5*time.Second, bucket.Take(limit, now.Add(5*time.Second)))
+	require.Zero(t, bucket.Take(limit, now.Add(10*time.Second)))
+
+	// Tokens do not exceed the burst
+	require.Zero(t, bucket.Take(limit, now.Add(time.Hour)))
+	require.Zero(t, bucket.Take(limit, now.Add(time.Hour)))
+	require.Equal(t, 10*time.Second, bucket.Take(limit, now.Add(time.Hour)))
+}
+
+func TestQuietHoursDelay(t *testing.T) {
+	quietHours := &outputModels.QuietHoursPolicy{
+		Timezone: "America/New_York",
+		Start:    "22:00",
+		End:      "08:00",
+		Days:     []string{"Friday"},
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	// 2020-01-03 is a Friday
+	friday := func(hour, minute int) time.Time {
+		return time.Date(2020, 1, 3, hour, minute, 0, 0, newYork)
+	}
+
+	assert.Zero(t, QuietHoursDelay(quietHours, "HIGH", friday(21, 59)))
+	assert.Equal(t, 10*time.Hour, QuietHoursDelay(quietHours, "HIGH", friday(22, 0)))
+	// Quiet hours started on Friday end on Saturday
+	assert.Equal(t, time.Hour, QuietHoursDelay(quietHours, "HIGH", friday(31, 0)))
+	assert.Zero(t, QuietHoursDelay(quietHours, "HIGH", friday(32, 0)))
+	// Quiet hours do not start on Saturday
+	assert.Zero(t, QuietHoursDelay(quietHours, "HIGH", friday(46, 0)))
+	// Times are in the time zone of the policy
+	assert.Equal(t, 10*time.Hour, QuietHoursDelay(quietHours, "HIGH", friday(22, 0).UTC()))
+	// Critical alerts bypass quiet hours by default
+	assert.Zero(t, QuietHoursDelay(quietHours, "CRITICAL", friday(23, 0)))
+
+	quietHours.BypassSeverities = []string{"HIGH"}
+	assert.Zero(t, QuietHoursDelay(quietHours, "HIGH", friday(23, 0)))
+	assert.Equal(t, 9*time.Hour, QuietHoursDelay(quietHours, "CRITICAL", friday(23, 0)))
+	assert.Zero(t, QuietHoursDelay(nil, "INFO", friday(23, 0)))
+}
+
+func TestQuietHoursDelaySameDay(t *testing.T) {
+	quietHours := &outputModels.QuietHoursPolicy{
+		Start: "12:00",
+		End:   "13:30",
+	}
+	noon := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 90*time.Minute, QuietHoursDelay(quietHours, "INFO", noon))
+	assert.Equal(t, time.Minute, QuietHoursDelay(quietHours, "INFO", noon.Add(89*time.Minute)))
+	assert.Zero(t, QuietHoursDelay(quietHours, "INFO", noon.Add(90*time.Minute)))
+	assert.Zero(t, QuietHoursDelay(quietHours, "INFO", noon.Add(-time.Minute)))
+}
+
+func TestApplies(t *testing.T) {
+	deliveryPolicy := &outputModels.DeliveryPolicy{}
+	assert.True(t, Applies(deliveryPolicy, &deliveryModels.Alert{}))
+	assert.False(t, Applies(nil, &deliveryModels.Alert{}))
+	assert.False(t, Applies(deliveryPolicy, &deliveryModels.Alert{IsTest: true}))
+	assert.False(t, Applies(deliveryPolicy, &deliveryModels.Alert{IsDigest: true}))
+}
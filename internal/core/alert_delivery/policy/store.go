@@ -0,0 +1,228 @@
+package policy
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/pkg/errors"
+
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+const (
+	idKey        = "id"
+	rateLimitKey = "ratelimit#"
+	digestKey    = "digest#"
+	// Delivery state expires a day after its last use
+	stateTTL = 24 * time.Hour
+	// The number of times a token is taken before giving up on concurrent updates to a bucket
+	maxTakeAttempts = 3
+)
+
+// Store persists the delivery state of outputs
+type Store interface {
+	// TakeToken takes a token from the rate limit of an output, see Bucket.Take
+	TakeToken(outputID string, limit *outputModels.RateLimitPolicy, now time.Time) (time.Duration, error)
+	// AddToDigest adds an alert to the digest of an output and reports if it opened a new window
+	AddToDigest(outputID string, entry *DigestEntry, window time.Duration, now time.Time) (bool, error)
+	// TakeDigest removes the digest of an output if its window has closed.
+	// An open digest is returned as is and a missing digest is nil.
+	TakeDigest(outputID string, now time.Time) (*Digest, error)
+}
+
+// DynamoStore is a Store in a DynamoDB table keyed by "id"
+type DynamoStore struct {
+	TableName string
+	Client    dynamodbiface.DynamoDBAPI
+}
+
+var _ Store = (*DynamoStore)(nil)
+
+type bucketItem struct {
+	ID        string  `dynamodbav:"id"`
+	Tokens    float64 `dynamodbav:"tokens"`
+	UpdatedAt int64   `dynamodbav:"updatedAt"`
+	ExpiresAt int64   `dynamodbav:"expiresAt"`
+}
+
+// TakeToken implements Store
+func (s *DynamoStore) TakeToken(outputID string, limit *outputModels.RateLimitPolicy, now time.Time) (time.Duration, error) {
+	key := rateLimitKey + outputID
+	for attempt := 0; attempt < maxTakeAttempts; attempt++ {
+		output, err := s.Client.GetItem(&dynamodb.GetItemInput{
+			TableName:      aws.String(s.TableName),
+			Key:            itemKey(key),
+			ConsistentRead: aws.Bool(true),
+		})
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to get rate limit of output %s", outputID)
+		}
+		item := bucketItem{}
+		if err := dynamodbattribute.UnmarshalMap(output.Item, &item); err != nil {
+			return 0, errors.Wrapf(err, "failed to unmarshal rate limit of output %s", outputID)
+		}
+
+		bucket := Bucket{Tokens: item.Tokens}
+		if item.UpdatedAt != 0 {
+			bucket.UpdatedAt = time.Unix(0, item.UpdatedAt)
+		}
+		wait := bucket.Take(limit, now)
+
+		// Only the last writer of the bucket can update it
+		condition := "attribute_not_exists(id)"
+		values := map[string]*dynamodb.AttributeValue{}
+		if output.Item != nil {
+			condition = "updatedAt = :updatedAt"
+			values[":updatedAt"] = &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(item.UpdatedAt, 10))}
+		}
+		newItem, err := dynamodbattribute.MarshalMap(&bucketItem{
+			ID:        key,
+			Tokens:    bucket.Tokens,
+			UpdatedAt: bucket.UpdatedAt.UnixNano(),
+			ExpiresAt: now.Add(stateTTL).Unix(),
+		})
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to marshal rate limit of output %s", outputID)
+		}
+		input := &dynamodb.PutItemInput{
+			TableName:           aws.String(s.TableName),
+			Item:                newItem,
+			ConditionExpression: aws.String(condition),
+		}
+		if len(values) > 0 {
+			input.ExpressionAttributeValues = values
+		}
+		if _, err := s.Client.PutItem(input); err != nil {
+			if isConditionalCheckFailed(err) {
+				continue
+			}
+			return 0, errors.Wrapf(err, "failed to update rate limit of output %s", outputID)
+		}
+		return wait, nil
+	}
+	return 0, errors.Errorf("failed to update rate limit of output %s, too many concurrent updates", outputID)
+}
+
+// AddToDigest implements Store
+func (s *DynamoStore) AddToDigest(outputID string, entry *DigestEntry, window time.Duration, now time.Time) (bool, error) {
+	key := digestKey + outputID
+	entryValue, err := dynamodbattribute.Marshal(entry)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to marshal digest entry of output %s", outputID)
+	}
+	windowEnd := now.Add(window)
+	output, err := s.Client.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName: aws.String(s.TableName),
+		Key:       itemKey(key),
+		UpdateExpression: aws.String("SET entries = list_append(if_not_exists(entries, :empty), :entry), " +
+			"windowEnd = if_not_exists(windowEnd, :windowEnd), expiresAt = if_not_exists(expiresAt, :expiresAt)"),
+		ConditionExpression: aws.String("attribute_not_exists(entries) OR size(entries) < :max"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":empty":     {L: []*dynamodb.AttributeValue{}},
+			":entry":     {L: []*dynamodb.AttributeValue{entryValue}},
+			":windowEnd": {N: aws.String(strconv.FormatInt(windowEnd.Unix(), 10))},
+			":expiresAt": {N: aws.String(strconv.FormatInt(windowEnd.Add(stateTTL).Unix(), 10))},
+			":max":       {N: aws.String(strconv.Itoa(MaxDigestEntries))},
+		},
+		ReturnValues: aws.String(dynamodb.ReturnValueUpdatedOld),
+	})
+	if err == nil {
+		// The window opened if the digest had no previous window
+		_, hasWindow := output.Attributes["windowEnd"]
+		return !hasWindow, nil
+	}
+	if !isConditionalCheckFailed(err) {
+		return false, errors.Wrapf(err, "failed to add alert to digest of output %s", outputID)
+	}
+
+	// The digest is full, count the alert
+	_, err = s.Client.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName:        aws.String(s.TableName),
+		Key:              itemKey(key),
+		UpdateExpression: aws.String("ADD dropped :one"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":one": {N: aws.String("1")},
+		},
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to count dropped alert in digest of output %s", outputID)
+	}
+	return false, nil
+}
+
+// TakeDigest implements Store
+func (s *DynamoStore) TakeDigest(outputID string, now time.Time) (*Digest, error) {
+	key := digestKey + outputID
+	output, err := s.Client.GetItem(&dynamodb.GetItemInput{
+		TableName:      aws.String(s.TableName),
+		Key:            itemKey(key),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get digest of output %s", outputID)
+	}
+	if output.Item == nil {
+		return nil, nil
+	}
+	digest, err := unmarshalDigest(outputID, output.Item)
+	if err != nil || digest.WindowEnd.After(now) {
+		return digest, err
+	}
+
+	// Use the deleted item, alerts could have been added since it was read
+	deleted, err := s.Client.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName:    aws.String(s.TableName),
+		Key:          itemKey(key),
+		ReturnValues: aws.String(dynamodb.ReturnValueAllOld),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to delete digest of output %s", outputID)
+	}
+	if deleted.Attributes == nil {
+		return nil, nil
+	}
+	return unmarshalDigest(outputID, deleted.Attributes)
+}
+
+func unmarshalDigest(outputID string, item map[string]*dynamodb.AttributeValue) (*Digest, error) {
+	digest := &Digest{}
+	if err := dynamodbattribute.UnmarshalMap(item, digest); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal digest of output %s", outputID)
+	}
+	digest.OutputID = outputID
+	return digest, nil
+}
+
+func itemKey(key string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		idKey: {S: aws.String(key)},
+	}
+}
+
+func isConditionalCheckFailed(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException
+}
@@ -0,0 +1,160 @@
+package policy
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+var (
+	testNow            = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	errConditionFailed = awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "", nil)
+)
+
+func TestTakeTokenNewBucket(t *testing.T) {
+	client := &testutils.DynamoDBMock{}
+	store := &DynamoStore{TableName: "table", Client: client}
+	limit := &outputModels.RateLimitPolicy{Burst: 2, PerMinute: 6}
+
+	client.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+	client.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+		return aws.StringValue(input.ConditionExpression) == "attribute_not_exists(id)" &&
+			aws.StringValue(input.Item["id"].S) == "ratelimit#output-id" &&
+			aws.StringValue(input.Item["tokens"].N) == "1"
+	})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	wait, err := store.TakeToken("output-id", limit, testNow)
+	require.NoError(t, err)
+	assert.Zero(t, wait)
+	client.AssertExpectations(t)
+}
+
+func TestTakeTokenConcurrentUpdate(t *testing.T) {
+	client := &testutils.DynamoDBMock{}
+	store := &DynamoStore{TableName: "table", Client: client}
+	limit := &outputModels.RateLimitPolicy{Burst: 2, PerMinute: 6}
+	item := map[string]*dynamodb.AttributeValue{
+		"id":        {S: aws.String("ratelimit#output-id")},
+		"tokens":    {N: aws.String("0")},
+		"updatedAt": {N: aws.String("1577836800000000000")},
+	}
+
+	client.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{Item: item}, nil).Twice()
+	client.On("PutItem", mock.Anything).Return(&dynamodb.PutItemOutput{}, errConditionFailed).Once()
+	client.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+		return aws.StringValue(input.ConditionExpression) == "updatedAt = :updatedAt" &&
+			aws.StringValue(input.ExpressionAttributeValues[":updatedAt"].N) == "1577836800000000000"
+	})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	wait, err := store.TakeToken("output-id", limit, testNow.Add(5*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, wait)
+	client.AssertExpectations(t)
+}
+
+func TestAddToDigest(t *testing.T) {
+	client := &testutils.DynamoDBMock{}
+	store := &DynamoStore{TableName: "table", Client: client}
+	entry := &DigestEntry{AnalysisID: "Rule.One", Severity: "INFO", Type: "RULE", CreatedAt: testNow}
+
+	// The first alert opens a window
+	client.On("UpdateItem", mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+	opened, err := store.AddToDigest("output-id", entry, time.Minute, testNow)
+	require.NoError(t, err)
+	assert.True(t, opened)
+
+	client.On("UpdateItem", mock.Anything).Return(&dynamodb.UpdateItemOutput{
+		Attributes: map[string]*dynamodb.AttributeValue{"windowEnd": {N: aws.String("1577836860")}},
+	}, nil).Once()
+	opened, err = store.AddToDigest("output-id", entry, time.Minute, testNow)
+	require.NoError(t, err)
+	assert.False(t, opened)
+
+	// Alerts are counted once the digest is full
+	client.On("UpdateItem", mock.Anything).Return(&dynamodb.UpdateItemOutput{}, errConditionFailed).Once()
+	client.On("UpdateItem", mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		return aws.StringValue(input.UpdateExpression) == "ADD dropped :one"
+	})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+	opened, err = store.AddToDigest("output-id", entry, time.Minute, testNow)
+	require.NoError(t, err)
+	assert.False(t, opened)
+	client.AssertExpectations(t)
+}
+
+func TestTakeDigest(t *testing.T) {
+	client := &testutils.DynamoDBMock{}
+	store := &DynamoStore{TableName: "table", Client: client}
+	item := map[string]*dynamodb.AttributeValue{
+		"id": {S: aws.String("digest#output-id")},
+		"entries": {L: []*dynamodb.AttributeValue{
+			{M: map[string]*dynamodb.AttributeValue{
+				"analysisId": {S: aws.String("Rule.One")},
+				"severity":   {S: aws.String("INFO")},
+				"type":       {S: aws.String("RULE")},
+				"createdAt":  {S: aws.String("2020-01-01T00:00:00Z")},
+			}},
+		}},
+		"dropped":   {N: aws.String("2")},
+		"windowEnd": {N: aws.String("1577836860")},
+	}
+	client.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{Item: item}, nil)
+
+	// The window is open
+	digest, err := store.TakeDigest("output-id", testNow)
+	require.NoError(t, err)
+	require.NotNil(t, digest)
+	assert.Equal(t, testNow.Add(time.Minute), digest.WindowEnd.UTC())
+	client.AssertNotCalled(t, "DeleteItem", mock.Anything)
+
+	// The window is closed
+	client.On("DeleteItem", mock.Anything).Return(&dynamodb.DeleteItemOutput{Attributes: item}, nil).Once()
+	digest, err = store.TakeDigest("output-id", testNow.Add(time.Minute))
+	require.NoError(t, err)
+	require.NotNil(t, digest)
+	expected := &Digest{
+		OutputID:  "output-id",
+		Entries:   []*DigestEntry{{AnalysisID: "Rule.One", Severity: "INFO", Type: "RULE", CreatedAt: testNow}},
+		Dropped:   2,
+		WindowEnd: time.Unix(1577836860, 0),
+	}
+	assert.Equal(t, expected, digest)
+	client.AssertExpectations(t)
+}
+
+func TestTakeDigestMissing(t *testing.T) {
+	client := &testutils.DynamoDBMock{}
+	store := &DynamoStore{TableName: "table", Client: client}
+	client.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+
+	digest, err := store.TakeDigest("output-id", testNow)
+	require.NoError(t, err)
+	assert.Nil(t, digest)
+	client.AssertExpectations(t)
+}
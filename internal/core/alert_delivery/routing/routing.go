@@ -0,0 +1,193 @@
+package routing
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"path"
+	"strings"
+
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+const alertOutputSkip = "SKIP"
+
+// Route returns the outputs an alert should be delivered to.
+//
+// Outputs are selected by the first of the following that applies:
+//   - the detection skips dispatching the alert
+//   - the delivery policy of an output deferred or batched the alert
+//   - dynamic destinations (set in the detection's python body)
+//   - destination overrides (set in the detection's form)
+//   - routing rules and default severities of each output
+func Route(alert *deliveryModels.Alert, outputs []*outputModels.AlertOutput) []*outputModels.AlertRoute {
+	routes := []*outputModels.AlertRoute{}
+
+	// First, check if we have an override to SKIP dispatching this alert
+	if shouldSkip(alert) {
+		return routes
+	}
+
+	// Alerts deferred or batched by a delivery policy are only delivered to its output, if it still exists
+	if alert.DeliveryOutputID != "" {
+		routes, _ := routeByID([]string{alert.DeliveryOutputID}, outputs, outputModels.RouteReasonDeliveryPolicy)
+		return routes
+	}
+
+	// Next, prioritize dynamic destinations (set in the detection's python body)
+	if routes, ok := routeByID(alert.Destinations, outputs, outputModels.RouteReasonDestinations); ok {
+		return routes
+	}
+
+	// Then, destination overrides (set in the detection's form)
+	if routes, ok := routeByID(alert.OutputIds, outputs, outputModels.RouteReasonOutputIds); ok {
+		return routes
+	}
+
+	// Finally, the routing rules or the severity rating (default) of each output
+	for _, output := range outputs {
+		if index, ok := MatchRules(output.RoutingRules, alert); ok {
+			if output.RoutingRules[index].Exclude {
+				continue
+			}
+			routes = append(routes, newRoute(output, outputModels.RouteReasonRoutingRule, &index))
+			continue
+		}
+		for _, outputSeverity := range output.DefaultForSeverity {
+			if alert.Severity == *outputSeverity {
+				routes = append(routes, newRoute(output, outputModels.RouteReasonDefaultForSeverity, nil))
+			}
+		}
+	}
+	return routes
+}
+
+// Outputs returns the outputs of routes
+func Outputs(routes []*outputModels.AlertRoute, outputs []*outputModels.AlertOutput) []*outputModels.AlertOutput {
+	alertOutputs := []*outputModels.AlertOutput{}
+	for _, route := range routes {
+		for _, output := range outputs {
+			if *output.OutputID == *route.OutputID {
+				alertOutputs = append(alertOutputs, output)
+				break
+			}
+		}
+	}
+	return alertOutputs
+}
+
+func newRoute(output *outputModels.AlertOutput, reason string, rule *int) *outputModels.AlertRoute {
+	return &outputModels.AlertRoute{
+		OutputID:    output.OutputID,
+		DisplayName: output.DisplayName,
+		OutputType:  output.OutputType,
+		Reason:      reason,
+		RoutingRule: rule,
+	}
+}
+
+func shouldSkip(alert *deliveryModels.Alert) bool {
+	for _, outputID := range alert.OutputIds {
+		if outputID == alertOutputSkip {
+			return true
+		}
+	}
+	return false
+}
+
+func routeByID(outputIds []string, outputs []*outputModels.AlertOutput, reason string) ([]*outputModels.AlertRoute, bool) {
+	routes := []*outputModels.AlertRoute{}
+	if len(outputIds) == 0 {
+		return routes, false
+	}
+
+	for _, output := range outputs {
+		for _, outputID := range outputIds {
+			if *output.OutputID == outputID {
+				routes = append(routes, newRoute(output, reason, nil))
+			}
+		}
+	}
+
+	return routes, len(routes) > 0
+}
+
+// MatchRules returns the index of the first routing rule that matches an alert
+func MatchRules(rules []*outputModels.AlertRoutingRule, alert *deliveryModels.Alert) (int, bool) {
+	for i, rule := range rules {
+		if MatchRule(rule, alert) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// MatchRule checks if an alert matches all the conditions of a routing rule
+func MatchRule(rule *outputModels.AlertRoutingRule, alert *deliveryModels.Alert) bool {
+	if len(rule.Severities) > 0 && !containsAny(rule.Severities, alert.Severity) {
+		return false
+	}
+	if len(rule.AlertTypes) > 0 && !containsAny(rule.AlertTypes, alert.Type) {
+		return false
+	}
+	if len(rule.LogTypes) > 0 && !containsAny(rule.LogTypes, alert.LogTypes...) {
+		return false
+	}
+	if len(rule.ResourceTypes) > 0 && !containsAny(rule.ResourceTypes, alert.ResourceTypes...) {
+		return false
+	}
+	if len(rule.Tags) > 0 && !containsAnyFold(rule.Tags, alert.Tags...) {
+		return false
+	}
+	if len(rule.AnalysisIDs) > 0 && !matchAny(rule.AnalysisIDs, alert.AnalysisID) {
+		return false
+	}
+	return true
+}
+
+func containsAny(values []string, candidates ...string) bool {
+	for _, value := range values {
+		for _, candidate := range candidates {
+			if value == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsAnyFold(values []string, candidates ...string) bool {
+	for _, value := range values {
+		for _, candidate := range candidates {
+			if strings.EqualFold(value, candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+package routing
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+var (
+	cloudSecOutput = &outputModels.AlertOutput{
+		OutputID:           aws.String("output-cloudsec"),
+		DisplayName:        aws.String("cloud-sec"),
+		OutputType:         aws.String("slack"),
+		DefaultForSeverity: aws.StringSlice([]string{"CRITICAL"}),
+		RoutingRules: []*outputModels.AlertRoutingRule{
+			{
+				AnalysisIDs: []string{"Test.*"},
+				Exclude:     true,
+			},
+			{
+				Severities: []string{"HIGH", "CRITICAL"},
+				LogTypes:   []string{"AWS.CloudTrail"},
+				Tags:       []string{"IAM"},
+			},
+		},
+	}
+	defaultOutput = &outputModels.AlertOutput{
+		OutputID:           aws.String("output-default"),
+		DisplayName:        aws.String("default"),
+		OutputType:         aws.String("sns"),
+		DefaultForSeverity: aws.StringSlice([]string{"HIGH", "CRITICAL"}),
+	}
+	allOutputs = []*outputModels.AlertOutput{cloudSecOutput, defaultOutput}
+)
+
+func TestRoute(t *testing.T) {
+	alert := &deliveryModels.Alert{
+		AnalysisID: "AWS.CloudTrail.IAMChange",
+		Type:       deliveryModels.RuleType,
+		Severity:   "HIGH",
+		LogTypes:   []string{"AWS.CloudTrail"},
+		Tags:       []string{"iam", "aws"},
+	}
+	require.Equal(t, []*outputModels.AlertRoute{
+		{
+			OutputID:    aws.String("output-cloudsec"),
+			DisplayName: aws.String("cloud-sec"),
+			OutputType:  aws.String("slack"),
+			Reason:      outputModels.RouteReasonRoutingRule,
+			RoutingRule: aws.Int(1),
+		},
+		{
+			OutputID:    aws.String("output-default"),
+			DisplayName: aws.String("default"),
+			OutputType:  aws.String("sns"),
+			Reason:      outputModels.RouteReasonDefaultForSeverity,
+		},
+	}, Route(alert, allOutputs))
+	require.Equal(t, allOutputs, Outputs(Route(alert, allOutputs), allOutputs))
+}
+
+func TestRouteExclude(t *testing.T) {
+	// Excluded by the first rule although the second rule matches
+	alert := &deliveryModels.Alert{
+		AnalysisID: "Test.IAMChange",
+		Type:       deliveryModels.RuleType,
+		Severity:   "CRITICAL",
+		LogTypes:   []string{"AWS.CloudTrail"},
+		Tags:       []string{"iam"},
+	}
+	routes := Route(alert, allOutputs)
+	require.Len(t, routes, 1)
+	require.Equal(t, "output-default", *routes[0].OutputID)
+}
+
+func TestRouteDefaultForSeverity(t *testing.T) {
+	// No routing rule matches so the default severities of the output apply
+	alert := &deliveryModels.Alert{
+		AnalysisID: "AWS.S3.Public",
+		Type:       deliveryModels.PolicyType,
+		Severity:   "CRITICAL",
+	}
+	routes := Route(alert, allOutputs)
+	require.Len(t, routes, 2)
+	require.Equal(t, outputModels.RouteReasonDefaultForSeverity, routes[0].Reason)
+	require.Equal(t, outputModels.RouteReasonDefaultForSeverity, routes[1].Reason)
+
+	alert.Severity = "INFO"
+	require.Empty(t, Route(alert, allOutputs))
+}
+
+func TestRouteOverrides(t *testing.T) {
+	alert := &deliveryModels.Alert{
+		AnalysisID: "AWS.CloudTrail.IAMChange",
+		Type:       deliveryModels.RuleType,
+		Severity:   "HIGH",
+		OutputIds:  []string{"output-default"},
+	}
+	require.Equal(t, []*outputModels.AlertRoute{
+		{
+			OutputID:    aws.String("output-default"),
+			DisplayName: aws.String("default"),
+			OutputType:  aws.String("sns"),
+			Reason:      outputModels.RouteReasonOutputIds,
+		},
+	}, Route(alert, allOutputs))
+
+	// Dynamic destinations take precedence
+	alert.Destinations = []string{"output-cloudsec"}
+	routes := Route(alert, allOutputs)
+	require.Len(t, routes, 1)
+	require.Equal(t, "output-cloudsec", *routes[0].OutputID)
+	require.Equal(t, outputModels.RouteReasonDestinations, routes[0].Reason)
+
+	// Unknown destinations are ignored
+	alert.Destinations = []string{"output-missing"}
+	routes = Route(alert, allOutputs)
+	require.Len(t, routes, 1)
+	require.Equal(t, outputModels.RouteReasonOutputIds, routes[0].Reason)
+
+	alert.OutputIds = []string{alertOutputSkip}
+	require.Empty(t, Route(alert, allOutputs))
+}
+
+func TestRouteDeliveryPolicy(t *testing.T) {
+	alert := &deliveryModels.Alert{
+		AnalysisID:       "AWS.CloudTrail.IAMChange",
+		Type:             deliveryModels.RuleType,
+		Severity:         "HIGH",
+		Destinations:     []string{"output-default"},
+		DeliveryOutputID: "output-cloudsec",
+	}
+	routes := Route(alert, allOutputs)
+	require.Len(t, routes, 1)
+	require.Equal(t, "output-cloudsec", *routes[0].OutputID)
+	require.Equal(t, outputModels.RouteReasonDeliveryPolicy, routes[0].Reason)
+
+	// Alerts of deleted outputs are dropped instead of routed by the rules
+	alert.DeliveryOutputID = "output-missing"
+	require.Empty(t, Route(alert, allOutputs))
+}
+
+func TestMatchRule(t *testing.T) {
+	alert := &deliveryModels.Alert{
+		AnalysisID:    "AWS.S3.Public",
+		Type:          deliveryModels.PolicyType,
+		Severity:      "MEDIUM",
+		ResourceTypes: []string{"AWS.S3.Bucket"},
+		Tags:          []string{"S3"},
+	}
+	require.True(t, MatchRule(&outputModels.AlertRoutingRule{}, alert))
+	require.True(t, MatchRule(&outputModels.AlertRoutingRule{
+		ResourceTypes: []string{"AWS.EC2.Instance", "AWS.S3.Bucket"},
+		AlertTypes:    []string{deliveryModels.PolicyType},
+		AnalysisIDs:   []string{"AWS.S3.*"},
+		Tags:          []string{"s3"},
+	}, alert))
+	require.False(t, MatchRule(&outputModels.AlertRoutingRule{
+		Severities: []string{"HIGH"},
+	}, alert))
+	require.False(t, MatchRule(&outputModels.AlertRoutingRule{
+		AlertTypes: []string{deliveryModels.RuleType},
+	}, alert))
+	require.False(t, MatchRule(&outputModels.AlertRoutingRule{
+		LogTypes: []string{"AWS.CloudTrail"},
+	}, alert))
+	require.False(t, MatchRule(&outputModels.AlertRoutingRule{
+		AnalysisIDs: []string{"AWS.EC2.*"},
+	}, alert))
+
+	index, ok := MatchRules(cloudSecOutput.RoutingRules, alert)
+	require.False(t, ok)
+	require.Equal(t, -1, index)
+}
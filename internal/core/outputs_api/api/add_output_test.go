@@ -278,3 +278,56 @@ func TestAddOutputAsana(t *testing.T) {
 	_, err = uuid.Parse(*result.OutputID)
 	assert.NoError(t, err)
 }
+
+func TestAddOutputSMTP(t *testing.T) {
+	mockEncryptionKey := &mockEncryptionKey{}
+	encryptionKey = mockEncryptionKey
+	mockOutputTable := &mockOutputTable{}
+	outputsTable = mockOutputTable
+
+	mockOutputTable.On("GetOutputByName", aws.String("on-call")).Return(nil, nil)
+	mockEncryptionKey.On("EncryptConfig", mock.Anything).Return(make([]byte, 1), nil)
+	mockOutputTable.On("PutOutput", mock.Anything).Return(nil)
+
+	input := &models.AddOutputInput{
+		UserID:      aws.String("userId"),
+		DisplayName: aws.String("on-call"),
+		OutputConfig: &models.OutputConfig{
+			SMTP: &models.SMTPConfig{
+				Host:     "smtp.example.com",
+				Username: "panther",
+				Password: "secret",
+				From:     "panther@example.com",
+				To:       []string{"oncall@example.com"},
+			},
+		},
+	}
+
+	result, err := (API{}).AddOutput(input)
+	require.NoError(t, err)
+	assert.Equal(t, "smtp", *result.OutputType)
+	// The password is redacted
+	assert.Equal(t, &models.SMTPConfig{
+		Host:     "smtp.example.com",
+		Username: "panther",
+		From:     "panther@example.com",
+		To:       []string{"oncall@example.com"},
+	}, result.OutputConfig.SMTP)
+
+	mockOutputTable.AssertExpectations(t)
+	mockEncryptionKey.AssertExpectations(t)
+}
+
+func TestAddOutputSMTPNoRecipients(t *testing.T) {
+	input := &models.AddOutputInput{
+		UserID:      aws.String("userId"),
+		DisplayName: aws.String("on-call"),
+		OutputConfig: &models.OutputConfig{
+			SMTP: &models.SMTPConfig{Host: "smtp.example.com", From: "panther@example.com"},
+		},
+	}
+
+	result, err := (API{}).AddOutput(input)
+	require.Error(t, err)
+	assert.Nil(t, result)
+}
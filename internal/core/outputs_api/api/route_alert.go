@@ -0,0 +1,46 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/api/lambda/outputs/models"
+	"github.com/panther-labs/panther/internal/core/alert_delivery/routing"
+)
+
+// RouteAlert returns the outputs an alert would be delivered to without delivering it
+func (API) RouteAlert(input *models.RouteAlertInput) (models.RouteAlertOutput, error) {
+	outputItems, err := outputsTable.GetOutputs()
+	if err != nil {
+		return nil, err
+	}
+
+	// Routing does not depend on the output configuration so there is no need to decrypt it
+	outputs := make([]*models.AlertOutput, len(outputItems))
+	for i, item := range outputItems {
+		outputs[i] = &models.AlertOutput{
+			OutputID:           item.OutputID,
+			DisplayName:        item.DisplayName,
+			OutputType:         item.OutputType,
+			DefaultForSeverity: item.DefaultForSeverity,
+			RoutingRules:       item.RoutingRules,
+		}
+	}
+
+	return routing.Route(input.Alert, outputs), nil
+}
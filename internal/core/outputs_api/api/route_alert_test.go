@@ -0,0 +1,93 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	"github.com/panther-labs/panther/api/lambda/outputs/models"
+	"github.com/panther-labs/panther/internal/core/outputs_api/table"
+)
+
+func TestRouteAlert(t *testing.T) {
+	mockOutputsTable := &mockOutputTable{}
+	outputsTable = mockOutputsTable
+	// The output config is not decrypted
+	mockEncryptionKey := new(mockEncryptionKey)
+	encryptionKey = mockEncryptionKey
+
+	routedItem := &table.AlertOutputItem{
+		OutputID:        aws.String("routedId"),
+		DisplayName:     aws.String("cloud-sec"),
+		OutputType:      aws.String("slack"),
+		EncryptedConfig: make([]byte, 1),
+		RoutingRules: []*models.AlertRoutingRule{
+			{LogTypes: []string{"AWS.CloudTrail"}, Tags: []string{"iam"}},
+		},
+	}
+	mockOutputsTable.On("GetOutputs").Return([]*table.AlertOutputItem{alertOutputItem, routedItem}, nil)
+
+	result, err := (API{}).RouteAlert(&models.RouteAlertInput{
+		Alert: &deliveryModels.Alert{
+			AnalysisID: "AWS.CloudTrail.IAMChange",
+			Type:       deliveryModels.RuleType,
+			Severity:   "HIGH",
+			LogTypes:   []string{"AWS.CloudTrail"},
+			Tags:       []string{"iam"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.RouteAlertOutput{
+		{
+			OutputID:    aws.String("outputId"),
+			DisplayName: aws.String("displayName"),
+			OutputType:  aws.String("slack"),
+			Reason:      models.RouteReasonDefaultForSeverity,
+		},
+		{
+			OutputID:    aws.String("routedId"),
+			DisplayName: aws.String("cloud-sec"),
+			OutputType:  aws.String("slack"),
+			Reason:      models.RouteReasonRoutingRule,
+			RoutingRule: aws.Int(0),
+		},
+	}, result)
+	mockOutputsTable.AssertExpectations(t)
+	mockEncryptionKey.AssertExpectations(t)
+}
+
+func TestRouteAlertGetOutputsError(t *testing.T) {
+	mockOutputsTable := &mockOutputTable{}
+	outputsTable = mockOutputsTable
+
+	mockOutputsTable.On("GetOutputs").Return([]*table.AlertOutputItem{}, errors.New("error"))
+
+	result, err := (API{}).RouteAlert(&models.RouteAlertInput{
+		Alert: &deliveryModels.Alert{Severity: "HIGH"},
+	})
+	require.Error(t, err)
+	assert.Nil(t, result)
+	mockOutputsTable.AssertExpectations(t)
+}
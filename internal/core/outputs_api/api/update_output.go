@@ -71,6 +71,8 @@ func (API) UpdateOutput(input *models.UpdateOutputInput) (*models.UpdateOutputOu
 		OutputID:           input.OutputID,
 		OutputConfig:       newConfig,
 		DefaultForSeverity: input.DefaultForSeverity,
+		RoutingRules:       input.RoutingRules,
+		DeliveryPolicy:     input.DeliveryPolicy,
 	}
 
 	alertOutputItem, err := AlertOutputToItem(alertOutput)
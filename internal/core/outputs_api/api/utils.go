@@ -43,6 +43,8 @@ func AlertOutputToItem(input *models.AlertOutput) (*table.AlertOutputItem, error
 		OutputID:           input.OutputID,
 		OutputType:         input.OutputType,
 		DefaultForSeverity: input.DefaultForSeverity,
+		RoutingRules:       input.RoutingRules,
+		DeliveryPolicy:     input.DeliveryPolicy,
 	}
 
 	if input.OutputConfig != nil {
@@ -67,6 +69,8 @@ func ItemToAlertOutput(input *table.AlertOutputItem) (alertOutput *models.AlertO
 		OutputID:           input.OutputID,
 		OutputType:         input.OutputType,
 		DefaultForSeverity: input.DefaultForSeverity,
+		RoutingRules:       input.RoutingRules,
+		DeliveryPolicy:     input.DeliveryPolicy,
 	}
 
 	// Decrypt the output before returning to the caller
@@ -82,6 +86,7 @@ func ItemToAlertOutput(input *table.AlertOutputItem) (alertOutput *models.AlertO
 func redactOutput(outputConfig *models.OutputConfig) {
 	if outputConfig.Slack != nil {
 		outputConfig.Slack.WebhookURL = redacted
+		redactTemplate(outputConfig.Slack.Template)
 	}
 	if outputConfig.PagerDuty != nil {
 		outputConfig.PagerDuty.IntegrationKey = redacted
@@ -97,12 +102,39 @@ func redactOutput(outputConfig *models.OutputConfig) {
 	}
 	if outputConfig.MsTeams != nil {
 		outputConfig.MsTeams.WebhookURL = redacted
+		redactTemplate(outputConfig.MsTeams.Template)
 	}
 	if outputConfig.Asana != nil {
 		outputConfig.Asana.PersonalAccessToken = redacted
 	}
 	if outputConfig.CustomWebhook != nil {
 		outputConfig.CustomWebhook.WebhookURL = redacted
+		redactTemplate(outputConfig.CustomWebhook.Template)
+	}
+	if outputConfig.SMTP != nil {
+		outputConfig.SMTP.Password = redacted
+	}
+	if outputConfig.GoogleChat != nil {
+		outputConfig.GoogleChat.WebhookURL = redacted
+		redactTemplate(outputConfig.GoogleChat.Template)
+	}
+	if outputConfig.Mattermost != nil {
+		outputConfig.Mattermost.WebhookURL = redacted
+		redactTemplate(outputConfig.Mattermost.Template)
+	}
+	if outputConfig.Discord != nil {
+		outputConfig.Discord.WebhookURL = redacted
+		redactTemplate(outputConfig.Discord.Template)
+	}
+}
+
+// redactTemplate redacts the header values of a payload template since they usually hold credentials
+func redactTemplate(tpl *models.PayloadTemplate) {
+	if tpl == nil {
+		return
+	}
+	for _, h := range tpl.Headers {
+		h.Value = redacted
 	}
 }
 
@@ -145,6 +177,18 @@ func getOutputType(outputConfig *models.OutputConfig) (*string, error) {
 	if outputConfig.CustomWebhook != nil {
 		return aws.String("customwebhook"), nil
 	}
+	if outputConfig.SMTP != nil {
+		return aws.String("smtp"), nil
+	}
+	if outputConfig.GoogleChat != nil {
+		return aws.String("googlechat"), nil
+	}
+	if outputConfig.Mattermost != nil {
+		return aws.String("mattermost"), nil
+	}
+	if outputConfig.Discord != nil {
+		return aws.String("discord"), nil
+	}
 
 	return nil, errors.New("no valid output configuration specified for alert output")
 }
@@ -211,9 +255,47 @@ func mergeConfigs(oldConfig, newConfig *models.OutputConfig) (*models.OutputConf
 		}
 	}
 
+	// Payload templates are replaced as a whole so redacted header values need to be restored
+	if oldConfig.Slack != nil && combinedConfig.Slack != nil {
+		mergeTemplateHeaders(oldConfig.Slack.Template, combinedConfig.Slack.Template)
+	}
+	if oldConfig.MsTeams != nil && combinedConfig.MsTeams != nil {
+		mergeTemplateHeaders(oldConfig.MsTeams.Template, combinedConfig.MsTeams.Template)
+	}
+	if oldConfig.CustomWebhook != nil && combinedConfig.CustomWebhook != nil {
+		mergeTemplateHeaders(oldConfig.CustomWebhook.Template, combinedConfig.CustomWebhook.Template)
+	}
+	if oldConfig.GoogleChat != nil && combinedConfig.GoogleChat != nil {
+		mergeTemplateHeaders(oldConfig.GoogleChat.Template, combinedConfig.GoogleChat.Template)
+	}
+	if oldConfig.Mattermost != nil && combinedConfig.Mattermost != nil {
+		mergeTemplateHeaders(oldConfig.Mattermost.Template, combinedConfig.Mattermost.Template)
+	}
+	if oldConfig.Discord != nil && combinedConfig.Discord != nil {
+		mergeTemplateHeaders(oldConfig.Discord.Template, combinedConfig.Discord.Template)
+	}
+
 	return combinedConfig, nil
 }
 
+// mergeTemplateHeaders keeps the existing values of headers that are redacted in the new template
+func mergeTemplateHeaders(oldTemplate, newTemplate *models.PayloadTemplate) {
+	if oldTemplate == nil || newTemplate == nil {
+		return
+	}
+	for _, h := range newTemplate.Headers {
+		if h.Value != redacted {
+			continue
+		}
+		for _, old := range oldTemplate.Headers {
+			if old.Key == h.Key {
+				h.Value = old.Value
+				break
+			}
+		}
+	}
+}
+
 func validateConfigByType(config *models.OutputConfig, outputType *string) error {
 	switch *outputType {
 	case "slack":
@@ -257,6 +339,23 @@ func validateConfigByType(config *models.OutputConfig, outputType *string) error
 		if config.CustomWebhook.WebhookURL != "" {
 			return nil
 		}
+	case "smtp":
+		// Credentials are optional since some relays authorize senders by IP
+		if config.SMTP.Host != "" && config.SMTP.From != "" && len(config.SMTP.To) != 0 {
+			return nil
+		}
+	case "googlechat":
+		if config.GoogleChat.WebhookURL != "" {
+			return nil
+		}
+	case "mattermost":
+		if config.Mattermost.WebhookURL != "" {
+			return nil
+		}
+	case "discord":
+		if config.Discord.WebhookURL != "" {
+			return nil
+		}
 	}
 
 	return errors.New("invalid output configuration specified for alert output, missing required fields")
@@ -0,0 +1,118 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+func TestRedactOutputTemplate(t *testing.T) {
+	config := &models.OutputConfig{
+		CustomWebhook: &models.CustomWebhookConfig{
+			WebhookURL: "https://example.com/hook",
+			Template: &models.PayloadTemplate{
+				Body:    `{"title":{{ json .Title }}}`,
+				Headers: []*models.PayloadHeader{{Key: "Authorization", Value: "Bearer token"}},
+			},
+		},
+	}
+	redactOutput(config)
+	require.Equal(t, &models.CustomWebhookConfig{
+		WebhookURL: redacted,
+		Template: &models.PayloadTemplate{
+			Body:    `{"title":{{ json .Title }}}`,
+			Headers: []*models.PayloadHeader{{Key: "Authorization", Value: redacted}},
+		},
+	}, config.CustomWebhook)
+}
+
+func TestMergeConfigsTemplateHeaders(t *testing.T) {
+	oldConfig := &models.OutputConfig{
+		Slack: &models.SlackConfig{
+			WebhookURL: "https://hooks.slack.com/services/old",
+			Template: &models.PayloadTemplate{
+				Body: `{"text":{{ json .Title }}}`,
+				Headers: []*models.PayloadHeader{
+					{Key: "X-Token", Value: "secret"},
+					{Key: "X-Removed", Value: "removed"},
+				},
+			},
+		},
+	}
+	newConfig := &models.OutputConfig{
+		Slack: &models.SlackConfig{
+			Template: &models.PayloadTemplate{
+				Body: `{"text":{{ json .Name }}}`,
+				Headers: []*models.PayloadHeader{
+					{Key: "X-Token", Value: redacted},
+					{Key: "X-Added", Value: "added"},
+				},
+			},
+		},
+	}
+	merged, err := mergeConfigs(oldConfig, newConfig)
+	require.NoError(t, err)
+	require.Equal(t, &models.SlackConfig{
+		WebhookURL: "https://hooks.slack.com/services/old",
+		Template: &models.PayloadTemplate{
+			Body: `{"text":{{ json .Name }}}`,
+			Headers: []*models.PayloadHeader{
+				{Key: "X-Token", Value: "secret"},
+				{Key: "X-Added", Value: "added"},
+			},
+		},
+	}, merged.Slack)
+}
+
+func TestMergeConfigsSMTPPassword(t *testing.T) {
+	oldConfig := &models.OutputConfig{
+		SMTP: &models.SMTPConfig{
+			Host:     "smtp.example.com",
+			Username: "panther",
+			Password: "secret",
+			From:     "panther@example.com",
+			To:       []string{"oncall@example.com"},
+		},
+	}
+	// The redacted password is kept
+	newConfig := &models.OutputConfig{
+		SMTP: &models.SMTPConfig{
+			Host:     "smtp.example.com",
+			Port:     2525,
+			Username: "panther",
+			Password: redacted,
+			From:     "panther@example.com",
+			To:       []string{"oncall@example.com", "security@example.com"},
+		},
+	}
+	merged, err := mergeConfigs(oldConfig, newConfig)
+	require.NoError(t, err)
+	require.Equal(t, &models.SMTPConfig{
+		Host:     "smtp.example.com",
+		Port:     2525,
+		Username: "panther",
+		Password: "secret",
+		From:     "panther@example.com",
+		To:       []string{"oncall@example.com", "security@example.com"},
+	}, merged.SMTP)
+}
@@ -24,6 +24,8 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/panther-labs/panther/api/lambda/outputs/models"
 )
 
 // OutputsAPI defines the interface for the outputs table which can be used for mocking.
@@ -84,4 +86,10 @@ type AlertOutputItem struct {
 	OutputType *string `json:"outputType"`
 
 	DefaultForSeverity []*string `json:"defaultForSeverity" dynamodbav:"defaultForSeverity,stringset"`
+
+	// RoutingRules is the ordered list of conditions that route alerts to the output
+	RoutingRules []*models.AlertRoutingRule `json:"routingRules,omitempty"`
+
+	// DeliveryPolicy throttles, batches or defers the alerts delivered through the output
+	DeliveryPolicy *models.DeliveryPolicy `json:"deliveryPolicy,omitempty"`
 }
@@ -28,6 +28,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/panther-labs/panther/api/lambda/outputs/models"
 	"github.com/panther-labs/panther/pkg/genericapi"
 )
 
@@ -46,6 +47,9 @@ var mockUpdateItemAlertOutput = &AlertOutputItem{
 	OutputType:         aws.String("outputType"),
 	DefaultForSeverity: aws.StringSlice([]string{"INFO", "WARN"}),
 	EncryptedConfig:    make([]byte, 1),
+	RoutingRules: []*models.AlertRoutingRule{
+		{Severities: []string{"HIGH"}, Tags: []string{"iam"}},
+	},
 }
 
 func TestUpdateOutput(t *testing.T) {
@@ -57,7 +61,8 @@ func TestUpdateOutput(t *testing.T) {
 		Set(expression.Name("lastModifiedTime"), expression.Value(mockUpdateItemAlertOutput.LastModifiedTime)).
 		Set(expression.Name("displayName"), expression.Value(mockUpdateItemAlertOutput.DisplayName)).
 		Set(expression.Name("encryptedConfig"), expression.Value(mockUpdateItemAlertOutput.EncryptedConfig)).
-		Set(expression.Name("defaultForSeverity"), expression.Value(mockUpdateItemAlertOutput.DefaultForSeverity))
+		Set(expression.Name("defaultForSeverity"), expression.Value(mockUpdateItemAlertOutput.DefaultForSeverity)).
+		Set(expression.Name("routingRules"), expression.Value(mockUpdateItemAlertOutput.RoutingRules))
 
 	expectedConditionExpression := expression.Name("outputId").Equal(expression.Value(mockUpdateItemAlertOutput.OutputID))
 
@@ -19,8 +19,14 @@ package validator
  */
 
 import (
+	"path"
+
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/panther-labs/panther/api/lambda/outputs/models"
+	"github.com/panther-labs/panther/internal/core/alert_delivery/outputs"
+	"github.com/panther-labs/panther/internal/core/alert_delivery/policy"
 )
 
 // Validator builds a custom struct validator.
@@ -29,6 +35,17 @@ func Validator() (*validator.Validate, error) {
 	if err := result.RegisterValidation("snsArn", validateAwsArn); err != nil {
 		return nil, err
 	}
+	if err := result.RegisterValidation("globPattern", validateGlobPattern); err != nil {
+		return nil, err
+	}
+	if err := result.RegisterValidation("clock", validateClock); err != nil {
+		return nil, err
+	}
+	if err := result.RegisterValidation("timezone", validateTimezone); err != nil {
+		return nil, err
+	}
+	result.RegisterStructValidation(validatePayloadTemplate, models.PayloadTemplate{})
+	result.RegisterStructValidation(validateSMTPConfig, models.SMTPConfig{})
 	return result, nil
 }
 
@@ -36,3 +53,34 @@ func validateAwsArn(fl validator.FieldLevel) bool {
 	fieldArn, err := arn.Parse(fl.Field().String())
 	return err == nil && fieldArn.Service == "sns"
 }
+
+func validateGlobPattern(fl validator.FieldLevel) bool {
+	_, err := path.Match(fl.Field().String(), "")
+	return err == nil
+}
+
+// validateClock checks that a time of day is in the HH:MM format
+func validateClock(fl validator.FieldLevel) bool {
+	return policy.ParseClock(fl.Field().String()) == nil
+}
+
+func validateTimezone(fl validator.FieldLevel) bool {
+	_, err := policy.LoadLocation(fl.Field().String())
+	return err == nil
+}
+
+// validatePayloadTemplate checks that the body of a payload template renders for a sample alert
+func validatePayloadTemplate(sl validator.StructLevel) {
+	tpl := sl.Current().Interface().(models.PayloadTemplate)
+	if err := outputs.ValidatePayloadTemplate(&tpl); err != nil {
+		sl.ReportError(tpl.Body, "Body", "Body", "payloadTemplate", err.Error())
+	}
+}
+
+// validateSMTPConfig checks that SMTP credentials are only sent over encrypted connections
+func validateSMTPConfig(sl validator.StructLevel) {
+	config := sl.Current().Interface().(models.SMTPConfig)
+	if config.Username != "" && config.Security == outputs.SMTPSecurityNone {
+		sl.ReportError(config.Security, "Security", "Security", "smtpCredentials", "")
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
 	"github.com/panther-labs/panther/api/lambda/outputs/models"
 )
 
@@ -87,3 +88,192 @@ func TestAddNonSnsArn(t *testing.T) {
 	require.Error(t, err)
 	assert.Equal(t, expectedMsg("AddOutputInput.OutputConfig.Sns", "TopicArn", "snsArn"), err.Error())
 }
+
+func TestAddOutputPayloadTemplate(t *testing.T) {
+	validator, err := Validator()
+	require.NoError(t, err)
+	input := &models.AddOutputInput{
+		UserID:      aws.String("3601990c-b566-404b-b367-3c6eacd6fe60"),
+		DisplayName: aws.String("mywebhook"),
+		OutputConfig: &models.OutputConfig{
+			CustomWebhook: &models.CustomWebhookConfig{
+				WebhookURL: "https://example.com/hook",
+				Template: &models.PayloadTemplate{
+					Body:    `{"summary":{{ json .Title }},"severity":"{{ .Severity }}"}`,
+					Method:  "PUT",
+					Headers: []*models.PayloadHeader{{Key: "Authorization", Value: "Bearer token"}},
+				},
+			},
+		},
+	}
+	assert.NoError(t, validator.Struct(input))
+
+	input.OutputConfig.CustomWebhook.Template.Body = `{"summary":{{ .Title }}}`
+	err = validator.Struct(input)
+	require.Error(t, err)
+	assert.Equal(t, expectedMsg("AddOutputInput.OutputConfig.CustomWebhook.Template", "Body", "payloadTemplate"), err.Error())
+
+	input.OutputConfig.CustomWebhook.Template.Body = `{{ .Severity }}: {{ .Title }}`
+	input.OutputConfig.CustomWebhook.Template.ContentType = "text/plain"
+	assert.NoError(t, validator.Struct(input))
+
+	input.OutputConfig.CustomWebhook.Template.Method = "GET"
+	err = validator.Struct(input)
+	require.Error(t, err)
+	assert.Equal(t, expectedMsg("AddOutputInput.OutputConfig.CustomWebhook.Template", "Method", "oneof"), err.Error())
+}
+
+func TestAddOutputRoutingRules(t *testing.T) {
+	validator, err := Validator()
+	require.NoError(t, err)
+	input := &models.AddOutputInput{
+		UserID:       aws.String("3601990c-b566-404b-b367-3c6eacd6fe60"),
+		DisplayName:  aws.String("cloud-sec"),
+		OutputConfig: &models.OutputConfig{Slack: &models.SlackConfig{WebhookURL: "https://hooks.slack.com"}},
+		RoutingRules: []*models.AlertRoutingRule{
+			{
+				Severities:  []string{"HIGH"},
+				LogTypes:    []string{"AWS.CloudTrail"},
+				Tags:        []string{"iam"},
+				AnalysisIDs: []string{"AWS.CloudTrail.*"},
+				AlertTypes:  []string{"RULE"},
+			},
+		},
+	}
+	assert.NoError(t, validator.Struct(input))
+
+	input.RoutingRules[0].AnalysisIDs = []string{"AWS.[CloudTrail"}
+	err = validator.Struct(input)
+	require.Error(t, err)
+	assert.Equal(t, expectedMsg("AddOutputInput.RoutingRules[0]", "AnalysisIDs[0]", "globPattern"), err.Error())
+
+	input.RoutingRules[0].AnalysisIDs = nil
+	input.RoutingRules[0].Severities = []string{"URGENT"}
+	err = validator.Struct(input)
+	require.Error(t, err)
+	assert.Equal(t, expectedMsg("AddOutputInput.RoutingRules[0]", "Severities[0]", "oneof"), err.Error())
+}
+
+func TestAddOutputSMTP(t *testing.T) {
+	validator, err := Validator()
+	require.NoError(t, err)
+	input := &models.AddOutputInput{
+		UserID:      aws.String("3601990c-b566-404b-b367-3c6eacd6fe60"),
+		DisplayName: aws.String("on-call"),
+		OutputConfig: &models.OutputConfig{
+			SMTP: &models.SMTPConfig{
+				Host:     "smtp.example.com",
+				Port:     465,
+				Security: "TLS",
+				From:     "panther@example.com",
+				To:       []string{"oncall@example.com"},
+			},
+		},
+	}
+	assert.NoError(t, validator.Struct(input))
+
+	input.OutputConfig.SMTP.To = []string{"on-call"}
+	err = validator.Struct(input)
+	require.Error(t, err)
+	assert.Equal(t, expectedMsg("AddOutputInput.OutputConfig.SMTP", "To[0]", "email"), err.Error())
+
+	input.OutputConfig.SMTP.To = nil
+	input.OutputConfig.SMTP.Security = "SSL"
+	err = validator.Struct(input)
+	require.Error(t, err)
+	assert.Equal(t, expectedMsg("AddOutputInput.OutputConfig.SMTP", "Security", "oneof"), err.Error())
+
+	// Credentials are not sent over unencrypted connections
+	input.OutputConfig.SMTP.Security = "NONE"
+	input.OutputConfig.SMTP.Username = "panther"
+	err = validator.Struct(input)
+	require.Error(t, err)
+	assert.Equal(t, expectedMsg("AddOutputInput.OutputConfig.SMTP", "Security", "smtpCredentials"), err.Error())
+
+	input.OutputConfig.SMTP.Security = ""
+	input.OutputConfig.SMTP.Host = "smtp example com"
+	err = validator.Struct(input)
+	require.Error(t, err)
+	assert.Equal(t, expectedMsg("AddOutputInput.OutputConfig.SMTP", "Host", "hostname_rfc1123|ip"), err.Error())
+}
+
+func TestAddOutputChatWebhooks(t *testing.T) {
+	validator, err := Validator()
+	require.NoError(t, err)
+	input := &models.AddOutputInput{
+		UserID:      aws.String("3601990c-b566-404b-b367-3c6eacd6fe60"),
+		DisplayName: aws.String("chat"),
+		OutputConfig: &models.OutputConfig{
+			GoogleChat: &models.GoogleChatConfig{WebhookURL: "https://chat.googleapis.com/v1/spaces/space/messages"},
+		},
+	}
+	assert.NoError(t, validator.Struct(input))
+
+	input.OutputConfig = &models.OutputConfig{Discord: &models.DiscordConfig{WebhookURL: "discord"}}
+	err = validator.Struct(input)
+	require.Error(t, err)
+	assert.Equal(t, expectedMsg("AddOutputInput.OutputConfig.Discord", "WebhookURL", "url"), err.Error())
+
+	input.OutputConfig = &models.OutputConfig{Mattermost: &models.MattermostConfig{
+		WebhookURL: "https://mattermost.example.com/hooks/id",
+		Template:   &models.PayloadTemplate{Body: `{"text": {{ .Title }`},
+	}}
+	err = validator.Struct(input)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "payloadTemplate")
+}
+
+func TestAddOutputDeliveryPolicy(t *testing.T) {
+	validator, err := Validator()
+	require.NoError(t, err)
+	input := &models.AddOutputInput{
+		UserID:       aws.String("3601990c-b566-404b-b367-3c6eacd6fe60"),
+		DisplayName:  aws.String("cloud-sec"),
+		OutputConfig: &models.OutputConfig{Slack: &models.SlackConfig{WebhookURL: "https://hooks.slack.com"}},
+		DeliveryPolicy: &models.DeliveryPolicy{
+			RateLimit: &models.RateLimitPolicy{Burst: 10, PerMinute: 0.5},
+			Digest:    &models.DigestPolicy{WindowSeconds: 600},
+			QuietHours: &models.QuietHoursPolicy{
+				Timezone:         "Europe/Athens",
+				Start:            "22:00",
+				End:              "08:00",
+				Days:             []string{"Saturday", "Sunday"},
+				BypassSeverities: []string{"HIGH", "CRITICAL"},
+			},
+		},
+	}
+	assert.NoError(t, validator.Struct(input))
+
+	input.DeliveryPolicy.QuietHours.Timezone = "Europe/Atlantis"
+	err = validator.Struct(input)
+	require.Error(t, err)
+	assert.Equal(t, expectedMsg("AddOutputInput.DeliveryPolicy.QuietHours", "Timezone", "timezone"), err.Error())
+
+	input.DeliveryPolicy.QuietHours.Timezone = ""
+	input.DeliveryPolicy.QuietHours.End = "8am"
+	err = validator.Struct(input)
+	require.Error(t, err)
+	assert.Equal(t, expectedMsg("AddOutputInput.DeliveryPolicy.QuietHours", "End", "clock"), err.Error())
+
+	input.DeliveryPolicy.QuietHours = nil
+	input.DeliveryPolicy.Digest.WindowSeconds = 30
+	err = validator.Struct(input)
+	require.Error(t, err)
+	assert.Equal(t, expectedMsg("AddOutputInput.DeliveryPolicy.Digest", "WindowSeconds", "min"), err.Error())
+
+	input.DeliveryPolicy.Digest = nil
+	input.DeliveryPolicy.RateLimit.PerMinute = 0
+	err = validator.Struct(input)
+	require.Error(t, err)
+	assert.Equal(t, expectedMsg("AddOutputInput.DeliveryPolicy.RateLimit", "PerMinute", "gt"), err.Error())
+}
+
+func TestRouteAlertInput(t *testing.T) {
+	validator, err := Validator()
+	require.NoError(t, err)
+	// The fields of the alert are not validated
+	assert.NoError(t, validator.Struct(&models.LambdaInput{
+		RouteAlert: &models.RouteAlertInput{Alert: &deliveryModels.Alert{OutputIds: []string{"SKIP"}}},
+	}))
+	assert.Error(t, validator.Struct(&models.RouteAlertInput{}))
+}
@@ -121,6 +121,16 @@ func (api *API) validateIntegration(input *models.PutIntegrationInput) error {
 				Message: "Cannot have duplicate prefixes in an s3 source.",
 			}
 		}
+		if err := input.S3PrefixLogTypes.Validate(); err != nil {
+			return &genericapi.InvalidInputError{
+				Message: err.Error(),
+			}
+		}
+	}
+	if err := input.EventFilters.Validate(); err != nil {
+		return &genericapi.InvalidInputError{
+			Message: err.Error(),
+		}
 	}
 
 	// Validate the new integration
@@ -289,6 +299,7 @@ func (api *API) generateNewIntegration(input *models.PutIntegrationInput) *model
 		metadata.RegionIgnoreList = input.RegionIgnoreList
 		metadata.ResourceTypeIgnoreList = input.ResourceTypeIgnoreList
 		metadata.ResourceRegexIgnoreList = input.ResourceRegexIgnoreList
+		metadata.EventFilters = input.EventFilters
 	case models.IntegrationTypeSqs:
 		metadata.SqsConfig = &models.SqsConfig{
 			S3Bucket:             api.Config.InputDataBucketName,
@@ -298,6 +309,7 @@ func (api *API) generateNewIntegration(input *models.PutIntegrationInput) *model
 			LogTypes:             input.SqsConfig.LogTypes,
 			QueueURL:             api.SourceSqsQueueURL(metadata.IntegrationID),
 		}
+		metadata.EventFilters = input.EventFilters
 	}
 	return &models.SourceIntegration{
 		SourceIntegrationMetadata: metadata,
@@ -114,6 +114,16 @@ func (api *API) validateUniqueConstraints(existingIntegrationItem *ddb.Integrati
 				Message: "Cannot have duplicate prefixes in an s3 source.",
 			}
 		}
+		if err := input.S3PrefixLogTypes.Validate(); err != nil {
+			return &genericapi.InvalidInputError{
+				Message: err.Error(),
+			}
+		}
+	}
+	if err := input.EventFilters.Validate(); err != nil {
+		return &genericapi.InvalidInputError{
+			Message: err.Error(),
+		}
 	}
 
 	existingIntegrations, err := api.ListIntegrations(&models.ListIntegrationsInput{})
@@ -184,11 +194,13 @@ func updateIntegrationDBItem(item *ddb.Integration, input *models.UpdateIntegrat
 		// These fields are replaced by S3PrefixLogTypes, clear them to avoid confusion when checking old records.
 		item.S3Prefix = ""
 		item.LogTypes = nil
+		item.EventFilters = input.EventFilters
 	case models.IntegrationTypeSqs:
 		item.IntegrationLabel = input.IntegrationLabel
 		item.SqsConfig.LogTypes = input.SqsConfig.LogTypes
 		item.SqsConfig.AllowedSourceArns = input.SqsConfig.AllowedSourceArns
 		item.SqsConfig.AllowedPrincipalArns = input.SqsConfig.AllowedPrincipalArns
+		item.EventFilters = input.EventFilters
 	}
 }
 
@@ -42,6 +42,7 @@ func integrationToItem(input *models.SourceIntegration) *ddb.Integration {
 		item.KmsKey = input.KmsKey
 		item.StackName = input.StackName
 		item.LogProcessingRole = generateLogProcessingRoleArn(input.AWSAccountID, input.IntegrationLabel)
+		item.EventFilters = input.EventFilters
 	case models.IntegrationTypeAWSScan:
 		item.AWSAccountID = input.AWSAccountID
 		item.CWEEnabled = input.CWEEnabled
@@ -64,6 +65,7 @@ func integrationToItem(input *models.SourceIntegration) *ddb.Integration {
 			AllowedPrincipalArns: input.SqsConfig.AllowedPrincipalArns,
 			AllowedSourceArns:    input.SqsConfig.AllowedSourceArns,
 		}
+		item.EventFilters = input.EventFilters
 	}
 	return item
 }
@@ -90,6 +92,7 @@ func itemToIntegration(item *ddb.Integration) *models.SourceIntegration {
 		integration.KmsKey = item.KmsKey
 		integration.StackName = item.StackName
 		integration.LogProcessingRole = item.LogProcessingRole
+		integration.EventFilters = item.EventFilters
 	case models.IntegrationTypeAWSScan:
 		integration.AWSAccountID = item.AWSAccountID
 		integration.CWEEnabled = item.CWEEnabled
@@ -116,6 +119,7 @@ func itemToIntegration(item *ddb.Integration) *models.SourceIntegration {
 			AllowedPrincipalArns: item.SqsConfig.AllowedPrincipalArns,
 			AllowedSourceArns:    item.SqsConfig.AllowedSourceArns,
 		}
+		integration.EventFilters = item.EventFilters
 	}
 	return integration
 }
@@ -60,6 +60,9 @@ type Integration struct {
 	LogProcessingRole string   `json:"logProcessingRole,omitempty"`
 
 	SqsConfig *SqsConfig `json:"sqsConfig,omitempty"`
+
+	// filters for log events of s3 and sqs integrations
+	EventFilters models.EventFilters `json:"eventFilters,omitempty"`
 }
 
 type IntegrationStatus struct {
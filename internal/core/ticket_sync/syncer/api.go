@@ -0,0 +1,65 @@
+package syncer
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	"github.com/kelseyhightower/envconfig"
+
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+	"github.com/panther-labs/panther/internal/core/ticket_sync/tickets"
+)
+
+type envConfig struct {
+	AlertsAPI              string        `required:"true" split_words:"true"`
+	OutputsAPI             string        `required:"true" split_words:"true"`
+	DeliveryStateTableName string        `required:"true" split_words:"true"`
+	AlertURLPrefix         string        `required:"true" split_words:"true"`
+	LookbackPeriod         time.Duration `required:"true" split_words:"true"`
+}
+
+// Globals
+var (
+	env          envConfig
+	awsSession   *session.Session
+	lambdaClient lambdaiface.LambdaAPI
+	stateStore   Store
+	newTracker   func(*outputModels.AlertOutput) tickets.Tracker
+)
+
+// Setup - initialize global state
+func Setup() {
+	envconfig.MustProcess("", &env)
+	awsSession = session.Must(session.NewSession())
+	lambdaClient = lambda.New(awsSession)
+	stateStore = &DynamoStore{
+		TableName: env.DeliveryStateTableName,
+		Client:    dynamodb.New(awsSession),
+	}
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	newTracker = func(output *outputModels.AlertOutput) tickets.Tracker {
+		return tickets.New(httpClient, output)
+	}
+}
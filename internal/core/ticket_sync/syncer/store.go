@@ -0,0 +1,155 @@
+package syncer
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/pkg/errors"
+)
+
+const (
+	idKey     = "id"
+	ticketKey = "ticket#"
+	// lastSyncID is the id of the item storing the time of the last successful sync
+	lastSyncID = "ticketSync#lastSync"
+	// Sync state expires 30 days after the last change of a ticket, this must exceed the lookback period
+	stateTTL = 30 * 24 * time.Hour
+)
+
+// State is the status of an alert and its ticket the last time they were synced
+type State struct {
+	AlertStatus  string `dynamodbav:"alertStatus"`
+	TicketClosed bool   `dynamodbav:"ticketClosed"`
+}
+
+// Store persists the sync state of tickets
+type Store interface {
+	// GetState returns the sync state of a ticket, or nil if it was never synced
+	GetState(outputID, ticket string) (*State, error)
+	// PutState stores the sync state of a ticket
+	PutState(outputID, ticket string, state *State, now time.Time) error
+	// GetLastSync returns the start time of the last successful sync, or the zero time if there was none
+	GetLastSync() (time.Time, error)
+	// PutLastSync stores the start time of the last successful sync
+	PutLastSync(lastSync time.Time) error
+}
+
+// DynamoStore is a Store in a DynamoDB table keyed by "id"
+type DynamoStore struct {
+	TableName string
+	Client    dynamodbiface.DynamoDBAPI
+}
+
+var _ Store = (*DynamoStore)(nil)
+
+type stateItem struct {
+	ID string `dynamodbav:"id"`
+	State
+	ExpiresAt int64 `dynamodbav:"expiresAt"`
+}
+
+// GetState implements Store
+func (s *DynamoStore) GetState(outputID, ticket string) (*State, error) {
+	output, err := s.Client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(s.TableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			idKey: {S: aws.String(stateKey(outputID, ticket))},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get sync state of ticket %s", ticket)
+	}
+	if output.Item == nil {
+		return nil, nil
+	}
+	item := stateItem{}
+	if err := dynamodbattribute.UnmarshalMap(output.Item, &item); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal sync state of ticket %s", ticket)
+	}
+	return &item.State, nil
+}
+
+// PutState implements Store
+func (s *DynamoStore) PutState(outputID, ticket string, state *State, now time.Time) error {
+	item, err := dynamodbattribute.MarshalMap(&stateItem{
+		ID:        stateKey(outputID, ticket),
+		State:     *state,
+		ExpiresAt: now.Add(stateTTL).Unix(),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal sync state of ticket %s", ticket)
+	}
+	_, err = s.Client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(s.TableName),
+		Item:      item,
+	})
+	return errors.Wrapf(err, "failed to put sync state of ticket %s", ticket)
+}
+
+type lastSyncItem struct {
+	ID        string    `dynamodbav:"id"`
+	LastSync  time.Time `dynamodbav:"lastSync"`
+	ExpiresAt int64     `dynamodbav:"expiresAt"`
+}
+
+// GetLastSync implements Store
+func (s *DynamoStore) GetLastSync() (time.Time, error) {
+	output, err := s.Client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(s.TableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			idKey: {S: aws.String(lastSyncID)},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to get last sync time")
+	}
+	item := lastSyncItem{}
+	if err := dynamodbattribute.UnmarshalMap(output.Item, &item); err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to unmarshal last sync time")
+	}
+	return item.LastSync, nil
+}
+
+// PutLastSync implements Store
+func (s *DynamoStore) PutLastSync(lastSync time.Time) error {
+	item, err := dynamodbattribute.MarshalMap(&lastSyncItem{
+		ID:        lastSyncID,
+		LastSync:  lastSync,
+		ExpiresAt: lastSync.Add(stateTTL).Unix(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal last sync time")
+	}
+	_, err = s.Client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(s.TableName),
+		Item:      item,
+	})
+	return errors.Wrap(err, "failed to put last sync time")
+}
+
+func stateKey(outputID, ticket string) string {
+	return ticketKey + outputID + "#" + ticket
+}
@@ -0,0 +1,103 @@
+package syncer
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+func TestGetStateMissing(t *testing.T) {
+	client := &testutils.DynamoDBMock{}
+	store := &DynamoStore{TableName: "table", Client: client}
+
+	client.On("GetItem", mock.MatchedBy(func(input *dynamodb.GetItemInput) bool {
+		return aws.StringValue(input.Key["id"].S) == "ticket#output-id#QR-24"
+	})).Return(&dynamodb.GetItemOutput{}, nil).Once()
+
+	state, err := store.GetState("output-id", "QR-24")
+	require.NoError(t, err)
+	assert.Nil(t, state)
+	client.AssertExpectations(t)
+}
+
+func TestGetState(t *testing.T) {
+	client := &testutils.DynamoDBMock{}
+	store := &DynamoStore{TableName: "table", Client: client}
+	item := map[string]*dynamodb.AttributeValue{
+		"id":           {S: aws.String("ticket#output-id#QR-24")},
+		"alertStatus":  {S: aws.String("RESOLVED")},
+		"ticketClosed": {BOOL: aws.Bool(true)},
+		"expiresAt":    {N: aws.String("1580428800")},
+	}
+
+	client.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{Item: item}, nil).Once()
+
+	state, err := store.GetState("output-id", "QR-24")
+	require.NoError(t, err)
+	assert.Equal(t, &State{AlertStatus: "RESOLVED", TicketClosed: true}, state)
+	client.AssertExpectations(t)
+}
+
+func TestPutState(t *testing.T) {
+	client := &testutils.DynamoDBMock{}
+	store := &DynamoStore{TableName: "table", Client: client}
+
+	client.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+		return aws.StringValue(input.Item["id"].S) == "ticket#output-id#QR-24" &&
+			aws.StringValue(input.Item["alertStatus"].S) == "OPEN" &&
+			aws.BoolValue(input.Item["ticketClosed"].BOOL) &&
+			aws.StringValue(input.Item["expiresAt"].N) == "1580428800"
+	})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	require.NoError(t, store.PutState("output-id", "QR-24", &State{AlertStatus: "OPEN", TicketClosed: true}, testNow))
+	client.AssertExpectations(t)
+}
+
+func TestLastSync(t *testing.T) {
+	client := &testutils.DynamoDBMock{}
+	store := &DynamoStore{TableName: "table", Client: client}
+
+	client.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+	lastSync, err := store.GetLastSync()
+	require.NoError(t, err)
+	assert.True(t, lastSync.IsZero())
+
+	var item map[string]*dynamodb.AttributeValue
+	client.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+		return aws.StringValue(input.Item["id"].S) == "ticketSync#lastSync" &&
+			aws.StringValue(input.Item["expiresAt"].N) == "1580428800"
+	})).Run(func(args mock.Arguments) {
+		item = args.Get(0).(*dynamodb.PutItemInput).Item
+	}).Return(&dynamodb.PutItemOutput{}, nil).Once()
+	require.NoError(t, store.PutLastSync(testNow))
+
+	client.On("GetItem", mock.Anything).Return(&dynamodb.GetItemOutput{Item: item}, nil).Once()
+	lastSync, err = store.GetLastSync()
+	require.NoError(t, err)
+	assert.True(t, testNow.Equal(lastSync))
+	client.AssertExpectations(t)
+}
@@ -0,0 +1,271 @@
+package syncer
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/alerts/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+	"github.com/panther-labs/panther/internal/core/ticket_sync/tickets"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+const (
+	// Panther user ID for automated updates (must be a valid UUID4)
+	systemUserID = "00000000-0000-4000-8000-000000000000"
+
+	listAlertsPageSize = 50
+	// maxConcurrentSyncs bounds the requests made to the ticketing APIs at once
+	maxConcurrentSyncs = 5
+)
+
+// ticketSync is a ticket to reconcile with the status of its alert
+type ticketSync struct {
+	tracker  tickets.Tracker
+	alert    *alertModels.AlertSummary
+	response *alertModels.DeliveryResponse
+	// ticket is the current state of the ticket if it was updated since the last sync
+	ticket *tickets.Ticket
+}
+
+// Sync reconciles the status of recent alerts with the tickets created by their outputs.
+//
+// Only the tickets updated since the last successful sync and the tickets of alerts whose status changed
+// since then are reconciled. The state of both sides is compared with the state of the last sync, to find
+// which side changed:
+//   - if the ticket was closed (or reopened), the alert is resolved (or reopened)
+//   - if the alert status changed, a comment is added to the ticket and the ticket is closed or reopened to match
+//   - if both sides changed, the alert status wins
+func Sync(ctx context.Context, now time.Time) error {
+	trackers, err := getTrackers()
+	if err != nil {
+		return err
+	}
+	// Nothing to sync without ticketing outputs
+	if len(trackers) == 0 {
+		return nil
+	}
+
+	lookback := now.Add(-env.LookbackPeriod)
+	since, err := stateStore.GetLastSync()
+	if err != nil {
+		return err
+	}
+	if since.Before(lookback) {
+		since = lookback
+	}
+
+	// Tickets of outputs that fail to list their updates are only synced when their alert status changes,
+	// the last sync time is not updated so they are listed again on the next sync.
+	failed := 0
+	updated := make(map[string]map[string]*tickets.Ticket)
+	for outputID, tracker := range trackers {
+		outputTickets, err := tracker.ListUpdated(ctx, since)
+		if err != nil {
+			zap.L().Warn("failed to list updated tickets", zap.String("outputId", outputID), zap.Error(err))
+			failed++
+			continue
+		}
+		updated[outputID] = make(map[string]*tickets.Ticket, len(outputTickets))
+		for _, ticket := range outputTickets {
+			updated[outputID][ticket.Key] = ticket
+		}
+	}
+
+	alerts, err := listAlerts(lookback)
+	if err != nil {
+		return err
+	}
+
+	var syncs []*ticketSync
+	for _, alert := range alerts {
+		for _, response := range alert.DeliveryResponses {
+			if !response.Success || response.TicketKey == "" {
+				continue
+			}
+			tracker, ok := trackers[response.OutputID]
+			if !ok {
+				continue
+			}
+			ticket := updated[response.OutputID][response.TicketKey]
+			if ticket == nil && alert.LastUpdatedByTime.Before(since) {
+				continue
+			}
+			syncs = append(syncs, &ticketSync{tracker: tracker, alert: alert, response: response, ticket: ticket})
+		}
+	}
+
+	failed += syncTickets(ctx, syncs, now)
+	if failed > 0 {
+		return errors.Errorf("failed to sync %d tickets", failed)
+	}
+	return stateStore.PutLastSync(now)
+}
+
+// syncTickets reconciles tickets with at most maxConcurrentSyncs at once and returns the number of failures
+func syncTickets(ctx context.Context, syncs []*ticketSync, now time.Time) int {
+	queue := make(chan *ticketSync)
+	var failed int64
+	var wg sync.WaitGroup
+	for i := 0; i < maxConcurrentSyncs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range queue {
+				if err := syncTicket(ctx, s, now); err != nil {
+					zap.L().Warn("failed to sync ticket",
+						zap.String("alertId", s.alert.AlertID),
+						zap.String("outputId", s.response.OutputID),
+						zap.String("ticket", s.response.TicketKey),
+						zap.Error(err))
+					atomic.AddInt64(&failed, 1)
+				}
+			}
+		}()
+	}
+	for _, s := range syncs {
+		queue <- s
+	}
+	close(queue)
+	wg.Wait()
+	return int(failed)
+}
+
+// syncTicket reconciles the status of an alert with one of its tickets
+func syncTicket(ctx context.Context, s *ticketSync, now time.Time) error {
+	alert, response, tracker := s.alert, s.response, s.tracker
+
+	last, err := stateStore.GetState(response.OutputID, response.TicketKey)
+	if err != nil {
+		return err
+	}
+	// Tickets are created open for open alerts
+	if last == nil {
+		last = &State{AlertStatus: alertModels.OpenStatus}
+	}
+
+	ticket := s.ticket
+	if ticket == nil {
+		if ticket, err = tracker.GetTicket(ctx, response.TicketKey); err != nil {
+			return err
+		}
+	}
+
+	alertStatus := alert.Status
+	if alertStatus == "" {
+		alertStatus = alertModels.OpenStatus
+	}
+	next := &State{AlertStatus: alertStatus, TicketClosed: ticket.Closed}
+
+	switch {
+	case alertStatus != last.AlertStatus:
+		comment := fmt.Sprintf("Panther alert status changed from %s to %s\n%s",
+			last.AlertStatus, alertStatus, env.AlertURLPrefix+alert.AlertID)
+		if err := tracker.AddComment(ctx, ticket.Key, comment); err != nil {
+			return err
+		}
+		if closed := isClosed(alertStatus); closed != ticket.Closed {
+			if err := tracker.SetClosed(ctx, ticket.Key, closed); err != nil {
+				return err
+			}
+			next.TicketClosed = closed
+		}
+	case ticket.Closed != last.TicketClosed && ticket.Closed != isClosed(alertStatus):
+		status := alertModels.OpenStatus
+		if ticket.Closed {
+			status = alertModels.ResolvedStatus
+		}
+		if err := updateAlertStatus(alert.AlertID, status); err != nil {
+			return err
+		}
+		next.AlertStatus = status
+	}
+
+	if *next == *last {
+		return nil
+	}
+	return stateStore.PutState(response.OutputID, response.TicketKey, next, now)
+}
+
+// isClosed returns true if the alert status is closed (or resolved)
+func isClosed(status string) bool {
+	return status == alertModels.ClosedStatus || status == alertModels.ResolvedStatus
+}
+
+// getTrackers returns the ticket trackers of outputs by their ID
+func getTrackers() (map[string]tickets.Tracker, error) {
+	input := outputModels.LambdaInput{GetOutputsWithSecrets: &outputModels.GetOutputsWithSecretsInput{}}
+	outputs := outputModels.GetOutputsOutput{}
+	if err := genericapi.Invoke(lambdaClient, env.OutputsAPI, &input, &outputs); err != nil {
+		return nil, errors.Wrap(err, "failed to get outputs")
+	}
+
+	trackers := make(map[string]tickets.Tracker)
+	for _, output := range outputs {
+		if tracker := newTracker(output); tracker != nil {
+			trackers[aws.StringValue(output.OutputID)] = tracker
+		}
+	}
+	return trackers, nil
+}
+
+// listAlerts returns all the alerts created after a point in time
+func listAlerts(createdAfter time.Time) ([]*alertModels.AlertSummary, error) {
+	var alerts []*alertModels.AlertSummary
+	input := alertModels.LambdaInput{
+		ListAlerts: &alertModels.ListAlertsInput{
+			PageSize:       aws.Int(listAlertsPageSize),
+			CreatedAtAfter: &createdAfter,
+		},
+	}
+	for {
+		output := alertModels.ListAlertsOutput{}
+		if err := genericapi.Invoke(lambdaClient, env.AlertsAPI, &input, &output); err != nil {
+			return nil, errors.Wrap(err, "failed to list alerts")
+		}
+		alerts = append(alerts, output.Alerts...)
+		if output.LastEvaluatedKey == nil {
+			return alerts, nil
+		}
+		input.ListAlerts.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// updateAlertStatus sets the status of an alert on behalf of the system user
+func updateAlertStatus(alertID, status string) error {
+	input := alertModels.LambdaInput{
+		UpdateAlertStatus: &alertModels.UpdateAlertStatusInput{
+			AlertIDs: []string{alertID},
+			Status:   status,
+			UserID:   systemUserID,
+		},
+	}
+	output := alertModels.UpdateAlertStatusOutput{}
+	return errors.Wrapf(genericapi.Invoke(lambdaClient, env.AlertsAPI, &input, &output),
+		"failed to update status of alert %s", alertID)
+}
@@ -0,0 +1,347 @@
+package syncer
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/alerts/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+	"github.com/panther-labs/panther/internal/core/ticket_sync/tickets"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+const (
+	testAlertID  = "84c3e4b27c702a1c31e6eb412fc377f6"
+	testOutputID = "1954ae35-f896-4d55-941f-f596ea80da86"
+)
+
+var testNow = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type mockTracker struct {
+	mock.Mock
+}
+
+func (m *mockTracker) GetTicket(ctx context.Context, key string) (*tickets.Ticket, error) {
+	args := m.Called(ctx, key)
+	return args.Get(0).(*tickets.Ticket), args.Error(1)
+}
+
+func (m *mockTracker) ListUpdated(ctx context.Context, since time.Time) ([]*tickets.Ticket, error) {
+	args := m.Called(ctx, since)
+	return args.Get(0).([]*tickets.Ticket), args.Error(1)
+}
+
+func (m *mockTracker) SetClosed(ctx context.Context, key string, closed bool) error {
+	return m.Called(ctx, key, closed).Error(0)
+}
+
+func (m *mockTracker) AddComment(ctx context.Context, key string, comment string) error {
+	return m.Called(ctx, key, comment).Error(0)
+}
+
+type mockStore struct {
+	mock.Mock
+}
+
+func (m *mockStore) GetState(outputID, ticket string) (*State, error) {
+	args := m.Called(outputID, ticket)
+	return args.Get(0).(*State), args.Error(1)
+}
+
+func (m *mockStore) PutState(outputID, ticket string, state *State, now time.Time) error {
+	return m.Called(outputID, ticket, state, now).Error(0)
+}
+
+func (m *mockStore) GetLastSync() (time.Time, error) {
+	args := m.Called()
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *mockStore) PutLastSync(lastSync time.Time) error {
+	return m.Called(lastSync).Error(0)
+}
+
+func setupTest() (*testutils.LambdaMock, *mockStore) {
+	env = envConfig{
+		AlertsAPI:      "panther-alerts-api",
+		OutputsAPI:     "panther-outputs-api",
+		AlertURLPrefix: "https://panther.io/alerts/",
+		LookbackPeriod: 24 * time.Hour,
+	}
+	client := &testutils.LambdaMock{}
+	lambdaClient = client
+	store := &mockStore{}
+	stateStore = store
+	return client, store
+}
+
+// expectInvoke mocks a lambda invocation of an API method
+func expectInvoke(t *testing.T, client *testutils.LambdaMock, function, method string, output interface{}) *mock.Call {
+	payload, err := jsoniter.Marshal(output)
+	require.NoError(t, err)
+	return client.On("Invoke", mock.MatchedBy(func(input *lambda.InvokeInput) bool {
+		return aws.StringValue(input.FunctionName) == function && strings.Contains(string(input.Payload), `"`+method+`"`)
+	})).Return(&lambda.InvokeOutput{Payload: payload}, nil)
+}
+
+func TestSyncTicket(t *testing.T) {
+	type testCase struct {
+		name        string
+		last        *State
+		alertStatus string
+		closed      bool
+		// expected changes
+		comment     bool
+		setClosed   *bool
+		updateAlert string
+		next        *State
+	}
+	testCases := []testCase{
+		{
+			name:        "unchanged",
+			last:        &State{AlertStatus: alertModels.OpenStatus},
+			alertStatus: alertModels.OpenStatus,
+		},
+		{
+			name:        "first sync unchanged",
+			alertStatus: alertModels.OpenStatus,
+		},
+		{
+			name:        "ticket closed",
+			alertStatus: alertModels.OpenStatus,
+			closed:      true,
+			updateAlert: alertModels.ResolvedStatus,
+			next:        &State{AlertStatus: alertModels.ResolvedStatus, TicketClosed: true},
+		},
+		{
+			name:        "ticket reopened",
+			last:        &State{AlertStatus: alertModels.ClosedStatus, TicketClosed: true},
+			alertStatus: alertModels.ClosedStatus,
+			updateAlert: alertModels.OpenStatus,
+			next:        &State{AlertStatus: alertModels.OpenStatus},
+		},
+		{
+			name:        "ticket closed with closed alert",
+			last:        &State{AlertStatus: alertModels.ClosedStatus},
+			alertStatus: alertModels.ClosedStatus,
+			closed:      true,
+			next:        &State{AlertStatus: alertModels.ClosedStatus, TicketClosed: true},
+		},
+		{
+			name:        "alert resolved",
+			alertStatus: alertModels.ResolvedStatus,
+			comment:     true,
+			setClosed:   aws.Bool(true),
+			next:        &State{AlertStatus: alertModels.ResolvedStatus, TicketClosed: true},
+		},
+		{
+			name:        "alert triaged",
+			alertStatus: alertModels.TriagedStatus,
+			comment:     true,
+			next:        &State{AlertStatus: alertModels.TriagedStatus},
+		},
+		{
+			name:        "alert reopened",
+			last:        &State{AlertStatus: alertModels.ResolvedStatus, TicketClosed: true},
+			alertStatus: alertModels.OpenStatus,
+			closed:      true,
+			comment:     true,
+			setClosed:   aws.Bool(false),
+			next:        &State{AlertStatus: alertModels.OpenStatus},
+		},
+		{
+			name:        "both changed",
+			last:        &State{AlertStatus: alertModels.OpenStatus},
+			alertStatus: alertModels.TriagedStatus,
+			closed:      true,
+			comment:     true,
+			setClosed:   aws.Bool(false),
+			next:        &State{AlertStatus: alertModels.TriagedStatus},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client, store := setupTest()
+			tracker := &mockTracker{}
+			ctx := context.Background()
+			alert := &alertModels.AlertSummary{AlertID: testAlertID, Status: tc.alertStatus}
+			response := &alertModels.DeliveryResponse{OutputID: testOutputID, Success: true, TicketKey: "QR-24"}
+
+			store.On("GetState", testOutputID, "QR-24").Return(tc.last, nil).Once()
+			tracker.On("GetTicket", ctx, "QR-24").Return(&tickets.Ticket{Key: "QR-24", Closed: tc.closed}, nil).Once()
+			if tc.comment {
+				lastStatus := alertModels.OpenStatus
+				if tc.last != nil {
+					lastStatus = tc.last.AlertStatus
+				}
+				comment := "Panther alert status changed from " + lastStatus + " to " + tc.alertStatus +
+					"\nhttps://panther.io/alerts/" + testAlertID
+				tracker.On("AddComment", ctx, "QR-24", comment).Return(nil).Once()
+			}
+			if tc.setClosed != nil {
+				tracker.On("SetClosed", ctx, "QR-24", *tc.setClosed).Return(nil).Once()
+			}
+			if tc.updateAlert != "" {
+				expectInvoke(t, client, "panther-alerts-api", "updateAlertStatus", alertModels.UpdateAlertStatusOutput{}).
+					Run(func(args mock.Arguments) {
+						input := alertModels.LambdaInput{}
+						require.NoError(t, jsoniter.Unmarshal(args.Get(0).(*lambda.InvokeInput).Payload, &input))
+						assert.Equal(t, &alertModels.UpdateAlertStatusInput{
+							AlertIDs: []string{testAlertID},
+							Status:   tc.updateAlert,
+							UserID:   systemUserID,
+						}, input.UpdateAlertStatus)
+					}).Once()
+			}
+			if tc.next != nil {
+				store.On("PutState", testOutputID, "QR-24", tc.next, testNow).Return(nil).Once()
+			}
+
+			require.NoError(t, syncTicket(ctx, &ticketSync{tracker: tracker, alert: alert, response: response}, testNow))
+			client.AssertExpectations(t)
+			store.AssertExpectations(t)
+			tracker.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSync(t *testing.T) {
+	client, store := setupTest()
+	tracker := &mockTracker{}
+	newTracker = func(output *outputModels.AlertOutput) tickets.Tracker {
+		if aws.StringValue(output.OutputType) == "jira" {
+			return tracker
+		}
+		return nil
+	}
+	ctx := context.Background()
+	lastSync := testNow.Add(-5 * time.Minute)
+
+	slackOutputID := "d498bac4-7ec3-432c-92b5-9a470d592c16"
+	expectInvoke(t, client, "panther-outputs-api", "getOutputsWithSecrets", outputModels.GetOutputsOutput{
+		{OutputID: aws.String(testOutputID), OutputType: aws.String("jira")},
+		{OutputID: aws.String(slackOutputID), OutputType: aws.String("slack")},
+	}).Once()
+	store.On("GetLastSync").Return(lastSync, nil).Once()
+	tracker.On("ListUpdated", ctx, lastSync).Return([]*tickets.Ticket{{Key: "QR-26", Closed: true}}, nil).Once()
+	expectInvoke(t, client, "panther-alerts-api", "listAlerts", alertModels.ListAlertsOutput{
+		Alerts: []*alertModels.AlertSummary{
+			{
+				// The alert status changed since the last sync
+				AlertID:           testAlertID,
+				Status:            alertModels.OpenStatus,
+				LastUpdatedByTime: testNow.Add(-time.Minute),
+				DeliveryResponses: []*alertModels.DeliveryResponse{
+					{OutputID: testOutputID, Success: false, StatusCode: 503},
+					{OutputID: testOutputID, Success: true, TicketKey: "QR-24"},
+					{OutputID: slackOutputID, Success: true},
+				},
+			},
+			{
+				// Neither the alert nor the ticket changed since the last sync
+				AlertID:           "a0b5e3a6c7d8e9f0a1b2c3d4e5f6a7b8",
+				LastUpdatedByTime: testNow.Add(-time.Hour),
+				DeliveryResponses: []*alertModels.DeliveryResponse{
+					{OutputID: testOutputID, Success: true, TicketKey: "QR-25"},
+				},
+			},
+		},
+		LastEvaluatedKey: aws.String(testAlertID),
+	}).Once()
+	expectInvoke(t, client, "panther-alerts-api", "listAlerts", alertModels.ListAlertsOutput{
+		Alerts: []*alertModels.AlertSummary{
+			{
+				// The ticket changed since the last sync
+				AlertID:           "f0b5e3a6c7d8e9f0a1b2c3d4e5f6a7b8",
+				Status:            alertModels.ResolvedStatus,
+				LastUpdatedByTime: testNow.Add(-time.Hour),
+				DeliveryResponses: []*alertModels.DeliveryResponse{
+					{OutputID: testOutputID, Success: true, TicketKey: "QR-26"},
+				},
+			},
+		},
+	}).Once()
+	store.On("GetState", testOutputID, "QR-24").Return((*State)(nil), nil).Once()
+	tracker.On("GetTicket", ctx, "QR-24").Return(&tickets.Ticket{Key: "QR-24"}, nil).Once()
+	store.On("GetState", testOutputID, "QR-26").Return(&State{AlertStatus: alertModels.ResolvedStatus}, nil).Once()
+	store.On("PutState", testOutputID, "QR-26", &State{AlertStatus: alertModels.ResolvedStatus, TicketClosed: true}, testNow).
+		Return(nil).Once()
+	store.On("PutLastSync", testNow).Return(nil).Once()
+
+	require.NoError(t, Sync(ctx, testNow))
+	client.AssertExpectations(t)
+	store.AssertExpectations(t)
+	tracker.AssertExpectations(t)
+}
+
+func TestSyncListUpdatedFailure(t *testing.T) {
+	client, store := setupTest()
+	tracker := &mockTracker{}
+	newTracker = func(*outputModels.AlertOutput) tickets.Tracker { return tracker }
+	ctx := context.Background()
+
+	expectInvoke(t, client, "panther-outputs-api", "getOutputsWithSecrets", outputModels.GetOutputsOutput{
+		{OutputID: aws.String(testOutputID), OutputType: aws.String("jira")},
+	}).Once()
+	// The first sync covers the lookback period
+	store.On("GetLastSync").Return(time.Time{}, nil).Once()
+	tracker.On("ListUpdated", ctx, testNow.Add(-24*time.Hour)).Return(([]*tickets.Ticket)(nil), errors.New("timeout")).Once()
+	expectInvoke(t, client, "panther-alerts-api", "listAlerts", alertModels.ListAlertsOutput{
+		Alerts: []*alertModels.AlertSummary{
+			{
+				AlertID: testAlertID,
+				DeliveryResponses: []*alertModels.DeliveryResponse{
+					{OutputID: testOutputID, Success: true, TicketKey: "QR-24"},
+				},
+			},
+		},
+	}).Once()
+
+	// The last sync time is kept to list the updated tickets again
+	require.Error(t, Sync(ctx, testNow))
+	client.AssertExpectations(t)
+	store.AssertExpectations(t)
+	tracker.AssertExpectations(t)
+}
+
+func TestSyncNoTicketOutputs(t *testing.T) {
+	client, _ := setupTest()
+	newTracker = func(*outputModels.AlertOutput) tickets.Tracker { return nil }
+
+	expectInvoke(t, client, "panther-outputs-api", "getOutputsWithSecrets", outputModels.GetOutputsOutput{
+		{OutputID: aws.String(testOutputID), OutputType: aws.String("slack")},
+	}).Once()
+
+	require.NoError(t, Sync(context.Background(), testNow))
+	client.AssertExpectations(t)
+}
@@ -0,0 +1,121 @@
+package tickets
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+const (
+	githubBaseURL     = "https://api.github.com"
+	githubStateClosed = "closed"
+	githubStateOpen   = "open"
+	githubPageSize    = 100
+)
+
+// GithubTracker tracks the issues created by a Github output, keyed by their issue number
+type GithubTracker struct {
+	client  HTTPiface
+	config  *outputModels.GithubConfig
+	baseURL string
+}
+
+var _ Tracker = (*GithubTracker)(nil)
+
+// GetTicket implements Tracker
+func (t *GithubTracker) GetTicket(ctx context.Context, key string) (*Ticket, error) {
+	var issue struct {
+		State string `json:"state"`
+	}
+	if err := t.request(ctx, http.MethodGet, t.issueURL(key), nil, &issue); err != nil {
+		return nil, err
+	}
+	if issue.State != githubStateClosed && issue.State != githubStateOpen {
+		return nil, errors.Errorf("github issue %s has unknown state %q", key, issue.State)
+	}
+	return &Ticket{Key: key, Closed: issue.State == githubStateClosed}, nil
+}
+
+// ListUpdated implements Tracker
+//
+// Pull requests are listed as issues by Github and are skipped.
+func (t *GithubTracker) ListUpdated(ctx context.Context, since time.Time) ([]*Ticket, error) {
+	query := url.Values{
+		"state":    {"all"},
+		"since":    {since.UTC().Format(time.RFC3339)},
+		"per_page": {strconv.Itoa(githubPageSize)},
+	}
+	var result []*Ticket
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var issues []struct {
+			Number      int         `json:"number"`
+			State       string      `json:"state"`
+			PullRequest interface{} `json:"pull_request"`
+		}
+		listURL := t.baseURL + "/repos/" + t.config.RepoName + "/issues?" + query.Encode()
+		if err := t.request(ctx, http.MethodGet, listURL, nil, &issues); err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			if issue.PullRequest != nil {
+				continue
+			}
+			result = append(result, &Ticket{Key: strconv.Itoa(issue.Number), Closed: issue.State == githubStateClosed})
+		}
+		if len(issues) < githubPageSize {
+			return result, nil
+		}
+	}
+}
+
+// SetClosed implements Tracker
+func (t *GithubTracker) SetClosed(ctx context.Context, key string, closed bool) error {
+	state := githubStateOpen
+	if closed {
+		state = githubStateClosed
+	}
+	body := map[string]string{"state": state}
+	return t.request(ctx, http.MethodPatch, t.issueURL(key), body, nil)
+}
+
+// AddComment implements Tracker
+func (t *GithubTracker) AddComment(ctx context.Context, key string, comment string) error {
+	body := map[string]string{"body": comment}
+	return t.request(ctx, http.MethodPost, t.issueURL(key)+"/comments", body, nil)
+}
+
+func (t *GithubTracker) issueURL(key string) string {
+	return t.baseURL + "/repos/" + t.config.RepoName + "/issues/" + key
+}
+
+func (t *GithubTracker) request(ctx context.Context, method, url string, body, result interface{}) error {
+	headers := map[string]string{
+		"Authorization": "token " + t.config.Token,
+	}
+	return request(ctx, t.client, method, url, headers, body, result)
+}
@@ -0,0 +1,86 @@
+package tickets
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+func newGithubTracker(server *httptest.Server) *GithubTracker {
+	config := &outputModels.GithubConfig{RepoName: "profile/reponame", Token: "github-token"}
+	return &GithubTracker{client: server.Client(), config: config, baseURL: server.URL}
+}
+
+func TestGithubGetTicket(t *testing.T) {
+	server, _ := newTestServer(t, map[string]string{
+		"GET /repos/profile/reponame/issues/1347": `{"number":1347,"state":"open"}`,
+	})
+
+	ticket, err := newGithubTracker(server).GetTicket(context.Background(), "1347")
+	require.NoError(t, err)
+	assert.Equal(t, &Ticket{Key: "1347", Closed: false}, ticket)
+}
+
+func TestGithubListUpdated(t *testing.T) {
+	server, requests := newTestServer(t, map[string]string{
+		"GET /repos/profile/reponame/issues": `[
+			{"number":1347,"state":"closed"},
+			{"number":1348,"state":"open","pull_request":{"url":"https://api.github.com/repos/profile/reponame/pulls/1348"}},
+			{"number":1349,"state":"open"}
+		]`,
+	})
+	since := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	updated, err := newGithubTracker(server).ListUpdated(context.Background(), since)
+	require.NoError(t, err)
+	assert.Equal(t, []*Ticket{{Key: "1347", Closed: true}, {Key: "1349", Closed: false}}, updated)
+	require.Len(t, *requests, 1)
+	query := (*requests)[0].Query
+	assert.Equal(t, "all", query.Get("state"))
+	assert.Equal(t, "2020-01-01T00:00:00Z", query.Get("since"))
+	assert.Equal(t, "1", query.Get("page"))
+}
+
+func TestGithubSetClosed(t *testing.T) {
+	server, requests := newTestServer(t, map[string]string{
+		"PATCH /repos/profile/reponame/issues/1347": `{"number":1347,"state":"closed"}`,
+	})
+
+	require.NoError(t, newGithubTracker(server).SetClosed(context.Background(), "1347", true))
+	require.Len(t, *requests, 1)
+	assert.JSONEq(t, `{"state":"closed"}`, (*requests)[0].Body)
+}
+
+func TestGithubAddComment(t *testing.T) {
+	server, requests := newTestServer(t, map[string]string{
+		"POST /repos/profile/reponame/issues/1347/comments": `{"id":1}`,
+	})
+
+	require.NoError(t, newGithubTracker(server).AddComment(context.Background(), "1347", "comment"))
+	require.Len(t, *requests, 1)
+	assert.JSONEq(t, `{"body":"comment"}`, (*requests)[0].Body)
+}
@@ -0,0 +1,155 @@
+package tickets
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+const (
+	jiraEndpoint       = "/rest/api/latest/issue/"
+	jiraSearchEndpoint = "/rest/api/latest/search"
+	jiraSearchPageSize = 100
+	// Statuses in the "done" category close an issue
+	jiraDoneCategory = "done"
+)
+
+// JiraTracker tracks the issues created by a Jira output
+type JiraTracker struct {
+	client HTTPiface
+	config *outputModels.JiraConfig
+}
+
+var _ Tracker = (*JiraTracker)(nil)
+
+type jiraStatus struct {
+	StatusCategory struct {
+		Key string `json:"key"`
+	} `json:"statusCategory"`
+}
+
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Status jiraStatus `json:"status"`
+	} `json:"fields"`
+}
+
+func (issue *jiraIssue) closed() bool {
+	return issue.Fields.Status.StatusCategory.Key == jiraDoneCategory
+}
+
+// GetTicket implements Tracker
+func (t *JiraTracker) GetTicket(ctx context.Context, key string) (*Ticket, error) {
+	issue := jiraIssue{}
+	if err := t.request(ctx, http.MethodGet, t.issueURL(key)+"?fields=status", nil, &issue); err != nil {
+		return nil, err
+	}
+	return &Ticket{Key: key, Closed: issue.closed()}, nil
+}
+
+// ListUpdated implements Tracker
+//
+// The issues of the output's project are searched by their age in minutes,
+// since JQL dates are in the timezone of the Jira user.
+func (t *JiraTracker) ListUpdated(ctx context.Context, since time.Time) ([]*Ticket, error) {
+	minutes := int64(math.Ceil(time.Since(since).Minutes()))
+	if minutes < 1 {
+		minutes = 1
+	}
+	query := url.Values{
+		"jql":        {fmt.Sprintf(`project = "%s" AND updated >= -%dm`, t.config.ProjectKey, minutes)},
+		"fields":     {"status"},
+		"maxResults": {strconv.Itoa(jiraSearchPageSize)},
+	}
+	searchURL := strings.TrimSuffix(t.config.OrgDomain, "/") + jiraSearchEndpoint
+	var result []*Ticket
+	for {
+		query.Set("startAt", strconv.Itoa(len(result)))
+		var page struct {
+			Total  int          `json:"total"`
+			Issues []*jiraIssue `json:"issues"`
+		}
+		if err := t.request(ctx, http.MethodGet, searchURL+"?"+query.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		for _, issue := range page.Issues {
+			result = append(result, &Ticket{Key: issue.Key, Closed: issue.closed()})
+		}
+		if len(page.Issues) == 0 || len(result) >= page.Total {
+			return result, nil
+		}
+	}
+}
+
+// SetClosed implements Tracker
+//
+// Jira issues change status through the transitions of their workflow,
+// the first available transition into (or out of) the "done" category is used.
+func (t *JiraTracker) SetClosed(ctx context.Context, key string, closed bool) error {
+	var transitions struct {
+		Transitions []struct {
+			ID string     `json:"id"`
+			To jiraStatus `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := t.request(ctx, http.MethodGet, t.issueURL(key)+"/transitions", nil, &transitions); err != nil {
+		return err
+	}
+	for _, transition := range transitions.Transitions {
+		if (transition.To.StatusCategory.Key == jiraDoneCategory) != closed {
+			continue
+		}
+		body := map[string]interface{}{
+			"transition": map[string]string{"id": transition.ID},
+		}
+		return t.request(ctx, http.MethodPost, t.issueURL(key)+"/transitions", body, nil)
+	}
+	return errors.Errorf("jira issue %s has no transition to close=%v", key, closed)
+}
+
+// AddComment implements Tracker
+func (t *JiraTracker) AddComment(ctx context.Context, key string, comment string) error {
+	body := map[string]string{"body": comment}
+	return t.request(ctx, http.MethodPost, t.issueURL(key)+"/comment", body, nil)
+}
+
+func (t *JiraTracker) issueURL(key string) string {
+	return strings.TrimSuffix(t.config.OrgDomain, "/") + jiraEndpoint + url.PathEscape(key)
+}
+
+func (t *JiraTracker) request(ctx context.Context, method, url string, body, result interface{}) error {
+	auth := t.config.UserName + ":" + t.config.APIKey
+	headers := map[string]string{
+		"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(auth)),
+	}
+	return request(ctx, t.client, method, url, headers, body, result)
+}
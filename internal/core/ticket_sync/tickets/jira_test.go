@@ -0,0 +1,155 @@
+package tickets
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+// testRequest is a request received by a test server
+type testRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   string
+}
+
+// newTestServer returns a server replying to "METHOD /path" with the configured bodies
+func newTestServer(t *testing.T, responses map[string]string) (*httptest.Server, *[]testRequest) {
+	requests := &[]testRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		*requests = append(*requests, testRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query(), Body: string(body)})
+		response, ok := responses[r.Method+" "+r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if response == "" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func newJiraTracker(server *httptest.Server) *JiraTracker {
+	config := &outputModels.JiraConfig{OrgDomain: server.URL, UserName: "username", APIKey: "apikey", ProjectKey: "QR"}
+	return &JiraTracker{client: server.Client(), config: config}
+}
+
+func TestJiraGetTicket(t *testing.T) {
+	server, _ := newTestServer(t, map[string]string{
+		"GET /rest/api/latest/issue/QR-24": `{"key":"QR-24","fields":{"status":{"statusCategory":{"key":"done"}}}}`,
+	})
+
+	ticket, err := newJiraTracker(server).GetTicket(context.Background(), "QR-24")
+	require.NoError(t, err)
+	assert.Equal(t, &Ticket{Key: "QR-24", Closed: true}, ticket)
+}
+
+func TestJiraGetTicketNotFound(t *testing.T) {
+	server, _ := newTestServer(t, map[string]string{})
+
+	_, err := newJiraTracker(server).GetTicket(context.Background(), "QR-24")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
+
+func TestJiraListUpdated(t *testing.T) {
+	server, requests := newTestServer(t, map[string]string{
+		"GET /rest/api/latest/search": `{"startAt":0,"maxResults":100,"total":2,"issues":[
+			{"key":"QR-24","fields":{"status":{"statusCategory":{"key":"done"}}}},
+			{"key":"QR-25","fields":{"status":{"statusCategory":{"key":"indeterminate"}}}}
+		]}`,
+	})
+
+	updated, err := newJiraTracker(server).ListUpdated(context.Background(), time.Now().Add(-10*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []*Ticket{{Key: "QR-24", Closed: true}, {Key: "QR-25", Closed: false}}, updated)
+	require.Len(t, *requests, 1)
+	query := (*requests)[0].Query
+	assert.Equal(t, `project = "QR" AND updated >= -11m`, query.Get("jql"))
+	assert.Equal(t, "status", query.Get("fields"))
+	assert.Equal(t, "0", query.Get("startAt"))
+}
+
+func TestJiraListUpdatedPages(t *testing.T) {
+	server, requests := newTestServer(t, map[string]string{
+		"GET /rest/api/latest/search": `{"total":3,"issues":[{"key":"QR-24","fields":{"status":{"statusCategory":{"key":"new"}}}}]}`,
+	})
+
+	updated, err := newJiraTracker(server).ListUpdated(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Len(t, updated, 3)
+	require.Len(t, *requests, 3)
+	assert.Equal(t, `project = "QR" AND updated >= -1m`, (*requests)[0].Query.Get("jql"))
+	assert.Equal(t, "2", (*requests)[2].Query.Get("startAt"))
+}
+
+func TestJiraSetClosed(t *testing.T) {
+	server, requests := newTestServer(t, map[string]string{
+		"GET /rest/api/latest/issue/QR-24/transitions": `{"transitions":[
+			{"id":"11","to":{"statusCategory":{"key":"new"}}},
+			{"id":"31","to":{"statusCategory":{"key":"done"}}}
+		]}`,
+		"POST /rest/api/latest/issue/QR-24/transitions": "",
+	})
+	tracker := newJiraTracker(server)
+
+	require.NoError(t, tracker.SetClosed(context.Background(), "QR-24", true))
+	require.Len(t, *requests, 2)
+	assert.JSONEq(t, `{"transition":{"id":"31"}}`, (*requests)[1].Body)
+
+	require.NoError(t, tracker.SetClosed(context.Background(), "QR-24", false))
+	require.Len(t, *requests, 4)
+	assert.JSONEq(t, `{"transition":{"id":"11"}}`, (*requests)[3].Body)
+}
+
+func TestJiraSetClosedNoTransition(t *testing.T) {
+	server, requests := newTestServer(t, map[string]string{
+		"GET /rest/api/latest/issue/QR-24/transitions": `{"transitions":[{"id":"11","to":{"statusCategory":{"key":"new"}}}]}`,
+	})
+
+	require.Error(t, newJiraTracker(server).SetClosed(context.Background(), "QR-24", true))
+	assert.Len(t, *requests, 1)
+}
+
+func TestJiraAddComment(t *testing.T) {
+	server, requests := newTestServer(t, map[string]string{
+		"POST /rest/api/latest/issue/QR-24/comment": `{"id":"10000"}`,
+	})
+
+	require.NoError(t, newJiraTracker(server).AddComment(context.Background(), "QR-24", "comment"))
+	require.Len(t, *requests, 1)
+	assert.JSONEq(t, `{"body":"comment"}`, (*requests)[0].Body)
+}
@@ -0,0 +1,113 @@
+package tickets
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+// Ticket is the state of an issue created by a ticketing output
+type Ticket struct {
+	Key    string
+	Closed bool
+}
+
+// Tracker reads and updates the issues created by a ticketing output
+type Tracker interface {
+	// GetTicket returns the current state of an issue
+	GetTicket(ctx context.Context, key string) (*Ticket, error)
+	// ListUpdated returns the current state of the issues updated since a point in time
+	ListUpdated(ctx context.Context, since time.Time) ([]*Ticket, error)
+	// SetClosed closes or reopens an issue
+	SetClosed(ctx context.Context, key string, closed bool) error
+	// AddComment adds a comment to an issue
+	AddComment(ctx context.Context, key string, comment string) error
+}
+
+// HTTPiface is an interface for http.Client to simplify unit testing.
+type HTTPiface interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// New returns the tracker of an output, or nil if the output does not create tickets.
+func New(client HTTPiface, output *outputModels.AlertOutput) Tracker {
+	if output.OutputConfig == nil {
+		return nil
+	}
+	switch {
+	case output.OutputConfig.Jira != nil:
+		return &JiraTracker{client: client, config: output.OutputConfig.Jira}
+	case output.OutputConfig.Github != nil:
+		return &GithubTracker{client: client, config: output.OutputConfig.Github, baseURL: githubBaseURL}
+	default:
+		return nil
+	}
+}
+
+// request sends a JSON request and decodes the JSON response into result (if not nil)
+func request(
+	ctx context.Context,
+	client HTTPiface,
+	method, url string,
+	headers map[string]string,
+	body interface{},
+	result interface{},
+) error {
+
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = jsoniter.Marshal(body); err != nil {
+			return errors.Wrap(err, "failed to marshal request")
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	response, err := client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "%s %s failed", method, url)
+	}
+	defer response.Body.Close()
+
+	responseBody, _ := ioutil.ReadAll(response.Body)
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return errors.Errorf("%s %s failed: %s: %s", method, url, response.Status, string(responseBody))
+	}
+	if result == nil {
+		return nil
+	}
+	return errors.Wrapf(jsoniter.Unmarshal(responseBody, result), "failed to unmarshal response of %s %s", method, url)
+}
@@ -0,0 +1,44 @@
+package tickets
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+func TestNew(t *testing.T) {
+	client := &http.Client{}
+	jira := &outputModels.JiraConfig{OrgDomain: "https://panther-labs.atlassian.net"}
+	github := &outputModels.GithubConfig{RepoName: "profile/reponame"}
+
+	assert.Equal(t, &JiraTracker{client: client, config: jira},
+		New(client, &outputModels.AlertOutput{OutputConfig: &outputModels.OutputConfig{Jira: jira}}))
+	assert.Equal(t, &GithubTracker{client: client, config: github, baseURL: "https://api.github.com"},
+		New(client, &outputModels.AlertOutput{OutputConfig: &outputModels.OutputConfig{Github: github}}))
+	assert.Nil(t, New(client, &outputModels.AlertOutput{
+		OutputType:   aws.String("slack"),
+		OutputConfig: &outputModels.OutputConfig{Slack: &outputModels.SlackConfig{}},
+	}))
+}
@@ -107,6 +107,74 @@ func GenerateLogViews(tables []*awsglue.GlueTableMetadata) (sqlStatements []stri
 	return sqlStatements, nil
 }
 
+// NormalizedTable is a log table with events normalized to some event classes (see package normalize)
+type NormalizedTable struct {
+	Table   *awsglue.GlueTableMetadata
+	Classes []string
+}
+
+// normalizedViewColumns are the columns of the views over normalized events (partitions are added per table)
+var normalizedViewColumns = []string{
+	"p_event_time",
+	"p_log_type",
+	"p_normalized",
+	"p_parse_time",
+	"p_row_id",
+	"p_source_id",
+	"p_source_label",
+}
+
+// CreateOrReplaceNormalizedViews will update Athena with a view per event class over the normalized tables provided
+func CreateOrReplaceNormalizedViews(athenaClient athenaiface.AthenaAPI, workgroup string, tables []NormalizedTable) error {
+	for _, sql := range GenerateNormalizedViews(tables) {
+		_, err := awsathena.RunQuery(athenaClient, workgroup, pantherdb.ViewsDatabase, sql)
+		if err != nil {
+			return errors.Wrapf(err, "CreateOrReplaceNormalizedViews() failed for WorkGroup %s for: %s", workgroup, sql)
+		}
+	}
+	return nil
+}
+
+// GenerateNormalizedViews creates a view per event class in the panther views database.
+// Each view selects the normalized events of the class from all tables with events of that class.
+func GenerateNormalizedViews(tables []NormalizedTable) (sqlStatements []string) {
+	tablesByClass := make(map[string][]*awsglue.GlueTableMetadata)
+	for _, t := range tables {
+		for _, class := range t.Classes {
+			tablesByClass[class] = append(tablesByClass[class], t.Table)
+		}
+	}
+	classes := make([]string, 0, len(tablesByClass))
+	for class := range tablesByClass {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes) // order needs to be preserved
+	for _, class := range classes {
+		sqlStatements = append(sqlStatements, generateNormalizedView(class, tablesByClass[class]))
+	}
+	return sqlStatements
+}
+
+func generateNormalizedView(class string, tables []*awsglue.GlueTableMetadata) string {
+	var sqlLines []string
+	sqlLines = append(sqlLines, fmt.Sprintf("create or replace view %s.normalized_%s as", pantherdb.ViewsDatabase, class))
+	for i, table := range tables {
+		selectColumns := make([]string, 0, len(normalizedViewColumns)+len(table.PartitionKeys())+1)
+		selectColumns = append(selectColumns, fmt.Sprintf("'%s' AS p_db_name", table.DatabaseName()))
+		selectColumns = append(selectColumns, normalizedViewColumns...)
+		for _, partitionKey := range table.PartitionKeys() {
+			selectColumns = append(selectColumns, partitionKey.Name)
+		}
+		sqlLines = append(sqlLines, fmt.Sprintf("select %s from %s.%s where p_normalized.class = '%s'",
+			strings.Join(selectColumns, ","), table.DatabaseName(), table.TableName(), class))
+		if i < len(tables)-1 {
+			sqlLines = append(sqlLines, "\tunion all")
+		}
+	}
+	sqlLines = append(sqlLines, ";\n")
+	return strings.Join(sqlLines, "\n")
+}
+
 // generateViewAllLogs creates a view over all log sources in log db using "panther" fields
 func generateViewAllLogs(tables []*awsglue.GlueTableMetadata) (sql string, logTables []*awsglue.GlueTableMetadata, err error) {
 	// some logTypes are under different databases and we want a view per database
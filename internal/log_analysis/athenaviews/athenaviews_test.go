@@ -45,38 +45,38 @@ func TestGenerateViewAllLogs(t *testing.T) {
 	table2 := awsglue.NewGlueTableMetadata(pantherdb.LogProcessingDatabase, "table2", "test table2", awsglue.GlueTableHourly, &table2Event{})
 	// nolint (lll)
 	expectedAllLogsSQL := `create or replace view panther_views.all_logs as
-select 'panther_logs' AS p_db_name,day,hour,month,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,partition_time,year from panther_logs.table1
+select 'panther_logs' AS p_db_name,day,hour,month,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_logs.table1
 	union all
-select 'panther_logs' AS p_db_name,day,hour,month,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,partition_time,year from panther_logs.table2
+select 'panther_logs' AS p_db_name,day,hour,month,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_logs.table2
 ;
 `
 	// nolint (lll)
 	expectedAllRuleMatchesSQL := `create or replace view panther_views.all_rule_matches as
-select 'panther_rule_matches' AS p_db_name,day,hour,month,p_alert_context,p_alert_creation_time,p_alert_id,p_alert_update_time,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_rule_id,p_rule_reports,p_rule_tags,p_source_id,p_source_label,partition_time,year from panther_rule_matches.table1
+select 'panther_rule_matches' AS p_db_name,day,hour,month,p_alert_context,p_alert_creation_time,p_alert_id,p_alert_update_time,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_rule_id,p_rule_reports,p_rule_tags,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_rule_matches.table1
 	union all
-select 'panther_rule_matches' AS p_db_name,day,hour,month,p_alert_context,p_alert_creation_time,p_alert_id,p_alert_update_time,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_rule_id,p_rule_reports,p_rule_tags,p_source_id,p_source_label,partition_time,year from panther_rule_matches.table2
+select 'panther_rule_matches' AS p_db_name,day,hour,month,p_alert_context,p_alert_creation_time,p_alert_id,p_alert_update_time,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_rule_id,p_rule_reports,p_rule_tags,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_rule_matches.table2
 ;
 `
 	// nolint (lll)
 	expectedAllRuleErrorsSQL := `create or replace view panther_views.all_rule_errors as
-select 'panther_rule_errors' AS p_db_name,day,hour,month,p_alert_context,p_alert_creation_time,p_alert_id,p_alert_update_time,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_rule_error,p_rule_id,p_rule_reports,p_rule_tags,p_source_id,p_source_label,partition_time,year from panther_rule_errors.table1
+select 'panther_rule_errors' AS p_db_name,day,hour,month,p_alert_context,p_alert_creation_time,p_alert_id,p_alert_update_time,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_rule_error,p_rule_id,p_rule_reports,p_rule_tags,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_rule_errors.table1
 	union all
-select 'panther_rule_errors' AS p_db_name,day,hour,month,p_alert_context,p_alert_creation_time,p_alert_id,p_alert_update_time,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_rule_error,p_rule_id,p_rule_reports,p_rule_tags,p_source_id,p_source_label,partition_time,year from panther_rule_errors.table2
+select 'panther_rule_errors' AS p_db_name,day,hour,month,p_alert_context,p_alert_creation_time,p_alert_id,p_alert_update_time,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_rule_error,p_rule_id,p_rule_reports,p_rule_tags,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_rule_errors.table2
 ;
 `
 	// nolint (lll)
 	expectedAllDatabasesSQL := `create or replace view panther_views.all_databases as
-select 'panther_logs' AS p_db_name,day,hour,month,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,partition_time,year from panther_logs.table1
+select 'panther_logs' AS p_db_name,day,hour,month,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_logs.table1
 	union all
-select 'panther_logs' AS p_db_name,day,hour,month,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,partition_time,year from panther_logs.table2
+select 'panther_logs' AS p_db_name,day,hour,month,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_logs.table2
 	union all
-select 'panther_rule_matches' AS p_db_name,day,hour,month,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,partition_time,year from panther_rule_matches.table1
+select 'panther_rule_matches' AS p_db_name,day,hour,month,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_rule_matches.table1
 	union all
-select 'panther_rule_matches' AS p_db_name,day,hour,month,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,partition_time,year from panther_rule_matches.table2
+select 'panther_rule_matches' AS p_db_name,day,hour,month,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_rule_matches.table2
 	union all
-select 'panther_rule_errors' AS p_db_name,day,hour,month,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,partition_time,year from panther_rule_errors.table1
+select 'panther_rule_errors' AS p_db_name,day,hour,month,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_rule_errors.table1
 	union all
-select 'panther_rule_errors' AS p_db_name,day,hour,month,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,partition_time,year from panther_rule_errors.table2
+select 'panther_rule_errors' AS p_db_name,day,hour,month,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_rule_errors.table2
 ;
 `
 
@@ -94,38 +94,38 @@ func TestGenerateViewAllCloudSecurity(t *testing.T) {
 	table2 := awsglue.NewGlueTableMetadata(pantherdb.CloudSecurityDatabase, "table2", "test table2", awsglue.GlueTableHourly, &table2Event{})
 	// nolint (lll)
 	expectedAllCloudsecSQL := `create or replace view panther_views.all_cloudsecurity as
-select 'panther_cloudsecurity' AS p_db_name,day,hour,month,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,partition_time,year from panther_cloudsecurity.table1
+select 'panther_cloudsecurity' AS p_db_name,day,hour,month,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_cloudsecurity.table1
 	union all
-select 'panther_cloudsecurity' AS p_db_name,day,hour,month,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,partition_time,year from panther_cloudsecurity.table2
+select 'panther_cloudsecurity' AS p_db_name,day,hour,month,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_cloudsecurity.table2
 ;
 `
 	// nolint (lll)
 	expectedAllRuleMatchesSQL := `create or replace view panther_views.all_rule_matches as
-select 'panther_rule_matches' AS p_db_name,day,hour,month,p_alert_context,p_alert_creation_time,p_alert_id,p_alert_update_time,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_rule_id,p_rule_reports,p_rule_tags,p_source_id,p_source_label,partition_time,year from panther_rule_matches.table1
+select 'panther_rule_matches' AS p_db_name,day,hour,month,p_alert_context,p_alert_creation_time,p_alert_id,p_alert_update_time,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_rule_id,p_rule_reports,p_rule_tags,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_rule_matches.table1
 	union all
-select 'panther_rule_matches' AS p_db_name,day,hour,month,p_alert_context,p_alert_creation_time,p_alert_id,p_alert_update_time,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_rule_id,p_rule_reports,p_rule_tags,p_source_id,p_source_label,partition_time,year from panther_rule_matches.table2
+select 'panther_rule_matches' AS p_db_name,day,hour,month,p_alert_context,p_alert_creation_time,p_alert_id,p_alert_update_time,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_rule_id,p_rule_reports,p_rule_tags,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_rule_matches.table2
 ;
 `
 	// nolint (lll)
 	expectedAllRuleErrorsSQL := `create or replace view panther_views.all_rule_errors as
-select 'panther_rule_errors' AS p_db_name,day,hour,month,p_alert_context,p_alert_creation_time,p_alert_id,p_alert_update_time,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_rule_error,p_rule_id,p_rule_reports,p_rule_tags,p_source_id,p_source_label,partition_time,year from panther_rule_errors.table1
+select 'panther_rule_errors' AS p_db_name,day,hour,month,p_alert_context,p_alert_creation_time,p_alert_id,p_alert_update_time,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_rule_error,p_rule_id,p_rule_reports,p_rule_tags,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_rule_errors.table1
 	union all
-select 'panther_rule_errors' AS p_db_name,day,hour,month,p_alert_context,p_alert_creation_time,p_alert_id,p_alert_update_time,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_rule_error,p_rule_id,p_rule_reports,p_rule_tags,p_source_id,p_source_label,partition_time,year from panther_rule_errors.table2
+select 'panther_rule_errors' AS p_db_name,day,hour,month,p_alert_context,p_alert_creation_time,p_alert_id,p_alert_update_time,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_rule_error,p_rule_id,p_rule_reports,p_rule_tags,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_rule_errors.table2
 ;
 `
 	// nolint (lll)
 	expectedAllDatabasesSQL := `create or replace view panther_views.all_databases as
-select 'panther_cloudsecurity' AS p_db_name,day,hour,month,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,partition_time,year from panther_cloudsecurity.table1
+select 'panther_cloudsecurity' AS p_db_name,day,hour,month,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_cloudsecurity.table1
 	union all
-select 'panther_cloudsecurity' AS p_db_name,day,hour,month,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,partition_time,year from panther_cloudsecurity.table2
+select 'panther_cloudsecurity' AS p_db_name,day,hour,month,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_cloudsecurity.table2
 	union all
-select 'panther_rule_matches' AS p_db_name,day,hour,month,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,partition_time,year from panther_rule_matches.table1
+select 'panther_rule_matches' AS p_db_name,day,hour,month,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_rule_matches.table1
 	union all
-select 'panther_rule_matches' AS p_db_name,day,hour,month,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,partition_time,year from panther_rule_matches.table2
+select 'panther_rule_matches' AS p_db_name,day,hour,month,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_rule_matches.table2
 	union all
-select 'panther_rule_errors' AS p_db_name,day,hour,month,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,partition_time,year from panther_rule_errors.table1
+select 'panther_rule_errors' AS p_db_name,day,hour,month,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_rule_errors.table1
 	union all
-select 'panther_rule_errors' AS p_db_name,day,hour,month,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,partition_time,year from panther_rule_errors.table2
+select 'panther_rule_errors' AS p_db_name,day,hour,month,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,p_source_metadata,partition_time,year from panther_rule_errors.table2
 ;
 `
 
@@ -153,3 +153,26 @@ func TestGenerateLogsViewsFail(t *testing.T) {
 	require.Error(t, err)
 	require.True(t, strings.Contains(err.Error(), "no tables"))
 }
+
+func TestGenerateNormalizedViews(t *testing.T) {
+	table1 := awsglue.NewGlueTableMetadata(pantherdb.LogProcessingDatabase, "table1", "test table1", awsglue.GlueTableHourly, &table1Event{})
+	table2 := awsglue.NewGlueTableMetadata(pantherdb.LogProcessingDatabase, "table2", "test table2", awsglue.GlueTableHourly, &table2Event{})
+	// nolint (lll)
+	expectedAPIActivitySQL := `create or replace view panther_views.normalized_api_activity as
+select 'panther_logs' AS p_db_name,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,year,month,day,hour,partition_time from panther_logs.table2 where p_normalized.class = 'api_activity'
+;
+`
+	// nolint (lll)
+	expectedAuthenticationSQL := `create or replace view panther_views.normalized_authentication as
+select 'panther_logs' AS p_db_name,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,year,month,day,hour,partition_time from panther_logs.table1 where p_normalized.class = 'authentication'
+	union all
+select 'panther_logs' AS p_db_name,p_event_time,p_log_type,p_normalized,p_parse_time,p_row_id,p_source_id,p_source_label,year,month,day,hour,partition_time from panther_logs.table2 where p_normalized.class = 'authentication'
+;
+`
+	sqlStatements := GenerateNormalizedViews([]NormalizedTable{
+		{Table: table1, Classes: []string{"authentication"}},
+		{Table: table2, Classes: []string{"api_activity", "authentication"}},
+	})
+	require.Equal(t, []string{expectedAPIActivitySQL, expectedAuthenticationSQL}, sqlStatements)
+	require.Empty(t, GenerateNormalizedViews(nil))
+}
@@ -33,6 +33,8 @@ const (
 	ruleMatchS3Prefix     = "rules"
 	ruleErrorsS3Prefix    = "rule_errors"
 	cloudSecurityS3Prefix = "cloud_security"
+	// JSON copies of data stored in other formats are kept here for consumers that only read JSON (e.g. rules engine)
+	stagingS3Prefix = "staging"
 )
 
 // Returns the prefix of the table in S3 or error if it failed to generate it
@@ -51,6 +53,17 @@ func TablePrefix(database, tableName string) string {
 	}
 }
 
+// StagingPrefix returns the prefix where JSON copies of the data of a table are stored if the table uses a format
+// other than JSON. Staged objects are not part of any table and expire shortly after they are written.
+func StagingPrefix(database, tableName string) string {
+	return stagingS3Prefix + "/" + TablePrefix(database, tableName)
+}
+
+// IsStagingObject checks if an S3 object key is under the staging prefix
+func IsStagingObject(s3key string) bool {
+	return strings.HasPrefix(s3key, stagingS3Prefix+"/")
+}
+
 func DataPrefix(databaseName string) string {
 	switch databaseName {
 	case pantherdb.LogProcessingDatabase:
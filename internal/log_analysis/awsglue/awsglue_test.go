@@ -56,3 +56,14 @@ func TestDataTypeFromS3Key(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, pantherdb.CloudSecurity, dataType)
 }
+
+func TestStagingPrefix(t *testing.T) {
+	prefix := StagingPrefix(pantherdb.LogProcessingDatabase, "some_table")
+	assert.Equal(t, "staging/logs/some_table/", prefix)
+	assert.True(t, IsStagingObject(prefix+"year=2020/month=01/day=03/hour=01/foo.json.gz"))
+	assert.False(t, IsStagingObject("logs/some_table/year=2020/month=01/day=03/hour=01/foo.json.gz"))
+
+	// staged objects are not partitions of any table
+	_, err := PartitionFromS3Object("bucket", prefix+"year=2020/month=01/day=03/hour=01/foo.json.gz")
+	require.Error(t, err)
+}
@@ -42,14 +42,25 @@ type PartitionKey struct {
 	Type string
 }
 
+// StorageFormat is the file format of the data files of a table
+type StorageFormat string
+
+const (
+	// StorageFormatJSON stores data as gzipped JSON lines (the default)
+	StorageFormatJSON StorageFormat = "json"
+	// StorageFormatParquet stores data as Parquet files
+	StorageFormatParquet StorageFormat = "parquet"
+)
+
 // Metadata about Glue table
 type GlueTableMetadata struct {
-	databaseName string
-	tableName    string
-	description  string
-	prefix       string
-	timebin      GlueTableTimebin // at what time resolution is this table partitioned
-	eventStruct  interface{}
+	databaseName  string
+	tableName     string
+	description   string
+	prefix        string
+	timebin       GlueTableTimebin // at what time resolution is this table partitioned
+	eventStruct   interface{}
+	storageFormat StorageFormat
 }
 
 // Creates a new GlueTableMetadata object for Panther log sources
@@ -92,6 +103,21 @@ func (gm *GlueTableMetadata) EventStruct() interface{} {
 	return gm.eventStruct
 }
 
+// StorageFormat is the file format of the table data, tables are stored as JSON unless specified otherwise
+func (gm *GlueTableMetadata) StorageFormat() StorageFormat {
+	if gm.storageFormat == "" {
+		return StorageFormatJSON
+	}
+	return gm.storageFormat
+}
+
+// WithStorageFormat returns a copy of the table metadata using a different storage format
+func (gm *GlueTableMetadata) WithStorageFormat(format StorageFormat) *GlueTableMetadata {
+	out := *gm
+	out.storageFormat = format
+	return &out
+}
+
 func (gm *GlueTableMetadata) HasPartitions(glueClient glueiface.GlueAPI) (bool, error) {
 	return TableHasPartitions(glueClient, gm.databaseName, gm.tableName)
 }
@@ -118,6 +144,7 @@ func (gm *GlueTableMetadata) RuleTable() *GlueTableMetadata {
 		return gm
 	}
 	// the corresponding rule table shares the same structure as the log table + some columns
+	// NOTE: rule tables are always JSON since they are written by the rules engine
 	return NewGlueTableMetadata(pantherdb.RuleMatchDatabase, gm.tableName, gm.Description(), GlueTableHourly, gm.EventStruct())
 }
 
@@ -162,6 +189,28 @@ func (gm *GlueTableMetadata) glueTableInput(bucketName string) (*glue.TableInput
 		}
 	}
 
+	var storageDescriptor *glue.StorageDescriptor
+	switch format := gm.StorageFormat(); format {
+	case StorageFormatJSON:
+		storageDescriptor = jsonStorageDescriptor(mappings)
+	case StorageFormatParquet:
+		storageDescriptor = parquetStorageDescriptor()
+	default:
+		return nil, errors.Errorf("unsupported storage format %q for table %s.%s", format, gm.databaseName, gm.tableName)
+	}
+	storageDescriptor.Columns = glueColumns
+	storageDescriptor.Location = aws.String("s3://" + bucketName + "/" + gm.prefix)
+
+	return &glue.TableInput{
+		Name:              &gm.tableName,
+		Description:       &gm.description,
+		PartitionKeys:     partitionColumns,
+		StorageDescriptor: storageDescriptor,
+		TableType:         aws.String("EXTERNAL_TABLE"),
+	}, nil
+}
+
+func jsonStorageDescriptor(mappings map[string]string) *glue.StorageDescriptor {
 	// Need to be case sensitive to deal with columns that have same name but different casing
 	// https://github.com/rcongiu/Hive-JSON-Serde#case-sensitivity-in-mappings
 	descriptorParameters := map[string]*string{
@@ -175,22 +224,28 @@ func (gm *GlueTableMetadata) glueTableInput(bucketName string) (*glue.TableInput
 		descriptorParameters[fmt.Sprintf("mapping.%s", from)] = &to
 	}
 
-	return &glue.TableInput{
-		Name:          &gm.tableName,
-		Description:   &gm.description,
-		PartitionKeys: partitionColumns,
-		StorageDescriptor: &glue.StorageDescriptor{ // configure as JSON
-			Columns:      glueColumns,
-			Location:     aws.String("s3://" + bucketName + "/" + gm.prefix),
-			InputFormat:  aws.String("org.apache.hadoop.mapred.TextInputFormat"),
-			OutputFormat: aws.String("org.apache.hadoop.hive.ql.io.HiveIgnoreKeyTextOutputFormat"),
-			SerdeInfo: &glue.SerDeInfo{
-				SerializationLibrary: aws.String("org.openx.data.jsonserde.JsonSerDe"),
-				Parameters:           descriptorParameters,
+	return &glue.StorageDescriptor{ // configure as JSON
+		InputFormat:  aws.String("org.apache.hadoop.mapred.TextInputFormat"),
+		OutputFormat: aws.String("org.apache.hadoop.hive.ql.io.HiveIgnoreKeyTextOutputFormat"),
+		SerdeInfo: &glue.SerDeInfo{
+			SerializationLibrary: aws.String("org.openx.data.jsonserde.JsonSerDe"),
+			Parameters:           descriptorParameters,
+		},
+	}
+}
+
+func parquetStorageDescriptor() *glue.StorageDescriptor {
+	// Parquet files store the column names so no mappings are needed.
+	return &glue.StorageDescriptor{ // configure as Parquet
+		InputFormat:  aws.String("org.apache.hadoop.hive.ql.io.parquet.MapredParquetInputFormat"),
+		OutputFormat: aws.String("org.apache.hadoop.hive.ql.io.parquet.MapredParquetOutputFormat"),
+		SerdeInfo: &glue.SerDeInfo{
+			SerializationLibrary: aws.String("org.apache.hadoop.hive.ql.io.parquet.serde.ParquetHiveSerDe"),
+			Parameters: map[string]*string{
+				"serialization.format": aws.String("1"),
 			},
 		},
-		TableType: aws.String("EXTERNAL_TABLE"),
-	}, nil
+	}
 }
 
 func (gm *GlueTableMetadata) UpdateTableIfExists(ctx context.Context, glueAPI glueiface.GlueAPI, bucketName string) (bool, error) {
@@ -386,7 +441,8 @@ func (gm *GlueTableMetadata) SyncPartitions(glueClient glueiface.GlueAPI, s3Clie
 				storageDescriptor := *getPartitionOutput.Partition.StorageDescriptor // copy because we will mutate
 				storageDescriptor.Columns = columns
 				// we need to update the SerDeInfo for JSON partitions to get the column mappings
-				if IsJSONPartition(&storageDescriptor) {
+				// partitions created before switching the table to Parquet keep their JSON SerDe
+				if IsJSONPartition(&storageDescriptor) && IsJSONPartition(tableOutput.Table.StorageDescriptor) {
 					storageDescriptor.SerdeInfo = tableOutput.Table.StorageDescriptor.SerdeInfo
 				}
 				_, err = UpdatePartition(glueClient, gm.databaseName, gm.tableName, values,
@@ -441,6 +497,23 @@ func (gm *GlueTableMetadata) CreateJSONPartition(client glueiface.GlueAPI, t tim
 	return gm.createPartition(client, t, tableOutput)
 }
 
+// CreatePartition creates the partition for t inheriting the storage format of the table.
+// It supports both JSON and Parquet tables.
+func (gm *GlueTableMetadata) CreatePartition(client glueiface.GlueAPI, t time.Time) (created bool, err error) {
+	// inherit StorageDescriptor from table
+	tableOutput, err := GetTable(client, gm.databaseName, gm.tableName)
+	if err != nil {
+		return false, err
+	}
+
+	storageDescriptor := tableOutput.Table.StorageDescriptor
+	if !IsJSONPartition(storageDescriptor) && !IsParquetPartition(storageDescriptor) {
+		return false, errors.Errorf("not a JSON or Parquet table: %#v", *storageDescriptor)
+	}
+
+	return gm.createPartition(client, t, tableOutput)
+}
+
 func (gm *GlueTableMetadata) createPartition(client glueiface.GlueAPI, t time.Time,
 	tableOutput *glue.GetTableOutput) (created bool, err error) {
 
@@ -189,6 +189,67 @@ func TestCreateJSONPartitionNonAWSError(t *testing.T) {
 	glueClient.AssertExpectations(t)
 }
 
+func TestCreatePartitionParquet(t *testing.T) {
+	gm := NewGlueTableMetadata(pantherdb.LogProcessingDatabase, "test_logs", "Description", GlueTableHourly, partitionTestEvent{})
+	parquetStorageDescriptor := *testStorageDescriptor
+	parquetStorageDescriptor.SerdeInfo = &glue.SerDeInfo{
+		SerializationLibrary: aws.String("org.apache.hadoop.hive.ql.io.parquet.serde.ParquetHiveSerDe"),
+	}
+	getTableOutput := &glue.GetTableOutput{
+		Table: &glue.TableData{
+			CreateTime:        aws.Time(refTime),
+			StorageDescriptor: &parquetStorageDescriptor,
+		},
+	}
+
+	glueClient := &testutils.GlueMock{}
+	glueClient.On("GetTable", mock.Anything).Return(getTableOutput, nil).Twice()
+	glueClient.On("CreatePartition", mock.MatchedBy(func(input *glue.CreatePartitionInput) bool {
+		sd := input.PartitionInput.StorageDescriptor
+		return IsParquetPartition(sd) &&
+			aws.StringValue(sd.Location) == "s3://"+metadataTestBucket+"/logs/test_logs/year=2020/month=01/day=03/hour=01/"
+	})).Return(testCreatePartitionOutput, nil).Once()
+	created, err := gm.CreatePartition(glueClient, refTime)
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	// JSON only partitions are refused for Parquet tables
+	created, err = gm.CreateJSONPartition(glueClient, refTime)
+	assert.Error(t, err)
+	assert.False(t, created)
+	glueClient.AssertExpectations(t)
+}
+
+func TestGlueTableInputStorageFormat(t *testing.T) {
+	type event struct {
+		Name string `json:"name"`
+	}
+	gm := NewGlueTableMetadata(pantherdb.LogProcessingDatabase, "test_logs", "Description", GlueTableHourly, event{})
+	require.Equal(t, StorageFormatJSON, gm.StorageFormat())
+	input, err := gm.glueTableInput(metadataTestBucket)
+	require.NoError(t, err)
+	require.True(t, IsJSONPartition(input.StorageDescriptor))
+	require.Equal(t, "name", aws.StringValue(input.StorageDescriptor.SerdeInfo.Parameters["mapping.name"]))
+
+	parquetTable := gm.WithStorageFormat(StorageFormatParquet)
+	require.Equal(t, StorageFormatJSON, gm.StorageFormat(), "original table metadata is not modified")
+	require.Equal(t, StorageFormatParquet, parquetTable.StorageFormat())
+	input, err = parquetTable.glueTableInput(metadataTestBucket)
+	require.NoError(t, err)
+	require.True(t, IsParquetPartition(input.StorageDescriptor))
+	require.Equal(t, "org.apache.hadoop.hive.ql.io.parquet.MapredParquetInputFormat", aws.StringValue(input.StorageDescriptor.InputFormat))
+	require.Equal(t, "s3://"+metadataTestBucket+"/logs/test_logs/", aws.StringValue(input.StorageDescriptor.Location))
+	require.Len(t, input.StorageDescriptor.Columns, 1)
+	require.Equal(t, "name", aws.StringValue(input.StorageDescriptor.Columns[0].Name))
+
+	// rule matches are written by the rules engine as JSON
+	require.Equal(t, StorageFormatJSON, parquetTable.RuleTable().StorageFormat())
+	require.Equal(t, StorageFormatJSON, parquetTable.RuleErrorTable().StorageFormat())
+
+	_, err = gm.WithStorageFormat("csv").glueTableInput(metadataTestBucket)
+	require.Error(t, err)
+}
+
 func TestSyncPartitions(t *testing.T) {
 	var startDate time.Time // default unset
 	gm := NewGlueTableMetadata(pantherdb.LogProcessingDatabase, "test_logs", "Description", GlueTableHourly, partitionTestEvent{})
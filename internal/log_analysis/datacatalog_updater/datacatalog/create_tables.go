@@ -28,6 +28,7 @@ import (
 	"github.com/panther-labs/panther/internal/log_analysis/gluetables"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
 	"github.com/panther-labs/panther/internal/log_analysis/pantherdb"
+	"github.com/panther-labs/panther/pkg/stringset"
 )
 
 type CreateTablesEvent struct {
@@ -49,7 +50,7 @@ func (h *LambdaHandler) HandleCreateTablesEvent(ctx context.Context, event *Crea
 
 func (h *LambdaHandler) createTablesForLogTypes(ctx context.Context, logTypes []string) error {
 	// We map the log types to their 'base' log tables.
-	tables, err := resolveTables(ctx, h.Resolver, logTypes...)
+	tables, err := h.resolveTables(ctx, logTypes...)
 	if err != nil {
 		return err
 	}
@@ -77,7 +78,7 @@ func (h *LambdaHandler) createTablesForLogTypes(ctx context.Context, logTypes []
 
 func (h *LambdaHandler) createOrUpdateTablesForLogTypes(ctx context.Context, logTypes []string) error {
 	// We map the log types to their 'base' log tables, errors are collected and not fatal
-	tables, err := resolveTables(ctx, h.Resolver, logTypes...)
+	tables, err := h.resolveTables(ctx, logTypes...)
 	if err != nil {
 		return err
 	}
@@ -111,7 +112,7 @@ func (h *LambdaHandler) createOrReplaceViewsForAllDeployedLogTables(ctx context.
 		return errors.Wrap(err, "failed to fetch deployed log types")
 	}
 	// We map the deployed log types to their 'base' log tables, errors are collected and not fatal
-	tables, err := resolveTables(ctx, h.Resolver, deployedLogTypes...)
+	tables, err := h.resolveTables(ctx, deployedLogTypes...)
 	if err != nil {
 		return err
 	}
@@ -121,6 +122,14 @@ func (h *LambdaHandler) createOrReplaceViewsForAllDeployedLogTables(ctx context.
 	if err := athenaviews.CreateOrReplaceLogViews(h.AthenaClient, h.AthenaWorkgroup, tables); err != nil {
 		return errors.Wrap(err, "failed to update athena views")
 	}
+	// update the views for each event class of normalized events
+	normalizedTables, err := h.resolveNormalizedTables(ctx, deployedLogTypes...)
+	if err != nil {
+		return err
+	}
+	if err := athenaviews.CreateOrReplaceNormalizedViews(h.AthenaClient, h.AthenaWorkgroup, normalizedTables); err != nil {
+		return errors.Wrap(err, "failed to update athena views of normalized events")
+	}
 	return nil
 }
 
@@ -135,25 +144,52 @@ func (h *LambdaHandler) fetchAllDeployedLogTypes(ctx context.Context) ([]string,
 // Resolves the tables for the provided log types.
 // Note that this will return only the BASE tables (tables in for panther_logs and panther_cloudsecurity databases) but not any
 // downstream tables e.g. panther_rule_matches, panther_rule_errors
-func resolveTables(ctx context.Context, r logtypes.Resolver, names ...string) ([]*awsglue.GlueTableMetadata, error) {
+func (h *LambdaHandler) resolveTables(ctx context.Context, names ...string) ([]*awsglue.GlueTableMetadata, error) {
 	var out []*awsglue.GlueTableMetadata
 	for _, name := range names {
-		entry, err := r.Resolve(ctx, name)
+		entry, err := h.Resolver.Resolve(ctx, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot resolve logType: %s", name)
+		}
+		if entry == nil { // don't fail whole operation if missing data...
+			continue
+		}
+		out = append(out, h.tableForEntry(entry))
+	}
+	return out, nil
+}
+
+// Resolves the base tables of the provided log types that have a normalization mapping along with their event classes.
+func (h *LambdaHandler) resolveNormalizedTables(ctx context.Context, names ...string) ([]athenaviews.NormalizedTable, error) {
+	var out []athenaviews.NormalizedTable
+	for _, name := range names {
+		entry, err := h.Resolver.Resolve(ctx, name)
 		if err != nil {
 			return nil, errors.Wrapf(err, "cannot resolve logType: %s", name)
 		}
 		if entry == nil { // don't fail whole operation if missing data...
 			continue
 		}
-		out = append(out, tableForEntry(entry))
+		classes := logtypes.Normalizer(entry).Classes()
+		if len(classes) == 0 {
+			continue
+		}
+		out = append(out, athenaviews.NormalizedTable{
+			Table:   h.tableForEntry(entry),
+			Classes: classes,
+		})
 	}
 	return out, nil
 }
 
-func tableForEntry(entry logtypes.Entry) *awsglue.GlueTableMetadata {
+func (h *LambdaHandler) tableForEntry(entry logtypes.Entry) *awsglue.GlueTableMetadata {
 	eventSchema := entry.Schema()
 	desc := entry.Describe()
 	tableName := pantherdb.TableName(desc.Name)
 	db := pantherdb.DatabaseName(pantherdb.GetDataType(desc.Name))
-	return awsglue.NewGlueTableMetadata(db, tableName, desc.Description, awsglue.GlueTableHourly, eventSchema)
+	table := awsglue.NewGlueTableMetadata(db, tableName, desc.Description, awsglue.GlueTableHourly, eventSchema)
+	if stringset.Contains(h.ParquetLogTypes, desc.Name) {
+		return table.WithStorageFormat(awsglue.StorageFormatParquet)
+	}
+	return table
 }
@@ -47,6 +47,8 @@ type LambdaHandler struct {
 	AthenaClient          athenaiface.AthenaAPI
 	SQSClient             sqsiface.SQSAPI
 	Logger                *zap.Logger
+	// Log types that are stored as Parquet instead of JSON
+	ParquetLogTypes []string
 
 	// Glue partitions known to have been created.
 	partitionsCreated map[string]struct{}
@@ -33,6 +33,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
 	"github.com/panther-labs/panther/internal/log_analysis/pantherdb"
@@ -170,6 +171,48 @@ func TestProcessInvalidS3Key(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestProcessStagingObject(t *testing.T) {
+	initProcessTest()
+	// JSON copies of Parquet data should be ignored without calling Glue
+	err := handler.HandleSQSEvent(lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{}),
+		getEvent(t, "staging/logs/table/year=2020/month=02/day=26/hour=15/item.json.gz"))
+	assert.NoError(t, err)
+	mockGlueClient.AssertExpectations(t)
+}
+
+func TestProcessParquetTable(t *testing.T) {
+	initProcessTest()
+
+	parquetStorageDescriptor := *testStorageDescriptor
+	parquetStorageDescriptor.SerdeInfo = &glue.SerDeInfo{
+		SerializationLibrary: aws.String("org.apache.hadoop.hive.ql.io.parquet.serde.ParquetHiveSerDe"),
+	}
+	getTableOutput := &glue.GetTableOutput{
+		Table: &glue.TableData{
+			StorageDescriptor: &parquetStorageDescriptor,
+		},
+	}
+	mockGlueClient.On("GetTable", mock.Anything).Return(getTableOutput, nil).Once()
+	mockGlueClient.On("CreatePartition", mock.Anything).Return(&glue.CreatePartitionOutput{}, nil).Once()
+
+	assert.NoError(t, handler.HandleSQSEvent(lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{}),
+		getEvent(t, "logs/table/year=2020/month=02/day=26/hour=15/20200226T150000Z-item.parquet")))
+	mockGlueClient.AssertExpectations(t)
+}
+
+func TestTableForEntryStorageFormat(t *testing.T) {
+	initProcessTest()
+	defer func() {
+		handler.ParquetLogTypes = nil
+	}()
+	entry, err := handler.Resolver.Resolve(context.Background(), "AWS.VPCFlow")
+	require.NoError(t, err)
+	require.Equal(t, awsglue.StorageFormatJSON, handler.tableForEntry(entry).StorageFormat())
+
+	handler.ParquetLogTypes = []string{"AWS.VPCFlow"}
+	require.Equal(t, awsglue.StorageFormatParquet, handler.tableForEntry(entry).StorageFormat())
+}
+
 // initProcessTest is run at the start of each test to create new mocks and reset state
 func initProcessTest() {
 	availableLogTypes := logtypes.CollectNames(registry.NativeLogTypes())
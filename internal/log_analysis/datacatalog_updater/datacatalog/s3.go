@@ -47,6 +47,10 @@ func (h *LambdaHandler) HandleS3Event(ctx context.Context, event *events.S3Event
 func (h *LambdaHandler) HandleS3EventRecord(ctx context.Context, event *events.S3EventRecord) error {
 	bucketName := event.S3.Bucket.Name
 	objectKey := event.S3.Object.Key
+	// JSON copies of Parquet data are staged for the rules engine, they do not belong to any partition
+	if awsglue.IsStagingObject(objectKey) {
+		return nil
+	}
 	partition, err := awsglue.PartitionFromS3Object(bucketName, objectKey)
 	if err != nil {
 		lambdalogger.FromContext(ctx).Warn("invalid S3 event", zap.Any("event", event))
@@ -58,7 +62,7 @@ func (h *LambdaHandler) HandleS3EventRecord(ctx context.Context, event *events.S
 	}
 	partitionTime := partition.GetTime()
 	tableMeta := partition.GetGlueTableMetadata()
-	if _, err := tableMeta.CreateJSONPartition(h.GlueClient, partitionTime); err != nil {
+	if _, err := tableMeta.CreatePartition(h.GlueClient, partitionTime); err != nil {
 		return err
 	}
 
@@ -24,6 +24,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/deadletters"
 	"github.com/panther-labs/panther/internal/log_analysis/pantherdb"
 	"github.com/panther-labs/panther/pkg/stringset"
 )
@@ -39,6 +40,10 @@ func (h *LambdaHandler) HandleSyncDatabaseEvent(ctx context.Context, event *Sync
 			return errors.Wrapf(err, "failed to create database %s", db)
 		}
 	}
+	// The dead letters table does not belong to a log type, its partitions are created from S3 notifications
+	if err := deadletters.TableMetadata.CreateOrUpdateTable(h.GlueClient, h.ProcessedDataBucket); err != nil {
+		return errors.Wrap(err, "failed to update dead letters table")
+	}
 	// We combine the deployed log types with the ones required by all active sources
 	// This way if new code for sources requires more log types on upgrade, they are added
 	var syncLogTypes []string
@@ -36,7 +36,7 @@ func (h *LambdaHandler) HandleUpdateTablesEvent(ctx context.Context, event *Upda
 	if err != nil {
 		return err
 	}
-	tbl := tableForEntry(entry)
+	tbl := h.tableForEntry(entry)
 	updated, err := tbl.UpdateTableIfExists(ctx, h.GlueClient, h.ProcessedDataBucket)
 	if err != nil {
 		return err
@@ -51,11 +51,12 @@ const (
 func main() {
 	// nolint: maligned
 	config := struct {
-		AthenaWorkgroup     string `required:"true" split_words:"true"`
-		SyncWorkersPerTable int    `default:"10" split_words:"true"`
-		QueueURL            string `required:"true" split_words:"true"`
-		ProcessedDataBucket string `split_words:"true"`
-		Debug               bool   `split_words:"true"`
+		AthenaWorkgroup     string   `required:"true" split_words:"true"`
+		SyncWorkersPerTable int      `default:"10" split_words:"true"`
+		QueueURL            string   `required:"true" split_words:"true"`
+		ProcessedDataBucket string   `split_words:"true"`
+		ParquetLogTypes     []string `split_words:"true"`
+		Debug               bool     `split_words:"true"`
 	}{}
 	envconfig.MustProcess("", &config)
 
@@ -109,6 +110,7 @@ func main() {
 		ProcessedDataBucket: config.ProcessedDataBucket,
 		QueueURL:            config.QueueURL,
 		AthenaWorkgroup:     config.AthenaWorkgroup,
+		ParquetLogTypes:     config.ParquetLogTypes,
 		ListAvailableLogTypes: func(ctx context.Context) ([]string, error) {
 			reply, err := logtypesAPI.ListAvailableLogTypes(ctx)
 			if err != nil {
@@ -26,7 +26,9 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/pkg/stringset"
 )
 
 // ClassifierAPI is the interface for a classifier
@@ -49,13 +51,31 @@ type ClassifierResult struct {
 	Matched bool
 	// NumMiss counts the number for failed classification attempts
 	NumMiss int
+	// ParserErrors holds the errors of the parsers that failed to parse the log entry
+	ParserErrors []ParserError
+}
+
+// ParserError is the error of a parser that failed to parse a log entry
+type ParserError struct {
+	LogType string
+	Err     error
 }
 
 // NewClassifier returns a new instance of a ClassifierAPI implementation
 func NewClassifier(parsers map[string]parsers.Interface) ClassifierAPI {
+	return NewStickyClassifier(parsers, 0)
+}
+
+// NewStickyClassifier returns a classifier that locks the log types of a stream.
+// The log types of the first learnLines classified lines are locked and the rest of the lines are
+// only parsed with their parsers. Lines that do not match the locked log types fall back to all parsers
+// and are counted as sticky mismatches.
+// If learnLines is zero the log types are never locked.
+func NewStickyClassifier(parsers map[string]parsers.Interface, learnLines int) ClassifierAPI {
 	return &Classifier{
 		parsers:     NewParserPriorityQueue(parsers),
 		parserStats: make(map[string]*ParserStats),
+		learnLines:  learnLines,
 	}
 }
 
@@ -66,6 +86,13 @@ type Classifier struct {
 	stats ClassifierStats
 	// per-parser stats, map of LogType -> stats
 	parserStats map[string]*ParserStats
+
+	// number of classified lines that decide the locked log types, zero disables sticky mode
+	learnLines int
+	// log types of the classified lines while learning
+	learned []string
+	// parsers of the locked log types, nil until the log types are locked
+	locked *ParserPriorityQueue
 }
 
 func (c *Classifier) Stats() *ClassifierStats {
@@ -94,8 +121,6 @@ func safeLogParse(logType string, parser parsers.Interface, log string) (results
 // Classify attempts to classify the provided log line
 func (c *Classifier) Classify(log string) (*ClassifierResult, error) {
 	startClassify := time.Now().UTC()
-	// Slice containing the popped queue items
-	var popped []interface{}
 	result := &ClassifierResult{}
 
 	if len(log) == 0 { // likely empty file, nothing to do
@@ -121,11 +146,102 @@ func (c *Classifier) Classify(log string) (*ClassifierResult, error) {
 		return result, nil
 	}
 
-	for c.parsers.Len() > 0 {
-		currentItem := c.parsers.Peek()
+	if c.locked != nil {
+		c.classify(c.locked, log, result)
+	}
+	if !result.Matched {
+		// Use the parsers of all log types, or the parsers of the other log types if log types are locked
+		logType, parserStats := c.classify(c.parsers, log, result)
+		switch {
+		case !result.Matched:
+		case c.locked != nil:
+			c.stats.StickyMismatchCount++
+			parserStats.StickyMismatchCount++
+		case c.learnLines > 0:
+			c.learn(logType)
+		}
+	}
+	if !result.Matched {
+		return result, errors.New("failed to classify log line")
+	}
+	c.classifyEmbedded(result)
+	return result, nil
+}
+
+// classifyEmbedded classifies the log entries embedded in the events of a result (i.e. the messages of container log lines).
+// Events with an embedded log entry that is classified are replaced by the events of the embedded log entry.
+// The metadata of the replaced events (i.e. the pod and container) are kept in the p_source_metadata of the new events.
+// The events of embedded log entries are not checked for embedded log entries again.
+func (c *Classifier) classifyEmbedded(result *ClassifierResult) {
+	events := result.Events[:0:0]
+	for _, event := range result.Events {
+		embedder, ok := event.Event.(pantherlog.EmbeddedLogger)
+		if !ok {
+			events = append(events, event)
+			continue
+		}
+		log := strings.TrimSpace(embedder.EmbeddedLog())
+		if log == "" {
+			events = append(events, event)
+			continue
+		}
+		// Misses of embedded log entries are not counted as misses of the log line
+		embedded := &ClassifierResult{}
+		if c.locked != nil {
+			c.classify(c.locked, log, embedded)
+		}
+		if !embedded.Matched {
+			c.classify(c.parsers, log, embedded)
+		}
+		if !embedded.Matched {
+			events = append(events, event)
+			continue
+		}
+		c.stats.EmbeddedLogCount++
+		if m, ok := event.Event.(pantherlog.EmbeddedLogMetadata); ok {
+			metadata := m.EmbeddedLogMetadata()
+			for _, e := range embedded.Events {
+				e.AddSourceMetadata(metadata)
+			}
+		}
+		events = append(events, embedded.Events...)
+	}
+	result.Events = events
+}
+
+// learn records the log type of a classified line and locks the learned log types once enough lines are classified
+func (c *Classifier) learn(logType string) {
+	c.learned = append(c.learned, logType)
+	if len(c.learned) < c.learnLines {
+		return
+	}
+	locked := &ParserPriorityQueue{}
+	others := &ParserPriorityQueue{}
+	for _, item := range c.parsers.items {
+		if stringset.Contains(c.learned, item.logType) {
+			locked.items = append(locked.items, item)
+			continue
+		}
+		others.items = append(others.items, item)
+	}
+	heap.Init(locked)
+	heap.Init(others)
+	c.locked, c.parsers = locked, others
+	c.learned = nil
+}
+
+// classify tries the parsers of a queue in priority order and returns the log type of the first that parses the line
+// along with the stats it updated.
+// Parsers shared by multiple log types (see logtypes.Dispatcher) update the stats of the log type of the parsed events.
+func (c *Classifier) classify(queue *ParserPriorityQueue, log string, result *ClassifierResult) (logType string,
+	parserStat *ParserStats) {
+	// Slice containing the popped queue items
+	var popped []interface{}
+	for queue.Len() > 0 {
+		currentItem := queue.Peek()
 
 		startParseTime := time.Now().UTC()
-		logType := currentItem.logType
+		logType = currentItem.logType
 		parsedEvents, err := safeLogParse(logType, currentItem.parser, log)
 		endParseTime := time.Now().UTC()
 
@@ -133,12 +249,16 @@ func (c *Classifier) Classify(log string) (*ClassifierResult, error) {
 		if err != nil {
 			zap.L().Debug("failed to parse event", zap.String("expectedLogType", logType), zap.Error(err))
 			// Removing parser from queue
-			popped = append(popped, heap.Pop(c.parsers))
+			popped = append(popped, heap.Pop(queue))
 			// Increasing penalty of the parser
 			// Due to increased penalty the parser will be lower priority in the queue
 			currentItem.penalty++
 			// Increment the number of misses in the result
 			result.NumMiss++
+			result.ParserErrors = append(result.ParserErrors, ParserError{
+				LogType: logType,
+				Err:     err,
+			})
 			// record failure
 			continue
 		}
@@ -150,14 +270,17 @@ func (c *Classifier) Classify(log string) (*ClassifierResult, error) {
 		result.Events = parsedEvents
 
 		// update per-parser stats
-		var parserStat *ParserStats
+		statsLogType := logType
+		if len(parsedEvents) > 0 && parsedEvents[0].PantherLogType != "" {
+			statsLogType = parsedEvents[0].PantherLogType
+		}
 		var parserStatExists bool
 		// lazy create
-		if parserStat, parserStatExists = c.parserStats[logType]; !parserStatExists {
+		if parserStat, parserStatExists = c.parserStats[statsLogType]; !parserStatExists {
 			parserStat = &ParserStats{
-				LogType: logType,
+				LogType: statsLogType,
 			}
-			c.parserStats[logType] = parserStat
+			c.parserStats[statsLogType] = parserStat
 		}
 		parserStat.ParserTimeMicroseconds += uint64(endParseTime.Sub(startParseTime).Microseconds())
 		parserStat.BytesProcessedCount += uint64(len(log))
@@ -171,12 +294,9 @@ func (c *Classifier) Classify(log string) (*ClassifierResult, error) {
 
 	// Put back the popped items to the ParserPriorityQueue.
 	for _, item := range popped {
-		heap.Push(c.parsers, item)
+		heap.Push(queue, item)
 	}
-	if !result.Matched {
-		return result, errors.New("failed to classify log line")
-	}
-	return result, nil
+	return logType, parserStat
 }
 
 // aggregate stats
@@ -187,6 +307,9 @@ type ClassifierStats struct {
 	EventCount                  uint64 // output records
 	SuccessfullyClassifiedCount uint64
 	ClassificationFailureCount  uint64
+	DroppedEventCount           uint64 // records dropped by source event filters
+	StickyMismatchCount         uint64 // records that did not match the locked log types
+	EmbeddedLogCount            uint64 // log entries embedded in records that were classified
 }
 
 func (s *ClassifierStats) Add(other *ClassifierStats) {
@@ -196,6 +319,9 @@ func (s *ClassifierStats) Add(other *ClassifierStats) {
 	s.SuccessfullyClassifiedCount += other.EventCount
 	s.LogLineCount += other.LogLineCount
 	s.ClassificationFailureCount += other.ClassificationFailureCount
+	s.DroppedEventCount += other.DroppedEventCount
+	s.StickyMismatchCount += other.StickyMismatchCount
+	s.EmbeddedLogCount += other.EmbeddedLogCount
 }
 
 // per parser stats
@@ -205,6 +331,8 @@ type ParserStats struct {
 	LogLineCount           uint64 // input records
 	EventCount             uint64 // output records
 	CombinedLatency        uint64 // sum of latency of events
+	DroppedEventCount      uint64 // records dropped by source event filters
+	StickyMismatchCount    uint64 // records parsed after falling back from the locked log types
 	LogType                string
 }
 
@@ -214,6 +342,8 @@ func (s *ParserStats) Add(other *ParserStats) {
 	s.EventCount += other.EventCount
 	s.LogLineCount += other.LogLineCount
 	s.CombinedLatency += other.CombinedLatency
+	s.DroppedEventCount += other.DroppedEventCount
+	s.StickyMismatchCount += other.StickyMismatchCount
 }
 
 func MergeParserStats(dst map[string]*ParserStats, src map[string]*ParserStats) {
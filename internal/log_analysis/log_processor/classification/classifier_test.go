@@ -92,6 +92,7 @@ func TestClassifyRespectsPriorityOfParsers(t *testing.T) {
 		if i == 0 {
 			// Maps are not ordered, we do not know if first result can miss
 			result.NumMiss = 0
+			result.ParserErrors = nil
 		}
 		require.NoError(t, err)
 		require.Equal(t, expectedResult, result)
@@ -114,8 +115,9 @@ func TestClassifyRespectsPriorityOfParsers(t *testing.T) {
 
 func TestClassifyNoMatch(t *testing.T) {
 	logLine := "log"
+	parseErr := errors.New("fail")
 	failingParser := testutil.ParserConfig{
-		logLine: errors.New("fail"),
+		logLine: parseErr,
 	}.Parser()
 	classifier := NewClassifier(map[string]parsers.Interface{
 		"failure": failingParser,
@@ -135,7 +137,12 @@ func TestClassifyNoMatch(t *testing.T) {
 	expectedStats.ClassifyTimeMicroseconds = classifier.Stats().ClassifyTimeMicroseconds
 	require.Equal(t, expectedStats, classifier.Stats())
 
-	require.Equal(t, &ClassifierResult{NumMiss: 1}, result)
+	require.Equal(t, &ClassifierResult{
+		NumMiss: 1,
+		ParserErrors: []ParserError{
+			{LogType: "failure", Err: parseErr},
+		},
+	}, result)
 	failingParser.AssertNumberOfCalls(t, "Parse", 1)
 	require.Nil(t, classifier.ParserStats()["failure"])
 }
@@ -172,7 +179,10 @@ func TestClassifyParserPanic(t *testing.T) {
 	expectedStats.ClassifyTimeMicroseconds = classifier.Stats().ClassifyTimeMicroseconds
 	require.Equal(t, expectedStats, classifier.Stats())
 
-	require.Equal(t, &ClassifierResult{NumMiss: 1}, result)
+	require.Equal(t, 1, result.NumMiss)
+	require.Len(t, result.ParserErrors, 1)
+	require.Equal(t, "panic", result.ParserErrors[0].LogType)
+	require.EqualError(t, result.ParserErrors[0].Err, `parser "panic" panic: test parser panic`)
 	panicParser.AssertNumberOfCalls(t, "Parse", 1)
 }
 
@@ -259,3 +269,143 @@ func testSkipClassify(logLine string, t *testing.T) {
 	require.Nil(t, classifier.ParserStats()["failure1"])
 	require.Nil(t, classifier.ParserStats()["failure2"])
 }
+
+func TestStickyClassifier(t *testing.T) {
+	newResult := func(logType string) *parsers.Result {
+		return &parsers.Result{
+			CoreFields: pantherlog.CoreFields{
+				PantherLogType: logType,
+			},
+		}
+	}
+	parserA := testutil.ParserConfig{
+		"a":    newResult("A"),
+		"both": newResult("A"),
+	}.Parser()
+	parserB := testutil.ParserConfig{
+		"b":    newResult("B"),
+		"both": newResult("B"),
+	}.Parser()
+	classifier := NewStickyClassifier(map[string]parsers.Interface{
+		"A": parserA,
+		"B": parserB,
+	}, 2)
+
+	classify := func(line string) string {
+		result, err := classifier.Classify(line)
+		require.NoError(t, err)
+		require.Len(t, result.Events, 1)
+		return result.Events[0].PantherLogType
+	}
+	require.Equal(t, "A", classify("a"))
+	require.Equal(t, "A", classify("a"))
+	// Log types are locked to A, B is only used as a fall back
+	require.Equal(t, "B", classify("b"))
+	// Without locking B would have higher priority after parsing the previous line
+	require.Equal(t, "A", classify("both"))
+	_, err := classifier.Classify("c")
+	require.Error(t, err)
+
+	stats := classifier.Stats()
+	require.Equal(t, uint64(5), stats.LogLineCount)
+	require.Equal(t, uint64(4), stats.SuccessfullyClassifiedCount)
+	require.Equal(t, uint64(1), stats.ClassificationFailureCount)
+	require.Equal(t, uint64(1), stats.StickyMismatchCount)
+	require.Equal(t, uint64(1), classifier.ParserStats()["B"].StickyMismatchCount)
+	require.Equal(t, uint64(0), classifier.ParserStats()["A"].StickyMismatchCount)
+	parserB.AssertNotCalled(t, "Parse", "both")
+}
+
+type embeddedLogEvent struct {
+	Log string
+}
+
+func (e *embeddedLogEvent) EmbeddedLog() string {
+	return e.Log
+}
+
+func TestClassifyEmbeddedLog(t *testing.T) {
+	newResult := func(logType string, event interface{}) *parsers.Result {
+		return &parsers.Result{
+			CoreFields: pantherlog.CoreFields{
+				PantherLogType: logType,
+			},
+			Event: event,
+		}
+	}
+	parserContainer := testutil.ParserConfig{
+		"wrapped":         newResult("Container", &embeddedLogEvent{Log: "inner\n"}),
+		"wrapped-unknown": newResult("Container", &embeddedLogEvent{Log: "unknown"}),
+		"wrapped-empty":   newResult("Container", &embeddedLogEvent{}),
+	}.Parser()
+	parserInner := testutil.ParserConfig{
+		"inner": newResult("Inner", nil),
+	}.Parser()
+	classifier := NewClassifier(map[string]parsers.Interface{
+		"Container": parserContainer,
+		"Inner":     parserInner,
+	})
+
+	classify := func(line string) *ClassifierResult {
+		result, err := classifier.Classify(line)
+		require.NoError(t, err)
+		require.Len(t, result.Events, 1)
+		return result
+	}
+	// Classify a container line first so that its parser has higher priority
+	require.Equal(t, "Container", classify("wrapped-empty").Events[0].PantherLogType)
+	result := classify("wrapped")
+	require.Equal(t, "Inner", result.Events[0].PantherLogType)
+	// Misses of the embedded log entry are not counted
+	require.Zero(t, result.NumMiss)
+	require.Equal(t, "Container", classify("wrapped-unknown").Events[0].PantherLogType)
+
+	stats := classifier.Stats()
+	require.Equal(t, uint64(3), stats.LogLineCount)
+	require.Equal(t, uint64(3), stats.SuccessfullyClassifiedCount)
+	require.Equal(t, uint64(0), stats.ClassificationFailureCount)
+	require.Equal(t, uint64(1), stats.EmbeddedLogCount)
+	parserInner.AssertCalled(t, "Parse", "inner")
+}
+
+type embeddedLogEventWithMetadata struct {
+	embeddedLogEvent
+	Pod string
+}
+
+func (e *embeddedLogEventWithMetadata) EmbeddedLogMetadata() map[string]string {
+	return map[string]string{
+		"pod": e.Pod,
+	}
+}
+
+func TestClassifyEmbeddedLogMetadata(t *testing.T) {
+	parserContainer := testutil.ParserConfig{
+		"wrapped": &parsers.Result{
+			CoreFields: pantherlog.CoreFields{
+				PantherLogType: "Container",
+			},
+			Event: &embeddedLogEventWithMetadata{
+				embeddedLogEvent: embeddedLogEvent{Log: "inner"},
+				Pod:              "pod-1",
+			},
+		},
+	}.Parser()
+	parserInner := testutil.ParserConfig{
+		"inner": &parsers.Result{
+			CoreFields: pantherlog.CoreFields{
+				PantherLogType: "Inner",
+			},
+		},
+	}.Parser()
+	classifier := NewClassifier(map[string]parsers.Interface{
+		"Container": parserContainer,
+		"Inner":     parserInner,
+	})
+
+	result, err := classifier.Classify("wrapped")
+	require.NoError(t, err)
+	require.Len(t, result.Events, 1)
+	require.Equal(t, "Inner", result.Events[0].PantherLogType)
+	require.Equal(t, map[string]string{"pod": "pod-1"}, result.Events[0].PantherSourceMetadata)
+}
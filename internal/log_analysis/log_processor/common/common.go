@@ -24,6 +24,8 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
 	"github.com/aws/aws-sdk-go/service/lambda"
 	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
 	"github.com/aws/aws-sdk-go/service/s3"
@@ -52,6 +54,7 @@ var (
 	S3Client     s3iface.S3API
 	SqsClient    sqsiface.SQSAPI
 	SnsClient    snsiface.SNSAPI
+	GlueClient   glueiface.GlueAPI
 
 	Config EnvConfig
 )
@@ -62,6 +65,34 @@ type EnvConfig struct {
 	SqsQueueURL                 string `required:"true" split_words:"true"`
 	SqsBatchSize                int64  `required:"true" split_words:"true"`
 	SnsTopicARN                 string `required:"true" split_words:"true"`
+	// Log types that are stored as Parquet instead of JSON
+	ParquetLogTypes []string `split_words:"true"`
+	// Destinations for processed events (s3, http, kafka, file)
+	Destinations []string `default:"s3" split_words:"true"`
+	// Destinations that fail log processing on errors, errors of other destinations are only logged
+	RequiredDestinations []string `default:"s3" split_words:"true"`
+	// Number of events buffered for each of multiple destinations, optional destinations drop events when full
+	FanOutBufferSize        int               `default:"1000" split_words:"true"`
+	HTTPDestinationURL      string            `split_words:"true"`
+	HTTPDestinationHeaders  map[string]string `split_words:"true"`
+	KafkaDestinationBrokers []string          `split_words:"true"`
+	KafkaDestinationTopic   string            `split_words:"true"`
+	KafkaDestinationTLS     bool              `split_words:"true"`
+	FileDestinationDir      string            `split_words:"true"`
+	FileDestinationMaxBytes int64             `split_words:"true"`
+	// Location of the enrichment configuration (s3://bucket/key or a file path)
+	EnrichmentConfig string `split_words:"true"`
+	// Paths to MaxMind databases used to resolve the country and ASN of ip addresses
+	GeoipCountryDatabase string `split_words:"true"`
+	GeoipAsnDatabase     string `split_words:"true"`
+	// Location of the masking configuration (s3://bucket/key or a file path)
+	MaskingConfig string `split_words:"true"`
+	// Key used to hash masked values
+	MaskingHashKey string `split_words:"true"`
+	// Number of classified lines that lock the log types of a stream, zero disables sticky classification
+	StickyClassifierLines int `split_words:"true"`
+	// Store the lines that fail classification in the dead letters table
+	StoreDeadLetters bool `split_words:"true"`
 }
 
 func Setup() {
@@ -71,6 +102,7 @@ func Setup() {
 	LambdaClient = lambda.New(clientsSession)
 	SqsClient = sqs.New(clientsSession)
 	SnsClient = sns.New(clientsSession)
+	GlueClient = glue.New(clientsSession)
 
 	s3UploaderSession := Session.Copy(request.WithRetryer(aws.NewConfig().WithMaxRetries(MaxRetries),
 		awsretry.NewAccessDeniedRetryer(MaxRetries)))
@@ -90,4 +122,12 @@ type DataStream struct {
 	S3ObjectKey  string
 	S3Bucket     string
 	S3ObjectSize int64
+	// ArchiveMember is the name of the file inside a zip/tar archive this stream reads from (empty for plain objects)
+	ArchiveMember string
+	// ArchiveMemberSize is the size of the archive member as recorded in the archive
+	ArchiveMemberSize int64
+	// Members is set for streams that could read a zip/tar archive.
+	// It sniffs the content on the first call and returns a separate stream for each member file of an archive.
+	// It returns nil for plain objects which are read from Stream.
+	Members func() ([]*DataStream, error)
 }
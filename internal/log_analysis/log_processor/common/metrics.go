@@ -40,5 +40,21 @@ var (
 			Name: "CombinedLatency",
 			Unit: metrics.UnitMilliseconds,
 		},
+		{
+			Name: "StickyMismatches",
+			Unit: metrics.UnitCount,
+		},
+	})
+
+	// DroppedEventsLogger reports the events dropped by optional destinations that could not keep up
+	DroppedEventsLogger = metrics.MustStaticLogger([]metrics.DimensionSet{
+		{
+			"Destination",
+		},
+	}, []metrics.Metric{
+		{
+			Name: "DroppedEvents",
+			Unit: metrics.UnitCount,
+		},
 	})
 )
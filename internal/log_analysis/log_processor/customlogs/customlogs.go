@@ -72,6 +72,14 @@ func Build(desc logtypes.Desc, schema *logschema.Schema) (logtypes.Entry, error)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to build preprocessor")
 	}
+	transform, err := logschema.BuildTransform(valueSchema)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build field transforms")
+	}
+	if transform != nil {
+		// Field transforms apply to the JSON output of the parser
+		preProcessor = preprocessors.Pipeline(preProcessor, transform)
+	}
 	entry, err := logtypes.Config{
 		Name:         logType,
 		Description:  desc.Description,
@@ -85,6 +93,7 @@ func Build(desc logtypes.Desc, schema *logschema.Schema) (logtypes.Entry, error)
 			Builder:      pantherlog.ResultBuilder{},
 			Validate:     pantherlog.ValidateStruct,
 		},
+		Framing: schema.Framing,
 	}.BuildEntry()
 	if err != nil {
 		return nil, errors.WithMessage(err, "log type entry generation failed")
@@ -102,6 +111,14 @@ func buildPreprocessor(parser *logschema.Parser) (preprocessors.Interface, error
 		return parser.CSV.BuildPreprocessor()
 	case parser.Regex != nil:
 		return parser.Regex.BuildPreprocessor()
+	case parser.KV != nil:
+		return parser.KV.BuildPreprocessor()
+	case parser.Logfmt != nil:
+		return parser.Logfmt.BuildPreprocessor()
+	case parser.CEF != nil:
+		return parser.CEF.BuildPreprocessor()
+	case parser.LEEF != nil:
+		return parser.LEEF.BuildPreprocessor()
 	default:
 		return preprocessors.Nop(), nil
 	}
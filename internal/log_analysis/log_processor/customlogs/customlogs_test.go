@@ -39,6 +39,7 @@ import (
 	"github.com/tidwall/sjson"
 	"gopkg.in/yaml.v2"
 
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue/glueschema"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/customlogs"
 	logschema "github.com/panther-labs/panther/internal/log_analysis/log_processor/logschema"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
@@ -279,6 +280,62 @@ func TestApacheCommonLog_FastMatch(t *testing.T) {
 	}
 }
 
+func TestFieldTransforms(t *testing.T) {
+	schemaFile := "../logschema/testdata/transforms_schema.yml"
+	assert := require.New(t)
+	data, err := ioutil.ReadFile(schemaFile)
+	assert.NoError(err)
+	logSchema := logschema.Schema{}
+	assert.NoError(yaml.Unmarshal(data, &logSchema))
+	assert.NoError(logschema.ValidateSchema(&logSchema))
+	desc := logtypes.Desc{
+		Name:         logSchema.Schema,
+		Description:  "foo",
+		ReferenceURL: "-",
+	}
+	entry, err := customlogs.Build(desc, &logSchema)
+	assert.NoError(err)
+	input := `{
+  "time": "2020-10-10T13:55:36Z",
+  "data": {"attributes": {"user_name": "frank"}},
+  "active": "yes",
+  "tags": "a, b,,c",
+  "labels": {"app.kubernetes.io/name": "web", "peer": "10.0.0.1"},
+  "message": "request_id=abc123 done",
+  "details": "{\"ip\":\"127.0.0.1\",\"ok\":\"Y\"}"
+}`
+	expectJSON := fmt.Sprintf(`{
+  "time": "2020-10-10T13:55:36Z",
+  "user": "frank",
+  "active": true,
+  "tags": ["a", "b", "c"],
+  "level": "info",
+  "labels": {"app.kubernetes.io/name": "web", "peer": "10.0.0.1"},
+  "request_id": "abc123",
+  "message": "request_id=abc123 done",
+  "details": {"ip": "127.0.0.1", "ok": true},
+  "p_log_type": "%s",
+  "p_any_ip_addresses": ["10.0.0.1", "127.0.0.1"],
+  "p_event_time": "2020-10-10T13:55:36Z"
+}`, entry.String())
+	logtesting.TestRegisteredParser(t, entry, entry.String(), input, expectJSON)
+
+	cols, err := glueschema.InferColumns(entry.Schema())
+	assert.NoError(err)
+	assert.Contains(cols, glueschema.Column{
+		Name:    "labels",
+		Type:    "map<string,string>",
+		Comment: "labels",
+	})
+
+	parser, err := entry.NewParser(nil)
+	assert.NoError(err)
+	// Values that violate a constraint do not drop the event
+	results, err := parser.ParseLog(`{"time": "2020-10-10T13:55:36Z", "level": "debug"}`)
+	assert.NoError(err)
+	assert.Len(results, 1)
+}
+
 //nolint: lll
 func TestVPCFlowLog_CSV(t *testing.T) {
 	schemaFile := "../logschema/testdata/vpcflow_schema.yml"
@@ -352,3 +409,29 @@ func TestNameCollisions(t *testing.T) {
 	assert.Error(err)
 	assert.Nil(entry)
 }
+
+func TestBuildFraming(t *testing.T) {
+	assert := require.New(t)
+	schemaYAML := `
+version: 0
+framing:
+  mode: regex
+  startPattern: '^\d{4}-\d{2}-\d{2} '
+fields:
+- name: foo
+  type: string
+`
+	logSchema := logschema.Schema{}
+	assert.NoError(yaml.Unmarshal([]byte(schemaYAML), &logSchema))
+	desc := logtypes.Desc{
+		Name: "Framed",
+	}
+	entry, err := customlogs.Build(desc, &logSchema)
+	assert.NoError(err)
+	assert.Equal(logSchema.Framing, logtypes.Framing(entry))
+
+	// The schema spec requires a start pattern for regex framing
+	logSchema.Framing.StartPattern = ""
+	_, err = customlogs.Build(desc, &logSchema)
+	assert.Error(err)
+}
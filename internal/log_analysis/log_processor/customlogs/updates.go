@@ -46,11 +46,32 @@ func CheckSchemaChange(d *logschema.Change) error {
 			return errors.Errorf("cannot change value type from %q to %q on field %q at %q", from.Type, to.Type, target, path)
 		}
 		return nil
+	case logschema.UpdateFieldTransform:
+		// Changing where a field reads its value from would mix unrelated data in the same column
+		from, to := transformSource(d.From.(*logschema.Transform)), transformSource(d.To.(*logschema.Transform))
+		if from != to {
+			target, path := splitPath(d.Path[:len(d.Path)-1])
+			return errors.Errorf("cannot change source of field %q at %q from %q to %q", target, path, from, to)
+		}
+		return nil
 	default:
 		return nil
 	}
 }
 
+// transformSource returns the path a field reads its value from, relative to its parent object.
+// An empty path means the field reads the value under its own name.
+func transformSource(t *logschema.Transform) string {
+	switch {
+	case t == nil:
+		return ""
+	case t.RenameFrom != "":
+		return t.RenameFrom
+	default:
+		return t.CopyFrom
+	}
+}
+
 func splitPath(path []string) (string, string) {
 	if last := len(path) - 1; 0 <= last && last < len(path) {
 		return path[last], strings.Join(path[:last], ".")
@@ -79,4 +79,24 @@ func TestCheckSchemaUpdates(t *testing.T) {
 			},
 		},
 	}))
+	assert.NoError(CheckSchemaChange(&logschema.Change{
+		Type: logschema.UpdateFieldTransform,
+		Path: []string{"Foo", "Bar", "Transform"},
+		From: (*logschema.Transform)(nil),
+		To: &logschema.Transform{
+			ValueMap: map[string]string{"yes": "true"},
+		},
+	}))
+	assert.NoError(CheckSchemaChange(&logschema.Change{
+		Type: logschema.UpdateFieldTransform,
+		Path: []string{"Foo", "Bar", "Transform"},
+		From: &logschema.Transform{RenameFrom: "baz.qux"},
+		To:   &logschema.Transform{CopyFrom: "baz.qux"},
+	}))
+	assert.Error(CheckSchemaChange(&logschema.Change{
+		Type: logschema.UpdateFieldTransform,
+		Path: []string{"Foo", "Bar", "Transform"},
+		From: (*logschema.Transform)(nil),
+		To:   &logschema.Transform{RenameFrom: "baz.qux"},
+	}))
 }
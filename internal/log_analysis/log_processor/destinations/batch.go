@@ -0,0 +1,158 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+const (
+	// defaults for destinations that send events in batches
+	defaultMaxBatchEvents   = 500
+	defaultMaxBatchBytes    = 5 * 1024 * 1024
+	defaultMaxBatchDuration = 10 * time.Second
+)
+
+// batchSender delivers batches of serialized events to an external system
+type batchSender interface {
+	// sendBatch delivers all events in the batch, the batch is reused after the call returns
+	sendBatch(batch *eventBatch) error
+	// close releases any resources held by the sender after the last batch is sent
+	close() error
+}
+
+// batchEvent is a single event serialized as a JSON object
+type batchEvent struct {
+	logType string
+	data    []byte
+}
+
+// eventBatch is a group of serialized events
+type eventBatch struct {
+	events []batchEvent
+	// the total size of the serialized events
+	bytes int
+	// the time the first event was added to the batch
+	createdAt time.Time
+}
+
+func (b *eventBatch) add(logType string, data []byte) {
+	if len(b.events) == 0 {
+		b.createdAt = time.Now()
+	}
+	b.events = append(b.events, batchEvent{
+		logType: logType,
+		data:    data,
+	})
+	b.bytes += len(data)
+}
+
+func (b *eventBatch) reset() {
+	for i := range b.events {
+		b.events[i] = batchEvent{}
+	}
+	b.events = b.events[:0]
+	b.bytes = 0
+	b.createdAt = time.Time{}
+}
+
+// batchDestination serializes events to JSON and delivers them in batches using a batchSender.
+// A batch is sent when it reaches maxEvents or maxBytes or it is older than maxDuration.
+type batchDestination struct {
+	// name is used to identify the destination in errors and logs
+	name        string
+	sender      batchSender
+	jsonAPI     jsoniter.API
+	maxEvents   int
+	maxBytes    int
+	maxDuration time.Duration
+}
+
+// SendEvents implements Destination interface.
+// If the method encounters an error it writes an error to the errorChannel and continues
+// until channel is closed (skipping events).
+func (d *batchDestination) SendEvents(parsedEventChannel chan *parsers.Result, errChan chan error) {
+	flushExpired := time.NewTicker(d.maxDuration)
+	defer flushExpired.Stop()
+
+	batch := &eventBatch{}
+	failed := false // set to true on error and loop will drain channel
+	fail := func(err error) {
+		failed = true
+		errChan <- errors.WithMessagef(err, "%s destination failed", d.name)
+	}
+	flush := func() {
+		if len(batch.events) == 0 {
+			return
+		}
+		if err := d.sender.sendBatch(batch); err != nil {
+			fail(err)
+		}
+		batch.reset()
+	}
+
+	stream := jsoniter.NewStream(d.jsonAPI, nil, 8192)
+	run := true
+	for run {
+		select {
+		case <-flushExpired.C:
+			if failed || len(batch.events) == 0 {
+				break
+			}
+			if time.Since(batch.createdAt) >= d.maxDuration {
+				flush()
+			}
+		case event, ok := <-parsedEventChannel:
+			if !ok {
+				run = false
+				break
+			}
+			if failed {
+				// Keep draining the channel so the upstream does not block
+				break
+			}
+			stream.Reset(nil)
+			stream.WriteVal(event)
+			if err := stream.Error; err != nil {
+				stream.Error = nil
+				fail(errors.Wrapf(err, "failed to serialize %s event to JSON", event.PantherLogType))
+				break
+			}
+			// The stream buffer is reused so we need to copy the data
+			data := append([]byte(nil), stream.Buffer()...)
+			batch.add(event.PantherLogType, data)
+			if len(batch.events) >= d.maxEvents || batch.bytes >= d.maxBytes {
+				flush()
+			}
+		}
+	}
+	if !failed {
+		flush()
+	}
+	if err := d.sender.close(); err != nil {
+		errChan <- errors.WithMessagef(err, "%s destination failed to close", d.name)
+	}
+	zap.L().Debug("finished sending events", zap.String("destination", d.name))
+}
@@ -19,10 +19,76 @@ package destinations
  */
 
 import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/pkg/stringset"
+)
+
+const (
+	DestinationS3    = "s3"
+	DestinationHTTP  = "http"
+	DestinationKafka = "kafka"
+	DestinationFile  = "file"
 )
 
 // Destination defines the interface that all Destinations should follow
 type Destination interface {
 	SendEvents(parsedEventChannel chan *parsers.Result, errChan chan error)
 }
+
+// CreateDestination creates the destination for processed events from common.Config.
+// If more than one destination is configured, events are sent to all of them.
+func CreateDestination(jsonAPI jsoniter.API, resolver logtypes.Resolver) (Destination, error) {
+	return newDestination(&common.Config, jsonAPI, resolver)
+}
+
+func newDestination(config *common.EnvConfig, jsonAPI jsoniter.API, resolver logtypes.Resolver) (Destination, error) {
+	names := config.Destinations
+	if len(names) == 0 {
+		names = []string{DestinationS3}
+	}
+	required := config.RequiredDestinations
+	if required == nil {
+		required = []string{DestinationS3}
+	}
+	targets := make([]FanOutTarget, 0, len(names))
+	for _, name := range stringset.New(names...) {
+		var dest Destination
+		switch name {
+		case DestinationS3:
+			dest = CreateS3Destination(jsonAPI, resolver)
+		case DestinationHTTP:
+			if config.HTTPDestinationURL == "" {
+				return nil, errors.New("HTTP destination requires a URL")
+			}
+			dest = NewHTTPDestination(config.HTTPDestinationURL, config.HTTPDestinationHeaders, jsonAPI)
+		case DestinationKafka:
+			if len(config.KafkaDestinationBrokers) == 0 || config.KafkaDestinationTopic == "" {
+				return nil, errors.New("Kafka destination requires brokers and a topic")
+			}
+			dest = NewKafkaDestination(config.KafkaDestinationBrokers, config.KafkaDestinationTopic,
+				config.KafkaDestinationTLS, jsonAPI)
+		case DestinationFile:
+			if config.FileDestinationDir == "" {
+				return nil, errors.New("file destination requires a directory")
+			}
+			dest = NewFileDestination(config.FileDestinationDir, config.FileDestinationMaxBytes, jsonAPI)
+		default:
+			return nil, errors.Errorf("unknown destination %q", name)
+		}
+		targets = append(targets, FanOutTarget{
+			Name:        name,
+			Destination: dest,
+			Required:    stringset.Contains(required, name),
+			BufferSize:  config.FanOutBufferSize,
+		})
+	}
+	if len(targets) == 1 {
+		return targets[0].Destination, nil
+	}
+	return NewFanOutDestination(jsonAPI, targets...), nil
+}
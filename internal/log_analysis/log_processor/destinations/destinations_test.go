@@ -0,0 +1,70 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+)
+
+func TestNewDestination(t *testing.T) {
+	t.Parallel()
+
+	dest, err := newDestination(&common.EnvConfig{}, nil, nil)
+	require.NoError(t, err)
+	require.IsType(t, &S3Destination{}, dest)
+
+	dest, err = newDestination(&common.EnvConfig{
+		Destinations:       []string{"file", "file"},
+		FileDestinationDir: "/tmp",
+	}, nil, nil)
+	require.NoError(t, err)
+	require.IsType(t, &batchDestination{}, dest)
+
+	dest, err = newDestination(&common.EnvConfig{
+		Destinations:            []string{"s3", "http", "kafka"},
+		RequiredDestinations:    []string{"s3", "kafka"},
+		HTTPDestinationURL:      "https://example.com/events",
+		KafkaDestinationBrokers: []string{"localhost:9092"},
+		KafkaDestinationTopic:   "events",
+	}, nil, nil)
+	require.NoError(t, err)
+	require.IsType(t, &FanOutDestination{}, dest)
+	targets := dest.(*FanOutDestination).targets
+	require.Len(t, targets, 3)
+	require.Equal(t, "s3", targets[0].Name)
+	require.True(t, targets[0].Required)
+	require.Equal(t, "http", targets[1].Name)
+	require.False(t, targets[1].Required)
+	require.Equal(t, "kafka", targets[2].Name)
+	require.True(t, targets[2].Required)
+
+	for _, config := range []*common.EnvConfig{
+		{Destinations: []string{"foo"}},
+		{Destinations: []string{"http"}},
+		{Destinations: []string{"kafka"}, KafkaDestinationTopic: "events"},
+		{Destinations: []string{"file"}},
+	} {
+		_, err := newDestination(config, nil, nil)
+		require.Error(t, err)
+	}
+}
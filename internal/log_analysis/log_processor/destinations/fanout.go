@@ -0,0 +1,152 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/pkg/metrics"
+)
+
+// default number of events buffered for each target of a fan-out destination
+const fanOutBufferSize = 1000
+
+// FanOutTarget is a destination receiving all events of a fan-out destination
+type FanOutTarget struct {
+	// Name identifies the target in errors and logs
+	Name        string
+	Destination Destination
+	// Errors of required targets fail log processing, errors of other targets are only logged
+	Required bool
+	// BufferSize is the number of events buffered for the target, defaults to fanOutBufferSize.
+	// Required targets block processing while their buffer is full, events are dropped for optional targets.
+	BufferSize int
+}
+
+// NewFanOutDestination creates a destination that sends all events to each of the targets.
+// Each target runs independently, a failing or slow optional target does not stop events from reaching the other
+// targets.
+func NewFanOutDestination(jsonAPI jsoniter.API, targets ...FanOutTarget) Destination {
+	if jsonAPI == nil {
+		jsonAPI = jsoniter.ConfigDefault
+	}
+	return &FanOutDestination{
+		jsonAPI: jsonAPI,
+		targets: targets,
+	}
+}
+
+// FanOutDestination sends events to multiple destinations
+type FanOutDestination struct {
+	jsonAPI jsoniter.API
+	targets []FanOutTarget
+}
+
+// SendEvents implements Destination interface
+func (d *FanOutDestination) SendEvents(parsedEventChannel chan *parsers.Result, errChan chan error) {
+	var wg sync.WaitGroup
+	channels := make([]chan *parsers.Result, len(d.targets))
+	dropped := make([]int, len(d.targets))
+	for i := range d.targets {
+		target := d.targets[i]
+		bufferSize := target.BufferSize
+		if bufferSize <= 0 {
+			bufferSize = fanOutBufferSize
+		}
+		events := make(chan *parsers.Result, bufferSize)
+		targetErrors := make(chan error)
+		channels[i] = events
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			defer close(targetErrors)
+			target.Destination.SendEvents(events, targetErrors)
+		}()
+		go func() {
+			defer wg.Done()
+			for err := range targetErrors {
+				if target.Required {
+					errChan <- errors.WithMessagef(err, "destination %s failed", target.Name)
+					continue
+				}
+				zap.L().Error("optional destination failed", zap.String("destination", target.Name), zap.Error(err))
+			}
+		}()
+	}
+
+	stream := jsoniter.NewStream(d.jsonAPI, nil, 8192)
+	failed := false
+	for event := range parsedEventChannel {
+		if failed {
+			continue
+		}
+		shared, err := shareResult(stream, event)
+		if err != nil {
+			failed = true
+			errChan <- err
+			continue
+		}
+		for i, events := range channels {
+			if d.targets[i].Required {
+				events <- shared
+				continue
+			}
+			select {
+			case events <- shared:
+			default:
+				dropped[i]++
+			}
+		}
+	}
+	for _, events := range channels {
+		close(events)
+	}
+	wg.Wait()
+	for i, n := range dropped {
+		if n > 0 {
+			zap.L().Warn("optional destination buffer full, events dropped",
+				zap.String("destination", d.targets[i].Name), zap.Int("numEvents", n))
+			common.DroppedEventsLogger.LogSingle(n, metrics.Dimension{Name: "Destination", Value: d.targets[i].Name})
+		}
+	}
+}
+
+// shareResult serializes a result once so it can be safely read by multiple destinations at the same time.
+// Serializing a result updates its fields (i.e. p_event_time and indicator fields) so results cannot be shared as-is.
+// Results that are already serialized are shared as-is.
+func shareResult(stream *jsoniter.Stream, result *parsers.Result) (*parsers.Result, error) {
+	if result.RawJSON() != nil {
+		return result, nil
+	}
+	stream.Reset(nil)
+	stream.WriteVal(result)
+	if err := stream.Error; err != nil {
+		stream.Error = nil
+		return nil, errors.Wrapf(err, "failed to serialize %s event to JSON", result.PantherLogType)
+	}
+	data := append([]byte(nil), stream.Buffer()...)
+	return pantherlog.NewRawResult(result, data), nil
+}
@@ -0,0 +1,166 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+// testDestination records the JSON of all events and fails after failAfter events
+type testDestination struct {
+	events    []string
+	failAfter int
+}
+
+func (d *testDestination) SendEvents(events chan *parsers.Result, errChan chan error) {
+	jsonAPI := common.ConfigForDataLakeWriters()
+	for event := range events {
+		if d.failAfter > 0 && len(d.events) == d.failAfter {
+			errChan <- errors.New("failed")
+			// Keep draining
+			d.failAfter = -1
+			continue
+		}
+		if d.failAfter < 0 {
+			continue
+		}
+		data, err := jsonAPI.MarshalToString(event)
+		if err != nil {
+			errChan <- err
+			continue
+		}
+		d.events = append(d.events, data)
+	}
+}
+
+func TestFanOutDestination(t *testing.T) {
+	t.Parallel()
+
+	const numEvents = 3
+	required := &testDestination{}
+	optional := &testDestination{failAfter: 1}
+	destination := NewFanOutDestination(common.ConfigForDataLakeWriters(),
+		FanOutTarget{Name: "required", Destination: required, Required: true},
+		FanOutTarget{Name: "optional", Destination: optional},
+	)
+
+	expect, err := common.ConfigForDataLakeWriters().MarshalToString(newTestResult(nil))
+	require.NoError(t, err)
+	events := make(chan *parsers.Result, numEvents)
+	for i := 0; i < numEvents; i++ {
+		events <- newTestResult(nil)
+	}
+	close(events)
+	// The optional destination error is not reported
+	require.NoError(t, runDestination(destination, events))
+
+	require.Len(t, required.events, numEvents)
+	for _, event := range required.events {
+		require.JSONEq(t, expect, event)
+	}
+	require.Len(t, optional.events, 1)
+	require.JSONEq(t, expect, optional.events[0])
+}
+
+func TestFanOutDestinationRequiredError(t *testing.T) {
+	t.Parallel()
+
+	required := &testDestination{failAfter: 1}
+	optional := &testDestination{}
+	destination := NewFanOutDestination(nil,
+		FanOutTarget{Name: "required", Destination: required, Required: true},
+		FanOutTarget{Name: "optional", Destination: optional},
+	)
+
+	events := make(chan *parsers.Result, 2)
+	events <- newTestResult(nil)
+	events <- newTestResult(nil)
+	close(events)
+	err := runDestination(destination, events)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "destination required failed")
+	// Other destinations are not affected
+	require.Len(t, optional.events, 2)
+}
+
+// releaseDestination records events and closes release after receiving releaseAfter events
+type releaseDestination struct {
+	events       []string
+	release      chan struct{}
+	releaseAfter int
+}
+
+func (d *releaseDestination) SendEvents(events chan *parsers.Result, errChan chan error) {
+	for event := range events {
+		d.events = append(d.events, event.PantherLogType)
+		if len(d.events) == d.releaseAfter {
+			close(d.release)
+		}
+	}
+}
+
+// blockedDestination does not read events until release is closed
+type blockedDestination struct {
+	testDestination
+	release chan struct{}
+}
+
+func (d *blockedDestination) SendEvents(events chan *parsers.Result, errChan chan error) {
+	<-d.release
+	d.testDestination.SendEvents(events, errChan)
+}
+
+func TestFanOutDestinationOptionalBufferFull(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	defer zap.ReplaceGlobals(zap.New(core))()
+
+	const numEvents = 5
+	release := make(chan struct{})
+	required := &releaseDestination{release: release, releaseAfter: numEvents}
+	optional := &blockedDestination{release: release}
+	// The optional target comes first so it has been offered each event before the required target receives it
+	destination := NewFanOutDestination(nil,
+		FanOutTarget{Name: "optional", Destination: optional, BufferSize: 1},
+		FanOutTarget{Name: "required", Destination: required, Required: true, BufferSize: 1},
+	)
+
+	events := make(chan *parsers.Result, numEvents)
+	for i := 0; i < numEvents; i++ {
+		events <- newTestResult(nil)
+	}
+	close(events)
+	require.NoError(t, runDestination(destination, events))
+
+	// The slow optional target does not block the required target, events that do not fit its buffer are dropped
+	require.Len(t, required.events, numEvents)
+	require.Len(t, optional.events, 1)
+	// The dropped events are reported for each destination
+	metric := logs.FilterMessage("metric").FilterField(zap.String("Destination", "optional")).All()
+	require.Len(t, metric, 1)
+	require.Equal(t, int64(numEvents-1), metric[0].ContextMap()["DroppedEvents"])
+	require.Zero(t, logs.FilterMessage("metric").FilterField(zap.String("Destination", "required")).Len())
+}
@@ -0,0 +1,147 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// default maximum size of a file before it is rotated
+	defaultFileMaxBytes = 100 * 1024 * 1024
+	// maximum age of a file before it is rotated
+	fileMaxAge = time.Hour
+	// The timestamp layout used in the file names
+	fileTimestampLayout = "20060102T150405.000000000Z"
+)
+
+// NewFileDestination creates a destination that writes events as newline delimited JSON to files in dir.
+// A new file is started once the current one exceeds maxBytes or becomes older than an hour.
+func NewFileDestination(dir string, maxBytes int64, jsonAPI jsoniter.API) Destination {
+	if jsonAPI == nil {
+		jsonAPI = jsoniter.ConfigDefault
+	}
+	if maxBytes <= 0 {
+		maxBytes = defaultFileMaxBytes
+	}
+	return &batchDestination{
+		name: "file",
+		sender: &fileSender{
+			dir:      dir,
+			maxBytes: maxBytes,
+			maxAge:   fileMaxAge,
+		},
+		jsonAPI:     jsonAPI,
+		maxEvents:   defaultMaxBatchEvents,
+		maxBytes:    defaultMaxBatchBytes,
+		maxDuration: defaultMaxBatchDuration,
+	}
+}
+
+type fileSender struct {
+	dir      string
+	maxBytes int64
+	maxAge   time.Duration
+
+	file      *os.File
+	w         *bufio.Writer
+	size      int64
+	createdAt time.Time
+}
+
+var _ batchSender = (*fileSender)(nil)
+
+// sendBatch implements batchSender interface
+func (s *fileSender) sendBatch(batch *eventBatch) error {
+	for i := range batch.events {
+		data := batch.events[i].data
+		if err := s.rotate(int64(len(data) + 1)); err != nil {
+			return err
+		}
+		n, err := s.w.Write(data)
+		s.size += int64(n)
+		if err != nil {
+			return errors.Wrapf(err, "failed to write to %s", s.file.Name())
+		}
+		n, err = s.w.Write(newLineDelimiter)
+		s.size += int64(n)
+		if err != nil {
+			return errors.Wrapf(err, "failed to write to %s", s.file.Name())
+		}
+	}
+	// Flush so the events are visible to readers of the file
+	if err := s.w.Flush(); err != nil {
+		return errors.Wrapf(err, "failed to write to %s", s.file.Name())
+	}
+	return nil
+}
+
+// rotate starts a new file if writing n more bytes to the current file would exceed its limits
+func (s *fileSender) rotate(n int64) error {
+	if s.file != nil {
+		full := s.size > 0 && s.size+n > s.maxBytes
+		expired := time.Since(s.createdAt) >= s.maxAge
+		if !full && !expired {
+			return nil
+		}
+		if err := s.close(); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create directory %s", s.dir)
+	}
+	now := time.Now().UTC()
+	name := filepath.Join(s.dir, fmt.Sprintf("events-%s.json", now.Format(fileTimestampLayout)))
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to create file")
+	}
+	zap.L().Debug("writing events to file", zap.String("file", name))
+	s.file = f
+	s.w = bufio.NewWriter(f)
+	s.size = 0
+	s.createdAt = now
+	return nil
+}
+
+// close implements batchSender interface
+func (s *fileSender) close() error {
+	if s.file == nil {
+		return nil
+	}
+	f, w := s.file, s.w
+	s.file, s.w, s.size = nil, nil, 0
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return errors.Wrapf(err, "failed to write to %s", f.Name())
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrapf(err, "failed to close %s", f.Name())
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+func TestFileDestination(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "file-destination")
+	require.NoError(t, err)
+	dir = filepath.Join(dir, "events")
+
+	const numEvents = 5
+	// Rotate after each event
+	destination := NewFileDestination(dir, 1, common.ConfigForDataLakeWriters())
+	destination.(*batchDestination).maxEvents = 2
+
+	events := make(chan *parsers.Result, numEvents)
+	for i := 0; i < numEvents; i++ {
+		events <- newTestResult(nil)
+	}
+	close(events)
+	require.NoError(t, runDestination(destination, events))
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, numEvents)
+	for _, f := range files {
+		require.True(t, strings.HasPrefix(f.Name(), "events-"))
+		data, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		require.NoError(t, err)
+		require.Equal(t, 1, strings.Count(string(data), "\n"))
+		require.Contains(t, string(data), `"foo":"bar"`)
+	}
+}
+
+func TestFileDestinationNoRotation(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "file-destination")
+	require.NoError(t, err)
+
+	const numEvents = 5
+	destination := NewFileDestination(dir, 0, nil)
+	destination.(*batchDestination).maxEvents = 2
+
+	events := make(chan *parsers.Result, numEvents)
+	for i := 0; i < numEvents; i++ {
+		events <- newTestResult(nil)
+	}
+	close(events)
+	require.NoError(t, runDestination(destination, events))
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := ioutil.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	require.Equal(t, numEvents, strings.Count(string(data), "\n"))
+}
@@ -0,0 +1,136 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	httpRequestTimeout = 30 * time.Second
+	// maximum time spent retrying a batch
+	httpMaxRetryDuration = 2 * time.Minute
+)
+
+// NewHTTPDestination creates a destination that POSTs batches of events to a URL.
+// Each request body is gzip compressed newline delimited JSON. Headers are added to all requests.
+func NewHTTPDestination(url string, headers map[string]string, jsonAPI jsoniter.API) Destination {
+	if jsonAPI == nil {
+		jsonAPI = jsoniter.ConfigDefault
+	}
+	return &batchDestination{
+		name: "http",
+		sender: &httpSender{
+			url:     url,
+			headers: headers,
+			client: &http.Client{
+				Timeout: httpRequestTimeout,
+			},
+			maxRetryDuration: httpMaxRetryDuration,
+		},
+		jsonAPI:     jsonAPI,
+		maxEvents:   defaultMaxBatchEvents,
+		maxBytes:    defaultMaxBatchBytes,
+		maxDuration: defaultMaxBatchDuration,
+	}
+}
+
+type httpSender struct {
+	url              string
+	headers          map[string]string
+	client           *http.Client
+	maxRetryDuration time.Duration
+	body             bytes.Buffer
+}
+
+var _ batchSender = (*httpSender)(nil)
+
+// sendBatch implements batchSender interface
+func (s *httpSender) sendBatch(batch *eventBatch) error {
+	s.body.Reset()
+	w := gzip.NewWriter(&s.body)
+	for i := range batch.events {
+		if _, err := w.Write(batch.events[i].data); err != nil {
+			return errors.Wrap(err, "failed to compress events")
+		}
+		if _, err := w.Write(newLineDelimiter); err != nil {
+			return errors.Wrap(err, "failed to compress events")
+		}
+	}
+	if err := w.Close(); err != nil {
+		return errors.Wrap(err, "failed to compress events")
+	}
+	body := s.body.Bytes()
+
+	retry := backoff.NewExponentialBackOff()
+	retry.MaxElapsedTime = s.maxRetryDuration
+	return backoff.RetryNotify(func() error {
+		return s.post(body)
+	}, retry, func(err error, wait time.Duration) {
+		zap.L().Warn("retrying HTTP destination request", zap.Error(err), zap.Duration("wait", wait))
+	})
+}
+
+func (s *httpSender) post(body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), httpRequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return backoff.Permanent(errors.Wrap(err, "invalid HTTP request"))
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set("Content-Encoding", "gzip")
+	for name, value := range s.headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "HTTP request failed")
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	switch code := resp.StatusCode; {
+	case 200 <= code && code < 300:
+		return nil
+	case code == http.StatusTooManyRequests || code >= 500:
+		return errors.Errorf("HTTP request failed with status %d", code)
+	default:
+		// Retrying will not help with other client errors
+		return backoff.Permanent(errors.Errorf("HTTP request failed with status %d", code))
+	}
+}
+
+// close implements batchSender interface
+func (s *httpSender) close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,118 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+func TestHTTPDestination(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		requests int
+		bodies   []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		// Fail the first request to check retries
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+		require.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+		gz, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(gz)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+	}))
+	defer srv.Close()
+
+	destination := NewHTTPDestination(srv.URL, map[string]string{"X-Api-Key": "secret"}, common.ConfigForDataLakeWriters())
+	destination.(*batchDestination).maxEvents = 2
+
+	events := make(chan *parsers.Result, 3)
+	for i := 0; i < 3; i++ {
+		events <- newTestResult(nil)
+	}
+	close(events)
+	require.NoError(t, runDestination(destination, events))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, 3, requests)
+	require.Len(t, bodies, 2)
+	lines := strings.Split(strings.TrimSpace(bodies[0]), "\n")
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], `"foo":"bar"`)
+	require.Contains(t, lines[0], `"p_log_type":"testLogType"`)
+	lines = strings.Split(strings.TrimSpace(bodies[1]), "\n")
+	require.Len(t, lines, 1)
+}
+
+func TestHTTPDestinationClientError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		requests int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	destination := NewHTTPDestination(srv.URL, nil, nil)
+	destination.(*batchDestination).maxEvents = 1
+	destination.(*batchDestination).sender.(*httpSender).maxRetryDuration = time.Second
+
+	events := make(chan *parsers.Result, 2)
+	events <- newTestResult(nil)
+	events <- newTestResult(nil)
+	close(events)
+	err := runDestination(destination, events)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "status 400")
+
+	mu.Lock()
+	defer mu.Unlock()
+	// Client errors are not retried and events after the failure are dropped
+	require.Equal(t, 1, requests)
+}
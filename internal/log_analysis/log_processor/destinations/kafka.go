@@ -0,0 +1,111 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	kafkaWriteTimeout = time.Minute
+	// the Kafka message header with the log type of the event
+	kafkaLogTypeHeader = "p_log_type"
+)
+
+// NewKafkaDestination creates a destination that produces events to a topic using the Kafka protocol.
+// Each event is a message keyed by its log type so events of the same log type keep their order.
+func NewKafkaDestination(brokers []string, topic string, useTLS bool, jsonAPI jsoniter.API) Destination {
+	if jsonAPI == nil {
+		jsonAPI = jsoniter.ConfigDefault
+	}
+	transport := &kafka.Transport{}
+	if useTLS {
+		transport.TLS = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	return &batchDestination{
+		name: "kafka",
+		sender: &kafkaSender{
+			writer: &kafka.Writer{
+				Addr:         kafka.TCP(brokers...),
+				Topic:        topic,
+				Balancer:     &kafka.Hash{},
+				RequiredAcks: kafka.RequireAll,
+				Compression:  kafka.Snappy,
+				Transport:    transport,
+			},
+		},
+		jsonAPI:     jsonAPI,
+		maxEvents:   defaultMaxBatchEvents,
+		maxBytes:    defaultMaxBatchBytes,
+		maxDuration: defaultMaxBatchDuration,
+	}
+}
+
+// kafkaWriter is the part of kafka.Writer used by kafkaSender
+type kafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+type kafkaSender struct {
+	writer   kafkaWriter
+	messages []kafka.Message
+}
+
+var _ batchSender = (*kafkaSender)(nil)
+
+// sendBatch implements batchSender interface
+func (s *kafkaSender) sendBatch(batch *eventBatch) error {
+	messages := s.messages[:0]
+	for i := range batch.events {
+		event := &batch.events[i]
+		messages = append(messages, kafka.Message{
+			Key:   []byte(event.logType),
+			Value: event.data,
+			Headers: []kafka.Header{
+				{
+					Key:   kafkaLogTypeHeader,
+					Value: []byte(event.logType),
+				},
+			},
+		})
+	}
+	// The writer keeps no reference to the messages once WriteMessages returns
+	s.messages = messages[:0]
+
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaWriteTimeout)
+	defer cancel()
+	if err := s.writer.WriteMessages(ctx, messages...); err != nil {
+		return errors.Wrap(err, "failed to produce messages")
+	}
+	return nil
+}
+
+// close implements batchSender interface
+func (s *kafkaSender) close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,91 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+type testKafkaWriter struct {
+	messages []kafka.Message
+	err      error
+	closed   bool
+}
+
+func (w *testKafkaWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	if w.err != nil {
+		return w.err
+	}
+	// The sender reuses the slice of messages
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+func (w *testKafkaWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestKafkaDestination(t *testing.T) {
+	t.Parallel()
+
+	destination := NewKafkaDestination([]string{"localhost:9092"}, "events", false, common.ConfigForDataLakeWriters())
+	writer := &testKafkaWriter{}
+	destination.(*batchDestination).sender = &kafkaSender{writer: writer}
+	destination.(*batchDestination).maxEvents = 2
+
+	events := make(chan *parsers.Result, 3)
+	for i := 0; i < 3; i++ {
+		events <- newTestResult(nil)
+	}
+	close(events)
+	require.NoError(t, runDestination(destination, events))
+
+	require.True(t, writer.closed)
+	require.Len(t, writer.messages, 3)
+	for _, msg := range writer.messages {
+		require.Equal(t, testLogType, string(msg.Key))
+		require.Equal(t, []kafka.Header{{Key: "p_log_type", Value: []byte(testLogType)}}, msg.Headers)
+		require.Contains(t, string(msg.Value), `"foo":"bar"`)
+	}
+}
+
+func TestKafkaDestinationError(t *testing.T) {
+	t.Parallel()
+
+	destination := NewKafkaDestination([]string{"localhost:9092"}, "events", false, nil)
+	writer := &testKafkaWriter{err: errors.New("broker not available")}
+	destination.(*batchDestination).sender = &kafkaSender{writer: writer}
+
+	events := make(chan *parsers.Result, 1)
+	events <- newTestResult(nil)
+	close(events)
+	err := runDestination(destination, events)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "broker not available")
+	require.True(t, writer.closed)
+}
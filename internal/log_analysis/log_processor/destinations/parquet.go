@@ -0,0 +1,482 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue/glueschema"
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue/gluetimestamp"
+)
+
+const (
+	// number of goroutines used to encode the pages of a Parquet file
+	parquetWriterConcurrency = 2
+	// Rows are held in memory until a row group is flushed to the file, smaller row groups bound the memory used.
+	parquetRowGroupSize = 16 * 1024 * 1024
+)
+
+// Decodes numbers as json.Number so that large integers are not truncated to float64
+var parquetJSON = jsoniter.Config{
+	UseNumber: true,
+}.Froze()
+
+// parquetSchema converts the JSON events of a log type to a Parquet file.
+// The Parquet columns are built from the same Glue columns used to define the table of the log type so that Athena can
+// read the files using the table schema.
+type parquetSchema struct {
+	root *parquetNode
+	// the schema in the JSON format used by parquet-go writers
+	schemaJSON string
+}
+
+func newParquetSchema(columns []glueschema.Column) (*parquetSchema, error) {
+	root := &parquetNode{
+		kind: parquetStruct,
+	}
+	for _, col := range columns {
+		node, err := parseGlueType(string(col.Type))
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid type for column %q", col.Name)
+		}
+		root.addField(col.Name, node)
+	}
+	item := root.schemaItem("parquet_go_root", "REQUIRED")
+	schemaJSON, err := jsoniter.MarshalToString(item)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode Parquet schema")
+	}
+	return &parquetSchema{
+		root:       root,
+		schemaJSON: schemaJSON,
+	}, nil
+}
+
+// convert reads gzipped JSON lines and writes them as rows of a Parquet file.
+// Events are decoded one at a time from the gzip stream and rows are flushed every parquetRowGroupSize bytes so only
+// the compressed input and output are kept in memory.
+func (s *parquetSchema) convert(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read JSON data")
+	}
+	out := &parquetFile{}
+	w, err := writer.NewJSONWriter(s.schemaJSON, out, parquetWriterConcurrency)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Parquet writer")
+	}
+	w.RowGroupSize = parquetRowGroupSize
+	w.CompressionType = parquet.CompressionCodec_SNAPPY
+
+	dec := parquetJSON.NewDecoder(r)
+	for dec.More() {
+		var event interface{}
+		if err := dec.Decode(&event); err != nil {
+			return nil, errors.Wrap(err, "failed to decode JSON event")
+		}
+		row := s.root.normalize(event)
+		if row == nil {
+			return nil, errors.New("JSON event is not an object")
+		}
+		rowJSON, err := parquetJSON.MarshalToString(row)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode Parquet row")
+		}
+		if err := w.Write(rowJSON); err != nil {
+			return nil, errors.Wrap(err, "failed to write Parquet row")
+		}
+	}
+	if err := w.WriteStop(); err != nil {
+		return nil, errors.Wrap(err, "failed to write Parquet file")
+	}
+	return out.Bytes(), nil
+}
+
+type parquetKind int
+
+const (
+	parquetScalar parquetKind = iota
+	parquetArray
+	parquetMap
+	parquetStruct
+)
+
+// parquetNode is the type of a column or a nested field in a Parquet file
+type parquetNode struct {
+	kind parquetKind
+	// Glue type of scalars and map keys
+	typ glueschema.Type
+	// element type of arrays and value type of maps
+	elem *parquetNode
+	// struct fields
+	fields []parquetField
+	index  map[string]int
+}
+
+type parquetField struct {
+	name string
+	node *parquetNode
+}
+
+func (n *parquetNode) addField(name string, node *parquetNode) {
+	// Glue column names are case insensitive
+	name = strings.ToLower(name)
+	if n.index == nil {
+		n.index = make(map[string]int)
+	}
+	n.index[name] = len(n.fields)
+	n.fields = append(n.fields, parquetField{
+		name: name,
+		node: node,
+	})
+}
+
+type parquetSchemaItem struct {
+	Tag    string
+	Fields []*parquetSchemaItem `json:",omitempty"`
+}
+
+func (n *parquetNode) schemaItem(name, repetition string) *parquetSchemaItem {
+	tag := "name=" + name
+	switch n.kind {
+	case parquetArray:
+		return &parquetSchemaItem{
+			Tag: tag + ", type=LIST, repetitiontype=" + repetition,
+			Fields: []*parquetSchemaItem{
+				n.elem.schemaItem("element", "OPTIONAL"),
+			},
+		}
+	case parquetMap:
+		key := &parquetNode{
+			kind: parquetScalar,
+			typ:  n.typ,
+		}
+		return &parquetSchemaItem{
+			Tag: tag + ", type=MAP, repetitiontype=" + repetition,
+			Fields: []*parquetSchemaItem{
+				key.schemaItem("key", "REQUIRED"),
+				n.elem.schemaItem("value", "OPTIONAL"),
+			},
+		}
+	case parquetStruct:
+		item := &parquetSchemaItem{
+			Tag:    tag + ", repetitiontype=" + repetition,
+			Fields: make([]*parquetSchemaItem, len(n.fields)),
+		}
+		for i, field := range n.fields {
+			item.Fields[i] = field.node.schemaItem(field.name, "OPTIONAL")
+		}
+		return item
+	default:
+		return &parquetSchemaItem{
+			Tag: tag + ", type=" + parquetScalarType(n.typ) + ", repetitiontype=" + repetition,
+		}
+	}
+}
+
+func parquetScalarType(typ glueschema.Type) string {
+	switch typ {
+	case glueschema.TypeBool:
+		return "BOOLEAN"
+	case glueschema.TypeTinyInt:
+		return "INT_8"
+	case glueschema.TypeSmallInt:
+		return "INT_16"
+	case glueschema.TypeInt:
+		return "INT32"
+	case glueschema.TypeBigInt:
+		return "INT64"
+	case glueschema.TypeFloat:
+		return "FLOAT"
+	case glueschema.TypeDouble:
+		return "DOUBLE"
+	case glueschema.TypeTimestamp:
+		return "TIMESTAMP_MILLIS"
+	default:
+		return "UTF8"
+	}
+}
+
+// normalize converts a value decoded from JSON to a value matching the Parquet schema.
+// Values that cannot be converted are dropped by returning nil.
+func (n *parquetNode) normalize(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	switch n.kind {
+	case parquetArray:
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		out := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			// parquet-go cannot handle null list elements
+			if v := n.elem.normalize(v); v != nil {
+				out = append(out, v)
+			}
+		}
+		return out
+	case parquetMap:
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		out := make(map[string]interface{}, len(values))
+		for k, v := range values {
+			if v := n.elem.normalize(v); v != nil {
+				out[k] = v
+			}
+		}
+		return out
+	case parquetStruct:
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		out := make(map[string]interface{}, len(values))
+		for k, v := range values {
+			name := strings.ToLower(glueschema.ColumnName(k))
+			i, ok := n.index[name]
+			if !ok {
+				continue
+			}
+			if v := n.fields[i].node.normalize(v); v != nil {
+				out[name] = v
+			}
+		}
+		return out
+	default:
+		return normalizeScalar(n.typ, value)
+	}
+}
+
+func normalizeScalar(typ glueschema.Type, value interface{}) interface{} {
+	switch typ {
+	case glueschema.TypeBool:
+		if b, ok := value.(bool); ok {
+			return b
+		}
+		return nil
+	case glueschema.TypeTinyInt, glueschema.TypeSmallInt, glueschema.TypeInt, glueschema.TypeBigInt:
+		n, ok := value.(json.Number)
+		if !ok {
+			return nil
+		}
+		if i, err := n.Int64(); err == nil {
+			return i
+		}
+		if f, err := n.Float64(); err == nil {
+			return int64(f)
+		}
+		return nil
+	case glueschema.TypeFloat, glueschema.TypeDouble:
+		if n, ok := value.(json.Number); ok {
+			return n
+		}
+		return nil
+	case glueschema.TypeTimestamp:
+		s, ok := value.(string)
+		if !ok {
+			return nil
+		}
+		tm, err := time.Parse(gluetimestamp.Layout, s)
+		if err != nil {
+			if tm, err = time.Parse(time.RFC3339Nano, s); err != nil {
+				return nil
+			}
+		}
+		return tm.UnixNano() / int64(time.Millisecond)
+	default:
+		// Columns holding arbitrary JSON values are stored as strings in Glue
+		if s, ok := value.(string); ok {
+			return s
+		}
+		s, err := parquetJSON.MarshalToString(value)
+		if err != nil {
+			return nil
+		}
+		return s
+	}
+}
+
+// parseGlueType parses a Glue type string (e.g. 'array<struct<foo:string>>') to a Parquet node
+func parseGlueType(typ string) (*parquetNode, error) {
+	p := glueTypeParser{input: typ}
+	node, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.input) {
+		return nil, errors.Errorf("unexpected input at %d in Glue type %q", p.pos, typ)
+	}
+	return node, nil
+}
+
+type glueTypeParser struct {
+	input string
+	pos   int
+}
+
+func (p *glueTypeParser) parseType() (*parquetNode, error) {
+	name := p.readName()
+	switch name {
+	case "array":
+		if err := p.expect('<'); err != nil {
+			return nil, err
+		}
+		elem, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect('>'); err != nil {
+			return nil, err
+		}
+		return &parquetNode{
+			kind: parquetArray,
+			elem: elem,
+		}, nil
+	case "map":
+		if err := p.expect('<'); err != nil {
+			return nil, err
+		}
+		key, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if key.kind != parquetScalar {
+			return nil, errors.Errorf("invalid map key type in Glue type %q", p.input)
+		}
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+		value, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect('>'); err != nil {
+			return nil, err
+		}
+		return &parquetNode{
+			kind: parquetMap,
+			typ:  key.typ,
+			elem: value,
+		}, nil
+	case "struct":
+		if err := p.expect('<'); err != nil {
+			return nil, err
+		}
+		node := &parquetNode{
+			kind: parquetStruct,
+		}
+		for {
+			fieldName := p.readName()
+			if fieldName == "" {
+				return nil, errors.Errorf("missing field name at %d in Glue type %q", p.pos, p.input)
+			}
+			if err := p.expect(':'); err != nil {
+				return nil, err
+			}
+			field, err := p.parseType()
+			if err != nil {
+				return nil, err
+			}
+			node.addField(fieldName, field)
+			if p.peek() != ',' {
+				break
+			}
+			p.pos++
+		}
+		if err := p.expect('>'); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case "":
+		return nil, errors.Errorf("missing type at %d in Glue type %q", p.pos, p.input)
+	default:
+		switch typ := glueschema.Type(name); typ {
+		case glueschema.TypeString, glueschema.TypeBool, glueschema.TypeTimestamp,
+			glueschema.TypeTinyInt, glueschema.TypeSmallInt, glueschema.TypeInt, glueschema.TypeBigInt,
+			glueschema.TypeFloat, glueschema.TypeDouble:
+			return &parquetNode{
+				kind: parquetScalar,
+				typ:  typ,
+			}, nil
+		default:
+			return nil, errors.Errorf("unsupported type %q in Glue type %q", name, p.input)
+		}
+	}
+}
+
+func (p *glueTypeParser) readName() string {
+	start := p.pos
+	for p.pos < len(p.input) && !strings.ContainsRune("<>,:", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *glueTypeParser) peek() byte {
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+func (p *glueTypeParser) expect(c byte) error {
+	if p.peek() != c {
+		return errors.Errorf("expected %q at %d in Glue type %q", c, p.pos, p.input)
+	}
+	p.pos++
+	return nil
+}
+
+// parquetFile is an in-memory source.ParquetFile.
+// Parquet writers only append to the file so other file operations are not supported.
+type parquetFile struct {
+	bytes.Buffer
+}
+
+var _ source.ParquetFile = (*parquetFile)(nil)
+
+func (*parquetFile) Seek(_ int64, _ int) (int64, error) {
+	return 0, errors.New("seek not supported")
+}
+
+func (*parquetFile) Close() error {
+	return nil
+}
+
+func (*parquetFile) Open(_ string) (source.ParquetFile, error) {
+	return nil, errors.New("open not supported")
+}
+
+func (*parquetFile) Create(_ string) (source.ParquetFile, error) {
+	return nil, errors.New("create not supported")
+}
@@ -0,0 +1,322 @@
+package destinations
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue/glueschema"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog/null"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+type parquetTestEvent struct {
+	Time   time.Time         `json:"time" tcodec:"rfc3339" event_time:"true" description:"time"`
+	Name   null.String       `json:"name" description:"name"`
+	Count  null.Int64        `json:"count" description:"count"`
+	Tags   []string          `json:"tags" description:"tags"`
+	Labels map[string]string `json:"labels" description:"labels"`
+	Nested *struct {
+		Value null.Float64 `json:"Value" description:"value"`
+	} `json:"nested" description:"nested"`
+	Raw pantherlog.RawMessage `json:"raw" description:"raw"`
+}
+
+var parquetTestEntry = logtypes.MustBuild(logtypes.Config{
+	Name:         "Parquet.Test",
+	Description:  "Parquet test log type",
+	ReferenceURL: "-",
+	Schema:       pantherlog.MustBuildEventSchema(&parquetTestEvent{}),
+	NewParser:    pantherlog.FactoryFunc(nil),
+})
+
+func TestParseGlueType(t *testing.T) {
+	for _, typ := range []string{
+		"string",
+		"bigint",
+		"array<string>",
+		"map<string,array<int>>",
+		"struct<foo:string,bar:array<struct<baz:timestamp>>>",
+	} {
+		node, err := parseGlueType(typ)
+		require.NoError(t, err, typ)
+		require.NotNil(t, node, typ)
+	}
+	node, err := parseGlueType("struct<Foo:string,bar:map<string,double>>")
+	require.NoError(t, err)
+	require.Equal(t, parquetStruct, node.kind)
+	require.Len(t, node.fields, 2)
+	require.Equal(t, "foo", node.fields[0].name, "field names are lower case")
+	require.Equal(t, parquetMap, node.fields[1].node.kind)
+	require.Equal(t, glueschema.TypeDouble, node.fields[1].node.elem.typ)
+
+	for _, typ := range []string{
+		"",
+		"varchar",
+		"array<string",
+		"array<string>>",
+		"map<array<string>,string>",
+		"struct<>",
+		"struct<foo>",
+	} {
+		_, err := parseGlueType(typ)
+		require.Error(t, err, typ)
+	}
+}
+
+func TestParquetSchemaConvert(t *testing.T) {
+	columns, err := glueschema.InferColumns(parquetTestEntry.Schema())
+	require.NoError(t, err)
+	schema, err := newParquetSchema(columns)
+	require.NoError(t, err)
+
+	tm := time.Date(2020, 1, 1, 0, 1, 1, 123000000, time.UTC)
+	events := []*parquetTestEvent{
+		{
+			Time:   tm,
+			Name:   null.FromString("foo"),
+			Count:  null.FromInt64(42),
+			Tags:   []string{"a", "b"},
+			Labels: map[string]string{"key": "value"},
+			Nested: &struct {
+				Value null.Float64 `json:"Value" description:"value"`
+			}{
+				Value: null.FromFloat64(1.5),
+			},
+			Raw: pantherlog.RawMessage(`{"foo":["bar"]}`),
+		},
+		{
+			Time: tm,
+		},
+	}
+	jsonAPI := common.ConfigForDataLakeWriters()
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	for _, event := range events {
+		result, err := resultBuilder.BuildResult(parquetTestEntry.String(), event)
+		require.NoError(t, err)
+		data, err := jsonAPI.Marshal(result)
+		require.NoError(t, err)
+		_, err = w.Write(append(data, '\n'))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	data, err := schema.convert(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "PAR1", string(data[:4]))
+
+	rows := readParquetRows(t, data)
+	require.Len(t, rows, 2)
+	row := rows[0]
+	require.Equal(t, "foo", row["name"])
+	require.Equal(t, float64(42), row["count"])
+	require.Equal(t, float64(tm.UnixNano()/int64(time.Millisecond)), row["time"])
+	require.Equal(t, "Parquet.Test", row["p_log_type"])
+	require.Equal(t, `{"foo":["bar"]}`, row["raw"])
+	require.Equal(t, map[string]interface{}{"value": 1.5}, row["nested"])
+	require.Nil(t, rows[1]["name"])
+	require.Nil(t, rows[1]["nested"])
+
+	_, err = schema.convert([]byte("not gzip"))
+	require.Error(t, err)
+}
+
+func TestSendParquetData(t *testing.T) {
+	t.Parallel()
+
+	destination := mockDestination()
+	destination.parquetLogTypes = []string{parquetTestEntry.String()}
+	destination.resolver = logtypes.LocalResolver(parquetTestEntry)
+
+	result, err := resultBuilder.BuildResult(parquetTestEntry.String(), &parquetTestEvent{
+		Time: (time.Time)(refTime),
+		Name: null.FromString("foo"),
+	})
+	require.NoError(t, err)
+	eventChannel := make(chan *parsers.Result, 1)
+	eventChannel <- result
+	close(eventChannel)
+
+	destination.mockS3Uploader.On("Upload", mock.Anything, mock.Anything).Return(&s3manager.UploadOutput{}, nil).Twice()
+	destination.mockSns.On("Publish", mock.Anything).Return(&sns.PublishOutput{}, nil).Twice()
+
+	require.NoError(t, runDestination(destination, eventChannel))
+
+	destination.mockS3Uploader.AssertExpectations(t)
+	destination.mockSns.AssertExpectations(t)
+
+	// The Parquet file goes to the table partition
+	uploadInput := destination.mockS3Uploader.Calls[0].Arguments.Get(0).(*s3manager.UploadInput)
+	require.True(t, strings.HasPrefix(*uploadInput.Key, "logs/parquet_test/year=2020/month=01/day=01/hour=00/"))
+	require.True(t, strings.HasSuffix(*uploadInput.Key, parquetObjectExtension))
+	data, err := ioutil.ReadAll(uploadInput.Body)
+	require.NoError(t, err)
+	rows := readParquetRows(t, data)
+	require.Len(t, rows, 1)
+	require.Equal(t, "foo", rows[0]["name"])
+	publishInput := destination.mockSns.Calls[0].Arguments.Get(0).(*sns.PublishInput)
+	require.Equal(t, "parquet", aws.StringValue(publishInput.MessageAttributes["format"].StringValue))
+
+	// The JSON copy is staged for real-time analysis
+	uploadInput = destination.mockS3Uploader.Calls[1].Arguments.Get(0).(*s3manager.UploadInput)
+	require.True(t, strings.HasPrefix(*uploadInput.Key, "staging/logs/parquet_test/year=2020/month=01/day=01/hour=00/"))
+	require.True(t, strings.HasSuffix(*uploadInput.Key, jsonObjectExtension))
+	publishInput = destination.mockSns.Calls[1].Arguments.Get(0).(*sns.PublishInput)
+	require.NotContains(t, publishInput.MessageAttributes, "format")
+}
+
+func TestSendParquetDataToJSONPartition(t *testing.T) {
+	t.Parallel()
+
+	destination := mockDestination()
+	destination.parquetLogTypes = []string{parquetTestEntry.String()}
+	destination.resolver = logtypes.LocalResolver(parquetTestEntry)
+	glueClient := &testutils.GlueMock{}
+	destination.glueClient = glueClient
+
+	result, err := resultBuilder.BuildResult(parquetTestEntry.String(), &parquetTestEvent{
+		Time: (time.Time)(refTime),
+		Name: null.FromString("foo"),
+	})
+	require.NoError(t, err)
+	eventChannel := make(chan *parsers.Result, 1)
+	eventChannel <- result
+	close(eventChannel)
+
+	// The partition of the hour was created before the log type was switched to Parquet
+	jsonPartition := &glue.GetPartitionOutput{
+		Partition: &glue.Partition{
+			StorageDescriptor: &glue.StorageDescriptor{
+				SerdeInfo: &glue.SerDeInfo{
+					SerializationLibrary: aws.String("org.openx.data.jsonserde.JsonSerDe"),
+				},
+			},
+		},
+	}
+	glueClient.On("GetPartition", mock.Anything).Return(jsonPartition, nil).Once()
+	destination.mockS3Uploader.On("Upload", mock.Anything, mock.Anything).Return(&s3manager.UploadOutput{}, nil).Once()
+	destination.mockSns.On("Publish", mock.Anything).Return(&sns.PublishOutput{}, nil).Once()
+
+	require.NoError(t, runDestination(destination, eventChannel))
+
+	glueClient.AssertExpectations(t)
+	destination.mockS3Uploader.AssertExpectations(t)
+	destination.mockSns.AssertExpectations(t)
+
+	// The hour keeps its JSON format so the partition is not mixed
+	getPartitionInput := glueClient.Calls[0].Arguments.Get(0).(*glue.GetPartitionInput)
+	require.Equal(t, "parquet_test", aws.StringValue(getPartitionInput.TableName))
+	uploadInput := destination.mockS3Uploader.Calls[0].Arguments.Get(0).(*s3manager.UploadInput)
+	require.True(t, strings.HasPrefix(*uploadInput.Key, "logs/parquet_test/year=2020/month=01/day=01/hour=00/"))
+	require.True(t, strings.HasSuffix(*uploadInput.Key, jsonObjectExtension))
+	publishInput := destination.mockSns.Calls[0].Arguments.Get(0).(*sns.PublishInput)
+	require.NotContains(t, publishInput.MessageAttributes, "format")
+}
+
+func TestParquetBufferMaxSize(t *testing.T) {
+	t.Parallel()
+
+	destination := mockDestination()
+	destination.parquetLogTypes = []string{parquetTestEntry.String()}
+	destination.maxParquetSize = 1
+	bs := destination.newS3EventBufferSet()
+
+	result, err := resultBuilder.BuildResult(parquetTestEntry.String(), &parquetTestEvent{
+		Time: (time.Time)(refTime),
+		Name: null.FromString("foo"),
+	})
+	require.NoError(t, err)
+	sendBuffers, err := bs.writeEvent(result)
+	require.NoError(t, err)
+	require.Len(t, sendBuffers, 1)
+	require.Equal(t, 1, sendBuffers[0].maxSize)
+}
+
+// readParquetRows reads all rows of a Parquet file as JSON objects
+func readParquetRows(t *testing.T, data []byte) []map[string]interface{} {
+	t.Helper()
+	r, err := reader.NewParquetReader(&parquetTestFile{Reader: bytes.NewReader(data)}, nil, 1)
+	require.NoError(t, err)
+	defer r.ReadStop()
+	values, err := r.ReadByNumber(int(r.GetNumRows()))
+	require.NoError(t, err)
+	rows := make([]map[string]interface{}, len(values))
+	for i, v := range values {
+		rowJSON, err := json.Marshal(v)
+		require.NoError(t, err)
+		var row map[string]interface{}
+		require.NoError(t, json.Unmarshal(rowJSON, &row))
+		rows[i] = lowerCaseKeys(row).(map[string]interface{})
+	}
+	return rows
+}
+
+// parquet-go uses capitalized field names for the values it reads
+func lowerCaseKeys(value interface{}) interface{} {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	out := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		out[strings.ToLower(k)] = lowerCaseKeys(v)
+	}
+	return out
+}
+
+type parquetTestFile struct {
+	*bytes.Reader
+}
+
+func (f *parquetTestFile) Write(_ []byte) (int, error) {
+	panic("not implemented")
+}
+
+func (f *parquetTestFile) Close() error {
+	return nil
+}
+
+func (f *parquetTestFile) Open(_ string) (source.ParquetFile, error) {
+	r := *f.Reader
+	return &parquetTestFile{Reader: &r}, nil
+}
+
+func (f *parquetTestFile) Create(_ string) (source.ParquetFile, error) {
+	panic("not implemented")
+}
@@ -21,12 +21,14 @@ package destinations
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"fmt"
 	"path"
 	"runtime"
 	"sync"
 	"time"
 
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
 	"github.com/aws/aws-sdk-go/service/sns"
@@ -37,17 +39,22 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue/glueschema"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sources"
 	"github.com/panther-labs/panther/internal/log_analysis/notify"
 	"github.com/panther-labs/panther/internal/log_analysis/pantherdb"
 	pq "github.com/panther-labs/panther/pkg/priorityq"
+	"github.com/panther-labs/panther/pkg/stringset"
 )
 
 const (
 	uploaderBufferMaxSizeBytes = 50 * 1024 * 1024
 	uploaderPartSize           = 5 * 1024 * 1024
+	// Parquet files are built in memory from the JSON buffer so Parquet buffers are flushed earlier
+	parquetBufferMaxSizeBytes = 10 * 1024 * 1024
 
 	numberConcurrentUploads = 8 // how many uploaders are run concurrently
 
@@ -59,6 +66,9 @@ const (
 
 	// maximum number of buffers in memory (if exceeded buffers are flushed)
 	maxBuffers = 256
+
+	jsonObjectExtension    = ".json.gz"
+	parquetObjectExtension = ".parquet"
 )
 
 var (
@@ -73,20 +83,28 @@ func init() {
 	memUsedAtStartupMB = (int)(memStats.Sys/(1024*1024)) + 1
 }
 
-func CreateS3Destination(jsonAPI jsoniter.API) Destination {
+// CreateS3Destination creates a destination that stores events in the processed data bucket.
+// Events of log types in common.Config.ParquetLogTypes are stored as Parquet files, the resolver is used to look up
+// their table schema.
+// Changing the format of a log type takes effect on the next hourly partition, see storageFormat().
+func CreateS3Destination(jsonAPI jsoniter.API, resolver logtypes.Resolver) Destination {
 	if jsonAPI == nil {
 		jsonAPI = jsoniter.ConfigDefault
 	}
 	return &S3Destination{
 		s3Uploader:          s3manager.NewUploaderWithClient(common.S3Client),
 		snsClient:           common.SnsClient,
+		glueClient:          common.GlueClient,
 		s3Bucket:            common.Config.ProcessedDataBucket,
 		snsTopicArn:         common.Config.SnsTopicARN,
 		maxBufferedMemBytes: maxS3BufferMemUsageBytes(common.Config.AwsLambdaFunctionMemorySize),
 		maxBufferSize:       uploaderBufferMaxSizeBytes,
+		maxParquetSize:      parquetBufferMaxSizeBytes,
 		maxDuration:         maxDuration,
 		maxBuffers:          maxBuffers,
 		jsonAPI:             jsonAPI,
+		parquetLogTypes:     common.Config.ParquetLogTypes,
+		resolver:            resolver,
 	}
 }
 
@@ -110,6 +128,8 @@ func maxS3BufferMemUsageBytes(lambdaSizeMB int) uint64 {
 type S3Destination struct {
 	s3Uploader s3manageriface.UploaderAPI
 	snsClient  snsiface.SNSAPI
+	// glueClient is used to look up the storage format of existing partitions, lookups are skipped if nil
+	glueClient glueiface.GlueAPI
 	// s3Bucket is the s3Bucket where the data will be stored
 	s3Bucket string
 	// snsTopic is the SNS Topic ARN where we will send the notification
@@ -118,9 +138,25 @@ type S3Destination struct {
 	// thresholds for ejection
 	maxBufferedMemBytes uint64 // max will hold in buffers before ejection
 	maxBufferSize       int
+	maxParquetSize      int // max size of a buffer of a log type stored as Parquet
 	maxDuration         time.Duration
 	maxBuffers          int
 	jsonAPI             jsoniter.API
+	// log types stored as Parquet
+	parquetLogTypes []string
+	resolver        logtypes.Resolver
+	// cache of Parquet schemas, shared by the upload goroutines
+	parquetSchemas   map[string]*parquetSchema
+	parquetSchemasMu sync.Mutex
+	// cache of the storage format of existing partitions, shared by the upload goroutines
+	partitionFormats   map[partitionKey]awsglue.StorageFormat
+	partitionFormatsMu sync.Mutex
+}
+
+// partitionKey identifies the hourly partition of a log type
+type partitionKey struct {
+	logType string
+	hour    time.Time
 }
 
 // SendEvents stores events in S3.
@@ -220,37 +256,62 @@ func (d *S3Destination) sendData(buffer *s3EventBuffer, errChan chan error) {
 		return
 	}
 
-	var (
-		err           error
-		contentLength int64
-		key           string
-	)
-
-	operation := common.OpLogManager.Start("sendData", common.OpLogS3ServiceDim)
-	defer func() {
-		operation.Stop()
-		operation.Log(err,
-			// s3 dim info
-			zap.Int64("contentLength", contentLength),
-			zap.String("bucket", d.s3Bucket),
-			zap.String("key", key))
-	}()
+	payload, err := buffer.read()
+	if err != nil {
+		errChan <- err
+		return
+	}
 
-	key = getS3ObjectKey(buffer)
+	format, err := d.storageFormat(buffer)
 	if err != nil {
 		errChan <- err
 		return
 	}
+	if format == awsglue.StorageFormatJSON {
+		key := getS3ObjectKey(buffer, jsonObjectExtension)
+		if err := d.putObject(key, payload, buffer.logType, awsglue.StorageFormatJSON); err != nil {
+			errChan <- err
+		}
+		return
+	}
 
-	payload, err := buffer.read()
+	schema, err := d.parquetSchema(buffer.logType)
 	if err != nil {
 		errChan <- err
 		return
 	}
+	// Parquet data are stored in the table and the JSON data are staged for the rules engine
+	data, err := schema.convert(payload)
+	if err != nil {
+		errChan <- errors.WithMessagef(err, "failed to convert %s events to Parquet", buffer.logType)
+		return
+	}
+	key := getS3ObjectKey(buffer, parquetObjectExtension)
+	if err := d.putObject(key, data, buffer.logType, awsglue.StorageFormatParquet); err != nil {
+		errChan <- err
+		return
+	}
+	stagingKey := getS3StagingObjectKey(buffer)
+	if err := d.putObject(stagingKey, payload, buffer.logType, awsglue.StorageFormatJSON); err != nil {
+		errChan <- err
+	}
+}
 
-	contentLength = int64(len(payload)) // for logging above
+// putObject uploads data to S3 and sends notification to SNS
+func (d *S3Destination) putObject(key string, payload []byte, logType string, format awsglue.StorageFormat) (err error) {
+	contentLength := int64(len(payload)) // for logging
 
-	if _, err := d.s3Uploader.Upload(&s3manager.UploadInput{
+	operation := common.OpLogManager.Start("sendData", common.OpLogS3ServiceDim)
+	defer func() {
+		operation.Stop()
+		operation.Log(err,
+			// s3 dim info
+			zap.Int64("contentLength", contentLength),
+			zap.String("bucket", d.s3Bucket),
+			zap.String("key", key))
+	}()
+
+	if _, err = d.s3Uploader.Upload(&s3manager.UploadInput{
 		Bucket: &d.s3Bucket,
 		Key:    &key,
 		Body:   bytes.NewReader(payload),
@@ -258,17 +319,15 @@ func (d *S3Destination) sendData(buffer *s3EventBuffer, errChan chan error) {
 		u.Concurrency = (len(payload) / uploaderPartSize) + 1 // if it evenly divides an extra won't matter
 		u.PartSize = uploaderPartSize
 	}); err != nil {
-		errChan <- errors.Wrap(err, "S3Upload")
-		return
+		err = errors.Wrap(err, "S3Upload")
+		return err
 	}
 
-	err = d.sendSNSNotification(key, buffer) // if send fails we fail whole operation
-	if err != nil {
-		errChan <- err
-	}
+	err = d.sendSNSNotification(key, logType, len(payload), format) // if send fails we fail whole operation
+	return err
 }
 
-func (d *S3Destination) sendSNSNotification(key string, buffer *s3EventBuffer) error {
+func (d *S3Destination) sendSNSNotification(key, logType string, nbytes int, format awsglue.StorageFormat) error {
 	var err error
 	operation := common.OpLogManager.Start("sendSNSNotification", common.OpLogSNSServiceDim)
 	defer func() {
@@ -277,7 +336,7 @@ func (d *S3Destination) sendSNSNotification(key string, buffer *s3EventBuffer) e
 			zap.String("topicArn", d.snsTopicArn))
 	}()
 
-	s3Notification := notify.NewS3ObjectPutNotification(d.s3Bucket, key, buffer.bytes)
+	s3Notification := notify.NewS3ObjectPutNotification(d.s3Bucket, key, nbytes)
 
 	marshalledNotification, err := jsoniter.MarshalToString(s3Notification)
 	if err != nil {
@@ -285,11 +344,15 @@ func (d *S3Destination) sendSNSNotification(key string, buffer *s3EventBuffer) e
 		return err
 	}
 
-	dataType := pantherdb.GetDataType(buffer.logType)
+	dataType := pantherdb.GetDataType(logType)
+	attributes := notify.NewLogAnalysisSNSMessageAttributes(dataType, logType)
+	if format == awsglue.StorageFormatParquet {
+		attributes = notify.NewLogAnalysisParquetSNSMessageAttributes(dataType, logType)
+	}
 	input := &sns.PublishInput{
 		TopicArn:          &d.snsTopicArn,
 		Message:           &marshalledNotification,
-		MessageAttributes: notify.NewLogAnalysisSNSMessageAttributes(dataType, buffer.logType),
+		MessageAttributes: attributes,
 	}
 	if _, err = d.snsClient.Publish(input); err != nil {
 		err = errors.Wrap(err, "failed to send notification to topic")
@@ -299,17 +362,116 @@ func (d *S3Destination) sendSNSNotification(key string, buffer *s3EventBuffer) e
 	return err
 }
 
+// storageFormat returns the format used to store the data of a buffer.
+// Glue tables have a single format per partition so once the partition of an hour exists its format is kept, even
+// if the log type was added to or removed from the Parquet log types since. New partitions use the configured format.
+func (d *S3Destination) storageFormat(buffer *s3EventBuffer) (awsglue.StorageFormat, error) {
+	format := awsglue.StorageFormatJSON
+	if stringset.Contains(d.parquetLogTypes, buffer.logType) {
+		format = awsglue.StorageFormatParquet
+	}
+	if d.glueClient == nil {
+		return format, nil
+	}
+
+	key := partitionKey{
+		logType: buffer.logType,
+		hour:    buffer.hour,
+	}
+	d.partitionFormatsMu.Lock()
+	existing, ok := d.partitionFormats[key]
+	d.partitionFormatsMu.Unlock()
+	if ok {
+		return existing, nil
+	}
+
+	typ := pantherdb.GetDataType(buffer.logType)
+	db := pantherdb.DatabaseName(typ)
+	table := pantherdb.TableName(buffer.logType)
+	tableMeta := awsglue.NewGlueTableMetadata(db, table, "", awsglue.GlueTableHourly, nil)
+	partition, err := tableMeta.GetPartition(d.glueClient, buffer.hour)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get partition of %s.%s", db, table)
+	}
+	if partition == nil {
+		// Do not cache missing partitions, the first file written will have the partition created using the table format
+		return format, nil
+	}
+	existing = awsglue.StorageFormatJSON
+	if awsglue.IsParquetPartition(partition.Partition.StorageDescriptor) {
+		existing = awsglue.StorageFormatParquet
+	}
+
+	d.partitionFormatsMu.Lock()
+	defer d.partitionFormatsMu.Unlock()
+	// Partitions are only looked up for the hours being written, resetting the cache keeps it small
+	if len(d.partitionFormats) >= maxBuffers {
+		d.partitionFormats = nil
+	}
+	if d.partitionFormats == nil {
+		d.partitionFormats = make(map[partitionKey]awsglue.StorageFormat)
+	}
+	d.partitionFormats[key] = existing
+	return existing, nil
+}
+
+// parquetSchema returns the Parquet schema for a log type
+func (d *S3Destination) parquetSchema(logType string) (*parquetSchema, error) {
+	d.parquetSchemasMu.Lock()
+	defer d.parquetSchemasMu.Unlock()
+	if schema, ok := d.parquetSchemas[logType]; ok {
+		return schema, nil
+	}
+	if d.resolver == nil {
+		return nil, errors.Errorf("cannot resolve Parquet schema for %s", logType)
+	}
+	entry, err := d.resolver.Resolve(context.Background(), logType)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to resolve log type %s", logType)
+	}
+	if entry == nil {
+		return nil, errors.Errorf("unresolved log type %s", logType)
+	}
+	// Use the same columns as the Glue table of the log type
+	columns, err := glueschema.InferColumns(entry.Schema())
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to infer columns for %s", logType)
+	}
+	schema, err := newParquetSchema(columns)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to build Parquet schema for %s", logType)
+	}
+	if d.parquetSchemas == nil {
+		d.parquetSchemas = make(map[string]*parquetSchema)
+	}
+	d.parquetSchemas[logType] = schema
+	return schema, nil
+}
+
 // getS3ObjectKey builds the S3 object key for storing a partition file of processed logs.
-func getS3ObjectKey(buf *s3EventBuffer) string {
+func getS3ObjectKey(buf *s3EventBuffer, ext string) string {
 	typ := pantherdb.GetDataType(buf.logType)
 	db := pantherdb.DatabaseName(typ)
 	table := pantherdb.TableName(buf.logType)
 	partitionPrefix := awsglue.PartitionPrefix(db, table, awsglue.GlueTableHourly, buf.hour)
-	filename := fmt.Sprintf("%s-%s.json.gz",
+	return path.Join(partitionPrefix, getS3ObjectFilename(buf, ext))
+}
+
+// getS3StagingObjectKey builds the S3 object key for staging the JSON data of log types stored as Parquet.
+func getS3StagingObjectKey(buf *s3EventBuffer) string {
+	typ := pantherdb.GetDataType(buf.logType)
+	db := pantherdb.DatabaseName(typ)
+	table := pantherdb.TableName(buf.logType)
+	stagingPrefix := awsglue.StagingPrefix(db, table) + awsglue.GlueTableHourly.PartitionPathS3(buf.hour)
+	return path.Join(stagingPrefix, getS3ObjectFilename(buf, jsonObjectExtension))
+}
+
+func getS3ObjectFilename(buf *s3EventBuffer, ext string) string {
+	return fmt.Sprintf("%s-%s%s",
 		buf.hour.Format(S3ObjectTimestampLayout),
 		uuid.New(),
+		ext,
 	)
-	return path.Join(partitionPrefix, filename)
 }
 
 // s3BufferSet is a group of buffers associated with hour time bins, pointing to maps logtype->s3EventBuffer
@@ -322,6 +484,8 @@ type s3EventBufferSet struct {
 	stream                  *jsoniter.Stream
 	maxBuffers              int
 	maxBufferSize           int
+	maxParquetSize          int
+	parquetLogTypes         []string
 	maxTotalSize            uint64
 }
 
@@ -330,11 +494,13 @@ func (d *S3Destination) newS3EventBufferSet() *s3EventBufferSet {
 	// Stream will be a buffered stream
 	stream := jsoniter.NewStream(d.jsonAPI, nil, initialBufferSize)
 	return &s3EventBufferSet{
-		stream:        stream,
-		set:           make(map[time.Time]map[string]*s3EventBuffer),
-		maxBuffers:    d.maxBuffers,
-		maxBufferSize: d.maxBufferSize,
-		maxTotalSize:  d.maxBufferedMemBytes,
+		stream:          stream,
+		set:             make(map[time.Time]map[string]*s3EventBuffer),
+		maxBuffers:      d.maxBuffers,
+		maxBufferSize:   d.maxBufferSize,
+		maxParquetSize:  d.maxParquetSize,
+		parquetLogTypes: d.parquetLogTypes,
+		maxTotalSize:    d.maxBufferedMemBytes,
 	}
 }
 
@@ -366,7 +532,7 @@ func (bs *s3EventBufferSet) writeEvent(event *parsers.Result) (sendBuffers []*s3
 	bs.sizePriorityQueue.UpdatePriority(buf, float64(buf.bytes/uploaderPartSize)) // in # parts to reduce cost of update
 
 	// Check if buffer is bigger than threshold for a single buffer
-	if buf.bytes >= bs.maxBufferSize {
+	if buf.bytes >= buf.maxSize {
 		bs.removeBuffer(buf) // bufferSet is not thread safe, do this here
 		sendBuffers = append(sendBuffers, buf)
 	}
@@ -421,6 +587,10 @@ func (bs *s3EventBufferSet) getBuffer(event *parsers.Result) *s3EventBuffer {
 	buffer, ok := logTypeToBuffer[logType]
 	if !ok {
 		buffer = newS3EventBuffer(logType, hour)
+		buffer.maxSize = bs.maxBufferSize
+		if bs.maxParquetSize > 0 && stringset.Contains(bs.parquetLogTypes, logType) {
+			buffer.maxSize = bs.maxParquetSize
+		}
 		logTypeToBuffer[logType] = buffer
 		bs.numBuffers++
 		bs.sizePriorityQueue.Insert(buffer, 0.0)
@@ -496,6 +666,7 @@ type s3EventBuffer struct {
 	writer     *gzip.Writer
 	bytes      int
 	events     int
+	maxSize    int       // flush when bytes reaches this size
 	hour       time.Time // the event time bin
 	createTime time.Time // used to expire buffer
 }
@@ -0,0 +1,143 @@
+package geoip
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"net"
+	"strconv"
+
+	maxminddb "github.com/oschwald/maxminddb-golang"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// Resolver resolves the ip addresses collected in p_any_ip_addresses to their countries and autonomous systems.
+// The values are added to p_any_ip_countries and p_any_ip_asns.
+type Resolver struct {
+	countries *maxminddb.Reader
+	asns      *maxminddb.Reader
+}
+
+var _ pantherlog.ValueResolver = (*Resolver)(nil)
+
+// Config configures the databases of a Resolver
+type Config struct {
+	// Path to a MMDB database with country records (ie GeoLite2-Country or GeoLite2-City)
+	CountryDatabase string
+	// Path to a MMDB database with autonomous system records (ie GeoLite2-ASN)
+	ASNDatabase string
+}
+
+// Load creates a Resolver from the databases in config.
+// It returns nil if no database is configured.
+func Load(config Config) (*Resolver, error) {
+	if config.CountryDatabase == "" && config.ASNDatabase == "" {
+		return nil, nil
+	}
+	r := Resolver{}
+	if path := config.CountryDatabase; path != "" {
+		db, err := maxminddb.Open(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open database %q", path)
+		}
+		r.countries = db
+	}
+	if path := config.ASNDatabase; path != "" {
+		db, err := maxminddb.Open(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open database %q", path)
+		}
+		r.asns = db
+	}
+	return &r, nil
+}
+
+// NewResolver creates a Resolver using a country and an ASN database.
+// Any of the databases can be nil.
+func NewResolver(countries, asns *maxminddb.Reader) *Resolver {
+	return &Resolver{
+		countries: countries,
+		asns:      asns,
+	}
+}
+
+// ResolveValues implements pantherlog.ValueResolver interface
+func (r *Resolver) ResolveValues(values *pantherlog.ValueBuffer) {
+	if values == nil {
+		return
+	}
+	for _, addr := range values.Get(pantherlog.FieldIPAddress) {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			continue
+		}
+		if country := r.Country(ip); country != "" {
+			values.WriteValues(pantherlog.FieldIPCountry, country)
+		}
+		if asn := r.ASN(ip); asn != "" {
+			values.WriteValues(pantherlog.FieldIPASN, asn)
+		}
+	}
+}
+
+// countryRecord is the part of the records of GeoIP2/GeoLite2 country and city databases used by the resolver
+type countryRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+}
+
+// asnRecord is the part of the records of GeoIP2/GeoLite2 ASN databases used by the resolver
+type asnRecord struct {
+	AutonomousSystemNumber uint `maxminddb:"autonomous_system_number"`
+}
+
+// Country returns the ISO code of the country of an ip address.
+// It falls back to the country the address is registered in if its location is unknown.
+// Addresses that are not found in the database and lookup errors result in an empty string.
+func (r *Resolver) Country(ip net.IP) string {
+	if r.countries == nil {
+		return ""
+	}
+	record := countryRecord{}
+	if err := r.countries.Lookup(ip, &record); err != nil {
+		return ""
+	}
+	if code := record.Country.ISOCode; code != "" {
+		return code
+	}
+	return record.RegisteredCountry.ISOCode
+}
+
+// ASN returns the autonomous system number of an ip address.
+// Addresses that are not found in the database and lookup errors result in an empty string.
+func (r *Resolver) ASN(ip net.IP) string {
+	if r.asns == nil {
+		return ""
+	}
+	record := asnRecord{}
+	if err := r.asns.Lookup(ip, &record); err != nil || record.AutonomousSystemNumber == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(record.AutonomousSystemNumber), 10)
+}
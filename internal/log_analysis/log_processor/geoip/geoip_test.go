@@ -0,0 +1,359 @@
+package geoip
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	maxminddb "github.com/oschwald/maxminddb-golang"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+func TestLoad(t *testing.T) {
+	resolver, err := Load(Config{})
+	require.NoError(t, err)
+	require.Nil(t, resolver)
+
+	dir := t.TempDir()
+	countryDB := filepath.Join(dir, "country.mmdb")
+	require.NoError(t, ioutil.WriteFile(countryDB, buildTestDB(t, 6, 28, testCountryRecords), 0644))
+	asnDB := filepath.Join(dir, "asn.mmdb")
+	require.NoError(t, ioutil.WriteFile(asnDB, buildTestDB(t, 4, 24, testASNRecords), 0644))
+
+	resolver, err = Load(Config{
+		CountryDatabase: countryDB,
+		ASNDatabase:     asnDB,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "GB", resolver.Country(net.ParseIP("81.2.69.142")))
+	require.Equal(t, "US", resolver.Country(net.ParseIP("2001:db8::1")))
+	require.Equal(t, "", resolver.Country(net.ParseIP("1.1.1.1")))
+	require.Equal(t, "13335", resolver.ASN(net.ParseIP("1.1.1.1")))
+	require.Equal(t, "", resolver.ASN(net.ParseIP("81.2.69.142")))
+
+	_, err = Load(Config{
+		ASNDatabase: filepath.Join(dir, "missing.mmdb"),
+	})
+	require.Error(t, err)
+}
+
+func TestResolver(t *testing.T) {
+	for _, recordSize := range []int{24, 28, 32} {
+		countries, err := maxminddb.FromBytes(buildTestDB(t, 6, recordSize, testCountryRecords))
+		require.NoError(t, err)
+		asns, err := maxminddb.FromBytes(buildTestDB(t, 4, recordSize, testASNRecords))
+		require.NoError(t, err)
+		resolver := NewResolver(countries, asns)
+
+		require.Equal(t, "GB", resolver.Country(net.ParseIP("81.2.69.142")), "record size %d", recordSize)
+		// Values are decoded through pointers
+		require.Equal(t, "GB", resolver.Country(net.ParseIP("81.2.70.1")), "record size %d", recordSize)
+		// The registered country is used if the location is unknown
+		require.Equal(t, "US", resolver.Country(net.ParseIP("2001:db8::1")), "record size %d", recordSize)
+		require.Equal(t, "", resolver.Country(net.ParseIP("10.0.0.1")), "record size %d", recordSize)
+		require.Equal(t, "15169", resolver.ASN(net.ParseIP("8.8.8.8")), "record size %d", recordSize)
+		// IPv6 addresses are not found in IPv4 databases
+		require.Equal(t, "", resolver.ASN(net.ParseIP("2001:db8::1")), "record size %d", recordSize)
+	}
+
+	// Databases are optional
+	resolver := NewResolver(nil, nil)
+	require.Equal(t, "", resolver.Country(net.ParseIP("81.2.69.142")))
+	require.Equal(t, "", resolver.ASN(net.ParseIP("8.8.8.8")))
+}
+
+func TestResolveValues(t *testing.T) {
+	countries, err := maxminddb.FromBytes(buildTestDB(t, 6, 24, testCountryRecords))
+	require.NoError(t, err)
+	asns, err := maxminddb.FromBytes(buildTestDB(t, 4, 24, testASNRecords))
+	require.NoError(t, err)
+
+	type T struct {
+		SourceIP      string `json:"src_ip" panther:"ip"`
+		DestinationIP string `json:"dst_ip" panther:"ip"`
+		Host          string `json:"host" panther:"hostname"`
+	}
+	result := &pantherlog.Result{
+		CoreFields: pantherlog.CoreFields{
+			PantherLogType: "Foo.Bar",
+			PantherRowID:   "id",
+		},
+		Event: &T{
+			SourceIP:      "81.2.69.142",
+			DestinationIP: "8.8.8.8",
+			Host:          "1.1.1.1",
+		},
+	}
+	api := common.ConfigForDataLakeWriters()
+	api.RegisterExtension(pantherlog.NewValueResolverExtension(NewResolver(countries, asns)))
+	data, err := api.Marshal(result)
+	require.NoError(t, err)
+	require.JSONEq(t, `["GB"]`, gjson.GetBytes(data, "p_any_ip_countries").Raw)
+	require.JSONEq(t, `["13335","15169"]`, gjson.GetBytes(data, "p_any_ip_asns").Raw)
+}
+
+// The marker that precedes the metadata section at the end of the file
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// The size of the zero-filled data section separator
+const dataSectionSeparatorSize = 16
+
+// Data section field types
+const (
+	typeExtended = iota
+	typePointer
+	typeString
+	typeDouble
+	typeBytes
+	typeUint16
+	typeUint32
+	typeMap
+	typeInt32
+	typeUint64
+	typeUint128
+	typeArray
+	typeContainer
+	typeEndMarker
+	typeBool
+	typeFloat
+)
+
+type testRecord struct {
+	CIDR  string
+	Value interface{}
+}
+
+// testPointer encodes a pointer to an offset in the data section
+type testPointer uint
+
+var testCountryRecords = []testRecord{
+	{
+		CIDR: "81.2.69.0/24",
+		Value: map[string]interface{}{
+			"country": map[string]interface{}{
+				"iso_code": "GB",
+				"names": map[string]interface{}{
+					"de": "Vereinigtes Königreich",
+					"en": "United Kingdom",
+				},
+			},
+			"location": map[string]interface{}{
+				"latitude":  51.4964,
+				"longitude": -0.1224,
+			},
+		},
+	},
+	{
+		// Points to the country of the previous record at offset 9 of the data section
+		CIDR: "81.2.70.0/23",
+		Value: map[string]interface{}{
+			"country": testPointer(9),
+		},
+	},
+	{
+		CIDR: "2001:db8::/32",
+		Value: map[string]interface{}{
+			"is_anycast": true,
+			"networks":   []interface{}{"2001:db8::/32"},
+			"registered_country": map[string]interface{}{
+				"geoname_id": uint32(6252001),
+				"iso_code":   "US",
+			},
+		},
+	},
+}
+
+var testASNRecords = []testRecord{
+	{
+		CIDR: "1.1.1.0/24",
+		Value: map[string]interface{}{
+			"autonomous_system_number":       uint32(13335),
+			"autonomous_system_organization": "CLOUDFLARENET",
+		},
+	},
+	{
+		CIDR: "8.8.8.0/24",
+		Value: map[string]interface{}{
+			"autonomous_system_number":       uint32(15169),
+			"autonomous_system_organization": "GOOGLE",
+		},
+	},
+}
+
+// buildTestDB writes a MMDB database with the records
+func buildTestDB(t *testing.T, ipVersion, recordSize int, records []testRecord) []byte {
+	t.Helper()
+	// Children are node indexes, -1 for empty records or -(2+i) for the i-th record
+	nodes := [][2]int{{-1, -1}}
+	data := testEncoder{}
+	offsets := make([]int, len(records))
+	for i, record := range records {
+		offsets[i] = data.Len()
+		data.encode(t, record.Value)
+
+		_, ipNet, err := net.ParseCIDR(record.CIDR)
+		require.NoError(t, err)
+		ip := []byte(ipNet.IP)
+		ones, _ := ipNet.Mask.Size()
+		if ipVersion == 6 && len(ip) == net.IPv4len {
+			ip = append(make([]byte, 12), ip...)
+			ones += 96
+		}
+		node := 0
+		for bit := 0; bit < ones; bit++ {
+			b := int(ip[bit/8]>>(7-bit%8)) & 1
+			if bit == ones-1 {
+				nodes[node][b] = -(2 + i)
+				break
+			}
+			next := nodes[node][b]
+			if next < 0 {
+				nodes = append(nodes, [2]int{-1, -1})
+				next = len(nodes) - 1
+				nodes[node][b] = next
+			}
+			node = next
+		}
+	}
+
+	nodeCount := len(nodes)
+	tree := bytes.Buffer{}
+	for _, node := range nodes {
+		var values [2]uint32
+		for i, child := range node {
+			switch {
+			case child >= 0:
+				values[i] = uint32(child)
+			case child == -1:
+				values[i] = uint32(nodeCount)
+			default:
+				values[i] = uint32(nodeCount + dataSectionSeparatorSize + offsets[-(child+2)])
+			}
+		}
+		left, right := values[0], values[1]
+		switch recordSize {
+		case 24:
+			tree.Write([]byte{byte(left >> 16), byte(left >> 8), byte(left)})
+			tree.Write([]byte{byte(right >> 16), byte(right >> 8), byte(right)})
+		case 28:
+			tree.Write([]byte{byte(left >> 16), byte(left >> 8), byte(left)})
+			tree.WriteByte(byte(left>>24)<<4 | byte(right>>24)&0x0F)
+			tree.Write([]byte{byte(right >> 16), byte(right >> 8), byte(right)})
+		default:
+			_ = binary.Write(&tree, binary.BigEndian, values)
+		}
+	}
+
+	meta := testEncoder{}
+	meta.encode(t, map[string]interface{}{
+		"binary_format_major_version": uint32(2),
+		"binary_format_minor_version": uint32(0),
+		"database_type":               "Test-" + map[int]string{4: "ASN", 6: "Country"}[ipVersion],
+		"ip_version":                  uint32(ipVersion),
+		"node_count":                  uint32(nodeCount),
+		"record_size":                 uint32(recordSize),
+	})
+
+	out := tree.Bytes()
+	out = append(out, make([]byte, dataSectionSeparatorSize)...)
+	out = append(out, data.Bytes()...)
+	out = append(out, metadataMarker...)
+	return append(out, meta.Bytes()...)
+}
+
+type testEncoder struct {
+	bytes.Buffer
+}
+
+func (e *testEncoder) encode(t *testing.T, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		e.control(typeString, len(v))
+		e.WriteString(v)
+	case uint32:
+		e.control(typeUint32, 4)
+		_ = binary.Write(e, binary.BigEndian, v)
+	case float64:
+		e.control(typeDouble, 8)
+		_ = binary.Write(e, binary.BigEndian, v)
+	case bool:
+		size := 0
+		if v {
+			size = 1
+		}
+		e.control(typeBool, size)
+	case *big.Int:
+		b := v.Bytes()
+		e.control(typeUint128, len(b))
+		e.Write(b)
+	case []interface{}:
+		e.control(typeArray, len(v))
+		for _, el := range v {
+			e.encode(t, el)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		e.control(typeMap, len(keys))
+		for _, key := range keys {
+			e.encode(t, key)
+			e.encode(t, v[key])
+		}
+	case testPointer:
+		require.Less(t, int(v), 2048)
+		e.WriteByte(typePointer<<5 | byte(v>>8))
+		e.WriteByte(byte(v))
+	default:
+		t.Fatalf("unsupported test value %v", value)
+	}
+}
+
+func (e *testEncoder) control(typ, size int) {
+	var ext []byte
+	switch {
+	case size < 29:
+	case size < 285:
+		ext = []byte{byte(size - 29)}
+		size = 29
+	default:
+		ext = []byte{byte((size - 285) >> 8), byte(size - 285)}
+		size = 30
+	}
+	if typ > typeMap {
+		e.WriteByte(byte(size))
+		e.WriteByte(byte(typ - typeMap))
+	} else {
+		e.WriteByte(byte(typ<<5 | size))
+	}
+	e.Write(ext)
+}
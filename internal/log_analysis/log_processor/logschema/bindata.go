@@ -3,24 +3,6 @@
 // schema.json
 package logschema
 
-/**
- * Panther is a Cloud-Native SIEM for the Modern Security Team.
- * Copyright (C) 2020 Panther Labs Inc
- *
- * This program is free software: you can redistribute it and/or modify
- * it under the terms of the GNU Affero General Public License as
- * published by the Free Software Foundation, either version 3 of the
- * License, or (at your option) any later version.
- *
- * This program is distributed in the hope that it will be useful,
- * but WITHOUT ANY WARRANTY; without even the implied warranty of
- * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
- * GNU Affero General Public License for more details.
- *
- * You should have received a copy of the GNU Affero General Public License
- * along with this program.  If not, see <https://www.gnu.org/licenses/>.
- */
-
 import (
 	"bytes"
 	"compress/gzip"
@@ -95,7 +77,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _schemaJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x5a\xdf\x73\x13\xb7\x13\x7f\xcf\x5f\xa1\x11\xf9\xbe\x80\x8d\xc3\x37\xa5\x1d\xf2\xd2\x81\x14\x06\x66\x60\xc8\x90\x02\x53\x12\x27\xa3\xdc\xed\xd9\xa2\x3a\xe9\x2a\xe9\xf2\x03\xc6\xff\x7b\xe7\x7e\xf9\x24\x9d\x74\x3e\xc7\x31\x9d\x52\x1e\x80\xb3\xa4\x5d\xed\xae\x3e\x9f\xd5\x4a\xe2\xeb\x0e\x42\x78\x57\x45\x73\x48\x09\x3e\x40\x78\xae\x75\x76\x30\x99\x7c\x56\x82\x8f\xab\xd6\x87\x42\xce\x26\xb1\x24\x89\x1e\xef\xfd\x32\xa9\xda\xee\xe1\x51\x21\xa7\xa9\x66\x50\x48\x1d\x11\xae\xe7\x20\x11\x13\x33\x54\xeb\x2a\x07\xec\xd2\xb8\x51\xaa\x0e\x26\x13\x99\xf3\xac\x1a\xf9\x90\x8a\x5a\x95\x9a\x30\x31\x53\x19\x44\x93\xcb\xbd\x5a\x48\x42\x52\x48\xdd\x9b\xc4\x90\x50\x4e\x35\x15\x5c\xd5\xa3\x8f\x33\x88\xaa\x51\x46\x1f\x3e\x40\x85\x1b\x08\x61\x63\x50\xd3\x56\x98\x79\x93\x95\x56\x8a\x8b\xcf\x10\xe9\x52\xbc\x6c\xcf\xa4\xc8\x40\x6a\x0a\xca\x18\x8d\x10\xbe\x04\xa9\xa8\xe0\x56\x23\x42\x38\x12\x5c\x69\x7c\x80\xf6\x96\x8d\x8b\x51\x2b\xb4\x0c\xa1\x25\xd3\x4c\xad\xb4\xa4\x7c\x86\x47\x66\x5f\x4a\xf9\x6b\xe0\x33\x3d\xc7\x07\x68\xdf\xea\xc9\x88\xd6\x20\x0b\x03\xf0\xd9\xc9\xd3\xf1\xa7\x69\xf1\x17\x19\x7f\xd9\x1b\x3f\x99\x3e\xd8\xc5\xde\xf9\x63\x50\x91\xa4\x99\xf6\x18\xee\x18\xe1\x15\x97\x90\x80\x04\x1e\xc1\xfb\x77\xaf\xd7\x71\x22\x11\x32\x25\x45\x54\x70\x2e\xa9\x5f\x75\x46\xa4\x02\xe9\x2a\x4d\xc9\xf5\x91\x19\xff\x47\x6e\x6c\x7a\x7a\x03\x0b\x57\xad\x92\xba\xec\x34\x22\x84\x05\x87\xb7\x05\xaa\x4e\x9c\x0e\xd4\x19\x5a\x0e\xf7\x63\xb0\xf2\xe4\xf0\xf8\xc3\x47\xaa\xe7\x2f\x81\xc4\x20\x71\x47\x7a\x31\xba\xb3\x29\x44\xae\x83\xb3\x38\x2d\xd3\x9d\x1e\x1b\x70\x42\x94\x4e\x89\x8e\xe6\xbe\xd0\xf4\x19\xf2\x82\x28\xfd\xa6\x14\xec\xd5\x2f\x61\x06\xd7\xeb\xea\x7e\x57\x08\xf9\x94\xef\xf8\xbe\x4d\x44\x25\x14\x58\xec\xae\x7d\x60\xae\x8a\xf4\x2f\x2a\x89\x00\x73\xba\x99\xa4\xee\x0a\x64\x0e\x64\x90\xf4\xa8\x07\x8b\x3e\xf2\x0e\x0e\xd2\x25\x61\x39\x94\xa9\x2c\x1c\x1d\xcb\x20\x12\xc7\xa5\x28\x61\x96\x4d\x09\x61\x0a\x76\x5c\xf1\xa5\x28\x96\xf0\x57\x4e\x25\x14\x89\xfa\x64\x99\xfa\x46\xcb\x20\x57\xc0\xaa\x87\x63\x2b\x9a\x9e\x14\x4b\xa4\x24\x37\x6d\x86\x4d\x29\x7f\xa5\x21\xb5\x18\x8c\x69\xdd\x62\x64\x5c\x7f\x04\x4a\x0b\xcc\x08\x2c\x2c\x5b\xda\x6e\xc3\x10\xc2\x98\xc3\xf3\xe1\x0b\xda\xb3\x92\x9c\xa4\xe0\x5b\xba\x50\x6a\xed\xac\x8e\x1d\xe8\xa0\x9e\x0b\x21\x18\x10\xde\xaf\xa8\x1e\x3c\x10\x47\xc5\xe8\xe3\xa8\x03\x23\x47\x67\x78\xfb\x58\xed\x67\x10\x91\x16\xb4\xca\x10\x8e\x6a\x55\x53\x1f\x13\x07\xb0\xd9\x43\x8a\x66\x7a\x1b\xa8\xed\x40\x03\x1c\xdd\x4d\x60\xc0\x94\x95\xcb\xce\x9c\x6b\x19\x5d\x81\x6d\x13\x0d\x25\xad\x36\x51\xa0\x22\xc2\x88\xdc\x44\x83\xa6\xa9\x1b\xf8\xb5\xe4\x25\x24\x43\xd6\x6d\x89\x56\x4f\x72\x71\xea\x0f\x0c\x3c\x4f\xad\xd5\xec\x56\x28\x5d\xa2\x3b\x29\x0a\x21\x5c\xd4\xba\xe6\x6f\xca\xad\xf1\x09\x13\xc4\x6a\x50\x29\x61\xcc\x19\x74\x41\x67\x6e\x4b\xcd\x64\xa3\xa9\x08\xa1\xd2\x24\xcd\xb0\x5d\x78\x61\x6f\x24\x0c\xd4\x6c\x50\xcb\x7a\x72\xc5\xb2\x90\x6d\x94\x6c\x6b\x8f\xed\xdf\x6a\x4a\xcb\x42\xfb\x4c\x0b\xf8\x6d\xf9\x5e\xc1\xc0\xeb\x3a\x30\x48\x81\xeb\x61\xbe\xf7\x64\xa4\x15\x8e\x37\xd3\xd8\x9e\x1b\x4c\xbd\x63\xd7\xfb\xca\xf8\x86\x4a\x15\xf8\x97\xa0\x6f\x81\x6d\xc2\xbe\xa1\x4c\x0b\xf2\xe9\x5a\xbe\x3b\x0e\xb7\xf9\x75\x5b\x6b\xdd\x77\xf0\xa1\x3c\xa6\x11\xd1\x42\x06\x8b\x3f\x37\x61\x78\x4b\x98\x1e\x84\x2c\x67\x30\xb7\xcd\xc5\x06\x11\x6b\x15\xde\x2a\x49\x52\x2b\xff\xc4\x22\x25\xd4\x4a\x53\x73\xa1\x74\xb5\x59\xb7\x6d\xb9\x64\xe6\x4f\x0e\xfa\x9c\xc4\xb1\x34\xdb\xd2\xf8\xb1\x95\x25\xe7\xe4\x91\xf3\xfb\xff\x8f\x7f\xb6\x12\xf1\x95\x3a\x27\x92\x77\x9a\xa2\x48\xe4\x5c\x9f\xd3\xd8\xed\xa1\x5c\x69\xc2\x23\xf0\x74\x69\x62\x65\x7d\x2d\x49\x35\xcc\xbf\xc7\x34\x5b\xd9\xb6\xf0\xd6\x26\x7a\x3f\xe4\xd4\xf3\x4b\xe0\xfa\x77\xda\xa9\x29\xc3\x75\xe0\xc2\xd9\x47\x5e\x34\xe7\x6e\x4b\xdc\x7f\xca\x5d\x55\xcd\xb9\x07\xd6\xf6\x5e\xe7\x59\x4e\x99\x1e\x53\x8e\x96\x1e\xa1\xfa\xc0\xdf\x91\xb1\x0b\x48\x7c\x28\xd2\x54\x74\xe5\x54\x57\x70\x99\x7a\x64\x12\xed\xef\xef\x3f\x29\xf2\x4a\xce\xe9\x75\xf3\xef\x79\xaa\x96\x9f\x79\xfb\xc9\x15\xee\x3d\xf3\xde\xde\xe9\xc3\x5c\x69\x91\xae\xef\xf2\x53\x14\xb5\x92\xb5\x10\xa2\x1c\x29\x2d\x93\xb2\x89\x0b\x4d\xca\xc1\x1d\x4d\xc6\x85\xcf\xff\x4e\xc8\xd3\x8b\x67\xd1\x61\x9c\xbc\x7c\xf5\x39\x7d\x93\x1d\xbf\xbf\xfa\x78\x7d\xf3\xc7\x97\x4f\xd3\x70\xd5\xbd\x5e\xfa\x1d\x59\x08\xb2\xa9\xd1\x54\x69\xdb\x62\x86\x51\xed\x38\x98\x26\x72\x06\x1d\x3c\x0f\xbd\x3e\x7b\x34\x38\x00\xd5\x34\xf6\x31\xa4\x71\xde\x77\xbd\x33\x20\x10\xd6\x04\x73\xa2\x6a\xc9\xe9\xca\x48\xb5\x63\x03\xe1\xd2\x32\x07\x6f\xb4\x62\x60\x34\xa5\xba\x2b\x39\x68\x8f\x1f\x15\xfe\x9f\x96\x51\x40\xad\x99\xb5\xe2\x84\xe4\xac\x5c\xaa\x91\x7f\xa1\x22\xc1\xf2\x34\x7c\x53\xe2\xd9\x2c\xad\x85\x1a\xb2\x8d\xf6\x32\x35\xb0\xee\xa1\xab\x22\xf5\x27\xcd\x8e\x24\x24\xd4\xbd\x9e\xba\x0d\xb6\xcc\x1a\x31\xcd\xf4\xcd\x87\xa2\xf6\xfb\x96\xa1\x58\xe9\xae\x96\x34\x3d\xce\x48\x74\xbb\x8d\x05\xae\x33\xc2\xe3\xce\xed\x0e\xea\x39\x15\xc2\xb5\x3e\x2a\x69\xf3\xdc\x94\xed\xd2\x31\x4c\xb4\xf6\x92\x73\x5d\xae\x35\x50\x5c\xcd\xb4\x7f\x90\x2f\x2b\x49\xee\xdc\xcf\xfd\xa0\x9a\xab\xf7\x07\xd5\x36\xa6\x5a\x7b\x8d\xbf\x2e\xc7\xaa\x57\x83\xd5\x0c\xf3\xbd\x2e\xdc\xe9\xf2\x04\xae\x88\x9b\x87\x8d\xa3\xba\x82\xfa\x8f\xa1\x74\x23\xc2\xfe\x9b\x10\x6c\x3c\x16\x19\x10\xbe\x15\x54\xeb\x62\xfb\xb7\xc2\xb2\xed\xbe\xfa\xec\x8d\x9f\x9c\x4f\xef\x7b\xdf\x7c\x56\x9d\x46\x42\x2b\xb9\xf9\x03\xd0\xe8\x1b\x92\x76\xdd\x9c\xfa\x7d\x91\xf3\x3b\x21\x60\x40\x6a\xc0\x4e\x12\x00\x64\xf7\xb0\xe8\x44\xcc\x79\xe7\x73\x73\xfc\xfa\xcf\x7d\xab\x50\xf3\x93\x2f\xc0\xb6\xa6\xf6\xbf\xba\x94\x46\xa0\x2b\xaa\xe7\x28\x63\x24\x82\xb9\x60\x45\x79\x67\x29\xdf\x8d\x44\x5a\xdf\x21\xe3\x37\xb9\xd2\x28\x12\x5c\x13\xca\x11\xd1\x88\x01\x51\x1a\x09\x0e\x61\x71\xf3\x3e\xe0\xeb\xe9\xa9\xba\x7f\x72\xb6\x98\x3e\x28\x3e\x16\xab\xdf\x5f\xbc\xa6\x8a\x5c\x23\x0e\x57\x8c\x72\x50\x61\x53\xdf\x72\x76\x83\x08\x63\xe2\xaa\x19\x5c\x18\xac\xe7\x80\x80\xc7\x41\x13\xcf\x4e\xce\x4e\x4f\x79\x61\x1f\xff\x75\x37\xf8\xc0\xb3\x53\xfc\x59\xec\xfc\x1d\x00\x00\xff\xff\x08\xb9\x61\x01\x71\x24\x00\x00")
+var _schemaJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x5b\x5b\x73\xdb\x36\x16\x7e\xe7\xaf\xc0\xa0\xee\x4b\x2b\x57\x4e\xbd\xd9\x4e\xfc\xb2\x93\xba\xf6\xb4\x5b\x67\xe3\xa9\xb7\xe9\x6c\x6d\xc5\x83\x90\x90\x84\x84\x04\x58\x00\xb4\xe5\x78\xf4\xdf\x77\x40\x91\xc4\x85\x00\x2f\x96\xbc\xcd\xa6\xce\x68\x62\x11\xc0\xb9\x7f\xe7\x00\x04\xa0\xfb\x08\x00\xb8\x27\xe2\x25\xce\x10\x3c\x02\x70\x29\x65\x7e\x34\x9d\xbe\x17\x8c\xee\x6f\x5a\xbf\x61\x7c\x31\x4d\x38\x9a\xcb\xfd\x83\xef\xa6\x9b\xb6\x2f\xe0\x44\xd1\x49\x22\x53\xac\xa8\xce\x11\x95\x4b\xcc\x41\xca\x16\xa0\xe2\x55\x0e\xd8\x23\x49\xcd\x54\x1c\x4d\xa7\xbc\xa0\xf9\x66\xe4\x37\x84\x55\xac\xc4\x34\x65\x0b\x91\xe3\x78\x7a\x73\xb0\xe1\xba\xc7\xf1\x5c\x51\x7d\x31\x4d\xf0\x9c\x50\x22\x09\xa3\xa2\x1a\x7d\x91\xe3\x78\x33\xca\xe8\x83\x47\x40\x99\x01\x00\x34\x06\xd5\x6d\x4a\xcd\xbb\xbc\xd4\x92\xbd\x7b\x8f\x63\x59\x92\x97\xed\x39\x67\x39\xe6\x92\x60\xcd\x41\x7d\xe0\x0d\xe6\x82\x30\x6a\x35\x02\x00\x63\x46\x85\x84\x47\xe0\xa0\x69\x5c\xd7\xac\x1a\xd1\x2e\x4d\x2d\x5a\x48\x4e\xe8\xa2\x11\xad\x3e\x30\x23\xf4\x0c\xd3\x85\x5c\xc2\x23\x70\x68\xf5\xe4\x48\x4a\xcc\x95\x02\xf0\xed\xe5\xcb\xfd\xdf\x67\xea\x3f\xb4\xff\xf1\x60\xff\xc5\xec\xeb\x3d\xe8\x95\x9f\x60\x11\x73\x92\x4b\x8f\xe2\x8e\x12\x5e\x72\x8e\xe7\x98\x63\x1a\xe3\x5f\x7f\x39\x1b\x63\xc4\x9c\xf1\x0c\x29\xaf\xc0\x82\x13\xbf\x66\x39\xe2\x02\x73\x97\x69\x86\x56\xe7\xa6\xff\x9f\x59\x6c\x33\x42\x3b\x7a\x03\x81\x53\x1f\x18\x8b\x9b\x56\x23\x00\x90\x51\xfc\x5a\xa1\xea\xd2\xe9\x00\xad\xa1\x00\x04\x31\xb8\xb1\xe4\xf8\xe2\xcd\x6f\x44\x2e\x7f\xc4\x28\xc1\x1c\x46\x0e\xa9\x69\xfa\xb6\x22\x58\x21\x83\x52\x9c\x96\x59\xd4\xa1\x03\x9c\x23\x21\x33\x24\xe3\xa5\xcf\x35\x5d\x8a\x9c\x22\x21\x5f\x95\x84\x9d\xfc\x39\x5e\xe0\xd5\x58\xde\xbf\x28\xa2\x01\xcc\x3f\xdc\x8c\xe5\xfc\xf3\x9b\x6e\x8e\x29\x5b\xcc\x33\x39\x96\xeb\xd9\x86\xaa\x93\x73\x8c\xe7\x63\xd9\x1e\x9f\x9c\xf6\x68\x8b\xc7\x33\x3d\x3b\x69\x71\x35\x9e\xd6\x91\x47\x16\x9c\x73\x94\xa9\x22\x65\x8b\x0a\x88\xa9\x06\x97\xa5\xd6\xcf\x8d\xe0\x34\x11\xc3\x98\x6d\x0a\xf3\xe9\x86\xc2\xcb\xcd\x18\x1d\xaa\x4e\x4e\x75\x37\x0b\xa9\x55\x4a\x4c\x62\xe0\x2f\xb0\xee\xa0\xa0\xe2\x37\x28\x2d\xb0\xed\x83\x96\xaf\x2d\x85\x50\x92\x94\xa4\x28\xb5\x74\x9a\xa3\x54\xe0\xc8\x25\x6f\x1c\x00\x39\xfe\xa3\x20\x1c\xab\xc9\xf4\xb2\x99\x9e\x26\x8d\x93\x67\x91\x31\x1c\x5a\xde\xd4\xa6\x34\x8e\x42\x9c\xa3\xbb\xc6\x4f\x6a\x1a\xfa\x49\xe2\xcc\xaa\xb2\x90\x54\x2d\xf7\x51\x8f\x07\x4a\x0d\x4c\x0f\xac\x2d\x5d\x74\xb7\xa1\x08\x4a\x53\xa7\x16\x0f\x0f\x68\x47\x24\x29\xca\x70\xab\xb5\x3d\x7d\x59\xdd\xeb\x89\xf5\x68\x3a\x3a\xc8\xe7\x1d\x63\x29\x46\xb4\x9b\x51\x35\x78\x20\x8e\xd4\xe8\x8b\x18\xc7\xdd\x3c\xc3\x53\xfc\x68\x3b\x25\x47\x54\xa8\xb9\x7b\x84\x8e\x35\xc9\x18\xbc\x5b\xc0\x2d\x03\x34\xa9\x14\x9d\x45\x1e\x8a\xfb\xa8\x57\x0d\x4f\xca\xd5\xe2\xed\x34\x68\x4c\x1c\xba\x1c\x0c\xae\x39\x42\xa8\x83\x1c\x2b\x93\x4e\x39\x73\xdd\xe8\xc6\xa2\xe6\xd4\x5a\xf7\x3d\xf3\x79\x01\xc6\x2c\xbf\xdb\x3d\xd7\x72\x66\xf8\xe7\xc5\xeb\x7f\x85\xd8\xb6\x80\x6d\x52\xe3\x95\xe4\x28\x96\x21\xda\xee\xb5\xe1\x66\x7d\x30\x52\x5f\x91\xa7\x44\xee\xd6\x05\x25\x78\x5e\xa1\x1c\x1e\x0d\x2f\x39\xdd\x4b\xd1\x40\x41\x37\xb9\xb7\xb5\x8e\x7c\xa9\x63\xea\x99\xe0\x39\x2a\xd2\x5d\x18\x1f\x39\xcc\x21\x65\x36\x5b\x3b\x45\x0d\x44\x4f\x0c\x1c\xce\x5a\x6c\x7a\x27\xb2\x6a\x28\xd4\xf9\xaa\xa5\x7a\x56\xe3\x03\x32\x7f\x03\x31\x27\xf5\x27\x63\x38\x6c\xc2\xbb\x0d\x87\x72\xee\xdc\x86\x41\x86\xf2\x6d\xc8\x45\x8c\x52\xc4\xb7\xe1\x20\x49\x86\xb7\xa1\xe7\x78\xee\x90\xfb\xab\x6f\x3d\xa3\x19\x51\x0f\xc0\x17\x62\x5a\x64\x16\x18\xda\x00\x6f\x67\xa6\xb3\x8c\x51\x19\x80\x72\xf3\x51\x6d\x61\x98\xcf\x84\x5a\xe4\xf3\x94\x21\xab\x41\x64\x28\x4d\x9d\x41\xef\xc8\xc2\x6d\xa9\x6a\xa4\xd1\xa4\x3c\x2a\x24\xca\x2c\xe9\xca\x77\x5e\xc7\x18\x18\xf4\xb8\xc6\x31\x33\x38\xf3\x54\xe3\xbd\xfb\x13\xb5\xaf\x9a\xbe\x1d\x2f\xcb\x23\x87\xab\x5d\x41\x4a\xcd\x42\x4b\x53\x9d\x3e\x8f\x65\x7b\x29\xc1\x6f\x3a\x4e\x71\x86\xa9\x1c\x66\x7b\xc7\x32\xa3\xc7\xf0\x5a\x8c\x6d\x79\x9d\xf7\x8f\x65\x77\x86\xf2\x4f\xd0\x6a\xa3\x5c\xed\xd8\xf0\x40\x2d\xb1\xea\x09\x2c\x73\xb7\x49\x75\x9d\xce\x66\xb2\xd7\x85\x42\xa7\xb6\xb1\x32\x1d\x60\xbb\x63\xb0\x9e\xa3\x76\x6c\x70\x13\xe9\xca\xe2\xa6\xaf\x51\xae\xac\x71\x09\x89\x91\x64\xdc\x66\x18\x7c\xf9\x0b\xbc\xeb\x75\x20\xa4\x91\xa0\x11\xa2\xfd\x64\x2b\x53\x85\x61\xa8\x1a\xbe\xf7\xd0\x2e\xfd\x1c\x04\xf4\xaa\xa3\x37\x55\xef\xa3\x30\x9b\xad\x17\xb0\x91\x23\xb9\x17\x32\xda\xa3\x5a\xb3\x90\x56\x0d\xb4\xab\x67\x45\x6e\x4d\x3b\x09\xcb\x10\xb1\x66\xa7\x25\x13\x52\xad\xe9\xcc\xb6\x82\xa7\xe6\x23\xc5\xf2\x1a\x25\x09\x37\xdb\xb2\xe4\xb9\xf9\x28\x96\xe8\x99\xf3\xfc\xed\xf3\xbf\x9b\x2d\xe8\x56\x5c\x23\x6e\x89\x2e\x9b\xe2\x98\x15\x54\x5e\x93\xc4\xed\x21\x54\x48\x44\x63\xec\xe9\x92\xc8\x0c\x04\x54\x2f\x1e\xe5\xb0\xaa\xc9\xf6\x5f\xb3\xa0\x79\xac\x84\xd3\xf3\x7b\xd3\x5d\xc9\x56\x1f\x48\xc4\xc9\x0d\xa6\xf2\xdf\xa4\xb5\xfb\xd0\xa8\x51\x57\x16\x2f\xbd\x62\x7f\x5a\x03\xed\x3e\xea\xdd\xb3\x36\x87\x74\x61\xa5\xfe\xa7\x4f\x69\xbe\x2f\x48\x2a\xf7\x09\x05\x8d\x45\xa0\x42\x78\x8b\xc6\xde\x6a\x80\xc7\x2c\xcb\x58\x9b\x4e\xb4\x85\xd5\x00\x85\x7c\x1e\x1f\x1e\x1e\xbe\x50\x85\xb5\xa0\x64\x55\xff\xbd\xce\x44\xf3\xb5\xd0\x5f\x69\xbd\x52\xf0\x78\x68\x3b\xa3\x8f\x0b\x21\x59\x36\xde\xe4\x97\x20\xd6\x94\x15\x11\x20\x14\x08\xc9\xe7\x65\x13\x65\x12\x95\x83\x5b\x9c\x74\xa5\x81\x5f\x5e\xa2\x97\xef\xbe\x8f\x8f\x93\xf9\x8f\x3f\xbd\xcf\x5e\xe5\x17\xbf\xde\xfe\xb6\xba\xfb\xcf\xc7\xdf\x67\x1a\x0c\x76\xc9\xaa\xe1\x3d\xb4\x98\x4c\x2c\x04\xd9\xa9\x51\xaf\xd5\x35\xae\x76\x9b\x19\xc6\x22\xd7\x50\x52\x51\x21\xbe\xc0\x2d\x3c\x77\xc4\xec\x81\xd5\x74\x23\xc6\xde\x52\xaa\xc6\x42\xeb\x24\xa5\x3a\x46\x19\xe0\x08\x4b\xc0\x12\x89\x8a\x72\xd6\xeb\x29\x3d\x36\xe0\x2e\xc9\x0b\x63\x8f\xb7\xe6\x57\x22\x2f\x25\x19\x91\x98\x8f\x71\x58\x93\x68\x13\x95\x44\x57\xa5\x17\x80\x56\xd3\xd9\x46\x80\x13\x7f\xa0\x62\x96\x16\x19\x15\xe3\xa6\x69\x1d\xa8\x07\xcc\xd3\x26\x49\x30\xee\x3a\xf2\xb6\xba\xe2\x03\xc9\xcf\x39\x9e\x93\x55\x48\x63\x9f\xab\xfc\x32\x4c\xbe\x38\xcb\xe5\xdd\x1b\xb5\xf8\xfd\x5f\xba\xa2\xd7\x5c\xc9\x49\x76\x91\xa3\xf8\x61\x13\x0b\x5e\xe5\x88\x26\xad\x73\x80\x8e\xc5\x9d\xc4\x2b\x79\x5e\xa6\xcd\x89\x49\x1b\xb9\x5a\xae\xc3\x89\xa6\x8f\x2c\xb5\xc4\x61\xb9\x56\x43\xb1\x3f\xd3\xfe\xc4\x7c\xe9\x4d\x72\xe7\x24\xe7\x29\xd5\x9e\x52\x6d\xe7\xa9\xa6\x0f\xe5\xb5\xa8\x61\x39\xb6\xb9\x03\xd0\x9f\x61\xbe\xbb\x02\x3b\x0d\x8f\xdf\x29\xcd\x35\x85\xf3\x6a\x05\xd5\x1b\xb6\xcf\x0b\xa5\x26\x49\x50\xcd\xcf\x01\xc1\xc6\xd5\x0f\x2d\xeb\x61\x50\xad\x16\xdb\x3f\xa8\xdc\x7a\xdc\xfb\x01\x07\xfb\x2f\xae\x67\x5f\x79\x6f\x07\x74\xc6\xb1\x23\x92\xda\x4b\x4e\x3c\x86\x9c\xb0\x38\x24\x8f\x9f\xb4\x8e\x95\x91\xcf\x8a\xcf\x37\x39\x7b\xcd\xfd\x7f\x4a\xc0\x9f\xdf\x18\x32\x42\x29\xd2\x91\x73\x84\xff\x30\x70\x11\xe6\x35\xf6\x03\xde\xc4\x6f\x28\x93\x70\x20\xbd\xec\xff\x28\x98\xc4\xc7\x4b\xc4\x45\x0f\x5f\x2f\x35\x16\x31\xca\x4b\xf2\x51\x5a\xa1\x55\x8f\x56\x94\x9d\x94\x9c\x1f\x04\x0f\x95\x4b\x67\x84\x86\x11\x4f\xa8\xc4\x0b\x6c\xee\xe1\x6d\x5c\x45\xb2\x22\x0b\x5f\x5a\xfd\x6b\x65\xe8\x23\xe4\xd8\x76\xf9\xdf\x91\xa1\xd5\x6d\x43\xcd\x6a\x74\x96\x3e\x05\x77\xdb\xe0\x76\x84\x47\xdd\xda\xd4\x42\x46\xc7\x66\x59\xbe\x44\xfa\x95\xed\x76\x62\x7d\x46\xf2\x9d\xdd\x81\x56\xfe\x8e\x61\xce\x35\x49\x82\x18\x08\x39\xfe\x09\x67\xdb\xe2\xec\xf1\x8a\xc8\xc9\x9f\x0d\xd3\xe7\x21\x98\x3e\x7f\x40\x6c\x4c\x92\x20\x84\xb4\x47\x76\xb0\xcb\xea\x17\xf1\x84\xfe\x4f\x1c\xfd\xe6\xed\x74\xcd\x29\x08\x7f\xfb\xc5\x93\x25\x78\xc8\x16\x89\x1a\x16\xd0\xb2\x73\xff\x31\x25\x14\x9b\xd7\x0e\xaa\xa3\x65\x75\xcf\xbf\xc4\xf2\x04\xc0\x8f\x18\x7f\x80\x33\xaf\x87\x84\x44\x5c\xd6\xdb\x23\x3b\x05\xdc\x46\xfc\x78\x28\x37\x86\x25\x38\x26\x19\x4a\x95\x6d\x05\xa1\xf2\xf0\xdb\x80\x09\x19\x5a\x9d\x50\xc9\xef\x2e\xc8\x47\xfc\xc0\xd5\xac\x61\x41\xe4\x08\xe8\x7c\x6f\x9e\x0c\xbc\x82\x4e\xda\x3f\xb8\x08\x01\x21\x0c\x07\x67\x67\xb8\xba\x43\xe0\x0c\x58\x47\xa1\x27\xc3\x65\xca\x31\x4b\xec\xc6\xdb\x05\xae\x05\x8d\x59\xe4\xe3\x5a\x7f\xb3\x4f\xca\x02\x19\xaa\xa5\x05\x13\x27\xe0\xeb\xfb\xa8\x15\xd0\x0a\x39\xfe\x6c\x75\xb7\xfd\xc6\xff\x56\xa0\x0f\xf2\x7f\x6b\x3a\xd6\x93\x00\xa7\xfa\xc0\xb8\x52\x02\xdc\x12\xb9\x04\x79\x8a\x62\xbc\x64\x69\xe2\x62\x71\x2f\x66\x59\x75\xaf\x0a\xbe\x2a\x84\x04\x31\xa3\x12\x11\x0a\x90\x04\x29\x46\x42\x02\x46\x71\x98\xbc\xda\x78\x52\xd4\x5f\xde\x5f\x5d\x89\xaf\x2e\xdf\xae\x67\x5f\xab\x2f\x6b\xf8\x30\x55\x59\x21\x01\xc5\xb7\xaa\xba\xd8\xc7\xf2\x96\xaa\xaf\x69\x7a\x07\x50\x9a\xb2\xdb\x7a\xb0\x52\x58\x2e\x31\xc0\x34\x09\xaa\xf8\xf6\xf2\xed\xd5\x15\x55\xfa\xd1\x7f\x98\x3f\x3f\xb4\xd1\x14\x01\xb0\x8e\xd6\xd1\x7f\x07\x00\x6a\x4d\xd4\xec\x52\x3a\x00\x00")
 
 func schemaJsonBytes() ([]byte, error) {
 	return bindataRead(
@@ -211,7 +193,7 @@ type bintree struct {
 }
 
 var _bintree = &bintree{nil, map[string]*bintree{
-	"schema.json": {schemaJson, map[string]*bintree{}},
+	"schema.json": &bintree{schemaJson, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory
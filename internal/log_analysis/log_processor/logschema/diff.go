@@ -41,11 +41,13 @@ const (
 	DeleteField = "DeleteField"
 	// UpdateFieldMeta is the type of change when a field's metadata was changed (i.e. Required, Description).
 	UpdateFieldMeta = "UpdateFieldMeta"
+	// UpdateFieldTransform is the type of change when a field's transform directives have changed.
+	UpdateFieldTransform = "UpdateFieldTransform"
 	// UpdateValue is the type of change when a field's value type has changed.
 	UpdateValue = "UpdateValue"
-	// UpdateValueMeta is the type of change when metadata about a field's value type has changed (i.e. TimeFormat, IsEventTime, Indicators).
+	// UpdateValueMeta is the type of change when metadata about a field's value type has changed (i.e. TimeFormat, IsEventTime, Indicators, Enum, Pattern).
 	UpdateValueMeta = "UpdateValueMeta"
-	// UpdateParser is the type of change when a schema's Parser has changed.
+	// UpdateParser is the type of change when a schema's Parser or Framing has changed.
 	UpdateParser = "UpdateParser"
 	// UpdateMeta is the type of change when a schema's metadata has changed (i.e. Schema, Description, ReferenceURL).
 	UpdateMeta = "UpdateMeta"
@@ -75,6 +77,9 @@ func Diff(from, to *Schema) ([]Change, error) {
 	if !reflect.DeepEqual(from.Parser, to.Parser) {
 		c.add(UpdateParser, from.Parser, to.Parser, "Parser")
 	}
+	if !from.Framing.Equal(to.Framing) {
+		c.add(UpdateParser, from.Framing, to.Framing, "Framing")
+	}
 	DiffWalk(valueFrom, valueTo, func(ch Change) bool {
 		c.changes = append(c.changes, ch)
 		return true
@@ -106,7 +111,7 @@ func diffWalk(from, to *ValueSchema, walk func(c Change) bool, path []string) bo
 	switch to.Type {
 	case TypeObject:
 		return walkObject(from.Fields, to.Fields, walk, path)
-	case TypeArray:
+	case TypeArray, TypeMap:
 		return diffWalk(from.Element, to.Element, walk, append(path, "*"))
 	case TypeTimestamp:
 		if from.IsEventTime != to.IsEventTime {
@@ -140,6 +145,28 @@ func diffWalk(from, to *ValueSchema, walk func(c Change) bool, path []string) bo
 				From: from,
 				To:   to,
 			}
+			if !walk(ch) {
+				return false
+			}
+		}
+		if from, to, changed := diffIndicators(from.Enum, to.Enum); changed {
+			ch := Change{
+				Type: UpdateValueMeta,
+				Path: append(path, "Enum"),
+				From: from,
+				To:   to,
+			}
+			if !walk(ch) {
+				return false
+			}
+		}
+		if from.Pattern != to.Pattern {
+			ch := Change{
+				Type: UpdateValueMeta,
+				Path: append(path, "Pattern"),
+				From: from.Pattern,
+				To:   to.Pattern,
+			}
 			return walk(ch)
 		}
 		return true
@@ -178,6 +205,17 @@ func walkObject(from, to []FieldSchema, walk func(c Change) bool, path []string)
 					return false
 				}
 			}
+			if !reflect.DeepEqual(A.Transform, B.Transform) {
+				ch := Change{
+					Type: UpdateFieldTransform,
+					Path: append(path, A.Name, "Transform"),
+					From: A.Transform,
+					To:   B.Transform,
+				}
+				if !walk(ch) {
+					return false
+				}
+			}
 		case A != nil:
 			ch := Change{
 				Type: DeleteField,
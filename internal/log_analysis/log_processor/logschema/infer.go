@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"net"
 	"net/url"
+	"regexp"
 	"sort"
 	"strconv"
 	"time"
@@ -38,6 +39,17 @@ import (
 func InferJSONValueSchema(x interface{}) *ValueSchema {
 	switch v := x.(type) {
 	case map[string]interface{}:
+		if isMapObject(v) {
+			// Keys that cannot be used as field names (i.e. Kubernetes labels) are inferred as a map
+			var merged *ValueSchema
+			for _, val := range v {
+				merged = Merge(merged, InferJSONValueSchema(val))
+			}
+			return &ValueSchema{
+				Type:    TypeMap,
+				Element: merged,
+			}
+		}
 		var fields []FieldSchema
 		for key, val := range v {
 			vs := InferJSONValueSchema(val)
@@ -89,6 +101,18 @@ func InferJSONValueSchema(x interface{}) *ValueSchema {
 	}
 }
 
+// isMapObject checks if an object has keys that cannot be used as field names
+func isMapObject(obj map[string]interface{}) bool {
+	for key := range obj {
+		if reMapKey.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+var reMapKey = regexp.MustCompile(`[^A-Za-z0-9_\-@$]`)
+
 func inferString(s string) *ValueSchema {
 	if _, err := json.Number(s).Int64(); err == nil {
 		return &ValueSchema{
@@ -151,10 +175,10 @@ func (v *ValueSchema) NonEmpty() *ValueSchema {
 			Type:   TypeObject,
 			Fields: fields,
 		}
-	case TypeArray:
+	case TypeArray, TypeMap:
 		if el := v.Element.NonEmpty(); el != nil {
 			return &ValueSchema{
-				Type:    TypeArray,
+				Type:    v.Type,
 				Element: el,
 			}
 		}
@@ -0,0 +1,47 @@
+package logschema
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInferJSONValueSchemaMap(t *testing.T) {
+	assert := require.New(t)
+	labels := map[string]interface{}{
+		"app.kubernetes.io/name": "web",
+		"tier":                   "frontend",
+	}
+	assert.Equal(&ValueSchema{
+		Type:    TypeMap,
+		Element: &ValueSchema{Type: TypeString},
+	}, InferJSONValueSchema(labels))
+
+	// Objects with keys that are valid field names are inferred as objects and merged with maps.
+	obj := InferJSONValueSchema(map[string]interface{}{
+		"tier": "frontend",
+	})
+	assert.Equal(TypeObject, obj.Type)
+	assert.Equal(&ValueSchema{
+		Type:    TypeMap,
+		Element: &ValueSchema{Type: TypeString},
+	}, Merge(obj, InferJSONValueSchema(labels)))
+}
@@ -25,6 +25,7 @@ import (
 	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
 
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/preprocessors"
 	"github.com/panther-labs/panther/pkg/stringset"
 
@@ -38,6 +39,7 @@ type ValueType string
 const (
 	TypeObject    ValueType = "object"
 	TypeArray     ValueType = "array"
+	TypeMap       ValueType = "map"
 	TypeTimestamp ValueType = "timestamp"
 	TypeRef       ValueType = "ref"
 	TypeString    ValueType = "string"
@@ -51,7 +53,7 @@ const (
 
 func (t ValueType) IsComposite() bool {
 	switch t {
-	case TypeObject, TypeArray, TypeJSON:
+	case TypeObject, TypeArray, TypeMap, TypeJSON:
 		return true
 	default:
 		return false
@@ -59,13 +61,14 @@ func (t ValueType) IsComposite() bool {
 }
 
 type Schema struct {
-	Schema       string                  `json:"schema,omitempty" yaml:"schema,omitempty"`
-	Parser       *Parser                 `json:"parser,omitempty" yaml:"parser,omitempty"`
-	Description  string                  `json:"description,omitempty" yaml:"description,omitempty"`
-	ReferenceURL string                  `json:"referenceURL,omitempty" yaml:"referenceURL,omitempty"`
-	Version      int                     `json:"version" yaml:"version"`
-	Definitions  map[string]*ValueSchema `json:"definitions,omitempty" yaml:"definitions,omitempty"`
-	Fields       []FieldSchema           `json:"fields" yaml:"fields"`
+	Schema       string                    `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Parser       *Parser                   `json:"parser,omitempty" yaml:"parser,omitempty"`
+	Framing      *pantherlog.FramingConfig `json:"framing,omitempty" yaml:"framing,omitempty"`
+	Description  string                    `json:"description,omitempty" yaml:"description,omitempty"`
+	ReferenceURL string                    `json:"referenceURL,omitempty" yaml:"referenceURL,omitempty"`
+	Version      int                       `json:"version" yaml:"version"`
+	Definitions  map[string]*ValueSchema   `json:"definitions,omitempty" yaml:"definitions,omitempty"`
+	Fields       []FieldSchema             `json:"fields" yaml:"fields"`
 }
 
 func (s *Schema) Clone() *Schema {
@@ -89,6 +92,10 @@ type Parser struct {
 	CSV       *preprocessors.CSVMatchConfig  `json:"csv,omitempty" yaml:"csv,omitempty"`
 	FastMatch *preprocessors.FastMatchConfig `json:"fastmatch,omitempty" yaml:"fastmatch,omitempty"`
 	Regex     *preprocessors.RegexConfig     `json:"regex,omitempty" yaml:"regex,omitempty"`
+	KV        *preprocessors.KVConfig        `json:"kv,omitempty" yaml:"kv,omitempty"`
+	Logfmt    *preprocessors.LogfmtConfig    `json:"logfmt,omitempty" yaml:"logfmt,omitempty"`
+	CEF       *preprocessors.CEFConfig       `json:"cef,omitempty" yaml:"cef,omitempty"`
+	LEEF      *preprocessors.LEEFConfig      `json:"leef,omitempty" yaml:"leef,omitempty"`
 }
 
 type ValueSchema struct {
@@ -97,6 +104,8 @@ type ValueSchema struct {
 	Element     *ValueSchema  `json:"element,omitempty" yaml:"element,omitempty"`
 	Target      string        `json:"target,omitempty" yaml:"target,omitempty"`
 	Indicators  []string      `json:"indicators,omitempty" yaml:"indicators,omitempty"`
+	Enum        []string      `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Pattern     string        `json:"pattern,omitempty" yaml:"pattern,omitempty"`
 	TimeFormat  string        `json:"timeFormat,omitempty" yaml:"timeFormat,omitempty"`
 	IsEventTime bool          `json:"isEventTime,omitempty" yaml:"isEventTime,omitempty"`
 }
@@ -117,9 +126,9 @@ func (v *ValueSchema) Clone() *ValueSchema {
 			Type:   TypeObject,
 			Fields: fields,
 		}
-	case TypeArray:
+	case TypeArray, TypeMap:
 		return &ValueSchema{
-			Type:    TypeArray,
+			Type:    v.Type,
 			Element: v.Element.Clone(),
 		}
 	case TypeTimestamp:
@@ -132,6 +141,8 @@ func (v *ValueSchema) Clone() *ValueSchema {
 		return &ValueSchema{
 			Type:       TypeString,
 			Indicators: stringset.New(v.Indicators...),
+			Enum:       stringset.New(v.Enum...),
+			Pattern:    v.Pattern,
 		}
 	case TypeRef:
 		return &ValueSchema{
@@ -146,9 +157,10 @@ func (v *ValueSchema) Clone() *ValueSchema {
 }
 
 type FieldSchema struct {
-	Name        string `json:"name" yaml:"name"`
-	Required    bool   `json:"required,omitempty" yaml:"required,omitempty"`
-	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Name        string     `json:"name" yaml:"name"`
+	Required    bool       `json:"required,omitempty" yaml:"required,omitempty"`
+	Description string     `json:"description,omitempty" yaml:"description,omitempty"`
+	Transform   *Transform `json:"transform,omitempty" yaml:"transform,omitempty"`
 	ValueSchema `yaml:",inline"`
 }
 
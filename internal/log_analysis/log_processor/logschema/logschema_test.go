@@ -42,6 +42,7 @@ func TestValueSpecValidate(t *testing.T) {
 		"./testdata/apache_common_log_fastmatch_schema.yml",
 		"./testdata/apache_common_log_regex_schema.yml",
 		"./testdata/vpcflow_schema.yml",
+		"./testdata/transforms_schema.yml",
 	} {
 		schemaFile := schemaFile
 		t.Run(schemaFile, func(t *testing.T) {
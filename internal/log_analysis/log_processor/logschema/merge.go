@@ -18,6 +18,12 @@ package logschema
  * along with this program.  If not, see <https://www.gnu.org/licenses/>.
  */
 
+import (
+	"sort"
+
+	"github.com/panther-labs/panther/pkg/stringset"
+)
+
 // Merge merges to value schemas to a a common schema that can handle both values.
 // The returned value is a fully independent ValueSchema (deep copy).
 // It panics if values a or b are not fully resolved via `Resolve().
@@ -41,21 +47,13 @@ func Merge(a, b *ValueSchema) *ValueSchema {
 				Type:   TypeObject,
 				Fields: mergeObjectFields(a.Fields, b.Fields),
 			}
-		case TypeArray:
+		case TypeArray, TypeMap:
 			return &ValueSchema{
-				Type:    TypeArray,
+				Type:    a.Type,
 				Element: Merge(a.Element, b.Element),
 			}
 		case TypeString:
-			// Try to preserve indicators.
-			// Make sure that indicators in the output value are a copy of the input slice.
-			if indicators, _, changed := diffIndicators(a.Indicators, b.Indicators); !changed {
-				return &ValueSchema{
-					Type:       TypeString,
-					Indicators: indicators,
-				}
-			}
-			return &ValueSchema{Type: TypeString}
+			return mergeString(a, b)
 		case TypeTimestamp:
 			if a.TimeFormat != b.TimeFormat {
 				return &ValueSchema{Type: TypeString}
@@ -77,6 +75,10 @@ func Merge(a, b *ValueSchema) *ValueSchema {
 	// Each castX function only handles the 'lesser' value types in the following order
 	// JSON > OBJECT,ARRAY > TIMESTAMP > STRING > FLOAT > BIGINT > INT
 	switch {
+	case a.Type == TypeMap && b.Type == TypeObject:
+		return mergeMapObject(a, b.Fields)
+	case a.Type == TypeObject && b.Type == TypeMap:
+		return mergeMapObject(b, a.Fields)
 	case a.Type.IsComposite(), b.Type.IsComposite():
 		return &ValueSchema{Type: TypeJSON}
 	case a.Type == TypeTimestamp:
@@ -124,6 +126,38 @@ func mergeObjectFields(a, b []FieldSchema) (fields []FieldSchema) {
 	return fields
 }
 
+// mergeString merges two string values, preserving indicators and constraints that apply to both.
+func mergeString(a, b *ValueSchema) *ValueSchema {
+	out := ValueSchema{Type: TypeString}
+	// Try to preserve indicators.
+	// Make sure that indicators in the output value are a copy of the input slice.
+	if indicators, _, changed := diffIndicators(a.Indicators, b.Indicators); !changed {
+		out.Indicators = indicators
+	}
+	// The merged value should allow values from both
+	if len(a.Enum) > 0 && len(b.Enum) > 0 {
+		enum := stringset.Concat(a.Enum, b.Enum)
+		sort.Strings(enum)
+		out.Enum = enum
+	}
+	if a.Pattern == b.Pattern {
+		out.Pattern = a.Pattern
+	}
+	return &out
+}
+
+// mergeMapObject merges the values of all object fields into the map values
+func mergeMapObject(m *ValueSchema, fields []FieldSchema) *ValueSchema {
+	el := m.Element.Clone()
+	for i := range fields {
+		el = Merge(el, &fields[i].ValueSchema)
+	}
+	return &ValueSchema{
+		Type:    TypeMap,
+		Element: el,
+	}
+}
+
 // castTimestamp handles values conversion for timestamps
 // a is always type timestamp
 // b is a 'lesser' value type (string, numeric, bool)
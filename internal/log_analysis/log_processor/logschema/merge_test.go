@@ -122,6 +122,14 @@ func TestMerge(t *testing.T) {
 		{"TimestampUNIX,EventTimestampUNIX", &V{Type: TypeTimestamp, TimeFormat: "unix"}, &V{Type: TypeTimestamp, TimeFormat: "unix", IsEventTime: true}, &V{Type: TypeTimestamp, TimeFormat: "unix", IsEventTime: true}},
 		{"Int,Int", &V{Type: TypeInt}, &V{Type: TypeInt}, &V{Type: TypeInt}},
 		{"SmallInt,Bool", &V{Type: TypeSmallInt}, &V{Type: TypeBoolean}, &V{Type: TypeString}},
+		{"Map,Map", &V{Type: TypeMap, Element: S}, &V{Type: TypeMap, Element: &V{Type: TypeInt}}, &V{Type: TypeMap, Element: S}},
+		{"Map,Object", &V{Type: TypeMap, Element: &V{Type: TypeInt}}, &V{Type: TypeObject, Fields: fieldsB}, &V{Type: TypeMap, Element: &V{Type: TypeInt}}},
+		{"Map,Array", &V{Type: TypeMap, Element: S}, &V{Type: TypeArray, Element: S}, &V{Type: TypeJSON}},
+		{"Map,String", &V{Type: TypeMap, Element: S}, S, &V{Type: TypeJSON}},
+		{"EnumString,EnumString", &V{Type: TypeString, Enum: []string{"b", "a"}}, &V{Type: TypeString, Enum: []string{"c", "a"}}, &V{Type: TypeString, Enum: []string{"a", "b", "c"}}},
+		{"EnumString,String", &V{Type: TypeString, Enum: []string{"a"}}, S, S},
+		{"PatternString,PatternString", &V{Type: TypeString, Pattern: "^a"}, &V{Type: TypeString, Pattern: "^a"}, &V{Type: TypeString, Pattern: "^a"}},
+		{"PatternString,String", &V{Type: TypeString, Pattern: "^a"}, S, S},
 		{"Int,Nil", &V{Type: TypeInt}, nil, &V{Type: TypeInt}},
 		{"Nil,Int", nil, &V{Type: TypeInt}, &V{Type: TypeInt}},
 		{"Nil,Nil", nil, nil, nil},
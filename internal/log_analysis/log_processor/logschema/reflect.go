@@ -46,6 +46,7 @@ var (
 		TypeBoolean:   reflect.TypeOf(pantherlog.Bool{}),
 		TypeTimestamp: reflect.TypeOf(pantherlog.Time{}),
 	}
+	typString = reflect.TypeOf("")
 )
 
 func (v *ValueSchema) GoType() (reflect.Type, error) {
@@ -67,6 +68,12 @@ func (v *ValueSchema) GoType() (reflect.Type, error) {
 			return nil, err
 		}
 		return reflect.SliceOf(el), nil
+	case TypeMap:
+		el, err := v.Element.GoType()
+		if err != nil {
+			return nil, err
+		}
+		return reflect.MapOf(typString, el), nil
 	default:
 		if typ := typeMappings[v.Type]; typ != nil {
 			return typ, nil
@@ -139,7 +146,7 @@ func normalizeSpace(input string) string {
 
 func extendStructTag(schema *ValueSchema, tag string) string {
 	switch schema.Type {
-	case TypeArray:
+	case TypeArray, TypeMap:
 		return extendStructTag(schema.Element, tag)
 	case TypeString:
 		if len(schema.Indicators) == 0 {
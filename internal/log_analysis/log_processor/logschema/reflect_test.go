@@ -60,3 +60,20 @@ func TestArrayIndicators(t *testing.T) {
 	assert.Equal(reflect.TypeOf([]null.String{}), goFields[0].Type)
 	assert.Equal(`json:"remote_ips,omitempty" panther:"ip" description:"remote ip addresses"`, string(goFields[0].Tag))
 }
+
+func TestMapIndicators(t *testing.T) {
+	schema := ValueSchema{
+		Type: TypeMap,
+		Element: &ValueSchema{
+			Type:       TypeString,
+			Indicators: []string{"ip"},
+		},
+	}
+	typ, err := schema.GoType()
+	assert := require.New(t)
+	assert.NoError(err)
+	assert.Equal(reflect.TypeOf(map[string]null.String{}), typ)
+	goFields, err := objectFields([]FieldSchema{{Name: "addresses", ValueSchema: schema}})
+	assert.NoError(err)
+	assert.Equal(`json:"addresses,omitempty" panther:"ip" description:"addresses"`, string(goFields[0].Tag))
+}
@@ -70,7 +70,7 @@ func safeBuild(input *ValueSchema, manifest map[string]*ValueSchema, path, visit
 			Type:   TypeObject,
 			Fields: out,
 		}, nil
-	case TypeArray:
+	case TypeArray, TypeMap:
 		if len(path) == cap(path) {
 			return nil, fmt.Errorf("max nesting level (%d) exceeded", MaxDepth)
 		}
@@ -79,7 +79,7 @@ func safeBuild(input *ValueSchema, manifest map[string]*ValueSchema, path, visit
 			return nil, err
 		}
 		return &ValueSchema{
-			Type:    TypeArray,
+			Type:    input.Type,
 			Element: item,
 		}, nil
 	case TypeRef:
@@ -103,6 +103,8 @@ func safeBuild(input *ValueSchema, manifest map[string]*ValueSchema, path, visit
 		return &ValueSchema{
 			Type:       TypeString,
 			Indicators: append([]string(nil), input.Indicators...),
+			Enum:       append([]string(nil), input.Enum...),
+			Pattern:    input.Pattern,
 		}, nil
 	case TypeTimestamp:
 		return &ValueSchema{
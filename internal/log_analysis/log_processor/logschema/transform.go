@@ -0,0 +1,418 @@
+package logschema
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/preprocessors"
+	"github.com/panther-labs/panther/pkg/stringset"
+)
+
+// Transform declares directives that normalize the value of a field before it is decoded.
+// Directives are applied in the order they are declared here.
+// nolint:lll
+type Transform struct {
+	RenameFrom string            `json:"renameFrom,omitempty" yaml:"renameFrom,omitempty" description:"Move the value found at a dot-separated path relative to the parent object to this field"`
+	CopyFrom   string            `json:"copyFrom,omitempty" yaml:"copyFrom,omitempty" description:"Copy the value found at a dot-separated path relative to the parent object to this field"`
+	ParseJSON  bool              `json:"parseJSON,omitempty" yaml:"parseJSON,omitempty" description:"Parse a string value as embedded JSON"`
+	Extract    string            `json:"extract,omitempty" yaml:"extract,omitempty" description:"Replace a string value with the first capture group (or the whole match) of a regular expression"`
+	Split      string            `json:"split,omitempty" yaml:"split,omitempty" description:"Split a string value into an array using a separator"`
+	ValueMap   map[string]string `json:"valueMap,omitempty" yaml:"valueMap,omitempty" description:"Replace values (or array elements) using a mapping"`
+	Default    string            `json:"default,omitempty" yaml:"default,omitempty" description:"Default value to use if the field is missing, null or empty"`
+}
+
+func (t *Transform) isEmpty() bool {
+	return t.RenameFrom == "" && t.CopyFrom == "" && !t.ParseJSON && t.Extract == "" && t.Split == "" &&
+		len(t.ValueMap) == 0 && t.Default == ""
+}
+
+// BuildTransform builds a preprocessor that applies all field transforms and checks all string constraints
+// (enum, pattern) in a resolved value schema.
+// It returns nil if the schema has no transforms or constraints.
+func BuildTransform(schema *ValueSchema) (preprocessors.Interface, error) {
+	t, err := buildValueTransform(schema, nil)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, nil
+	}
+	return &transformPreprocessor{
+		api:       transformJSON,
+		transform: t,
+	}, nil
+}
+
+var transformJSON = jsoniter.Config{
+	UseNumber: true,
+}.Froze()
+
+type transformPreprocessor struct {
+	api       jsoniter.API
+	transform valueTransform
+}
+
+func (p *transformPreprocessor) PreProcessLog(log string) (string, error) {
+	if log == "" {
+		return "", nil
+	}
+	var event interface{}
+	if err := p.api.UnmarshalFromString(log, &event); err != nil {
+		return "", errors.Wrap(err, "failed to read event for transforms")
+	}
+	event, err := p.transform.transformValue(event)
+	if err != nil {
+		return "", err
+	}
+	return p.api.MarshalToString(event)
+}
+
+type valueTransform interface {
+	transformValue(v interface{}) (interface{}, error)
+}
+
+func buildValueTransform(schema *ValueSchema, path []string) (valueTransform, error) {
+	switch schema.Type {
+	case TypeObject:
+		var fields []fieldTransform
+		for i := range schema.Fields {
+			field := &schema.Fields[i]
+			fieldPath := appendPath(path, field.Name)
+			value, err := buildValueTransform(&field.ValueSchema, fieldPath)
+			if err != nil {
+				return nil, err
+			}
+			directives, err := compileTransform(field, fieldPath)
+			if err != nil {
+				return nil, err
+			}
+			if value == nil && directives == nil {
+				continue
+			}
+			fields = append(fields, fieldTransform{
+				name:       field.Name,
+				directives: directives,
+				value:      value,
+			})
+		}
+		if fields == nil {
+			return nil, nil
+		}
+		return objectTransform(fields), nil
+	case TypeArray:
+		element, err := buildValueTransform(schema.Element, appendPath(path, "*"))
+		if err != nil {
+			return nil, err
+		}
+		if element == nil {
+			return nil, nil
+		}
+		return &arrayTransform{element: element}, nil
+	case TypeMap:
+		element, err := buildValueTransform(schema.Element, appendPath(path, "*"))
+		if err != nil {
+			return nil, err
+		}
+		if element == nil {
+			return nil, nil
+		}
+		return &mapTransform{element: element}, nil
+	case TypeString:
+		return buildStringConstraint(schema, path)
+	default:
+		return nil, nil
+	}
+}
+
+// appendPath returns a copy of path with name appended, so that sibling fields do not share a backing array
+func appendPath(path []string, name string) []string {
+	p := make([]string, len(path), len(path)+1)
+	copy(p, path)
+	return append(p, name)
+}
+
+type objectTransform []fieldTransform
+
+type fieldTransform struct {
+	name       string
+	directives *compiledTransform
+	value      valueTransform
+}
+
+func (fields objectTransform) transformValue(v interface{}) (interface{}, error) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return v, nil
+	}
+	for i := range fields {
+		field := &fields[i]
+		value, found := obj[field.name]
+		if d := field.directives; d != nil {
+			var err error
+			value, found, err = d.apply(obj, value, found)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "failed to transform field %q", field.name)
+			}
+		}
+		if found && field.value != nil {
+			var err error
+			if value, err = field.value.transformValue(value); err != nil {
+				return nil, errors.WithMessagef(err, "invalid field %q", field.name)
+			}
+		}
+		if found {
+			obj[field.name] = value
+		} else {
+			delete(obj, field.name)
+		}
+	}
+	return obj, nil
+}
+
+type arrayTransform struct {
+	element valueTransform
+}
+
+func (a *arrayTransform) transformValue(v interface{}) (interface{}, error) {
+	values, ok := v.([]interface{})
+	if !ok {
+		return v, nil
+	}
+	for i, el := range values {
+		el, err := a.element.transformValue(el)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = el
+	}
+	return values, nil
+}
+
+type mapTransform struct {
+	element valueTransform
+}
+
+func (m *mapTransform) transformValue(v interface{}) (interface{}, error) {
+	values, ok := v.(map[string]interface{})
+	if !ok {
+		return v, nil
+	}
+	for key, el := range values {
+		el, err := m.element.transformValue(el)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid map value %q", key)
+		}
+		values[key] = el
+	}
+	return values, nil
+}
+
+// stringConstraint reports string values that are not allowed by a schema.
+// Values that violate a constraint are logged as warnings and kept, so that events are never dropped.
+type stringConstraint struct {
+	field   string
+	enum    []string
+	pattern *regexp.Regexp
+}
+
+func buildStringConstraint(schema *ValueSchema, path []string) (valueTransform, error) {
+	if len(schema.Enum) == 0 && schema.Pattern == "" {
+		return nil, nil
+	}
+	c := stringConstraint{
+		field: strings.Join(path, "."),
+		enum:  schema.Enum,
+	}
+	if schema.Pattern != "" {
+		re, err := regexp.Compile(schema.Pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "field %q has invalid pattern", strings.Join(path, "."))
+		}
+		c.pattern = re
+	}
+	return &c, nil
+}
+
+func (c *stringConstraint) transformValue(v interface{}) (interface{}, error) {
+	s, ok := scalarString(v)
+	if !ok {
+		return v, nil
+	}
+	// Values are not logged to avoid leaking log data
+	if len(c.enum) > 0 && !stringset.Contains(c.enum, s) {
+		zap.L().Warn("field value is not one of the allowed values",
+			zap.String("field", c.field), zap.Strings("enum", c.enum))
+	}
+	if c.pattern != nil && !c.pattern.MatchString(s) {
+		zap.L().Warn("field value does not match pattern",
+			zap.String("field", c.field), zap.Stringer("pattern", c.pattern))
+	}
+	return v, nil
+}
+
+type compiledTransform struct {
+	source       []string
+	copy         bool
+	parseJSON    bool
+	extract      *regexp.Regexp
+	split        string
+	valueMap     map[string]string
+	defaultValue string
+}
+
+func compileTransform(field *FieldSchema, path []string) (*compiledTransform, error) {
+	t := field.Transform
+	if t == nil || t.isEmpty() {
+		return nil, nil
+	}
+	c := compiledTransform{
+		parseJSON:    t.ParseJSON,
+		split:        t.Split,
+		valueMap:     t.ValueMap,
+		defaultValue: t.Default,
+	}
+	switch {
+	case t.RenameFrom != "" && t.CopyFrom != "":
+		return nil, errors.Errorf("field %q cannot both rename and copy a value", strings.Join(path, "."))
+	case t.RenameFrom != "":
+		c.source = strings.Split(t.RenameFrom, ".")
+	case t.CopyFrom != "":
+		c.source = strings.Split(t.CopyFrom, ".")
+		c.copy = true
+	}
+	if t.Extract != "" {
+		re, err := regexp.Compile(t.Extract)
+		if err != nil {
+			return nil, errors.Wrapf(err, "field %q has invalid extract pattern", strings.Join(path, "."))
+		}
+		c.extract = re
+	}
+	if t.Split != "" && field.Type != TypeArray {
+		return nil, errors.Errorf("field %q must be an array to split values", strings.Join(path, "."))
+	}
+	return &c, nil
+}
+
+func (c *compiledTransform) apply(obj map[string]interface{}, value interface{}, found bool) (interface{}, bool, error) {
+	if c.source != nil {
+		if v, ok := lookupPath(obj, c.source, !c.copy); ok {
+			value, found = v, true
+		}
+	}
+	if found && value != nil {
+		if c.parseJSON {
+			if s, ok := value.(string); ok {
+				var v interface{}
+				if err := transformJSON.UnmarshalFromString(s, &v); err != nil {
+					return nil, false, errors.Wrap(err, "invalid embedded JSON")
+				}
+				value = v
+			}
+		}
+		if c.extract != nil {
+			if s, ok := scalarString(value); ok {
+				match := c.extract.FindStringSubmatch(s)
+				switch {
+				case match == nil:
+					value, found = nil, false
+				case len(match) > 1:
+					value = match[1]
+				default:
+					value = match[0]
+				}
+			}
+		}
+		if c.split != "" {
+			if s, ok := value.(string); ok {
+				value = splitValues(s, c.split)
+			}
+		}
+		if c.valueMap != nil {
+			value = mapValues(value, c.valueMap)
+		}
+	}
+	if c.defaultValue != "" && (!found || value == nil || value == "") {
+		value, found = c.defaultValue, true
+	}
+	return value, found, nil
+}
+
+// lookupPath finds the value at path in obj, optionally removing it
+func lookupPath(obj map[string]interface{}, path []string, remove bool) (interface{}, bool) {
+	last := len(path) - 1
+	for _, key := range path[:last] {
+		next, ok := obj[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		obj = next
+	}
+	key := path[last]
+	value, ok := obj[key]
+	if ok && remove {
+		delete(obj, key)
+	}
+	return value, ok
+}
+
+func splitValues(s, sep string) []interface{} {
+	var values []interface{}
+	for _, part := range strings.Split(s, sep) {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+func mapValues(value interface{}, mapping map[string]string) interface{} {
+	if values, ok := value.([]interface{}); ok {
+		for i, v := range values {
+			values[i] = mapValues(v, mapping)
+		}
+		return values
+	}
+	if s, ok := scalarString(value); ok {
+		if mapped, ok := mapping[s]; ok {
+			return mapped
+		}
+	}
+	return value
+}
+
+func scalarString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
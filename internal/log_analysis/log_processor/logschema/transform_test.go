@@ -0,0 +1,186 @@
+package logschema
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestBuildTransform(t *testing.T) {
+	assert := require.New(t)
+	schema := &ValueSchema{
+		Type: TypeObject,
+		Fields: []FieldSchema{
+			{
+				Name:        "id",
+				ValueSchema: ValueSchema{Type: TypeString},
+				Transform:   &Transform{Extract: `^id-\d+$`},
+			},
+			{
+				Name: "items",
+				ValueSchema: ValueSchema{
+					Type: TypeArray,
+					Element: &ValueSchema{
+						Type: TypeObject,
+						Fields: []FieldSchema{
+							{
+								Name:        "code",
+								ValueSchema: ValueSchema{Type: TypeInt},
+								Transform:   &Transform{ValueMap: map[string]string{"ok": "200"}, Default: "0"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	transform, err := BuildTransform(schema)
+	assert.NoError(err)
+	assert.NotNil(transform)
+	actual, err := transform.PreProcessLog(`{"id":"id-42","items":[{"code":"ok"},{"code":404},{}]}`)
+	assert.NoError(err)
+	assert.JSONEq(`{"id":"id-42","items":[{"code":"200"},{"code":404},{"code":"0"}]}`, actual)
+	actual, err = transform.PreProcessLog(`{"id":"foo"}`)
+	assert.NoError(err)
+	assert.JSONEq(`{}`, actual)
+
+	transform, err = BuildTransform(&ValueSchema{
+		Type:   TypeObject,
+		Fields: []FieldSchema{{Name: "foo", ValueSchema: ValueSchema{Type: TypeString}}},
+	})
+	assert.NoError(err)
+	assert.Nil(transform)
+	// Empty transforms do not need a preprocessor
+	transform, err = BuildTransform(&ValueSchema{
+		Type: TypeObject,
+		Fields: []FieldSchema{{
+			Name:        "foo",
+			ValueSchema: ValueSchema{Type: TypeArray, Element: &ValueSchema{Type: TypeString}},
+			Transform:   &Transform{},
+		}},
+	})
+	assert.NoError(err)
+	assert.Nil(transform)
+
+	_, err = BuildTransform(&ValueSchema{
+		Type: TypeObject,
+		Fields: []FieldSchema{{
+			Name:        "foo",
+			ValueSchema: ValueSchema{Type: TypeString},
+			Transform:   &Transform{Split: ","},
+		}},
+	})
+	assert.Error(err)
+
+	transform, err = BuildTransform(&ValueSchema{
+		Type: TypeObject,
+		Fields: []FieldSchema{{
+			Name:        "foo",
+			ValueSchema: ValueSchema{Type: TypeJSON},
+			Transform:   &Transform{ParseJSON: true},
+		}},
+	})
+	assert.NoError(err)
+	_, err = transform.PreProcessLog(`{"foo":"{invalid"}`)
+	assert.Error(err)
+}
+
+func TestDiffTransform(t *testing.T) {
+	assert := require.New(t)
+	from := &Schema{
+		Fields: []FieldSchema{{Name: "foo", ValueSchema: ValueSchema{Type: TypeString}}},
+	}
+	to := from.Clone()
+	to.Fields[0].Transform = &Transform{Default: "bar"}
+	changes, err := Diff(from, to)
+	assert.NoError(err)
+	assert.Equal([]Change{{
+		Type: UpdateFieldTransform,
+		Path: []string{"Fields", "foo", "Transform"},
+		From: (*Transform)(nil),
+		To:   &Transform{Default: "bar"},
+	}}, changes)
+}
+
+func TestStringConstraints(t *testing.T) {
+	assert := require.New(t)
+	schema := &ValueSchema{
+		Type: TypeObject,
+		Fields: []FieldSchema{
+			{
+				Name:        "level",
+				ValueSchema: ValueSchema{Type: TypeString, Enum: []string{"info", "error"}},
+			},
+			{
+				Name: "labels",
+				ValueSchema: ValueSchema{
+					Type:    TypeMap,
+					Element: &ValueSchema{Type: TypeString, Pattern: `^[a-z]+$`},
+				},
+			},
+		},
+	}
+	transform, err := BuildTransform(schema)
+	assert.NoError(err)
+	actual, err := transform.PreProcessLog(`{"level":"info","labels":{"app":"web"}}`)
+	assert.NoError(err)
+	assert.JSONEq(`{"level":"info","labels":{"app":"web"}}`, actual)
+
+	// Values that violate a constraint are reported and the event is kept
+	core, logs := observer.New(zap.WarnLevel)
+	defer zap.ReplaceGlobals(zap.New(core))()
+	actual, err = transform.PreProcessLog(`{"level":"debug","labels":{"app":"Web"}}`)
+	assert.NoError(err)
+	assert.JSONEq(`{"level":"debug","labels":{"app":"Web"}}`, actual)
+	assert.Equal(1, logs.FilterField(zap.String("field", "level")).Len())
+	assert.Equal(1, logs.FilterField(zap.String("field", "labels.*")).Len())
+}
+
+func TestDiffConstraints(t *testing.T) {
+	assert := require.New(t)
+	from := &Schema{
+		Fields: []FieldSchema{
+			{Name: "foo", ValueSchema: ValueSchema{Type: TypeString}},
+			{Name: "bar", ValueSchema: ValueSchema{Type: TypeMap, Element: &ValueSchema{Type: TypeString}}},
+		},
+	}
+	to := from.Clone()
+	to.Fields[0].Enum = []string{"a"}
+	to.Fields[1].Element.Pattern = "^a"
+	changes, err := Diff(from, to)
+	assert.NoError(err)
+	assert.Equal([]Change{
+		{
+			Type: UpdateValueMeta,
+			Path: []string{"Fields", "foo", "Enum"},
+			From: []string(nil),
+			To:   []string{"a"},
+		},
+		{
+			Type: UpdateValueMeta,
+			Path: []string{"Fields", "bar", "*", "Pattern"},
+			From: "",
+			To:   "^a",
+		},
+	}, changes)
+}
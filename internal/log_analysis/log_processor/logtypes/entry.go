@@ -27,6 +27,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/panther-labs/panther/internal/log_analysis/awsglue/glueschema"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/normalize"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 )
 
@@ -43,6 +44,56 @@ type Entry interface {
 	Group
 }
 
+// FramedEntry is implemented by entries that need a specific framing to split log data into log entries.
+type FramedEntry interface {
+	Framing() *pantherlog.FramingConfig
+}
+
+// Framing returns the framing configuration of an entry or nil if the entry uses the default line framing.
+func Framing(e Entry) *pantherlog.FramingConfig {
+	if framed, ok := e.(FramedEntry); ok {
+		return framed.Framing()
+	}
+	return nil
+}
+
+// NormalizedEntry is implemented by entries that project their events onto the normalized schema.
+type NormalizedEntry interface {
+	Normalizer() *normalize.Normalizer
+}
+
+// Normalizer returns the normalizer of an entry or nil if the entry has no normalization mapping.
+func Normalizer(e Entry) *normalize.Normalizer {
+	if n, ok := e.(NormalizedEntry); ok {
+		return n.Normalizer()
+	}
+	return nil
+}
+
+// DispatchedEntry is implemented by entries whose log entries are parsed by a parser shared with other log types.
+// The shared parser reads the log type of each log entry once (i.e. the event_type of Suricata EVE records) so that
+// log entries are not tried by the parsers of all the log types.
+type DispatchedEntry interface {
+	Dispatcher() Dispatcher
+}
+
+// Dispatcher creates parsers that dispatch log entries to the parsers of their log types.
+type Dispatcher interface {
+	// Name identifies the parsers of the dispatcher
+	Name() string
+	// NewDispatchParser creates a parser for the log entries of some of the dispatched log types.
+	// Log entries of other log types are rejected.
+	NewDispatchParser(logTypes []string) (pantherlog.LogParser, error)
+}
+
+// DispatcherOf returns the dispatcher of an entry or nil if the entry is parsed by its own parser.
+func DispatcherOf(e Entry) Dispatcher {
+	if d, ok := e.(DispatchedEntry); ok {
+		return d.Dispatcher()
+	}
+	return nil
+}
+
 // EntryBuilder builds a new entry.
 // It is used by various entry configurations (Config, ConfigJSON).
 type EntryBuilder interface {
@@ -111,6 +162,8 @@ type ConfigJSON struct {
 	NextRowID       func() string
 	Now             func() time.Time
 	ExtraIndicators pantherlog.FieldSet
+	Framing         *pantherlog.FramingConfig
+	Normalization   *normalize.Mapping
 }
 
 // BuildEntry implements EntryBuilder interface
@@ -137,6 +190,8 @@ func (c ConfigJSON) BuildEntry() (Entry, error) {
 			Now:       c.Now,
 			NextRowID: c.NextRowID,
 		},
+		Framing:       c.Framing,
+		Normalization: c.Normalization,
 	}
 	return config.BuildEntry()
 }
@@ -150,6 +205,12 @@ type Config struct {
 	ReferenceURL string
 	Schema       interface{}
 	NewParser    pantherlog.LogParserFactory
+	// Framing is an optional framing configuration for splitting log data into log entries
+	Framing *pantherlog.FramingConfig
+	// Normalization is an optional mapping of the events onto the normalized schema (see package normalize)
+	Normalization *normalize.Mapping
+	// Dispatcher optionally parses the log entries of this log type with a parser shared with other log types
+	Dispatcher Dispatcher
 }
 
 func (c *Config) Describe() Desc {
@@ -175,6 +236,9 @@ func (c *Config) Validate() error {
 	if c.NewParser == nil {
 		return errors.New("nil parser factory")
 	}
+	if err := c.Framing.Validate(); err != nil {
+		return errors.WithMessagef(err, "invalid framing for log type %q", desc.Name)
+	}
 	return nil
 }
 
@@ -183,13 +247,24 @@ func (c Config) BuildEntry() (Entry, error) {
 	if err := c.Validate(); err != nil {
 		return nil, err
 	}
-	return newEntry(c.Describe(), c.Schema, c.NewParser), nil
+	normalizer, err := normalize.New(c.Normalization, c.Schema)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid normalization for log type %q", c.Name)
+	}
+	e := newEntry(c.Describe(), c.Schema, c.NewParser)
+	e.framing = c.Framing
+	e.normalizer = normalizer
+	e.dispatcher = c.Dispatcher
+	return e, nil
 }
 
 type entry struct {
-	desc      Desc
-	schema    interface{}
-	newParser pantherlog.FactoryFunc
+	desc       Desc
+	schema     interface{}
+	newParser  pantherlog.FactoryFunc
+	framing    *pantherlog.FramingConfig
+	normalizer *normalize.Normalizer
+	dispatcher Dispatcher
 }
 
 func newEntry(desc Desc, schema interface{}, fac pantherlog.LogParserFactory) *entry {
@@ -231,6 +306,21 @@ func (e *entry) Schema() interface{} {
 	return e.schema
 }
 
+// Framing implements FramedEntry
+func (e *entry) Framing() *pantherlog.FramingConfig {
+	return e.framing
+}
+
+// Normalizer implements NormalizedEntry
+func (e *entry) Normalizer() *normalize.Normalizer {
+	return e.normalizer
+}
+
+// Dispatcher implements DispatchedEntry
+func (e *entry) Dispatcher() Dispatcher {
+	return e.dispatcher
+}
+
 // Parser returns a new pantherlog.LogParser
 func (e *entry) NewParser(params interface{}) (pantherlog.LogParser, error) {
 	return e.newParser(params)
@@ -0,0 +1,299 @@
+// Package logsamples loads and generates sample log entries for log types.
+// It is used to benchmark parsers, so it does not depend on package testing.
+package logsamples
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// Samples holds sample log entries by log type
+type Samples map[string][]string
+
+// Add adds sample log entries for a log type
+func (s Samples) Add(logType string, entries ...string) {
+	s[logType] = append(s[logType], entries...)
+}
+
+// Limit keeps at most max samples for each log type
+func (s Samples) Limit(max int) {
+	for logType, samples := range s {
+		if len(samples) > max {
+			s[logType] = samples[:max]
+		}
+	}
+}
+
+// maxSampleFileLines is the number of lines read from each sample file
+const maxSampleFileLines = 100
+
+// LoadSamples loads sample log entries for the log types of a group from all `testdata` directories under dir.
+// The inputs of YAML test cases that produce results are samples of the log type of the test case.
+// The lines of other files (i.e. `*_samples.jsonl`) are samples of every log type that parses them.
+func LoadSamples(dir string, group logtypes.Group) (Samples, error) {
+	samples := Samples{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Base(filepath.Dir(path)) != "testdata" {
+			return nil
+		}
+		switch filepath.Ext(path) {
+		case ".yml", ".yaml":
+			return loadSamplesYAML(samples, path, group)
+		case ".jsonl", ".log":
+			return loadSamplesFile(samples, path, group)
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+type sampleTestCase struct {
+	Input   string   `yaml:"input"`
+	LogType string   `yaml:"logType"`
+	Result  string   `yaml:"result"`
+	Results []string `yaml:"results"`
+}
+
+func loadSamplesYAML(samples Samples, filename string, group logtypes.Group) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	dec := yaml.NewDecoder(f)
+	for {
+		var tc sampleTestCase
+		if err := dec.Decode(&tc); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return errors.Wrapf(err, "failed to read test cases from %q", filename)
+		}
+		if tc.Result == "" && len(tc.Results) == 0 {
+			continue
+		}
+		if group.Find(tc.LogType) == nil {
+			continue
+		}
+		samples.Add(tc.LogType, tc.Input)
+	}
+}
+
+func loadSamplesFile(samples Samples, filename string, group logtypes.Group) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() && len(lines) < maxSampleFileLines {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrapf(err, "failed to read samples from %q", filename)
+	}
+	for _, entry := range group.Entries() {
+		parser, err := entry.NewParser(nil)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create %q parser", entry.String())
+		}
+		for _, line := range lines {
+			if results, err := TryParse(parser, line); err == nil && len(results) > 0 {
+				samples.Add(entry.String(), line)
+			}
+		}
+	}
+	return nil
+}
+
+// TryParse parses a log entry recovering from parser panics the same way the classifier does
+func TryParse(parser pantherlog.LogParser, log string) (results []*pantherlog.Result, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("parser panic: %v", r)
+		}
+	}()
+	return parser.ParseLog(log)
+}
+
+// AverageSize returns the average size in bytes of samples
+func AverageSize(samples []string) int64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	size := 0
+	for _, sample := range samples {
+		size += len(sample)
+	}
+	return int64(size / len(samples))
+}
+
+// Generate generates sample log entries for a log type from its schema.
+// The fields of the schema are filled with random values and the event is serialized to JSON,
+// so only log types with a JSON parser that accepts all such values get samples.
+// Generated entries that the parser of the log type rejects are skipped.
+func Generate(entry logtypes.Entry, n int) ([]string, error) {
+	parser, err := entry.NewParser(nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create %q parser", entry.String())
+	}
+	typ := reflect.TypeOf(entry.Schema())
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, errors.Errorf("invalid %q schema type %s", entry.String(), typ)
+	}
+	g := generator{
+		rnd: rand.New(rand.NewSource(int64(n))),
+		now: time.Now().UTC().Truncate(time.Second),
+	}
+	var samples []string
+	for i := 0; i < n; i++ {
+		event := reflect.New(typ)
+		g.fill(event.Elem(), 0)
+		sample, err := marshalSample(event.Interface())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to serialize %q sample", entry.String())
+		}
+		if results, err := TryParse(parser, sample); err != nil || len(results) == 0 {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+// marshalSample serializes an event without the fields added by Panther
+func marshalSample(event interface{}) (string, error) {
+	data, err := pantherlog.ConfigJSON().Marshal(event)
+	if err != nil {
+		return "", err
+	}
+	var fields map[string]jsoniter.RawMessage
+	if err := jsoniter.Unmarshal(data, &fields); err != nil {
+		return "", err
+	}
+	for name := range fields {
+		if strings.HasPrefix(name, pantherlog.FieldPrefixJSON) {
+			delete(fields, name)
+		}
+	}
+	return jsoniter.MarshalToString(fields)
+}
+
+// maxDepth limits the nesting of generated values for recursive types
+const maxDepth = 6
+
+var (
+	typTime       = reflect.TypeOf(time.Time{})
+	typRawMessage = reflect.TypeOf(jsoniter.RawMessage{})
+)
+
+type generator struct {
+	rnd *rand.Rand
+	now time.Time
+}
+
+// fill sets random values to the exported fields of v
+func (g *generator) fill(v reflect.Value, depth int) {
+	if depth > maxDepth {
+		return
+	}
+	typ := v.Type()
+	switch {
+	case typ.ConvertibleTo(typTime) && typ.Kind() == reflect.Struct:
+		v.Set(reflect.ValueOf(g.now).Convert(typ))
+		return
+	case typ.ConvertibleTo(typRawMessage):
+		v.Set(reflect.ValueOf(jsoniter.RawMessage(`{}`)).Convert(typ))
+		return
+	}
+	switch typ.Kind() {
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if field := typ.Field(i); field.PkgPath == "" {
+				g.fill(v.Field(i), depth+1)
+			}
+		}
+	case reflect.Ptr:
+		v.Set(reflect.New(typ.Elem()))
+		g.fill(v.Elem(), depth+1)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(typ, 1, 1))
+		g.fill(v.Index(0), depth+1)
+	case reflect.Map:
+		if typ.Key().Kind() != reflect.String {
+			return
+		}
+		key := reflect.New(typ.Key()).Elem()
+		g.fill(key, depth+1)
+		value := reflect.New(typ.Elem()).Elem()
+		g.fill(value, depth+1)
+		v.Set(reflect.MakeMap(typ))
+		v.SetMapIndex(key, value)
+	case reflect.String:
+		v.SetString(g.word())
+	case reflect.Bool:
+		// Optional values (i.e. pantherlog.String) are only serialized if they exist
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(g.rnd.Int63n(100))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(g.rnd.Int63n(100)))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(g.rnd.Float64() * 100)
+	}
+}
+
+const letters = "abcdefghijklmnopqrstuvwxyz"
+
+func (g *generator) word() string {
+	b := make([]byte, 4+g.rnd.Intn(8))
+	for i := range b {
+		b[i] = letters[g.rnd.Intn(len(letters))]
+	}
+	return string(b)
+}
@@ -0,0 +1,103 @@
+package logsamples
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll
+type testEvent struct {
+	Time    pantherlog.Time       `json:"time" tcodec:"rfc3339" event_time:"true" validate:"required" description:"time"`
+	Name    pantherlog.String     `json:"name" validate:"required" description:"name"`
+	Count   pantherlog.Int64      `json:"count" description:"count"`
+	Tags    []string              `json:"tags" description:"tags"`
+	Labels  map[string]string     `json:"labels" description:"labels"`
+	Details pantherlog.RawMessage `json:"details" description:"details"`
+	Nested  *testNested           `json:"nested" description:"nested"`
+}
+
+type testNested struct {
+	IP pantherlog.String `json:"ip" panther:"ip" description:"ip"`
+}
+
+func TestGenerate(t *testing.T) {
+	entry := logtypes.MustBuild(logtypes.ConfigJSON{
+		Name:         "Test.Event",
+		Description:  "Test log type",
+		ReferenceURL: "-",
+		NewEvent: func() interface{} {
+			return &testEvent{}
+		},
+	})
+	samples, err := Generate(entry, 3)
+	require.NoError(t, err)
+	require.Len(t, samples, 3)
+	parser, err := entry.NewParser(nil)
+	require.NoError(t, err)
+	for _, sample := range samples {
+		require.NotContains(t, sample, pantherlog.FieldPrefixJSON)
+		results, err := parser.ParseLog(sample)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		event := results[0].Event.(*testEvent)
+		require.NotEmpty(t, event.Name.Value)
+		require.Len(t, event.Tags, 1)
+		require.Len(t, event.Labels, 1)
+		require.NotNil(t, event.Nested)
+	}
+
+	// Samples the parser rejects are skipped
+	entry = logtypes.MustBuild(logtypes.ConfigJSON{
+		Name:         "Test.Enum",
+		Description:  "Test log type",
+		ReferenceURL: "-",
+		NewEvent: func() interface{} {
+			return &struct {
+				Kind string `json:"kind" validate:"oneof=foo bar" description:"kind"`
+			}{}
+		},
+	})
+	samples, err = Generate(entry, 3)
+	require.NoError(t, err)
+	require.Empty(t, samples)
+}
+
+type panicParser struct{}
+
+func (panicParser) ParseLog(_ string) ([]*pantherlog.Result, error) {
+	panic("boom")
+}
+
+func TestTryParse(t *testing.T) {
+	results, err := TryParse(panicParser{}, "foo")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "boom")
+	require.Nil(t, results)
+}
+
+func TestAverageSize(t *testing.T) {
+	require.Equal(t, int64(2), AverageSize([]string{"a", "abc"}))
+	require.Equal(t, int64(0), AverageSize(nil))
+}
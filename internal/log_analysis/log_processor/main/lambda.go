@@ -24,14 +24,20 @@ import (
 
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"gopkg.in/go-playground/validator.v9"
 
 	"github.com/panther-labs/panther/internal/compliance/snapshotlogs"
 	"github.com/panther-labs/panther/internal/core/logtypesapi"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/geoip"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/deadletters"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/enrichment"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/masking"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
 	"github.com/panther-labs/panther/pkg/lambdalogger"
 )
@@ -42,11 +48,87 @@ const (
 	logTypeMaxAge                  = time.Minute
 )
 
+var (
+	// enricher is shared by all invocations so lookup tables are reloaded only when they expire,
+	// it is loaded by the first invocation (see loadEnricher)
+	enricher *enrichment.Enricher
+	// masker is shared by all invocations so the configuration is loaded once,
+	// it is loaded by the first invocation (see loadMasker)
+	masker *masking.Masker
+	// geoResolver is shared by all invocations so databases are loaded once
+	geoResolver *geoip.Resolver
+	// deadLetters stores the lines that fail classification, it is flushed at the end of each invocation
+	deadLetters *deadletters.Store
+)
+
 func main() {
 	common.Setup()
+	geoResolver = mustLoadGeoIP()
+	deadLetters = newDeadLetterStore()
 	lambda.Start(handle)
 }
 
+func mustLoadGeoIP() *geoip.Resolver {
+	r, err := geoip.Load(geoip.Config{
+		CountryDatabase: common.Config.GeoipCountryDatabase,
+		ASNDatabase:     common.Config.GeoipAsnDatabase,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// loadEnricher loads the enrichment configuration if it is not loaded yet.
+// An invalid or unreachable configuration fails the invocation and is loaded again by the next one.
+func loadEnricher(ctx context.Context) error {
+	location := common.Config.EnrichmentConfig
+	if location == "" || enricher != nil {
+		return nil
+	}
+	config, err := enrichment.LoadConfig(ctx, common.S3Client, location)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to load enrichment config %s", location)
+	}
+	e, err := enrichment.New(config, common.S3Client)
+	if err != nil {
+		return errors.WithMessagef(err, "invalid enrichment config %s", location)
+	}
+	enricher = e
+	return nil
+}
+
+// loadMasker loads the masking configuration if it is not loaded yet.
+// An invalid or unreachable configuration fails the invocation and is loaded again by the next one.
+func loadMasker(ctx context.Context) error {
+	location := common.Config.MaskingConfig
+	if location == "" || masker != nil {
+		return nil
+	}
+	config, err := masking.LoadConfig(ctx, common.S3Client, location)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to load masking config %s", location)
+	}
+	m, err := masking.New(config, []byte(common.Config.MaskingHashKey))
+	if err != nil {
+		return errors.WithMessagef(err, "invalid masking config %s", location)
+	}
+	masker = m
+	return nil
+}
+
+func newDeadLetterStore() *deadletters.Store {
+	if !common.Config.StoreDeadLetters {
+		return nil
+	}
+	return &deadletters.Store{
+		Uploader: s3manager.NewUploaderWithClient(common.S3Client),
+		SNSAPI:   common.SnsClient,
+		Bucket:   common.Config.ProcessedDataBucket,
+		TopicARN: common.Config.SnsTopicARN,
+	}
+}
+
 func handle(ctx context.Context) error {
 	lambdalogger.ConfigureGlobal(ctx, nil)
 	return process(ctx, defaultScalingDecisionInterval)
@@ -56,17 +138,25 @@ func process(ctx context.Context, scalingDecisionInterval time.Duration) (err er
 	lc, _ := lambdacontext.FromContext(ctx)
 	operation := common.OpLogManager.Start(lc.InvokedFunctionArn, common.OpLogLambdaServiceDim).WithMemUsed(lambdacontext.MemoryLimitInMB)
 
-	// Create cancellable deadline for Scaling Decisions go routine
-	scalingCtx, cancelScaling := context.WithCancel(ctx)
-	// runs in the background, periodically polling the queue to make scaling decisions
-	go processor.RunScalingDecisions(scalingCtx, common.SqsClient, common.LambdaClient, scalingDecisionInterval)
-
 	var sqsMessageCount int
 	defer func() {
-		cancelScaling()
 		operation.Stop().Log(err, zap.Int("sqsMessageCount", sqsMessageCount))
 	}()
 
+	if err = loadEnricher(ctx); err != nil {
+		return err
+	}
+	// Events are never stored unmasked, so the invocation fails if the masking configuration cannot be loaded
+	if err = loadMasker(ctx); err != nil {
+		return err
+	}
+
+	// Create cancellable deadline for Scaling Decisions go routine
+	scalingCtx, cancelScaling := context.WithCancel(ctx)
+	defer cancelScaling()
+	// runs in the background, periodically polling the queue to make scaling decisions
+	go processor.RunScalingDecisions(scalingCtx, common.SqsClient, common.LambdaClient, scalingDecisionInterval)
+
 	// Chain default registry and customlogs resolver
 	logTypesResolver := logtypes.ChainResolvers(
 		registry.NativeLogTypesResolver(),
@@ -80,6 +170,11 @@ func process(ctx context.Context, scalingDecisionInterval time.Duration) (err er
 		}),
 	)
 
-	sqsMessageCount, err = processor.PollEvents(ctx, common.SqsClient, logTypesResolver)
+	sqsMessageCount, err = processor.PollEvents(ctx, common.SqsClient, logTypesResolver,
+		processor.EnrichEvents(enricher),
+		processor.MaskEvents(masker),
+		processor.ResolveGeoIP(geoResolver),
+		processor.StoreDeadLetters(deadLetters),
+	)
 	return err
 }
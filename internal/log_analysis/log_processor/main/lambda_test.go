@@ -92,3 +92,47 @@ func TestProcessOpLog(t *testing.T) {
 	assert.Equal(t, common.OpLogLambdaServiceDim.String, serviceDim)
 	sqsMock.AssertExpectations(t)
 }
+
+func TestProcessEnrichmentConfigError(t *testing.T) {
+	common.Config.EnrichmentConfig = "/does/not/exist.json"
+	defer func() {
+		common.Config.EnrichmentConfig = ""
+	}()
+
+	logs := mockLogger()
+	functionName := "myfunction"
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+		InvokedFunctionArn: functionName,
+	})
+
+	// The invocation fails without polling the queue
+	err := process(ctx, time.Minute)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to load enrichment config")
+	require.Nil(t, enricher)
+	message := common.OpLogNamespace + ":" + common.OpLogComponent + ":" + functionName
+	require.Equal(t, 1, len(logs.FilterMessage(message).All()))
+	assert.Equal(t, zapcore.ErrorLevel, logs.FilterMessage(message).All()[0].Level)
+}
+
+func TestProcessMaskingConfigError(t *testing.T) {
+	common.Config.MaskingConfig = "/does/not/exist.json"
+	defer func() {
+		common.Config.MaskingConfig = ""
+	}()
+
+	logs := mockLogger()
+	functionName := "myfunction"
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+		InvokedFunctionArn: functionName,
+	})
+
+	// The invocation fails without polling the queue
+	err := process(ctx, time.Minute)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to load masking config")
+	require.Nil(t, masker)
+	message := common.OpLogNamespace + ":" + common.OpLogComponent + ":" + functionName
+	require.Equal(t, 1, len(logs.FilterMessage(message).All()))
+	assert.Equal(t, zapcore.ErrorLevel, logs.FilterMessage(message).All()[0].Level)
+}
@@ -0,0 +1,421 @@
+// Package normalize projects events of different log types onto a common schema.
+//
+// Log types declare a Mapping alongside their description (see logtypes.Config).
+// The mapping is checked against the schema of the log type when the log type is registered and
+// the log processor uses it to add the normalized event as the p_normalized field of each event.
+// Events are normalized after masking, so normalized fields only ever hold masked values
+// and masking rules for p_normalized fields are not needed.
+package normalize
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// Event classes of normalized events.
+// The names and unique ids follow the OCSF event classes.
+const (
+	ClassAccountChange   = "account_change"
+	ClassAuthentication  = "authentication"
+	ClassNetworkActivity = "network_activity"
+	ClassHTTPActivity    = "http_activity"
+	ClassDNSActivity     = "dns_activity"
+	ClassAPIActivity     = "api_activity"
+)
+
+var classUIDs = map[string]int64{
+	ClassAccountChange:   3001,
+	ClassAuthentication:  3002,
+	ClassNetworkActivity: 4001,
+	ClassHTTPActivity:    4002,
+	ClassDNSActivity:     4003,
+	ClassAPIActivity:     6003,
+}
+
+// ClassUID returns the unique id of an event class
+func ClassUID(class string) (int64, bool) {
+	uid, ok := classUIDs[class]
+	return uid, ok
+}
+
+// Activities of authentication events
+const (
+	ActivityLogon  = "logon"
+	ActivityLogoff = "logoff"
+)
+
+// Outcomes of the activity of normalized events
+const (
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+	StatusOther   = "other"
+)
+
+// Mapping projects the events of a log type onto the normalized schema.
+type Mapping struct {
+	// Fields maps normalized fields to event fields for all rules (i.e. "src_endpoint.ip": "client.ipAddress").
+	// Fields are dot separated JSON paths, array elements are selected by their index.
+	Fields map[string]string
+	// Rules are checked in order and the first rule that matches an event decides its class.
+	// Events that do not match any rule are not normalized.
+	Rules []Rule
+}
+
+// Rule normalizes the events that match all of its conditions to an event class.
+type Rule struct {
+	Class    string
+	Activity string
+	Status   string
+	// Match lists the conditions that an event should satisfy for the rule to apply.
+	// A rule without conditions matches all events.
+	Match []Match
+	// Fields maps normalized fields to event fields for the events matching the rule.
+	// Values found in these fields override the values of the mapping fields.
+	Fields map[string]string
+}
+
+// Match is a condition on the value of an event field.
+// Array values match if any of their elements matches.
+type Match struct {
+	Field string
+	// Values lists the accepted values of the field.
+	// If no values are set, the field should have a non-empty value.
+	Values []string
+}
+
+// Normalizer projects the JSON of events onto the normalized schema using a mapping.
+type Normalizer struct {
+	fields []field
+	rules  []rule
+}
+
+type rule struct {
+	normalized pantherlog.Normalized
+	match      []Match
+	fields     []field
+}
+
+// field copies the value of an event field to a normalized field
+type field struct {
+	src  string
+	dst  []int
+	kind reflect.Kind
+}
+
+// New compiles a mapping for the events of a schema.
+// It checks that all fields of the mapping exist in the schema and in the normalized schema.
+// A nil mapping returns a nil Normalizer without error.
+func New(m *Mapping, schema interface{}) (*Normalizer, error) {
+	if m == nil {
+		return nil, nil
+	}
+	if len(m.Rules) == 0 {
+		return nil, errors.New("normalization mapping without rules")
+	}
+	schemaType := reflect.TypeOf(schema)
+	fields, err := compileFields(m.Fields, schemaType)
+	if err != nil {
+		return nil, err
+	}
+	n := Normalizer{
+		fields: fields,
+		rules:  make([]rule, 0, len(m.Rules)),
+	}
+	for i := range m.Rules {
+		r := &m.Rules[i]
+		uid, ok := classUIDs[r.Class]
+		if !ok {
+			return nil, errors.Errorf("rule %d: invalid event class %q", i, r.Class)
+		}
+		switch r.Status {
+		case "", StatusSuccess, StatusFailure, StatusOther:
+		default:
+			return nil, errors.Errorf("rule %d: invalid status %q", i, r.Status)
+		}
+		for _, match := range r.Match {
+			if _, err := lookupPath(schemaType, match.Field); err != nil {
+				return nil, errors.WithMessagef(err, "rule %d: invalid match field", i)
+			}
+		}
+		fields, err := compileFields(r.Fields, schemaType)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "rule %d", i)
+		}
+		n.rules = append(n.rules, rule{
+			normalized: pantherlog.Normalized{
+				Class:    r.Class,
+				ClassUID: uid,
+				Activity: r.Activity,
+				Status:   r.Status,
+			},
+			match:  r.Match,
+			fields: fields,
+		})
+	}
+	return &n, nil
+}
+
+// Classes returns the distinct event classes of the normalized events in order
+func (n *Normalizer) Classes() []string {
+	if n == nil {
+		return nil
+	}
+	var classes []string
+	for i := range n.rules {
+		class := n.rules[i].normalized.Class
+		if index := sort.SearchStrings(classes, class); index == len(classes) || classes[index] != class {
+			classes = append(classes, class)
+			sort.Strings(classes)
+		}
+	}
+	return classes
+}
+
+// Normalize projects the JSON of an event onto the normalized schema.
+// It returns nil if the event does not match any rule.
+func (n *Normalizer) Normalize(data []byte) *pantherlog.Normalized {
+	if n == nil {
+		return nil
+	}
+	for i := range n.rules {
+		r := &n.rules[i]
+		if !r.Match(data) {
+			continue
+		}
+		normalized := r.normalized
+		dst := reflect.ValueOf(&normalized).Elem()
+		for _, f := range n.fields {
+			f.Copy(dst, data)
+		}
+		for _, f := range r.fields {
+			f.Copy(dst, data)
+		}
+		return &normalized
+	}
+	return nil
+}
+
+// Match checks if the JSON of an event satisfies all conditions of the rule
+func (r *rule) Match(data []byte) bool {
+	for i := range r.match {
+		if !matchValue(gjson.GetBytes(data, r.match[i].Field), r.match[i].Values) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchValue(value gjson.Result, values []string) bool {
+	if value.IsArray() {
+		matched := false
+		value.ForEach(func(_, el gjson.Result) bool {
+			matched = matchValue(el, values)
+			return !matched
+		})
+		return matched
+	}
+	if !value.Exists() || value.Type == gjson.Null {
+		return false
+	}
+	if len(values) == 0 {
+		return value.String() != ""
+	}
+	s := value.String()
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Copy sets the normalized field to the value of the event field.
+// Only the first element of array values is used and empty values are skipped.
+func (f *field) Copy(dst reflect.Value, data []byte) {
+	value := gjson.GetBytes(data, f.src)
+	if value.IsArray() {
+		elements := value.Array()
+		if len(elements) == 0 {
+			return
+		}
+		value = elements[0]
+	}
+	if !value.Exists() || value.Type == gjson.Null {
+		return
+	}
+	var s string
+	var n int64
+	if f.kind == reflect.Int64 {
+		if n = value.Int(); n == 0 {
+			return
+		}
+	} else if s = value.String(); s == "" {
+		return
+	}
+	for _, index := range f.dst {
+		if dst.Kind() == reflect.Ptr {
+			if dst.IsNil() {
+				dst.Set(reflect.New(dst.Type().Elem()))
+			}
+			dst = dst.Elem()
+		}
+		dst = dst.Field(index)
+	}
+	if f.kind == reflect.Int64 {
+		dst.SetInt(n)
+	} else {
+		dst.SetString(s)
+	}
+}
+
+var typNormalized = reflect.TypeOf(pantherlog.Normalized{})
+
+func compileFields(mapping map[string]string, schema reflect.Type) ([]field, error) {
+	// Sort the fields so that errors are reported consistently
+	names := make([]string, 0, len(mapping))
+	for name := range mapping {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fields := make([]field, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case "class", "class_uid":
+			return nil, errors.Errorf("normalized field %q is set by the rules", name)
+		}
+		dst, kind, err := normalizedField(name)
+		if err != nil {
+			return nil, err
+		}
+		src := mapping[name]
+		if _, err := lookupPath(schema, src); err != nil {
+			return nil, errors.WithMessagef(err, "invalid event field for normalized field %q", name)
+		}
+		fields = append(fields, field{
+			src:  src,
+			dst:  dst,
+			kind: kind,
+		})
+	}
+	return fields, nil
+}
+
+// normalizedField returns the index and kind of a scalar field of the normalized schema
+func normalizedField(path string) ([]int, reflect.Kind, error) {
+	typ := typNormalized
+	var index []int
+	for _, name := range strings.Split(path, ".") {
+		typ = indirect(typ)
+		if typ.Kind() != reflect.Struct {
+			return nil, 0, errors.Errorf("invalid normalized field %q", path)
+		}
+		f, ok := lookupField(typ, name)
+		if !ok || len(f.Index) != 1 {
+			return nil, 0, errors.Errorf("invalid normalized field %q", path)
+		}
+		index = append(index, f.Index[0])
+		typ = f.Type
+	}
+	switch kind := typ.Kind(); kind {
+	case reflect.String, reflect.Int64:
+		return index, kind, nil
+	default:
+		return nil, 0, errors.Errorf("normalized field %q is not a value", path)
+	}
+}
+
+// lookupPath resolves the type of the field at a JSON path of a schema struct
+func lookupPath(typ reflect.Type, path string) (reflect.Type, error) {
+	if path == "" {
+		return nil, errors.New("empty field path")
+	}
+	for _, name := range strings.Split(path, ".") {
+		typ = indirect(typ)
+		switch typ.Kind() {
+		case reflect.Struct:
+			f, ok := lookupField(typ, name)
+			if !ok {
+				return nil, errors.Errorf("field %q not found in %q", name, path)
+			}
+			typ = f.Type
+		case reflect.Slice, reflect.Array:
+			// Raw JSON values can have any fields
+			if typ.Elem().Kind() == reflect.Uint8 {
+				return typ, nil
+			}
+			if _, err := strconv.Atoi(name); err != nil {
+				return nil, errors.Errorf("invalid array index %q in %q", name, path)
+			}
+			typ = typ.Elem()
+		case reflect.Map:
+			typ = typ.Elem()
+		case reflect.Interface:
+			return typ, nil
+		default:
+			return nil, errors.Errorf("field %q not found in %q", name, path)
+		}
+	}
+	return typ, nil
+}
+
+// lookupField finds a struct field by its JSON name, including the fields of embedded structs
+func lookupField(typ reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		fieldName := strings.Split(tag, ",")[0]
+		if f.Anonymous && fieldName == "" {
+			if embedded := indirect(f.Type); embedded.Kind() == reflect.Struct {
+				if f, ok := lookupField(embedded, name); ok {
+					return f, true
+				}
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if fieldName == "" {
+			fieldName = f.Name
+		}
+		if fieldName == name {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func indirect(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
@@ -0,0 +1,205 @@
+package normalize
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+type testEvent struct {
+	Action  string                `json:"action"`
+	Result  string                `json:"result"`
+	User    *testUser             `json:"user,omitempty"`
+	Client  testClient            `json:"client"`
+	Tags    []string              `json:"tags,omitempty"`
+	Extra   map[string]string     `json:"extra,omitempty"`
+	Details pantherlog.RawMessage `json:"details,omitempty"`
+	testEmbedded
+}
+
+type testUser struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+}
+
+type testClient struct {
+	IP   string `json:"ip"`
+	Port int    `json:"port"`
+}
+
+type testEmbedded struct {
+	App string `json:"app"`
+}
+
+var testMapping = Mapping{
+	Fields: map[string]string{
+		"actor.user.name":   "user.name",
+		"actor.user.uid":    "user.id",
+		"src_endpoint.ip":   "client.ip",
+		"src_endpoint.port": "client.port",
+		"service.name":      "app",
+	},
+	Rules: []Rule{
+		{
+			Class:    ClassAuthentication,
+			Activity: ActivityLogon,
+			Status:   StatusSuccess,
+			Match: []Match{
+				{Field: "action", Values: []string{"login"}},
+				{Field: "result", Values: []string{"ok"}},
+			},
+		},
+		{
+			Class:    ClassAuthentication,
+			Activity: ActivityLogon,
+			Status:   StatusFailure,
+			Match: []Match{
+				{Field: "action", Values: []string{"login"}},
+			},
+			Fields: map[string]string{
+				"message": "result",
+			},
+		},
+		{
+			Class: ClassAPIActivity,
+			Match: []Match{
+				{Field: "tags", Values: []string{"api"}},
+			},
+		},
+	},
+}
+
+func TestNormalize(t *testing.T) {
+	n, err := New(&testMapping, testEvent{})
+	require.NoError(t, err)
+	require.Equal(t, []string{ClassAPIActivity, ClassAuthentication}, n.Classes())
+
+	normalized := n.Normalize([]byte(`{"action":"login","result":"ok","user":{"name":"alice","id":"42"},"client":{"ip":"1.1.1.1","port":443},"app":"foo"}`))
+	require.Equal(t, &pantherlog.Normalized{
+		Class:    ClassAuthentication,
+		ClassUID: 3002,
+		Activity: ActivityLogon,
+		Status:   StatusSuccess,
+		Actor: &pantherlog.NormalizedActor{
+			User: &pantherlog.NormalizedUser{
+				Name: "alice",
+				UID:  "42",
+			},
+		},
+		SrcEndpoint: &pantherlog.NormalizedEndpoint{
+			IP:   "1.1.1.1",
+			Port: 443,
+		},
+		Service: &pantherlog.NormalizedService{
+			Name: "foo",
+		},
+	}, normalized)
+
+	// Rule fields are added and missing or empty values are skipped
+	normalized = n.Normalize([]byte(`{"action":"login","result":"bad password","user":{"name":""},"client":{"ip":"1.1.1.1"}}`))
+	require.Equal(t, &pantherlog.Normalized{
+		Class:    ClassAuthentication,
+		ClassUID: 3002,
+		Activity: ActivityLogon,
+		Status:   StatusFailure,
+		Message:  "bad password",
+		SrcEndpoint: &pantherlog.NormalizedEndpoint{
+			IP: "1.1.1.1",
+		},
+	}, normalized)
+
+	// Array values match if any element matches
+	normalized = n.Normalize([]byte(`{"action":"list","tags":["foo","api"],"client":{}}`))
+	require.Equal(t, &pantherlog.Normalized{
+		Class:    ClassAPIActivity,
+		ClassUID: 6003,
+	}, normalized)
+
+	require.Nil(t, n.Normalize([]byte(`{"action":"logout","tags":["foo"]}`)))
+}
+
+func TestNormalizeNil(t *testing.T) {
+	n, err := New(nil, testEvent{})
+	require.NoError(t, err)
+	require.Nil(t, n)
+	require.Nil(t, n.Normalize([]byte(`{"action":"login"}`)))
+	require.Empty(t, n.Classes())
+}
+
+func TestNewInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		Name    string
+		Mapping Mapping
+	}{
+		{"no rules", Mapping{}},
+		{"invalid class", Mapping{Rules: []Rule{{Class: "foo"}}}},
+		{"invalid status", Mapping{Rules: []Rule{{Class: ClassAuthentication, Status: "foo"}}}},
+		{"invalid match field", Mapping{Rules: []Rule{{Class: ClassAuthentication, Match: []Match{{Field: "foo"}}}}}},
+		{"empty match field", Mapping{Rules: []Rule{{Class: ClassAuthentication, Match: []Match{{}}}}}},
+		{"invalid event field", Mapping{
+			Fields: map[string]string{"actor.user.name": "user.foo"},
+			Rules:  []Rule{{Class: ClassAuthentication}},
+		}},
+		{"invalid array index", Mapping{
+			Fields: map[string]string{"actor.user.name": "tags.foo"},
+			Rules:  []Rule{{Class: ClassAuthentication}},
+		}},
+		{"invalid normalized field", Mapping{
+			Fields: map[string]string{"actor.foo": "user.name"},
+			Rules:  []Rule{{Class: ClassAuthentication}},
+		}},
+		{"normalized struct field", Mapping{
+			Fields: map[string]string{"actor.user": "user.name"},
+			Rules:  []Rule{{Class: ClassAuthentication}},
+		}},
+		{"normalized class field", Mapping{
+			Fields: map[string]string{"class": "action"},
+			Rules:  []Rule{{Class: ClassAuthentication}},
+		}},
+		{"invalid rule field", Mapping{
+			Rules: []Rule{{Class: ClassAuthentication, Fields: map[string]string{"message": "foo"}}},
+		}},
+	} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := New(&tc.Mapping, &testEvent{})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestLookupPath(t *testing.T) {
+	for _, path := range []string{
+		"action",
+		"user.name",
+		"client.port",
+		"tags.0",
+		"extra.foo",
+		"details.foo.bar",
+		"app",
+	} {
+		_, err := lookupPath(reflect.TypeOf(&testEvent{}), path)
+		require.NoError(t, err, path)
+	}
+}
@@ -0,0 +1,123 @@
+package pantherlog
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Message types of CloudWatch Logs subscription payloads
+const (
+	// CloudWatchLogsDataMessage is the message type of payloads with log events
+	CloudWatchLogsDataMessage = "DATA_MESSAGE"
+	// CloudWatchLogsControlMessage is the message type of payloads sent to check that the destination is reachable
+	CloudWatchLogsControlMessage = "CONTROL_MESSAGE"
+)
+
+// Keys of the source metadata of log events unwrapped from CloudWatch Logs subscription payloads
+const (
+	MetadataLogGroup  = "logGroup"
+	MetadataLogStream = "logStream"
+	MetadataOwner     = "owner"
+)
+
+// CloudWatchLogsPayload is the payload of a CloudWatch Logs subscription delivered by Firehose or Kinesis
+type CloudWatchLogsPayload struct {
+	MessageType         string               `json:"messageType"`
+	Owner               string               `json:"owner"`
+	LogGroup            string               `json:"logGroup"`
+	LogStream           string               `json:"logStream"`
+	SubscriptionFilters []string             `json:"subscriptionFilters"`
+	LogEvents           []CloudWatchLogEvent `json:"logEvents"`
+}
+
+// CloudWatchLogEvent is a log event of a CloudWatch Logs subscription payload
+type CloudWatchLogEvent struct {
+	ID        string `json:"id"`
+	Timestamp int64  `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+// Metadata returns the source metadata attached to the events parsed from the log events of the payload
+func (p *CloudWatchLogsPayload) Metadata() map[string]string {
+	return map[string]string{
+		MetadataLogGroup:  p.LogGroup,
+		MetadataLogStream: p.LogStream,
+		MetadataOwner:     p.Owner,
+	}
+}
+
+// ReadCloudWatchLogs reads the CloudWatch Logs subscription payloads of a log entry.
+// Firehose concatenates payloads without a separator so a single log entry can hold multiple payloads.
+// It returns false if the log entry is not made of subscription payloads.
+// Payloads are detected by decoding the entry, so that the order or spacing of the keys does not matter.
+func ReadCloudWatchLogs(entry string) ([]*CloudWatchLogsPayload, bool) {
+	iter := jsoniter.ConfigDefault.BorrowIterator([]byte(entry))
+	defer jsoniter.ConfigDefault.ReturnIterator(iter)
+	var payloads []*CloudWatchLogsPayload
+	for iter.WhatIsNext() != jsoniter.InvalidValue {
+		payload, ok := readCloudWatchLogsPayload(iter)
+		if !ok {
+			return nil, false
+		}
+		payloads = append(payloads, payload)
+	}
+	return payloads, len(payloads) > 0
+}
+
+// readCloudWatchLogsPayload decodes a single payload.
+// It fails on the first key that is not part of a payload so that other JSON log entries are not decoded in full.
+func readCloudWatchLogsPayload(iter *jsoniter.Iterator) (*CloudWatchLogsPayload, bool) {
+	if iter.WhatIsNext() != jsoniter.ObjectValue {
+		return nil, false
+	}
+	payload := CloudWatchLogsPayload{}
+	hasEvents := false
+	for key := iter.ReadObject(); key != ""; key = iter.ReadObject() {
+		switch key {
+		case "messageType":
+			payload.MessageType = iter.ReadString()
+		case "owner":
+			payload.Owner = iter.ReadString()
+		case "logGroup":
+			payload.LogGroup = iter.ReadString()
+		case "logStream":
+			payload.LogStream = iter.ReadString()
+		case "subscriptionFilters":
+			iter.ReadVal(&payload.SubscriptionFilters)
+		case "logEvents":
+			iter.ReadVal(&payload.LogEvents)
+			hasEvents = true
+		default:
+			return nil, false
+		}
+		if iter.Error != nil {
+			return nil, false
+		}
+	}
+	if iter.Error != nil || !hasEvents {
+		return nil, false
+	}
+	switch payload.MessageType {
+	case CloudWatchLogsDataMessage, CloudWatchLogsControlMessage:
+		return &payload, true
+	default:
+		return nil, false
+	}
+}
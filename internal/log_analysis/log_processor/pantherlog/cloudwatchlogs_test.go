@@ -0,0 +1,74 @@
+package pantherlog
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCloudWatchLogs(t *testing.T) {
+	const data = `{"messageType":"DATA_MESSAGE","owner":"123456789012","logGroup":"/aws/lambda/foo","logStream":"stream",` +
+		`"subscriptionFilters":["filter"],"logEvents":[{"id":"1","timestamp":1600000000000,"message":"foo"},` +
+		`{"id":"2","timestamp":1600000000001,"message":"bar"}]}`
+	const control = `{"messageType":"CONTROL_MESSAGE","owner":"CloudwatchLogs","logGroup":"","logStream":"",` +
+		`"subscriptionFilters":[],"logEvents":[{"id":"","timestamp":1600000000000,"message":"CWL CONTROL MESSAGE"}]}`
+
+	payloads, ok := ReadCloudWatchLogs(data)
+	require.True(t, ok)
+	require.Len(t, payloads, 1)
+	require.Equal(t, CloudWatchLogsDataMessage, payloads[0].MessageType)
+	require.Len(t, payloads[0].LogEvents, 2)
+	require.Equal(t, "bar", payloads[0].LogEvents[1].Message)
+	require.Equal(t, map[string]string{
+		MetadataLogGroup:  "/aws/lambda/foo",
+		MetadataLogStream: "stream",
+		MetadataOwner:     "123456789012",
+	}, payloads[0].Metadata())
+
+	// Firehose concatenates payloads
+	payloads, ok = ReadCloudWatchLogs(control + data + "\n")
+	require.True(t, ok)
+	require.Len(t, payloads, 2)
+	require.Equal(t, CloudWatchLogsControlMessage, payloads[0].MessageType)
+	require.Equal(t, CloudWatchLogsDataMessage, payloads[1].MessageType)
+
+	// Keys are not required to be in order
+	payloads, ok = ReadCloudWatchLogs(`{ "logEvents": [], "messageType": "DATA_MESSAGE", "logGroup": "foo" }`)
+	require.True(t, ok)
+	require.Len(t, payloads, 1)
+	require.Equal(t, "foo", payloads[0].LogGroup)
+
+	for _, entry := range []string{
+		``,
+		`foo`,
+		`{"foo":"bar"}`,
+		`{"messageType":"FOO","logEvents":[]}`,
+		`{"messageType":"DATA_MESSAGE"}`,
+		`{"messageType":"DATA_MESSAGE","logEvents":[],"foo":"bar"}`,
+		`{"messageType":1,"logEvents":[]}`,
+		`[{"messageType":"DATA_MESSAGE","logEvents":[]}]`,
+		`{"messageType":"DATA_MESSAGE","logEvents":[`,
+		data + `{"foo":"bar"}`,
+	} {
+		_, ok := ReadCloudWatchLogs(entry)
+		require.False(t, ok, entry)
+	}
+}
@@ -152,3 +152,72 @@ func (enc *sliceIndicatorEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.St
 		enc.scanner.ScanValues(vw, s)
 	}
 }
+
+type mapIndicatorEncoder struct {
+	parent   jsoniter.ValEncoder
+	typ      reflect.Type
+	scanner  ValueScanner
+	indirect bool
+	addr     bool
+}
+
+func newMapIndicatorEncoder(typ reflect.Type, parent jsoniter.ValEncoder, scanner ValueScanner) (*mapIndicatorEncoder, bool) {
+	if typ.Kind() != reflect.Map {
+		return nil, false
+	}
+	var addr, indirect bool
+	el := typ.Elem()
+	// map of indicator values
+	switch {
+	case isIndicatorType(el):
+		addr, indirect = false, false
+	case isIndicatorType(reflect.PtrTo(el)):
+		addr, indirect = true, false
+	case el.Kind() == reflect.Ptr && isIndicatorType(el.Elem()):
+		addr, indirect = false, true
+	default:
+		return nil, false
+	}
+	return &mapIndicatorEncoder{
+		parent:   parent,
+		typ:      typ,
+		scanner:  scanner,
+		indirect: indirect,
+		addr:     addr,
+	}, true
+}
+
+// IsEmpty implements jsoniter.ValEncoder interface
+func (enc *mapIndicatorEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return enc.parent.IsEmpty(ptr)
+}
+
+// Encode implements jsoniter.ValEncoder interface
+func (enc *mapIndicatorEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	enc.parent.Encode(ptr, stream)
+	if stream.Error != nil {
+		return
+	}
+	vw, ok := stream.Attachment.(ValueWriter)
+	if !ok {
+		return
+	}
+	val := reflect.NewAt(enc.typ, ptr).Elem()
+	iter := val.MapRange()
+	for iter.Next() {
+		el := iter.Value()
+		if enc.addr {
+			// map values are not addressable
+			cp := reflect.New(el.Type())
+			cp.Elem().Set(el)
+			el = cp
+		} else if enc.indirect {
+			if el.IsNil() {
+				continue
+			}
+			el = el.Elem()
+		}
+		s := fmt.Sprint(el.Interface())
+		enc.scanner.ScanValues(vw, s)
+	}
+}
@@ -0,0 +1,41 @@
+package pantherlog
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Enrichment holds the rows of lookup tables that matched values of an event.
+// Rows are stored by table name and by the event field whose value matched the row key.
+type Enrichment map[string]map[string]map[string]string
+
+// Set stores the row of a table that matched the value of an event field
+func (e *Enrichment) Set(table, field string, row map[string]string) {
+	if *e == nil {
+		*e = make(Enrichment)
+	}
+	fields := (*e)[table]
+	if fields == nil {
+		fields = make(map[string]map[string]string)
+		(*e)[table] = fields
+	}
+	fields[field] = row
+}
+
+// Row returns the row of a table that matched the value of an event field
+func (e Enrichment) Row(table, field string) map[string]string {
+	return e[table][field]
+}
@@ -0,0 +1,101 @@
+package pantherlog
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// Framing modes for splitting a stream of data into log entries
+const (
+	// FramingLine reads one log entry per line (default)
+	FramingLine = "line"
+	// FramingJSON reads a stream of concatenated (possibly pretty-printed) JSON values.
+	// The elements of top-level JSON arrays are read as separate entries.
+	FramingJSON = "json"
+	// FramingRegex reads multi-line records where the first line of each record matches a regular expression
+	FramingRegex = "regex"
+	// FramingLength reads length-prefixed records
+	FramingLength = "length"
+	// FramingZeek reads Zeek logs in JSON or TSV format, converting TSV records to JSON
+	FramingZeek = "zeek"
+)
+
+// Length prefix formats for FramingLength
+const (
+	// LengthPrefixDecimal is an ASCII decimal length followed by a single space (RFC6587 octet counting)
+	LengthPrefixDecimal = "decimal"
+	// LengthPrefixUint32 is a 4-byte big-endian unsigned integer
+	LengthPrefixUint32 = "uint32"
+)
+
+// nolint:lll
+// FramingConfig configures how a stream of data is split into log entries
+type FramingConfig struct {
+	// Mode is the framing mode to use. Defaults to FramingLine.
+	Mode string `json:"mode" yaml:"mode" description:"The framing mode (line, json, regex, length or zeek)"`
+	// StartPattern is a regular expression that matches the first line of a record in FramingRegex mode
+	StartPattern string `json:"startPattern,omitempty" yaml:"startPattern,omitempty" description:"A regular expression matching the first line of a record"`
+	// LengthPrefix is the format of the length prefix in FramingLength mode. Defaults to LengthPrefixDecimal.
+	LengthPrefix string `json:"lengthPrefix,omitempty" yaml:"lengthPrefix,omitempty" description:"The length prefix format (decimal or uint32)"`
+	// MaxEntrySize is the max size in bytes of a single entry in FramingRegex and FramingLength modes.
+	// Defaults to logstream.DefaultMaxEntrySize.
+	MaxEntrySize int `json:"maxEntrySize,omitempty" yaml:"maxEntrySize,omitempty" description:"The max size of a single entry in bytes"`
+}
+
+// Validate checks that the framing configuration is valid
+func (c *FramingConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.MaxEntrySize < 0 {
+		return errors.Errorf("invalid max entry size %d", c.MaxEntrySize)
+	}
+	switch c.Mode {
+	case "", FramingLine, FramingJSON, FramingZeek:
+		return nil
+	case FramingRegex:
+		if c.StartPattern == "" {
+			return errors.New("missing start pattern for regex framing")
+		}
+		if _, err := regexp.Compile(c.StartPattern); err != nil {
+			return errors.Wrap(err, "invalid start pattern for regex framing")
+		}
+		return nil
+	case FramingLength:
+		switch c.LengthPrefix {
+		case "", LengthPrefixDecimal, LengthPrefixUint32:
+			return nil
+		default:
+			return errors.Errorf("invalid length prefix format %q", c.LengthPrefix)
+		}
+	default:
+		return errors.Errorf("invalid framing mode %q", c.Mode)
+	}
+}
+
+// Equal checks if two framing configurations are the same
+func (c *FramingConfig) Equal(other *FramingConfig) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	return *c == *other
+}
@@ -0,0 +1,36 @@
+package pantherlog
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFramingConfigValidate(t *testing.T) {
+	assert := require.New(t)
+	assert.NoError((*FramingConfig)(nil).Validate())
+	assert.NoError((&FramingConfig{Mode: FramingJSON}).Validate())
+	assert.Error((&FramingConfig{Mode: "foo"}).Validate())
+	assert.Error((&FramingConfig{Mode: FramingRegex}).Validate())
+	assert.Error((&FramingConfig{Mode: FramingRegex, StartPattern: "("}).Validate())
+	assert.Error((&FramingConfig{Mode: FramingLength, LengthPrefix: "foo"}).Validate())
+	assert.Error((&FramingConfig{Mode: FramingLine, MaxEntrySize: -1}).Validate())
+}
@@ -64,7 +64,9 @@ var (
 )
 
 // Special encoder for *Result. It extends the event JSON object with all the required Panther fields.
-type resultEncoder struct{}
+type resultEncoder struct {
+	resolvers []ValueResolver
+}
 
 // IsEmpty implements jsoniter.ValEncoder interface
 func (*resultEncoder) IsEmpty(ptr unsafe.Pointer) bool {
@@ -79,6 +81,22 @@ func (e *resultEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
 	// TODO: Remove this once all parsers are ported to not use parsers.PantherLog
 	if result.EventIncludesPantherFields {
 		stream.WriteVal(result.Event)
+		// Source metadata, normalized event and enrichment are not part of the embedded panther fields
+		if len(result.PantherSourceMetadata) > 0 && extendJSON(stream.Buffer()) {
+			stream.WriteObjectField(FieldSourceMetadataJSON)
+			stream.WriteVal(result.PantherSourceMetadata)
+			stream.WriteObjectEnd()
+		}
+		if result.PantherNormalized != nil && extendJSON(stream.Buffer()) {
+			stream.WriteObjectField(FieldNormalizedJSON)
+			stream.WriteVal(result.PantherNormalized)
+			stream.WriteObjectEnd()
+		}
+		if len(result.PantherEnrichment) > 0 && extendJSON(stream.Buffer()) {
+			stream.WriteObjectField(FieldEnrichmentJSON)
+			stream.WriteVal(result.PantherEnrichment)
+			stream.WriteObjectEnd()
+		}
 		return
 	}
 
@@ -105,7 +123,10 @@ func (e *resultEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
 }
 
 // writePantherFields extends the JSON object buffer with all required Panther fields.
-func (*resultEncoder) writePantherFields(r *Result, stream *jsoniter.Stream) {
+func (e *resultEncoder) writePantherFields(r *Result, stream *jsoniter.Stream) {
+	for _, resolver := range e.resolvers {
+		resolver.ResolveValues(r.values)
+	}
 	// For unit tests it will be useful to be able to write only the panther added field as a 'proper' JSON object
 	if !extendJSON(stream.Buffer()) {
 		stream.WriteObjectStart()
@@ -141,6 +162,24 @@ func (*resultEncoder) writePantherFields(r *Result, stream *jsoniter.Stream) {
 		stream.WriteVal(r.PantherSourceLabel)
 	}
 
+	if len(r.PantherSourceMetadata) > 0 {
+		stream.WriteMore()
+		stream.WriteObjectField(FieldSourceMetadataJSON)
+		stream.WriteVal(r.PantherSourceMetadata)
+	}
+
+	if r.PantherNormalized != nil {
+		stream.WriteMore()
+		stream.WriteObjectField(FieldNormalizedJSON)
+		stream.WriteVal(r.PantherNormalized)
+	}
+
+	if len(r.PantherEnrichment) > 0 {
+		stream.WriteMore()
+		stream.WriteObjectField(FieldEnrichmentJSON)
+		stream.WriteVal(r.PantherEnrichment)
+	}
+
 	for id, values := range r.values.index {
 		if len(values) == 0 || id.IsCore() {
 			continue
@@ -174,6 +213,43 @@ func extendJSON(data []byte) bool {
 	return false
 }
 
+// NewValueResolverExtension creates an extension that runs a ValueResolver on the values of every encoded result.
+// Events that embed parsers.PantherLog are not affected.
+func NewValueResolverExtension(resolver ValueResolver) jsoniter.Extension {
+	return &resolverExt{
+		resolver: resolver,
+	}
+}
+
+type resolverExt struct {
+	jsoniter.DummyExtension
+	resolver ValueResolver
+}
+
+// DecorateEncoder implements jsoniter.Extension interface
+func (ext *resolverExt) DecorateEncoder(_ reflect2.Type, encoder jsoniter.ValEncoder) jsoniter.ValEncoder {
+	switch enc := encoder.(type) {
+	case *resultEncoder:
+		return ext.extend(enc)
+	case *jsoniter.OptionalEncoder:
+		// Encoders for *Result wrap the encoder registered for Result
+		if inner, ok := enc.ValueEncoder.(*resultEncoder); ok {
+			return &jsoniter.OptionalEncoder{
+				ValueEncoder: ext.extend(inner),
+			}
+		}
+	}
+	return encoder
+}
+
+func (ext *resolverExt) extend(enc *resultEncoder) *resultEncoder {
+	resolvers := make([]ValueResolver, 0, len(enc.resolvers)+1)
+	resolvers = append(resolvers, enc.resolvers...)
+	return &resultEncoder{
+		resolvers: append(resolvers, ext.resolver),
+	}
+}
+
 func NewExtension() jsoniter.Extension {
 	return &pantherExt{}
 }
@@ -321,6 +397,10 @@ func (*pantherExt) decorateIndicatorField(b *jsoniter.Binding, scanners ...Value
 		b.Encoder = enc
 		return
 	}
+	if enc, ok := newMapIndicatorEncoder(typ, b.Encoder, scanner); ok {
+		b.Encoder = enc
+		return
+	}
 }
 
 func buildJSON() jsoniter.API {
@@ -87,14 +87,16 @@ func init() {
 func TestPantherExt_DecorateEncoder(t *testing.T) {
 	// Check all possible string types
 	type T struct {
-		Foo      testStringer  `json:"foo" panther:"foo"`
-		Bar      testStringer  `json:"bar" panther:"bar"`
-		Baz      string        `json:"baz" panther:"baz"`
-		Qux      *string       `json:"qux" panther:"qux"`
-		Quux     null.String   `json:"quux" panther:"quux"`
-		FooSlice []string      `json:"foos" panther:"foo"`
-		BarSlice []null.String `json:"bars" panther:"bar"`
-		QuxSlice []*string     `json:"quxs" panther:"qux"`
+		Foo      testStringer           `json:"foo" panther:"foo"`
+		Bar      testStringer           `json:"bar" panther:"bar"`
+		Baz      string                 `json:"baz" panther:"baz"`
+		Qux      *string                `json:"qux" panther:"qux"`
+		Quux     null.String            `json:"quux" panther:"quux"`
+		FooSlice []string               `json:"foos" panther:"foo"`
+		BarSlice []null.String          `json:"bars" panther:"bar"`
+		QuxSlice []*string              `json:"quxs" panther:"qux"`
+		BazMap   map[string]string      `json:"bazm" panther:"baz"`
+		QuuxMap  map[string]null.String `json:"quuxm" panther:"quux"`
 	}
 
 	v := T{
@@ -110,6 +112,8 @@ func TestPantherExt_DecorateEncoder(t *testing.T) {
 		FooSlice: []string{"in", "slice"},
 		BarSlice: []null.String{null.FromString("in"), null.FromString("slice")},
 		QuxSlice: []*string{box.String("qux1"), box.String("qux2"), nil},
+		BazMap:   map[string]string{"a": "baz1"},
+		QuuxMap:  map[string]null.String{"b": null.FromString("quux1")},
 	}
 
 	result := Result{
@@ -120,12 +124,12 @@ func TestPantherExt_DecorateEncoder(t *testing.T) {
 	stream.WriteVal(&v)
 	require.Equal(t, []string{"in", "ok", "slice"}, result.values.Get(kindFoo), "foo")
 	require.Equal(t, []string{"in", "ok", "slice"}, result.values.Get(kindBar), "bar")
-	require.Equal(t, []string{"baz"}, result.values.Get(kindBaz), "baz")
+	require.Equal(t, []string{"baz", "baz1"}, result.values.Get(kindBaz), "baz")
 	require.Equal(t, []string{"qux", "qux1", "qux2"}, result.values.Get(kindQux), "qux")
-	require.Equal(t, []string{"quux"}, result.values.Get(kindQuux), "quux")
+	require.Equal(t, []string{"quux", "quux1"}, result.values.Get(kindQuux), "quux")
 	actual := string(stream.Buffer())
 	//nolint:lll
-	require.Equal(t, `{"foo":"ok","bar":"ok","baz":"baz","qux":"qux","quux":"quux","foos":["in","slice"],"bars":["in","slice"],"quxs":["qux1","qux2",null]}`, actual)
+	require.Equal(t, `{"foo":"ok","bar":"ok","baz":"baz","qux":"qux","quux":"quux","foos":["in","slice"],"bars":["in","slice"],"quxs":["qux1","qux2",null],"bazm":{"a":"baz1"},"quuxm":{"b":"quux1"}}`, actual)
 }
 
 func TestResultEncoder(t *testing.T) {
@@ -170,3 +174,49 @@ func TestResultEncoder(t *testing.T) {
 	}`, tm.In(loc).Format(time.RFC3339Nano), tm.UTC().Format(time.RFC3339Nano), now.UTC().Format(time.RFC3339Nano))
 	assert.JSONEq(expect, actual)
 }
+
+type testResolver map[string]string
+
+func (r testResolver) ResolveValues(values *ValueBuffer) {
+	for _, ip := range values.Get(FieldIPAddress) {
+		values.WriteValues(FieldIPCountry, r[ip])
+	}
+}
+
+func TestValueResolverExtension(t *testing.T) {
+	assert := require.New(t)
+	type T struct {
+		RemoteIP string `json:"remote_ip" panther:"ip"`
+		LocalIP  string `json:"local_ip" panther:"ip"`
+	}
+	result := Result{
+		CoreFields: CoreFields{
+			PantherLogType: "Foo.Bar",
+			PantherRowID:   "id",
+		},
+		Event: &T{
+			RemoteIP: "2.2.2.2",
+			LocalIP:  "10.0.0.1",
+		},
+	}
+	api := buildJSON()
+	api.RegisterExtension(NewValueResolverExtension(testResolver{"2.2.2.2": "GR"}))
+	actual, err := api.MarshalToString(&result)
+	assert.NoError(err)
+	expect := `{
+		"remote_ip":"2.2.2.2",
+		"local_ip":"10.0.0.1",
+		"p_row_id": "id",
+		"p_event_time": "0001-01-01T00:00:00Z",
+		"p_parse_time": "0001-01-01T00:00:00Z",
+		"p_any_ip_addresses": ["10.0.0.1", "2.2.2.2"],
+		"p_any_ip_countries": ["GR"],
+		"p_log_type": "Foo.Bar"
+	}`
+	assert.JSONEq(expect, actual)
+
+	// The default API does not resolve values
+	actual, err = buildJSON().MarshalToString(&result)
+	assert.NoError(err)
+	assert.NotContains(actual, FieldNameJSON(FieldIPCountry))
+}
@@ -72,6 +72,8 @@ const (
 	FieldAWSTag
 	FieldEmail
 	FieldUsername
+	FieldIPCountry
+	FieldIPASN
 )
 
 // ScanValues implements ValueScanner interface
@@ -88,18 +90,27 @@ type CoreFields struct {
 	PantherRowID       string    `json:"p_row_id" validate:"required" description:"Panther added field with unique id (within table)"`
 	PantherSourceID    string    `json:"p_source_id,omitempty" description:"Panther added field with the source id"`
 	PantherSourceLabel string    `json:"p_source_label,omitempty" description:"Panther added field with the source label"`
+	// Set by the log processor for log entries unwrapped from an envelope (i.e. CloudWatch Logs subscriptions)
+	PantherSourceMetadata map[string]string `json:"p_source_metadata,omitempty" description:"Panther added field with the source metadata"`
+	// Set by the normalization stage of the log processor for log types with a normalization mapping
+	PantherNormalized *Normalized `json:"p_normalized,omitempty" description:"Panther added field with the event projected onto a common schema"`
+	// Set by the enrichment stage of the log processor
+	PantherEnrichment Enrichment `json:"p_enrichment,omitempty" description:"Panther added field with rows of lookup tables matching the event"`
 }
 
 const (
 	// FieldPrefixJSON is the prefix for field names injected by panther to log events.
-	FieldPrefixJSON      = "p_"
-	FieldPrefix          = "Panther"
-	FieldLogTypeJSON     = FieldPrefixJSON + "log_type"
-	FieldRowIDJSON       = FieldPrefixJSON + "row_id"
-	FieldEventTimeJSON   = FieldPrefixJSON + "event_time"
-	FieldParseTimeJSON   = FieldPrefixJSON + "parse_time"
-	FieldSourceIDJSON    = FieldPrefixJSON + "source_id"
-	FieldSourceLabelJSON = FieldPrefixJSON + "source_label"
+	FieldPrefixJSON         = "p_"
+	FieldPrefix             = "Panther"
+	FieldLogTypeJSON        = FieldPrefixJSON + "log_type"
+	FieldRowIDJSON          = FieldPrefixJSON + "row_id"
+	FieldEventTimeJSON      = FieldPrefixJSON + "event_time"
+	FieldParseTimeJSON      = FieldPrefixJSON + "parse_time"
+	FieldSourceIDJSON       = FieldPrefixJSON + "source_id"
+	FieldSourceLabelJSON    = FieldPrefixJSON + "source_label"
+	FieldEnrichmentJSON     = FieldPrefixJSON + "enrichment"
+	FieldSourceMetadataJSON = FieldPrefixJSON + "source_metadata"
+	FieldNormalizedJSON     = FieldPrefixJSON + "normalized"
 )
 
 var (
@@ -130,6 +141,15 @@ var (
 		"PantherLogType":   FieldNone,
 		FieldRowIDJSON:     FieldNone,
 		"PantherRowID":     FieldNone,
+
+		FieldEnrichmentJSON: FieldNone,
+		"PantherEnrichment": FieldNone,
+
+		FieldSourceMetadataJSON: FieldNone,
+		"PantherSourceMetadata": FieldNone,
+
+		FieldNormalizedJSON: FieldNone,
+		"PantherNormalized": FieldNone,
 	}
 )
 
@@ -210,6 +230,16 @@ func init() {
 		NameJSON:    "p_any_usernames",
 		Description: "Panther added field with collection of usernames associated with the row",
 	})
+	MustRegisterIndicator(FieldIPCountry, FieldMeta{
+		Name:        "PantherAnyIPCountries",
+		NameJSON:    "p_any_ip_countries",
+		Description: "Panther added field with collection of ISO country codes of ip addresses associated with the row",
+	})
+	MustRegisterIndicator(FieldIPASN, FieldMeta{
+		Name:        "PantherAnyIPASNs",
+		NameJSON:    "p_any_ip_asns",
+		Description: "Panther added field with collection of autonomous system numbers of ip addresses associated with the row",
+	})
 	MustRegisterScannerFunc("ip", ScanIPAddress, FieldIPAddress)
 	MustRegisterScannerFunc("domain", ScanDomainName, FieldDomainName)
 	MustRegisterScannerFunc("md5", ScanMD5Hash, FieldMD5Hash)
@@ -339,7 +369,7 @@ func BuildEventTypeSchema(eventType reflect.Type, indicators ...FieldID) (reflec
 
 	// Auto-detect required field ids
 	indicators = append(indicators, FieldSetFromType(eventType)...)
-	indicators = FieldSet(indicators).Indicators()
+	indicators = FieldSet(indicators).Indicators().WithDerived()
 	// Sort field set to make sure struct fields have strict order
 	sort.Sort(FieldSet(indicators))
 
@@ -523,6 +553,20 @@ func (fields FieldSet) Indicators() (indicators FieldSet) {
 	return
 }
 
+// derivedFields maps indicator fields to the fields that can be resolved from their values by a ValueResolver.
+var derivedFields = map[FieldID][]FieldID{
+	FieldIPAddress: {FieldIPCountry, FieldIPASN},
+}
+
+// WithDerived returns a copy of the set extended with all fields that can be derived from its fields.
+func (fields FieldSet) WithDerived() (out FieldSet) {
+	out = append(out, fields...)
+	for _, id := range fields {
+		out = out.Extend(derivedFields[id]...)
+	}
+	return out
+}
+
 // FieldSetFromTag produces the minimum required field set to support scanners defined in a struct tag.
 func FieldSetFromTag(tag string) FieldSet {
 	tags, err := structtag.Parse(tag)
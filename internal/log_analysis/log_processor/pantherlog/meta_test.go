@@ -39,17 +39,44 @@ func TestMetaEventStruct(t *testing.T) {
 	require.NoError(t, err)
 	// nolint:lll
 	expectMappings := map[string]string{
+		"activity":           "activity",
+		"actor":              "actor",
 		"addr":               "addr",
+		"class":              "class",
+		"class_uid":          "class_uid",
+		"country":            "country",
+		"domain":             "domain",
+		"dst_endpoint":       "dst_endpoint",
+		"email_addr":         "email_addr",
 		"foo":                "foo",
+		"hostname":           "hostname",
+		"http_method":        "http_method",
+		"http_request":       "http_request",
+		"ip":                 "ip",
+		"message":            "message",
+		"name":               "name",
 		"p_any_domain_names": "p_any_domain_names",
 		"p_any_ip_addresses": "p_any_ip_addresses",
+		"p_any_ip_asns":      "p_any_ip_asns",
+		"p_any_ip_countries": "p_any_ip_countries",
+		"p_enrichment":       "p_enrichment",
 		"p_event_time":       "p_event_time",
 		"p_log_type":         "p_log_type",
+		"p_normalized":       "p_normalized",
 		"p_parse_time":       "p_parse_time",
 		"p_row_id":           "p_row_id",
 		"p_source_id":        "p_source_id",
 		"p_source_label":     "p_source_label",
+		"p_source_metadata":  "p_source_metadata",
+		"port":               "port",
+		"service":            "service",
+		"src_endpoint":       "src_endpoint",
+		"status":             "status",
 		"ts":                 "ts",
+		"uid":                "uid",
+		"url":                "url",
+		"user":               "user",
+		"user_agent":         "user_agent",
 	}
 	require.Equal(t, expectMappings, mappings)
 	// nolint: lll,govet
@@ -63,8 +90,13 @@ func TestMetaEventStruct(t *testing.T) {
 		{"p_row_id", "string", "Panther added field with unique id (within table)", true},
 		{"p_source_id", "string", "Panther added field with the source id", false},
 		{"p_source_label", "string", "Panther added field with the source label", false},
+		{"p_source_metadata", "map<string,string>", "Panther added field with the source metadata", false},
+		{"p_normalized", "struct<class:string,class_uid:bigint,activity:string,status:string,message:string,actor:struct<user:struct<name:string,uid:string,email_addr:string,domain:string>>,src_endpoint:struct<ip:string,port:bigint,hostname:string,country:string>,dst_endpoint:struct<ip:string,port:bigint,hostname:string,country:string>,service:struct<name:string,uid:string>,http_request:struct<http_method:string,url:string,user_agent:string>>", "Panther added field with the event projected onto a common schema", false},
+		{"p_enrichment", "map<string,map<string,map<string,string>>>", "Panther added field with rows of lookup tables matching the event", false},
 		{"p_any_ip_addresses", "array<string>", "Panther added field with collection of ip addresses associated with the row", false},
 		{"p_any_domain_names", "array<string>", "Panther added field with collection of domain names associated with the row", false},
+		{"p_any_ip_countries", "array<string>", "Panther added field with collection of ISO country codes of ip addresses associated with the row", false},
+		{"p_any_ip_asns", "array<string>", "Panther added field with collection of autonomous system numbers of ip addresses associated with the row", false},
 	}, columns)
 }
 
@@ -103,3 +135,19 @@ func TestFieldSetFromTag(t *testing.T) {
 	sort.Sort(actual)
 	assert.Equal(expect, actual)
 }
+
+func TestDerivedFields(t *testing.T) {
+	type T struct {
+		RemoteIP string `json:"remote_ip" panther:"ip"`
+		Domain   string `json:"domain" panther:"domain"`
+	}
+	typ, err := pantherlog.BuildEventTypeSchema(reflect.TypeOf(T{}))
+	require.NoError(t, err)
+	_, ok := typ.FieldByName("PantherAnyIPCountries")
+	require.True(t, ok)
+	_, ok = typ.FieldByName("PantherAnyIPASNs")
+	require.True(t, ok)
+
+	require.Equal(t, pantherlog.FieldSet{pantherlog.FieldDomainName}, pantherlog.FieldSet{pantherlog.FieldDomainName}.WithDerived())
+	require.Equal(t, pantherlog.FieldSet{pantherlog.FieldIPAddress, pantherlog.FieldIPCountry, pantherlog.FieldIPASN}, pantherlog.FieldSet{pantherlog.FieldIPAddress}.WithDerived())
+}
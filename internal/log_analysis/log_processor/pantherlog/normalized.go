@@ -0,0 +1,70 @@
+package pantherlog
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Normalized is an event projected onto a common schema shared by all log types.
+// The schema follows the Open Cybersecurity Schema Framework (https://schema.ocsf.io) so that
+// detections and queries for an event class (i.e. authentication) can be written once for all log types.
+// nolint:lll
+type Normalized struct {
+	Class       string                 `json:"class" description:"The name of the event class"`
+	ClassUID    int64                  `json:"class_uid" description:"The unique identifier of the event class"`
+	Activity    string                 `json:"activity,omitempty" description:"The activity of the event within its class"`
+	Status      string                 `json:"status,omitempty" description:"The outcome of the activity (success, failure or other)"`
+	Message     string                 `json:"message,omitempty" description:"The description of the event"`
+	Actor       *NormalizedActor       `json:"actor,omitempty" description:"The actor that performed the activity"`
+	SrcEndpoint *NormalizedEndpoint    `json:"src_endpoint,omitempty" description:"The endpoint that initiated the activity"`
+	DstEndpoint *NormalizedEndpoint    `json:"dst_endpoint,omitempty" description:"The endpoint that was the target of the activity"`
+	Service     *NormalizedService     `json:"service,omitempty" description:"The service or application involved in the activity"`
+	HTTPRequest *NormalizedHTTPRequest `json:"http_request,omitempty" description:"The HTTP request of the activity"`
+}
+
+// NormalizedActor is the actor of a normalized event
+type NormalizedActor struct {
+	User *NormalizedUser `json:"user,omitempty" description:"The user that performed the activity"`
+}
+
+// NormalizedUser is a user of a normalized event
+type NormalizedUser struct {
+	Name   string `json:"name,omitempty" description:"The user name"`
+	UID    string `json:"uid,omitempty" description:"The unique identifier of the user"`
+	Email  string `json:"email_addr,omitempty" description:"The email address of the user"`
+	Domain string `json:"domain,omitempty" description:"The domain of the user"`
+}
+
+// NormalizedEndpoint is a network endpoint of a normalized event
+type NormalizedEndpoint struct {
+	IP       string `json:"ip,omitempty" description:"The IP address of the endpoint"`
+	Port     int64  `json:"port,omitempty" description:"The port of the endpoint"`
+	Hostname string `json:"hostname,omitempty" description:"The hostname of the endpoint"`
+	Country  string `json:"country,omitempty" description:"The country of the endpoint"`
+}
+
+// NormalizedService is a service of a normalized event
+type NormalizedService struct {
+	Name string `json:"name,omitempty" description:"The name of the service"`
+	UID  string `json:"uid,omitempty" description:"The unique identifier of the service"`
+}
+
+// NormalizedHTTPRequest is the HTTP request of a normalized event
+type NormalizedHTTPRequest struct {
+	Method    string `json:"http_method,omitempty" description:"The HTTP method of the request"`
+	URL       string `json:"url,omitempty" description:"The URL of the request"`
+	UserAgent string `json:"user_agent,omitempty" description:"The user agent of the request"`
+}
@@ -19,9 +19,11 @@ package pantherlog
  */
 
 import (
-	"errors"
 	"time"
 
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog/rowid"
 )
 
@@ -49,6 +51,46 @@ func (r *Result) WriteValues(kind FieldID, values ...string) {
 	r.values.WriteValues(kind, values...)
 }
 
+// NewRawResult returns a result with the JSON of an event.
+// The JSON must include all panther fields, so p_source_metadata, p_normalized and p_enrichment are not set.
+func NewRawResult(result *Result, data []byte) *Result {
+	raw := &Result{
+		CoreFields:                 result.CoreFields,
+		Event:                      jsoniter.RawMessage(data),
+		EventIncludesPantherFields: true,
+	}
+	raw.PantherSourceMetadata = nil
+	raw.PantherNormalized = nil
+	raw.PantherEnrichment = nil
+	return raw
+}
+
+// RawJSON returns the JSON of a result returned by NewRawResult.
+// It returns nil if the result needs to be serialized to produce its JSON.
+func (r *Result) RawJSON() []byte {
+	data, ok := r.Event.(jsoniter.RawMessage)
+	if !ok || !r.EventIncludesPantherFields {
+		return nil
+	}
+	if r.PantherSourceMetadata != nil || r.PantherNormalized != nil || r.PantherEnrichment != nil {
+		return nil
+	}
+	return data
+}
+
+// MarshalResult returns the JSON of a result.
+// The JSON of raw results is returned as-is, without serializing the event again.
+func MarshalResult(api jsoniter.API, result *Result) ([]byte, error) {
+	if data := result.RawJSON(); data != nil {
+		return data, nil
+	}
+	data, err := api.Marshal(result)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to serialize %s event to JSON", result.PantherLogType)
+	}
+	return data, nil
+}
+
 // ResultBuilder builds new results filling out result fields.
 type ResultBuilder struct {
 	// Override this to have static row ids for tests
@@ -64,6 +106,36 @@ type EventTimer interface {
 	PantherEventTime() time.Time
 }
 
+// EmbeddedLogger returns a log entry embedded in an event (i.e. the message of a container log line).
+// The classifier checks for events that implement this interface and classifies the embedded log entry again.
+// If the embedded log entry is classified the event is replaced by the events of the embedded log entry.
+// Events that do not embed a log entry of another log type should return an empty string.
+type EmbeddedLogger interface {
+	EmbeddedLog() string
+}
+
+// EmbeddedLogMetadata returns metadata describing where an embedded log entry came from
+// (i.e. the pod and container of a container log line).
+// The classifier adds the metadata to the p_source_metadata of the events of the embedded log entry.
+type EmbeddedLogMetadata interface {
+	EmbeddedLogMetadata() map[string]string
+}
+
+// AddSourceMetadata adds metadata to p_source_metadata keeping any existing values.
+func (r *Result) AddSourceMetadata(metadata map[string]string) {
+	if len(metadata) == 0 {
+		return
+	}
+	if r.PantherSourceMetadata == nil {
+		r.PantherSourceMetadata = make(map[string]string, len(metadata))
+	}
+	for key, value := range metadata {
+		if _, ok := r.PantherSourceMetadata[key]; !ok {
+			r.PantherSourceMetadata[key] = value
+		}
+	}
+}
+
 // BuildResult builds a new result for an event.
 // Log type is passed as an argument so that a single result builder can be reused for producing results of different
 // log types.
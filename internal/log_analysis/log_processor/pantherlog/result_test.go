@@ -25,6 +25,7 @@ import (
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
 
 	"github.com/panther-labs/panther/internal/log_analysis/awsglue/gluetimestamp"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logtesting"
@@ -306,3 +307,119 @@ func TestSourceFields(t *testing.T) {
 	require.NoError(t, err)
 	require.JSONEq(t, expect, string(actual))
 }
+
+func TestEnrichmentField(t *testing.T) {
+	now := time.Now().UTC()
+	b := newBuilder("id", now)
+	result, err := b.BuildResult("TestEvent", &testEvent{
+		Name:      "event",
+		Timestamp: now,
+	})
+	require.NoError(t, err)
+	result.PantherEnrichment.Set("assets", "ip", map[string]string{"owner": "alice"})
+	require.Equal(t, map[string]string{"owner": "alice"}, result.PantherEnrichment.Row("assets", "ip"))
+
+	api := buildAPI()
+	actual, err := api.Marshal(result)
+	require.NoError(t, err)
+	require.Equal(t, `{"assets":{"ip":{"owner":"alice"}}}`, gjson.GetBytes(actual, "p_enrichment").Raw)
+
+	// Events that include the panther fields are extended with p_enrichment
+	result.Event = jsoniter.RawMessage(`{"p_log_type":"TestEvent"}`)
+	result.EventIncludesPantherFields = true
+	actual, err = api.Marshal(result)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"p_log_type":"TestEvent","p_enrichment":{"assets":{"ip":{"owner":"alice"}}}}`, string(actual))
+}
+
+func TestSourceMetadataField(t *testing.T) {
+	now := time.Now().UTC()
+	b := newBuilder("id", now)
+	result, err := b.BuildResult("TestEvent", &testEvent{
+		Name:      "event",
+		Timestamp: now,
+	})
+	require.NoError(t, err)
+	api := buildAPI()
+	actual, err := api.Marshal(result)
+	require.NoError(t, err)
+	require.False(t, gjson.GetBytes(actual, "p_source_metadata").Exists())
+
+	result.PantherSourceMetadata = map[string]string{"logGroup": "/aws/lambda/foo"}
+	actual, err = api.Marshal(result)
+	require.NoError(t, err)
+	require.Equal(t, `{"logGroup":"/aws/lambda/foo"}`, gjson.GetBytes(actual, "p_source_metadata").Raw)
+
+	// Events that include the panther fields are extended with p_source_metadata
+	result.Event = jsoniter.RawMessage(`{"p_log_type":"TestEvent"}`)
+	result.EventIncludesPantherFields = true
+	actual, err = api.Marshal(result)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"p_log_type":"TestEvent","p_source_metadata":{"logGroup":"/aws/lambda/foo"}}`, string(actual))
+}
+
+func TestNormalizedField(t *testing.T) {
+	now := time.Now().UTC()
+	b := newBuilder("id", now)
+	result, err := b.BuildResult("TestEvent", &testEvent{
+		Name:      "event",
+		Timestamp: now,
+	})
+	require.NoError(t, err)
+	result.PantherNormalized = &pantherlog.Normalized{
+		Class:    "authentication",
+		ClassUID: 3002,
+		Actor: &pantherlog.NormalizedActor{
+			User: &pantherlog.NormalizedUser{
+				Name: "alice",
+			},
+		},
+	}
+	api := buildAPI()
+	actual, err := api.Marshal(result)
+	require.NoError(t, err)
+	expect := `{"class":"authentication","class_uid":3002,"actor":{"user":{"name":"alice"}}}`
+	require.Equal(t, expect, gjson.GetBytes(actual, "p_normalized").Raw)
+
+	// Events that include the panther fields are extended with p_normalized
+	result.Event = jsoniter.RawMessage(`{"p_log_type":"TestEvent"}`)
+	result.EventIncludesPantherFields = true
+	actual, err = api.Marshal(result)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"p_log_type":"TestEvent","p_normalized":`+expect+`}`, string(actual))
+}
+
+func TestRawResult(t *testing.T) {
+	now := time.Now().UTC()
+	b := newBuilder("id", now)
+	result, err := b.BuildResult("TestEvent", &testEvent{
+		Name:      "event",
+		Timestamp: now,
+	})
+	require.NoError(t, err)
+	result.PantherSourceMetadata = map[string]string{"logGroup": "/aws/lambda/foo"}
+	require.Nil(t, result.RawJSON())
+
+	api := buildAPI()
+	data, err := pantherlog.MarshalResult(api, result)
+	require.NoError(t, err)
+	raw := pantherlog.NewRawResult(result, data)
+	require.Equal(t, data, raw.RawJSON())
+	require.Equal(t, result.PantherRowID, raw.PantherRowID)
+	require.Nil(t, raw.PantherSourceMetadata)
+
+	// The JSON of raw results is not serialized again
+	actual, err := pantherlog.MarshalResult(api, raw)
+	require.NoError(t, err)
+	require.Equal(t, data, actual)
+	actual, err = api.Marshal(raw)
+	require.NoError(t, err)
+	require.JSONEq(t, string(data), string(actual))
+
+	// Fields set after the JSON was produced need serializing
+	raw.PantherNormalized = &pantherlog.Normalized{Class: "authentication"}
+	require.Nil(t, raw.RawJSON())
+	actual, err = pantherlog.MarshalResult(api, raw)
+	require.NoError(t, err)
+	require.Equal(t, `{"class":"authentication"}`, gjson.GetBytes(actual, "p_normalized").Raw)
+}
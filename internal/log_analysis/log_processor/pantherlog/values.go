@@ -33,6 +33,12 @@ type ValueWriterTo interface {
 	WriteValuesTo(w ValueWriter)
 }
 
+// ValueResolver derives new field values from the values already collected for a result.
+// Resolvers are added to a jsoniter.API with NewValueResolverExtension and run before the fields of a result are written.
+type ValueResolver interface {
+	ResolveValues(values *ValueBuffer)
+}
+
 // ValueBuffer is a reusable buffer of field values.
 // It provides helper methods to collect fields from log entries.
 // A ValueBuffer can be reset and used in a pool.
@@ -20,6 +20,7 @@ package duologs
 
 import (
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/normalize"
 )
 
 func LogTypes() logtypes.Group {
@@ -37,6 +38,39 @@ var logTypes = func() logtypes.Group {
 			NewEvent: func() interface{} {
 				return &AuthenticationLog{}
 			},
+			Normalization: &normalize.Mapping{
+				Fields: map[string]string{
+					"message":               "reason",
+					"actor.user.name":       "user.name",
+					"actor.user.uid":        "user.key",
+					"actor.user.email_addr": "email",
+					"src_endpoint.ip":       "access_device.ip",
+					"src_endpoint.hostname": "access_device.hostname",
+					"src_endpoint.country":  "access_device.location.country",
+					"service.name":          "application.name",
+					"service.uid":           "application.key",
+				},
+				Rules: []normalize.Rule{
+					{
+						Class:    normalize.ClassAuthentication,
+						Activity: normalize.ActivityLogon,
+						Status:   normalize.StatusSuccess,
+						Match: []normalize.Match{
+							{Field: "event_type", Values: []string{"authentication"}},
+							{Field: "result", Values: []string{"success"}},
+						},
+					},
+					{
+						Class:    normalize.ClassAuthentication,
+						Activity: normalize.ActivityLogon,
+						Status:   normalize.StatusFailure,
+						Match: []normalize.Match{
+							{Field: "event_type", Values: []string{"authentication"}},
+							{Field: "result", Values: []string{"denied", "failure", "fraud", "error"}},
+						},
+					},
+				},
+			},
 		},
 
 		logtypes.ConfigJSON{
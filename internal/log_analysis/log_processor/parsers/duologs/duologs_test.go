@@ -21,9 +21,45 @@ package duologs
 import (
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logtesting"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/normalize"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 )
 
 func TestDuoParsers(t *testing.T) {
 	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/duologs_test.yml")
 }
+
+func TestAuthenticationNormalization(t *testing.T) {
+	normalizer := logtypes.Normalizer(LogTypes().Find(TypeAuthentication))
+	// nolint:lll
+	log := `{"event_type":"authentication","result":"denied","reason":"user_marked_fraud","email":"alice@example.com","user":{"key":"DU1","name":"alice"},"access_device":{"ip":"1.2.3.4","hostname":"laptop","location":{"country":"United States"}},"application":{"key":"DI1","name":"Web SSO"}}`
+	require.Equal(t, &pantherlog.Normalized{
+		Class:    normalize.ClassAuthentication,
+		ClassUID: 3002,
+		Activity: normalize.ActivityLogon,
+		Status:   normalize.StatusFailure,
+		Message:  "user_marked_fraud",
+		Actor: &pantherlog.NormalizedActor{
+			User: &pantherlog.NormalizedUser{
+				Name:  "alice",
+				UID:   "DU1",
+				Email: "alice@example.com",
+			},
+		},
+		SrcEndpoint: &pantherlog.NormalizedEndpoint{
+			IP:       "1.2.3.4",
+			Hostname: "laptop",
+			Country:  "United States",
+		},
+		Service: &pantherlog.NormalizedService{
+			Name: "Web SSO",
+			UID:  "DI1",
+		},
+	}, normalizer.Normalize([]byte(log)))
+	require.Nil(t, normalizer.Normalize([]byte(`{"event_type":"enrollment","result":"success"}`)))
+	require.Nil(t, logtypes.Normalizer(LogTypes().Find(TypeTelephony)))
+}
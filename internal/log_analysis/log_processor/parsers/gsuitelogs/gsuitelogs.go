@@ -20,6 +20,7 @@ package gsuitelogs
 
 import (
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/normalize"
 )
 
 const TypeReports = `GSuite.Reports`
@@ -36,4 +37,42 @@ var logTypes = logtypes.Must("GSuite", logtypes.ConfigJSON{
 	NewEvent: func() interface{} {
 		return &Reports{}
 	},
+	Normalization: &normalize.Mapping{
+		Fields: map[string]string{
+			"actor.user.name":       "actor.email",
+			"actor.user.uid":        "actor.profileId",
+			"actor.user.email_addr": "actor.email",
+			"actor.user.domain":     "ownerDomain",
+			"src_endpoint.ip":       "ipAddress",
+			"service.name":          "id.applicationName",
+		},
+		Rules: []normalize.Rule{
+			{
+				Class:    normalize.ClassAuthentication,
+				Activity: normalize.ActivityLogon,
+				Status:   normalize.StatusSuccess,
+				Match: []normalize.Match{
+					{Field: "id.applicationName", Values: []string{"login"}},
+					{Field: "events.0.name", Values: []string{"login_success"}},
+				},
+			},
+			{
+				Class:    normalize.ClassAuthentication,
+				Activity: normalize.ActivityLogon,
+				Status:   normalize.StatusFailure,
+				Match: []normalize.Match{
+					{Field: "id.applicationName", Values: []string{"login"}},
+					{Field: "events.0.name", Values: []string{"login_failure"}},
+				},
+			},
+			{
+				Class:    normalize.ClassAuthentication,
+				Activity: normalize.ActivityLogoff,
+				Match: []normalize.Match{
+					{Field: "id.applicationName", Values: []string{"login"}},
+					{Field: "events.0.name", Values: []string{"logout"}},
+				},
+			},
+		},
+	},
 })
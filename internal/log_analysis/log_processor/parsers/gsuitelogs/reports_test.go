@@ -22,8 +22,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logtesting"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/normalize"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
 )
 
@@ -41,3 +45,30 @@ func TestReportsSamples(t *testing.T) {
 		assert.NoErrorf(t, err, "failed to parse line %d", i)
 	}
 }
+
+func TestReportsNormalization(t *testing.T) {
+	normalizer := logtypes.Normalizer(LogTypes().Find(TypeReports))
+	// nolint:lll
+	log := `{"id":{"applicationName":"login"},"actor":{"email":"alice@example.com","profileId":"1234"},"ownerDomain":"example.com","ipAddress":"1.2.3.4","events":[{"type":"login","name":"login_success"}]}`
+	require.Equal(t, &pantherlog.Normalized{
+		Class:    normalize.ClassAuthentication,
+		ClassUID: 3002,
+		Activity: normalize.ActivityLogon,
+		Status:   normalize.StatusSuccess,
+		Actor: &pantherlog.NormalizedActor{
+			User: &pantherlog.NormalizedUser{
+				Name:   "alice@example.com",
+				UID:    "1234",
+				Email:  "alice@example.com",
+				Domain: "example.com",
+			},
+		},
+		SrcEndpoint: &pantherlog.NormalizedEndpoint{
+			IP: "1.2.3.4",
+		},
+		Service: &pantherlog.NormalizedService{
+			Name: "login",
+		},
+	}, normalizer.Normalize([]byte(log)))
+	require.Nil(t, normalizer.Normalize([]byte(`{"id":{"applicationName":"drive"},"events":[{"name":"login_success"}]}`)))
+}
@@ -0,0 +1,145 @@
+package kuberneteslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// Audit is a Kubernetes API server audit event
+// nolint:lll
+type Audit struct {
+	Kind                     pantherlog.String     `json:"kind" validate:"required,eq=Event" description:"The kind of the object (always Event)."`
+	APIVersion               pantherlog.String     `json:"apiVersion" validate:"required,startswith=audit.k8s.io/" description:"The version of the audit event schema (i.e. audit.k8s.io/v1)."`
+	Level                    pantherlog.String     `json:"level" validate:"required" description:"The audit level at which the event was generated (None, Metadata, Request or RequestResponse)."`
+	AuditID                  pantherlog.String     `json:"auditID" validate:"required" description:"The unique audit ID generated for each request."`
+	Stage                    pantherlog.String     `json:"stage" validate:"required" description:"The stage of the request handling when the event was generated (RequestReceived, ResponseStarted, ResponseComplete or Panic)."`
+	RequestURI               pantherlog.String     `json:"requestURI" validate:"required" description:"The request URI as sent by the client."`
+	Verb                     pantherlog.String     `json:"verb" validate:"required" description:"The Kubernetes verb of resource requests (get, list, watch, create, update, patch, delete) or the lowercase HTTP method of non-resource requests."`
+	User                     UserInfo              `json:"user" description:"The authenticated user."`
+	ImpersonatedUser         *UserInfo             `json:"impersonatedUser,omitempty" description:"The impersonated user."`
+	SourceIPs                []string              `json:"sourceIPs" panther:"ip" description:"The source IP addresses of the request and the intermediate proxies."`
+	UserAgent                pantherlog.String     `json:"userAgent" description:"The user agent reported by the client."`
+	ObjectRef                *ObjectReference      `json:"objectRef,omitempty" description:"The object the request is targeted at."`
+	ResponseStatus           *ResponseStatus       `json:"responseStatus,omitempty" description:"The status of the response."`
+	RequestObject            pantherlog.RawMessage `json:"requestObject,omitempty" description:"The API object of the request in JSON format (logged at Request level and higher)."`
+	ResponseObject           pantherlog.RawMessage `json:"responseObject,omitempty" description:"The API object of the response in JSON format (logged at RequestResponse level)."`
+	RequestReceivedTimestamp pantherlog.Time       `json:"requestReceivedTimestamp" tcodec:"rfc3339" description:"The time the request reached the API server."`
+	StageTimestamp           pantherlog.Time       `json:"stageTimestamp" tcodec:"rfc3339" event_time:"true" validate:"required" description:"The time the request reached the current audit stage."`
+	Annotations              map[string]string     `json:"annotations,omitempty" description:"The annotations of the audit event (i.e. the authorization decision and reason)."`
+}
+
+// UserInfo is the information about a user of an API request
+// nolint:lll
+type UserInfo struct {
+	Username pantherlog.String   `json:"username" panther:"username" description:"The name of the user."`
+	UID      pantherlog.String   `json:"uid" description:"The unique identifier of the user."`
+	Groups   []string            `json:"groups,omitempty" description:"The groups of the user."`
+	Extra    map[string][]string `json:"extra,omitempty" description:"The extra information provided by the authenticator (i.e. the IAM ARN of EKS users)."`
+}
+
+// ObjectReference is a reference to the object of an API request
+// nolint:lll
+type ObjectReference struct {
+	Resource        pantherlog.String `json:"resource" description:"The resource of the object."`
+	Namespace       pantherlog.String `json:"namespace" description:"The namespace of the object."`
+	Name            pantherlog.String `json:"name" description:"The name of the object."`
+	UID             pantherlog.String `json:"uid" description:"The unique identifier of the object."`
+	APIGroup        pantherlog.String `json:"apiGroup" description:"The API group of the object (empty for the core API group)."`
+	APIVersion      pantherlog.String `json:"apiVersion" description:"The version of the API group of the object."`
+	ResourceVersion pantherlog.String `json:"resourceVersion" description:"The resource version of the object."`
+	Subresource     pantherlog.String `json:"subresource" description:"The subresource of the object."`
+}
+
+// ResponseStatus is the status of the response to an API request
+// nolint:lll
+type ResponseStatus struct {
+	Status  pantherlog.String     `json:"status" description:"The status of the operation (Success or Failure)."`
+	Message pantherlog.String     `json:"message" description:"A description of the status of the operation."`
+	Reason  pantherlog.String     `json:"reason" description:"A machine-readable description of why the operation is in the Failure status."`
+	Details pantherlog.RawMessage `json:"details,omitempty" description:"The extended data associated with the reason in JSON format."`
+	Code    pantherlog.Int32      `json:"code" description:"The HTTP status code of the response."`
+}
+
+// NewAuditParser creates a parser for Kubernetes audit events
+func NewAuditParser(_ interface{}) (pantherlog.LogParser, error) {
+	return &auditParser{}, nil
+}
+
+type auditParser struct {
+	builder pantherlog.ResultBuilder
+}
+
+var _ pantherlog.LogParser = (*auditParser)(nil)
+
+// auditEventList is the list of audit events posted by the webhook backend
+type auditEventList struct {
+	Kind  string                `json:"kind"`
+	Items []jsoniter.RawMessage `json:"items"`
+}
+
+// ParseLog implements pantherlog.LogParser interface
+// Event lists posted by the webhook backend produce a result for each event.
+func (p *auditParser) ParseLog(log string) ([]*pantherlog.Result, error) {
+	log = strings.TrimSpace(log)
+	list := auditEventList{}
+	if err := jsoniter.UnmarshalFromString(log, &list); err != nil {
+		return nil, errors.Wrap(err, "failed to read audit event JSON")
+	}
+	if list.Kind != "EventList" {
+		result, err := p.parseEvent([]byte(log))
+		if err != nil {
+			return nil, err
+		}
+		return []*pantherlog.Result{result}, nil
+	}
+	results := make([]*pantherlog.Result, 0, len(list.Items))
+	for _, item := range list.Items {
+		result, err := p.parseEvent(item)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (p *auditParser) parseEvent(data []byte) (*pantherlog.Result, error) {
+	event, err := parseAudit(data)
+	if err != nil {
+		return nil, err
+	}
+	return p.builder.BuildResult(TypeAudit, event)
+}
+
+func parseAudit(data []byte) (*Audit, error) {
+	event := Audit{}
+	if err := pantherlog.ConfigJSON().Unmarshal(data, &event); err != nil {
+		return nil, errors.Wrap(err, "failed to read audit event JSON")
+	}
+	if err := pantherlog.ValidateStruct(&event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
@@ -0,0 +1,174 @@
+package kuberneteslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// Container is a container log line in the Docker JSON format
+// nolint:lll
+type Container struct {
+	Log        pantherlog.String   `json:"log" validate:"required" description:"The log message written by the container."`
+	Stream     pantherlog.String   `json:"stream" validate:"required,oneof=stdout stderr" description:"The output stream of the log message (stdout or stderr)."`
+	Time       pantherlog.Time     `json:"time" tcodec:"rfc3339" event_time:"true" validate:"required" description:"The time the log message was written."`
+	Attrs      map[string]string   `json:"attrs,omitempty" description:"The attributes added by the labels and env options of the json-file logging driver."`
+	Kubernetes *KubernetesMetadata `json:"kubernetes,omitempty" description:"The Kubernetes metadata of the container added by Fluent Bit or Fluentd."`
+}
+
+var _ pantherlog.EmbeddedLogger = (*Container)(nil)
+
+// EmbeddedLog implements pantherlog.EmbeddedLogger interface
+func (c *Container) EmbeddedLog() string {
+	return c.Log.Value
+}
+
+var _ pantherlog.EmbeddedLogMetadata = (*Container)(nil)
+
+// EmbeddedLogMetadata implements pantherlog.EmbeddedLogMetadata interface
+// It returns the output stream and the Kubernetes metadata of the container that wrote the log message.
+func (c *Container) EmbeddedLogMetadata() map[string]string {
+	metadata := streamMetadata(c.Stream)
+	if k := c.Kubernetes; k != nil {
+		for key, value := range map[string]pantherlog.String{
+			"namespace":      k.NamespaceName,
+			"pod":            k.PodName,
+			"podId":          k.PodID,
+			"host":           k.Host,
+			"container":      k.ContainerName,
+			"containerId":    k.DockerID,
+			"containerImage": k.ContainerImage,
+		} {
+			if value.Value != "" {
+				metadata[key] = value.Value
+			}
+		}
+	}
+	return metadata
+}
+
+func streamMetadata(stream pantherlog.String) map[string]string {
+	metadata := map[string]string{}
+	if stream.Value != "" {
+		metadata["stream"] = stream.Value
+	}
+	return metadata
+}
+
+// KubernetesMetadata is the metadata of the pod of a container
+// nolint:lll
+type KubernetesMetadata struct {
+	PodName        pantherlog.String `json:"pod_name" description:"The name of the pod."`
+	NamespaceName  pantherlog.String `json:"namespace_name" description:"The namespace of the pod."`
+	PodID          pantherlog.String `json:"pod_id" description:"The unique identifier of the pod."`
+	Host           pantherlog.String `json:"host" panther:"hostname" description:"The node running the pod."`
+	ContainerName  pantherlog.String `json:"container_name" description:"The name of the container."`
+	DockerID       pantherlog.String `json:"docker_id" description:"The identifier of the container."`
+	ContainerHash  pantherlog.String `json:"container_hash" description:"The digest of the container image."`
+	ContainerImage pantherlog.String `json:"container_image" description:"The container image."`
+	Labels         map[string]string `json:"labels,omitempty" description:"The labels of the pod."`
+	Annotations    map[string]string `json:"annotations,omitempty" description:"The annotations of the pod."`
+}
+
+// CRI is a container log line written by a CRI container runtime
+// nolint:lll
+type CRI struct {
+	Time   pantherlog.Time   `json:"time" tcodec:"rfc3339" event_time:"true" validate:"required" description:"The time the log message was written."`
+	Stream pantherlog.String `json:"stream" validate:"required,oneof=stdout stderr" description:"The output stream of the log message (stdout or stderr)."`
+	Tag    pantherlog.String `json:"tag" validate:"required" description:"The tag of the log line (F for full lines or P for partial lines split by the runtime)."`
+	Log    pantherlog.String `json:"log" description:"The log message written by the container."`
+}
+
+var _ pantherlog.EmbeddedLogger = (*CRI)(nil)
+
+// EmbeddedLog implements pantherlog.EmbeddedLogger interface
+// Partial lines are not classified again since they only contain part of the log message.
+func (c *CRI) EmbeddedLog() string {
+	if c.partial() {
+		return ""
+	}
+	return c.Log.Value
+}
+
+var _ pantherlog.EmbeddedLogMetadata = (*CRI)(nil)
+
+// EmbeddedLogMetadata implements pantherlog.EmbeddedLogMetadata interface
+// CRI log lines only record the output stream, the pod and container are part of the name of the log file.
+func (c *CRI) EmbeddedLogMetadata() map[string]string {
+	return streamMetadata(c.Stream)
+}
+
+// partial checks the first tag of the line since tags are a ':' separated list of flags
+func (c *CRI) partial() bool {
+	return strings.HasPrefix(c.Tag.Value, criTagPartial)
+}
+
+const (
+	criTagFull    = "F"
+	criTagPartial = "P"
+)
+
+// NewCRIParser creates a parser for CRI container log lines
+func NewCRIParser(_ interface{}) (pantherlog.LogParser, error) {
+	return &criParser{}, nil
+}
+
+type criParser struct {
+	builder pantherlog.ResultBuilder
+}
+
+var _ pantherlog.LogParser = (*criParser)(nil)
+
+// ParseLog implements pantherlog.LogParser interface
+// CRI log lines are formatted as `<RFC3339Nano time> <stream> <tag> <message>`.
+func (p *criParser) ParseLog(log string) ([]*pantherlog.Result, error) {
+	log = strings.TrimRight(log, "\r\n")
+	fields := strings.SplitN(log, " ", 4)
+	if len(fields) < 3 {
+		return nil, errors.New("invalid CRI log line")
+	}
+	tm, err := time.Parse(time.RFC3339Nano, fields[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid CRI log line timestamp")
+	}
+	event := CRI{
+		Time:   tm.UTC(),
+		Stream: nonEmpty(fields[1]),
+		Tag:    nonEmpty(fields[2]),
+	}
+	if len(fields) == 4 {
+		event.Log = nonEmpty(fields[3])
+	}
+	if flag := strings.SplitN(event.Tag.Value, ":", 2)[0]; flag != criTagFull && flag != criTagPartial {
+		return nil, errors.Errorf("invalid CRI log line tag %q", event.Tag.Value)
+	}
+	if err := pantherlog.ValidateStruct(&event); err != nil {
+		return nil, err
+	}
+	result, err := p.builder.BuildResult(TypeCRI, &event)
+	if err != nil {
+		return nil, err
+	}
+	return []*pantherlog.Result{result}, nil
+}
@@ -0,0 +1,170 @@
+package kuberneteslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// EKS is a log message of an Amazon EKS control plane component.
+// The messages are unwrapped from CloudWatch Logs subscription payloads by the log processor, so the log group,
+// log stream and owner of each message are stored in the source metadata of the event.
+// nolint:lll
+type EKS struct {
+	Timestamp pantherlog.Time   `json:"timestamp" tcodec:"rfc3339" event_time:"true" validate:"required" description:"The time the log message was written."`
+	Message   pantherlog.String `json:"message" description:"The log message (empty for audit events)."`
+	Severity  pantherlog.String `json:"severity" description:"The severity of the log message (INFO, WARNING, ERROR or FATAL)."`
+	Source    pantherlog.String `json:"source" description:"The source file and line that wrote the log message."`
+	ARN       pantherlog.String `json:"arn" panther:"aws_arn" description:"The IAM ARN of authenticator log messages."`
+	Username  pantherlog.String `json:"username" panther:"username" description:"The Kubernetes user mapped to the IAM ARN of authenticator log messages."`
+	Audit     *Audit            `json:"audit,omitempty" description:"The API server audit event of kube-apiserver-audit log streams."`
+}
+
+// NewEKSParser creates a parser for the messages of EKS control plane logs
+func NewEKSParser(_ interface{}) (pantherlog.LogParser, error) {
+	return &eksParser{
+		now: time.Now,
+	}, nil
+}
+
+type eksParser struct {
+	builder pantherlog.ResultBuilder
+	// klog headers omit the year, it is guessed from the time of parsing
+	now func() time.Time
+}
+
+var _ pantherlog.LogParser = (*eksParser)(nil)
+
+// ParseLog implements pantherlog.LogParser interface
+// Audit events are stored in the audit field, all other messages must have a klog header or authenticator logfmt fields.
+func (p *eksParser) ParseLog(log string) ([]*pantherlog.Result, error) {
+	log = strings.TrimSpace(log)
+	event := EKS{}
+	if strings.HasPrefix(log, "{") {
+		audit, err := parseAudit([]byte(log))
+		if err != nil {
+			return nil, err
+		}
+		event.Audit = audit
+		event.Timestamp = audit.StageTimestamp
+	} else if err := event.setMessage(log, p.now()); err != nil {
+		return nil, err
+	}
+	if err := pantherlog.ValidateStruct(&event); err != nil {
+		return nil, err
+	}
+	result, err := p.builder.BuildResult(TypeEKS, &event)
+	if err != nil {
+		return nil, err
+	}
+	return []*pantherlog.Result{result}, nil
+}
+
+// klog header of Kubernetes components, i.e. `I1008 12:34:56.789012       1 controller.go:606] message`
+var klogHeader = regexp.MustCompile(`^([IWEF])(\d{4} \d{2}:\d{2}:\d{2}\.\d+)\s+\d+ ([^\]]+)\] `)
+
+// logfmt fields of authenticator messages, i.e. `time="2020-10-08T12:40:10Z" level=info msg="access granted" arn="arn:aws:iam::..."`
+var logfmtField = regexp.MustCompile(`(?:^|\s)(\w+)=("(?:[^"\\]|\\.)*"|\S*)`)
+
+var klogSeverities = map[string]string{
+	"I": "INFO",
+	"W": "WARNING",
+	"E": "ERROR",
+	"F": "FATAL",
+}
+
+// setMessage sets the message and the fields read from the klog header or the authenticator logfmt fields
+func (e *EKS) setMessage(message string, now time.Time) error {
+	e.Message = nonEmpty(message)
+	if match := klogHeader.FindStringSubmatch(message); match != nil {
+		tm, err := klogTimestamp(match[2], now)
+		if err != nil {
+			return err
+		}
+		e.Timestamp = tm
+		e.Severity = nonEmpty(klogSeverities[match[1]])
+		e.Source = nonEmpty(match[3])
+		return nil
+	}
+	hasMessage := false
+	for _, match := range logfmtField.FindAllStringSubmatch(message, -1) {
+		value := match[2]
+		if strings.HasPrefix(value, `"`) {
+			if unquoted, err := unquote(value); err == nil {
+				value = unquoted
+			}
+		}
+		switch match[1] {
+		case "time":
+			tm, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return errors.Wrap(err, "invalid authenticator log message time")
+			}
+			e.Timestamp = tm.UTC()
+		case "msg":
+			hasMessage = true
+		case "level":
+			e.Severity = nonEmpty(strings.ToUpper(value))
+		case "arn":
+			e.ARN = nonEmpty(value)
+		case "username":
+			e.Username = nonEmpty(value)
+		}
+	}
+	if !hasMessage {
+		return errors.New("log message is not an EKS control plane log message")
+	}
+	return nil
+}
+
+// klogTimestamp parses the timestamp of a klog header.
+// The header omits the year so it is guessed by comparing with the time of parsing (like juniperlogs).
+func klogTimestamp(s string, now time.Time) (time.Time, error) {
+	const layoutKlog = `0102 15:04:05.999999999`
+	tm, err := time.ParseInLocation(layoutKlog, s, time.UTC)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "invalid klog header time")
+	}
+	now = now.UTC()
+	year, month := now.Year(), now.Month()
+	if month == time.January && tm.Month() > month {
+		year--
+	}
+	return tm.AddDate(year, 0, 0), nil
+}
+
+func unquote(s string) (string, error) {
+	var value string
+	err := jsoniter.UnmarshalFromString(s, &value)
+	return value, err
+}
+
+func nonEmpty(s string) pantherlog.String {
+	return pantherlog.String{
+		Value:  s,
+		Exists: s != "",
+	}
+}
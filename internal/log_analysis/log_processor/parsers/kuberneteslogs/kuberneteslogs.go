@@ -0,0 +1,79 @@
+package kuberneteslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+const (
+	// LogTypePrefix is the prefix of all logs parsed by this package and the name of the log type group
+	LogTypePrefix = "Kubernetes"
+	// TypeAudit is the log type of Kubernetes API server audit events
+	TypeAudit = LogTypePrefix + ".Audit"
+	// TypeEKS is the log type of Amazon EKS control plane logs delivered by CloudWatch Logs subscriptions
+	TypeEKS = LogTypePrefix + ".EKS"
+	// TypeCRI is the log type of container log lines written by CRI container runtimes
+	TypeCRI = LogTypePrefix + ".CRI"
+	// TypeContainer is the log type of container log lines in the Docker JSON format
+	TypeContainer = LogTypePrefix + ".Container"
+)
+
+// LogTypes exports the available log type entries
+func LogTypes() logtypes.Group {
+	return logTypes
+}
+
+var logTypes = logtypes.Must(LogTypePrefix,
+	logtypes.Config{
+		Name: TypeAudit,
+		Description: `Kubernetes API server audit events (audit.k8s.io/v1) written by the log backend.
+Event lists posted by the webhook backend produce a record for each event. The request and response objects are stored in JSON format.`,
+		ReferenceURL: `https://kubernetes.io/docs/reference/config-api/apiserver-audit.v1/#audit-k8s-io-v1-Event`,
+		Schema:       pantherlog.MustBuildEventSchema(&Audit{}),
+		NewParser:    pantherlog.FactoryFunc(NewAuditParser),
+	},
+	logtypes.Config{
+		Name: TypeEKS,
+		Description: `Amazon EKS control plane logs delivered by CloudWatch Logs subscriptions.
+Each log event of the subscription payload is a separate record and API server audit events are stored in the audit field.
+The log group and log stream of the log events are stored in the source metadata.`,
+		ReferenceURL: `https://docs.aws.amazon.com/eks/latest/userguide/control-plane-logs.html`,
+		Schema:       pantherlog.MustBuildEventSchema(&EKS{}),
+		NewParser:    pantherlog.FactoryFunc(NewEKSParser),
+	},
+	logtypes.Config{
+		Name: TypeCRI,
+		Description: `Container log lines written by CRI container runtimes (containerd, CRI-O) to /var/log/containers.
+The message of full lines is classified again using the other log types of the source.`,
+		ReferenceURL: `https://github.com/kubernetes/community/blob/master/contributors/design-proposals/node/kubelet-cri-logging.md`,
+		Schema:       pantherlog.MustBuildEventSchema(&CRI{}),
+		NewParser:    pantherlog.FactoryFunc(NewCRIParser),
+	},
+	logtypes.ConfigJSON{
+		Name: TypeContainer,
+		Description: `Container log lines in the Docker json-file format, including the Kubernetes metadata added by Fluent Bit or Fluentd.
+The message is classified again using the other log types of the source.`,
+		ReferenceURL: `https://docs.docker.com/config/containers/logging/json-file/`,
+		NewEvent: func() interface{} {
+			return &Container{}
+		},
+	},
+)
@@ -0,0 +1,154 @@
+package kuberneteslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logtesting"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/nginxlogs"
+)
+
+func TestAudit(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/audit_tests.yml")
+}
+
+func TestEKS(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/eks_tests.yml")
+}
+
+// klog headers omit the year
+// nolint:lll
+func TestEKSKlog(t *testing.T) {
+	p := &eksParser{
+		now: func() time.Time {
+			return time.Date(2021, time.January, 2, 0, 0, 0, 0, time.UTC)
+		},
+	}
+	results, err := p.ParseLog("E1231 23:59:59.000456       1 leaderelection.go:325] error retrieving resource lock kube-system/kube-scheduler: etcdserver: request timed out\n")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	event := results[0].Event.(*EKS)
+	require.Equal(t, time.Date(2020, time.December, 31, 23, 59, 59, 456000, time.UTC), event.Timestamp)
+	require.Equal(t, "ERROR", event.Severity.Value)
+	require.Equal(t, "leaderelection.go:325", event.Source.Value)
+	require.True(t, strings.HasSuffix(event.Message.Value, "etcdserver: request timed out"))
+
+	results, err = p.ParseLog(`I0102 00:00:00.5       1 scheduler.go:597] "Successfully bound pod to node"`)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2021, time.January, 2, 0, 0, 0, 500000000, time.UTC), results[0].Event.(*EKS).Timestamp)
+}
+
+func TestContainer(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/container_tests.yml")
+}
+
+// nolint:lll
+func TestInvalid(t *testing.T) {
+	for logType, logs := range map[string][]string{
+		TypeAudit: {
+			`{"kind":"Pod","apiVersion":"v1","metadata":{"name":"web"}}`,
+			`{"kind":"Event","apiVersion":"v1","reason":"Scheduled","lastTimestamp":"2020-10-08T12:34:56Z"}`,
+		},
+		TypeEKS: {
+			`{"messageType":"DATA_MESSAGE","owner":"123456789012","logGroup":"/aws/eks/prod/cluster","logStream":"etcd-abc","logEvents":[]}`,
+			`I1308 12:41:40.500123       1 scheduler.go:597] invalid date`,
+			`level=info path=/authenticate`,
+			`time="yesterday" level=info msg="access granted"`,
+			`Listening on :8080`,
+		},
+		TypeCRI: {
+			`2020-10-08 12:45:00 stdout F message`,
+			`2020-10-08T12:45:00.123456789Z console F message`,
+			`2020-10-08T12:45:00.123456789Z stdout X message`,
+		},
+		TypeContainer: {
+			`{"log":"message\n","stream":"stdout"}`,
+		},
+	} {
+		parser, err := LogTypes().Find(logType).NewParser(nil)
+		require.NoError(t, err)
+		for _, log := range logs {
+			_, err := parser.ParseLog(log)
+			require.Error(t, err, log)
+		}
+	}
+}
+
+// Tests that the messages of container log lines are classified using the other log types
+// nolint:lll
+func TestEmbeddedLog(t *testing.T) {
+	index := map[string]parsers.Interface{}
+	for _, entry := range []logtypes.Entry{
+		LogTypes().Find(TypeCRI),
+		LogTypes().Find(TypeContainer),
+		nginxlogs.LogTypes().Find(nginxlogs.TypeAccess),
+	} {
+		parser, err := entry.NewParser(nil)
+		require.NoError(t, err)
+		index[entry.String()] = parser
+	}
+	classifier := classification.NewClassifier(index)
+	for log, logType := range map[string]string{
+		`2020-10-08T12:45:00.123456789Z stdout F 10.0.1.5 - - [08/Oct/2020:12:45:00 +0000] "GET /healthz HTTP/1.1" 200 2 "-" "kube-probe/1.18"`:                    nginxlogs.TypeAccess,
+		`2020-10-08T12:45:00.123456789Z stdout P 10.0.1.5 - - [08/Oct/2020:12:45:00 +0000] "GET /healthz HTTP/1.1" 200 2 "-" "kube-probe/1.18"`:                    TypeCRI,
+		`{"log":"10.0.1.5 - - [08/Oct/2020:12:45:00 +0000] \"GET / HTTP/1.1\" 200 612 \"-\" \"curl/7.68.0\"\n","stream":"stdout","time":"2020-10-08T12:45:00.1Z"}`: nginxlogs.TypeAccess,
+		`{"log":"Listening on :8080\n","stream":"stdout","time":"2020-10-08T12:47:00.000000001Z"}`:                                                                 TypeContainer,
+	} {
+		result, err := classifier.Classify(log)
+		require.NoError(t, err)
+		require.Len(t, result.Events, 1)
+		require.Equal(t, logType, result.Events[0].PantherLogType, log)
+	}
+}
+
+// Tests that the events of container log messages keep the container metadata in p_source_metadata
+// nolint:lll
+func TestEmbeddedLogMetadata(t *testing.T) {
+	index := map[string]parsers.Interface{}
+	for _, entry := range []logtypes.Entry{
+		LogTypes().Find(TypeContainer),
+		nginxlogs.LogTypes().Find(nginxlogs.TypeAccess),
+	} {
+		parser, err := entry.NewParser(nil)
+		require.NoError(t, err)
+		index[entry.String()] = parser
+	}
+	classifier := classification.NewClassifier(index)
+	log := `{"log":"10.0.1.5 - - [08/Oct/2020:12:45:00 +0000] \"GET / HTTP/1.1\" 200 612 \"-\" \"curl/7.68.0\"\n","stream":"stdout","time":"2020-10-08T12:45:00.1Z","kubernetes":{"pod_name":"nginx-6799fc88d8-7xq2b","namespace_name":"default","host":"ip-10-0-1-5","container_name":"nginx","container_image":"nginx:1.19"}}`
+	result, err := classifier.Classify(log)
+	require.NoError(t, err)
+	require.Len(t, result.Events, 1)
+	require.Equal(t, nginxlogs.TypeAccess, result.Events[0].PantherLogType)
+	require.Equal(t, map[string]string{
+		"stream":         "stdout",
+		"pod":            "nginx-6799fc88d8-7xq2b",
+		"namespace":      "default",
+		"host":           "ip-10-0-1-5",
+		"container":      "nginx",
+		"containerImage": "nginx:1.19",
+	}, result.Events[0].PantherSourceMetadata)
+}
@@ -20,6 +20,7 @@ package oktalogs
 
 import (
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/normalize"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
 )
 
@@ -37,4 +38,49 @@ Panther Enterprise Only
 	ReferenceURL: `https://developer.okta.com/docs/reference/api/system-log/`,
 	Schema:       LogEvent{},
 	NewParser:    parsers.AdapterFactory(&SystemLogParser{}),
+	Normalization: &normalize.Mapping{
+		Fields: map[string]string{
+			"message":                 "displayMessage",
+			"actor.user.name":         "actor.alternateId",
+			"actor.user.uid":          "actor.id",
+			"src_endpoint.ip":         "client.ipAddress",
+			"src_endpoint.country":    "client.geographicalContext.country",
+			"http_request.user_agent": "client.userAgent.rawUserAgent",
+		},
+		Rules: []normalize.Rule{
+			{
+				Class:    normalize.ClassAuthentication,
+				Activity: normalize.ActivityLogon,
+				Status:   normalize.StatusSuccess,
+				Match: []normalize.Match{
+					{Field: "eventType", Values: []string{"user.session.start"}},
+					{Field: "outcome.result", Values: []string{"SUCCESS", "ALLOW"}},
+				},
+			},
+			{
+				Class:    normalize.ClassAuthentication,
+				Activity: normalize.ActivityLogon,
+				Status:   normalize.StatusFailure,
+				Match: []normalize.Match{
+					{Field: "eventType", Values: []string{"user.session.start"}},
+					{Field: "outcome.result", Values: []string{"FAILURE", "DENY"}},
+				},
+			},
+			{
+				Class:    normalize.ClassAuthentication,
+				Activity: normalize.ActivityLogon,
+				Status:   normalize.StatusOther,
+				Match: []normalize.Match{
+					{Field: "eventType", Values: []string{"user.session.start"}},
+				},
+			},
+			{
+				Class:    normalize.ClassAuthentication,
+				Activity: normalize.ActivityLogoff,
+				Match: []normalize.Match{
+					{Field: "eventType", Values: []string{"user.session.end"}},
+				},
+			},
+		},
+	},
 })
@@ -27,6 +27,9 @@ import (
 	"github.com/stretchr/testify/require"
 	"gopkg.in/go-playground/validator.v9"
 
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/normalize"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
 )
@@ -133,3 +136,31 @@ func TestSystemLogJSONSamples(t *testing.T) {
 		}
 	}
 }
+
+func TestSystemLogNormalization(t *testing.T) {
+	normalizer := logtypes.Normalizer(LogTypes().Find(TypeSystemLog))
+	require.Equal(t, []string{normalize.ClassAuthentication}, normalizer.Classes())
+	// nolint:lll
+	log := `{"eventType":"user.session.start","displayMessage":"User login to Okta","actor":{"id":"00u1","type":"User","alternateId":"alice@example.com"},"client":{"ipAddress":"1.2.3.4","userAgent":{"rawUserAgent":"curl/7.64.1"},"geographicalContext":{"country":"United States"}},"outcome":{"result":"FAILURE","reason":"INVALID_CREDENTIALS"}}`
+	require.Equal(t, &pantherlog.Normalized{
+		Class:    normalize.ClassAuthentication,
+		ClassUID: 3002,
+		Activity: normalize.ActivityLogon,
+		Status:   normalize.StatusFailure,
+		Message:  "User login to Okta",
+		Actor: &pantherlog.NormalizedActor{
+			User: &pantherlog.NormalizedUser{
+				Name: "alice@example.com",
+				UID:  "00u1",
+			},
+		},
+		SrcEndpoint: &pantherlog.NormalizedEndpoint{
+			IP:      "1.2.3.4",
+			Country: "United States",
+		},
+		HTTPRequest: &pantherlog.NormalizedHTTPRequest{
+			UserAgent: "curl/7.64.1",
+		},
+	}, normalizer.Normalize([]byte(log)))
+	require.Nil(t, normalizer.Normalize([]byte(`{"eventType":"user.account.lock"}`)))
+}
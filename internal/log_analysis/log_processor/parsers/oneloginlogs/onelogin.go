@@ -20,6 +20,7 @@ package oneloginlogs
 
 import (
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/normalize"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 )
 
@@ -38,6 +39,35 @@ Panther Enterprise Only
 	NewEvent: func() interface{} {
 		return &OneLogin{}
 	},
+	Normalization: &normalize.Mapping{
+		Fields: map[string]string{
+			"actor.user.name":         "user_name",
+			"actor.user.uid":          "user_id",
+			"src_endpoint.ip":         "ipaddr",
+			"service.name":            "app_name",
+			"http_request.user_agent": "user_agent",
+		},
+		Rules: []normalize.Rule{
+			{
+				// USER_LOGGED_INTO_ONELOGIN
+				Class:    normalize.ClassAuthentication,
+				Activity: normalize.ActivityLogon,
+				Status:   normalize.StatusSuccess,
+				Match: []normalize.Match{
+					{Field: "event_type_id", Values: []string{"5"}},
+				},
+			},
+			{
+				// USER_FAILED_ONELOGIN_AUTHENTICATION
+				Class:    normalize.ClassAuthentication,
+				Activity: normalize.ActivityLogon,
+				Status:   normalize.StatusFailure,
+				Match: []normalize.Match{
+					{Field: "event_type_id", Values: []string{"6"}},
+				},
+			},
+		},
+	},
 })
 
 // nolint:lll
@@ -21,9 +21,39 @@ package oneloginlogs
 import (
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logtesting"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/normalize"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 )
 
 func TestOneLoginEvents(t *testing.T) {
 	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/onelogin_tests.yml")
 }
+
+func TestOneLoginNormalization(t *testing.T) {
+	normalizer := logtypes.Normalizer(LogTypes().Find(TypeOneLogin))
+	log := `{"event_type_id":5,"user_name":"Alice","user_id":42,"ipaddr":"1.2.3.4","app_name":"Slack"}`
+	require.Equal(t, &pantherlog.Normalized{
+		Class:    normalize.ClassAuthentication,
+		ClassUID: 3002,
+		Activity: normalize.ActivityLogon,
+		Status:   normalize.StatusSuccess,
+		Actor: &pantherlog.NormalizedActor{
+			User: &pantherlog.NormalizedUser{
+				Name: "Alice",
+				UID:  "42",
+			},
+		},
+		SrcEndpoint: &pantherlog.NormalizedEndpoint{
+			IP: "1.2.3.4",
+		},
+		Service: &pantherlog.NormalizedService{
+			Name: "Slack",
+		},
+	}, normalizer.Normalize([]byte(log)))
+	require.Equal(t, normalize.StatusFailure, normalizer.Normalize([]byte(`{"event_type_id":6}`)).Status)
+	require.Nil(t, normalizer.Normalize([]byte(`{"event_type_id":13}`)))
+}
@@ -64,6 +64,15 @@ type PantherLog struct {
 	PantherAnySHA1Hashes   PantherAnyString `json:"p_any_sha1_hashes,omitempty" description:"Panther added field with collection of SHA1 hashes associated with the row"`
 	PantherAnyMD5Hashes    PantherAnyString `json:"p_any_md5_hashes,omitempty" description:"Panther added field with collection of MD5 hashes associated with the row"`
 	PantherAnySHA256Hashes PantherAnyString `json:"p_any_sha256_hashes,omitempty" description:"Panther added field with collection of SHA256 hashes of any algorithm associated with the row"`
+
+	// Source metadata is added to the JSON of the result by the log processor, the field only declares the column
+	PantherSourceMetadata map[string]string `json:"p_source_metadata,omitempty" description:"Panther added field with the source metadata"`
+
+	// Normalized event is added to the JSON of the result by the log processor, the field only declares the column
+	PantherNormalized *pantherlog.Normalized `json:"p_normalized,omitempty" description:"Panther added field with the event projected onto a common schema"`
+
+	// Enrichment is added to the JSON of the result by the log processor, the field only declares the column
+	PantherEnrichment pantherlog.Enrichment `json:"p_enrichment,omitempty" description:"Panther added field with rows of lookup tables matching the event"`
 }
 
 type PantherAnyString []string
@@ -0,0 +1,100 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// Alert is an EVE record of a rule match
+// nolint:lll
+type Alert struct {
+	EventHeader
+	Alert            *AlertDetails         `json:"alert" validate:"required" description:"The details of the rule that matched."`
+	Flow             *FlowDetails          `json:"flow" description:"The state of the flow when the alert was raised."`
+	HTTP             *HTTPDetails          `json:"http" description:"The HTTP transaction that triggered the alert."`
+	TLS              *TLSDetails           `json:"tls" description:"The TLS session that triggered the alert."`
+	SSH              *SSHDetails           `json:"ssh" description:"The SSH session that triggered the alert."`
+	SMTP             *SMTPDetails          `json:"smtp" description:"The SMTP transaction that triggered the alert."`
+	Email            *EmailDetails         `json:"email" description:"The email that triggered the alert."`
+	DNS              pantherlog.RawMessage `json:"dns" description:"The DNS transaction that triggered the alert."`
+	Files            pantherlog.RawMessage `json:"files" description:"The files of the transaction that triggered the alert."`
+	Payload          pantherlog.String     `json:"payload" description:"The base64 encoded payload of the packet that triggered the alert."`
+	PayloadPrintable pantherlog.String     `json:"payload_printable" description:"The printable characters of the payload."`
+	Packet           pantherlog.String     `json:"packet" description:"The base64 encoded packet that triggered the alert."`
+	PacketInfo       *PacketInfo           `json:"packet_info" description:"Information about the packet that triggered the alert."`
+	Stream           pantherlog.Int64      `json:"stream" description:"Whether the payload is a reassembled stream."`
+	XFF              pantherlog.String     `json:"xff" panther:"ip" description:"The X-Forwarded-For address of the HTTP transaction."`
+}
+
+// Drop is an EVE record of a packet dropped in IPS mode
+// nolint:lll
+type Drop struct {
+	EventHeader
+	Drop  *DropDetails  `json:"drop" validate:"required" description:"The details of the dropped packet."`
+	Alert *AlertDetails `json:"alert" description:"The details of the rule that caused the drop."`
+}
+
+// nolint:lll
+type AlertDetails struct {
+	Action      pantherlog.String     `json:"action" description:"The action taken (allowed or blocked)."`
+	GID         pantherlog.Int64      `json:"gid" description:"The group id of the rule."`
+	SignatureID pantherlog.Int64      `json:"signature_id" description:"The id of the rule."`
+	Rev         pantherlog.Int64      `json:"rev" description:"The revision of the rule."`
+	Signature   pantherlog.String     `json:"signature" description:"The message of the rule."`
+	Category    pantherlog.String     `json:"category" description:"The classification of the rule."`
+	Severity    pantherlog.Int64      `json:"severity" description:"The priority of the rule."`
+	Rule        pantherlog.String     `json:"rule" description:"The text of the rule."`
+	Metadata    pantherlog.RawMessage `json:"metadata" description:"The metadata keywords of the rule."`
+	Source      *AlertEndpoint        `json:"source" description:"The source of the attack as defined by the rule target keyword."`
+	Target      *AlertEndpoint        `json:"target" description:"The target of the attack as defined by the rule target keyword."`
+}
+
+// nolint:lll
+type AlertEndpoint struct {
+	IP   pantherlog.String `json:"ip" panther:"ip" description:"The IP address of the endpoint."`
+	Port pantherlog.Uint16 `json:"port" description:"The port of the endpoint."`
+}
+
+// nolint:lll
+type PacketInfo struct {
+	Linktype pantherlog.Int64 `json:"linktype" description:"The link type of the packet."`
+}
+
+// nolint:lll
+type DropDetails struct {
+	Len     pantherlog.Int64 `json:"len" description:"The length of the packet."`
+	TOS     pantherlog.Int64 `json:"tos" description:"The IP type of service."`
+	TTL     pantherlog.Int64 `json:"ttl" description:"The IP time to live."`
+	IPID    pantherlog.Int64 `json:"ipid" description:"The IP identification."`
+	TCPSeq  pantherlog.Int64 `json:"tcpseq" description:"The TCP sequence number."`
+	TCPAck  pantherlog.Int64 `json:"tcpack" description:"The TCP acknowledgement number."`
+	TCPWin  pantherlog.Int64 `json:"tcpwin" description:"The TCP window size."`
+	SYN     pantherlog.Bool  `json:"syn" description:"Whether the TCP SYN flag was set."`
+	ACK     pantherlog.Bool  `json:"ack" description:"Whether the TCP ACK flag was set."`
+	PSH     pantherlog.Bool  `json:"psh" description:"Whether the TCP PSH flag was set."`
+	RST     pantherlog.Bool  `json:"rst" description:"Whether the TCP RST flag was set."`
+	URG     pantherlog.Bool  `json:"urg" description:"Whether the TCP URG flag was set."`
+	FIN     pantherlog.Bool  `json:"fin" description:"Whether the TCP FIN flag was set."`
+	TCPRes  pantherlog.Int64 `json:"tcpres" description:"The TCP reserved bits."`
+	TCPUrgP pantherlog.Int64 `json:"tcpurgp" description:"The TCP urgent pointer."`
+	UDPLen  pantherlog.Int64 `json:"udplen" description:"The UDP length."`
+	ICMPID  pantherlog.Int64 `json:"icmp_id" description:"The ICMP id."`
+	ICMPSeq pantherlog.Int64 `json:"icmp_seq" description:"The ICMP sequence number."`
+}
@@ -0,0 +1,53 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// DHCP is an EVE record of a DHCP message
+// nolint:lll
+type DHCP struct {
+	EventHeader
+	DHCP *DHCPDetails `json:"dhcp" validate:"required" description:"The details of the DHCP message."`
+}
+
+// nolint:lll
+type DHCPDetails struct {
+	Type          pantherlog.String   `json:"type" description:"The message direction (request or reply)."`
+	ID            pantherlog.Int64    `json:"id" description:"The DHCP transaction id."`
+	ClientMAC     pantherlog.String   `json:"client_mac" description:"The MAC address of the client."`
+	AssignedIP    pantherlog.String   `json:"assigned_ip" panther:"ip" description:"The IP address assigned to the client."`
+	ClientIP      pantherlog.String   `json:"client_ip" panther:"ip" description:"The IP address of the client."`
+	RelayIP       pantherlog.String   `json:"relay_ip" panther:"ip" description:"The IP address of the relay agent."`
+	NextServerIP  pantherlog.String   `json:"next_server_ip" panther:"ip" description:"The IP address of the next server."`
+	DHCPType      pantherlog.String   `json:"dhcp_type" description:"The DHCP message type."`
+	ClientID      pantherlog.String   `json:"client_id" description:"The client identifier option."`
+	Hostname      pantherlog.String   `json:"hostname" description:"The hostname option."`
+	Params        []pantherlog.String `json:"params" description:"The requested parameters."`
+	RequestedIP   pantherlog.String   `json:"requested_ip" panther:"ip" description:"The IP address requested by the client."`
+	LeaseTime     pantherlog.Int64    `json:"lease_time" description:"The lease time in seconds."`
+	RenewalTime   pantherlog.Int64    `json:"renewal_time" description:"The renewal time in seconds."`
+	RebindingTime pantherlog.Int64    `json:"rebinding_time" description:"The rebinding time in seconds."`
+	SubnetMask    pantherlog.String   `json:"subnet_mask" description:"The subnet mask option."`
+	Routers       []pantherlog.String `json:"routers" panther:"ip" description:"The routers option."`
+	DNSServers    []pantherlog.String `json:"dns_servers" panther:"ip" description:"The DNS servers option."`
+	VendorClassID pantherlog.String   `json:"vendor_class_identifier" description:"The vendor class identifier option."`
+}
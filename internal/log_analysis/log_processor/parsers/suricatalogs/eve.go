@@ -0,0 +1,196 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/pkg/stringset"
+)
+
+// EventHeader holds the fields common to all EVE records
+// nolint:lll
+type EventHeader struct {
+	Timestamp    pantherlog.Time       `json:"timestamp" tcodec:"layout=2006-01-02T15:04:05.999999999Z0700" event_time:"true" validate:"required" description:"The time the event was logged."`
+	EventType    pantherlog.String     `json:"event_type" validate:"required" description:"The type of the EVE record."`
+	FlowID       pantherlog.Int64      `json:"flow_id" description:"The id of the flow the event belongs to."`
+	ParentID     pantherlog.Int64      `json:"parent_id" description:"The id of the parent flow for flows created by an expectation."`
+	PcapCnt      pantherlog.Int64      `json:"pcap_cnt" description:"The packet number in the pcap file or live capture."`
+	PcapFilename pantherlog.String     `json:"pcap_filename" description:"The name of the pcap file the event was read from."`
+	InIface      pantherlog.String     `json:"in_iface" description:"The capture interface the event was seen on."`
+	Vlan         []pantherlog.Int64    `json:"vlan" description:"The VLAN ids of the packet."`
+	SrcIP        pantherlog.String     `json:"src_ip" panther:"ip" description:"The source IP address."`
+	SrcPort      pantherlog.Uint16     `json:"src_port" description:"The source port."`
+	DestIP       pantherlog.String     `json:"dest_ip" panther:"ip" description:"The destination IP address."`
+	DestPort     pantherlog.Uint16     `json:"dest_port" description:"The destination port."`
+	Proto        pantherlog.String     `json:"proto" description:"The transport protocol."`
+	AppProto     pantherlog.String     `json:"app_proto" description:"The application layer protocol of the flow."`
+	AppProtoOrig pantherlog.String     `json:"app_proto_orig" description:"The original application layer protocol if the protocol changed during the flow."`
+	TxID         pantherlog.Int64      `json:"tx_id" description:"The id of the application layer transaction."`
+	IcmpType     pantherlog.Int64      `json:"icmp_type" description:"The ICMP type."`
+	IcmpCode     pantherlog.Int64      `json:"icmp_code" description:"The ICMP code."`
+	CommunityID  pantherlog.String     `json:"community_id" description:"The Community ID flow hash."`
+	Host         pantherlog.String     `json:"host" description:"The name of the sensor that logged the event."`
+	Direction    pantherlog.String     `json:"direction" description:"The direction of the packet or transaction relative to the flow (to_server or to_client)."`
+	Metadata     pantherlog.RawMessage `json:"metadata" description:"The flowbits, flowints and flowvars set on the flow."`
+}
+
+// eveConfig declares a log type for a single EVE event type.
+// Records are dispatched on their `event_type` key before being decoded.
+type eveConfig struct {
+	Name         string
+	Description  string
+	ReferenceURL string
+	EventType    string
+	NewEvent     func() interface{}
+	// Schema and NewParser override the JSON parser built from NewEvent (used by the legacy parsers)
+	Schema    interface{}
+	NewParser pantherlog.LogParserFactory
+
+	dispatcher *eveDispatcher
+}
+
+// BuildEntry implements logtypes.EntryBuilder interface
+func (c eveConfig) BuildEntry() (logtypes.Entry, error) {
+	schema, factory := c.Schema, c.NewParser
+	if factory == nil {
+		if c.NewEvent == nil {
+			return nil, errors.New(`nil event factory`)
+		}
+		s, err := pantherlog.BuildEventSchema(c.NewEvent())
+		if err != nil {
+			return nil, err
+		}
+		schema = s
+		factory = &pantherlog.JSONParserFactory{
+			LogType:  c.Name,
+			NewEvent: c.NewEvent,
+		}
+	}
+	d := c.dispatcher
+	d.factories[c.Name] = factory
+	logType := c.Name
+	return logtypes.Config{
+		Name:         c.Name,
+		Description:  c.Description,
+		ReferenceURL: c.ReferenceURL,
+		Schema:       schema,
+		NewParser: pantherlog.FactoryFunc(func(_ interface{}) (pantherlog.LogParser, error) {
+			return d.NewDispatchParser([]string{logType})
+		}),
+		Dispatcher: d,
+	}.BuildEntry()
+}
+
+// eveLogTypes builds the log type entries of the EVE event types and the index used to dispatch records.
+func eveLogTypes(configs ...eveConfig) (logtypes.Group, map[string]string) {
+	d := &eveDispatcher{
+		eventTypes: make(map[string]string, len(configs)),
+		factories:  make(map[string]pantherlog.LogParserFactory, len(configs)),
+	}
+	builders := make([]logtypes.EntryBuilder, 0, len(configs))
+	for _, c := range configs {
+		if _, duplicate := d.eventTypes[c.EventType]; duplicate {
+			panic(errors.Errorf("duplicate EVE event type %q", c.EventType))
+		}
+		d.eventTypes[c.EventType] = c.Name
+		c.dispatcher = d
+		builders = append(builders, c)
+	}
+	return logtypes.Must(LogTypePrefix, builders...), d.eventTypes
+}
+
+// eveDispatcher creates a single parser for the EVE records of multiple event types.
+// It implements logtypes.Dispatcher so that sources try one parser for all the Suricata log types they use.
+type eveDispatcher struct {
+	// eventTypes maps each EVE event type to the name of its log type
+	eventTypes map[string]string
+	// factories are the parser factories of each log type
+	factories map[string]pantherlog.LogParserFactory
+}
+
+var _ logtypes.Dispatcher = (*eveDispatcher)(nil)
+
+// Name implements logtypes.Dispatcher interface
+func (d *eveDispatcher) Name() string {
+	return LogTypePrefix
+}
+
+// NewDispatchParser implements logtypes.Dispatcher interface
+func (d *eveDispatcher) NewDispatchParser(logTypes []string) (pantherlog.LogParser, error) {
+	p := &eveParser{
+		parsers: make(map[string]pantherlog.LogParser, len(logTypes)),
+		iter:    jsoniter.NewIterator(jsoniter.ConfigDefault),
+	}
+	for eventType, logType := range d.eventTypes {
+		if !stringset.Contains(logTypes, logType) {
+			continue
+		}
+		parser, err := d.factories[logType].NewParser(nil)
+		if err != nil {
+			return nil, err
+		}
+		p.parsers[eventType] = parser
+	}
+	if len(p.parsers) != len(stringset.New(logTypes...)) {
+		return nil, errors.Errorf("invalid EVE log types %q", logTypes)
+	}
+	return p, nil
+}
+
+// eveParser reads the `event_type` of a record once and delegates to the parser of the event type.
+// Records of other event types are rejected without being decoded.
+type eveParser struct {
+	// parsers of each event type
+	parsers map[string]pantherlog.LogParser
+	iter    *jsoniter.Iterator
+}
+
+// ParseLog implements pantherlog.LogParser interface
+func (p *eveParser) ParseLog(log string) ([]*pantherlog.Result, error) {
+	eventType := p.peekEventType(log)
+	if parser, ok := p.parsers[eventType]; ok {
+		return parser.ParseLog(log)
+	}
+	logType, ok := eveEventTypes[eventType]
+	if !ok {
+		return nil, errors.Errorf("unknown EVE event type %q", eventType)
+	}
+	return nil, errors.Errorf("EVE event type %q is parsed by %s", eventType, logType)
+}
+
+// peekEventType reads the `event_type` of an EVE record skipping over all other fields
+func (p *eveParser) peekEventType(log string) string {
+	iter := p.iter
+	iter.ResetBytes([]byte(log))
+	iter.Error = nil
+	for key := iter.ReadObject(); key != ""; key = iter.ReadObject() {
+		if key == "event_type" {
+			if iter.WhatIsNext() != jsoniter.StringValue {
+				return ""
+			}
+			return iter.ReadString()
+		}
+		iter.Skip()
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// FileInfo is an EVE record of a file transferred over a flow
+// nolint:lll
+type FileInfo struct {
+	EventHeader
+	FileInfo *FileInfoDetails `json:"fileinfo" validate:"required" description:"The details of the file."`
+	HTTP     *HTTPDetails     `json:"http" description:"The HTTP transaction the file was transferred in."`
+	SMTP     *SMTPDetails     `json:"smtp" description:"The SMTP transaction the file was transferred in."`
+	Email    *EmailDetails    `json:"email" description:"The email the file was attached to."`
+}
+
+// nolint:lll
+type FileInfoDetails struct {
+	Filename pantherlog.String  `json:"filename" description:"The name of the file."`
+	SID      []pantherlog.Int64 `json:"sid" description:"The ids of the rules that matched the file."`
+	Gaps     pantherlog.Bool    `json:"gaps" description:"Whether the file has gaps."`
+	State    pantherlog.String  `json:"state" description:"The state of the file (CLOSED, TRUNCATED or ERROR)."`
+	Magic    pantherlog.String  `json:"magic" description:"The libmagic description of the file."`
+	MD5      pantherlog.String  `json:"md5" panther:"md5" description:"The MD5 hash of the file."`
+	SHA1     pantherlog.String  `json:"sha1" panther:"sha1" description:"The SHA1 hash of the file."`
+	SHA256   pantherlog.String  `json:"sha256" panther:"sha256" description:"The SHA256 hash of the file."`
+	Stored   pantherlog.Bool    `json:"stored" description:"Whether the file was stored to disk."`
+	FileID   pantherlog.Int64   `json:"file_id" description:"The id of the stored file."`
+	Size     pantherlog.Int64   `json:"size" description:"The size of the file in bytes."`
+	TxID     pantherlog.Int64   `json:"tx_id" description:"The id of the transaction the file was transferred in."`
+	Start    pantherlog.Int64   `json:"start" description:"The start offset of a ranged file transfer."`
+	End      pantherlog.Int64   `json:"end" description:"The end offset of a ranged file transfer."`
+}
@@ -0,0 +1,82 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// Flow is an EVE record of a completed or timed out flow
+// nolint:lll
+type Flow struct {
+	EventHeader
+	Flow *FlowDetails `json:"flow" validate:"required" description:"The details of the flow."`
+	TCP  *TCPDetails  `json:"tcp" description:"The TCP flags and state of the flow."`
+}
+
+// Netflow is an EVE record of a unidirectional flow
+// nolint:lll
+type Netflow struct {
+	EventHeader
+	Netflow *NetflowDetails `json:"netflow" validate:"required" description:"The details of the unidirectional flow."`
+	TCP     *TCPDetails     `json:"tcp" description:"The TCP flags of the flow."`
+}
+
+// nolint:lll
+type FlowDetails struct {
+	PktsToServer  pantherlog.Int64  `json:"pkts_toserver" description:"The number of packets sent to the server."`
+	PktsToClient  pantherlog.Int64  `json:"pkts_toclient" description:"The number of packets sent to the client."`
+	BytesToServer pantherlog.Int64  `json:"bytes_toserver" description:"The number of bytes sent to the server."`
+	BytesToClient pantherlog.Int64  `json:"bytes_toclient" description:"The number of bytes sent to the client."`
+	Start         pantherlog.Time   `json:"start" tcodec:"layout=2006-01-02T15:04:05.999999999Z0700" description:"The time of the first packet of the flow."`
+	End           pantherlog.Time   `json:"end" tcodec:"layout=2006-01-02T15:04:05.999999999Z0700" description:"The time of the last packet of the flow."`
+	Age           pantherlog.Int64  `json:"age" description:"The duration of the flow in seconds."`
+	State         pantherlog.String `json:"state" description:"The state of the flow (new, established, closed or bypassed)."`
+	Reason        pantherlog.String `json:"reason" description:"The reason the flow was logged (timeout, forced or shutdown)."`
+	Alerted       pantherlog.Bool   `json:"alerted" description:"Whether an alert was raised for the flow."`
+	Bypass        pantherlog.String `json:"bypass" description:"The type of bypass applied to the flow."`
+	Emergency     pantherlog.Bool   `json:"emergency" description:"Whether the flow engine was in emergency mode."`
+}
+
+// nolint:lll
+type NetflowDetails struct {
+	Pkts   pantherlog.Int64 `json:"pkts" description:"The number of packets in the flow."`
+	Bytes  pantherlog.Int64 `json:"bytes" description:"The number of bytes in the flow."`
+	Start  pantherlog.Time  `json:"start" tcodec:"layout=2006-01-02T15:04:05.999999999Z0700" description:"The time of the first packet of the flow."`
+	End    pantherlog.Time  `json:"end" tcodec:"layout=2006-01-02T15:04:05.999999999Z0700" description:"The time of the last packet of the flow."`
+	Age    pantherlog.Int64 `json:"age" description:"The duration of the flow in seconds."`
+	MinTTL pantherlog.Int64 `json:"min_ttl" description:"The minimum IP time to live seen in the flow."`
+	MaxTTL pantherlog.Int64 `json:"max_ttl" description:"The maximum IP time to live seen in the flow."`
+}
+
+// nolint:lll
+type TCPDetails struct {
+	TCPFlags   pantherlog.String `json:"tcp_flags" description:"The TCP flags seen in both directions as a hex string."`
+	TCPFlagsTS pantherlog.String `json:"tcp_flags_ts" description:"The TCP flags seen to the server as a hex string."`
+	TCPFlagsTC pantherlog.String `json:"tcp_flags_tc" description:"The TCP flags seen to the client as a hex string."`
+	SYN        pantherlog.Bool   `json:"syn" description:"Whether the SYN flag was seen."`
+	FIN        pantherlog.Bool   `json:"fin" description:"Whether the FIN flag was seen."`
+	RST        pantherlog.Bool   `json:"rst" description:"Whether the RST flag was seen."`
+	PSH        pantherlog.Bool   `json:"psh" description:"Whether the PSH flag was seen."`
+	ACK        pantherlog.Bool   `json:"ack" description:"Whether the ACK flag was seen."`
+	URG        pantherlog.Bool   `json:"urg" description:"Whether the URG flag was seen."`
+	ECN        pantherlog.Bool   `json:"ecn" description:"Whether the ECN flag was seen."`
+	CWR        pantherlog.Bool   `json:"cwr" description:"Whether the CWR flag was seen."`
+	State      pantherlog.String `json:"state" description:"The TCP state of the flow."`
+}
@@ -0,0 +1,54 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// FTP is an EVE record of an FTP command
+// nolint:lll
+type FTP struct {
+	EventHeader
+	FTP *FTPDetails `json:"ftp" validate:"required" description:"The details of the FTP command."`
+}
+
+// FTPData is an EVE record of an FTP data transfer
+// nolint:lll
+type FTPData struct {
+	EventHeader
+	FTPData *FTPDataDetails `json:"ftp_data" validate:"required" description:"The details of the FTP data transfer."`
+}
+
+// nolint:lll
+type FTPDetails struct {
+	Command        pantherlog.String   `json:"command" description:"The FTP command."`
+	CommandData    pantherlog.String   `json:"command_data" description:"The argument of the FTP command."`
+	Reply          []pantherlog.String `json:"reply" description:"The replies of the server."`
+	CompletionCode []pantherlog.String `json:"completion_code" description:"The completion codes of the replies."`
+	DynamicPort    pantherlog.Uint16   `json:"dynamic_port" description:"The port negotiated for the data transfer."`
+	Mode           pantherlog.String   `json:"mode" description:"The data transfer mode (active or passive)."`
+	ReplyReceived  pantherlog.String   `json:"reply_received" description:"Whether a reply was received (yes or no)."`
+}
+
+// nolint:lll
+type FTPDataDetails struct {
+	Filename pantherlog.String `json:"filename" description:"The name of the transferred file."`
+	Command  pantherlog.String `json:"command" description:"The FTP command that started the transfer."`
+}
@@ -0,0 +1,57 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// HTTP is an EVE record of an HTTP transaction
+// nolint:lll
+type HTTP struct {
+	EventHeader
+	HTTP *HTTPDetails `json:"http" validate:"required" description:"The details of the HTTP transaction."`
+}
+
+// nolint:lll
+type HTTPDetails struct {
+	Hostname         pantherlog.String     `json:"hostname" panther:"hostname" description:"The hostname of the request."`
+	HTTPPort         pantherlog.Uint16     `json:"http_port" description:"The port of the request if it differs from the destination port."`
+	URL              pantherlog.String     `json:"url" description:"The URL of the request."`
+	HTTPUserAgent    pantherlog.String     `json:"http_user_agent" description:"The User-Agent header of the request."`
+	HTTPContentType  pantherlog.String     `json:"http_content_type" description:"The Content-Type header of the response."`
+	HTTPMethod       pantherlog.String     `json:"http_method" description:"The method of the request."`
+	HTTPRefer        pantherlog.String     `json:"http_refer" panther:"url" description:"The Referer header of the request."`
+	Protocol         pantherlog.String     `json:"protocol" description:"The protocol version of the request."`
+	Status           pantherlog.Int64      `json:"status" description:"The status code of the response."`
+	Length           pantherlog.Int64      `json:"length" description:"The length of the response body."`
+	Redirect         pantherlog.String     `json:"redirect" panther:"url" description:"The Location header of the response."`
+	XFF              pantherlog.String     `json:"xff" panther:"ip" description:"The X-Forwarded-For header of the request."`
+	RequestHeaders   []HTTPHeader          `json:"request_headers" description:"The headers of the request."`
+	ResponseHeaders  []HTTPHeader          `json:"response_headers" description:"The headers of the response."`
+	HTTPRequestBody  pantherlog.String     `json:"http_request_body" description:"The base64 encoded body of the request."`
+	HTTPResponseBody pantherlog.String     `json:"http_response_body" description:"The base64 encoded body of the response."`
+	HTTP2            pantherlog.RawMessage `json:"http2" description:"The HTTP/2 frames of the transaction."`
+}
+
+// nolint:lll
+type HTTPHeader struct {
+	Name  pantherlog.String `json:"name" description:"The name of the header."`
+	Value pantherlog.String `json:"value" description:"The value of the header."`
+}
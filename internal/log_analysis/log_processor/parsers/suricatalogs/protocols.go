@@ -0,0 +1,181 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// The records of application protocols with deeply nested or version dependent details
+// keep those details as raw JSON.
+
+// DCERPC is an EVE record of a DCE/RPC transaction
+// nolint:lll
+type DCERPC struct {
+	EventHeader
+	DCERPC pantherlog.RawMessage `json:"dcerpc" validate:"required" description:"The details of the DCE/RPC transaction."`
+}
+
+// DNP3 is an EVE record of a DNP3 message
+// nolint:lll
+type DNP3 struct {
+	EventHeader
+	DNP3 pantherlog.RawMessage `json:"dnp3" validate:"required" description:"The details of the DNP3 message."`
+}
+
+// IKEv2 is an EVE record of an IKEv2 exchange
+// nolint:lll
+type IKEv2 struct {
+	EventHeader
+	IKEv2 *IKEv2Details `json:"ikev2" validate:"required" description:"The details of the IKEv2 exchange."`
+}
+
+// nolint:lll
+type IKEv2Details struct {
+	VersionMajor pantherlog.Int64      `json:"version_major" description:"The major version of the protocol."`
+	VersionMinor pantherlog.Int64      `json:"version_minor" description:"The minor version of the protocol."`
+	ExchangeType pantherlog.Int64      `json:"exchange_type" description:"The type of the exchange."`
+	MessageID    pantherlog.Int64      `json:"message_id" description:"The id of the message."`
+	InitSPI      pantherlog.String     `json:"init_spi" description:"The SPI of the initiator."`
+	RespSPI      pantherlog.String     `json:"resp_spi" description:"The SPI of the responder."`
+	Role         pantherlog.String     `json:"role" description:"The role of the sender (initiator or responder)."`
+	Errors       pantherlog.Int64      `json:"errors" description:"The number of errors in the exchange."`
+	AlgEnc       pantherlog.String     `json:"alg_enc" description:"The encryption algorithm."`
+	AlgAuth      pantherlog.String     `json:"alg_auth" description:"The authentication algorithm."`
+	AlgPRF       pantherlog.String     `json:"alg_prf" description:"The pseudo random function."`
+	AlgDH        pantherlog.String     `json:"alg_dh" description:"The Diffie-Hellman group."`
+	AlgESN       pantherlog.String     `json:"alg_esn" description:"The extended sequence numbers setting."`
+	Notify       pantherlog.RawMessage `json:"notify" description:"The notify payloads of the exchange."`
+}
+
+// KRB5 is an EVE record of a Kerberos 5 message
+// nolint:lll
+type KRB5 struct {
+	EventHeader
+	KRB5 *KRB5Details `json:"krb5" validate:"required" description:"The details of the Kerberos 5 message."`
+}
+
+// nolint:lll
+type KRB5Details struct {
+	MsgType        pantherlog.String `json:"msg_type" description:"The type of the message."`
+	FailedRequest  pantherlog.String `json:"failed_request" description:"The type of the request that failed."`
+	ErrorCode      pantherlog.String `json:"error_code" description:"The error code of the reply."`
+	CName          pantherlog.String `json:"cname" description:"The client principal name."`
+	Realm          pantherlog.String `json:"realm" panther:"domain" description:"The realm of the principal."`
+	SName          pantherlog.String `json:"sname" description:"The server principal name."`
+	Encryption     pantherlog.String `json:"encryption" description:"The encryption type of the ticket."`
+	WeakEncryption pantherlog.Bool   `json:"weak_encryption" description:"Whether the encryption type is considered weak."`
+}
+
+// MQTT is an EVE record of an MQTT message
+// nolint:lll
+type MQTT struct {
+	EventHeader
+	MQTT pantherlog.RawMessage `json:"mqtt" validate:"required" description:"The details of the MQTT message."`
+}
+
+// NFS is an EVE record of an NFS transaction
+// nolint:lll
+type NFS struct {
+	EventHeader
+	NFS *NFSDetails           `json:"nfs" validate:"required" description:"The details of the NFS transaction."`
+	RPC pantherlog.RawMessage `json:"rpc" description:"The details of the RPC call."`
+}
+
+// nolint:lll
+type NFSDetails struct {
+	Version   pantherlog.Int64      `json:"version" description:"The NFS version."`
+	Procedure pantherlog.String     `json:"procedure" description:"The NFS procedure."`
+	Filename  pantherlog.String     `json:"filename" description:"The name of the file the procedure applies to."`
+	ID        pantherlog.Int64      `json:"id" description:"The id of the transaction."`
+	FileTx    pantherlog.Bool       `json:"file_tx" description:"Whether the transaction transferred a file."`
+	Type      pantherlog.String     `json:"type" description:"The type of the message (request or response)."`
+	Status    pantherlog.String     `json:"status" description:"The status of the response."`
+	HHash     pantherlog.String     `json:"hhash" description:"The hash of the file handle."`
+	Rename    pantherlog.RawMessage `json:"rename" description:"The source and destination of a rename."`
+}
+
+// RDP is an EVE record of an RDP message
+// nolint:lll
+type RDP struct {
+	EventHeader
+	RDP pantherlog.RawMessage `json:"rdp" validate:"required" description:"The details of the RDP message."`
+}
+
+// RFB is an EVE record of an RFB (VNC) session
+// nolint:lll
+type RFB struct {
+	EventHeader
+	RFB pantherlog.RawMessage `json:"rfb" validate:"required" description:"The details of the RFB session."`
+}
+
+// SIP is an EVE record of a SIP message
+// nolint:lll
+type SIP struct {
+	EventHeader
+	SIP *SIPDetails `json:"sip" validate:"required" description:"The details of the SIP message."`
+}
+
+// nolint:lll
+type SIPDetails struct {
+	Method       pantherlog.String `json:"method" description:"The method of the request."`
+	URI          pantherlog.String `json:"uri" description:"The URI of the request."`
+	Version      pantherlog.String `json:"version" description:"The SIP version."`
+	Code         pantherlog.String `json:"code" description:"The status code of the response."`
+	Reason       pantherlog.String `json:"reason" description:"The reason phrase of the response."`
+	RequestLine  pantherlog.String `json:"request_line" description:"The request line."`
+	ResponseLine pantherlog.String `json:"response_line" description:"The response line."`
+}
+
+// SMB is an EVE record of an SMB command
+// nolint:lll
+type SMB struct {
+	EventHeader
+	SMB pantherlog.RawMessage `json:"smb" validate:"required" description:"The details of the SMB command."`
+}
+
+// SNMP is an EVE record of an SNMP message
+// nolint:lll
+type SNMP struct {
+	EventHeader
+	SNMP *SNMPDetails `json:"snmp" validate:"required" description:"The details of the SNMP message."`
+}
+
+// nolint:lll
+type SNMPDetails struct {
+	Version   pantherlog.Int64    `json:"version" description:"The SNMP version."`
+	PDUType   pantherlog.String   `json:"pdu_type" description:"The type of the PDU."`
+	Vars      []pantherlog.String `json:"vars" description:"The OIDs of the variable bindings."`
+	Community pantherlog.String   `json:"community" description:"The community string."`
+	Usm       pantherlog.String   `json:"usm" description:"The user of the user based security model."`
+}
+
+// TFTP is an EVE record of a TFTP request
+// nolint:lll
+type TFTP struct {
+	EventHeader
+	TFTP *TFTPDetails `json:"tftp" validate:"required" description:"The details of the TFTP request."`
+}
+
+// nolint:lll
+type TFTPDetails struct {
+	Packet pantherlog.String `json:"packet" description:"The type of the request (read or write)."`
+	File   pantherlog.String `json:"file" description:"The name of the requested file."`
+	Mode   pantherlog.String `json:"mode" description:"The transfer mode."`
+}
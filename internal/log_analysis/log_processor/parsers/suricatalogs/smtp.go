@@ -0,0 +1,53 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// SMTP is an EVE record of an SMTP transaction
+// nolint:lll
+type SMTP struct {
+	EventHeader
+	SMTP  *SMTPDetails  `json:"smtp" validate:"required" description:"The details of the SMTP transaction."`
+	Email *EmailDetails `json:"email" description:"The email sent in the transaction."`
+}
+
+// nolint:lll
+type SMTPDetails struct {
+	Helo     pantherlog.String   `json:"helo" panther:"hostname" description:"The HELO or EHLO argument of the client."`
+	MailFrom pantherlog.String   `json:"mail_from" description:"The MAIL FROM address."`
+	RcptTo   []pantherlog.String `json:"rcpt_to" description:"The RCPT TO addresses."`
+}
+
+// nolint:lll
+type EmailDetails struct {
+	Status     pantherlog.String   `json:"status" description:"The status of the email parsing."`
+	From       pantherlog.String   `json:"from" description:"The From header of the email."`
+	To         []pantherlog.String `json:"to" description:"The To header of the email."`
+	CC         []pantherlog.String `json:"cc" description:"The Cc header of the email."`
+	Subject    pantherlog.String   `json:"subject" description:"The Subject header of the email."`
+	SubjectMD5 pantherlog.String   `json:"subject_md5" description:"The MD5 hash of the Subject header."`
+	BodyMD5    pantherlog.String   `json:"body_md5" description:"The MD5 hash of the email body."`
+	MessageID  pantherlog.String   `json:"message_id" description:"The Message-Id header of the email."`
+	XMailer    pantherlog.String   `json:"x_mailer" description:"The X-Mailer header of the email."`
+	Attachment []pantherlog.String `json:"attachment" description:"The filenames of the email attachments."`
+	URL        []pantherlog.String `json:"url" panther:"url" description:"The URLs found in the email body."`
+}
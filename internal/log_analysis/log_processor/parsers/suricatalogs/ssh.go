@@ -0,0 +1,49 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// SSH is an EVE record of an SSH handshake
+// nolint:lll
+type SSH struct {
+	EventHeader
+	SSH *SSHDetails `json:"ssh" validate:"required" description:"The details of the SSH handshake."`
+}
+
+// nolint:lll
+type SSHDetails struct {
+	Client *SSHEndpoint `json:"client" description:"The SSH client."`
+	Server *SSHEndpoint `json:"server" description:"The SSH server."`
+}
+
+// nolint:lll
+type SSHEndpoint struct {
+	ProtoVersion    pantherlog.String `json:"proto_version" description:"The SSH protocol version."`
+	SoftwareVersion pantherlog.String `json:"software_version" description:"The SSH software version."`
+	HASSH           *HASSHDetails     `json:"hassh" description:"The HASSH fingerprint of the endpoint."`
+}
+
+// nolint:lll
+type HASSHDetails struct {
+	Hash   pantherlog.String `json:"hash" panther:"md5" description:"The MD5 hash of the HASSH string."`
+	String pantherlog.String `json:"string" description:"The HASSH string."`
+}
@@ -24,27 +24,287 @@ import (
 )
 
 const (
-	TypeDNS     = "Suricata.DNS"
-	TypeAnomaly = "Suricata.Anomaly"
+	// LogTypePrefix is the prefix of all logs parsed by this package and the name of the log type group
+	LogTypePrefix = "Suricata"
+	TypeAlert     = LogTypePrefix + ".Alert"
+	TypeAnomaly   = LogTypePrefix + ".Anomaly"
+	TypeDCERPC    = LogTypePrefix + ".DCERPC"
+	TypeDHCP      = LogTypePrefix + ".DHCP"
+	TypeDNP3      = LogTypePrefix + ".DNP3"
+	TypeDNS       = LogTypePrefix + ".DNS"
+	TypeDrop      = LogTypePrefix + ".Drop"
+	TypeFileInfo  = LogTypePrefix + ".FileInfo"
+	TypeFlow      = LogTypePrefix + ".Flow"
+	TypeFTP       = LogTypePrefix + ".FTP"
+	TypeFTPData   = LogTypePrefix + ".FTPData"
+	TypeHTTP      = LogTypePrefix + ".HTTP"
+	TypeIKEv2     = LogTypePrefix + ".IKEv2"
+	TypeKRB5      = LogTypePrefix + ".KRB5"
+	TypeMQTT      = LogTypePrefix + ".MQTT"
+	TypeNetflow   = LogTypePrefix + ".Netflow"
+	TypeNFS       = LogTypePrefix + ".NFS"
+	TypeRDP       = LogTypePrefix + ".RDP"
+	TypeRFB       = LogTypePrefix + ".RFB"
+	TypeSIP       = LogTypePrefix + ".SIP"
+	TypeSMB       = LogTypePrefix + ".SMB"
+	TypeSMTP      = LogTypePrefix + ".SMTP"
+	TypeSNMP      = LogTypePrefix + ".SNMP"
+	TypeSSH       = LogTypePrefix + ".SSH"
+	TypeStats     = LogTypePrefix + ".Stats"
+	TypeTFTP      = LogTypePrefix + ".TFTP"
+	TypeTLS       = LogTypePrefix + ".TLS"
 )
 
+// LogTypes exports the available log type entries
 func LogTypes() logtypes.Group {
 	return logTypes
 }
 
-var logTypes = logtypes.Must("Suricata",
-	logtypes.Config{
+const referenceURL = `https://suricata.readthedocs.io/en/suricata-6.0.0/output/eve/eve-json-format.html`
+
+// All log types are dispatched on the `event_type` of the EVE record so that
+// records are not trial-parsed by the parsers of other event types.
+// eveEventTypes maps each EVE event type to the name of its log type.
+var logTypes, eveEventTypes = eveLogTypes(
+	eveConfig{
+		Name:         TypeAlert,
+		Description:  `Suricata parser for the Alert event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-alert`,
+		EventType:    "alert",
+		NewEvent: func() interface{} {
+			return &Alert{}
+		},
+	},
+	eveConfig{
 		Name:         TypeAnomaly,
 		Description:  `Suricata parser for the Anomaly event type in the EVE JSON output.`,
-		ReferenceURL: `https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-output.html#anomaly`,
+		ReferenceURL: referenceURL + `#event-type-anomaly`,
+		EventType:    "anomaly",
 		Schema:       Anomaly{},
 		NewParser:    parsers.AdapterFactory(&AnomalyParser{}),
 	},
-	logtypes.Config{
+	eveConfig{
+		Name:         TypeDCERPC,
+		Description:  `Suricata parser for the DCERPC event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-dcerpc`,
+		EventType:    "dcerpc",
+		NewEvent: func() interface{} {
+			return &DCERPC{}
+		},
+	},
+	eveConfig{
+		Name:         TypeDHCP,
+		Description:  `Suricata parser for the DHCP event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-dhcp`,
+		EventType:    "dhcp",
+		NewEvent: func() interface{} {
+			return &DHCP{}
+		},
+	},
+	eveConfig{
+		Name:         TypeDNP3,
+		Description:  `Suricata parser for the DNP3 event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-dnp3`,
+		EventType:    "dnp3",
+		NewEvent: func() interface{} {
+			return &DNP3{}
+		},
+	},
+	eveConfig{
 		Name:         TypeDNS,
 		Description:  `Suricata parser for the DNS event type in the EVE JSON output.`,
-		ReferenceURL: `https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-output.html#dns`,
+		ReferenceURL: referenceURL + `#event-type-dns`,
+		EventType:    "dns",
 		Schema:       DNS{},
 		NewParser:    parsers.AdapterFactory(&DNSParser{}),
 	},
+	eveConfig{
+		Name:         TypeDrop,
+		Description:  `Suricata parser for the Drop event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-drop`,
+		EventType:    "drop",
+		NewEvent: func() interface{} {
+			return &Drop{}
+		},
+	},
+	eveConfig{
+		Name:         TypeFileInfo,
+		Description:  `Suricata parser for the FileInfo event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-fileinfo`,
+		EventType:    "fileinfo",
+		NewEvent: func() interface{} {
+			return &FileInfo{}
+		},
+	},
+	eveConfig{
+		Name:         TypeFlow,
+		Description:  `Suricata parser for the Flow event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-flow`,
+		EventType:    "flow",
+		NewEvent: func() interface{} {
+			return &Flow{}
+		},
+	},
+	eveConfig{
+		Name:         TypeFTP,
+		Description:  `Suricata parser for the FTP event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-ftp`,
+		EventType:    "ftp",
+		NewEvent: func() interface{} {
+			return &FTP{}
+		},
+	},
+	eveConfig{
+		Name:         TypeFTPData,
+		Description:  `Suricata parser for the FTP data event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-ftp-data`,
+		EventType:    "ftp_data",
+		NewEvent: func() interface{} {
+			return &FTPData{}
+		},
+	},
+	eveConfig{
+		Name:         TypeHTTP,
+		Description:  `Suricata parser for the HTTP event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-http`,
+		EventType:    "http",
+		NewEvent: func() interface{} {
+			return &HTTP{}
+		},
+	},
+	eveConfig{
+		Name:         TypeIKEv2,
+		Description:  `Suricata parser for the IKEv2 event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-ikev2`,
+		EventType:    "ikev2",
+		NewEvent: func() interface{} {
+			return &IKEv2{}
+		},
+	},
+	eveConfig{
+		Name:         TypeKRB5,
+		Description:  `Suricata parser for the Kerberos 5 event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-krb5`,
+		EventType:    "krb5",
+		NewEvent: func() interface{} {
+			return &KRB5{}
+		},
+	},
+	eveConfig{
+		Name:         TypeMQTT,
+		Description:  `Suricata parser for the MQTT event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-mqtt`,
+		EventType:    "mqtt",
+		NewEvent: func() interface{} {
+			return &MQTT{}
+		},
+	},
+	eveConfig{
+		Name:         TypeNetflow,
+		Description:  `Suricata parser for the Netflow event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-netflow`,
+		EventType:    "netflow",
+		NewEvent: func() interface{} {
+			return &Netflow{}
+		},
+	},
+	eveConfig{
+		Name:         TypeNFS,
+		Description:  `Suricata parser for the NFS event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-nfs`,
+		EventType:    "nfs",
+		NewEvent: func() interface{} {
+			return &NFS{}
+		},
+	},
+	eveConfig{
+		Name:         TypeRDP,
+		Description:  `Suricata parser for the RDP event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-rdp`,
+		EventType:    "rdp",
+		NewEvent: func() interface{} {
+			return &RDP{}
+		},
+	},
+	eveConfig{
+		Name:         TypeRFB,
+		Description:  `Suricata parser for the RFB event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-rfb`,
+		EventType:    "rfb",
+		NewEvent: func() interface{} {
+			return &RFB{}
+		},
+	},
+	eveConfig{
+		Name:         TypeSIP,
+		Description:  `Suricata parser for the SIP event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-sip`,
+		EventType:    "sip",
+		NewEvent: func() interface{} {
+			return &SIP{}
+		},
+	},
+	eveConfig{
+		Name:         TypeSMB,
+		Description:  `Suricata parser for the SMB event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-smb`,
+		EventType:    "smb",
+		NewEvent: func() interface{} {
+			return &SMB{}
+		},
+	},
+	eveConfig{
+		Name:         TypeSMTP,
+		Description:  `Suricata parser for the SMTP event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-smtp`,
+		EventType:    "smtp",
+		NewEvent: func() interface{} {
+			return &SMTP{}
+		},
+	},
+	eveConfig{
+		Name:         TypeSNMP,
+		Description:  `Suricata parser for the SNMP event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-snmp`,
+		EventType:    "snmp",
+		NewEvent: func() interface{} {
+			return &SNMP{}
+		},
+	},
+	eveConfig{
+		Name:         TypeSSH,
+		Description:  `Suricata parser for the SSH event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-ssh`,
+		EventType:    "ssh",
+		NewEvent: func() interface{} {
+			return &SSH{}
+		},
+	},
+	eveConfig{
+		Name:         TypeStats,
+		Description:  `Suricata parser for the Stats event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-stats`,
+		EventType:    "stats",
+		NewEvent: func() interface{} {
+			return &Stats{}
+		},
+	},
+	eveConfig{
+		Name:         TypeTFTP,
+		Description:  `Suricata parser for the TFTP event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-tftp`,
+		EventType:    "tftp",
+		NewEvent: func() interface{} {
+			return &TFTP{}
+		},
+	},
+	eveConfig{
+		Name:         TypeTLS,
+		Description:  `Suricata parser for the TLS event type in the EVE JSON output.`,
+		ReferenceURL: referenceURL + `#event-type-tls`,
+		EventType:    "tls",
+		NewEvent: func() interface{} {
+			return &TLS{}
+		},
+	},
 )
@@ -0,0 +1,135 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logtesting"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+)
+
+func TestAlert(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/alert_tests.yml")
+}
+func TestDHCP(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/dhcp_tests.yml")
+}
+func TestDrop(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/drop_tests.yml")
+}
+func TestFileInfo(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/fileinfo_tests.yml")
+}
+func TestFlow(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/flow_tests.yml")
+}
+func TestHTTP(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/http_tests.yml")
+}
+func TestNetflow(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/netflow_tests.yml")
+}
+func TestSMTP(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/smtp_tests.yml")
+}
+func TestSSH(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/ssh_tests.yml")
+}
+func TestStats(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/stats_tests.yml")
+}
+func TestTLS(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/tls_tests.yml")
+}
+
+// Tests that EVE records are only parsed by the log type of their event type
+func TestEventTypeDispatch(t *testing.T) {
+	samples := testutil.MustReadFileJSONLines("testdata/eve_samples.jsonl")
+	expect := []string{
+		TypeAlert,
+		TypeFlow,
+		TypeNetflow,
+		TypeHTTP,
+		TypeTLS,
+		TypeFileInfo,
+		TypeSMTP,
+		TypeSSH,
+		TypeDHCP,
+		TypeDrop,
+		TypeStats,
+		TypeDNS,
+		TypeAnomaly,
+	}
+	require.Len(t, samples, len(expect))
+	for i, sample := range samples {
+		var matched []string
+		for _, entry := range LogTypes().Entries() {
+			parser, err := entry.NewParser(nil)
+			require.NoError(t, err)
+			if _, err := parser.ParseLog(sample); err == nil {
+				matched = append(matched, entry.String())
+			}
+		}
+		require.Equal(t, []string{expect[i]}, matched, "sample %d", i)
+	}
+}
+
+func TestEventTypeParser(t *testing.T) {
+	parser, err := LogTypes().Find(TypeFlow).NewParser(nil)
+	require.NoError(t, err)
+	_, err = parser.ParseLog(`{"event_type":"alert","timestamp":"2020-06-05T14:41:00.122516+0000","flow":{}}`)
+	require.EqualError(t, err, `EVE event type "alert" is parsed by Suricata.Alert`)
+	_, err = parser.ParseLog(`{"event_type":"quic","timestamp":"2020-06-05T14:41:00.122516+0000","flow":{}}`)
+	require.EqualError(t, err, `unknown EVE event type "quic"`)
+	_, err = parser.ParseLog(`{"timestamp":"2020-06-05T14:41:00.122516+0000","flow":{}}`)
+	require.EqualError(t, err, `unknown EVE event type ""`)
+	_, err = parser.ParseLog(`{"event_type":"flow","timestamp":"2020-06-05T14:41:00.122516+0000","flow":{}}`)
+	require.NoError(t, err)
+}
+
+func TestEventTypesIndex(t *testing.T) {
+	require.Len(t, eveEventTypes, len(LogTypes().Entries()))
+	for eventType, logType := range eveEventTypes {
+		require.NotNil(t, LogTypes().Find(logType), eventType)
+	}
+}
+
+func TestEventTypeDispatcher(t *testing.T) {
+	d := logtypes.DispatcherOf(LogTypes().Find(TypeFlow))
+	require.NotNil(t, d)
+	require.Equal(t, LogTypePrefix, d.Name())
+	// All log types share the dispatcher
+	for _, entry := range LogTypes().Entries() {
+		require.Equal(t, d, logtypes.DispatcherOf(entry), entry.String())
+	}
+	parser, err := d.NewDispatchParser([]string{TypeFlow, TypeAlert})
+	require.NoError(t, err)
+	results, err := parser.ParseLog(`{"event_type":"alert","timestamp":"2020-06-05T14:41:00.122516+0000","alert":{}}`)
+	require.NoError(t, err)
+	require.Equal(t, TypeAlert, results[0].PantherLogType)
+	_, err = parser.ParseLog(`{"event_type":"dns","timestamp":"2020-06-05T14:41:00.122516+0000","dns":{}}`)
+	require.EqualError(t, err, `EVE event type "dns" is parsed by Suricata.DNS`)
+
+	_, err = d.NewDispatchParser([]string{TypeFlow, "Zeek.DNS"})
+	require.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// TLS is an EVE record of a TLS handshake
+// nolint:lll
+type TLS struct {
+	EventHeader
+	TLS *TLSDetails `json:"tls" validate:"required" description:"The details of the TLS handshake."`
+}
+
+// nolint:lll
+type TLSDetails struct {
+	Subject        pantherlog.String   `json:"subject" description:"The subject of the server certificate."`
+	IssuerDN       pantherlog.String   `json:"issuerdn" description:"The issuer of the server certificate."`
+	Serial         pantherlog.String   `json:"serial" description:"The serial number of the server certificate."`
+	Fingerprint    pantherlog.String   `json:"fingerprint" description:"The SHA1 fingerprint of the server certificate."`
+	SNI            pantherlog.String   `json:"sni" panther:"domain" description:"The Server Name Indication of the client hello."`
+	Version        pantherlog.String   `json:"version" description:"The TLS version of the session."`
+	NotBefore      pantherlog.Time     `json:"notbefore" tcodec:"layout=2006-01-02T15:04:05" description:"The start of the validity of the server certificate."`
+	NotAfter       pantherlog.Time     `json:"notafter" tcodec:"layout=2006-01-02T15:04:05" description:"The end of the validity of the server certificate."`
+	SessionResumed pantherlog.Bool     `json:"session_resumed" description:"Whether the session was resumed."`
+	Certificate    pantherlog.String   `json:"certificate" description:"The base64 encoded server certificate."`
+	Chain          []pantherlog.String `json:"chain" description:"The base64 encoded certificate chain."`
+	JA3            *JA3Details         `json:"ja3" description:"The JA3 fingerprint of the client hello."`
+	JA3S           *JA3Details         `json:"ja3s" description:"The JA3S fingerprint of the server hello."`
+}
+
+// nolint:lll
+type JA3Details struct {
+	Hash   pantherlog.String `json:"hash" panther:"md5" description:"The MD5 hash of the JA3 string."`
+	String pantherlog.String `json:"string" description:"The JA3 string."`
+}
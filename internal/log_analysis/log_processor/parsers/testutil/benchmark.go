@@ -0,0 +1,78 @@
+package testutil
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logsamples"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// BenchmarkParser measures the cost of parsing sample log entries with the parser of a log type.
+// The benchmark reports allocations and throughput per log entry.
+func BenchmarkParser(b *testing.B, entry logtypes.Entry, samples []string) {
+	b.Helper()
+	if len(samples) == 0 {
+		b.Skipf("no samples for log type %q", entry.String())
+	}
+	parser, err := entry.NewParser(nil)
+	if err != nil {
+		b.Fatalf("failed to create %q parser: %s", entry.String(), err)
+	}
+	b.SetBytes(logsamples.AverageSize(samples))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sample := samples[i%len(samples)]
+		if _, err := parser.ParseLog(sample); err != nil {
+			b.Fatalf("failed to parse %q sample %d: %s", entry.String(), i%len(samples), err)
+		}
+	}
+}
+
+// BenchmarkFallThrough measures the cost of trying sample log entries of a log type with the parsers of other log types.
+// This is the cost a classifier pays for a log entry before it reaches the parser of its log type.
+func BenchmarkFallThrough(b *testing.B, entry logtypes.Entry, others []logtypes.Entry, samples []string) {
+	b.Helper()
+	if len(samples) == 0 {
+		b.Skipf("no samples for log type %q", entry.String())
+	}
+	var parsers []pantherlog.LogParser
+	for _, other := range others {
+		if other.String() == entry.String() {
+			continue
+		}
+		parser, err := other.NewParser(nil)
+		if err != nil {
+			b.Fatalf("failed to create %q parser: %s", other.String(), err)
+		}
+		parsers = append(parsers, parser)
+	}
+	b.SetBytes(logsamples.AverageSize(samples))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sample := samples[i%len(samples)]
+		for _, parser := range parsers {
+			_, _ = logsamples.TryParse(parser, sample)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package windowslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog/null"
+)
+
+// EventLog is a Windows event log record
+// nolint:lll
+type EventLog struct {
+	// System section
+	ProviderName      pantherlog.String   `json:"ProviderName" validate:"required" description:"The name of the event provider."`
+	ProviderGUID      pantherlog.String   `json:"ProviderGuid" description:"The GUID of the event provider."`
+	EventSourceName   pantherlog.String   `json:"EventSourceName" description:"The name of the event source for classic event log providers."`
+	EventID           pantherlog.Uint32   `json:"EventID" validate:"required" description:"The identifier of the event."`
+	Qualifiers        pantherlog.Uint16   `json:"Qualifiers" description:"The qualifiers of the event identifier for classic event log providers."`
+	Version           pantherlog.Uint8    `json:"Version" description:"The version of the event definition."`
+	Level             pantherlog.Uint8    `json:"Level" description:"The severity level of the event."`
+	Task              pantherlog.Uint16   `json:"Task" description:"The task of the event."`
+	Opcode            pantherlog.Uint8    `json:"Opcode" description:"The opcode of the event."`
+	Keywords          pantherlog.String   `json:"Keywords" description:"The keywords bitmask of the event."`
+	TimeCreated       pantherlog.Time     `json:"TimeCreated" tcodec:"rfc3339" event_time:"true" validate:"required" description:"The time the event was logged."`
+	EventRecordID     pantherlog.Uint64   `json:"EventRecordID" description:"The record number of the event in the channel."`
+	ActivityID        pantherlog.String   `json:"ActivityID" description:"The activity the event belongs to."`
+	RelatedActivityID pantherlog.String   `json:"RelatedActivityID" description:"The activity related to the activity of the event."`
+	ProcessID         pantherlog.Uint32   `json:"ProcessID" description:"The id of the process that logged the event."`
+	ThreadID          pantherlog.Uint32   `json:"ThreadID" description:"The id of the thread that logged the event."`
+	Channel           pantherlog.String   `json:"Channel" description:"The channel the event was logged to."`
+	Computer          pantherlog.String   `json:"Computer" panther:"hostname" description:"The name of the computer the event was logged on."`
+	UserID            pantherlog.String   `json:"UserID" description:"The SID of the user the event was logged for."`
+	UserName          pantherlog.String   `json:"UserName" panther:"username" description:"The name of the user the event was logged for, if resolved by the shipper."`
+	UserDomain        pantherlog.String   `json:"UserDomain" description:"The domain of the user the event was logged for, if resolved by the shipper."`
+	Message           pantherlog.String   `json:"Message" description:"The rendered message of the event."`
+	LevelName         pantherlog.String   `json:"LevelName" description:"The rendered severity level of the event."`
+	TaskName          pantherlog.String   `json:"TaskName" description:"The rendered task of the event."`
+	OpcodeName        pantherlog.String   `json:"OpcodeName" description:"The rendered opcode of the event."`
+	KeywordNames      []pantherlog.String `json:"KeywordNames" description:"The rendered keywords of the event."`
+
+	// EventData section
+	SubjectUserSid            pantherlog.String `json:"SubjectUserSid" description:"The SID of the account that requested the operation."`
+	SubjectUserName           pantherlog.String `json:"SubjectUserName" panther:"username" description:"The name of the account that requested the operation."`
+	SubjectDomainName         pantherlog.String `json:"SubjectDomainName" description:"The domain of the account that requested the operation."`
+	SubjectLogonID            pantherlog.String `json:"SubjectLogonId" description:"The logon id of the account that requested the operation."`
+	TargetUserSid             pantherlog.String `json:"TargetUserSid" description:"The SID of the account the operation was performed on."`
+	TargetUserName            pantherlog.String `json:"TargetUserName" panther:"username" description:"The name of the account the operation was performed on."`
+	TargetDomainName          pantherlog.String `json:"TargetDomainName" description:"The domain of the account the operation was performed on."`
+	TargetLogonID             pantherlog.String `json:"TargetLogonId" description:"The logon id of the account the operation was performed on."`
+	TargetServerName          pantherlog.String `json:"TargetServerName" panther:"hostname" description:"The name of the server the operation was performed on."`
+	LogonType                 pantherlog.String `json:"LogonType" description:"The type of logon."`
+	LogonProcessName          pantherlog.String `json:"LogonProcessName" description:"The name of the trusted logon process."`
+	AuthenticationPackageName pantherlog.String `json:"AuthenticationPackageName" description:"The name of the authentication package used for the logon."`
+	WorkstationName           pantherlog.String `json:"WorkstationName" panther:"hostname" description:"The name of the workstation the operation was requested from."`
+	IPAddress                 pantherlog.String `json:"IpAddress" panther:"ip" description:"The IP address the operation was requested from."`
+	IPPort                    pantherlog.String `json:"IpPort" description:"The port the operation was requested from."`
+	Status                    pantherlog.String `json:"Status" description:"The status code of the operation."`
+	SubStatus                 pantherlog.String `json:"SubStatus" description:"The sub status code of the operation."`
+	FailureReason             pantherlog.String `json:"FailureReason" description:"The reason the operation failed."`
+	ProcessName               pantherlog.String `json:"ProcessName" description:"The path of the process that requested the operation."`
+	NewProcessID              pantherlog.String `json:"NewProcessId" description:"The id of the created process."`
+	NewProcessName            pantherlog.String `json:"NewProcessName" description:"The path of the created process."`
+	ParentProcessName         pantherlog.String `json:"ParentProcessName" description:"The path of the parent of the created process."`
+	CommandLine               pantherlog.String `json:"CommandLine" description:"The command line of the created process."`
+	ServiceName               pantherlog.String `json:"ServiceName" description:"The name of the service."`
+	ServiceFileName           pantherlog.String `json:"ServiceFileName" description:"The path of the service binary."`
+	ObjectName                pantherlog.String `json:"ObjectName" description:"The name of the accessed object."`
+	ObjectType                pantherlog.String `json:"ObjectType" description:"The type of the accessed object."`
+	AccessMask                pantherlog.String `json:"AccessMask" description:"The access mask of the requested access."`
+	PrivilegeList             pantherlog.String `json:"PrivilegeList" description:"The privileges assigned or used."`
+	ShareName                 pantherlog.String `json:"ShareName" description:"The name of the accessed network share."`
+	TicketEncryptionType      pantherlog.String `json:"TicketEncryptionType" description:"The encryption type of the Kerberos ticket."`
+	EventData                 map[string]string `json:"EventData" description:"The EventData values that are not mapped to a field."`
+}
+
+// eventDataField returns the field of a known EventData key
+func (e *EventLog) eventDataField(key string) *pantherlog.String {
+	switch key {
+	case "SubjectUserSid":
+		return &e.SubjectUserSid
+	case "SubjectUserName":
+		return &e.SubjectUserName
+	case "SubjectDomainName":
+		return &e.SubjectDomainName
+	case "SubjectLogonId":
+		return &e.SubjectLogonID
+	case "TargetUserSid":
+		return &e.TargetUserSid
+	case "TargetUserName":
+		return &e.TargetUserName
+	case "TargetDomainName":
+		return &e.TargetDomainName
+	case "TargetLogonId":
+		return &e.TargetLogonID
+	case "TargetServerName":
+		return &e.TargetServerName
+	case "LogonType":
+		return &e.LogonType
+	case "LogonProcessName":
+		return &e.LogonProcessName
+	case "AuthenticationPackageName":
+		return &e.AuthenticationPackageName
+	case "WorkstationName":
+		return &e.WorkstationName
+	case "IpAddress":
+		return &e.IPAddress
+	case "IpPort":
+		return &e.IPPort
+	case "Status":
+		return &e.Status
+	case "SubStatus":
+		return &e.SubStatus
+	case "FailureReason":
+		return &e.FailureReason
+	case "ProcessName":
+		return &e.ProcessName
+	case "NewProcessId":
+		return &e.NewProcessID
+	case "NewProcessName":
+		return &e.NewProcessName
+	case "ParentProcessName":
+		return &e.ParentProcessName
+	case "CommandLine":
+		return &e.CommandLine
+	case "ServiceName":
+		return &e.ServiceName
+	case "ServiceFileName":
+		return &e.ServiceFileName
+	case "ObjectName":
+		return &e.ObjectName
+	case "ObjectType":
+		return &e.ObjectType
+	case "AccessMask":
+		return &e.AccessMask
+	case "PrivilegeList":
+		return &e.PrivilegeList
+	case "ShareName":
+		return &e.ShareName
+	case "TicketEncryptionType":
+		return &e.TicketEncryptionType
+	default:
+		return nil
+	}
+}
+
+// SetEventData sets an EventData value to its field or adds it to the EventData map if there is no field for the key.
+// Windows renders missing values as '-' so we leave their fields empty.
+func (e *EventLog) SetEventData(key, value string) {
+	if field := e.eventDataField(key); field != nil {
+		if value != "-" {
+			*field = nonEmpty(value)
+		}
+		return
+	}
+	if e.EventData == nil {
+		e.EventData = make(map[string]string)
+	}
+	e.EventData[key] = value
+}
+
+// record holds the System section values of an event as rendered by a shipper before they are normalized.
+type record struct {
+	ProviderName      string
+	ProviderGUID      string
+	EventSourceName   string
+	EventID           string
+	Qualifiers        string
+	Version           string
+	Level             string
+	Task              string
+	Opcode            string
+	Keywords          string
+	TimeCreated       string
+	EventRecordID     string
+	ActivityID        string
+	RelatedActivityID string
+	ProcessID         string
+	ThreadID          string
+	Channel           string
+	Computer          string
+	UserID            string
+	UserName          string
+	UserDomain        string
+	Message           string
+	LevelName         string
+	TaskName          string
+	OpcodeName        string
+	KeywordNames      []string
+}
+
+// normalize sets the System section fields of an event from the rendered values of a record.
+func (r *record) normalize(e *EventLog) (err error) {
+	e.ProviderName = nonEmpty(r.ProviderName)
+	e.ProviderGUID = nonEmpty(r.ProviderGUID)
+	e.EventSourceName = nonEmpty(r.EventSourceName)
+	e.Keywords = nonEmpty(r.Keywords)
+	e.ActivityID = nonEmpty(r.ActivityID)
+	e.RelatedActivityID = nonEmpty(r.RelatedActivityID)
+	e.Channel = nonEmpty(r.Channel)
+	e.Computer = nonEmpty(r.Computer)
+	e.UserID = nonEmpty(r.UserID)
+	e.UserName = nonEmpty(r.UserName)
+	e.UserDomain = nonEmpty(r.UserDomain)
+	e.Message = nonEmpty(r.Message)
+	e.LevelName = nonEmpty(r.LevelName)
+	e.TaskName = nonEmpty(r.TaskName)
+	e.OpcodeName = nonEmpty(r.OpcodeName)
+	for _, name := range r.KeywordNames {
+		e.KeywordNames = append(e.KeywordNames, null.FromString(name))
+	}
+	if e.TimeCreated, err = parseTime(r.TimeCreated); err != nil {
+		return err
+	}
+	numbers := []struct {
+		name  string
+		value string
+		bits  int
+		set   func(n uint64)
+	}{
+		{"EventID", r.EventID, 32, func(n uint64) { e.EventID = null.FromUint32(uint32(n)) }},
+		{"Qualifiers", r.Qualifiers, 16, func(n uint64) { e.Qualifiers = null.FromUint16(uint16(n)) }},
+		{"Version", r.Version, 8, func(n uint64) { e.Version = null.FromUint8(uint8(n)) }},
+		{"Level", r.Level, 8, func(n uint64) { e.Level = null.FromUint8(uint8(n)) }},
+		{"Task", r.Task, 16, func(n uint64) { e.Task = null.FromUint16(uint16(n)) }},
+		{"Opcode", r.Opcode, 8, func(n uint64) { e.Opcode = null.FromUint8(uint8(n)) }},
+		{"EventRecordID", r.EventRecordID, 64, func(n uint64) { e.EventRecordID = null.FromUint64(n) }},
+		{"ProcessID", r.ProcessID, 32, func(n uint64) { e.ProcessID = null.FromUint32(uint32(n)) }},
+		{"ThreadID", r.ThreadID, 32, func(n uint64) { e.ThreadID = null.FromUint32(uint32(n)) }},
+	}
+	for _, num := range numbers {
+		if num.value == "" {
+			continue
+		}
+		n, err := parseUint(num.value, num.bits)
+		if err != nil {
+			return errors.WithMessagef(err, "invalid %s", num.name)
+		}
+		num.set(n)
+	}
+	return nil
+}
+
+func nonEmpty(s string) pantherlog.String {
+	if s == "" {
+		return pantherlog.String{}
+	}
+	return null.FromString(s)
+}
+
+// parseUint parses decimal and 0x prefixed hexadecimal numbers
+func parseUint(s string, bits int) (uint64, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return strconv.ParseUint(s[2:], 16, bits)
+	}
+	return strconv.ParseUint(s, 10, bits)
+}
+
+// Layouts used by shippers to render the event time.
+// NXLog renders local time without a timezone by default, which we handle as UTC.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999 -0700",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+}
+
+func parseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, errors.New("missing event time")
+	}
+	for _, layout := range timeLayouts {
+		if tm, err := time.Parse(layout, s); err == nil {
+			return tm.UTC(), nil
+		}
+	}
+	return time.Time{}, errors.Errorf("invalid event time %q", s)
+}
@@ -0,0 +1,77 @@
+package windowslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	jsoniter "github.com/json-iterator/go"
+)
+
+// parseNXLog parses events shipped by the NXLog im_msvistalog module with the to_json() output.
+// NXLog writes the EventData values at the top level along with the System values.
+// See https://nxlog.co/documentation/nxlog-user-guide/im_msvistalog.html#im_msvistalog_fields
+func parseNXLog(event *EventLog, fields map[string]jsoniter.RawMessage) error {
+	r := record{}
+	system := map[string]*string{
+		"SourceName":        &r.ProviderName,
+		"ProviderGuid":      &r.ProviderGUID,
+		"EventID":           &r.EventID,
+		"Version":           &r.Version,
+		"Task":              &r.Task,
+		"OpcodeValue":       &r.Opcode,
+		"Keywords":          &r.Keywords,
+		"EventTime":         &r.TimeCreated,
+		"RecordNumber":      &r.EventRecordID,
+		"ActivityID":        &r.ActivityID,
+		"RelatedActivityID": &r.RelatedActivityID,
+		"ProcessID":         &r.ProcessID,
+		"ThreadID":          &r.ThreadID,
+		"Channel":           &r.Channel,
+		"Hostname":          &r.Computer,
+		"UserID":            &r.UserID,
+		"AccountName":       &r.UserName,
+		"Domain":            &r.UserDomain,
+		"Message":           &r.Message,
+		"Severity":          &r.LevelName,
+		"Category":          &r.TaskName,
+		"Opcode":            &r.OpcodeName,
+	}
+	var eventType string
+	for key, value := range fields {
+		if dst, ok := system[key]; ok {
+			*dst = rawString(value)
+			continue
+		}
+		switch key {
+		case "EventType":
+			eventType = rawString(value)
+		case "AccountType", "SeverityValue", "EventReceivedTime", "SourceModuleName", "SourceModuleType":
+			// NXLog metadata that are not part of the event
+		default:
+			event.SetEventData(key, rawString(value))
+		}
+	}
+	// NXLog renders the audit keywords as the event type
+	switch eventType {
+	case "AUDIT_SUCCESS":
+		r.KeywordNames = []string{"Audit Success"}
+	case "AUDIT_FAILURE":
+		r.KeywordNames = []string{"Audit Failure"}
+	}
+	return r.normalize(event)
+}
@@ -0,0 +1,94 @@
+package windowslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// NewEventLogParser creates a parser for Windows event log records
+func NewEventLogParser(_ interface{}) (pantherlog.LogParser, error) {
+	return &eventLogParser{}, nil
+}
+
+type eventLogParser struct {
+	builder pantherlog.ResultBuilder
+}
+
+var _ pantherlog.LogParser = (*eventLogParser)(nil)
+
+// ParseLog implements pantherlog.LogParser interface
+// The format of the event is detected from its content.
+func (p *eventLogParser) ParseLog(log string) ([]*pantherlog.Result, error) {
+	event := EventLog{}
+	var err error
+	log = strings.TrimSpace(log)
+	switch {
+	case strings.HasPrefix(log, "<"):
+		err = parseXML(&event, log)
+	case strings.HasPrefix(log, "{"):
+		err = parseJSON(&event, log)
+	default:
+		err = errors.New("log entry is not a Windows event in JSON or XML format")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := pantherlog.ValidateStruct(&event); err != nil {
+		return nil, err
+	}
+	result, err := p.builder.BuildResult(TypeEventLog, &event)
+	if err != nil {
+		return nil, err
+	}
+	return []*pantherlog.Result{result}, nil
+}
+
+// parseJSON parses events shipped by Winlogbeat or NXLog.
+// Winlogbeat nests the event under a `winlog` key while NXLog writes all fields at the top level.
+func parseJSON(event *EventLog, log string) error {
+	fields := make(map[string]jsoniter.RawMessage)
+	if err := jsoniter.UnmarshalFromString(log, &fields); err != nil {
+		return errors.Wrap(err, "failed to read Windows event JSON")
+	}
+	if _, ok := fields["winlog"]; ok {
+		return parseWinlogbeat(event, log)
+	}
+	return parseNXLog(event, fields)
+}
+
+// rawString converts a raw JSON value to a string.
+// Strings are unquoted while any other value is kept as JSON.
+func rawString(raw jsoniter.RawMessage) string {
+	if len(raw) > 0 && raw[0] == '"' {
+		var s string
+		if err := jsoniter.Unmarshal(raw, &s); err == nil {
+			return s
+		}
+	}
+	if string(raw) == "null" {
+		return ""
+	}
+	return string(raw)
+}
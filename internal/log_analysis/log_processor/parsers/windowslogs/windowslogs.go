@@ -0,0 +1,52 @@
+package windowslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+const (
+	// LogTypePrefix is the prefix of all logs parsed by this package and the name of the log type group
+	LogTypePrefix = "Windows"
+	// TypeEventLog is the log type of Windows event log records
+	TypeEventLog = LogTypePrefix + ".EventLog"
+)
+
+// LogTypes exports the available log type entries
+func LogTypes() logtypes.Group {
+	return logTypes
+}
+
+var logTypes = logtypes.Must(LogTypePrefix,
+	logtypes.Config{
+		Name: TypeEventLog,
+		Description: `Windows event log records shipped by Winlogbeat or NXLog in JSON format or rendered as EVTX XML.
+The System and EventData sections are normalized into fields and EventData values without a field are kept in the EventData map.`,
+		ReferenceURL: `https://docs.microsoft.com/en-us/windows/win32/wes/eventschema-schema`,
+		Schema:       pantherlog.MustBuildEventSchema(&EventLog{}),
+		NewParser:    pantherlog.FactoryFunc(NewEventLogParser),
+		// XML events can span multiple lines, JSON events are one per line.
+		Framing: &pantherlog.FramingConfig{
+			Mode:         pantherlog.FramingRegex,
+			StartPattern: `^\s*(<Event[\s>]|\{)`,
+		},
+	},
+)
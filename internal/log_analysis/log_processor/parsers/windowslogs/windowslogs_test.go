@@ -0,0 +1,79 @@
+package windowslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logtesting"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+)
+
+func TestEventLog(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/eventlog_tests.yml")
+}
+
+func TestEventLogInvalid(t *testing.T) {
+	parser, err := LogTypes().Find(TypeEventLog).NewParser(nil)
+	require.NoError(t, err)
+	for _, log := range []string{
+		`Jun  5 14:39:59 host sshd[123]: Accepted publickey for alice`,
+		`{"EventTime":"2020-06-05 14:39:59","Hostname":"WKS042"}`,
+		`{"EventTime":"yesterday","EventID":4624,"SourceName":"Microsoft-Windows-Security-Auditing"}`,
+		`{"EventTime":"2020-06-05 14:39:59","EventID":"0xZZ","SourceName":"Microsoft-Windows-Security-Auditing"}`,
+		`<Events></Events>`,
+	} {
+		_, err := parser.ParseLog(log)
+		require.Error(t, err, log)
+	}
+}
+
+// Tests that XML events spanning multiple lines are framed as a single log entry
+func TestEventLogFraming(t *testing.T) {
+	input := strings.Join([]string{
+		`<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event">`,
+		`  <System><Provider Name="Microsoft-Windows-Eventlog"/><EventID>1102</EventID>`,
+		`  <TimeCreated SystemTime="2020-06-05T14:43:30.1234567Z"/></System>`,
+		`</Event>`,
+		`<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event">`,
+		`  <System><Provider Name="Microsoft-Windows-Eventlog"/><EventID>1104</EventID>`,
+		`  <TimeCreated SystemTime="2020-06-05T14:43:31.1234567Z"/></System>`,
+		`</Event>`,
+		`{"EventTime":"2020-06-05 14:39:59","EventID":4688,"SourceName":"Microsoft-Windows-Security-Auditing"}`,
+	}, "\n")
+	entry := LogTypes().Find(TypeEventLog)
+	stream, err := logstream.NewFramedStream(logtypes.Framing(entry), strings.NewReader(input), 0)
+	require.NoError(t, err)
+	parser, err := entry.NewParser(nil)
+	require.NoError(t, err)
+	var eventIDs []uint32
+	for entry := stream.Next(); entry != nil; entry = stream.Next() {
+		results, err := parser.ParseLog(string(bytes.TrimSpace(entry)))
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		eventIDs = append(eventIDs, results[0].Event.(*EventLog).EventID.Value)
+	}
+	require.NoError(t, stream.Err())
+	require.Equal(t, []uint32{1102, 1104, 4688}, eventIDs)
+}
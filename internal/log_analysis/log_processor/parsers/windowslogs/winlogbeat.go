@@ -0,0 +1,114 @@
+package windowslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// winlogbeatEvent is the event format of Winlogbeat.
+// See https://www.elastic.co/guide/en/beats/winlogbeat/current/exported-fields-winlog.html
+type winlogbeatEvent struct {
+	Timestamp string `json:"@timestamp"`
+	Message   string `json:"message"`
+	Log       struct {
+		Level string `json:"level"`
+	} `json:"log"`
+	Winlog struct {
+		ProviderName      string                         `json:"provider_name"`
+		ProviderGUID      string                         `json:"provider_guid"`
+		EventID           flexString                     `json:"event_id"`
+		Version           flexString                     `json:"version"`
+		Task              string                         `json:"task"`
+		Opcode            string                         `json:"opcode"`
+		Keywords          []string                       `json:"keywords"`
+		TimeCreated       string                         `json:"time_created"`
+		RecordID          flexString                     `json:"record_id"`
+		ActivityID        string                         `json:"activity_id"`
+		RelatedActivityID string                         `json:"related_activity_id"`
+		Channel           string                         `json:"channel"`
+		ComputerName      string                         `json:"computer_name"`
+		EventData         map[string]jsoniter.RawMessage `json:"event_data"`
+		UserData          map[string]jsoniter.RawMessage `json:"user_data"`
+		Process           struct {
+			PID    flexString `json:"pid"`
+			Thread struct {
+				ID flexString `json:"id"`
+			} `json:"thread"`
+		} `json:"process"`
+		User struct {
+			Identifier string `json:"identifier"`
+			Name       string `json:"name"`
+			Domain     string `json:"domain"`
+		} `json:"user"`
+	} `json:"winlog"`
+}
+
+func parseWinlogbeat(event *EventLog, log string) error {
+	src := winlogbeatEvent{}
+	if err := jsoniter.UnmarshalFromString(log, &src); err != nil {
+		return errors.Wrap(err, "failed to read Winlogbeat event")
+	}
+	w := &src.Winlog
+	r := record{
+		ProviderName:      w.ProviderName,
+		ProviderGUID:      w.ProviderGUID,
+		EventID:           string(w.EventID),
+		Version:           string(w.Version),
+		TimeCreated:       w.TimeCreated,
+		EventRecordID:     string(w.RecordID),
+		ActivityID:        w.ActivityID,
+		RelatedActivityID: w.RelatedActivityID,
+		ProcessID:         string(w.Process.PID),
+		ThreadID:          string(w.Process.Thread.ID),
+		Channel:           w.Channel,
+		Computer:          w.ComputerName,
+		UserID:            w.User.Identifier,
+		UserName:          w.User.Name,
+		UserDomain:        w.User.Domain,
+		Message:           src.Message,
+		LevelName:         src.Log.Level,
+		TaskName:          w.Task,
+		OpcodeName:        w.Opcode,
+		KeywordNames:      w.Keywords,
+	}
+	if r.TimeCreated == "" {
+		r.TimeCreated = src.Timestamp
+	}
+	if err := r.normalize(event); err != nil {
+		return err
+	}
+	for key, value := range w.EventData {
+		event.SetEventData(key, rawString(value))
+	}
+	for key, value := range w.UserData {
+		event.SetEventData(key, rawString(value))
+	}
+	return nil
+}
+
+// flexString decodes both JSON strings and numbers to a string.
+type flexString string
+
+// UnmarshalJSON implements json.Unmarshaler interface
+func (s *flexString) UnmarshalJSON(data []byte) error {
+	*s = flexString(rawString(data))
+	return nil
+}
@@ -0,0 +1,180 @@
+package windowslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// xmlEvent is the XML rendering of an event.
+// See https://docs.microsoft.com/en-us/windows/win32/wes/eventschema-schema
+type xmlEvent struct {
+	System struct {
+		Provider struct {
+			Name            string `xml:"Name,attr"`
+			GUID            string `xml:"Guid,attr"`
+			EventSourceName string `xml:"EventSourceName,attr"`
+		} `xml:"Provider"`
+		EventID struct {
+			Value      string `xml:",chardata"`
+			Qualifiers string `xml:"Qualifiers,attr"`
+		} `xml:"EventID"`
+		Version     string `xml:"Version"`
+		Level       string `xml:"Level"`
+		Task        string `xml:"Task"`
+		Opcode      string `xml:"Opcode"`
+		Keywords    string `xml:"Keywords"`
+		TimeCreated struct {
+			SystemTime string `xml:"SystemTime,attr"`
+		} `xml:"TimeCreated"`
+		EventRecordID string `xml:"EventRecordID"`
+		Correlation   struct {
+			ActivityID        string `xml:"ActivityID,attr"`
+			RelatedActivityID string `xml:"RelatedActivityID,attr"`
+		} `xml:"Correlation"`
+		Execution struct {
+			ProcessID string `xml:"ProcessID,attr"`
+			ThreadID  string `xml:"ThreadID,attr"`
+		} `xml:"Execution"`
+		Channel  string `xml:"Channel"`
+		Computer string `xml:"Computer"`
+		Security struct {
+			UserID string `xml:"UserID,attr"`
+		} `xml:"Security"`
+	} `xml:"System"`
+	EventData struct {
+		Data []struct {
+			Name  string `xml:"Name,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"Data"`
+		Binary string `xml:"Binary"`
+	} `xml:"EventData"`
+	UserData struct {
+		InnerXML string `xml:",innerxml"`
+	} `xml:"UserData"`
+	RenderingInfo struct {
+		Message  string   `xml:"Message"`
+		Level    string   `xml:"Level"`
+		Task     string   `xml:"Task"`
+		Opcode   string   `xml:"Opcode"`
+		Keywords []string `xml:"Keywords>Keyword"`
+	} `xml:"RenderingInfo"`
+}
+
+// parseXML parses an event rendered as XML.
+// Any elements before the first `Event` element (ie an XML declaration or an `Events` root element) are skipped.
+func parseXML(event *EventLog, log string) error {
+	dec := xml.NewDecoder(strings.NewReader(log))
+	src := xmlEvent{}
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if err == io.EOF {
+				return errors.New("missing Event element")
+			}
+			return errors.Wrap(err, "failed to read Windows event XML")
+		}
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local == "Event" {
+			if err := dec.DecodeElement(&src, &start); err != nil {
+				return errors.Wrap(err, "failed to read Windows event XML")
+			}
+			break
+		}
+	}
+	sys := &src.System
+	info := &src.RenderingInfo
+	r := record{
+		ProviderName:      sys.Provider.Name,
+		ProviderGUID:      sys.Provider.GUID,
+		EventSourceName:   sys.Provider.EventSourceName,
+		EventID:           sys.EventID.Value,
+		Qualifiers:        sys.EventID.Qualifiers,
+		Version:           sys.Version,
+		Level:             sys.Level,
+		Task:              sys.Task,
+		Opcode:            sys.Opcode,
+		Keywords:          sys.Keywords,
+		TimeCreated:       sys.TimeCreated.SystemTime,
+		EventRecordID:     sys.EventRecordID,
+		ActivityID:        sys.Correlation.ActivityID,
+		RelatedActivityID: sys.Correlation.RelatedActivityID,
+		ProcessID:         sys.Execution.ProcessID,
+		ThreadID:          sys.Execution.ThreadID,
+		Channel:           sys.Channel,
+		Computer:          sys.Computer,
+		UserID:            sys.Security.UserID,
+		Message:           strings.TrimSpace(info.Message),
+		LevelName:         info.Level,
+		TaskName:          info.Task,
+		OpcodeName:        info.Opcode,
+		KeywordNames:      info.Keywords,
+	}
+	if err := r.normalize(event); err != nil {
+		return err
+	}
+	// Classic events have unnamed data values that we name after their position like Winlogbeat does
+	for i, data := range src.EventData.Data {
+		name := data.Name
+		if name == "" {
+			name = "param" + strconv.Itoa(i+1)
+		}
+		event.SetEventData(name, data.Value)
+	}
+	if src.EventData.Binary != "" {
+		event.SetEventData("Binary", src.EventData.Binary)
+	}
+	if src.UserData.InnerXML != "" {
+		return setUserData(event, src.UserData.InnerXML)
+	}
+	return nil
+}
+
+// setUserData adds the text of all leaf elements in a UserData section to the event data
+func setUserData(event *EventLog, inner string) error {
+	dec := xml.NewDecoder(strings.NewReader(inner))
+	var name string
+	var text strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read UserData XML")
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			name = tok.Name.Local
+			text.Reset()
+		case xml.CharData:
+			text.Write(tok)
+		case xml.EndElement:
+			// Only leaf elements have a name set when they end
+			if name != "" {
+				event.SetEventData(name, strings.TrimSpace(text.String()))
+			}
+			name = ""
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll
+type Conn struct {
+	Path          pantherlog.String   `json:"_path" description:"The name of the Zeek log stream this entry was written to."`
+	TS            pantherlog.Time     `json:"ts" tcodec:"unix" event_time:"true" validate:"required" description:"The time of the first packet."`
+	UID           pantherlog.String   `json:"uid" validate:"required" description:"A unique identifier of the connection."`
+	IDOrigH       pantherlog.String   `json:"id.orig_h" panther:"ip" validate:"required" description:"The originator’s IP address."`
+	IDOrigP       pantherlog.Uint16   `json:"id.orig_p" validate:"required" description:"The originator’s port number."`
+	IDRespH       pantherlog.String   `json:"id.resp_h" panther:"ip" validate:"required" description:"The responder’s IP address."`
+	IDRespP       pantherlog.Uint16   `json:"id.resp_p" validate:"required" description:"The responder’s port number."`
+	Proto         pantherlog.String   `json:"proto" validate:"required" description:"The transport layer protocol of the connection."`
+	Service       pantherlog.String   `json:"service" description:"An identification of an application protocol being sent in the connection."`
+	Duration      pantherlog.Float64  `json:"duration" description:"How long the connection lasted in seconds."`
+	OrigBytes     pantherlog.Uint64   `json:"orig_bytes" description:"The number of payload bytes the originator sent."`
+	RespBytes     pantherlog.Uint64   `json:"resp_bytes" description:"The number of payload bytes the responder sent."`
+	ConnState     pantherlog.String   `json:"conn_state" validate:"required" description:"The state of the connection (S0, S1, SF, REJ, ...)."`
+	LocalOrig     pantherlog.Bool     `json:"local_orig" description:"Whether the connection originated locally."`
+	LocalResp     pantherlog.Bool     `json:"local_resp" description:"Whether the connection responded locally."`
+	MissedBytes   pantherlog.Uint64   `json:"missed_bytes" description:"The number of bytes missed in content gaps."`
+	History       pantherlog.String   `json:"history" description:"The state history of the connection as a string of letters."`
+	OrigPkts      pantherlog.Uint64   `json:"orig_pkts" description:"The number of packets the originator sent."`
+	OrigIPBytes   pantherlog.Uint64   `json:"orig_ip_bytes" description:"The number of IP level bytes the originator sent."`
+	RespPkts      pantherlog.Uint64   `json:"resp_pkts" description:"The number of packets the responder sent."`
+	RespIPBytes   pantherlog.Uint64   `json:"resp_ip_bytes" description:"The number of IP level bytes the responder sent."`
+	TunnelParents []pantherlog.String `json:"tunnel_parents" description:"The unique ids of any encapsulating parent connections."`
+	OrigL2Addr    pantherlog.String   `json:"orig_l2_addr" description:"The link-layer address of the originator."`
+	RespL2Addr    pantherlog.String   `json:"resp_l2_addr" description:"The link-layer address of the responder."`
+	VLAN          pantherlog.Int64    `json:"vlan" description:"The outer VLAN of the connection."`
+	InnerVLAN     pantherlog.Int64    `json:"inner_vlan" description:"The inner VLAN of the connection."`
+	CommunityID   pantherlog.String   `json:"community_id" description:"The Community ID flow hash of the connection."`
+}
@@ -0,0 +1,50 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll
+type DHCP struct {
+	Path           pantherlog.String   `json:"_path" description:"The name of the Zeek log stream this entry was written to."`
+	TS             pantherlog.Time     `json:"ts" tcodec:"unix" event_time:"true" validate:"required" description:"The earliest time at which a DHCP message over the associated connection is observed."`
+	UIDs           []pantherlog.String `json:"uids" validate:"required,min=1" description:"The unique identifiers of the connections over which DHCP is occurring."`
+	ClientAddr     pantherlog.String   `json:"client_addr" panther:"ip" description:"The IP address of the client."`
+	ServerAddr     pantherlog.String   `json:"server_addr" panther:"ip" description:"The IP address of the server handing out the lease."`
+	ClientPort     pantherlog.Uint16   `json:"client_port" description:"The port number of the client."`
+	ServerPort     pantherlog.Uint16   `json:"server_port" description:"The port number of the server."`
+	MAC            pantherlog.String   `json:"mac" description:"The client’s hardware address."`
+	HostName       pantherlog.String   `json:"host_name" description:"The name given by the client in the Hostname option."`
+	ClientFQDN     pantherlog.String   `json:"client_fqdn" panther:"domain" description:"The FQDN given by the client in the Client FQDN option."`
+	Domain         pantherlog.String   `json:"domain" panther:"domain" description:"The domain given by the server in the Domain Name option."`
+	RequestedAddr  pantherlog.String   `json:"requested_addr" panther:"ip" description:"The IP address requested by the client."`
+	AssignedAddr   pantherlog.String   `json:"assigned_addr" panther:"ip" description:"The IP address assigned by the server."`
+	LeaseTime      pantherlog.Float64  `json:"lease_time" description:"The IP address lease interval in seconds."`
+	ClientMessage  pantherlog.String   `json:"client_message" description:"The message typically accompanying a DHCP_DECLINE from the client."`
+	ServerMessage  pantherlog.String   `json:"server_message" description:"The message typically accompanying a DHCP_NAK from the server."`
+	MsgTypes       []pantherlog.String `json:"msg_types" description:"The DHCP message types seen by this DHCP transaction."`
+	Duration       pantherlog.Float64  `json:"duration" description:"The duration of the DHCP session in seconds."`
+	ClientSoftware pantherlog.String   `json:"client_software" description:"The software reported by the client in the vendor_class option."`
+	ServerSoftware pantherlog.String   `json:"server_software" description:"The software reported by the server in the vendor_class option."`
+	CircuitID      pantherlog.String   `json:"circuit_id" description:"The circuit ID added by DHCP relay agents which terminate switched or permanent circuits."`
+	AgentRemoteID  pantherlog.String   `json:"agent_remote_id" description:"A globally unique identifier added by relay agents to identify the remote host end of the circuit."`
+	SubscriberID   pantherlog.String   `json:"subscriber_id" description:"The subscriber ID is a value independent of the physical network configuration."`
+}
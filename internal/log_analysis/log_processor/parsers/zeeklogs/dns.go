@@ -20,6 +20,7 @@ package zeeklogs
 
 import (
 	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
 
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
@@ -71,12 +72,23 @@ func (p *ZeekDNSParser) New() parsers.LogParser {
 // Parse returns the parsed events or nil if parsing failed
 func (p *ZeekDNSParser) Parse(log string) ([]*parsers.PantherLog, error) {
 	zeekDNS := &ZeekDNS{}
+	// The `_path` is only checked, it is not stored so that the table schema does not change
+	event := struct {
+		Path *string `json:"_path"`
+		*ZeekDNS
+	}{
+		ZeekDNS: zeekDNS,
+	}
 
-	err := jsoniter.UnmarshalFromString(log, zeekDNS)
+	err := jsoniter.UnmarshalFromString(log, &event)
 	if err != nil {
 		return nil, err
 	}
 
+	if event.Path != nil && *event.Path != "dns" {
+		return nil, errors.Errorf("zeek log path %q does not match %q", *event.Path, "dns")
+	}
+
 	zeekDNS.updatePantherFields(p)
 
 	if err := parsers.Validator.Struct(zeekDNS); err != nil {
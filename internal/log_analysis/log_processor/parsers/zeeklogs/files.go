@@ -0,0 +1,58 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll
+type Files struct {
+	Path            pantherlog.String   `json:"_path" description:"The name of the Zeek log stream this entry was written to."`
+	TS              pantherlog.Time     `json:"ts" tcodec:"unix" event_time:"true" validate:"required" description:"The time when the file was first seen."`
+	FUID            pantherlog.String   `json:"fuid" validate:"required" description:"An identifier associated with a single file."`
+	UID             pantherlog.String   `json:"uid" description:"The unique identifier of the connection the file was transferred over."`
+	IDOrigH         pantherlog.String   `json:"id.orig_h" panther:"ip" description:"The originator’s IP address."`
+	IDOrigP         pantherlog.Uint16   `json:"id.orig_p" description:"The originator’s port number."`
+	IDRespH         pantherlog.String   `json:"id.resp_h" panther:"ip" description:"The responder’s IP address."`
+	IDRespP         pantherlog.Uint16   `json:"id.resp_p" description:"The responder’s port number."`
+	TxHosts         []pantherlog.String `json:"tx_hosts" panther:"ip" description:"The hosts that have sourced the data of the file."`
+	RxHosts         []pantherlog.String `json:"rx_hosts" panther:"ip" description:"The hosts that have received the data of the file."`
+	ConnUIDs        []pantherlog.String `json:"conn_uids" description:"The connection UIDs over which the file was transferred."`
+	Source          pantherlog.String   `json:"source" description:"An identification of the source of the file data."`
+	Depth           pantherlog.Uint64   `json:"depth" description:"A value to represent the depth of this file in relation to its source."`
+	Analyzers       []pantherlog.String `json:"analyzers" description:"A set of analysis types done during the file analysis."`
+	MIMEType        pantherlog.String   `json:"mime_type" description:"A mime type provided by the strongest file magic signature match."`
+	Filename        pantherlog.String   `json:"filename" description:"A filename for the file if one is available from the source."`
+	Duration        pantherlog.Float64  `json:"duration" description:"The duration the file was analyzed for in seconds."`
+	LocalOrig       pantherlog.Bool     `json:"local_orig" description:"Whether the file originated locally."`
+	IsOrig          pantherlog.Bool     `json:"is_orig" description:"Whether the file was sent by the originator of the connection."`
+	SeenBytes       pantherlog.Uint64   `json:"seen_bytes" description:"The number of bytes provided to the file analysis engine for the file."`
+	TotalBytes      pantherlog.Uint64   `json:"total_bytes" description:"The total number of bytes that are supposed to comprise the full file."`
+	MissingBytes    pantherlog.Uint64   `json:"missing_bytes" description:"The number of bytes in the file stream that were completely missed."`
+	OverflowBytes   pantherlog.Uint64   `json:"overflow_bytes" description:"The number of bytes in the file stream that were not delivered to stream file analyzers."`
+	TimedOut        pantherlog.Bool     `json:"timedout" description:"Whether the file analysis timed out at least once for the file."`
+	ParentFUID      pantherlog.String   `json:"parent_fuid" description:"The identifier of the container file this file was extracted from."`
+	MD5             pantherlog.String   `json:"md5" panther:"md5" description:"The MD5 digest of the file contents."`
+	SHA1            pantherlog.String   `json:"sha1" panther:"sha1" description:"The SHA1 digest of the file contents."`
+	SHA256          pantherlog.String   `json:"sha256" panther:"sha256" description:"The SHA256 digest of the file contents."`
+	Extracted       pantherlog.String   `json:"extracted" description:"The local filename of the extracted file."`
+	ExtractedCutoff pantherlog.Bool     `json:"extracted_cutoff" description:"Whether the file extraction was cut off."`
+	ExtractedSize   pantherlog.Uint64   `json:"extracted_size" description:"The number of bytes extracted to disk."`
+}
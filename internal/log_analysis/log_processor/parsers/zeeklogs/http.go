@@ -0,0 +1,58 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll
+type HTTP struct {
+	Path            pantherlog.String   `json:"_path" description:"The name of the Zeek log stream this entry was written to."`
+	TS              pantherlog.Time     `json:"ts" tcodec:"unix" event_time:"true" validate:"required" description:"The time the request happened."`
+	UID             pantherlog.String   `json:"uid" validate:"required" description:"A unique identifier of the connection."`
+	IDOrigH         pantherlog.String   `json:"id.orig_h" panther:"ip" validate:"required" description:"The originator’s IP address."`
+	IDOrigP         pantherlog.Uint16   `json:"id.orig_p" validate:"required" description:"The originator’s port number."`
+	IDRespH         pantherlog.String   `json:"id.resp_h" panther:"ip" validate:"required" description:"The responder’s IP address."`
+	IDRespP         pantherlog.Uint16   `json:"id.resp_p" validate:"required" description:"The responder’s port number."`
+	TransDepth      pantherlog.Uint64   `json:"trans_depth" validate:"required" description:"The pipelined depth into the connection of this request/response transaction."`
+	Method          pantherlog.String   `json:"method" description:"The verb used in the HTTP request (GET, POST, HEAD, etc.)."`
+	Host            pantherlog.String   `json:"host" panther:"hostname" description:"The value of the HOST header."`
+	URI             pantherlog.String   `json:"uri" description:"The URI used in the request."`
+	Referrer        pantherlog.String   `json:"referrer" panther:"url" description:"The value of the Referer header."`
+	Version         pantherlog.String   `json:"version" description:"The value of the version portion of the request."`
+	UserAgent       pantherlog.String   `json:"user_agent" description:"The value of the User-Agent header from the client."`
+	Origin          pantherlog.String   `json:"origin" panther:"url" description:"The value of the Origin header from the client."`
+	RequestBodyLen  pantherlog.Uint64   `json:"request_body_len" description:"The actual uncompressed content size of the data transferred from the client."`
+	ResponseBodyLen pantherlog.Uint64   `json:"response_body_len" description:"The actual uncompressed content size of the data transferred from the server."`
+	StatusCode      pantherlog.Uint64   `json:"status_code" description:"The status code returned by the server."`
+	StatusMsg       pantherlog.String   `json:"status_msg" description:"The status message returned by the server."`
+	InfoCode        pantherlog.Uint64   `json:"info_code" description:"The last seen 1xx informational reply code returned by the server."`
+	InfoMsg         pantherlog.String   `json:"info_msg" description:"The last seen 1xx informational reply message returned by the server."`
+	Tags            []pantherlog.String `json:"tags" description:"A set of indicators of various attributes discovered and related to a particular request/response pair."`
+	Username        pantherlog.String   `json:"username" panther:"username" description:"The username if basic-auth is performed for the request."`
+	Password        pantherlog.String   `json:"password" description:"The password if basic-auth is performed for the request."`
+	Proxied         []pantherlog.String `json:"proxied" description:"All of the headers that may indicate if the request was proxied."`
+	OrigFUIDs       []pantherlog.String `json:"orig_fuids" description:"An ordered vector of file unique IDs sent by the originator."`
+	OrigFilenames   []pantherlog.String `json:"orig_filenames" description:"An ordered vector of filenames from the originator."`
+	OrigMIMETypes   []pantherlog.String `json:"orig_mime_types" description:"An ordered vector of mime types sent by the originator."`
+	RespFUIDs       []pantherlog.String `json:"resp_fuids" description:"An ordered vector of file unique IDs sent by the responder."`
+	RespFilenames   []pantherlog.String `json:"resp_filenames" description:"An ordered vector of filenames from the responder."`
+	RespMIMETypes   []pantherlog.String `json:"resp_mime_types" description:"An ordered vector of mime types sent by the responder."`
+}
@@ -0,0 +1,54 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll
+type Notice struct {
+	Path                      pantherlog.String   `json:"_path" description:"The name of the Zeek log stream this entry was written to."`
+	TS                        pantherlog.Time     `json:"ts" tcodec:"unix" event_time:"true" validate:"required" description:"The time when the notice occurred."`
+	UID                       pantherlog.String   `json:"uid" description:"A unique identifier of the connection which triggered the notice."`
+	IDOrigH                   pantherlog.String   `json:"id.orig_h" panther:"ip" description:"The originator’s IP address."`
+	IDOrigP                   pantherlog.Uint16   `json:"id.orig_p" description:"The originator’s port number."`
+	IDRespH                   pantherlog.String   `json:"id.resp_h" panther:"ip" description:"The responder’s IP address."`
+	IDRespP                   pantherlog.Uint16   `json:"id.resp_p" description:"The responder’s port number."`
+	FUID                      pantherlog.String   `json:"fuid" description:"A file unique ID if this notice is related to a file."`
+	FileMIMEType              pantherlog.String   `json:"file_mime_type" description:"The mime type of the file related to the notice."`
+	FileDesc                  pantherlog.String   `json:"file_desc" description:"A description of the file related to the notice."`
+	Proto                     pantherlog.String   `json:"proto" description:"The transport protocol."`
+	Note                      pantherlog.String   `json:"note" validate:"required" description:"The type of the notice."`
+	Msg                       pantherlog.String   `json:"msg" description:"The human readable message for the notice."`
+	Sub                       pantherlog.String   `json:"sub" description:"The human readable sub-message."`
+	Src                       pantherlog.String   `json:"src" panther:"ip" description:"The source address, if we don't have a connection."`
+	Dst                       pantherlog.String   `json:"dst" panther:"ip" description:"The destination address."`
+	P                         pantherlog.Uint16   `json:"p" description:"The associated port, if we don't have a connection."`
+	N                         pantherlog.Uint64   `json:"n" description:"The associated count, or perhaps a status code."`
+	PeerDescr                 pantherlog.String   `json:"peer_descr" description:"A textual description of the peer that raised this notice."`
+	Actions                   []pantherlog.String `json:"actions" description:"The actions which have been applied to this notice."`
+	EmailDest                 []pantherlog.String `json:"email_dest" panther:"email" description:"The email addresses to send the notice to."`
+	SuppressFor               pantherlog.Float64  `json:"suppress_for" description:"The length of time in seconds this notice should be suppressed."`
+	RemoteLocationCountryCode pantherlog.String   `json:"remote_location.country_code" description:"The country code of the remote host."`
+	RemoteLocationRegion      pantherlog.String   `json:"remote_location.region" description:"The region of the remote host."`
+	RemoteLocationCity        pantherlog.String   `json:"remote_location.city" description:"The city of the remote host."`
+	RemoteLocationLatitude    pantherlog.Float64  `json:"remote_location.latitude" description:"The latitude of the remote host."`
+	RemoteLocationLongitude   pantherlog.Float64  `json:"remote_location.longitude" description:"The longitude of the remote host."`
+}
@@ -0,0 +1,54 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll
+type SSH struct {
+	Path                      pantherlog.String  `json:"_path" description:"The name of the Zeek log stream this entry was written to."`
+	TS                        pantherlog.Time    `json:"ts" tcodec:"unix" event_time:"true" validate:"required" description:"The time when the SSH connection began."`
+	UID                       pantherlog.String  `json:"uid" validate:"required" description:"A unique identifier of the connection."`
+	IDOrigH                   pantherlog.String  `json:"id.orig_h" panther:"ip" validate:"required" description:"The originator’s IP address."`
+	IDOrigP                   pantherlog.Uint16  `json:"id.orig_p" validate:"required" description:"The originator’s port number."`
+	IDRespH                   pantherlog.String  `json:"id.resp_h" panther:"ip" validate:"required" description:"The responder’s IP address."`
+	IDRespP                   pantherlog.Uint16  `json:"id.resp_p" validate:"required" description:"The responder’s port number."`
+	Version                   pantherlog.Uint64  `json:"version" description:"The SSH major version (1 or 2)."`
+	AuthSuccess               pantherlog.Bool    `json:"auth_success" description:"Authentication result (T=success, F=failure, unset=unknown)."`
+	AuthAttempts              pantherlog.Uint64  `json:"auth_attempts" description:"The number of authentication attempts observed."`
+	Direction                 pantherlog.String  `json:"direction" description:"The direction of the connection (INBOUND or OUTBOUND)."`
+	Client                    pantherlog.String  `json:"client" description:"The client’s version string."`
+	Server                    pantherlog.String  `json:"server" description:"The server’s version string."`
+	CipherAlg                 pantherlog.String  `json:"cipher_alg" description:"The encryption algorithm in use."`
+	MACAlg                    pantherlog.String  `json:"mac_alg" description:"The signing (MAC) algorithm in use."`
+	CompressionAlg            pantherlog.String  `json:"compression_alg" description:"The compression algorithm in use."`
+	KexAlg                    pantherlog.String  `json:"kex_alg" description:"The key exchange algorithm in use."`
+	HostKeyAlg                pantherlog.String  `json:"host_key_alg" description:"The server host key’s algorithm."`
+	HostKey                   pantherlog.String  `json:"host_key" description:"The server’s key fingerprint."`
+	RemoteLocationCountryCode pantherlog.String  `json:"remote_location.country_code" description:"The country code of the remote host."`
+	RemoteLocationRegion      pantherlog.String  `json:"remote_location.region" description:"The region of the remote host."`
+	RemoteLocationCity        pantherlog.String  `json:"remote_location.city" description:"The city of the remote host."`
+	RemoteLocationLatitude    pantherlog.Float64 `json:"remote_location.latitude" description:"The latitude of the remote host."`
+	RemoteLocationLongitude   pantherlog.Float64 `json:"remote_location.longitude" description:"The longitude of the remote host."`
+	HasshVersion              pantherlog.String  `json:"hasshVersion" description:"The HASSH algorithm version."`
+	Hassh                     pantherlog.String  `json:"hassh" panther:"md5" description:"The HASSH client fingerprint."`
+	HasshServer               pantherlog.String  `json:"hasshServer" panther:"md5" description:"The HASSH server fingerprint."`
+}
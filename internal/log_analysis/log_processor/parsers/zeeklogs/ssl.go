@@ -0,0 +1,53 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll
+type SSL struct {
+	Path                 pantherlog.String   `json:"_path" description:"The name of the Zeek log stream this entry was written to."`
+	TS                   pantherlog.Time     `json:"ts" tcodec:"unix" event_time:"true" validate:"required" description:"The time when the SSL connection was first detected."`
+	UID                  pantherlog.String   `json:"uid" validate:"required" description:"A unique identifier of the connection."`
+	IDOrigH              pantherlog.String   `json:"id.orig_h" panther:"ip" validate:"required" description:"The originator’s IP address."`
+	IDOrigP              pantherlog.Uint16   `json:"id.orig_p" validate:"required" description:"The originator’s port number."`
+	IDRespH              pantherlog.String   `json:"id.resp_h" panther:"ip" validate:"required" description:"The responder’s IP address."`
+	IDRespP              pantherlog.Uint16   `json:"id.resp_p" validate:"required" description:"The responder’s port number."`
+	Version              pantherlog.String   `json:"version" description:"The SSL/TLS version that the server chose."`
+	Cipher               pantherlog.String   `json:"cipher" description:"The SSL/TLS cipher suite that the server chose."`
+	Curve                pantherlog.String   `json:"curve" description:"The elliptic curve the server chose when using ECDH/ECDHE."`
+	ServerName           pantherlog.String   `json:"server_name" panther:"domain" description:"The value of the Server Name Indicator SSL/TLS extension."`
+	Resumed              pantherlog.Bool     `json:"resumed" description:"Whether the session was resumed."`
+	LastAlert            pantherlog.String   `json:"last_alert" description:"The last alert that was seen during the connection."`
+	NextProtocol         pantherlog.String   `json:"next_protocol" description:"The next protocol the server chose using the application layer next protocol extension."`
+	Established          pantherlog.Bool     `json:"established" description:"Whether this connection was established."`
+	SSLHistory           pantherlog.String   `json:"ssl_history" description:"The SSL/TLS history of the connection as a string of letters."`
+	CertChainFUIDs       []pantherlog.String `json:"cert_chain_fuids" description:"An ordered vector of all certificate file unique IDs for the certificates offered by the server."`
+	ClientCertChainFUIDs []pantherlog.String `json:"client_cert_chain_fuids" description:"An ordered vector of all certificate file unique IDs for the certificates offered by the client."`
+	Subject              pantherlog.String   `json:"subject" description:"The subject of the X.509 certificate offered by the server."`
+	Issuer               pantherlog.String   `json:"issuer" description:"The issuer of the X.509 certificate offered by the server."`
+	ClientSubject        pantherlog.String   `json:"client_subject" description:"The subject of the X.509 certificate offered by the client."`
+	ClientIssuer         pantherlog.String   `json:"client_issuer" description:"The issuer of the X.509 certificate offered by the client."`
+	SNIMatchesCert       pantherlog.Bool     `json:"sni_matches_cert" description:"Whether the server name indicator matches the certificate of the server."`
+	ValidationStatus     pantherlog.String   `json:"validation_status" description:"The result of certificate validation for this connection."`
+	JA3                  pantherlog.String   `json:"ja3" description:"The JA3 fingerprint of the client hello."`
+	JA3S                 pantherlog.String   `json:"ja3s" description:"The JA3S fingerprint of the server hello."`
+}
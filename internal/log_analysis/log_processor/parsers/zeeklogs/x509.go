@@ -0,0 +1,52 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll
+type X509 struct {
+	Path                      pantherlog.String   `json:"_path" description:"The name of the Zeek log stream this entry was written to."`
+	TS                        pantherlog.Time     `json:"ts" tcodec:"unix" event_time:"true" validate:"required" description:"The time when the certificate was seen."`
+	ID                        pantherlog.String   `json:"id" validate:"required" description:"The file id of the certificate."`
+	Fingerprint               pantherlog.String   `json:"fingerprint" panther:"sha256" description:"The SHA256 fingerprint of the certificate."`
+	CertificateVersion        pantherlog.Uint64   `json:"certificate.version" validate:"required" description:"The version number of the certificate."`
+	CertificateSerial         pantherlog.String   `json:"certificate.serial" description:"The serial number of the certificate."`
+	CertificateSubject        pantherlog.String   `json:"certificate.subject" description:"The subject of the certificate."`
+	CertificateIssuer         pantherlog.String   `json:"certificate.issuer" description:"The issuer of the certificate."`
+	CertificateCN             pantherlog.String   `json:"certificate.cn" description:"The last (most specific) common name of the certificate."`
+	CertificateNotValidBefore pantherlog.Time     `json:"certificate.not_valid_before" tcodec:"unix" description:"The timestamp of the start of the certificate validity."`
+	CertificateNotValidAfter  pantherlog.Time     `json:"certificate.not_valid_after" tcodec:"unix" description:"The timestamp of the end of the certificate validity."`
+	CertificateKeyAlg         pantherlog.String   `json:"certificate.key_alg" description:"The name of the key algorithm."`
+	CertificateSigAlg         pantherlog.String   `json:"certificate.sig_alg" description:"The name of the signature algorithm."`
+	CertificateKeyType        pantherlog.String   `json:"certificate.key_type" description:"The key type, if key is parseable by openssl (either rsa, dsa or ec)."`
+	CertificateKeyLength      pantherlog.Uint64   `json:"certificate.key_length" description:"The key length in bits."`
+	CertificateExponent       pantherlog.String   `json:"certificate.exponent" description:"The exponent, if RSA-certificate."`
+	CertificateCurve          pantherlog.String   `json:"certificate.curve" description:"The curve, if EC-certificate."`
+	SANDNS                    []pantherlog.String `json:"san.dns" panther:"domain" description:"The list of DNS entries in the Subject Alternative Name."`
+	SANURI                    []pantherlog.String `json:"san.uri" panther:"url" description:"The list of URI entries in the Subject Alternative Name."`
+	SANEmail                  []pantherlog.String `json:"san.email" panther:"email" description:"The list of email entries in the Subject Alternative Name."`
+	SANIP                     []pantherlog.String `json:"san.ip" panther:"ip" description:"The list of IP entries in the Subject Alternative Name."`
+	BasicConstraintsCA        pantherlog.Bool     `json:"basic_constraints.ca" description:"Whether the certificate is a CA certificate."`
+	BasicConstraintsPathLen   pantherlog.Uint64   `json:"basic_constraints.path_len" description:"The maximum path length of the CA certificate."`
+	HostCert                  pantherlog.Bool     `json:"host_cert" description:"Whether this certificate was sent as the host certificate of a connection."`
+	ClientCert                pantherlog.Bool     `json:"client_cert" description:"Whether this certificate was sent as the client certificate of a connection."`
+}
@@ -19,22 +19,164 @@ package zeeklogs
  */
 
 import (
+	"github.com/pkg/errors"
+
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
 )
 
 const (
-	TypeZeekDNS = "Zeek.DNS"
+	// LogTypePrefix is the prefix of all logs parsed by this package and the name of the log type group
+	LogTypePrefix = "Zeek"
+	TypeZeekDNS   = LogTypePrefix + ".DNS"
+	TypeConn      = LogTypePrefix + ".Conn"
+	TypeHTTP      = LogTypePrefix + ".HTTP"
+	TypeSSL       = LogTypePrefix + ".SSL"
+	TypeX509      = LogTypePrefix + ".X509"
+	TypeFiles     = LogTypePrefix + ".Files"
+	TypeNotice    = LogTypePrefix + ".Notice"
+	TypeWeird     = LogTypePrefix + ".Weird"
+	TypeSSH       = LogTypePrefix + ".SSH"
+	TypeDHCP      = LogTypePrefix + ".DHCP"
 )
 
+// LogTypes exports the available log type entries
 func LogTypes() logtypes.Group {
 	return logTypes
 }
 
-var logTypes = logtypes.Must("Zeek", logtypes.Config{
-	Name:         TypeZeekDNS,
-	Description:  `Zeek DNS activity`,
-	ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/dns/main.zeek.html#type-DNS::Info`,
-	Schema:       &ZeekDNS{},
-	NewParser:    parsers.AdapterFactory(&ZeekDNSParser{}),
-})
+// All Zeek log types use the zeek framing so that both JSON and TSV log files can be processed.
+var zeekFraming = &pantherlog.FramingConfig{
+	Mode: pantherlog.FramingZeek,
+}
+
+var logTypes = logtypes.Must(LogTypePrefix,
+	logtypes.Config{
+		Name:         TypeZeekDNS,
+		Description:  `Zeek DNS activity`,
+		ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/dns/main.zeek.html#type-DNS::Info`,
+		Schema:       &ZeekDNS{},
+		NewParser:    parsers.AdapterFactory(&ZeekDNSParser{}),
+		Framing:      zeekFraming,
+	},
+	logtypes.ConfigJSON{
+		Name:         TypeConn,
+		Description:  `Zeek TCP, UDP and ICMP connection summaries`,
+		ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/conn/main.zeek.html#type-Conn::Info`,
+		NewEvent: func() interface{} {
+			return &Conn{}
+		},
+		Validate: validatePath("conn"),
+		Framing:  zeekFraming,
+	},
+	logtypes.ConfigJSON{
+		Name:         TypeHTTP,
+		Description:  `Zeek HTTP requests and replies`,
+		ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/http/main.zeek.html#type-HTTP::Info`,
+		NewEvent: func() interface{} {
+			return &HTTP{}
+		},
+		Validate: validatePath("http"),
+		Framing:  zeekFraming,
+	},
+	logtypes.ConfigJSON{
+		Name:         TypeSSL,
+		Description:  `Zeek SSL/TLS handshake info`,
+		ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/ssl/main.zeek.html#type-SSL::Info`,
+		NewEvent: func() interface{} {
+			return &SSL{}
+		},
+		Validate: validatePath("ssl"),
+		Framing:  zeekFraming,
+	},
+	logtypes.ConfigJSON{
+		Name:         TypeX509,
+		Description:  `Zeek X.509 certificate info`,
+		ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/files/x509/main.zeek.html#type-X509::Info`,
+		NewEvent: func() interface{} {
+			return &X509{}
+		},
+		Validate: validatePath("x509"),
+		Framing:  zeekFraming,
+	},
+	logtypes.ConfigJSON{
+		Name:         TypeFiles,
+		Description:  `Zeek file analysis results`,
+		ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/frameworks/files/main.zeek.html#type-Files::Info`,
+		NewEvent: func() interface{} {
+			return &Files{}
+		},
+		Validate: validatePath("files"),
+		Framing:  zeekFraming,
+	},
+	logtypes.ConfigJSON{
+		Name:         TypeNotice,
+		Description:  `Zeek notices raised by the notice framework`,
+		ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/frameworks/notice/main.zeek.html#type-Notice::Info`,
+		NewEvent: func() interface{} {
+			return &Notice{}
+		},
+		Validate: validatePath("notice"),
+		Framing:  zeekFraming,
+	},
+	logtypes.ConfigJSON{
+		Name:         TypeWeird,
+		Description:  `Zeek unexpected network-level activity`,
+		ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/frameworks/notice/weird.zeek.html#type-Weird::Info`,
+		NewEvent: func() interface{} {
+			return &Weird{}
+		},
+		Validate: validatePath("weird"),
+		Framing:  zeekFraming,
+	},
+	logtypes.ConfigJSON{
+		Name:         TypeSSH,
+		Description:  `Zeek SSH handshakes`,
+		ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/ssh/main.zeek.html#type-SSH::Info`,
+		NewEvent: func() interface{} {
+			return &SSH{}
+		},
+		Validate: validatePath("ssh"),
+		Framing:  zeekFraming,
+	},
+	logtypes.ConfigJSON{
+		Name:         TypeDHCP,
+		Description:  `Zeek DHCP leases`,
+		ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/dhcp/main.zeek.html#type-DHCP::Info`,
+		NewEvent: func() interface{} {
+			return &DHCP{}
+		},
+		Validate: validatePath("dhcp"),
+		Framing:  zeekFraming,
+	},
+)
+
+// pathEvent is implemented by Zeek events that carry the `_path` of the log stream they were written to.
+type pathEvent interface {
+	zeekPath() string
+}
+
+// validatePath checks that an event written to a Zeek log stream belongs to the log type.
+// Zeek JSON logs only include `_path` if configured to, while TSV logs always set it from the `#path` header.
+// Events without a `_path` are validated by their required fields alone.
+func validatePath(path string) func(interface{}) error {
+	return func(x interface{}) error {
+		if event, ok := x.(pathEvent); ok {
+			if actual := event.zeekPath(); actual != "" && actual != path {
+				return errors.Errorf("zeek log path %q does not match %q", actual, path)
+			}
+		}
+		return pantherlog.ValidateStruct(x)
+	}
+}
+
+func (e *Conn) zeekPath() string   { return e.Path.Value }
+func (e *HTTP) zeekPath() string   { return e.Path.Value }
+func (e *SSL) zeekPath() string    { return e.Path.Value }
+func (e *X509) zeekPath() string   { return e.Path.Value }
+func (e *Files) zeekPath() string  { return e.Path.Value }
+func (e *Notice) zeekPath() string { return e.Path.Value }
+func (e *Weird) zeekPath() string  { return e.Path.Value }
+func (e *SSH) zeekPath() string    { return e.Path.Value }
+func (e *DHCP) zeekPath() string   { return e.Path.Value }
@@ -0,0 +1,98 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logtesting"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+)
+
+func TestConn(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/conn_tests.yml")
+}
+func TestHTTP(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/http_tests.yml")
+}
+func TestSSL(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/ssl_tests.yml")
+}
+func TestX509(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/x509_tests.yml")
+}
+func TestFiles(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/files_tests.yml")
+}
+func TestNotice(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/notice_tests.yml")
+}
+func TestWeird(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/weird_tests.yml")
+}
+func TestSSH(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/ssh_tests.yml")
+}
+func TestDHCP(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/dhcp_tests.yml")
+}
+
+// Tests that TSV logs are converted by the zeek framing and only match the log type of their `#path`
+func TestZeekTSV(t *testing.T) {
+	// nolint:lll
+	input := strings.Join([]string{
+		"#separator \\x09",
+		"#set_separator\t,",
+		"#empty_field\t(empty)",
+		"#unset_field\t-",
+		"#path\tconn",
+		"#fields\tts\tuid\tid.orig_h\tid.orig_p\tid.resp_h\tid.resp_p\tproto\tservice\tduration\torig_bytes\tresp_bytes\tconn_state\tlocal_orig\thistory\ttunnel_parents",
+		"#types\ttime\tstring\taddr\tport\taddr\tport\tenum\tstring\tinterval\tcount\tcount\tstring\tbool\tstring\tset[string]",
+		"1591367999.305988\tCMdzit1AMNsmfAIiQc\t192.168.4.76\t36844\t192.168.4.1\t53\tudp\tdns\t0.066852\t62\t141\tSF\tT\tDd\t(empty)",
+		"#close\t2020-06-05-15-00-00",
+	}, "\n")
+	stream := logstream.NewZeekStream(strings.NewReader(input), 0)
+	line := stream.Next()
+	require.NoError(t, stream.Err())
+	require.NotNil(t, line)
+	require.Nil(t, stream.Next())
+
+	connParser, err := LogTypes().Find(TypeConn).NewParser(nil)
+	require.NoError(t, err)
+	results, err := connParser.ParseLog(string(line))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	event := results[0].Event.(*Conn)
+	require.Equal(t, "conn", event.Path.Value)
+	require.Equal(t, "CMdzit1AMNsmfAIiQc", event.UID.Value)
+	require.Equal(t, uint16(53), event.IDRespP.Value)
+	require.Equal(t, "SF", event.ConnState.Value)
+	require.True(t, event.LocalOrig.Value)
+	require.Empty(t, event.TunnelParents)
+
+	for _, logType := range []string{TypeZeekDNS, TypeSSL, TypeSSH} {
+		parser, err := LogTypes().Find(logType).NewParser(nil)
+		require.NoError(t, err)
+		_, err = parser.ParseLog(string(line))
+		require.Error(t, err, "conn log passes as %s", logType)
+	}
+}
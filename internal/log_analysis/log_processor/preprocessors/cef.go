@@ -0,0 +1,251 @@
+package preprocessors
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// nolint:lll
+type CEFConfig struct {
+	HeaderFields []string          `json:"headerFields,omitempty" yaml:"headerFields,omitempty" description:"Field names for the 7 header values (defaults to version, deviceVendor, deviceProduct, deviceVersion, signatureId, name, severity)"`
+	SkipPrefix   string            `json:"skipPrefix,omitempty" yaml:"skipPrefix,omitempty" description:"Skip comment lines by prefix"`
+	EmptyValues  []string          `json:"emptyValues,omitempty" yaml:"emptyValues,omitempty" description:"Placeholder value for empty or missing data"`
+	ExpandFields map[string]string `json:"expandFields,omitempty" yaml:"expandFields,omitempty" description:"Add fields by text templates"`
+	TrimSpace    bool              `json:"trimSpace,omitempty" yaml:"trimSpace,omitempty" description:"Trim space surrounding values"`
+}
+
+// DefaultCEFHeaderFields are the field names of the CEF header values
+var DefaultCEFHeaderFields = []string{
+	"version",
+	"deviceVendor",
+	"deviceProduct",
+	"deviceVersion",
+	"signatureId",
+	"name",
+	"severity",
+}
+
+const (
+	cefPrefix  = "CEF:"
+	leefPrefix = "LEEF:"
+)
+
+// BuildPreprocessor builds a preprocessor for ArcSight Common Event Format logs.
+// Any text before the 'CEF:' prefix (i.e. a syslog header) is ignored.
+// The header values are stored in the header fields and the extension key/value pairs in fields named after their keys.
+func (config CEFConfig) BuildPreprocessor() (Interface, error) {
+	header := DefaultCEFHeaderFields
+	if len(config.HeaderFields) > 0 {
+		header = config.HeaderFields
+	}
+	if len(header) != len(DefaultCEFHeaderFields) {
+		return nil, errors.Errorf("CEF header has %d fields, got %d field names", len(DefaultCEFHeaderFields), len(header))
+	}
+	return &matchTextPreprocessor{
+		match: func(dst []string, src string) ([]string, error) {
+			return splitCEF(dst, src, header)
+		},
+		skipPrefix:   config.SkipPrefix,
+		emptyValues:  config.EmptyValues,
+		expandFields: compileFieldTemplates(config.ExpandFields),
+		trimSpace:    config.TrimSpace,
+		stream:       buildJSONStream(),
+	}, nil
+}
+
+func splitCEF(dst []string, src string, header []string) ([]string, error) {
+	pos := strings.Index(src, cefPrefix)
+	if pos == -1 {
+		return dst, errors.New("missing CEF prefix")
+	}
+	src = src[pos+len(cefPrefix):]
+	for _, name := range header {
+		end := indexUnescaped(src, '|', '\\')
+		if end == -1 {
+			return dst, errors.New("invalid CEF header")
+		}
+		dst = append(dst, name, unescapeValue(src[:end], '\\'))
+		src = src[end+1:]
+	}
+	return splitCEFExtension(dst, src)
+}
+
+// splitCEFExtension splits the space separated key=value pairs of a CEF extension.
+// Values can contain spaces, a value ends at the last space before the next key.
+func splitCEFExtension(dst []string, src string) ([]string, error) {
+	src = strings.TrimSpace(src)
+	if src == "" {
+		return dst, nil
+	}
+	end := indexUnescaped(src, '=', '\\')
+	if end == -1 {
+		return dst, errors.New("invalid CEF extension")
+	}
+	key := strings.TrimSpace(src[:end])
+	src = src[end+1:]
+	for {
+		// Find the next key
+		valueEnd, next := -1, -1
+		for offset := 0; ; {
+			pos := indexUnescaped(src[offset:], '=', '\\')
+			if pos == -1 {
+				break
+			}
+			pos += offset
+			if space := strings.LastIndexByte(src[:pos], ' '); space != -1 {
+				valueEnd, next = space, pos
+				break
+			}
+			// An unescaped '=' without a key, it is part of the value
+			offset = pos + 1
+		}
+		if next == -1 {
+			dst = append(dst, key, unescapeValue(strings.TrimSpace(src), '\\'))
+			return dst, nil
+		}
+		dst = append(dst, key, unescapeValue(strings.TrimSpace(src[:valueEnd]), '\\'))
+		key = src[valueEnd+1 : next]
+		src = src[next+1:]
+	}
+}
+
+// nolint:lll
+type LEEFConfig struct {
+	HeaderFields []string          `json:"headerFields,omitempty" yaml:"headerFields,omitempty" description:"Field names for the 5 header values (defaults to version, vendor, product, productVersion, eventId)"`
+	Delimiter    string            `json:"delimiter,omitempty" yaml:"delimiter,omitempty" description:"Delimiter of the attributes (defaults to tab or the delimiter in the LEEF 2.0 header)"`
+	SkipPrefix   string            `json:"skipPrefix,omitempty" yaml:"skipPrefix,omitempty" description:"Skip comment lines by prefix"`
+	EmptyValues  []string          `json:"emptyValues,omitempty" yaml:"emptyValues,omitempty" description:"Placeholder value for empty or missing data"`
+	ExpandFields map[string]string `json:"expandFields,omitempty" yaml:"expandFields,omitempty" description:"Add fields by text templates"`
+	TrimSpace    bool              `json:"trimSpace,omitempty" yaml:"trimSpace,omitempty" description:"Trim space surrounding values"`
+}
+
+// DefaultLEEFHeaderFields are the field names of the LEEF header values
+var DefaultLEEFHeaderFields = []string{
+	"version",
+	"vendor",
+	"product",
+	"productVersion",
+	"eventId",
+}
+
+const defaultLEEFDelimiter = "\t"
+
+// BuildPreprocessor builds a preprocessor for IBM QRadar Log Event Extended Format (LEEF 1.0 and 2.0) logs.
+// Any text before the 'LEEF:' prefix (i.e. a syslog header) is ignored.
+func (config LEEFConfig) BuildPreprocessor() (Interface, error) {
+	header := DefaultLEEFHeaderFields
+	if len(config.HeaderFields) > 0 {
+		header = config.HeaderFields
+	}
+	if len(header) != len(DefaultLEEFHeaderFields) {
+		return nil, errors.Errorf("LEEF header has %d fields, got %d field names", len(DefaultLEEFHeaderFields), len(header))
+	}
+	return &matchTextPreprocessor{
+		match: func(dst []string, src string) ([]string, error) {
+			return splitLEEF(dst, src, header, config.Delimiter)
+		},
+		skipPrefix:   config.SkipPrefix,
+		emptyValues:  config.EmptyValues,
+		expandFields: compileFieldTemplates(config.ExpandFields),
+		trimSpace:    config.TrimSpace,
+		stream:       buildJSONStream(),
+	}, nil
+}
+
+func splitLEEF(dst []string, src string, header []string, delimiter string) ([]string, error) {
+	pos := strings.Index(src, leefPrefix)
+	if pos == -1 {
+		return dst, errors.New("missing LEEF prefix")
+	}
+	src = src[pos+len(leefPrefix):]
+	var version string
+	for i, name := range header {
+		end := strings.IndexByte(src, '|')
+		if end == -1 {
+			return dst, errors.New("invalid LEEF header")
+		}
+		if i == 0 {
+			version = src[:end]
+		}
+		dst = append(dst, name, src[:end])
+		src = src[end+1:]
+	}
+	// LEEF 2.0 headers specify the attribute delimiter
+	if strings.HasPrefix(version, "2") {
+		end := strings.IndexByte(src, '|')
+		if end == -1 {
+			return dst, errors.New("invalid LEEF 2.0 header")
+		}
+		if delimiter == "" {
+			d, err := parseLEEFDelimiter(src[:end])
+			if err != nil {
+				return dst, err
+			}
+			delimiter = d
+		}
+		src = src[end+1:]
+	}
+	if delimiter == "" {
+		delimiter = defaultLEEFDelimiter
+	}
+	for _, attr := range strings.Split(src, delimiter) {
+		if strings.TrimSpace(attr) == "" {
+			continue
+		}
+		end := strings.IndexByte(attr, '=')
+		if end == -1 {
+			return dst, errors.Errorf("invalid LEEF attribute %q", attr)
+		}
+		dst = append(dst, strings.TrimSpace(attr[:end]), attr[end+1:])
+	}
+	return dst, nil
+}
+
+// parseLEEFDelimiter parses the delimiter of a LEEF 2.0 header, it is either a character or its hex code (i.e. 'x09' or '0x09')
+func parseLEEFDelimiter(src string) (string, error) {
+	if len(src) <= 1 {
+		return src, nil
+	}
+	hex := strings.TrimPrefix(strings.TrimPrefix(src, "0"), "x")
+	if hex == src {
+		return "", errors.Errorf("invalid LEEF delimiter %q", src)
+	}
+	code, err := strconv.ParseUint(hex, 16, 8)
+	if err != nil {
+		return "", errors.Errorf("invalid LEEF delimiter %q", src)
+	}
+	return string(rune(code)), nil
+}
+
+// indexUnescaped returns the position of the first c in src that is not escaped or -1
+func indexUnescaped(src string, c, escape byte) int {
+	for i := 0; i < len(src); i++ {
+		switch src[i] {
+		case escape:
+			i++
+		case c:
+			return i
+		}
+	}
+	return -1
+}
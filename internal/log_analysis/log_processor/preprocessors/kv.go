@@ -0,0 +1,266 @@
+package preprocessors
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// nolint:lll
+type KVConfig struct {
+	PairDelimiter     string            `json:"pairDelimiter,omitempty" yaml:"pairDelimiter,omitempty" description:"Delimiter between key/value pairs (defaults to whitespace)"`
+	KeyValueDelimiter string            `json:"keyValueDelimiter,omitempty" yaml:"keyValueDelimiter,omitempty" description:"Delimiter between a key and its value (defaults to '=')"`
+	QuoteChars        string            `json:"quoteChars,omitempty" yaml:"quoteChars,omitempty" description:"Characters that quote values (defaults to '\"')"`
+	EscapeChar        string            `json:"escapeChar,omitempty" yaml:"escapeChar,omitempty" description:"Character that escapes delimiters and quotes in values (defaults to '\\')"`
+	NoEscape          bool              `json:"noEscape,omitempty" yaml:"noEscape,omitempty" description:"Disable escaping so that values are read as-is (i.e. Windows paths)"`
+	SkipLines         int               `json:"skipLines,omitempty" yaml:"skipLines,omitempty" description:"Number of lines to skip at start of file"`
+	SkipPrefix        string            `json:"skipPrefix,omitempty" yaml:"skipPrefix,omitempty" description:"Skip comment lines by prefix"`
+	EmptyValues       []string          `json:"emptyValues,omitempty" yaml:"emptyValues,omitempty" description:"Placeholder value for empty or missing data"`
+	ExpandFields      map[string]string `json:"expandFields,omitempty" yaml:"expandFields,omitempty" description:"Add fields by text templates"`
+	TrimSpace         bool              `json:"trimSpace,omitempty" yaml:"trimSpace,omitempty" description:"Trim space surrounding values"`
+}
+
+const (
+	defaultKVDelimiter  = "="
+	defaultKVQuoteChars = `"`
+	defaultKVEscapeChar = `\`
+)
+
+func (config KVConfig) BuildPreprocessor() (Interface, error) {
+	escape := config.EscapeChar
+	switch {
+	case config.NoEscape && escape != "":
+		return nil, errors.New("escape character is set but escaping is disabled")
+	case escape == "" && !config.NoEscape:
+		escape = defaultKVEscapeChar
+	}
+	splitter, err := newKVSplitter(config.PairDelimiter, config.KeyValueDelimiter, config.QuoteChars, escape)
+	if err != nil {
+		return nil, err
+	}
+	return &matchTextPreprocessor{
+		match:        splitter.match,
+		skipLines:    config.SkipLines,
+		skipPrefix:   config.SkipPrefix,
+		emptyValues:  config.EmptyValues,
+		expandFields: compileFieldTemplates(config.ExpandFields),
+		trimSpace:    config.TrimSpace,
+		stream:       buildJSONStream(),
+	}, nil
+}
+
+// nolint:lll
+type LogfmtConfig struct {
+	SkipPrefix   string            `json:"skipPrefix,omitempty" yaml:"skipPrefix,omitempty" description:"Skip comment lines by prefix"`
+	EmptyValues  []string          `json:"emptyValues,omitempty" yaml:"emptyValues,omitempty" description:"Placeholder value for empty or missing data"`
+	ExpandFields map[string]string `json:"expandFields,omitempty" yaml:"expandFields,omitempty" description:"Add fields by text templates"`
+}
+
+// BuildPreprocessor builds a preprocessor for logfmt (https://brandur.org/logfmt) logs.
+// Keys without a value are set to 'true'.
+func (config LogfmtConfig) BuildPreprocessor() (Interface, error) {
+	splitter, err := newKVSplitter("", "", "", defaultKVEscapeChar)
+	if err != nil {
+		return nil, err
+	}
+	splitter.flagValue = "true"
+	return &matchTextPreprocessor{
+		match:        splitter.match,
+		skipPrefix:   config.SkipPrefix,
+		emptyValues:  config.EmptyValues,
+		expandFields: compileFieldTemplates(config.ExpandFields),
+		stream:       buildJSONStream(),
+	}, nil
+}
+
+// kvSplitter splits text into key/value pairs
+type kvSplitter struct {
+	// pairDelimiter is empty if pairs are delimited by whitespace
+	pairDelimiter string
+	kvDelimiter   string
+	quotes        string
+	// escape is zero if escaping is disabled
+	escape byte
+	// flagValue is the value of keys without a value
+	flagValue string
+}
+
+// newKVSplitter creates a splitter, escaping is disabled if escape is empty
+func newKVSplitter(pairDelimiter, kvDelimiter, quotes, escape string) (*kvSplitter, error) {
+	if kvDelimiter == "" {
+		kvDelimiter = defaultKVDelimiter
+	}
+	if quotes == "" {
+		quotes = defaultKVQuoteChars
+	}
+	if len(escape) > 1 {
+		return nil, errors.Errorf("invalid escape character %q", escape)
+	}
+	// Pairs delimited by a space are delimited by any whitespace
+	if strings.TrimSpace(pairDelimiter) == "" {
+		pairDelimiter = ""
+	}
+	if pairDelimiter == kvDelimiter {
+		return nil, errors.New("pair and key/value delimiters must be different")
+	}
+	s := &kvSplitter{
+		pairDelimiter: pairDelimiter,
+		kvDelimiter:   kvDelimiter,
+		quotes:        quotes,
+	}
+	if escape != "" {
+		s.escape = escape[0]
+	}
+	return s, nil
+}
+
+func (s *kvSplitter) match(dst []string, src string) ([]string, error) {
+	numFields := len(dst)
+	for {
+		src = s.trimDelimiters(src)
+		if src == "" {
+			break
+		}
+		keyEnd := strings.Index(src, s.kvDelimiter)
+		pairEnd := s.indexPairDelimiter(src)
+		if keyEnd == -1 || (0 <= pairEnd && pairEnd < keyEnd) {
+			// A key without a value
+			if pairEnd == -1 {
+				pairEnd = len(src)
+			}
+			if key := strings.TrimSpace(src[:pairEnd]); key != "" {
+				dst = append(dst, key, s.flagValue)
+			}
+			src = src[pairEnd:]
+			continue
+		}
+		key := strings.TrimSpace(src[:keyEnd])
+		if key == "" {
+			return dst, errors.New("missing key")
+		}
+		value, tail, err := s.readValue(src[keyEnd+len(s.kvDelimiter):])
+		if err != nil {
+			return dst, errors.WithMessagef(err, "invalid value for key %q", key)
+		}
+		dst = append(dst, key, value)
+		src = tail
+	}
+	if len(dst) == numFields {
+		return dst, errors.New("no key/value pairs")
+	}
+	return dst, nil
+}
+
+// readValue reads a value and returns the rest of the text
+func (s *kvSplitter) readValue(src string) (value, tail string, err error) {
+	if src != "" && strings.IndexByte(s.quotes, src[0]) != -1 {
+		quote := src[0]
+		for i := 1; i < len(src); i++ {
+			switch c := src[i]; {
+			case s.isEscape(c):
+				i++
+			case c == quote:
+				return s.unescape(src[1:i]), src[i+1:], nil
+			}
+		}
+		return "", "", errors.New("unterminated quoted value")
+	}
+	end := s.indexPairDelimiter(src)
+	if end == -1 {
+		end = len(src)
+	}
+	return s.unescape(src[:end]), src[end:], nil
+}
+
+func (s *kvSplitter) isEscape(c byte) bool {
+	return s.escape != 0 && c == s.escape
+}
+
+func (s *kvSplitter) unescape(value string) string {
+	if s.escape == 0 {
+		return value
+	}
+	return unescapeValue(value, s.escape)
+}
+
+// indexPairDelimiter returns the position of the first unescaped pair delimiter or -1
+func (s *kvSplitter) indexPairDelimiter(src string) int {
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case s.isEscape(c):
+			i++
+		case s.pairDelimiter == "":
+			if isSpace(c) {
+				return i
+			}
+		case strings.HasPrefix(src[i:], s.pairDelimiter):
+			return i
+		}
+	}
+	return -1
+}
+
+// trimDelimiters removes the leading pair delimiters and whitespace
+func (s *kvSplitter) trimDelimiters(src string) string {
+	for {
+		src = strings.TrimLeft(src, " \t")
+		if s.pairDelimiter == "" || !strings.HasPrefix(src, s.pairDelimiter) {
+			return src
+		}
+		src = src[len(s.pairDelimiter):]
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// unescapeValue replaces escape sequences, the common control character escapes (\n, \r, \t) are supported
+func unescapeValue(src string, escape byte) string {
+	pos := strings.IndexByte(src, escape)
+	if pos == -1 {
+		return src
+	}
+	var b strings.Builder
+	b.Grow(len(src))
+	for ; pos != -1; pos = strings.IndexByte(src, escape) {
+		b.WriteString(src[:pos])
+		if pos+1 == len(src) {
+			// Trailing escape character
+			b.WriteByte(escape)
+			return b.String()
+		}
+		switch c := src[pos+1]; c {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		default:
+			b.WriteByte(c)
+		}
+		src = src[pos+2:]
+	}
+	b.WriteString(src)
+	return b.String()
+}
@@ -0,0 +1,164 @@
+package preprocessors
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVPreprocessor(t *testing.T) {
+	type testCase struct {
+		Name   string
+		Config KVConfig
+		Input  string
+		Expect string
+	}
+	for _, tc := range []testCase{
+		{
+			Name:   "default",
+			Input:  `foo=bar baz="quoted \"value\"" empty=`,
+			Expect: `{"foo":"bar","baz":"quoted \"value\"","empty":""}`,
+		},
+		{
+			Name: "delimiters",
+			Config: KVConfig{
+				PairDelimiter:     ",",
+				KeyValueDelimiter: ":",
+				QuoteChars:        `'`,
+			},
+			Input:  `foo:bar, baz:'a, b',n:1`,
+			Expect: `{"foo":"bar","baz":"a, b","n":"1"}`,
+		},
+		{
+			Name: "empty values",
+			Config: KVConfig{
+				EmptyValues: []string{"-"},
+				TrimSpace:   true,
+			},
+			Input:  `foo=bar baz=- qux=" x "`,
+			Expect: `{"foo":"bar","qux":"x"}`,
+		},
+		{
+			Name: "no escape",
+			Config: KVConfig{
+				NoEscape: true,
+			},
+			Input:  `dir=C:\Temp\ file="C:\Temp\a b.txt"`,
+			Expect: `{"dir":"C:\\Temp\\","file":"C:\\Temp\\a b.txt"}`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			p, err := tc.Config.BuildPreprocessor()
+			require.NoError(t, err)
+			actual, err := p.PreProcessLog(tc.Input)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.Expect, actual)
+		})
+	}
+	p, err := KVConfig{}.BuildPreprocessor()
+	require.NoError(t, err)
+	_, err = p.PreProcessLog(`foo="bar`)
+	require.Error(t, err)
+	_, err = KVConfig{EscapeChar: "ab"}.BuildPreprocessor()
+	require.Error(t, err)
+	_, err = KVConfig{EscapeChar: "^", NoEscape: true}.BuildPreprocessor()
+	require.Error(t, err)
+}
+
+func TestLogfmtPreprocessor(t *testing.T) {
+	p, err := LogfmtConfig{}.BuildPreprocessor()
+	require.NoError(t, err)
+	actual, err := p.PreProcessLog(`level=info msg="request done" debug took=10ms`)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"level":"info","msg":"request done","debug":"true","took":"10ms"}`, actual)
+}
+
+func TestCEFPreprocessor(t *testing.T) {
+	p, err := CEFConfig{}.BuildPreprocessor()
+	require.NoError(t, err)
+	input := `Sep 19 08:26:10 host CEF:0|Security|threatmanager|1.0|100|worm a\|b stopped|10|` +
+		`src=10.0.0.1 dst=2.1.2.2 msg=Detected a threat. No action needed a\=b spt=1232`
+	actual, err := p.PreProcessLog(input)
+	require.NoError(t, err)
+	expect := `{
+		"version": "0",
+		"deviceVendor": "Security",
+		"deviceProduct": "threatmanager",
+		"deviceVersion": "1.0",
+		"signatureId": "100",
+		"name": "worm a|b stopped",
+		"severity": "10",
+		"src": "10.0.0.1",
+		"dst": "2.1.2.2",
+		"msg": "Detected a threat. No action needed a=b",
+		"spt": "1232"
+	}`
+	require.JSONEq(t, expect, actual)
+
+	_, err = p.PreProcessLog(`CEF:0|Security|threatmanager`)
+	require.Error(t, err)
+	_, err = CEFConfig{HeaderFields: []string{"version"}}.BuildPreprocessor()
+	require.Error(t, err)
+}
+
+func TestLEEFPreprocessor(t *testing.T) {
+	p, err := LEEFConfig{}.BuildPreprocessor()
+	require.NoError(t, err)
+	actual, err := p.PreProcessLog("LEEF:1.0|Microsoft|MSExchange|4.0 SP1|15345|src=192.0.2.0\tdst=172.50.123.1\tsev=5")
+	require.NoError(t, err)
+	expect := `{
+		"version": "1.0",
+		"vendor": "Microsoft",
+		"product": "MSExchange",
+		"productVersion": "4.0 SP1",
+		"eventId": "15345",
+		"src": "192.0.2.0",
+		"dst": "172.50.123.1",
+		"sev": "5"
+	}`
+	require.JSONEq(t, expect, actual)
+
+	actual, err = p.PreProcessLog("LEEF:2.0|Lancope|StealthWatch|1.0|41|^|src=10.0.1.8^dst=10.0.0.5^sev=5")
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"version": "2.0",
+		"vendor": "Lancope",
+		"product": "StealthWatch",
+		"productVersion": "1.0",
+		"eventId": "41",
+		"src": "10.0.1.8",
+		"dst": "10.0.0.5",
+		"sev": "5"
+	}`, actual)
+
+	actual, err = p.PreProcessLog("LEEF:2.0|Lancope|StealthWatch|1.0|41|0x7c|src=10.0.1.8|dst=10.0.0.5")
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"version": "2.0",
+		"vendor": "Lancope",
+		"product": "StealthWatch",
+		"productVersion": "1.0",
+		"eventId": "41",
+		"src": "10.0.1.8",
+		"dst": "10.0.0.5"
+	}`, actual)
+}
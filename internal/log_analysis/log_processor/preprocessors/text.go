@@ -68,6 +68,8 @@ func (p *matchTextPreprocessor) PreProcessLog(log string) (string, error) {
 			}
 		}
 	}
+	// reset the stream so output from previous lines is not repeated
+	p.stream.SetBuffer(p.stream.Buffer()[:0])
 	writeFieldsJSON(p.stream, matches)
 	// Reuse buffer
 	p.matches = matches
@@ -0,0 +1,194 @@
+package deadletters
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+func TestNewRecord(t *testing.T) {
+	result := &classification.ClassifierResult{
+		NumMiss: 3,
+		ParserErrors: []classification.ParserError{
+			{LogType: "Foo.Bar", Err: errors.New("foo failed")},
+			{LogType: "Foo.Baz", Err: errors.New("baz failed")},
+			{LogType: "Foo.Bar", Err: errors.New("bar failed")},
+		},
+	}
+	record := NewRecord("foo", result, errors.New("failed to classify log line"))
+	require.Equal(t, "foo", record.Line)
+	require.Equal(t, []ParserError{
+		{LogType: "Foo.Bar", Error: "foo failed"},
+		{LogType: "Foo.Baz", Error: "baz failed"},
+		{LogType: "Foo.Bar", Error: "bar failed"},
+	}, record.Errors)
+	require.Equal(t, []string{"Foo.Bar", "Foo.Baz"}, record.LogTypes())
+
+	record = NewRecord("foo", nil, errors.New("failed to parse JSON message"))
+	require.Equal(t, []ParserError{{Error: "failed to parse JSON message"}}, record.Errors)
+	require.Empty(t, record.LogTypes())
+}
+
+func TestStore(t *testing.T) {
+	uploader := &testutils.S3UploaderMock{}
+	snsClient := &testutils.SnsMock{}
+	store := Store{
+		Uploader:     uploader,
+		SNSAPI:       snsClient,
+		Bucket:       "bucket",
+		TopicARN:     "arn:aws:sns:us-east-1:123456789012:topic",
+		MaxBatchSize: 1024,
+	}
+
+	var uploads [][]byte
+	var keys []string
+	uploader.On("Upload", mock.Anything, mock.Anything).Return(&s3manager.UploadOutput{}, nil).Run(func(args mock.Arguments) {
+		input := args.Get(0).(*s3manager.UploadInput)
+		data, err := ioutil.ReadAll(input.Body)
+		require.NoError(t, err)
+		uploads = append(uploads, data)
+		keys = append(keys, aws.StringValue(input.Key))
+	}).Twice()
+	snsClient.On("Publish", mock.Anything).Return(&sns.PublishOutput{}, nil).Run(func(args mock.Arguments) {
+		input := args.Get(0).(*sns.PublishInput)
+		require.Equal(t, "DeadLetters", aws.StringValue(input.MessageAttributes["type"].StringValue))
+	}).Twice()
+
+	tm := time.Date(2020, 11, 5, 10, 30, 0, 0, time.UTC)
+	records := []*Record{
+		{
+			Timestamp:   tm,
+			SourceID:    "source-id",
+			S3Bucket:    "input",
+			S3ObjectKey: "logs/foo.log",
+			LineNumber:  1,
+			Line:        "foo",
+			Errors:      []ParserError{{LogType: "Foo.Bar", Error: "failed"}},
+		},
+		{
+			Timestamp:   tm.Add(time.Minute),
+			SourceID:    "source-id",
+			S3Bucket:    "input",
+			S3ObjectKey: "logs/foo.log",
+			LineNumber:  2,
+			Line:        "bar",
+			Errors:      []ParserError{{LogType: "Foo.Bar", Error: "failed"}},
+		},
+		{
+			// A different hour starts a new batch
+			Timestamp:   tm.Add(time.Hour),
+			SourceID:    "source-id",
+			S3Bucket:    "input",
+			S3ObjectKey: "logs/bar.log",
+			LineNumber:  1,
+			Line:        "baz",
+			Errors:      []ParserError{{LogType: "Foo.Bar", Error: "failed"}},
+		},
+	}
+	for _, record := range records {
+		require.NoError(t, store.Put(record))
+	}
+	require.Len(t, uploads, 1)
+	require.NoError(t, store.Flush())
+	require.Len(t, uploads, 2)
+	// Nothing to flush
+	require.NoError(t, store.Flush())
+	uploader.AssertExpectations(t)
+	snsClient.AssertExpectations(t)
+
+	require.True(t, strings.HasPrefix(keys[0], "logs/panther_dead_letters/year=2020/month=11/day=05/hour=10/20201105T100000Z-"), keys[0])
+	require.True(t, strings.HasPrefix(keys[1], "logs/panther_dead_letters/year=2020/month=11/day=05/hour=11/20201105T110000Z-"), keys[1])
+
+	var actual []*Record
+	for _, data := range uploads {
+		err := ReadRecords(bytes.NewReader(data), func(record *Record) error {
+			actual = append(actual, record)
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	require.Equal(t, records, actual)
+}
+
+func TestStoreMaxBatchSize(t *testing.T) {
+	uploader := &testutils.S3UploaderMock{}
+	snsClient := &testutils.SnsMock{}
+	store := Store{
+		Uploader:     uploader,
+		SNSAPI:       snsClient,
+		MaxBatchSize: 10,
+	}
+	uploader.On("Upload", mock.Anything, mock.Anything).Return(&s3manager.UploadOutput{}, nil).Once()
+	snsClient.On("Publish", mock.Anything).Return(&sns.PublishOutput{}, nil).Once()
+	require.NoError(t, store.Put(&Record{
+		Timestamp: time.Now(),
+		Line:      "a line longer than the batch",
+	}))
+	uploader.AssertExpectations(t)
+	snsClient.AssertExpectations(t)
+}
+
+func TestStoreUploadWithoutLock(t *testing.T) {
+	uploader := &testutils.S3UploaderMock{}
+	snsClient := &testutils.SnsMock{}
+	store := Store{
+		Uploader: uploader,
+		SNSAPI:   snsClient,
+	}
+	// Records can be added while a batch is uploading
+	uploader.On("Upload", mock.Anything, mock.Anything).Return(&s3manager.UploadOutput{}, nil).Run(func(_ mock.Arguments) {
+		require.NoError(t, store.Put(&Record{Timestamp: time.Now(), Line: "bar"}))
+	}).Once()
+	uploader.On("Upload", mock.Anything, mock.Anything).Return(&s3manager.UploadOutput{}, nil).Once()
+	snsClient.On("Publish", mock.Anything).Return(&sns.PublishOutput{}, nil).Twice()
+	require.NoError(t, store.Put(&Record{Timestamp: time.Now(), Line: "foo"}))
+	require.NoError(t, store.Flush())
+	require.NoError(t, store.Flush())
+	uploader.AssertExpectations(t)
+	snsClient.AssertExpectations(t)
+}
+
+func TestReadRecordsPlain(t *testing.T) {
+	input := `{"timestamp":"2020-11-05 10:30:00.000000000","sourceId":"id","line":"foo","lineNumber":3}
+{"timestamp":"2020-11-05 10:31:00.000000000","sourceId":"id","line":"bar","lineNumber":4}
+`
+	var lines []string
+	err := ReadRecords(strings.NewReader(input), func(record *Record) error {
+		require.Equal(t, "id", record.SourceID)
+		require.Equal(t, 2020, record.Timestamp.Year())
+		lines = append(lines, record.Line)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo", "bar"}, lines)
+}
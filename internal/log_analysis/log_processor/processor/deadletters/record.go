@@ -0,0 +1,127 @@
+package deadletters
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/pantherdb"
+	"github.com/panther-labs/panther/pkg/stringset"
+)
+
+// Record is a log line that failed classification
+type Record struct {
+	Timestamp   time.Time     `json:"timestamp" tcodec:"layout=2006-01-02 15:04:05.000000000" description:"When classification failed"`
+	SourceID    string        `json:"sourceId" description:"The id of the source of the line"`
+	SourceLabel string        `json:"sourceLabel" description:"The label of the source of the line"`
+	S3Bucket    string        `json:"s3Bucket" description:"The S3 bucket of the file with the line"`
+	S3ObjectKey string        `json:"s3ObjectKey" description:"The S3 object key of the file with the line"`
+	LineNumber  uint64        `json:"lineNumber" description:"The line number in the file"`
+	Line        string        `json:"line" description:"The raw log line"`
+	Errors      []ParserError `json:"errors" description:"The errors of each parser that failed to parse the line"`
+}
+
+// ParserError is the error of a parser that failed to parse a line
+type ParserError struct {
+	LogType string `json:"logType" description:"The log type of the parser"`
+	Error   string `json:"error" description:"The parser error"`
+}
+
+// LogTypes returns the log types the line was classified against
+func (r *Record) LogTypes() []string {
+	var logTypes []string
+	for _, e := range r.Errors {
+		if e.LogType != "" {
+			logTypes = stringset.Append(logTypes, e.LogType)
+		}
+	}
+	return logTypes
+}
+
+// NewRecord creates the record of a line that failed classification.
+// The errors of the parsers are taken from the classifier result, if there are none (i.e. the line could not be
+// passed to any parser) err is recorded instead.
+func NewRecord(line string, result *classification.ClassifierResult, err error) *Record {
+	record := Record{
+		Timestamp: time.Now().UTC(),
+		Line:      line,
+	}
+	if result != nil {
+		for _, e := range result.ParserErrors {
+			record.Errors = append(record.Errors, ParserError{
+				LogType: e.LogType,
+				Error:   e.Err.Error(),
+			})
+		}
+	}
+	if len(record.Errors) == 0 && err != nil {
+		record.Errors = []ParserError{{Error: err.Error()}}
+	}
+	return &record
+}
+
+// TableMetadata is the Glue table of dead letter records
+var TableMetadata = awsglue.NewGlueTableMetadata(
+	pantherdb.DatabaseName(pantherdb.DeadLetters),
+	pantherdb.DeadLettersTable,
+	pantherdb.DeadLettersTableDescription,
+	awsglue.GlueTableHourly,
+	&Record{},
+)
+
+// ReadRecords reads the records of a dead letter file, the file can be gzipped
+func ReadRecords(r io.Reader, fn func(record *Record) error) error {
+	br := bufio.NewReader(r)
+	// Detect gzip magic bytes
+	if header, _ := br.Peek(2); len(header) == 2 && header[0] == 0x1f && header[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return errors.Wrap(err, "failed to read gzip header")
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = br
+	}
+	const bufferSize = 64 * 1024
+	iter := jsoniter.Parse(pantherlog.ConfigJSON(), r, bufferSize)
+	for iter.WhatIsNext() != jsoniter.InvalidValue {
+		record := Record{}
+		iter.ReadVal(&record)
+		if iter.Error != nil {
+			return errors.Wrap(iter.Error, "failed to read dead letter record")
+		}
+		if err := fn(&record); err != nil {
+			return err
+		}
+	}
+	if iter.Error != nil && iter.Error != io.EOF {
+		return errors.Wrap(iter.Error, "failed to read dead letter records")
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package deadletters
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/notify"
+	"github.com/panther-labs/panther/internal/log_analysis/pantherdb"
+)
+
+const (
+	// DefaultMaxBatchSize is the default maximum size in bytes of the uncompressed records of a batch
+	DefaultMaxBatchSize = 10 * 1024 * 1024
+
+	// The timestamp layout used in the S3 object key filename part with second precision: yyyyMMddTHHmmssZ
+	objectTimestampLayout = "20060102T150405Z"
+)
+
+// Store writes dead letter records in batches of gzipped JSON lines to the hourly partitions of the dead letters table.
+// A notification is sent to the processed data topic for each batch so that the partitions are created.
+type Store struct {
+	Uploader s3manageriface.UploaderAPI
+	SNSAPI   snsiface.SNSAPI
+	// Bucket is the processed data bucket
+	Bucket string
+	// TopicARN is the processed data topic
+	TopicARN string
+	// MaxBatchSize is the maximum size in bytes of the uncompressed records of a batch.
+	// If zero DefaultMaxBatchSize is used.
+	MaxBatchSize int
+
+	mu         sync.Mutex
+	hour       time.Time
+	buffer     *bytes.Buffer
+	gzip       *gzip.Writer
+	size       int
+	numRecords int
+}
+
+// batch is a batch of compressed records taken from the store to be uploaded
+type batch struct {
+	hour       time.Time
+	data       *bytes.Buffer
+	numRecords int
+}
+
+// Put adds a record to the current batch.
+// The batch is stored when it is full or when the record belongs to a different hour.
+// Batches are uploaded without holding the lock, so that other records can be added meanwhile.
+func (s *Store) Put(record *Record) error {
+	data, err := pantherlog.ConfigJSON().Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize dead letter record")
+	}
+	data = append(data, '\n')
+	s.mu.Lock()
+	batches, err := s.put(record.Timestamp.UTC().Truncate(time.Hour), data)
+	s.mu.Unlock()
+	for _, b := range batches {
+		if err := s.upload(b); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// put adds a record to the current batch and returns the batches that are ready to be uploaded
+func (s *Store) put(hour time.Time, data []byte) ([]*batch, error) {
+	var ready []*batch
+	if s.numRecords > 0 && !hour.Equal(s.hour) {
+		b, err := s.take()
+		if err != nil {
+			return nil, err
+		}
+		ready = append(ready, b)
+	}
+	if s.gzip == nil {
+		s.buffer = &bytes.Buffer{}
+		s.gzip = gzip.NewWriter(s.buffer)
+	}
+	s.hour = hour
+	if _, err := s.gzip.Write(data); err != nil {
+		return ready, errors.Wrap(err, "failed to compress dead letter record")
+	}
+	s.size += len(data)
+	s.numRecords++
+	if s.size < s.maxBatchSize() {
+		return ready, nil
+	}
+	b, err := s.take()
+	if err != nil {
+		return ready, err
+	}
+	return append(ready, b), nil
+}
+
+// Flush stores the current batch
+func (s *Store) Flush() error {
+	s.mu.Lock()
+	b, err := s.take()
+	s.mu.Unlock()
+	if err != nil || b == nil {
+		return err
+	}
+	return s.upload(b)
+}
+
+func (s *Store) maxBatchSize() int {
+	if s.MaxBatchSize > 0 {
+		return s.MaxBatchSize
+	}
+	return DefaultMaxBatchSize
+}
+
+// take removes the current batch from the store, it returns nil if there are no records
+func (s *Store) take() (*batch, error) {
+	if s.numRecords == 0 {
+		return nil, nil
+	}
+	b := &batch{
+		hour:       s.hour,
+		data:       s.buffer,
+		numRecords: s.numRecords,
+	}
+	err := s.gzip.Close()
+	s.buffer = nil
+	s.gzip = nil
+	s.size = 0
+	s.numRecords = 0
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compress dead letter records")
+	}
+	return b, nil
+}
+
+// upload uploads a batch, records of a batch that fails to upload are lost
+func (s *Store) upload(b *batch) error {
+	key := path.Join(TableMetadata.PartitionPrefix(b.hour), fmt.Sprintf("%s-%s.json.gz",
+		b.hour.Format(objectTimestampLayout),
+		uuid.New(),
+	))
+	nbytes := b.data.Len()
+	if _, err := s.Uploader.Upload(&s3manager.UploadInput{
+		Bucket: &s.Bucket,
+		Key:    &key,
+		Body:   b.data,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to upload %d dead letter records", b.numRecords)
+	}
+	notification, err := jsoniter.MarshalToString(notify.NewS3ObjectPutNotification(s.Bucket, key, nbytes))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal notification")
+	}
+	if _, err := s.SNSAPI.Publish(&sns.PublishInput{
+		TopicArn:          &s.TopicARN,
+		Message:           &notification,
+		MessageAttributes: notify.NewLogAnalysisSNSMessageAttributes(pantherdb.DeadLetters, pantherdb.DeadLettersTable),
+	}); err != nil {
+		return errors.Wrap(err, "failed to send notification to topic")
+	}
+	return nil
+}
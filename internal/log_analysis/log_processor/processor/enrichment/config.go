@@ -0,0 +1,167 @@
+package enrichment
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+
+	DefaultMaxRows         = 100000
+	DefaultMaxBytes        = 50 * 1024 * 1024
+	DefaultRefreshInterval = 15 * time.Minute
+)
+
+// Config is the configuration of the enrichment stage
+type Config struct {
+	Tables []TableConfig `json:"tables"`
+	Joins  []JoinConfig  `json:"joins"`
+}
+
+// TableConfig describes a lookup table
+type TableConfig struct {
+	// Name is the key of the table rows in p_enrichment
+	Name string `json:"name"`
+	// Location is either an S3 URL (s3://bucket/key) or a local file path.
+	// Files ending in .gz are decompressed.
+	Location string `json:"location"`
+	// Format is either csv or jsonl, if empty it is detected from the location extension.
+	// CSV files must have a header row.
+	Format string `json:"format,omitempty"`
+	// Key is the column used to look up rows
+	Key string `json:"key"`
+	// IgnoreCase matches keys regardless of case
+	IgnoreCase bool `json:"ignoreCase,omitempty"`
+	// Maximum number of rows of the table
+	MaxRows int `json:"maxRows,omitempty"`
+	// Maximum size of the table file in bytes
+	MaxBytes int64 `json:"maxBytes,omitempty"`
+	// RefreshInterval is how often the table is reloaded (i.e. '10m')
+	RefreshInterval string `json:"refreshInterval,omitempty"`
+}
+
+// JoinConfig adds the rows of a table to events of some log types
+type JoinConfig struct {
+	// Table is the name of the lookup table
+	Table string `json:"table"`
+	// Field is the path of the event field with the lookup keys (i.e. 'userIdentity.arn' or 'p_any_ip_addresses').
+	// If the field is an array each element is looked up and the first match is used.
+	Field string `json:"field"`
+	// LogTypes are the log types to enrich, if empty all log types are enriched
+	LogTypes []string `json:"logTypes,omitempty"`
+}
+
+func (c *TableConfig) format() (string, error) {
+	if c.Format != "" {
+		switch c.Format {
+		case FormatCSV, FormatJSONL:
+			return c.Format, nil
+		default:
+			return "", errors.Errorf("invalid table format %q", c.Format)
+		}
+	}
+	location := strings.TrimSuffix(c.Location, ".gz")
+	switch {
+	case strings.HasSuffix(location, ".csv"):
+		return FormatCSV, nil
+	case strings.HasSuffix(location, ".jsonl"), strings.HasSuffix(location, ".json"):
+		return FormatJSONL, nil
+	default:
+		return "", errors.Errorf("cannot detect format of table %q", c.Name)
+	}
+}
+
+func (c *TableConfig) maxRows() int {
+	if c.MaxRows > 0 {
+		return c.MaxRows
+	}
+	return DefaultMaxRows
+}
+
+func (c *TableConfig) maxBytes() int64 {
+	if c.MaxBytes > 0 {
+		return c.MaxBytes
+	}
+	return DefaultMaxBytes
+}
+
+func (c *TableConfig) refreshInterval() (time.Duration, error) {
+	if c.RefreshInterval == "" {
+		return DefaultRefreshInterval, nil
+	}
+	d, err := time.ParseDuration(c.RefreshInterval)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid refresh interval for table %q", c.Name)
+	}
+	return d, nil
+}
+
+// Validate checks the configuration for errors
+func (c *Config) Validate() error {
+	tables := make(map[string]bool, len(c.Tables))
+	for i := range c.Tables {
+		t := &c.Tables[i]
+		if t.Name == "" || t.Location == "" || t.Key == "" {
+			return errors.Errorf("table %d requires a name, a location and a key", i)
+		}
+		if tables[t.Name] {
+			return errors.Errorf("duplicate table %q", t.Name)
+		}
+		tables[t.Name] = true
+		if _, err := t.format(); err != nil {
+			return err
+		}
+		if _, err := t.refreshInterval(); err != nil {
+			return err
+		}
+		if _, _, err := parseLocation(t.Location); err != nil {
+			return err
+		}
+	}
+	for i := range c.Joins {
+		j := &c.Joins[i]
+		if j.Field == "" {
+			return errors.Errorf("join %d requires a field", i)
+		}
+		if !tables[j.Table] {
+			return errors.Errorf("join %d references unknown table %q", i, j.Table)
+		}
+	}
+	return nil
+}
+
+// parseLocation splits an S3 URL to bucket and key, local paths return an empty bucket
+func parseLocation(location string) (bucket, key string, err error) {
+	if !strings.HasPrefix(location, "s3://") {
+		return "", location, nil
+	}
+	bucket, key, err = awsglue.ParseS3URL(location)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "invalid table location %q", location)
+	}
+	return bucket, key, nil
+}
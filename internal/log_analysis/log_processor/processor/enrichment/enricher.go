@@ -0,0 +1,216 @@
+package enrichment
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/pkg/stringset"
+)
+
+// Enricher adds the rows of lookup tables matching event fields to the p_enrichment field of results.
+// It is safe to use Enrich concurrently with Refresh.
+type Enricher struct {
+	s3Client s3iface.S3API
+	tables   []*tableState
+	// joins for all log types
+	joins []*join
+	// joins for specific log types
+	joinsByLogType map[string][]*join
+
+	mu sync.RWMutex
+}
+
+type tableState struct {
+	config          TableConfig
+	refreshInterval time.Duration
+	// the loaded table, nil until the first successful load
+	table    *Table
+	loadedAt time.Time
+}
+
+type join struct {
+	field string
+	table *tableState
+}
+
+// LoadConfig reads the enrichment configuration from an S3 URL or a local file
+func LoadConfig(ctx context.Context, s3Client s3iface.S3API, location string) (*Config, error) {
+	r, err := OpenLocation(ctx, s3Client, location)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read enrichment config")
+	}
+	config := Config{}
+	if err := jsoniter.Unmarshal(data, &config); err != nil {
+		return nil, errors.Wrap(err, "invalid enrichment config")
+	}
+	return &config, nil
+}
+
+// New creates an enricher. Tables are not loaded until Refresh is called.
+func New(config *Config, s3Client s3iface.S3API) (*Enricher, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	e := &Enricher{
+		s3Client:       s3Client,
+		joinsByLogType: make(map[string][]*join),
+	}
+	tables := make(map[string]*tableState, len(config.Tables))
+	for _, t := range config.Tables {
+		refreshInterval, _ := t.refreshInterval()
+		state := &tableState{
+			config:          t,
+			refreshInterval: refreshInterval,
+		}
+		tables[t.Name] = state
+		e.tables = append(e.tables, state)
+	}
+	for _, j := range config.Joins {
+		jn := &join{
+			field: j.Field,
+			table: tables[j.Table],
+		}
+		if len(j.LogTypes) == 0 {
+			e.joins = append(e.joins, jn)
+			continue
+		}
+		for _, logType := range stringset.New(j.LogTypes...) {
+			e.joinsByLogType[logType] = append(e.joinsByLogType[logType], jn)
+		}
+	}
+	return e, nil
+}
+
+// Refresh loads the tables that were never loaded or whose refresh interval has passed.
+// Tables that fail to load keep their previous rows.
+func (e *Enricher) Refresh(ctx context.Context) (err error) {
+	now := time.Now()
+	for _, state := range e.tables {
+		e.mu.RLock()
+		fresh := state.table != nil && now.Sub(state.loadedAt) < state.refreshInterval
+		e.mu.RUnlock()
+		if fresh {
+			continue
+		}
+		table, loadErr := e.loadTable(ctx, &state.config)
+		if loadErr != nil {
+			err = multierr.Append(err, loadErr)
+			continue
+		}
+		e.mu.Lock()
+		state.table = table
+		state.loadedAt = now
+		e.mu.Unlock()
+		zap.L().Debug("loaded enrichment table",
+			zap.String("table", state.config.Name),
+			zap.Int("rows", table.Len()))
+	}
+	return err
+}
+
+func (e *Enricher) loadTable(ctx context.Context, config *TableConfig) (*Table, error) {
+	r, err := OpenLocation(ctx, e.s3Client, config.Location)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to load table %q", config.Name)
+	}
+	defer r.Close()
+	return ReadTable(r, config)
+}
+
+// Enrich adds p_enrichment to a result if any of the joins for its log type match.
+// The JSON API should be the same one used by the destinations so field paths (i.e. p_any_ip_countries) match
+// the output.
+func (e *Enricher) Enrich(jsonAPI jsoniter.API, result *parsers.Result) error {
+	// Find the joins that apply before serializing the result, most log types have none
+	lookups := e.lookups(result.PantherLogType)
+	if len(lookups) == 0 {
+		return nil
+	}
+	// Serialize the result to look up fields by their JSON path, this includes panther fields
+	data, err := pantherlog.MarshalResult(jsonAPI, result)
+	if err != nil {
+		return err
+	}
+	for _, l := range lookups {
+		l.apply(result, data)
+	}
+	return nil
+}
+
+// lookup is a join with the table rows loaded when the lookup was created
+type lookup struct {
+	*join
+	rows *Table
+}
+
+// lookups returns the joins for a log type whose tables are loaded
+func (e *Enricher) lookups(logType string) []lookup {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	var lookups []lookup
+	for _, joins := range [][]*join{e.joins, e.joinsByLogType[logType]} {
+		for _, j := range joins {
+			if table := j.table.table; table != nil {
+				lookups = append(lookups, lookup{join: j, rows: table})
+			}
+		}
+	}
+	return lookups
+}
+
+// apply adds the row matching the join field of the serialized result to its p_enrichment.
+// A result holds a single row for each join, so only the first element of an array field that matches is used.
+func (l *lookup) apply(result *parsers.Result, data []byte) {
+	name := l.table.config.Name
+	value := gjson.GetBytes(data, l.field)
+	if !value.Exists() {
+		return
+	}
+	if !value.IsArray() {
+		if row, ok := l.rows.Lookup(value.String()); ok {
+			result.PantherEnrichment.Set(name, l.field, row)
+		}
+		return
+	}
+	value.ForEach(func(_, el gjson.Result) bool {
+		row, ok := l.rows.Lookup(el.String())
+		if ok {
+			result.PantherEnrichment.Set(name, l.field, row)
+		}
+		return !ok
+	})
+}
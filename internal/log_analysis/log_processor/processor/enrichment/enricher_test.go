@@ -0,0 +1,239 @@
+package enrichment
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog/null"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+type testEvent struct {
+	Time     time.Time   `json:"time" tcodec:"rfc3339" event_time:"true"`
+	SourceIP null.String `json:"sourceIP" panther:"ip"`
+	DestIP   null.String `json:"destIP" panther:"ip"`
+	User     null.String `json:"user"`
+}
+
+// countingAPI counts the results serialized by the enricher
+type countingAPI struct {
+	jsoniter.API
+	numMarshal int
+}
+
+func (api *countingAPI) Marshal(v interface{}) ([]byte, error) {
+	api.numMarshal++
+	return api.API.Marshal(v)
+}
+
+func TestEnricher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "enrichment")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assetsPath := filepath.Join(dir, "assets.csv")
+	require.NoError(t, ioutil.WriteFile(assetsPath, []byte("ip,owner\n10.0.0.1,alice\n10.0.0.2,bob\n"), 0600))
+
+	var users bytes.Buffer
+	gz := gzip.NewWriter(&users)
+	_, err = gz.Write([]byte(`{"name":"alice","team":"security"}`))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	s3Client := &testutils.S3Mock{}
+	s3Client.On("GetObjectWithContext", mock.Anything, &s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("users.jsonl.gz"),
+	}, mock.Anything).Return(&s3.GetObjectOutput{
+		Body: ioutil.NopCloser(bytes.NewReader(users.Bytes())),
+	}, nil).Once()
+
+	jsonAPI := &countingAPI{API: jsoniter.ConfigDefault}
+	enricher, err := New(&Config{
+		Tables: []TableConfig{
+			{Name: "assets", Location: assetsPath, Key: "ip", RefreshInterval: "1ns"},
+			{Name: "users", Location: "s3://bucket/users.jsonl.gz", Key: "name", RefreshInterval: "1h"},
+		},
+		Joins: []JoinConfig{
+			{Table: "assets", Field: "p_any_ip_addresses"},
+			{Table: "assets", Field: "destIP", LogTypes: []string{"Test.Event"}},
+			{Table: "users", Field: "user", LogTypes: []string{"Test.Event"}},
+		},
+	}, s3Client)
+	require.NoError(t, err)
+
+	b := pantherlog.ResultBuilder{}
+	result, err := b.BuildResult("Test.Event", &testEvent{
+		Time:     time.Now(),
+		SourceIP: null.FromString("192.168.1.1"),
+		DestIP:   null.FromString("10.0.0.2"),
+		User:     null.FromString("alice"),
+	})
+	require.NoError(t, err)
+
+	// Tables are not loaded yet, the result is not serialized
+	require.NoError(t, enricher.Enrich(jsonAPI, result))
+	require.Nil(t, result.PantherEnrichment)
+	require.Zero(t, jsonAPI.numMarshal)
+
+	require.NoError(t, enricher.Refresh(context.Background()))
+	// The S3 table is not reloaded before its refresh interval
+	require.NoError(t, enricher.Refresh(context.Background()))
+	s3Client.AssertExpectations(t)
+
+	require.NoError(t, enricher.Enrich(jsonAPI, result))
+	require.Equal(t, pantherlog.Enrichment{
+		"assets": {
+			"p_any_ip_addresses": {"ip": "10.0.0.2", "owner": "bob"},
+			"destIP":             {"ip": "10.0.0.2", "owner": "bob"},
+		},
+		"users": {
+			"user": {"name": "alice", "team": "security"},
+		},
+	}, result.PantherEnrichment)
+	require.Equal(t, 1, jsonAPI.numMarshal)
+
+	// Joins for other log types do not apply
+	result, err = b.BuildResult("Other.Event", &testEvent{
+		Time:   time.Now(),
+		DestIP: null.FromString("10.0.0.1"),
+		User:   null.FromString("alice"),
+	})
+	require.NoError(t, err)
+	require.NoError(t, enricher.Enrich(jsonAPI, result))
+	require.Equal(t, pantherlog.Enrichment{
+		"assets": {
+			"p_any_ip_addresses": {"ip": "10.0.0.1", "owner": "alice"},
+		},
+	}, result.PantherEnrichment)
+
+	// Tables that fail to reload keep their rows
+	require.NoError(t, os.Remove(assetsPath))
+	require.Error(t, enricher.Refresh(context.Background()))
+	result, err = b.BuildResult("Other.Event", &testEvent{
+		Time:     time.Now(),
+		SourceIP: null.FromString("10.0.0.1"),
+	})
+	require.NoError(t, err)
+	require.NoError(t, enricher.Enrich(jsonAPI, result))
+	require.Equal(t, "alice", result.PantherEnrichment.Row("assets", "p_any_ip_addresses")["owner"])
+
+	// Only the first matching element of an array is used
+	result, err = b.BuildResult("Other.Event", &testEvent{
+		Time:     time.Now(),
+		SourceIP: null.FromString("10.0.0.2"),
+		DestIP:   null.FromString("10.0.0.1"),
+	})
+	require.NoError(t, err)
+	require.NoError(t, enricher.Enrich(jsonAPI, result))
+	require.Equal(t, pantherlog.Enrichment{
+		"assets": {
+			"p_any_ip_addresses": {"ip": "10.0.0.1", "owner": "alice"},
+		},
+	}, result.PantherEnrichment)
+
+	// The JSON of raw results is not serialized again
+	numMarshal := jsonAPI.numMarshal
+	result = pantherlog.NewRawResult(result, []byte(`{"p_any_ip_addresses":["10.0.0.2"]}`))
+	require.NoError(t, enricher.Enrich(jsonAPI, result))
+	require.Equal(t, "bob", result.PantherEnrichment.Row("assets", "p_any_ip_addresses")["owner"])
+	require.Equal(t, numMarshal, jsonAPI.numMarshal)
+}
+
+// countryResolver resolves the country of ip addresses
+type countryResolver map[string]string
+
+func (r countryResolver) ResolveValues(values *pantherlog.ValueBuffer) {
+	for _, ip := range values.Get(pantherlog.FieldIPAddress) {
+		if country, ok := r[ip]; ok {
+			values.WriteValues(pantherlog.FieldIPCountry, country)
+		}
+	}
+}
+
+func TestEnricherResolvedFields(t *testing.T) {
+	dir, err := ioutil.TempDir("", "enrichment")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	countriesPath := filepath.Join(dir, "countries.csv")
+	require.NoError(t, ioutil.WriteFile(countriesPath, []byte("code,name\nGR,Greece\n"), 0600))
+
+	enricher, err := New(&Config{
+		Tables: []TableConfig{
+			{Name: "countries", Location: countriesPath, Key: "code"},
+		},
+		Joins: []JoinConfig{
+			{Table: "countries", Field: "p_any_ip_countries"},
+		},
+	}, nil)
+	require.NoError(t, err)
+	require.NoError(t, enricher.Refresh(context.Background()))
+
+	b := pantherlog.ResultBuilder{}
+	result, err := b.BuildResult("Test.Event", &testEvent{
+		Time:     time.Now(),
+		SourceIP: null.FromString("2.2.2.2"),
+	})
+	require.NoError(t, err)
+	// Fields added by the destination JSON API are not available to other APIs
+	require.NoError(t, enricher.Enrich(jsoniter.ConfigDefault, result))
+	require.Nil(t, result.PantherEnrichment)
+
+	jsonAPI := jsoniter.Config{}.Froze()
+	jsonAPI.RegisterExtension(pantherlog.NewValueResolverExtension(countryResolver{"2.2.2.2": "GR"}))
+	require.NoError(t, enricher.Enrich(jsonAPI, result))
+	require.Equal(t, pantherlog.Enrichment{
+		"countries": {
+			"p_any_ip_countries": {"code": "GR", "name": "Greece"},
+		},
+	}, result.PantherEnrichment)
+}
+
+func TestConfigValidate(t *testing.T) {
+	for _, config := range []*Config{
+		{Tables: []TableConfig{{Name: "t", Location: "t.csv"}}},
+		{Tables: []TableConfig{{Name: "t", Location: "t.txt", Key: "id"}}},
+		{Tables: []TableConfig{{Name: "t", Location: "t.csv", Key: "id", Format: "xml"}}},
+		{Tables: []TableConfig{{Name: "t", Location: "t.csv", Key: "id", RefreshInterval: "soon"}}},
+		{Tables: []TableConfig{{Name: "t", Location: "s3://", Key: "id"}}},
+		{Tables: []TableConfig{{Name: "t", Location: "t.csv", Key: "id"}, {Name: "t", Location: "t.csv", Key: "id"}}},
+		{Joins: []JoinConfig{{Table: "t", Field: "id"}}},
+		{Tables: []TableConfig{{Name: "t", Location: "t.csv", Key: "id"}}, Joins: []JoinConfig{{Table: "t"}}},
+	} {
+		require.Error(t, config.Validate())
+	}
+	config := &Config{
+		Tables: []TableConfig{{Name: "t", Location: "data", Key: "id", Format: "jsonl", RefreshInterval: "5m"}},
+		Joins:  []JoinConfig{{Table: "t", Field: "id"}},
+	}
+	require.NoError(t, config.Validate())
+}
@@ -0,0 +1,216 @@
+package enrichment
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// Row is a row of a lookup table
+type Row = map[string]string
+
+// Table is a lookup table indexed by its key column
+type Table struct {
+	rows       map[string]Row
+	ignoreCase bool
+}
+
+// Len returns the number of rows in the table
+func (t *Table) Len() int {
+	return len(t.rows)
+}
+
+// Lookup finds the row matching a key
+func (t *Table) Lookup(key string) (Row, bool) {
+	if t.ignoreCase {
+		key = strings.ToLower(key)
+	}
+	row, ok := t.rows[key]
+	return row, ok
+}
+
+// ReadTable reads a lookup table in CSV or JSONL format.
+// It fails if the table has more rows than maxRows or if the data exceed maxBytes.
+func ReadTable(r io.Reader, config *TableConfig) (*Table, error) {
+	format, err := config.format()
+	if err != nil {
+		return nil, err
+	}
+	maxBytes := config.maxBytes()
+	// Read one more byte to detect oversized tables
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read table %q", config.Name)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, errors.Errorf("table %q exceeds the size limit of %d bytes", config.Name, maxBytes)
+	}
+	t := &Table{
+		rows:       make(map[string]Row),
+		ignoreCase: config.IgnoreCase,
+	}
+	add := func(row Row) error {
+		key, ok := row[config.Key]
+		if !ok || key == "" {
+			// Rows without a key cannot be joined
+			return nil
+		}
+		if t.ignoreCase {
+			key = strings.ToLower(key)
+		}
+		t.rows[key] = row
+		if len(t.rows) > config.maxRows() {
+			return errors.Errorf("table %q exceeds the limit of %d rows", config.Name, config.maxRows())
+		}
+		return nil
+	}
+	switch format {
+	case FormatCSV:
+		err = readCSV(bytes.NewReader(data), add)
+	case FormatJSONL:
+		err = readJSONL(bytes.NewReader(data), add)
+	}
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to read table %q", config.Name)
+	}
+	return t, nil
+}
+
+func readCSV(r io.Reader, add func(row Row) error) error {
+	rd := csv.NewReader(r)
+	rd.ReuseRecord = true
+	header, err := rd.Read()
+	if err != nil {
+		return errors.Wrap(err, "failed to read CSV header")
+	}
+	// The record is reused so we need to copy the header
+	columns := append([]string(nil), header...)
+	for {
+		record, err := rd.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "invalid CSV row")
+		}
+		row := make(Row, len(columns))
+		for i, value := range record {
+			if i < len(columns) && value != "" {
+				row[columns[i]] = value
+			}
+		}
+		if err := add(row); err != nil {
+			return err
+		}
+	}
+}
+
+func readJSONL(r io.Reader, add func(row Row) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var obj map[string]jsoniter.RawMessage
+		if err := jsoniter.Unmarshal(line, &obj); err != nil {
+			return errors.Wrap(err, "invalid JSON row")
+		}
+		row := make(Row, len(obj))
+		for name, value := range obj {
+			iter := jsoniter.ConfigDefault.BorrowIterator(value)
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+			case jsoniter.StringValue:
+				row[name] = iter.ReadString()
+			default:
+				// Keep the JSON text of numbers, booleans, arrays and objects
+				row[name] = string(value)
+			}
+			jsoniter.ConfigDefault.ReturnIterator(iter)
+		}
+		if err := add(row); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(scanner.Err(), "failed to read JSON rows")
+}
+
+// OpenLocation opens a file on S3 (s3://bucket/key) or on the local disk.
+// Files ending in .gz are decompressed.
+func OpenLocation(ctx context.Context, s3Client s3iface.S3API, location string) (io.ReadCloser, error) {
+	bucket, key, err := parseLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	var r io.ReadCloser
+	if bucket == "" {
+		f, err := os.Open(key)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open table file")
+		}
+		r = f
+	} else {
+		if s3Client == nil {
+			return nil, errors.Errorf("cannot read %s without an S3 client", location)
+		}
+		out, err := s3Client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get %s", location)
+		}
+		r = out.Body
+	}
+	if !strings.HasSuffix(location, ".gz") {
+		return r, nil
+	}
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		_ = r.Close()
+		return nil, errors.Wrapf(err, "failed to read %s", location)
+	}
+	return &gzipReadCloser{Reader: gz, closer: r}, nil
+}
+
+type gzipReadCloser struct {
+	*gzip.Reader
+	closer io.Closer
+}
+
+func (r *gzipReadCloser) Close() error {
+	_ = r.Reader.Close()
+	return r.closer.Close()
+}
@@ -0,0 +1,101 @@
+package enrichment
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTableCSV(t *testing.T) {
+	const data = `ip,owner,env
+10.0.0.1,alice,prod
+10.0.0.2,bob,
+,nobody,dev
+`
+	table, err := ReadTable(strings.NewReader(data), &TableConfig{
+		Name:     "assets",
+		Location: "assets.csv",
+		Key:      "ip",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, table.Len())
+	row, ok := table.Lookup("10.0.0.1")
+	require.True(t, ok)
+	require.Equal(t, Row{"ip": "10.0.0.1", "owner": "alice", "env": "prod"}, row)
+	row, ok = table.Lookup("10.0.0.2")
+	require.True(t, ok)
+	require.Equal(t, Row{"ip": "10.0.0.2", "owner": "bob"}, row)
+	_, ok = table.Lookup("10.0.0.3")
+	require.False(t, ok)
+}
+
+func TestReadTableJSONL(t *testing.T) {
+	const data = `{"user":"Alice","team":"security","admin":true,"groups":["a","b"],"manager":null}
+
+{"user":"bob","team":"eng","level":3}
+`
+	table, err := ReadTable(strings.NewReader(data), &TableConfig{
+		Name:       "users",
+		Location:   "s3://bucket/users.jsonl.gz",
+		Key:        "user",
+		IgnoreCase: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, table.Len())
+	row, ok := table.Lookup("ALICE")
+	require.True(t, ok)
+	require.Equal(t, Row{"user": "Alice", "team": "security", "admin": "true", "groups": `["a","b"]`}, row)
+	row, ok = table.Lookup("bob")
+	require.True(t, ok)
+	require.Equal(t, "3", row["level"])
+}
+
+func TestReadTableLimits(t *testing.T) {
+	const data = `id,name
+1,foo
+2,bar
+`
+	_, err := ReadTable(strings.NewReader(data), &TableConfig{
+		Name:     "ids",
+		Location: "ids.csv",
+		Key:      "id",
+		MaxRows:  1,
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "limit of 1 rows")
+
+	_, err = ReadTable(strings.NewReader(data), &TableConfig{
+		Name:     "ids",
+		Location: "ids.csv",
+		Key:      "id",
+		MaxBytes: 10,
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "size limit")
+
+	_, err = ReadTable(strings.NewReader(`{"id":`), &TableConfig{
+		Name:     "ids",
+		Location: "ids.jsonl",
+		Key:      "id",
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,251 @@
+package processor
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+// indicator fields collect the values of event fields (i.e. p_any_ip_addresses)
+const indicatorPrefixJSON = pantherlog.FieldPrefixJSON + "any_"
+
+// eventFilter is the compiled form of the event filters of a source for a log type
+type eventFilter struct {
+	drops   []dropFilter
+	exclude []string
+}
+
+type dropFilter struct {
+	field  string
+	values []string
+	regex  *regexp.Regexp
+}
+
+func newEventFilter(config *models.LogTypeEventFilters) (*eventFilter, error) {
+	f := eventFilter{
+		exclude: config.ExcludeFields,
+	}
+	for _, drop := range config.DropFilters {
+		d := dropFilter{
+			field:  drop.Field,
+			values: drop.Values,
+		}
+		if drop.Regex != "" {
+			re, err := regexp.Compile(drop.Regex)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid drop filter regex for field %q", drop.Field)
+			}
+			d.regex = re
+		}
+		f.drops = append(f.drops, d)
+	}
+	return &f, nil
+}
+
+// Apply returns the event JSON with excluded fields removed or false if the event should be dropped.
+// The values of excluded fields are removed from the indicator fields too.
+func (f *eventFilter) Apply(data []byte) ([]byte, bool) {
+	for i := range f.drops {
+		if f.drops[i].Match(data) {
+			return nil, false
+		}
+	}
+	excluded := map[string]bool{}
+	for _, field := range f.exclude {
+		value := gjson.GetBytes(data, field)
+		if !value.Exists() {
+			continue
+		}
+		if out, err := sjson.DeleteBytes(data, field); err == nil {
+			data = out
+			collectValues(excluded, value)
+		}
+	}
+	if len(excluded) == 0 {
+		return data, true
+	}
+	return removeIndicatorValues(data, excluded), true
+}
+
+// removeIndicatorValues removes values from the indicator fields (p_any_*) of the event JSON.
+// Values that are also found in fields of the event that were kept are not removed.
+func removeIndicatorValues(data []byte, values map[string]bool) []byte {
+	event := gjson.ParseBytes(data)
+	kept := map[string]bool{}
+	event.ForEach(func(key, value gjson.Result) bool {
+		if !strings.HasPrefix(key.String(), pantherlog.FieldPrefixJSON) {
+			collectValues(kept, value)
+		}
+		return true
+	})
+	event.ForEach(func(key, value gjson.Result) bool {
+		name := key.String()
+		if !strings.HasPrefix(name, indicatorPrefixJSON) || !value.IsArray() {
+			return true
+		}
+		var indicators []string
+		removed := false
+		for _, el := range value.Array() {
+			if v := el.String(); values[v] && !kept[v] {
+				removed = true
+				continue
+			}
+			indicators = append(indicators, el.String())
+		}
+		if !removed {
+			return true
+		}
+		var out []byte
+		var err error
+		if len(indicators) == 0 {
+			out, err = sjson.DeleteBytes(data, name)
+		} else {
+			out, err = sjson.SetBytes(data, name, indicators)
+		}
+		if err == nil {
+			data = out
+		}
+		return true
+	})
+	return data
+}
+
+// collectValues adds all scalar values of a JSON value to values
+func collectValues(values map[string]bool, value gjson.Result) {
+	if value.IsArray() || value.IsObject() {
+		value.ForEach(func(_, el gjson.Result) bool {
+			collectValues(values, el)
+			return true
+		})
+		return
+	}
+	if value.Type != gjson.Null {
+		values[value.String()] = true
+	}
+}
+
+// Match checks if the value of the filter field matches.
+// Array values match if any of their elements matches.
+func (d *dropFilter) Match(data []byte) bool {
+	value := gjson.GetBytes(data, d.field)
+	if !value.Exists() {
+		return false
+	}
+	if !value.IsArray() {
+		return d.matchValue(value.String())
+	}
+	matched := false
+	value.ForEach(func(_, el gjson.Result) bool {
+		matched = d.matchValue(el.String())
+		return !matched
+	})
+	return matched
+}
+
+func (d *dropFilter) matchValue(value string) bool {
+	for _, v := range d.values {
+		if v == value {
+			return true
+		}
+	}
+	return d.regex != nil && d.regex.MatchString(value)
+}
+
+// filterEvent applies the event filters of the event source.
+// It returns the event to send (possibly with fields removed) or nil if the event was dropped.
+func (p *Processor) filterEvent(event *parsers.Result) (*parsers.Result, error) {
+	filter, err := p.eventFilterFor(event.PantherSourceID, event.PantherLogType)
+	if err != nil || filter == nil {
+		return event, err
+	}
+	// Serializing the event updates p_event_time and collects indicators so the JSON is complete
+	data, err := pantherlog.MarshalResult(p.filterAPI, event)
+	if err != nil {
+		return event, err
+	}
+	data, keep := filter.Apply(data)
+	if !keep {
+		p.countDropped(event.PantherLogType)
+		return nil, nil
+	}
+	// The JSON is passed on so that later stages do not need to serialize the event again
+	return pantherlog.NewRawResult(event, data), nil
+}
+
+// eventFilterFor returns the event filter of a source for a log type.
+// Filters are compiled once per source and cached for the lifetime of the processor.
+func (p *Processor) eventFilterFor(sourceID, logType string) (*eventFilter, error) {
+	filters, ok := p.eventFilters[sourceID]
+	if !ok {
+		var err error
+		filters, err = p.buildEventFilters(sourceID)
+		if p.eventFilters == nil {
+			p.eventFilters = map[string]map[string]*eventFilter{}
+		}
+		// Cache failures too, so that an invalid configuration does not get reported for every event
+		p.eventFilters[sourceID] = filters
+		if err != nil {
+			return nil, err
+		}
+	}
+	return filters[logType], nil
+}
+
+func (p *Processor) buildEventFilters(sourceID string) (map[string]*eventFilter, error) {
+	src := p.input.Source
+	// SQS sources receive events of multiple sources
+	if src == nil || src.IntegrationID != sourceID {
+		if p.loadSource == nil {
+			return nil, nil
+		}
+		var err error
+		if src, err = p.loadSource(sourceID); err != nil {
+			return nil, errors.WithMessagef(err, "failed to load event filters of source %q", sourceID)
+		}
+	}
+	if len(src.EventFilters) == 0 {
+		return nil, nil
+	}
+	filters := make(map[string]*eventFilter, len(src.EventFilters))
+	for i := range src.EventFilters {
+		config := &src.EventFilters[i]
+		f, err := newEventFilter(config)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid %s event filters of source %q", config.LogType, sourceID)
+		}
+		filters[config.LogType] = f
+	}
+	return filters, nil
+}
+
+func (p *Processor) countDropped(logType string) {
+	if p.droppedEvents == nil {
+		p.droppedEvents = map[string]uint64{}
+	}
+	p.droppedEvents[logType]++
+}
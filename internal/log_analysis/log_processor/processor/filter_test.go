@@ -0,0 +1,125 @@
+package processor
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+func TestEventFilter(t *testing.T) {
+	f, err := newEventFilter(&models.LogTypeEventFilters{
+		LogType: "Foo",
+		DropFilters: []models.DropFilter{
+			{Field: "action", Values: []string{"ACCEPT"}},
+			{Field: "tags", Regex: "^health"},
+		},
+		ExcludeFields: []string{"request.headers", "missing"},
+	})
+	require.NoError(t, err)
+
+	_, keep := f.Apply([]byte(`{"action":"ACCEPT"}`))
+	require.False(t, keep)
+	_, keep = f.Apply([]byte(`{"action":"REJECT","tags":["foo","healthcheck"]}`))
+	require.False(t, keep)
+
+	data, keep := f.Apply([]byte(`{"action":"REJECT","tags":["foo"],"request":{"method":"GET","headers":{"a":"b"}}}`))
+	require.True(t, keep)
+	require.JSONEq(t, `{"action":"REJECT","tags":["foo"],"request":{"method":"GET"}}`, string(data))
+
+	// Values of excluded fields are removed from indicator fields unless kept in other fields
+	data, keep = f.Apply([]byte(`{"client":"10.0.0.2","request":{"headers":{"x-forwarded-for":["10.0.0.1","10.0.0.2"]}},` +
+		`"p_any_ip_addresses":["10.0.0.1","10.0.0.2"]}`))
+	require.True(t, keep)
+	require.JSONEq(t, `{"client":"10.0.0.2","request":{},"p_any_ip_addresses":["10.0.0.2"]}`, string(data))
+	data, keep = f.Apply([]byte(`{"request":{"headers":{"host":"example.com"}},"p_any_domain_names":["example.com"]}`))
+	require.True(t, keep)
+	require.JSONEq(t, `{"request":{}}`, string(data))
+}
+
+func TestProcessorFilterEvent(t *testing.T) {
+	type event struct {
+		Action  string `json:"action"`
+		Comment string `json:"comment" panther:"email"`
+	}
+	builder := pantherlog.ResultBuilder{
+		NextRowID: func() string { return "id" },
+		Now: func() time.Time {
+			return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		},
+	}
+	newResult := func(sourceID, action string) *parsers.Result {
+		result, err := builder.BuildResult("Foo", &event{Action: action, Comment: "foo@example.com"})
+		require.NoError(t, err)
+		result.PantherSourceID = sourceID
+		return result
+	}
+	otherSource := &models.SourceIntegration{
+		SourceIntegrationMetadata: models.SourceIntegrationMetadata{
+			IntegrationID: "other",
+			EventFilters: models.EventFilters{
+				{LogType: "Foo", ExcludeFields: []string{"comment"}},
+			},
+		},
+	}
+	dataStream := makeDataStream()
+	dataStream.Source.EventFilters = models.EventFilters{
+		{LogType: "Foo", DropFilters: []models.DropFilter{{Field: "action", Values: []string{"ACCEPT"}}}},
+	}
+	p := &Processor{
+		input:     dataStream,
+		filterAPI: jsoniter.ConfigCompatibleWithStandardLibrary,
+		loadSource: func(id string) (*models.SourceIntegration, error) {
+			require.Equal(t, "other", id)
+			return otherSource, nil
+		},
+		classifier: &classification.Classifier{},
+	}
+
+	result, err := p.filterEvent(newResult(testSourceID, "ACCEPT"))
+	require.NoError(t, err)
+	require.Nil(t, result)
+	input := newResult(testSourceID, "REJECT")
+	result, err = p.filterEvent(input)
+	require.NoError(t, err)
+	// Kept events carry their JSON to the next stages
+	require.Contains(t, string(result.RawJSON()), `"action":"REJECT"`)
+	require.Equal(t, input.CoreFields, result.CoreFields)
+
+	result, err = p.filterEvent(newResult("other", "ACCEPT"))
+	require.NoError(t, err)
+	require.True(t, result.EventIncludesPantherFields)
+	data, err := jsoniter.Marshal(result)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"action":"ACCEPT"`)
+	require.NotContains(t, string(data), `"comment"`)
+	// Excluded values do not leak into indicator fields
+	require.NotContains(t, string(data), `foo@example.com`)
+	require.Contains(t, string(data), `"p_source_id":"other"`)
+
+	require.Equal(t, map[string]uint64{"Foo": 1}, p.droppedEvents)
+}
@@ -0,0 +1,62 @@
+package logstream
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"io"
+	"regexp"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// DefaultMaxEntrySize is the default max size of a single log entry for multi-line framings
+const DefaultMaxEntrySize = 10 * 1024 * 1024
+
+// NewFramedStream creates a log entry stream that splits the data read from r according to the framing configuration.
+// A nil configuration reads one log entry per line.
+func NewFramedStream(c *pantherlog.FramingConfig, r io.Reader, size int) (Stream, error) {
+	if c == nil {
+		return NewLineStream(r, size), nil
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	maxSize := c.MaxEntrySize
+	if maxSize == 0 {
+		maxSize = DefaultMaxEntrySize
+	}
+	switch c.Mode {
+	case "", pantherlog.FramingLine:
+		return NewLineStream(r, size), nil
+	case pantherlog.FramingJSON:
+		return NewJSONStream(r, size), nil
+	case pantherlog.FramingRegex:
+		// Validate already checked that the pattern compiles
+		start := regexp.MustCompile(c.StartPattern)
+		return NewRecordStream(r, size, start, maxSize), nil
+	case pantherlog.FramingLength:
+		return NewLengthPrefixedStream(r, size, c.LengthPrefix, maxSize), nil
+	case pantherlog.FramingZeek:
+		return NewZeekStream(r, size), nil
+	default:
+		return nil, errors.Errorf("invalid framing mode %q", c.Mode)
+	}
+}
@@ -0,0 +1,186 @@
+package logstream
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+func TestFraming(t *testing.T) {
+	type testCase struct {
+		Name    string
+		Config  *pantherlog.FramingConfig
+		Input   string
+		Expect  []string
+		WantErr bool
+	}
+	for _, tc := range []testCase{
+		{
+			Name:   "Default",
+			Input:  "foo\nbar\n",
+			Expect: []string{"foo", "bar"},
+		},
+		{
+			Name:   "JSON pretty printed",
+			Config: &pantherlog.FramingConfig{Mode: pantherlog.FramingJSON},
+			Input:  "{\n  \"foo\": 1\n}\n{\n  \"foo\": 2\n}\n",
+			Expect: []string{"{\n  \"foo\": 1\n}", "{\n  \"foo\": 2\n}"},
+		},
+		{
+			Name:   "JSON concatenated",
+			Config: &pantherlog.FramingConfig{Mode: pantherlog.FramingJSON},
+			Input:  `{"foo":1}{"foo":2} {"foo":3}`,
+			Expect: []string{`{"foo":1}`, `{"foo":2}`, `{"foo":3}`},
+		},
+		{
+			Name:   "JSON top-level array",
+			Config: &pantherlog.FramingConfig{Mode: pantherlog.FramingJSON},
+			Input:  "[\n{\"foo\":1},\n{\"foo\":2}\n]\n[]\n{\"foo\":3}",
+			Expect: []string{`{"foo":1}`, `{"foo":2}`, `{"foo":3}`},
+		},
+		{
+			Name:    "JSON truncated",
+			Config:  &pantherlog.FramingConfig{Mode: pantherlog.FramingJSON},
+			Input:   `{"foo":1} {"foo":`,
+			Expect:  []string{`{"foo":1}`},
+			WantErr: true,
+		},
+		{
+			Name: "Regex",
+			Config: &pantherlog.FramingConfig{
+				Mode:         pantherlog.FramingRegex,
+				StartPattern: `^\d{4}-\d{2}-\d{2} `,
+			},
+			Input: "2020-01-01 ERROR failed\njava.lang.Exception: foo\n\tat Foo.bar(Foo.java:42)\n2020-01-01 INFO ok\n",
+			Expect: []string{
+				"2020-01-01 ERROR failed\njava.lang.Exception: foo\n\tat Foo.bar(Foo.java:42)",
+				"2020-01-01 INFO ok",
+			},
+		},
+		{
+			Name: "Regex max size",
+			Config: &pantherlog.FramingConfig{
+				Mode:         pantherlog.FramingRegex,
+				StartPattern: `^START`,
+				MaxEntrySize: 12,
+			},
+			Input:  "START foo\nbar\nbaz\nSTART qux",
+			Expect: []string{"START foo", "bar\nbaz", "START qux"},
+		},
+		{
+			Name:   "Length decimal",
+			Config: &pantherlog.FramingConfig{Mode: pantherlog.FramingLength},
+			Input:  "11 foo bar baz\n8 foo\nbar\n",
+			Expect: []string{"foo bar baz", "foo\nbar\n"},
+		},
+		{
+			Name:    "Length decimal truncated",
+			Config:  &pantherlog.FramingConfig{Mode: pantherlog.FramingLength},
+			Input:   "11 foo bar baz 11 foo",
+			Expect:  []string{"foo bar baz"},
+			WantErr: true,
+		},
+		{
+			Name: "Length uint32",
+			Config: &pantherlog.FramingConfig{
+				Mode:         pantherlog.FramingLength,
+				LengthPrefix: pantherlog.LengthPrefixUint32,
+			},
+			Input:  string(uint32Frame("foo\nbar")) + string(uint32Frame("baz")),
+			Expect: []string{"foo\nbar", "baz"},
+		},
+		{
+			Name:   "Zeek TSV",
+			Config: &pantherlog.FramingConfig{Mode: pantherlog.FramingZeek},
+			Input: strings.Join([]string{
+				`#separator \x09`,
+				"#set_separator\t,",
+				"#empty_field\t(empty)",
+				"#unset_field\t-",
+				"#path\tdns",
+				"#open\t2020-01-01-00-00-00",
+				"#fields\tts\tquery\tAA\tanswers\tTTLs\trcode",
+				"#types\ttime\tstring\tbool\tvector[string]\tvector[interval]\tcount",
+				"1541001600.580233\tfoo\\x09bar.com\tT\ta.com,b.com\t60.000000,30.000000\t-",
+				"1541001600.580233\t(empty)\tF\t(empty)\t-\t0",
+				"#close\t2020-01-01-01-00-00",
+				`{"ts":1541001600.580233}`,
+			}, "\n"),
+			Expect: []string{
+				`{"_path":"dns","ts":1541001600.580233,"query":"foo\tbar.com","AA":true,"answers":["a.com","b.com"],"TTLs":[60.000000,30.000000]}`,
+				`{"_path":"dns","ts":1541001600.580233,"query":"","AA":false,"answers":[],"rcode":0}`,
+				`{"ts":1541001600.580233}`,
+			},
+		},
+		{
+			Name:   "Zeek TSV field mismatch",
+			Config: &pantherlog.FramingConfig{Mode: pantherlog.FramingZeek},
+			Input:  "#fields\tts\tuid\n1541001600.580233\n",
+			Expect: []string{"1541001600.580233"},
+		},
+		{
+			Name: "Length too large",
+			Config: &pantherlog.FramingConfig{
+				Mode:         pantherlog.FramingLength,
+				MaxEntrySize: 2,
+			},
+			Input:   "3 foo",
+			WantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			s, err := NewFramedStream(tc.Config, strings.NewReader(tc.Input), 16)
+			require.NoError(t, err)
+			var result []string
+			for {
+				entry := s.Next()
+				if entry == nil {
+					break
+				}
+				result = append(result, string(entry))
+			}
+			require.Equal(t, tc.Expect, result)
+			if tc.WantErr {
+				require.Error(t, s.Err())
+				return
+			}
+			require.NoError(t, s.Err())
+		})
+	}
+}
+
+func TestNewFramedStreamInvalid(t *testing.T) {
+	_, err := NewFramedStream(&pantherlog.FramingConfig{Mode: "foo"}, strings.NewReader(""), 0)
+	require.Error(t, err)
+}
+
+func uint32Frame(data string) []byte {
+	buf := bytes.Buffer{}
+	_ = binary.Write(&buf, binary.BigEndian, uint32(len(data)))
+	buf.WriteString(data)
+	return buf.Bytes()
+}
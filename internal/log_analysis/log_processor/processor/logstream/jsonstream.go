@@ -0,0 +1,116 @@
+package logstream
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// NewJSONStream creates a new stream of JSON values.
+// r is the underlying io.Reader
+// size is the read buffer size for the jsoniter.Iterator
+func NewJSONStream(r io.Reader, size int) *JSONStream {
+	if size <= 0 {
+		size = DefaultBufferSize
+	} else if size < MinBufferSize {
+		size = MinBufferSize
+	}
+	return &JSONStream{
+		iter: jsoniter.Parse(jsoniter.ConfigDefault, r, size),
+	}
+}
+
+// JSONStream is a log entry stream that reads concatenated JSON values regardless of whitespace between them.
+// This allows reading pretty-printed JSON documents that span multiple lines.
+// The elements of a top-level JSON array are read as separate entries.
+type JSONStream struct {
+	iter       *jsoniter.Iterator
+	err        error
+	entry      []byte
+	inArray    bool
+	numEntries int64
+}
+
+// Err implements the Stream interface
+func (s *JSONStream) Err() error {
+	if errors.Is(s.err, io.EOF) {
+		return nil
+	}
+	return s.err
+}
+
+// Next implements the Stream interface
+func (s *JSONStream) Next() []byte {
+	for s.err == nil {
+		if s.inArray {
+			// Read the next element of a top-level array
+			if s.iter.ReadArray() {
+				return s.readEntry()
+			}
+			if err := s.iter.Error; err != nil {
+				s.err = errors.WithStack(err)
+				return nil
+			}
+			s.inArray = false
+			continue
+		}
+		// WhatIsNext skips any whitespace before the value
+		switch s.iter.WhatIsNext() {
+		case jsoniter.InvalidValue:
+			if err := s.iter.Error; err != nil {
+				// io.EOF is reported here once all values have been read
+				s.err = errors.WithStack(err)
+				return nil
+			}
+			s.iter.ReportError("JSONStream", "invalid JSON value")
+			s.err = errors.WithStack(s.iter.Error)
+			return nil
+		case jsoniter.ArrayValue:
+			// ReadArray will read the first element on the next iteration
+			s.inArray = true
+		default:
+			return s.readEntry()
+		}
+	}
+	return nil
+}
+
+func (s *JSONStream) readEntry() []byte {
+	// Initialize the entry buffer
+	if s.entry == nil {
+		s.entry = make([]byte, MinBufferSize)
+	}
+	// Skip any whitespace before the value so it is not included in the entry
+	s.iter.WhatIsNext()
+	s.entry = s.iter.SkipAndAppendBytes(s.entry[:0])
+	if err := s.iter.Error; err != nil {
+		// An io.EOF here means the value was truncated
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		s.err = errors.WithStack(err)
+		return nil
+	}
+	s.numEntries++
+	// Return the entry data. It is valid until the next call to Next
+	return s.entry
+}
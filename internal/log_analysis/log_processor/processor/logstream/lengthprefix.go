@@ -0,0 +1,147 @@
+package logstream
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// NewLengthPrefixedStream creates a stream of length-prefixed records.
+// format is the format of the length prefix (pantherlog.LengthPrefixDecimal or pantherlog.LengthPrefixUint32).
+// Records larger than maxSize bytes cause the stream to fail as there is no way to recover the framing.
+func NewLengthPrefixedStream(r io.Reader, size int, format string, maxSize int) *LengthPrefixedStream {
+	if size <= 0 {
+		size = DefaultBufferSize
+	} else if size < MinBufferSize {
+		size = MinBufferSize
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultMaxEntrySize
+	}
+	if format == "" {
+		format = pantherlog.LengthPrefixDecimal
+	}
+	return &LengthPrefixedStream{
+		r:       bufio.NewReaderSize(r, size),
+		format:  format,
+		maxSize: maxSize,
+	}
+}
+
+// LengthPrefixedStream is a log entry stream for records prefixed by their length in bytes.
+// This is the octet-counting framing used by syslog over TCP and by many binary log shippers.
+type LengthPrefixedStream struct {
+	r       *bufio.Reader
+	format  string
+	maxSize int
+	entry   []byte
+	err     error
+}
+
+// Err implements the Stream interface
+func (s *LengthPrefixedStream) Err() error {
+	if errors.Is(s.err, io.EOF) {
+		return nil
+	}
+	return s.err
+}
+
+// Next implements the Stream interface
+func (s *LengthPrefixedStream) Next() []byte {
+	if s.err != nil {
+		return nil
+	}
+	n, err := s.readLength()
+	if err != nil {
+		s.err = err
+		return nil
+	}
+	if n > s.maxSize {
+		s.err = errors.Errorf("record size %d exceeds max entry size %d", n, s.maxSize)
+		return nil
+	}
+	if cap(s.entry) < n {
+		s.entry = make([]byte, n)
+	}
+	s.entry = s.entry[:n]
+	if _, err := io.ReadFull(s.r, s.entry); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		s.err = errors.WithStack(err)
+		return nil
+	}
+	return s.entry
+}
+
+func (s *LengthPrefixedStream) readLength() (int, error) {
+	switch s.format {
+	case pantherlog.LengthPrefixUint32:
+		var buf [4]byte
+		if _, err := io.ReadFull(s.r, buf[:]); err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				return 0, errors.WithStack(err)
+			}
+			// A clean io.EOF means there are no more records
+			return 0, err
+		}
+		n := binary.BigEndian.Uint32(buf[:])
+		if uint64(n) > uint64(s.maxSize) {
+			return 0, errors.Errorf("record size %d exceeds max entry size %d", n, s.maxSize)
+		}
+		return int(n), nil
+	case pantherlog.LengthPrefixDecimal:
+		// Skip any whitespace (ie trailing newlines) between records
+		for {
+			c, err := s.r.ReadByte()
+			if err != nil {
+				return 0, err
+			}
+			switch c {
+			case ' ', '\t', '\r', '\n':
+				continue
+			}
+			if err := s.r.UnreadByte(); err != nil {
+				return 0, errors.WithStack(err)
+			}
+			break
+		}
+		digits, err := s.r.ReadSlice(' ')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, errors.Wrap(err, "failed to read record length")
+		}
+		n, err := strconv.ParseUint(string(digits[:len(digits)-1]), 10, 31)
+		if err != nil {
+			return 0, errors.Wrap(err, "invalid record length")
+		}
+		return int(n), nil
+	default:
+		return 0, errors.Errorf("invalid length prefix format %q", s.format)
+	}
+}
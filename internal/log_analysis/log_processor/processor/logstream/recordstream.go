@@ -0,0 +1,84 @@
+package logstream
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"io"
+	"regexp"
+)
+
+// NewRecordStream creates a stream of multi-line records.
+// Each record starts at a line matching start and spans all lines until the next match.
+// Records larger than maxSize bytes are split at line boundaries.
+func NewRecordStream(r io.Reader, size int, start *regexp.Regexp, maxSize int) *RecordStream {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxEntrySize
+	}
+	return &RecordStream{
+		lines:   NewLineStream(r, size),
+		start:   start,
+		maxSize: maxSize,
+	}
+}
+
+// RecordStream is a log entry stream for multi-line log records (ie Java stack traces, multi-line syslog messages).
+type RecordStream struct {
+	lines   *LineStream
+	start   *regexp.Regexp
+	maxSize int
+	record  []byte
+	// next holds the first line of the next record
+	next    []byte
+	hasNext bool
+}
+
+// Err implements the Stream interface
+func (s *RecordStream) Err() error {
+	return s.lines.Err()
+}
+
+// Next implements the Stream interface
+func (s *RecordStream) Next() []byte {
+	s.record = s.record[:0]
+	if s.hasNext {
+		s.record = append(s.record, s.next...)
+		s.hasNext = false
+	} else {
+		line := s.lines.Next()
+		if line == nil {
+			return nil
+		}
+		s.record = append(s.record, line...)
+	}
+	for {
+		line := s.lines.Next()
+		if line == nil {
+			break
+		}
+		if s.start.Match(line) || len(s.record)+1+len(line) > s.maxSize {
+			// Lines returned by LineStream are only valid until the next call so we need to copy
+			s.next = append(s.next[:0], line...)
+			s.hasNext = true
+			break
+		}
+		s.record = append(s.record, '\n')
+		s.record = append(s.record, line...)
+	}
+	return s.record
+}
@@ -0,0 +1,231 @@
+package logstream
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// NewZeekStream creates a new stream of Zeek log entries.
+// r is the underlying io.Reader
+// size is the read buffer size for the underlying LineStream
+func NewZeekStream(r io.Reader, size int) *ZeekStream {
+	return &ZeekStream{
+		lines:  NewLineStream(r, size),
+		header: defaultZeekHeader(),
+		stream: jsoniter.NewStream(jsoniter.ConfigDefault, nil, 4096),
+	}
+}
+
+// ZeekStream reads Zeek logs written either as JSON or in the native TSV format.
+// JSON lines are read as is.
+// TSV records are converted to JSON objects using the `#fields` and `#types` header lines of the log,
+// container values (`set[...]`, `vector[...]`) are converted to arrays and unset fields are omitted.
+// The name of the log from the `#path` header line is set to the `_path` field, as in Zeek's JSON streaming output.
+type ZeekStream struct {
+	lines  *LineStream
+	header zeekHeader
+	stream *jsoniter.Stream
+}
+
+type zeekHeader struct {
+	separator    string
+	setSeparator string
+	emptyField   string
+	unsetField   string
+	path         string
+	fields       []string
+	types        []string
+}
+
+func defaultZeekHeader() zeekHeader {
+	return zeekHeader{
+		separator:    "\t",
+		setSeparator: ",",
+		emptyField:   "(empty)",
+		unsetField:   "-",
+	}
+}
+
+// Err implements the Stream interface
+func (s *ZeekStream) Err() error {
+	return s.lines.Err()
+}
+
+// Next implements the Stream interface
+func (s *ZeekStream) Next() []byte {
+	for {
+		line := s.lines.Next()
+		if line == nil {
+			return nil
+		}
+		switch {
+		case len(line) > 0 && line[0] == '#':
+			s.readHeader(string(line))
+			continue
+		case len(line) == 0, line[0] == '{', s.header.fields == nil:
+			return line
+		default:
+			return s.convert(string(line))
+		}
+	}
+}
+
+func (s *ZeekStream) readHeader(line string) {
+	const separatorDirective = "#separator "
+	if strings.HasPrefix(line, separatorDirective) {
+		// A new log starts, the separator is escaped as `\x09`
+		s.header = defaultZeekHeader()
+		s.header.separator = unescapeZeek(strings.TrimPrefix(line, separatorDirective))
+		return
+	}
+	values := strings.Split(line, s.header.separator)
+	directive, values := values[0], values[1:]
+	switch directive {
+	case "#set_separator":
+		if len(values) == 1 {
+			s.header.setSeparator = unescapeZeek(values[0])
+		}
+	case "#empty_field":
+		if len(values) == 1 {
+			s.header.emptyField = values[0]
+		}
+	case "#unset_field":
+		if len(values) == 1 {
+			s.header.unsetField = values[0]
+		}
+	case "#path":
+		if len(values) == 1 {
+			s.header.path = values[0]
+		}
+	case "#fields":
+		s.header.fields = values
+	case "#types":
+		s.header.types = values
+	case "#close":
+		// Records after the end of a log cannot be mapped to fields
+		s.header.fields, s.header.types = nil, nil
+	}
+}
+
+func (s *ZeekStream) convert(line string) []byte {
+	h := &s.header
+	values := strings.Split(line, h.separator)
+	if len(values) != len(h.fields) {
+		// Let the parsers fail on the original line
+		return []byte(line)
+	}
+	stream := s.stream
+	stream.SetBuffer(stream.Buffer()[:0])
+	stream.WriteObjectStart()
+	more := false
+	if h.path != "" {
+		stream.WriteObjectField("_path")
+		stream.WriteString(h.path)
+		more = true
+	}
+	for i, value := range values {
+		if value == h.unsetField {
+			continue
+		}
+		if more {
+			stream.WriteMore()
+		}
+		more = true
+		stream.WriteObjectField(h.fields[i])
+		typ := ""
+		if i < len(h.types) {
+			typ = h.types[i]
+		}
+		element, isContainer := zeekContainerElement(typ)
+		if !isContainer {
+			if value == h.emptyField {
+				value = ""
+			}
+			writeZeekValue(stream, typ, value)
+			continue
+		}
+		stream.WriteArrayStart()
+		if value != h.emptyField {
+			for j, el := range strings.Split(value, h.setSeparator) {
+				if j > 0 {
+					stream.WriteMore()
+				}
+				writeZeekValue(stream, element, el)
+			}
+		}
+		stream.WriteArrayEnd()
+	}
+	stream.WriteObjectEnd()
+	return stream.Buffer()
+}
+
+// zeekContainerElement returns the element type of set[T] and vector[T] types
+func zeekContainerElement(typ string) (string, bool) {
+	for _, prefix := range []string{"set[", "vector["} {
+		if strings.HasPrefix(typ, prefix) && strings.HasSuffix(typ, "]") {
+			return typ[len(prefix) : len(typ)-1], true
+		}
+	}
+	return "", false
+}
+
+func writeZeekValue(stream *jsoniter.Stream, typ, value string) {
+	switch typ {
+	case "count", "int", "port", "double", "time", "interval":
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			stream.WriteRaw(value)
+			return
+		}
+	case "bool":
+		switch value {
+		case "T":
+			stream.WriteTrue()
+			return
+		case "F":
+			stream.WriteFalse()
+			return
+		}
+	}
+	stream.WriteString(unescapeZeek(value))
+}
+
+// unescapeZeek unescapes `\xHH` sequences used by Zeek for separators and non-printable characters
+func unescapeZeek(s string) string {
+	if !strings.Contains(s, `\x`) {
+		return s
+	}
+	var b bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) && s[i+1] == 'x' {
+			if c, err := strconv.ParseUint(s[i+2:i+4], 16, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
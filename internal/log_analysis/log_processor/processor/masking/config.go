@@ -0,0 +1,121 @@
+package masking
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/pkg/stringset"
+)
+
+const (
+	// TransformRedact replaces values with a fixed string
+	TransformRedact = "redact"
+	// TransformTruncate keeps the first characters of values
+	TransformTruncate = "truncate"
+	// TransformHash replaces values with their hex encoded HMAC-SHA256
+	TransformHash = "hash"
+
+	DefaultReplacement = "[REDACTED]"
+
+	indicatorPrefixJSON = pantherlog.FieldPrefixJSON + "any_"
+)
+
+// Config is the configuration of the masking stage
+type Config struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule masks the values of an event field or of an indicator field.
+//
+// Indicator rules mask all the values of an indicator field (i.e. 'p_any_emails').
+// All event fields with the exact same value are masked too, so that masked indicators can still be used
+// to join events. Values masked by field rules are masked the same way in indicator fields.
+type Rule struct {
+	// LogTypes are the log types to mask, if empty all log types are masked
+	LogTypes []string `json:"logTypes,omitempty"`
+	// Field is the path of the event field to mask (i.e. 'actor.alternateId').
+	// Arrays along the path are traversed so that all their elements are masked.
+	// Source metadata and normalized fields can be masked too (i.e. 'p_source_metadata.logStream').
+	Field string `json:"field,omitempty"`
+	// Indicator is the name of the indicator field to mask (i.e. 'p_any_emails')
+	Indicator string `json:"indicator,omitempty"`
+	// Transform is one of redact, truncate or hash
+	Transform string `json:"transform"`
+	// Length is the number of characters kept by truncate
+	Length int `json:"length,omitempty"`
+	// Replacement is the string used by redact, it defaults to DefaultReplacement
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// Validate checks the configuration for errors
+func (c *Config) Validate() error {
+	for i := range c.Rules {
+		if err := c.Rules[i].Validate(); err != nil {
+			return errors.WithMessagef(err, "invalid masking rule #%d", i)
+		}
+	}
+	return nil
+}
+
+// Validate checks the rule for errors
+func (r *Rule) Validate() error {
+	switch {
+	case r.Field == "" && r.Indicator == "":
+		return errors.New("rule has no field or indicator")
+	case r.Field != "" && r.Indicator != "":
+		return errors.New("rule has both a field and an indicator")
+	case strings.HasPrefix(r.Field, pantherlog.FieldPrefixJSON) && !isMaskedPantherField(r.Field):
+		return errors.Errorf("cannot mask panther field %q, use an indicator rule", r.Field)
+	case r.Indicator != "" && !isIndicatorName(r.Indicator):
+		return errors.Errorf("unknown indicator field %q", r.Indicator)
+	}
+	switch r.Transform {
+	case TransformRedact, TransformHash:
+	case TransformTruncate:
+		if r.Length <= 0 {
+			return errors.New("truncate rule requires a positive length")
+		}
+	default:
+		return errors.Errorf("invalid transform %q", r.Transform)
+	}
+	return nil
+}
+
+// requiresKey checks if any rule needs a key to hash values
+func (c *Config) requiresKey() bool {
+	for i := range c.Rules {
+		if c.Rules[i].Transform == TransformHash {
+			return true
+		}
+	}
+	return false
+}
+
+func isMaskedPantherField(field string) bool {
+	name := strings.SplitN(field, ".", 2)[0]
+	return name == pantherlog.FieldSourceMetadataJSON || name == pantherlog.FieldNormalizedJSON
+}
+
+func isIndicatorName(name string) bool {
+	return strings.HasPrefix(name, indicatorPrefixJSON) && stringset.Contains(pantherlog.RegisteredFieldNamesJSON(), name)
+}
@@ -0,0 +1,351 @@
+package masking
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/enrichment"
+	"github.com/panther-labs/panther/pkg/stringset"
+)
+
+// Masker masks the values of event fields before they are stored.
+// It is safe to use MaskJSON concurrently.
+type Masker struct {
+	// rules for all log types
+	rules []*rule
+	// rules for specific log types, including the rules for all log types
+	rulesByLogType map[string][]*rule
+}
+
+type rule struct {
+	path      []string
+	transform func(value string) string
+	// indicator rules mask equal values in all event fields
+	indicator bool
+}
+
+// LoadConfig reads the masking configuration from an S3 URL or a local file
+func LoadConfig(ctx context.Context, s3Client s3iface.S3API, location string) (*Config, error) {
+	r, err := enrichment.OpenLocation(ctx, s3Client, location)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read masking config")
+	}
+	config := Config{}
+	if err := jsoniter.Unmarshal(data, &config); err != nil {
+		return nil, errors.Wrap(err, "invalid masking config")
+	}
+	return &config, nil
+}
+
+// New creates a masker.
+// The key is used to hash values with HMAC-SHA256 so that hashed values can only be reproduced by its owner.
+func New(config *Config, key []byte) (*Masker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if config.requiresKey() && len(key) == 0 {
+		return nil, errors.New("a key is required to hash values")
+	}
+	m := &Masker{
+		rulesByLogType: make(map[string][]*rule),
+	}
+	for i := range config.Rules {
+		r := &config.Rules[i]
+		compiled := &rule{
+			transform: newTransform(r, key),
+			indicator: r.Indicator != "",
+		}
+		if r.Indicator != "" {
+			compiled.path = []string{r.Indicator}
+		} else {
+			compiled.path = strings.Split(r.Field, ".")
+		}
+		if len(r.LogTypes) == 0 {
+			m.rules = append(m.rules, compiled)
+			for logType, rules := range m.rulesByLogType {
+				m.rulesByLogType[logType] = append(rules, compiled)
+			}
+			continue
+		}
+		for _, logType := range stringset.New(r.LogTypes...) {
+			rules, ok := m.rulesByLogType[logType]
+			if !ok {
+				rules = append(rules, m.rules...)
+			}
+			m.rulesByLogType[logType] = append(rules, compiled)
+		}
+	}
+	return m, nil
+}
+
+func newTransform(r *Rule, key []byte) func(string) string {
+	switch r.Transform {
+	case TransformTruncate:
+		n := r.Length
+		return func(value string) string {
+			for i := range value {
+				if n == 0 {
+					return value[:i]
+				}
+				n--
+			}
+			return value
+		}
+	case TransformHash:
+		return func(value string) string {
+			h := hmac.New(sha256.New, key)
+			_, _ = h.Write([]byte(value))
+			return hex.EncodeToString(h.Sum(nil))
+		}
+	default:
+		replacement := r.Replacement
+		if replacement == "" {
+			replacement = DefaultReplacement
+		}
+		return func(_ string) string {
+			return replacement
+		}
+	}
+}
+
+func (m *Masker) rulesFor(logType string) []*rule {
+	if rules, ok := m.rulesByLogType[logType]; ok {
+		return rules
+	}
+	return m.rules
+}
+
+// Masks checks if there are masking rules for a log type
+func (m *Masker) Masks(logType string) bool {
+	return len(m.rulesFor(logType)) > 0
+}
+
+// MaskJSON masks the JSON of an event of a log type.
+// The JSON should include the panther fields of the event so that indicator values are masked too.
+func (m *Masker) MaskJSON(logType string, data []byte) ([]byte, error) {
+	rules := m.rulesFor(logType)
+	if len(rules) == 0 {
+		return data, nil
+	}
+	data, err := maskJSON(jsoniter.ConfigDefault, data, rules)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to mask %s event", logType)
+	}
+	return data, nil
+}
+
+// maskJSON masks an event in two passes.
+// The first pass collects the masked values of all matching fields.
+// The second pass writes the event replacing the masked values in indicator fields, so that values are masked
+// the same way in event fields and indicator fields. Values masked by indicator rules are replaced in all fields.
+func maskJSON(api jsoniter.API, data []byte, rules []*rule) ([]byte, error) {
+	w := walker{
+		rules:        rules,
+		replacements: make(map[string]replacement),
+		collect:      true,
+	}
+	iter := api.BorrowIterator(data)
+	defer api.ReturnIterator(iter)
+	w.walk(iter)
+	if err := iter.Error; err != nil {
+		return nil, err
+	}
+
+	iter.ResetBytes(data)
+	stream := api.BorrowStream(nil)
+	defer api.ReturnStream(stream)
+	w.collect = false
+	w.stream = stream
+	w.walk(iter)
+	if err := iter.Error; err != nil {
+		return nil, err
+	}
+	if err := stream.Error; err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), stream.Buffer()...), nil
+}
+
+type walker struct {
+	rules        []*rule
+	replacements map[string]replacement
+	path         []string
+	// if collect is set no output is written
+	collect bool
+	stream  *jsoniter.Stream
+}
+
+func (w *walker) walk(iter *jsoniter.Iterator) {
+	switch iter.WhatIsNext() {
+	case jsoniter.ObjectValue:
+		w.writeObjectStart()
+		n := 0
+		iter.ReadObjectCB(func(iter *jsoniter.Iterator, key string) bool {
+			w.writeField(n, key)
+			n++
+			if len(w.path) == 0 && isCoreField(key) {
+				w.writeRaw(iter.SkipAndReturnBytes())
+				return true
+			}
+			w.path = append(w.path, key)
+			w.walk(iter)
+			w.path = w.path[:len(w.path)-1]
+			return true
+		})
+		w.writeObjectEnd()
+	case jsoniter.ArrayValue:
+		w.writeArrayStart()
+		n := 0
+		iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+			if n > 0 {
+				w.writeMore()
+			}
+			n++
+			w.walk(iter)
+			return true
+		})
+		w.writeArrayEnd()
+	case jsoniter.StringValue:
+		value := w.mask(iter.ReadString())
+		if !w.collect {
+			w.stream.WriteString(value)
+		}
+	default:
+		w.writeRaw(iter.SkipAndReturnBytes())
+	}
+}
+
+type replacement struct {
+	masked string
+	// if everywhere is set the value is replaced in all fields, otherwise only in indicator fields
+	everywhere bool
+}
+
+func (w *walker) mask(value string) string {
+	if r := w.ruleAt(w.path); r != nil {
+		repl, ok := w.replacements[value]
+		if !ok {
+			repl.masked = r.transform(value)
+		}
+		repl.everywhere = repl.everywhere || r.indicator
+		w.replacements[value] = repl
+		return repl.masked
+	}
+	if repl, ok := w.replacements[value]; ok && (repl.everywhere || w.inIndicator()) {
+		return repl.masked
+	}
+	return value
+}
+
+func (w *walker) inIndicator() bool {
+	return len(w.path) > 0 && strings.HasPrefix(w.path[0], indicatorPrefixJSON)
+}
+
+func (w *walker) ruleAt(path []string) *rule {
+	for _, r := range w.rules {
+		if equalPaths(r.path, path) {
+			return r
+		}
+	}
+	return nil
+}
+
+func equalPaths(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// isCoreField checks if a top level field is a panther field that is never masked.
+// Indicator fields and the panther fields that hold values of the event or its source are masked.
+func isCoreField(key string) bool {
+	switch key {
+	case pantherlog.FieldSourceMetadataJSON, pantherlog.FieldNormalizedJSON, pantherlog.FieldEnrichmentJSON:
+		return false
+	}
+	return strings.HasPrefix(key, pantherlog.FieldPrefixJSON) && !strings.HasPrefix(key, indicatorPrefixJSON)
+}
+
+func (w *walker) writeObjectStart() {
+	if !w.collect {
+		w.stream.WriteObjectStart()
+	}
+}
+
+func (w *walker) writeObjectEnd() {
+	if !w.collect {
+		w.stream.WriteObjectEnd()
+	}
+}
+
+func (w *walker) writeArrayStart() {
+	if !w.collect {
+		w.stream.WriteArrayStart()
+	}
+}
+
+func (w *walker) writeArrayEnd() {
+	if !w.collect {
+		w.stream.WriteArrayEnd()
+	}
+}
+
+func (w *walker) writeMore() {
+	if !w.collect {
+		w.stream.WriteMore()
+	}
+}
+
+func (w *walker) writeField(n int, key string) {
+	if w.collect {
+		return
+	}
+	if n > 0 {
+		w.stream.WriteMore()
+	}
+	w.stream.WriteObjectField(key)
+}
+
+func (w *walker) writeRaw(raw []byte) {
+	if !w.collect {
+		w.stream.WriteRaw(string(raw))
+	}
+}
@@ -0,0 +1,178 @@
+package masking
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog/null"
+)
+
+type testEvent struct {
+	Time    time.Time     `json:"time" tcodec:"rfc3339" event_time:"true"`
+	Email   null.String   `json:"email" panther:"email"`
+	Phone   null.String   `json:"phone"`
+	IP      null.String   `json:"ip" panther:"ip"`
+	Devices []*testDevice `json:"devices"`
+}
+
+type testDevice struct {
+	Owner null.String `json:"owner" panther:"email"`
+	Name  null.String `json:"name"`
+}
+
+func TestMasker(t *testing.T) {
+	key := []byte("secret")
+	hash := func(value string) string {
+		h := hmac.New(sha256.New, key)
+		_, _ = h.Write([]byte(value))
+		return hex.EncodeToString(h.Sum(nil))
+	}
+	masker, err := New(&Config{
+		Rules: []Rule{
+			{Indicator: "p_any_emails", Transform: TransformHash},
+			{Field: "phone", Transform: TransformTruncate, Length: 3, LogTypes: []string{"Test.Event"}},
+			{Field: "devices.name", Transform: TransformRedact, LogTypes: []string{"Test.Event"}},
+		},
+	}, key)
+	require.NoError(t, err)
+
+	b := pantherlog.ResultBuilder{
+		NextRowID: func() string { return "row" },
+		Now: func() time.Time {
+			return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		},
+	}
+	result, err := b.BuildResult("Test.Event", &testEvent{
+		Time:  time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Email: null.FromString("alice@example.com"),
+		Phone: null.FromString("+15551234567"),
+		IP:    null.FromString("10.0.0.1"),
+		Devices: []*testDevice{
+			{Owner: null.FromString("bob@example.com"), Name: null.FromString("bob's laptop")},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, masker.Masks("Test.Event"))
+	data, err := pantherlog.ConfigJSON().Marshal(result)
+	require.NoError(t, err)
+	data, err = masker.MaskJSON("Test.Event", data)
+	require.NoError(t, err)
+	alice, bob := hash("alice@example.com"), hash("bob@example.com")
+	expect := map[string]interface{}{
+		"time":  "2020-01-01T00:00:00Z",
+		"email": alice,
+		"phone": "+15",
+		"ip":    "10.0.0.1",
+		"devices": []interface{}{
+			map[string]interface{}{"owner": bob, "name": DefaultReplacement},
+		},
+		"p_log_type":   "Test.Event",
+		"p_row_id":     "row",
+		"p_event_time": "2020-01-01T00:00:00Z",
+		"p_parse_time": "2020-01-01T00:00:00Z",
+		// Masked values keep the order of the original values
+		"p_any_emails":       []string{alice, bob},
+		"p_any_ip_addresses": []interface{}{"10.0.0.1"},
+	}
+	expectJSON, err := jsoniter.Marshal(expect)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectJSON), string(data))
+
+	// Rules for other log types do not apply
+	result, err = b.BuildResult("Other.Event", &testEvent{
+		Time:  time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Email: null.FromString("alice@example.com"),
+		Phone: null.FromString("+15551234567"),
+	})
+	require.NoError(t, err)
+	data, err = pantherlog.ConfigJSON().Marshal(result)
+	require.NoError(t, err)
+	data, err = masker.MaskJSON("Other.Event", data)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"phone":"+15551234567"`)
+	require.Contains(t, string(data), `"p_any_emails":["`+alice+`"]`)
+}
+
+func TestMaskerPantherFields(t *testing.T) {
+	masker, err := New(&Config{
+		Rules: []Rule{
+			{Field: "user", Transform: TransformRedact},
+			{Field: "p_source_metadata.logStream", Transform: TransformRedact},
+		},
+	}, nil)
+	require.NoError(t, err)
+	data, err := masker.MaskJSON("Test.Event", []byte(`{
+"user":"alice",
+"comment":"alice",
+"p_source_metadata":{"logGroup":"group","logStream":"alice"},
+"p_normalized":{"actor":{"user":{"name":"bob"}}},
+"p_any_usernames":["alice"]
+}`))
+	require.NoError(t, err)
+	// Values masked by field rules are only replaced in indicator fields
+	require.JSONEq(t, `{
+"user":"[REDACTED]",
+"comment":"alice",
+"p_source_metadata":{"logGroup":"group","logStream":"[REDACTED]"},
+"p_normalized":{"actor":{"user":{"name":"bob"}}},
+"p_any_usernames":["[REDACTED]"]
+}`, string(data))
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(&Config{
+		Rules: []Rule{{Field: "email", Transform: TransformHash}},
+	}, nil)
+	require.Error(t, err)
+	_, err = New(&Config{
+		Rules: []Rule{{Field: "email", Transform: TransformRedact}},
+	}, nil)
+	require.NoError(t, err)
+}
+
+func TestConfigValidate(t *testing.T) {
+	for _, config := range []*Config{
+		{Rules: []Rule{{Transform: TransformRedact}}},
+		{Rules: []Rule{{Field: "email", Indicator: "p_any_emails", Transform: TransformRedact}}},
+		{Rules: []Rule{{Field: "p_any_emails", Transform: TransformRedact}}},
+		{Rules: []Rule{{Indicator: "p_any_foo", Transform: TransformRedact}}},
+		{Rules: []Rule{{Indicator: "p_row_id", Transform: TransformRedact}}},
+		{Rules: []Rule{{Field: "email", Transform: TransformTruncate}}},
+		{Rules: []Rule{{Field: "email", Transform: "encrypt"}}},
+	} {
+		require.Error(t, config.Validate())
+	}
+	config := &Config{
+		Rules: []Rule{
+			{Field: "email", Transform: TransformTruncate, Length: 3},
+			{Indicator: "p_any_ip_addresses", Transform: TransformHash},
+			{Field: "p_source_metadata.logStream", Transform: TransformRedact},
+		},
+	}
+	require.NoError(t, config.Validate())
+}
@@ -0,0 +1,77 @@
+package processor
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/normalize"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+// normalizeEvent sets the normalized event of events whose log type has a normalization mapping.
+// It returns an event that carries the JSON used to build the normalized event,
+// so that the event is not serialized again by the destination.
+func (p *Processor) normalizeEvent(event *parsers.Result) (*parsers.Result, error) {
+	normalizer, err := p.normalizerFor(event.PantherLogType)
+	if err != nil || normalizer == nil {
+		return event, err
+	}
+	// The mapping refers to fields of the event JSON
+	data, err := pantherlog.MarshalResult(p.normalizeAPI, event)
+	if err != nil {
+		return event, err
+	}
+	normalized := normalizer.Normalize(data)
+	if normalized == nil {
+		return event, nil
+	}
+	if event.RawJSON() == nil {
+		event = pantherlog.NewRawResult(event, data)
+	}
+	event.PantherNormalized = normalized
+	return event, nil
+}
+
+// normalizerFor returns the normalizer of a log type.
+// Normalizers are resolved once per log type and cached for the lifetime of the processor.
+func (p *Processor) normalizerFor(logType string) (*normalize.Normalizer, error) {
+	if normalizer, ok := p.normalizers[logType]; ok {
+		return normalizer, nil
+	}
+	if p.normalizers == nil {
+		p.normalizers = map[string]*normalize.Normalizer{}
+	}
+	entry, err := p.resolver.Resolve(context.TODO(), logType)
+	if err != nil {
+		// Cache failures too, so that a resolver error does not get reported for every event
+		p.normalizers[logType] = nil
+		return nil, errors.WithMessagef(err, "failed to resolve log type %q", logType)
+	}
+	var normalizer *normalize.Normalizer
+	if entry != nil {
+		normalizer = logtypes.Normalizer(entry)
+	}
+	p.normalizers[logType] = normalizer
+	return normalizer, nil
+}
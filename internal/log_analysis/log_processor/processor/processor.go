@@ -22,6 +22,7 @@ import (
 	"context"
 	"sync"
 
+	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -31,7 +32,12 @@ import (
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/normalize"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/deadletters"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/enrichment"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/masking"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sources"
 	"github.com/panther-labs/panther/pkg/metrics"
 	"github.com/panther-labs/panther/pkg/oplog"
@@ -136,6 +142,22 @@ func processDataStream(
 		}()
 	}
 
+	if dataStream.Members != nil {
+		members, err := dataStream.Members()
+		if err != nil {
+			return err
+		}
+		// The member files of archives are processed as separate streams
+		if members != nil {
+			for _, member := range members {
+				if err := processDataStream(ctx, member, resultsChannel, newProcessor); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
 	processor, err := newProcessor(dataStream)
 	if err != nil {
 		zap.L().Error("failed to build log processor for source",
@@ -151,11 +173,104 @@ type Processor struct {
 	input      *common.DataStream
 	classifier classification.ClassifierAPI
 	operation  *oplog.Operation
+	enricher   *enrichment.Enricher
+	enrichAPI  jsoniter.API
+	masker     *masking.Masker
+	maskAPI    jsoniter.API
+	// lines that fail classification are stored if deadLetters is set
+	deadLetters *deadletters.Store
+	// event filters are enabled if filterAPI is set
+	filterAPI     jsoniter.API
+	loadSource    func(id string) (*models.SourceIntegration, error)
+	eventFilters  map[string]map[string]*eventFilter
+	droppedEvents map[string]uint64
+	// events of log types with a normalization mapping are normalized if normalizeAPI is set
+	normalizeAPI jsoniter.API
+	resolver     logtypes.Resolver
+	normalizers  map[string]*normalize.Normalizer
+	// number of unwrapped CloudWatch Logs subscription payloads and dropped control messages
+	cloudWatchLogsPayloads        uint64
+	cloudWatchLogsControlMessages uint64
 }
 
 type Factory func(r *common.DataStream) (*Processor, error)
 
+// WithEnricher returns a factory for processors that add lookup table rows to events using enricher.
+// The jsonAPI should be the same as the one used by the destination to serialize events.
+func (f Factory) WithEnricher(enricher *enrichment.Enricher, jsonAPI jsoniter.API) Factory {
+	return func(input *common.DataStream) (*Processor, error) {
+		p, err := f(input)
+		if err != nil {
+			return nil, err
+		}
+		p.enricher = enricher
+		p.enrichAPI = jsonAPI
+		return p, nil
+	}
+}
+
+// WithEventFilters returns a factory for processors that apply the event filters of each source.
+// The jsonAPI should be the same as the one used by the destination to serialize events.
+func (f Factory) WithEventFilters(jsonAPI jsoniter.API) Factory {
+	return func(input *common.DataStream) (*Processor, error) {
+		p, err := f(input)
+		if err != nil {
+			return nil, err
+		}
+		p.filterAPI = jsonAPI
+		p.loadSource = sources.LoadSource
+		return p, nil
+	}
+}
+
+// WithNormalization returns a factory for processors that add the normalized event to the events of log types
+// with a normalization mapping.
+// The jsonAPI should be the same as the one used by the destination to serialize events.
+func (f Factory) WithNormalization(resolver logtypes.Resolver, jsonAPI jsoniter.API) Factory {
+	return func(input *common.DataStream) (*Processor, error) {
+		p, err := f(input)
+		if err != nil {
+			return nil, err
+		}
+		p.normalizeAPI = jsonAPI
+		p.resolver = resolver
+		return p, nil
+	}
+}
+
+// WithMasker returns a factory for processors that mask event fields using masker.
+// The jsonAPI should be the same as the one used by the destination to serialize events.
+func (f Factory) WithMasker(masker *masking.Masker, jsonAPI jsoniter.API) Factory {
+	return func(input *common.DataStream) (*Processor, error) {
+		p, err := f(input)
+		if err != nil {
+			return nil, err
+		}
+		p.masker = masker
+		p.maskAPI = jsonAPI
+		return p, nil
+	}
+}
+
+// WithDeadLetters returns a factory for processors that store the lines that fail classification
+func (f Factory) WithDeadLetters(store *deadletters.Store) Factory {
+	return func(input *common.DataStream) (*Processor, error) {
+		p, err := f(input)
+		if err != nil {
+			return nil, err
+		}
+		p.deadLetters = store
+		return p, nil
+	}
+}
+
 func NewFactory(resolver logtypes.Resolver) Factory {
+	return NewStickyFactory(resolver, 0)
+}
+
+// NewStickyFactory returns a factory for processors that lock the log types of each stream after
+// classifying stickyLines lines. See classification.NewStickyClassifier
+func NewStickyFactory(resolver logtypes.Resolver, stickyLines int) Factory {
 	return func(input *common.DataStream) (*Processor, error) {
 		switch src := input.Source; src.IntegrationType {
 		case models.IntegrationTypeSqs:
@@ -163,8 +278,9 @@ func NewFactory(resolver logtypes.Resolver) Factory {
 				operation: common.OpLogManager.Start(operationName),
 				input:     input,
 				classifier: &sources.SQSClassifier{
-					Resolver:   resolver,
-					LoadSource: sources.LoadSource,
+					Resolver:    resolver,
+					LoadSource:  sources.LoadSource,
+					StickyLines: stickyLines,
 				},
 			}, nil
 		case models.IntegrationTypeAWS3:
@@ -173,7 +289,7 @@ func NewFactory(resolver logtypes.Resolver) Factory {
 			if m, matched := src.S3PrefixLogTypes.LongestPrefixMatch(input.S3ObjectKey); matched {
 				availableLogTypes = m.LogTypes
 			}
-			c, err := sources.BuildClassifier(availableLogTypes, src, resolver)
+			c, err := sources.BuildStickyClassifier(availableLogTypes, src, resolver, stickyLines)
 			if err != nil {
 				return nil, err
 			}
@@ -183,7 +299,7 @@ func NewFactory(resolver logtypes.Resolver) Factory {
 				classifier: c,
 			}, nil
 		case models.IntegrationTypeAWSScan:
-			c, err := sources.BuildClassifier(src.RequiredLogTypes(), src, resolver)
+			c, err := sources.BuildStickyClassifier(src.RequiredLogTypes(), src, resolver, stickyLines)
 			if err != nil {
 				return nil, err
 			}
@@ -207,13 +323,13 @@ func (p *Processor) run(ctx context.Context, outputChan chan<- *parsers.Result)
 	defer func() {
 		p.logStats(err) // emit log line describing the processing of the file and any errors
 		operation.Stop()
-		operation.Log(err,
+		operation.Log(err, append([]zap.Field{
 			// s3 dim info
 			zap.String("bucket", p.input.S3Bucket),
 			zap.String("key", p.input.S3ObjectKey),
 			zap.Int64("size", p.input.S3ObjectSize),
 			zap.String("sourceID", p.input.Source.IntegrationID),
-		)
+		}, p.archiveFields()...)...)
 	}()
 	stream := p.input.Stream
 	for {
@@ -229,24 +345,105 @@ func (p *Processor) run(ctx context.Context, outputChan chan<- *parsers.Result)
 	return
 }
 
+// processLogLine classifies a log line.
+// The log events of CloudWatch Logs subscription payloads are unwrapped and classified separately for all sources.
 func (p *Processor) processLogLine(ctx context.Context, line string, outputChan chan<- *parsers.Result) {
+	if payloads, ok := pantherlog.ReadCloudWatchLogs(line); ok {
+		for _, payload := range payloads {
+			p.processCloudWatchLogs(ctx, payload, outputChan)
+		}
+		return
+	}
+	p.processLogEntry(ctx, line, nil, outputChan)
+}
+
+// processCloudWatchLogs classifies the message of each log event in a CloudWatch Logs subscription payload.
+// The log group and stream of the payload are attached to the events as source metadata.
+// Control messages sent by CloudWatch Logs to check that the subscription destination is reachable are dropped.
+func (p *Processor) processCloudWatchLogs(ctx context.Context, payload *pantherlog.CloudWatchLogsPayload,
+	outputChan chan<- *parsers.Result) {
+
+	if payload.MessageType == pantherlog.CloudWatchLogsControlMessage {
+		p.cloudWatchLogsControlMessages++
+		return
+	}
+	p.cloudWatchLogsPayloads++
+	metadata := payload.Metadata()
+	for _, logEvent := range payload.LogEvents {
+		p.processLogEntry(ctx, logEvent.Message, metadata, outputChan)
+	}
+}
+
+// processLogEntry classifies a log entry and sends its events to the output channel.
+// The source metadata (if any) are attached to the events.
+func (p *Processor) processLogEntry(ctx context.Context, line string, metadata map[string]string,
+	outputChan chan<- *parsers.Result) {
+
 	result, err := p.classifier.Classify(line)
 	// A classifier returns an error when it cannot classify a non-empty log line
 	if err != nil {
+		lineNum := p.classifier.Stats().LogLineCount
 		// make easy to troubleshoot but do not add log line (even partial) to avoid leaking data into CW
-		p.operation.LogWarn(errors.New("failed to classify log line"),
-			zap.Uint64("lineNum", p.classifier.Stats().LogLineCount),
+		p.operation.LogWarn(errors.New("failed to classify log line"), append([]zap.Field{
+			zap.Uint64("lineNum", lineNum),
 			zap.String("sourceId", p.input.Source.IntegrationID),
 			zap.String("sourceLabel", p.input.Source.IntegrationLabel),
 			zap.String("s3Bucket", p.input.S3Bucket),
 			zap.String("s3ObjectKey", p.input.S3ObjectKey),
-		)
+		}, p.archiveFields()...)...)
+		if p.deadLetters != nil {
+			p.storeDeadLetter(line, lineNum, result, err)
+		}
 		return
 	}
 	if result == nil {
 		return
 	}
 	for _, event := range result.Events {
+		event.AddSourceMetadata(metadata)
+		if p.filterAPI != nil {
+			filtered, err := p.filterEvent(event)
+			if err != nil {
+				// The event is sent unfiltered
+				p.operation.LogWarn(errors.WithMessage(err, "failed to filter event"),
+					zap.String("logType", event.PantherLogType),
+					zap.String("sourceId", p.input.Source.IntegrationID))
+			}
+			if filtered == nil {
+				continue
+			}
+			event = filtered
+		}
+		// Events are masked first so that normalized fields and lookups cannot expose unmasked values
+		if p.masker != nil {
+			masked, err := p.maskEvent(event)
+			if err != nil {
+				// The event is dropped so that unmasked values are never stored
+				p.operation.LogWarn(errors.WithMessage(err, "failed to mask event"),
+					zap.String("logType", event.PantherLogType),
+					zap.String("sourceId", p.input.Source.IntegrationID))
+				continue
+			}
+			event = masked
+		}
+		if p.normalizeAPI != nil {
+			normalized, err := p.normalizeEvent(event)
+			if err != nil {
+				// The event is sent without the normalized event
+				p.operation.LogWarn(errors.WithMessage(err, "failed to normalize event"),
+					zap.String("logType", event.PantherLogType),
+					zap.String("sourceId", p.input.Source.IntegrationID))
+			}
+			event = normalized
+		}
+		if p.enricher != nil {
+			if err := p.enricher.Enrich(p.enrichAPI, event); err != nil {
+				// The event is sent without enrichment
+				p.operation.LogWarn(errors.Wrap(err, "failed to enrich event"),
+					zap.String("logType", event.PantherLogType),
+					zap.String("sourceId", p.input.Source.IntegrationID))
+			}
+		}
 		select {
 		case outputChan <- event:
 		case <-ctx.Done():
@@ -255,20 +452,105 @@ func (p *Processor) processLogLine(ctx context.Context, line string, outputChan
 	}
 }
 
+// storeDeadLetter keeps a line that failed classification so that it can be inspected and replayed
+func (p *Processor) storeDeadLetter(line string, lineNum uint64, result *classification.ClassifierResult, err error) {
+	record := deadletters.NewRecord(line, result, err)
+	if p.masksLine(record) {
+		// Raw lines cannot be masked, so lines that could hold masked values are not stored
+		return
+	}
+	record.SourceID = p.input.Source.IntegrationID
+	record.SourceLabel = p.input.Source.IntegrationLabel
+	record.S3Bucket = p.input.S3Bucket
+	record.S3ObjectKey = p.input.S3ObjectKey
+	record.LineNumber = lineNum
+	if err := p.deadLetters.Put(record); err != nil {
+		p.operation.LogWarn(errors.WithMessage(err, "failed to store dead letter"),
+			zap.Uint64("lineNum", lineNum),
+			zap.String("sourceId", p.input.Source.IntegrationID))
+	}
+}
+
+// masksLine checks if a line that failed classification could hold values that must be masked
+func (p *Processor) masksLine(record *deadletters.Record) bool {
+	if p.masker == nil {
+		return false
+	}
+	logTypes := record.LogTypes()
+	if len(logTypes) == 0 {
+		logTypes = p.input.Source.RequiredLogTypes()
+	}
+	for _, logType := range logTypes {
+		if p.masker.Masks(logType) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskEvent returns a result with the masked JSON of the event
+func (p *Processor) maskEvent(event *parsers.Result) (*parsers.Result, error) {
+	if !p.masker.Masks(event.PantherLogType) {
+		return event, nil
+	}
+	data, err := pantherlog.MarshalResult(p.maskAPI, event)
+	if err != nil {
+		return nil, err
+	}
+	data, err = p.masker.MaskJSON(event.PantherLogType, data)
+	if err != nil {
+		return nil, err
+	}
+	return pantherlog.NewRawResult(event, data), nil
+}
+
+// archiveFields returns log fields identifying the archive member being processed (if any)
+func (p *Processor) archiveFields() []zap.Field {
+	if p.input.ArchiveMember == "" {
+		return nil
+	}
+	return []zap.Field{
+		zap.String("archiveMember", p.input.ArchiveMember),
+		zap.Int64("archiveMemberSize", p.input.ArchiveMemberSize),
+	}
+}
+
 func (p *Processor) logStats(err error) {
 	p.operation.Stop()
-	p.operation.Log(err, zap.Any(statsKey, *p.classifier.Stats()))
+	stats := *p.classifier.Stats()
+	for _, n := range p.droppedEvents {
+		stats.DroppedEventCount += n
+	}
+	fields := append(p.archiveFields(), zap.Any(statsKey, stats))
+	if p.cloudWatchLogsPayloads > 0 || p.cloudWatchLogsControlMessages > 0 {
+		fields = append(fields,
+			zap.Uint64("cloudWatchLogsPayloads", p.cloudWatchLogsPayloads),
+			zap.Uint64("cloudWatchLogsControlMessages", p.cloudWatchLogsControlMessages))
+	}
+	p.operation.Log(err, fields...)
+	if stats.StickyMismatchCount > 0 {
+		p.operation.LogWarn(errors.New("log lines did not match the locked log types"), append([]zap.Field{
+			zap.Uint64("stickyMismatchCount", stats.StickyMismatchCount),
+			zap.String("sourceId", p.input.Source.IntegrationID),
+			zap.String("s3Bucket", p.input.S3Bucket),
+			zap.String("s3ObjectKey", p.input.S3ObjectKey),
+		}, p.archiveFields()...)...)
+	}
 	logType := metrics.Dimension{Name: "LogType"}
 	pMetrics := []metrics.Metric{
 		{Name: "BytesProcessed"},
 		{Name: "EventsProcessed"},
 		{Name: "CombinedLatency"},
+		{Name: "StickyMismatches"},
 	}
-	for _, parserStats := range p.classifier.ParserStats() {
-		p.operation.Log(err, zap.Any(statsKey, *parserStats))
+	for _, s := range p.classifier.ParserStats() {
+		// Copy the stats so that dropped events are not added to the classifier stats
+		parserStats := *s
+		parserStats.DroppedEventCount += p.droppedEvents[parserStats.LogType]
+		p.operation.Log(err, append(p.archiveFields(), zap.Any(statsKey, parserStats))...)
 		logType.Value = parserStats.LogType
-		pMetrics[0].Value, pMetrics[1].Value, pMetrics[2].Value =
-			parserStats.BytesProcessedCount, parserStats.EventCount, parserStats.CombinedLatency
+		pMetrics[0].Value, pMetrics[1].Value, pMetrics[2].Value, pMetrics[3].Value =
+			parserStats.BytesProcessedCount, parserStats.EventCount, parserStats.CombinedLatency, parserStats.StickyMismatchCount
 		common.BytesProcessedLogger.Log(pMetrics, logType)
 	}
 }
@@ -19,13 +19,18 @@ package processor
  */
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/sns"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -39,13 +44,18 @@ import (
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/normalize"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/deadletters"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/enrichment"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/masking"
 	"github.com/panther-labs/panther/pkg/metrics"
 	"github.com/panther-labs/panther/pkg/oplog"
+	"github.com/panther-labs/panther/pkg/testutils"
 )
 
 var (
@@ -131,6 +141,323 @@ func TestProcess(t *testing.T) {
 	assert.True(t, dataStream.Closer.(*dummyCloser).closed)
 }
 
+func TestProcessArchiveMembers(t *testing.T) {
+	logs := mockLogger()
+	destination := (&testDestination{}).standardMock()
+
+	members := []*common.DataStream{makeDataStream(), makeDataStream()}
+	members[0].ArchiveMember, members[0].ArchiveMemberSize = "foo.log", 42
+	members[1].ArchiveMember, members[1].ArchiveMemberSize = "bar.log", 24
+	dataStream := makeDataStream()
+	dataStream.Stream = nil
+	dataStream.Members = func() ([]*common.DataStream, error) {
+		return members, nil
+	}
+
+	f := NewFactory(testResolver)
+	var processed []string
+	newProcessorFunc := func(input *common.DataStream) (*Processor, error) {
+		p, err := f(input)
+		if err != nil {
+			return nil, err
+		}
+		mockClassifier := &testClassifier{}
+		mockClassifier.standardMocks(&classification.ClassifierStats{
+			LogLineCount: testLogLines,
+			EventCount:   testLogLines,
+		}, map[string]*classification.ParserStats{})
+		p.classifier = mockClassifier
+		processed = append(processed, input.ArchiveMember)
+		return p, nil
+	}
+	streamChan := make(chan *common.DataStream, 1)
+	streamChan <- dataStream
+	close(streamChan)
+	err := Process(context.Background(), streamChan, destination, newProcessorFunc)
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo.log", "bar.log"}, processed)
+	require.Equal(t, 2*testLogEvents, destination.nEvents)
+	for _, s := range append(members, dataStream) {
+		assert.True(t, s.Closer.(*dummyCloser).closed)
+	}
+
+	// Each member logs its own stats
+	var memberStats []interface{}
+	for _, entry := range logs.All() {
+		fields := entry.ContextMap()
+		if _, ok := fields[statsKey]; ok {
+			memberStats = append(memberStats, fields["archiveMember"])
+		}
+	}
+	require.Equal(t, []interface{}{"foo.log", "bar.log"}, memberStats)
+}
+
+func TestProcessCloudWatchLogs(t *testing.T) {
+	f := NewFactory(testResolver)
+	p, err := f(makeDataStream())
+	require.NoError(t, err)
+	mockClassifier := &testClassifier{}
+	p.classifier = mockClassifier
+	mockClassifier.On("Classify", "foo").Return(&classification.ClassifierResult{
+		Events:  []*parsers.Result{newTestLog()},
+		Matched: true,
+	}, nil).Once()
+	// The metadata of embedded log entries are kept
+	embedded := newTestLog()
+	embedded.PantherSourceMetadata = map[string]string{"pod": "pod-1"}
+	mockClassifier.On("Classify", "bar").Return(&classification.ClassifierResult{
+		Events:  []*parsers.Result{embedded},
+		Matched: true,
+	}, nil).Once()
+
+	const control = `{"messageType":"CONTROL_MESSAGE","owner":"CloudwatchLogs","logGroup":"","logStream":"",` +
+		`"subscriptionFilters":[],"logEvents":[{"id":"","timestamp":1600000000000,"message":"CWL CONTROL MESSAGE"}]}`
+	const data = `{"messageType":"DATA_MESSAGE","owner":"123456789012","logGroup":"/aws/lambda/foo","logStream":"stream",` +
+		`"subscriptionFilters":["filter"],"logEvents":[{"id":"1","timestamp":1600000000000,"message":"foo"},` +
+		`{"id":"2","timestamp":1600000000001,"message":"bar"}]}`
+	outputChan := make(chan *parsers.Result, 2)
+	p.processLogLine(context.Background(), control+data, outputChan)
+	close(outputChan)
+
+	mockClassifier.AssertExpectations(t)
+	require.Equal(t, uint64(1), p.cloudWatchLogsPayloads)
+	require.Equal(t, uint64(1), p.cloudWatchLogsControlMessages)
+	var events []*parsers.Result
+	for event := range outputChan {
+		events = append(events, event)
+	}
+	require.Len(t, events, 2)
+	require.Equal(t, map[string]string{
+		"logGroup":  "/aws/lambda/foo",
+		"logStream": "stream",
+		"owner":     "123456789012",
+	}, events[0].PantherSourceMetadata)
+	require.Equal(t, map[string]string{
+		"logGroup":  "/aws/lambda/foo",
+		"logStream": "stream",
+		"owner":     "123456789012",
+		"pod":       "pod-1",
+	}, events[1].PantherSourceMetadata)
+}
+
+func TestFactoryWithEnricher(t *testing.T) {
+	enricher, err := enrichment.New(&enrichment.Config{}, nil)
+	require.NoError(t, err)
+	f := NewFactory(testResolver).WithEnricher(enricher, jsoniter.ConfigDefault)
+	p, err := f(makeDataStream())
+	require.NoError(t, err)
+	require.Same(t, enricher, p.enricher)
+	require.Equal(t, jsoniter.ConfigDefault, p.enrichAPI)
+}
+
+func TestProcessorMaskEvent(t *testing.T) {
+	masker, err := masking.New(&masking.Config{
+		Rules: []masking.Rule{{Field: "logLine", Transform: masking.TransformRedact}},
+	}, nil)
+	require.NoError(t, err)
+	f := NewFactory(testResolver).WithMasker(masker, jsoniter.ConfigDefault)
+	p, err := f(makeDataStream())
+	require.NoError(t, err)
+	event, err := (&pantherlog.ResultBuilder{}).BuildResult(testLogType, &struct {
+		LogLine string `json:"logLine"`
+	}{
+		LogLine: testLogLine,
+	})
+	require.NoError(t, err)
+	masked, err := p.maskEvent(event)
+	require.NoError(t, err)
+	require.Equal(t, event.CoreFields, masked.CoreFields)
+	data, err := jsoniter.Marshal(masked)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"logLine":"[REDACTED]"`)
+	require.Contains(t, string(data), `"p_log_type":"testLogType"`)
+}
+
+func TestProcessorNormalizeEvent(t *testing.T) {
+	type testLoginEvent struct {
+		LogLine string `json:"logLine" description:"log line"`
+		User    string `json:"user" description:"user"`
+	}
+	resolver := logtypes.LocalResolver(logtypes.Must("testNormalizedLogTypes", logtypes.Config{
+		Name:         testLogType,
+		Description:  "Test log type",
+		ReferenceURL: "-",
+		Schema:       &testLoginEvent{},
+		NewParser: pantherlog.FactoryFunc(func(_ interface{}) (parsers.Interface, error) {
+			return testutil.AlwaysFailParser(errors.New("fail parser")), nil
+		}),
+		Normalization: &normalize.Mapping{
+			Fields: map[string]string{
+				"actor.user.name": "user",
+			},
+			Rules: []normalize.Rule{
+				{
+					Class:    normalize.ClassAuthentication,
+					Activity: normalize.ActivityLogon,
+					Match:    []normalize.Match{{Field: "logLine", Values: []string{"login"}}},
+				},
+			},
+		},
+	}))
+	f := NewFactory(resolver).WithNormalization(resolver, jsoniter.ConfigDefault)
+	p, err := f(makeDataStream())
+	require.NoError(t, err)
+
+	event, err := (&pantherlog.ResultBuilder{}).BuildResult(testLogType, &testLoginEvent{
+		LogLine: "login",
+		User:    "alice",
+	})
+	require.NoError(t, err)
+	event, err = p.normalizeEvent(event)
+	require.NoError(t, err)
+	require.Equal(t, &pantherlog.Normalized{
+		Class:    normalize.ClassAuthentication,
+		ClassUID: 3002,
+		Activity: normalize.ActivityLogon,
+		Actor: &pantherlog.NormalizedActor{
+			User: &pantherlog.NormalizedUser{Name: "alice"},
+		},
+	}, event.PantherNormalized)
+	data, err := jsoniter.Marshal(event)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"p_normalized":{"class":"authentication"`)
+	// The event carries the JSON it was normalized from
+	require.IsType(t, jsoniter.RawMessage{}, event.Event)
+	require.True(t, event.EventIncludesPantherFields)
+	require.Contains(t, string(data), `"user":"alice"`)
+
+	// Events that do not match any rule are not normalized
+	event, err = (&pantherlog.ResultBuilder{}).BuildResult(testLogType, &testLoginEvent{
+		LogLine: "logout",
+	})
+	require.NoError(t, err)
+	normalized, err := p.normalizeEvent(event)
+	require.NoError(t, err)
+	require.Same(t, event, normalized)
+	require.Nil(t, event.PantherNormalized)
+
+	// Log types without a normalization mapping are skipped
+	p, err = NewFactory(testResolver).WithNormalization(testResolver, jsoniter.ConfigDefault)(makeDataStream())
+	require.NoError(t, err)
+	event = newTestLog()
+	normalized, err = p.normalizeEvent(event)
+	require.NoError(t, err)
+	require.Same(t, event, normalized)
+	require.Nil(t, event.PantherNormalized)
+	require.Contains(t, p.normalizers, testLogType)
+}
+
+func TestProcessorMaskBeforeNormalize(t *testing.T) {
+	type testLoginEvent struct {
+		LogLine string `json:"logLine" description:"log line"`
+		User    string `json:"user" description:"user"`
+	}
+	resolver := logtypes.LocalResolver(logtypes.MustBuild(logtypes.ConfigJSON{
+		Name:         testLogType,
+		Description:  "Test log type",
+		ReferenceURL: "-",
+		NewEvent: func() interface{} {
+			return &testLoginEvent{}
+		},
+		Normalization: &normalize.Mapping{
+			Fields: map[string]string{
+				"actor.user.name": "user",
+			},
+			Rules: []normalize.Rule{
+				{
+					Class:    normalize.ClassAuthentication,
+					Activity: normalize.ActivityLogon,
+					Match:    []normalize.Match{{Field: "logLine", Values: []string{"login"}}},
+				},
+			},
+		},
+	}))
+	masker, err := masking.New(&masking.Config{
+		Rules: []masking.Rule{{Field: "user", Transform: masking.TransformRedact}},
+	}, nil)
+	require.NoError(t, err)
+	f := NewFactory(resolver).
+		WithNormalization(resolver, jsoniter.ConfigDefault).
+		WithMasker(masker, jsoniter.ConfigDefault)
+	p, err := f(makeDataStream())
+	require.NoError(t, err)
+
+	outputChan := make(chan *parsers.Result, 1)
+	p.processLogEntry(context.Background(), `{"logLine":"login","user":"alice"}`, nil, outputChan)
+	require.Len(t, outputChan, 1)
+	data, err := jsoniter.Marshal(<-outputChan)
+	require.NoError(t, err)
+	// The normalized event is built from the masked event
+	require.NotContains(t, string(data), "alice")
+	require.Contains(t, string(data), `"user":"[REDACTED]"`)
+	require.Contains(t, string(data), `"actor":{"user":{"name":"[REDACTED]"}}`)
+}
+
+func TestProcessorStoreDeadLetter(t *testing.T) {
+	uploader := &testutils.S3UploaderMock{}
+	snsClient := &testutils.SnsMock{}
+	store := &deadletters.Store{
+		Uploader: uploader,
+		SNSAPI:   snsClient,
+		Bucket:   testBucket,
+	}
+	var uploaded []byte
+	uploader.On("Upload", mock.Anything, mock.Anything).Return(&s3manager.UploadOutput{}, nil).Run(func(args mock.Arguments) {
+		data, err := ioutil.ReadAll(args.Get(0).(*s3manager.UploadInput).Body)
+		require.NoError(t, err)
+		uploaded = data
+	}).Once()
+	snsClient.On("Publish", mock.Anything).Return(&sns.PublishOutput{}, nil).Once()
+
+	f := NewFactory(testResolver).WithDeadLetters(store)
+	p, err := f(makeDataStream())
+	require.NoError(t, err)
+	mockClassifier := &testClassifier{}
+	p.classifier = mockClassifier
+	mockClassifier.On("Classify", mock.Anything).Return(&classification.ClassifierResult{
+		NumMiss: 1,
+		ParserErrors: []classification.ParserError{
+			{LogType: testLogType, Err: errors.New("parse failed")},
+		},
+	}, errors.New("failed to classify log line")).Once()
+	mockClassifier.On("Stats", mock.Anything).Return(&classification.ClassifierStats{LogLineCount: 3})
+
+	p.processLogLine(context.Background(), "bad line", make(chan *parsers.Result))
+	require.NoError(t, store.Flush())
+	uploader.AssertExpectations(t)
+	snsClient.AssertExpectations(t)
+
+	var records []*deadletters.Record
+	err = deadletters.ReadRecords(bytes.NewReader(uploaded), func(record *deadletters.Record) error {
+		records = append(records, record)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	record := records[0]
+	require.Equal(t, "bad line", record.Line)
+	require.Equal(t, uint64(3), record.LineNumber)
+	require.Equal(t, testSourceID, record.SourceID)
+	require.Equal(t, testSourceLabel, record.SourceLabel)
+	require.Equal(t, testBucket, record.S3Bucket)
+	require.Equal(t, testKey, record.S3ObjectKey)
+	require.Equal(t, []deadletters.ParserError{{LogType: testLogType, Error: "parse failed"}}, record.Errors)
+
+	// Lines of masked log types are not stored
+	masker, err := masking.New(&masking.Config{
+		Rules: []masking.Rule{{Field: "logLine", Transform: masking.TransformRedact, LogTypes: []string{testLogType}}},
+	}, nil)
+	require.NoError(t, err)
+	p, err = f.WithMasker(masker, jsoniter.ConfigDefault)(makeDataStream())
+	require.NoError(t, err)
+	p.classifier = mockClassifier
+	mockClassifier.On("Classify", mock.Anything).Return(&classification.ClassifierResult{},
+		errors.New("failed to classify log line")).Once()
+	p.processLogLine(context.Background(), "bad line", make(chan *parsers.Result))
+	require.NoError(t, store.Flush())
+	uploader.AssertExpectations(t)
+}
+
 func TestProcessDataStreamError(t *testing.T) {
 	logs := mockLogger()
 
@@ -309,6 +636,10 @@ func TestProcessClassifyFailure(t *testing.T) {
 						Name: "CombinedLatency",
 						Unit: metrics.UnitMilliseconds,
 					},
+					{
+						Name: "StickyMismatches",
+						Unit: metrics.UnitCount,
+					},
 				},
 			},
 		},
@@ -389,6 +720,7 @@ func TestProcessClassifyFailure(t *testing.T) {
 				zap.Uint64("BytesProcessed", 7996),
 				zap.Uint64("EventsProcessed", 1999),
 				zap.Uint64("CombinedLatency", 0),
+				zap.Uint64("StickyMismatches", 0),
 				zap.Any("_aws", embeddedMetric),
 			},
 		},
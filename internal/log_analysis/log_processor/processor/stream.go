@@ -34,7 +34,12 @@ import (
 
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/geoip"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/deadletters"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/enrichment"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/masking"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sources"
 	"github.com/panther-labs/panther/pkg/awsbatch/sqsbatch"
 	"github.com/panther-labs/panther/pkg/awsutils"
@@ -45,29 +50,111 @@ const (
 	processingMaxFilesLimit = 5000
 )
 
+// PollOption enables an optional processing stage of PollEvents
+type PollOption func(*pollOptions)
+
+type pollOptions struct {
+	enricher    *enrichment.Enricher
+	masker      *masking.Masker
+	geoResolver *geoip.Resolver
+	deadLetters *deadletters.Store
+}
+
+// EnrichEvents adds the rows of lookup tables to events (see Factory.WithEnricher)
+func EnrichEvents(enricher *enrichment.Enricher) PollOption {
+	return func(o *pollOptions) {
+		o.enricher = enricher
+	}
+}
+
+// MaskEvents masks event fields before they are stored (see Factory.WithMasker)
+func MaskEvents(masker *masking.Masker) PollOption {
+	return func(o *pollOptions) {
+		o.masker = masker
+	}
+}
+
+// ResolveGeoIP resolves the country and ASN of the ip addresses of events for all destinations
+func ResolveGeoIP(resolver *geoip.Resolver) PollOption {
+	return func(o *pollOptions) {
+		o.geoResolver = resolver
+	}
+}
+
+// StoreDeadLetters stores the lines that fail classification (see Factory.WithDeadLetters)
+func StoreDeadLetters(store *deadletters.Store) PollOption {
+	return func(o *pollOptions) {
+		o.deadLetters = store
+	}
+}
+
 /*
 PollEvents acts as an interface to aggregate sqs messages to avoid many small S3 files being created under load.
 The function will attempt to read more messages from the queue when the queue has messages. Under load
 the lambda will continue to read events and maximally aggregate data to produce fewer, bigger files.
 Fewer, bigger files makes Athena queries much faster.
+Options with a nil value do not enable their processing stage.
 */
 func PollEvents(
 	ctx context.Context,
 	sqsClient sqsiface.SQSAPI,
 	resolver logtypes.Resolver,
+	options ...PollOption,
 ) (sqsMessageCount int, err error) {
 
-	newProcessor := NewFactory(resolver)
+	opts := pollOptions{}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	// Use a properly configured JSON API for Athena quirks
+	jsonAPI := common.ConfigForDataLakeWriters()
+	if opts.geoResolver != nil {
+		// Resolve the country and ASN of ip addresses for all destinations
+		jsonAPI.RegisterExtension(pantherlog.NewValueResolverExtension(opts.geoResolver))
+	}
+	// Events are normalized and filtered using the destination JSON API so that the stored JSON is not altered
+	newProcessor := NewStickyFactory(resolver, common.Config.StickyClassifierLines).
+		WithNormalization(resolver, jsonAPI).
+		WithEventFilters(jsonAPI)
+	if opts.enricher != nil {
+		// Reload expired lookup tables, tables that fail to load keep their previous rows
+		if err := opts.enricher.Refresh(ctx); err != nil {
+			zap.L().Warn("failed to refresh enrichment tables", zap.Error(err))
+		}
+		newProcessor = newProcessor.WithEnricher(opts.enricher, jsonAPI)
+	}
+	if opts.masker != nil {
+		newProcessor = newProcessor.WithMasker(opts.masker, jsonAPI)
+	}
+	if opts.deadLetters != nil {
+		newProcessor = newProcessor.WithDeadLetters(opts.deadLetters)
+	}
 	process := func(streams <-chan *common.DataStream, dest destinations.Destination) error {
-		return Process(ctx, streams, dest, newProcessor)
+		err := Process(ctx, streams, dest, newProcessor)
+		if opts.deadLetters != nil {
+			// Dead letters are best effort, failing to store them should not cause the files to be processed again
+			if err := opts.deadLetters.Flush(); err != nil {
+				zap.L().Error("failed to store dead letters", zap.Error(err))
+			}
+		}
+		return err
 	}
-	return pollEvents(ctx, sqsClient, process, sources.ReadSnsMessage)
+	readSnsMessage := func(ctx context.Context, message string) ([]*common.DataStream, error) {
+		return sources.ReadSnsMessage(ctx, message, resolver)
+	}
+	dest, err := destinations.CreateDestination(jsonAPI, resolver)
+	if err != nil {
+		return 0, err
+	}
+	return pollEvents(ctx, sqsClient, dest, process, readSnsMessage)
 }
 
 // entry point for unit testing, pass in read/process functions
 func pollEvents(
 	ctx context.Context,
 	sqsClient sqsiface.SQSAPI,
+	dest destinations.Destination,
 	processFunc ProcessFunc,
 	generateDataStreamsFunc func(context.Context, string) ([]*common.DataStream, error)) (int, error) {
 
@@ -155,10 +242,7 @@ func pollEvents(
 		}
 	}()
 
-	// Use a properly configured JSON API for Athena quirks
-	jsonAPI := common.ConfigForDataLakeWriters()
 	// process streamChan until closed (blocks)
-	dest := destinations.CreateS3Destination(jsonAPI)
 	if err := processFunc(streamChan, dest); err != nil {
 		return 0, err
 	}
@@ -36,6 +36,10 @@ import (
 
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/geoip"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/deadletters"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/enrichment"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/masking"
 	"github.com/panther-labs/panther/pkg/testutils"
 )
 
@@ -69,7 +73,7 @@ func TestStreamEvents(t *testing.T) {
 
 	ctx, cancel := testContext()
 	defer cancel()
-	count, err := pollEvents(ctx, sqsMock, noopProcessorFunc, noopGenerateDataStream)
+	count, err := pollEvents(ctx, sqsMock, nil, noopProcessorFunc, noopGenerateDataStream)
 	require.NoError(t, err)
 	assert.Equal(t, len(streamTestReceiveMessageOutput.Messages), count)
 
@@ -82,7 +86,7 @@ func TestStreamEventsProcessingTimeLimitExceeded(t *testing.T) {
 
 	ctx, cancel := context.WithDeadline(context.Background(), time.Now()) // set to current time so code exits immediately
 	defer cancel()
-	count, err := pollEvents(ctx, sqsMock, noopProcessorFunc, noopGenerateDataStream)
+	count, err := pollEvents(ctx, sqsMock, nil, noopProcessorFunc, noopGenerateDataStream)
 	require.NoError(t, err)
 	assert.Equal(t, 0, count)
 	sqsMock.AssertExpectations(t)
@@ -99,7 +103,7 @@ func TestStreamEventsReadEventError(t *testing.T) {
 
 	ctx, cancel := testContext()
 	defer cancel()
-	count, err := pollEvents(ctx, sqsMock, noopProcessorFunc, failGenerateDataStream)
+	count, err := pollEvents(ctx, sqsMock, nil, noopProcessorFunc, failGenerateDataStream)
 	// Failure in the generateDataStreamsFunc should no cause the function invocation to fail
 	// but we shouldn't invoke the DeleteBatch operation neither since the messages haven't been processed
 	require.NoError(t, err)
@@ -118,7 +122,7 @@ func TestStreamEventsProcessError(t *testing.T) {
 
 	ctx, cancel := testContext()
 	defer cancel()
-	count, err := pollEvents(ctx, sqsMock, failProcessorFunc, noopGenerateDataStream)
+	count, err := pollEvents(ctx, sqsMock, nil, failProcessorFunc, noopGenerateDataStream)
 	require.Error(t, err)
 	assert.Equal(t, "processError", err.Error())
 	require.Equal(t, 0, count)
@@ -136,7 +140,7 @@ func TestStreamEventsProcessErrorAndReadEventError(t *testing.T) {
 
 	ctx, cancel := testContext()
 	defer cancel()
-	count, err := pollEvents(ctx, sqsMock, failProcessorFunc, failGenerateDataStream)
+	count, err := pollEvents(ctx, sqsMock, nil, failProcessorFunc, failGenerateDataStream)
 	require.Error(t, err)
 	assert.Equal(t, "processError", err.Error())
 	require.Equal(t, 0, count)
@@ -160,7 +164,7 @@ func TestStreamEventsReceiveSQSError(t *testing.T) {
 
 	ctx, cancel := testContext()
 	defer cancel()
-	count, err := pollEvents(ctx, sqsMock, noopProcessorFunc, noopGenerateDataStream)
+	count, err := pollEvents(ctx, sqsMock, nil, noopProcessorFunc, noopGenerateDataStream)
 	assert.NoError(t, err)
 	require.Equal(t, len(streamTestReceiveMessageOutput.Messages), count)
 
@@ -186,7 +190,7 @@ func TestStreamEventsDeleteSQSError(t *testing.T) {
 
 	ctx, cancel := testContext()
 	defer cancel()
-	count, err := pollEvents(ctx, sqsMock, noopProcessorFunc, noopGenerateDataStream)
+	count, err := pollEvents(ctx, sqsMock, nil, noopProcessorFunc, noopGenerateDataStream)
 
 	// keep sure we get error logging
 	actualLogs := logs.AllUntimed()
@@ -245,3 +249,25 @@ func noopGenerateDataStream(_ context.Context, _ string) ([]*common.DataStream,
 func failGenerateDataStream(_ context.Context, _ string) ([]*common.DataStream, error) {
 	return nil, fmt.Errorf("readEventError")
 }
+
+func TestPollOptions(t *testing.T) {
+	enricher := &enrichment.Enricher{}
+	masker := &masking.Masker{}
+	geoResolver := &geoip.Resolver{}
+	store := &deadletters.Store{}
+	opts := pollOptions{}
+	for _, option := range []PollOption{
+		EnrichEvents(enricher),
+		MaskEvents(masker),
+		ResolveGeoIP(geoResolver),
+		StoreDeadLetters(store),
+	} {
+		option(&opts)
+	}
+	require.Equal(t, pollOptions{
+		enricher:    enricher,
+		masker:      masker,
+		geoResolver: geoResolver,
+		deadLetters: store,
+	}, opts)
+}
@@ -0,0 +1,49 @@
+package registry
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logsamples"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+)
+
+// BenchmarkLogTypes benchmarks the parsers of all native log types with the samples in parsers testdata.
+// Run with `go test -run=NONE -bench=LogTypes/AWS.CloudTrail/ ./internal/log_analysis/log_processor/registry`
+// to benchmark a single log type.
+func BenchmarkLogTypes(b *testing.B) {
+	group := NativeLogTypes()
+	samples, err := logsamples.LoadSamples("../parsers", group)
+	if err != nil {
+		b.Fatal(err)
+	}
+	entries := group.Entries()
+	for _, entry := range entries {
+		entry := entry
+		b.Run(entry.String(), func(b *testing.B) {
+			b.Run("parse", func(b *testing.B) {
+				testutil.BenchmarkParser(b, entry, samples[entry.String()])
+			})
+			b.Run("fallthrough", func(b *testing.B) {
+				testutil.BenchmarkFallThrough(b, entry, entries, samples[entry.String()])
+			})
+		})
+	}
+}
@@ -35,6 +35,7 @@ import (
 	gravitationallogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/gravitationallogs"
 	gsuitelogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/gsuitelogs"
 	juniperlogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/juniperlogs"
+	kuberneteslogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/kuberneteslogs"
 	laceworklogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/laceworklogs"
 	nginxlogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/nginxlogs"
 	oktalogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/oktalogs"
@@ -46,6 +47,7 @@ import (
 	suricatalogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/suricatalogs"
 	sysloglogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/sysloglogs"
 	umbrellalogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/umbrellalogs"
+	windowslogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/windowslogs"
 	zeeklogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/zeeklogs"
 )
 
@@ -79,6 +81,8 @@ func init() {
 
 		juniperlogs.LogTypes(),
 
+		kuberneteslogs.LogTypes(),
+
 		laceworklogs.LogTypes(),
 
 		nginxlogs.LogTypes(),
@@ -101,6 +105,8 @@ func init() {
 
 		umbrellalogs.LogTypes(),
 
+		windowslogs.LogTypes(),
+
 		zeeklogs.LogTypes(),
 	)
 }
@@ -0,0 +1,96 @@
+package s3pipe
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// Compression encodings that are transparently decoded.
+const (
+	EncodingNone  = ""
+	EncodingGzip  = "gzip"
+	EncodingBzip2 = "bzip2"
+	EncodingZstd  = "zstd"
+)
+
+var (
+	// gzip magic bytes followed by the 'deflate' compression method
+	magicGzip = []byte{0x1f, 0x8b, 0x08}
+	// 'BZh' followed by the block size ('1'-'9')
+	magicBzip2 = []byte("BZh")
+	magicZstd  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// DetectEncoding sniffs the magic bytes at the start of p to detect the compression of a stream.
+// It returns EncodingNone if the data is not compressed using a known encoding.
+func DetectEncoding(p []byte) string {
+	switch {
+	case bytes.HasPrefix(p, magicGzip):
+		return EncodingGzip
+	case bytes.HasPrefix(p, magicZstd):
+		return EncodingZstd
+	case bytes.HasPrefix(p, magicBzip2) && len(p) > len(magicBzip2):
+		if level := p[len(magicBzip2)]; '1' <= level && level <= '9' {
+			return EncodingBzip2
+		}
+		return EncodingNone
+	default:
+		return EncodingNone
+	}
+}
+
+// NewDecoder wraps r with a reader that decodes the data using encoding.
+// The returned reader should be closed to release any resources held by the decoder.
+func NewDecoder(r io.Reader, encoding string) (io.ReadCloser, error) {
+	switch encoding {
+	case EncodingNone:
+		return ioutil.NopCloser(r), nil
+	case EncodingGzip:
+		return gzip.NewReader(r)
+	case EncodingBzip2:
+		return ioutil.NopCloser(bzip2.NewReader(r)), nil
+	case EncodingZstd:
+		// Use a single goroutine, we are decoding a single stream sequentially.
+		dec, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderLowmem(true))
+		if err != nil {
+			return nil, err
+		}
+		return &zstdReadCloser{Decoder: dec}, nil
+	default:
+		return nil, errors.Errorf("unsupported encoding %q", encoding)
+	}
+}
+
+type zstdReadCloser struct {
+	*zstd.Decoder
+}
+
+// Close implements io.Closer
+func (r *zstdReadCloser) Close() error {
+	r.Decoder.Close()
+	return nil
+}
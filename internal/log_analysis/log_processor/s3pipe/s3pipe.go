@@ -21,7 +21,6 @@ package s3pipe
 import (
 	"bufio"
 	"bytes"
-	"compress/gzip"
 	"context"
 	"io"
 	"sync"
@@ -62,7 +61,7 @@ func (dl Downloader) Download(ctx context.Context, input *s3.GetObjectInput) io.
 		// Set both pipe and out to the piped reader.
 		pipe:  r,
 		ready: make(chan struct{}),
-		// If a gzip stream is detected on the first chunk, out will be replaced with an *gzip.Reader
+		// If a compressed stream is detected on the first chunk, out will be replaced with a decoder
 		out: r,
 	}
 	// defer the downloading until the first call to Read
@@ -109,12 +108,7 @@ func (dr *downloadReader) peekFirstChunk(p []byte) {
 	if p == nil {
 		return
 	}
-	r := bytes.NewReader(p)
-	gz, err := gzip.NewReader(r)
-	if err != nil {
-		return
-	}
-	dr.out = gz
+	dr.encoding = DetectEncoding(p)
 }
 
 func copyBuffers(w *io.PipeWriter, parts <-chan *bytes.Buffer, peek func([]byte)) {
@@ -154,6 +148,10 @@ type downloadReader struct {
 
 	// Closed after peekFirstChunk() has ran
 	ready chan struct{}
+	// encoding is the compression detected on the first chunk
+	encoding string
+	// decoder is the decompressing reader if the stream is compressed
+	decoder io.ReadCloser
 	// out is the transparently uncompressed reader to read data from
 	out io.Reader
 }
@@ -175,17 +173,20 @@ func (dr *downloadReader) startDownloading() {
 	// Kick off downloading
 	go download()
 	<-dr.ready
-	// It is important to only call gz.Reset **after** 'ready' is closed to avoid blocking copyBuffers
-	if gz, ok := dr.out.(*gzip.Reader); ok {
-		// Wrap the pipe reader in a buffered reader
-		// 64K should provide smooth decompression without raising the overall memory requirements.
-		r := bufio.NewReaderSize(dr.pipe, DefaultReadBufferSize)
-		if err := gz.Reset(r); err != nil {
-			// we already know it is gzipped, but the pipe might have been closed in between then and now
-			_ = dr.pipe.CloseWithError(err)
-			return
-		}
+	// It is important to only create the decoder **after** 'ready' is closed to avoid blocking copyBuffers
+	if dr.encoding == EncodingNone {
+		return
 	}
+	// Wrap the pipe reader in a buffered reader
+	// 64K should provide smooth decompression without raising the overall memory requirements.
+	r := bufio.NewReaderSize(dr.pipe, DefaultReadBufferSize)
+	dec, err := NewDecoder(r, dr.encoding)
+	if err != nil {
+		// we already know it is compressed, but the pipe might have been closed in between then and now
+		_ = dr.pipe.CloseWithError(err)
+		return
+	}
+	dr.decoder, dr.out = dec, dec
 }
 
 // Close implements io.ReadCloser
@@ -198,6 +199,12 @@ func (dr *downloadReader) Close() error {
 		// This way context errors from the Download do not override the pipe closed error on Read().
 		defer cancel()
 	}
+	var dec io.Closer
+	dec, dr.decoder = dr.decoder, nil
+	if dec != nil {
+		// The decoder only reads from the pipe, any errors will be reported by the pipe.
+		defer dec.Close()
+	}
 	return dr.pipe.Close()
 }
 
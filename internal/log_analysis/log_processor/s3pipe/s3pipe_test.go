@@ -32,6 +32,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
@@ -79,9 +80,48 @@ func TestGunzip(t *testing.T) {
 	_, _ = gz.Write([]byte(data))
 	err := gz.Close()
 	assert.NoError(err)
+	assertDownload(t, buf.Bytes(), data)
+}
+
+func TestDecompressBzip2(t *testing.T) {
+	// Output of `printf 'foo bar baz' | bzip2 -c`, the standard library only provides a bzip2 decoder.
+	data := []byte{
+		0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x68, 0x8b,
+		0x39, 0xbd, 0x00, 0x00, 0x03, 0x11, 0x80, 0x40, 0x00, 0x31, 0x00, 0x90,
+		0x10, 0x20, 0x00, 0x31, 0x0c, 0x00, 0x94, 0x1e, 0xa6, 0x8f, 0x26, 0x91,
+		0x90, 0xf1, 0x77, 0x24, 0x53, 0x85, 0x09, 0x06, 0x88, 0xb3, 0x9b, 0xd0,
+	}
+	require.Equal(t, EncodingBzip2, DetectEncoding(data))
+	assertDownload(t, data, "foo bar baz")
+}
+
+func TestDecompressZstd(t *testing.T) {
+	assert := require.New(t)
+	enc, err := zstd.NewWriter(nil)
+	assert.NoError(err)
+	data := enc.EncodeAll([]byte("foo bar baz"), nil)
+	assert.NoError(enc.Close())
+	assert.Equal(EncodingZstd, DetectEncoding(data))
+	assertDownload(t, data, "foo bar baz")
+}
+
+func TestDetectEncoding(t *testing.T) {
+	assert := require.New(t)
+	assert.Equal(EncodingNone, DetectEncoding(nil))
+	assert.Equal(EncodingNone, DetectEncoding([]byte("foo bar baz")))
+	assert.Equal(EncodingNone, DetectEncoding([]byte("BZh")))
+	assert.Equal(EncodingNone, DetectEncoding([]byte("BZhX")))
+	assert.Equal(EncodingBzip2, DetectEncoding([]byte("BZh1")))
+	assert.Equal(EncodingGzip, DetectEncoding([]byte{0x1f, 0x8b, 0x08, 0x00}))
+}
+
+// assertDownload checks that body is transparently decoded to expect when downloaded in a single part.
+func assertDownload(t *testing.T, body []byte, expect string) {
+	t.Helper()
+	assert := require.New(t)
 	s3Mock := &testutils.S3Mock{}
 	s3Mock.On("MaxRetries").Return(3)
-	part, contentRange := bodyPart(buf.Bytes(), 0, 512)
+	part, contentRange := bodyPart(body, 0, 512)
 	s3Mock.On("GetObjectWithContext", mock.Anything, mock.Anything, mock.Anything).Return(&s3.GetObjectOutput{
 		ContentRange: &contentRange,
 		Body:         ioutil.NopCloser(bytes.NewReader(part)),
@@ -96,10 +136,10 @@ func TestGunzip(t *testing.T) {
 	}
 	rc := dl.Download(context.Background(), input)
 	defer rc.Close()
-	body := bytes.Buffer{}
-	_, err = body.ReadFrom(rc)
+	actual := bytes.Buffer{}
+	_, err := actual.ReadFrom(rc)
 	assert.NoError(err)
-	assert.Equal("foo bar baz", body.String())
+	assert.Equal(expect, actual.String())
 	s3Mock.AssertExpectations(t)
 }
 
@@ -0,0 +1,293 @@
+package sources
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/s3pipe"
+)
+
+const (
+	// ArchiveMaxSize is the max size of an (uncompressed) archive that can be processed.
+	// Archives need random access so they are spooled to local storage which is limited to 512MB on AWS Lambda.
+	ArchiveMaxSize = 400 * 1024 * 1024
+
+	archiveFormatNone = ""
+	archiveFormatZip  = "zip"
+	archiveFormatTar  = "tar"
+	// The 'ustar' magic of tar archives is at offset 257 so we need to peek at the whole first block.
+	archiveSniffSize = 512
+	tarMagicOffset   = 257
+)
+
+var (
+	magicZip      = []byte("PK\x03\x04")
+	magicZipEmpty = []byte("PK\x05\x06")
+	// Matches both POSIX ("ustar\x0000") and GNU ("ustar  \x00") tar headers
+	magicTar = []byte("ustar")
+)
+
+// detectArchiveFormat sniffs the magic bytes at the start of p to detect zip and tar archives.
+func detectArchiveFormat(p []byte) string {
+	switch {
+	case bytes.HasPrefix(p, magicZip), bytes.HasPrefix(p, magicZipEmpty):
+		return archiveFormatZip
+	case len(p) > tarMagicOffset && bytes.HasPrefix(p[tarMagicOffset:], magicTar):
+		return archiveFormatTar
+	default:
+		return archiveFormatNone
+	}
+}
+
+// buildArchiveStreams spools an archive to local storage and creates a separate stream for each member file.
+// The spooled file is removed once all member streams have been closed.
+func buildArchiveStreams(src *models.SourceIntegration, s3Object *S3ObjectInfo, framing *pantherlog.FramingConfig,
+	format string, r io.Reader) ([]*archiveMemberStream, error) {
+	f, err := ioutil.TempFile("", "panther-archive-")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create archive file")
+	}
+	archive := &archiveFile{
+		File: f,
+		refs: 1,
+	}
+	// Release our reference once all member streams have acquired theirs
+	defer func() {
+		_ = archive.release()
+	}()
+
+	n, err := io.Copy(f, io.LimitReader(r, ArchiveMaxSize+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to download %s archive", format)
+	}
+	if n > ArchiveMaxSize {
+		return nil, errors.Errorf("%s archive exceeds max size of %d bytes", format, ArchiveMaxSize)
+	}
+	members, err := readArchiveMembers(format, f, n)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to read %s archive", format)
+	}
+	streams := make([]*archiveMemberStream, 0, len(members))
+	for _, member := range members {
+		member := member
+		streams = append(streams, &archiveMemberStream{
+			name:    member.Name,
+			size:    member.Size,
+			archive: archive.acquire(),
+			open: func() (io.Closer, logstream.Stream, error) {
+				return openArchiveMember(src, framing, &member)
+			},
+		})
+	}
+	zap.L().Debug("detected archive",
+		zap.String("format", format),
+		zap.String("bucket", s3Object.S3Bucket),
+		zap.String("key", s3Object.S3ObjectKey),
+		zap.Int("numMembers", len(members)))
+	return streams, nil
+}
+
+type archiveMember struct {
+	Name string
+	Size int64
+	Open func() (io.ReadCloser, error)
+}
+
+// readArchiveMembers lists the regular files in an archive
+func readArchiveMembers(format string, r io.ReaderAt, size int64) ([]archiveMember, error) {
+	var members []archiveMember
+	switch format {
+	case archiveFormatZip:
+		zr, err := zip.NewReader(r, size)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if !f.Mode().IsRegular() {
+				continue
+			}
+			members = append(members, archiveMember{
+				Name: f.Name,
+				Size: int64(f.UncompressedSize64),
+				Open: f.Open,
+			})
+		}
+		return members, nil
+	case archiveFormatTar:
+		// io.SectionReader implements io.Seeker so the tar reader will seek over member data.
+		// This allows us to find the offset of each member's data in the archive.
+		sr := io.NewSectionReader(r, 0, size)
+		tr := tar.NewReader(sr)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return members, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			if !hdr.FileInfo().Mode().IsRegular() {
+				continue
+			}
+			offset, err := sr.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return nil, err
+			}
+			data := io.NewSectionReader(r, offset, hdr.Size)
+			members = append(members, archiveMember{
+				Name: hdr.Name,
+				Size: hdr.Size,
+				Open: func() (io.ReadCloser, error) {
+					return ioutil.NopCloser(data), nil
+				},
+			})
+		}
+	default:
+		return nil, errors.Errorf("unsupported archive format %q", format)
+	}
+}
+
+// openArchiveMember opens a member file, transparently decompressing it if needed.
+func openArchiveMember(src *models.SourceIntegration, framing *pantherlog.FramingConfig,
+	member *archiveMember) (io.Closer, logstream.Stream, error) {
+	rc, err := member.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	r := bufio.NewReaderSize(rc, s3pipe.DefaultReadBufferSize)
+	head, err := r.Peek(archiveSniffSize)
+	if err != nil && err != io.EOF {
+		_ = rc.Close()
+		return nil, nil, err
+	}
+	enc := s3pipe.DetectEncoding(head)
+	if enc == s3pipe.EncodingNone && detectArchiveFormat(head) != archiveFormatNone {
+		_ = rc.Close()
+		return nil, nil, errors.New("nested archives are not supported")
+	}
+	dec, err := s3pipe.NewDecoder(r, enc)
+	if err != nil {
+		_ = rc.Close()
+		return nil, nil, errors.Wrapf(err, "failed to decode %s member", enc)
+	}
+	closer := multiCloser{dec, rc}
+	stream, err := newLogStream(src, member.Name, framing, dec)
+	if err != nil {
+		_ = closer.Close()
+		return nil, nil, err
+	}
+	return closer, stream, nil
+}
+
+// archiveFile is a spooled archive shared by the streams of its members
+type archiveFile struct {
+	*os.File
+	refs int32
+}
+
+func (a *archiveFile) acquire() *archiveFile {
+	atomic.AddInt32(&a.refs, 1)
+	return a
+}
+
+// release removes the archive file once no member streams reference it
+func (a *archiveFile) release() error {
+	if atomic.AddInt32(&a.refs, -1) > 0 {
+		return nil
+	}
+	return multierr.Combine(a.Close(), os.Remove(a.Name()))
+}
+
+// archiveMemberStream is a logstream.Stream that opens an archive member on the first call to Next().
+// This way members that are waiting to be processed do not hold any read buffers.
+type archiveMemberStream struct {
+	name    string
+	size    int64
+	archive *archiveFile
+	open    func() (io.Closer, logstream.Stream, error)
+	closer  io.Closer
+	stream  logstream.Stream
+	err     error
+}
+
+var _ logstream.Stream = (*archiveMemberStream)(nil)
+
+// Next implements logstream.Stream
+func (s *archiveMemberStream) Next() []byte {
+	if s.stream == nil {
+		if s.err != nil || s.archive == nil {
+			return nil
+		}
+		s.closer, s.stream, s.err = s.open()
+		if s.err != nil {
+			return nil
+		}
+	}
+	return s.stream.Next()
+}
+
+// Err implements logstream.Stream
+func (s *archiveMemberStream) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.stream != nil {
+		return s.stream.Err()
+	}
+	return nil
+}
+
+// Close implements io.Closer
+// It releases the member's reference to the spooled archive file.
+func (s *archiveMemberStream) Close() (err error) {
+	if c := s.closer; c != nil {
+		s.closer = nil
+		err = c.Close()
+	}
+	if a := s.archive; a != nil {
+		s.archive = nil
+		err = multierr.Append(err, a.release())
+	}
+	return err
+}
+
+type multiCloser []io.Closer
+
+// Close implements io.Closer
+func (c multiCloser) Close() (err error) {
+	for _, closer := range c {
+		err = multierr.Append(err, closer.Close())
+	}
+	return err
+}
@@ -0,0 +1,235 @@
+package sources
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+)
+
+func TestBuildArchiveStreamsZip(t *testing.T) {
+	assert := require.New(t)
+	buf := bytes.Buffer{}
+	zw := zip.NewWriter(&buf)
+	_, err := zw.Create("logs/")
+	assert.NoError(err)
+	w, err := zw.Create("logs/foo.log")
+	assert.NoError(err)
+	_, err = w.Write([]byte("foo\nbar\n"))
+	assert.NoError(err)
+	w, err = zw.Create("logs/baz.log.gz")
+	assert.NoError(err)
+	_, err = w.Write(gzipData(t, "baz\n"))
+	assert.NoError(err)
+	assert.NoError(zw.Close())
+	assert.Equal(archiveFormatZip, detectArchiveFormat(buf.Bytes()))
+
+	streams := buildTestArchiveStreams(t, archiveFormatZip, buf.Bytes())
+	assert.Len(streams, 2)
+	assert.Equal("logs/foo.log", streams[0].name)
+	assert.Equal(int64(8), streams[0].size)
+	assert.Equal([]string{"foo", "bar"}, readStream(t, streams[0]))
+	assert.Equal("logs/baz.log.gz", streams[1].name)
+	assert.Equal([]string{"baz"}, readStream(t, streams[1]))
+}
+
+func TestBuildArchiveStreamsTar(t *testing.T) {
+	assert := require.New(t)
+	buf := bytes.Buffer{}
+	tw := tar.NewWriter(&buf)
+	assert.NoError(tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     "logs/",
+		Mode:     0755,
+	}))
+	for _, member := range []struct {
+		Name string
+		Data []byte
+	}{
+		{"logs/foo.log", []byte("foo\nbar\n")},
+		{"logs/baz.log.gz", gzipData(t, "baz\n")},
+	} {
+		assert.NoError(tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     member.Name,
+			Mode:     0644,
+			Size:     int64(len(member.Data)),
+		}))
+		_, err := tw.Write(member.Data)
+		assert.NoError(err)
+	}
+	assert.NoError(tw.Close())
+	assert.Equal(archiveFormatTar, detectArchiveFormat(buf.Bytes()))
+
+	streams := buildTestArchiveStreams(t, archiveFormatTar, buf.Bytes())
+	assert.Len(streams, 2)
+	assert.Equal("logs/foo.log", streams[0].name)
+	assert.Equal([]string{"foo", "bar"}, readStream(t, streams[0]))
+	assert.Equal("logs/baz.log.gz", streams[1].name)
+	assert.Equal([]string{"baz"}, readStream(t, streams[1]))
+}
+
+func TestBuildArchiveStreamsInvalid(t *testing.T) {
+	src := &models.SourceIntegration{}
+	src.IntegrationType = models.IntegrationTypeAWS3
+	_, err := buildArchiveStreams(src, &S3ObjectInfo{}, nil, archiveFormatZip, bytes.NewReader(magicZip))
+	require.Error(t, err)
+}
+
+func TestDetectArchiveFormat(t *testing.T) {
+	assert := require.New(t)
+	assert.Equal(archiveFormatNone, detectArchiveFormat(nil))
+	assert.Equal(archiveFormatNone, detectArchiveFormat([]byte("foo bar baz")))
+	assert.Equal(archiveFormatZip, detectArchiveFormat(magicZipEmpty))
+}
+
+// buildTestArchiveStreams builds the archive streams and asserts the spooled file is removed once all streams close
+func buildTestArchiveStreams(t *testing.T, format string, data []byte) []*archiveMemberStream {
+	t.Helper()
+	src := &models.SourceIntegration{}
+	src.IntegrationType = models.IntegrationTypeAWS3
+	s3Object := &S3ObjectInfo{
+		S3Bucket:     "bucket",
+		S3ObjectKey:  "key",
+		S3ObjectSize: int64(len(data)),
+	}
+	streams, err := buildArchiveStreams(src, s3Object, nil, format, bytes.NewReader(data))
+	require.NoError(t, err)
+	if len(streams) == 0 {
+		return nil
+	}
+	archive := streams[0].archive
+	t.Cleanup(func() {
+		for _, s := range streams {
+			require.NoError(t, s.Close())
+		}
+		_, err := os.Stat(archive.Name())
+		require.True(t, os.IsNotExist(err), "archive file was not removed")
+	})
+	return streams
+}
+
+func readStream(t *testing.T, s logstream.Stream) (lines []string) {
+	t.Helper()
+	for {
+		line := s.Next()
+		if line == nil {
+			break
+		}
+		lines = append(lines, string(line))
+	}
+	require.NoError(t, s.Err())
+	return lines
+}
+
+func gzipData(t *testing.T, data string) []byte {
+	t.Helper()
+	buf := bytes.Buffer{}
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+// countingReader counts the reads of the underlying object
+type countingReader struct {
+	*bytes.Reader
+	reads  int
+	closed bool
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	r.reads++
+	return r.Reader.Read(p)
+}
+
+func (r *countingReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func newTestObjectStream(data []byte) (*objectStream, *countingReader) {
+	src := &models.SourceIntegration{}
+	src.IntegrationType = models.IntegrationTypeAWS3
+	r := &countingReader{Reader: bytes.NewReader(data)}
+	return &objectStream{
+		src:      src,
+		s3Object: &S3ObjectInfo{S3Bucket: "bucket", S3ObjectKey: "key"},
+		r:        r,
+	}, r
+}
+
+func TestObjectStreamSniffsOnFirstRead(t *testing.T) {
+	assert := require.New(t)
+	stream, r := newTestObjectStream([]byte("foo\nbar\n"))
+	assert.Zero(r.reads, "object was read before the stream")
+
+	members, err := stream.memberStreams()
+	assert.NoError(err)
+	assert.Nil(members)
+	assert.Equal([]string{"foo", "bar"}, readStream(t, stream))
+	assert.NoError(stream.Close())
+	assert.True(r.closed)
+}
+
+func TestObjectStreamArchive(t *testing.T) {
+	assert := require.New(t)
+	buf := bytes.Buffer{}
+	zw := zip.NewWriter(&buf)
+	for name, data := range map[string]string{"foo.log": "foo\nbar\n", "baz.log": "baz\n"} {
+		w, err := zw.Create(name)
+		assert.NoError(err)
+		_, err = w.Write([]byte(data))
+		assert.NoError(err)
+	}
+	assert.NoError(zw.Close())
+
+	stream, r := newTestObjectStream(buf.Bytes())
+	assert.Zero(r.reads, "object was read before the stream")
+
+	members, err := stream.memberStreams()
+	assert.NoError(err)
+	assert.Len(members, 2)
+	// The download is released once the archive is spooled
+	assert.True(r.closed)
+	lines := map[string][]string{}
+	for _, member := range members {
+		assert.Equal("key", member.S3ObjectKey)
+		lines[member.ArchiveMember] = readStream(t, member.Stream)
+		assert.NoError(member.Closer.Close())
+	}
+	assert.Equal(map[string][]string{
+		"foo.log": {"foo", "bar"},
+		"baz.log": {"baz"},
+	}, lines)
+	// Archive members are not read from the object stream
+	assert.Nil(stream.Next())
+	assert.Error(stream.Err())
+	assert.NoError(stream.Close())
+}
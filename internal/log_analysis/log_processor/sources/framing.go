@@ -0,0 +1,66 @@
+package sources
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// resolveFraming returns the framing to use for splitting an S3 object into log entries.
+// A framing set on the matching S3 prefix takes precedence over the framing declared by the prefix's log types.
+// It returns an error if the log types of a prefix declare different framings, the prefix should set a framing then.
+func resolveFraming(ctx context.Context, resolver logtypes.Resolver, src *models.SourceIntegration,
+	key string) (*pantherlog.FramingConfig, error) {
+
+	if src.IntegrationType != models.IntegrationTypeAWS3 {
+		return nil, nil
+	}
+	m, matched := src.S3PrefixLogTypes.LongestPrefixMatch(key)
+	if !matched {
+		return nil, nil
+	}
+	if m.Framing != nil {
+		return m.Framing, nil
+	}
+	if resolver == nil {
+		return nil, nil
+	}
+	var framing *pantherlog.FramingConfig
+	resolved := false
+	for _, logType := range m.LogTypes {
+		entry, err := resolver.Resolve(ctx, logType)
+		if err != nil || entry == nil {
+			// The classifier will fail for this log type, we do not need to handle it here
+			continue
+		}
+		f := logtypes.Framing(entry)
+		if resolved && !f.Equal(framing) {
+			return nil, errors.Errorf("log types %q of prefix %q in source %s have conflicting framings, set a framing for the prefix",
+				m.LogTypes, m.S3Prefix, src.IntegrationID)
+		}
+		framing, resolved = f, true
+	}
+	return framing, nil
+}
@@ -0,0 +1,83 @@
+package sources
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+func TestResolveFraming(t *testing.T) {
+	jsonFraming := &pantherlog.FramingConfig{Mode: pantherlog.FramingJSON}
+	regexFraming := &pantherlog.FramingConfig{Mode: pantherlog.FramingRegex, StartPattern: "^BEGIN"}
+	resolver := logtypes.ResolverFunc(func(_ context.Context, name string) (logtypes.Entry, error) {
+		config := logtypes.Config{
+			Name:         name,
+			Description:  "Test log type",
+			ReferenceURL: "-",
+			Schema: &struct {
+				Foo string `json:"foo" description:"foo"`
+			}{},
+			NewParser: pantherlog.FactoryFunc(nil),
+		}
+		switch name {
+		case "Framed.JSON":
+			config.Framing = jsonFraming
+		case "Framed.Regex":
+			config.Framing = regexFraming
+		case "Missing":
+			return nil, nil
+		}
+		entry, err := config.BuildEntry()
+		require.NoError(t, err)
+		return entry, nil
+	})
+	src := &models.SourceIntegration{
+		SourceIntegrationMetadata: models.SourceIntegrationMetadata{
+			IntegrationType: models.IntegrationTypeAWS3,
+			S3PrefixLogTypes: models.S3PrefixLogtypes{
+				{S3Prefix: "json/", LogTypes: []string{"Missing", "Framed.JSON"}},
+				{S3Prefix: "mixed/", LogTypes: []string{"Framed.JSON", "Framed.Regex"}},
+				{S3Prefix: "lines/", LogTypes: []string{"Plain"}},
+				{S3Prefix: "override/", LogTypes: []string{"Framed.JSON"}, Framing: regexFraming},
+			},
+		},
+	}
+	ctx := context.Background()
+	assertFraming := func(expect *pantherlog.FramingConfig, resolver logtypes.Resolver, key string) {
+		t.Helper()
+		framing, err := resolveFraming(ctx, resolver, src, key)
+		require.NoError(t, err)
+		require.Equal(t, expect, framing)
+	}
+	assertFraming(jsonFraming, resolver, "json/foo.log")
+	assertFraming(nil, resolver, "lines/foo.log")
+	assertFraming(regexFraming, resolver, "override/foo.log")
+	assertFraming(nil, resolver, "unknown/foo.log")
+	assertFraming(nil, nil, "json/foo.log")
+	// Log types with different framings need a framing set on the prefix
+	_, err := resolveFraming(ctx, resolver, src, "mixed/foo.log")
+	require.Error(t, err)
+}
@@ -19,7 +19,9 @@ package sources
  */
 
 import (
+	"bufio"
 	"context"
+	"io"
 	"net/url"
 	"path"
 	"regexp"
@@ -37,6 +39,8 @@ import (
 
 	"github.com/panther-labs/panther/api/lambda/source/models"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/s3pipe"
 	"github.com/panther-labs/panther/pkg/stringset"
@@ -50,8 +54,9 @@ const (
 	cloudTrailValidationMessage = "CloudTrail validation message."
 )
 
-// ReadSnsMessage reads incoming messages containing SNS notifications and returns a slice of DataStream items
-func ReadSnsMessage(ctx context.Context, message string) (result []*common.DataStream, err error) {
+// ReadSnsMessage reads incoming messages containing SNS notifications and returns a slice of DataStream items.
+// The resolver is used to look up the framing declared by the log types of each S3 object.
+func ReadSnsMessage(ctx context.Context, message string, resolver logtypes.Resolver) (result []*common.DataStream, err error) {
 	snsNotificationMessage := &SnsNotification{}
 	if err := jsoniter.UnmarshalFromString(message, snsNotificationMessage); err != nil {
 		return nil, err
@@ -59,7 +64,7 @@ func ReadSnsMessage(ctx context.Context, message string) (result []*common.DataS
 
 	switch snsNotificationMessage.Type {
 	case "Notification":
-		streams, err := handleNotificationMessage(ctx, snsNotificationMessage)
+		streams, err := handleNotificationMessage(ctx, snsNotificationMessage, resolver)
 		if err != nil {
 			return nil, err
 		}
@@ -101,7 +106,8 @@ func ConfirmSubscription(notification *SnsNotification) (err error) {
 	return nil
 }
 
-func handleNotificationMessage(ctx context.Context, notification *SnsNotification) (result []*common.DataStream, err error) {
+func handleNotificationMessage(ctx context.Context, notification *SnsNotification,
+	resolver logtypes.Resolver) (result []*common.DataStream, err error) {
 	s3Objects, err := ParseNotification(notification.Message)
 	if err != nil {
 		return nil, err
@@ -110,25 +116,35 @@ func handleNotificationMessage(ctx context.Context, notification *SnsNotificatio
 		if shouldIgnoreS3Object(s3Object) {
 			continue
 		}
-		var dataStream *common.DataStream
-		dataStream, err = buildStream(ctx, s3Object)
+		var dataStreams []*common.DataStream
+		dataStreams, err = buildStreams(ctx, s3Object, resolver)
 		if err != nil {
-			return
-		}
-		if dataStream != nil {
-			result = append(result, dataStream)
+			// Release any resources held by the streams we already built (ie spooled archives)
+			closeStreams(result)
+			return nil, err
 		}
+		result = append(result, dataStreams...)
 	}
 	return result, err
 }
 
+func closeStreams(streams []*common.DataStream) {
+	for _, s := range streams {
+		if s.Closer != nil {
+			_ = s.Closer.Close()
+		}
+	}
+}
+
 func shouldIgnoreS3Object(s3Object *S3ObjectInfo) bool {
 	// We should ignore S3 objects that end in `/`.
 	// These objects are used in S3 to define a "folder" and do not contain data.
 	return strings.HasSuffix(s3Object.S3ObjectKey, "/")
 }
 
-func buildStream(ctx context.Context, s3Object *S3ObjectInfo) (*common.DataStream, error) {
+// buildStreams creates the data streams for an S3 object.
+// Plain objects produce a single stream while zip/tar archives produce a separate stream for each member file.
+func buildStreams(ctx context.Context, s3Object *S3ObjectInfo, resolver logtypes.Resolver) ([]*common.DataStream, error) {
 	key, bucket := s3Object.S3ObjectKey, s3Object.S3Bucket
 	s3Client, src, err := getS3Client(bucket, key)
 	if err != nil {
@@ -142,37 +158,175 @@ func buildStream(ctx context.Context, s3Object *S3ObjectInfo) (*common.DataStrea
 		return nil, nil
 	}
 
+	framing, err := resolveFraming(ctx, resolver, src, key)
+	if err != nil {
+		return nil, err
+	}
+
 	downloader := s3pipe.Downloader{
 		S3:       s3Client,
 		PartSize: calculatePartSize(s3Object.S3ObjectSize),
 	}
-	// gzip streams are transparently uncompressed
+
+	// gzip, bzip2 and zstd streams are transparently uncompressed
 	r := downloader.Download(ctx, &s3.GetObjectInput{
 		Bucket: &bucket,
 		Key:    &key,
 	})
-	var stream logstream.Stream
+
+	// The content is sniffed when the stream is first processed, so that downloads only start at that point.
+	stream := &objectStream{
+		src:      src,
+		s3Object: s3Object,
+		framing:  framing,
+		r:        r,
+	}
+	return []*common.DataStream{
+		{
+			Stream:       stream,
+			Closer:       stream,
+			Source:       src,
+			S3Bucket:     s3Object.S3Bucket,
+			S3ObjectKey:  s3Object.S3ObjectKey,
+			S3ObjectSize: s3Object.S3ObjectSize,
+			Members:      stream.memberStreams,
+		},
+	}, nil
+}
+
+// objectStream is a logstream.Stream over an S3 object that sniffs the object content when it is first read.
+// Plain objects are read as a single log stream while the member files of zip/tar archives are read as separate
+// data streams (see memberStreams).
+type objectStream struct {
+	src      *models.SourceIntegration
+	s3Object *S3ObjectInfo
+	framing  *pantherlog.FramingConfig
+	r        io.ReadCloser
+
+	opened  bool
+	stream  logstream.Stream
+	members []*archiveMemberStream
+	err     error
+}
+
+var _ logstream.Stream = (*objectStream)(nil)
+
+// Next implements logstream.Stream
+func (s *objectStream) Next() []byte {
+	if s.openOnce(); s.stream == nil {
+		if s.err == nil && s.members != nil {
+			s.err = errors.New("archive members must be read as separate streams")
+		}
+		return nil
+	}
+	return s.stream.Next()
+}
+
+// Err implements logstream.Stream
+func (s *objectStream) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.stream != nil {
+		return s.stream.Err()
+	}
+	return nil
+}
+
+// memberStreams returns a separate data stream for each member file if the object is a zip/tar archive.
+// It returns nil for plain objects.
+func (s *objectStream) memberStreams() ([]*common.DataStream, error) {
+	if s.openOnce(); s.err != nil || s.members == nil {
+		return nil, s.err
+	}
+	streams := make([]*common.DataStream, 0, len(s.members))
+	for _, member := range s.members {
+		streams = append(streams, &common.DataStream{
+			Stream:            member,
+			Closer:            member,
+			Source:            s.src,
+			S3Bucket:          s.s3Object.S3Bucket,
+			S3ObjectKey:       s.s3Object.S3ObjectKey,
+			S3ObjectSize:      s.s3Object.S3ObjectSize,
+			ArchiveMember:     member.name,
+			ArchiveMemberSize: member.size,
+		})
+	}
+	return streams, nil
+}
+
+func (s *objectStream) openOnce() {
+	if !s.opened {
+		s.opened = true
+		s.err = s.open()
+	}
+}
+
+// open sniffs the object content to detect archives
+func (s *objectStream) open() error {
+	bucket, key := s.s3Object.S3Bucket, s.s3Object.S3ObjectKey
+	// The buffer is small so that reads by the log stream bypass it once the peeked bytes are consumed.
+	br := bufio.NewReaderSize(s.r, archiveSniffSize)
+	head, err := br.Peek(archiveSniffSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return errors.Wrapf(err, "failed to read s3://%s/%s", bucket, key)
+	}
+	format := detectArchiveFormat(head)
+	if format == archiveFormatNone {
+		stream, err := newLogStream(s.src, key, s.framing, br)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to read s3://%s/%s", bucket, key)
+		}
+		s.stream = stream
+		return nil
+	}
+
+	// The whole archive is spooled to local storage so we are done with the download
+	defer s.closeDownload()
+	members, err := buildArchiveStreams(s.src, s.s3Object, s.framing, format, br)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to extract s3://%s/%s", bucket, key)
+	}
+	// Empty archives are not mistaken for plain objects
+	s.members = append(make([]*archiveMemberStream, 0, len(members)), members...)
+	return nil
+}
+
+func (s *objectStream) closeDownload() (err error) {
+	if r := s.r; r != nil {
+		s.r = nil
+		err = r.Close()
+	}
+	return err
+}
+
+// Close implements io.Closer
+// It releases the download and any archive members that were not closed by their data streams.
+func (s *objectStream) Close() error {
+	for _, member := range s.members {
+		_ = member.Close()
+	}
+	s.members = nil
+	return s.closeDownload()
+}
+
+// newLogStream creates a log entry stream for a file.
+// If framing is not nil it is used to split the file into log entries.
+func newLogStream(src *models.SourceIntegration, key string, framing *pantherlog.FramingConfig, r io.Reader) (logstream.Stream, error) {
+	if framing != nil {
+		return logstream.NewFramedStream(framing, r, DownloadMinPartSize)
+	}
 	switch src.IntegrationType {
 	case models.IntegrationTypeAWS3:
 		if isCloudTrailLog(key) && stringset.Contains(src.RequiredLogTypes(), "AWS.CloudTrail") {
-			zap.L().Debug("detected CloudTrail logs", zap.String("bucket", bucket), zap.String("key", key))
-			stream = logstream.NewJSONArrayStream(r, DownloadMinPartSize, "Records")
-		} else {
-			stream = logstream.NewLineStream(r, DownloadMinPartSize)
+			zap.L().Debug("detected CloudTrail logs", zap.String("bucket", src.S3Bucket), zap.String("key", key))
+			return logstream.NewJSONArrayStream(r, DownloadMinPartSize, "Records"), nil
 		}
+		return logstream.NewLineStream(r, DownloadMinPartSize), nil
 	default:
 		// Set the buffer size to something big to avoid multiple fill() calls if possible
-		stream = logstream.NewLineStream(r, DownloadMinPartSize)
+		return logstream.NewLineStream(r, DownloadMinPartSize), nil
 	}
-
-	return &common.DataStream{
-		Stream:       stream,
-		Closer:       r,
-		Source:       src,
-		S3Bucket:     s3Object.S3Bucket,
-		S3ObjectKey:  s3Object.S3ObjectKey,
-		S3ObjectSize: s3Object.S3ObjectSize,
-	}, nil
 }
 
 func calculatePartSize(size int64) int64 {
@@ -160,7 +160,7 @@ func TestHandleUnsupportedFileType(t *testing.T) {
 	}
 	s3Mock.On("GetObjectWithContext", mock.Anything, mock.Anything, mock.Anything).Return(getObjectOutput, nil)
 
-	dataStreams, err := ReadSnsMessage(context.TODO(), marshaledNotification)
+	dataStreams, err := ReadSnsMessage(context.TODO(), marshaledNotification, nil)
 	// Method shouldn't return error
 	require.NoError(t, err)
 	// Method should not return data stream
@@ -186,7 +186,7 @@ func TestHandleS3Folder(t *testing.T) {
 	marshaledNotification, err := jsoniter.MarshalToString(notification)
 	require.NoError(t, err)
 
-	dataStreams, err := ReadSnsMessage(context.TODO(), marshaledNotification)
+	dataStreams, err := ReadSnsMessage(context.TODO(), marshaledNotification, nil)
 	// Method shouldn't return error
 	require.NoError(t, err)
 	// Method should not return data stream
@@ -224,7 +224,7 @@ func TestHandleUnregisteredSource(t *testing.T) {
 	// Getting the list of available sources
 	lambdaMock.On("Invoke", mock.Anything).Return(lambdaOutput, nil).Once()
 
-	dataStreams, err := ReadSnsMessage(context.TODO(), marshaledNotification)
+	dataStreams, err := ReadSnsMessage(context.TODO(), marshaledNotification, nil)
 	// Method shouldn't return error
 	require.NoError(t, err)
 	// Method should not return data stream
@@ -68,7 +68,17 @@ func LoadSourceS3(bucketName, objectKey string) (*models.SourceIntegration, erro
 
 // BuildClassifier builds a classifier for a source
 func BuildClassifier(availableLogTypes []string, src *models.SourceIntegration, r logtypes.Resolver) (classification.ClassifierAPI, error) {
+	return BuildStickyClassifier(availableLogTypes, src, r, 0)
+}
+
+// BuildStickyClassifier builds a classifier for a source that locks the log types of the first learnLines classified lines.
+// See classification.NewStickyClassifier
+func BuildStickyClassifier(availableLogTypes []string, src *models.SourceIntegration, r logtypes.Resolver,
+	learnLines int) (classification.ClassifierAPI, error) {
+
 	parserIndex := map[string]pantherlog.LogParser{}
+	// Log types with a dispatcher share a single parser so that log entries are parsed once
+	dispatchedLogTypes := map[logtypes.Dispatcher][]string{}
 	for _, logType := range availableLogTypes {
 		entry, err := r.Resolve(context.TODO(), logType)
 		if err != nil {
@@ -78,13 +88,24 @@ func BuildClassifier(availableLogTypes []string, src *models.SourceIntegration,
 			zap.L().Warn("unresolved log type", zap.String("logType", logType), zap.String("sourceId", src.IntegrationID))
 			continue
 		}
+		if d := logtypes.DispatcherOf(entry); d != nil {
+			dispatchedLogTypes[d] = append(dispatchedLogTypes[d], logType)
+			continue
+		}
 		parser, err := entry.NewParser(nil)
 		if err != nil {
 			return nil, errors.WithMessagef(err, "failed to create %q parser", logType)
 		}
 		parserIndex[logType] = newSourceFieldsParser(src.IntegrationID, src.IntegrationLabel, parser)
 	}
-	return classification.NewClassifier(parserIndex), nil
+	for d, logTypes := range dispatchedLogTypes {
+		parser, err := d.NewDispatchParser(logTypes)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to create %q parser", d.Name())
+		}
+		parserIndex[d.Name()] = newSourceFieldsParser(src.IntegrationID, src.IntegrationLabel, parser)
+	}
+	return classification.NewStickyClassifier(parserIndex, learnLines), nil
 }
 
 func newSourceFieldsParser(id, label string, parser pantherlog.LogParser) pantherlog.LogParser {
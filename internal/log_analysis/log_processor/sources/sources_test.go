@@ -24,6 +24,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/suricatalogs"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
 )
 
@@ -43,3 +46,37 @@ func Test_BuildClassifier_NoLogTypes(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, "failed to classify log line", err.Error())
 }
+
+// Tests that log types with a dispatcher share a single parser so each record is parsed once
+func TestBuildClassifierDispatchedLogTypes(t *testing.T) {
+	src := &models.SourceIntegration{
+		SourceIntegrationMetadata: models.SourceIntegrationMetadata{
+			IntegrationID:    "integration-id",
+			IntegrationLabel: "integration-label",
+		},
+	}
+	logTypes := logtypes.CollectNames(suricatalogs.LogTypes())
+	c, err := BuildClassifier(logTypes, src, registry.NativeLogTypesResolver())
+	require.NoError(t, err)
+
+	samples := testutil.MustReadFileJSONLines("../parsers/suricatalogs/testdata/eve_samples.jsonl")
+	expect := map[string]uint64{}
+	for _, sample := range samples {
+		result, err := c.Classify(sample)
+		require.NoError(t, err)
+		require.Zero(t, result.NumMiss)
+		require.Len(t, result.Events, 1)
+		expect[result.Events[0].PantherLogType]++
+	}
+	// Stats are kept for the log types of the events
+	require.Len(t, c.ParserStats(), len(expect))
+	for logType, stats := range c.ParserStats() {
+		require.Equal(t, expect[logType], stats.EventCount, logType)
+	}
+
+	// Records of unknown event types are rejected by a single parser
+	result, err := c.Classify(`{"event_type":"quic","timestamp":"2020-06-05T14:41:00.122516+0000"}`)
+	require.Error(t, err)
+	require.Equal(t, 1, result.NumMiss)
+	require.Equal(t, suricatalogs.LogTypePrefix, result.ParserErrors[0].LogType)
+}
@@ -35,6 +35,9 @@ type SQSClassifier struct {
 	LoadSource  func(id string) (*models.SourceIntegration, error)
 	stats       classification.ClassifierStats
 	classifiers map[string]classification.ClassifierAPI
+
+	// StickyLines enables sticky classification for each source, see classification.NewStickyClassifier
+	StickyLines int
 }
 
 var _ classification.ClassifierAPI = (*SQSClassifier)(nil)
@@ -71,7 +74,7 @@ func (c *SQSClassifier) buildSourceClassifier(id string) (classification.Classif
 	if err != nil {
 		return nil, err
 	}
-	return BuildClassifier(src.SqsConfig.LogTypes, src, c.Resolver)
+	return BuildStickyClassifier(src.SqsConfig.LogTypes, src, c.Resolver, c.StickyLines)
 }
 
 func (c *SQSClassifier) Stats() *classification.ClassifierStats {
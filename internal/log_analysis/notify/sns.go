@@ -27,6 +27,8 @@ import (
 const (
 	logDataTypeAttributeName = "type"
 	logTypeAttributeName     = "id"
+	formatAttributeName      = "format"
+	formatParquet            = "parquet"
 )
 
 var (
@@ -45,3 +47,15 @@ func NewLogAnalysisSNSMessageAttributes(dataType pantherdb.DataType, logType str
 		},
 	}
 }
+
+// NewLogAnalysisParquetSNSMessageAttributes returns the attributes of notifications for Parquet files.
+// Parquet notifications carry an additional 'format' attribute so that subscribers reading JSON can filter them out.
+func NewLogAnalysisParquetSNSMessageAttributes(dataType pantherdb.DataType, logType string) map[string]*sns.MessageAttributeValue {
+	attributes := NewLogAnalysisSNSMessageAttributes(dataType, logType)
+	format := formatParquet
+	attributes[formatAttributeName] = &sns.MessageAttributeValue{
+		StringValue: &format,
+		DataType:    &messageAttributeDataType,
+	}
+	return attributes
+}
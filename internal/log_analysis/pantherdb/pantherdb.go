@@ -42,6 +42,10 @@ const (
 
 	TempDatabase            = "panther_temp"
 	TempDatabaseDescription = "Holds temporary tables used for processing tasks"
+
+	// DeadLettersTable holds the log lines that failed classification, it is stored in the LogProcessingDatabase
+	DeadLettersTable            = "panther_dead_letters"
+	DeadLettersTableDescription = "Holds log lines that Panther log processing failed to classify"
 )
 
 var Databases = map[string]string{
@@ -65,6 +69,8 @@ const (
 	RuleErrors DataType = "RuleErrors"
 	// CloudSecurity represents CloudSecurity data processed by Panther
 	CloudSecurity DataType = "CloudSecurity"
+	// DeadLetters represents log lines that failed classification
+	DeadLetters DataType = "DeadLetters"
 )
 
 // Returns the datatype associated to this LogType
@@ -106,6 +112,8 @@ func DatabaseName(typ DataType) string {
 		return RuleErrorsDatabase
 	case CloudSecurity:
 		return CloudSecurityDatabase
+	case DeadLetters:
+		return LogProcessingDatabase
 	default:
 		panic("Unknow DataType " + typ)
 	}
@@ -34,19 +34,35 @@ type PantherConfig struct {
 }
 
 type Infra struct {
-	BaseLayerVersionArns               string   `yaml:"BaseLayerVersionArns"`
-	LoadBalancerSecurityGroupCidr      string   `yaml:"LoadBalancerSecurityGroupCidr"`
-	LogProcessorLambdaMemorySize       int      `yaml:"LogProcessorLambdaMemorySize"`
-	LogProcessorLambdaSQSReadBatchSize string   `yaml:"LogProcessorLambdaSQSReadBatchSize"`
-	PipLayer                           []string `yaml:"PipLayer"`
-	KvTableBillingMode                 string   `yaml:"KvTableBillingMode"`
-	PythonLayerVersionArn              string   `yaml:"PythonLayerVersionArn"`
-	SecurityGroupID                    string   `yaml:"SecurityGroupID"`
-	SubnetOneID                        string   `yaml:"SubnetOneID"`
-	SubnetTwoID                        string   `yaml:"SubnetTwoID"`
-	SubnetOneIPRange                   string   `yaml:"SubnetOneIPRange"`
-	SubnetTwoIPRange                   string   `yaml:"SubnetTwoIPRange"`
-	VpcID                              string   `yaml:"VpcID"`
+	BaseLayerVersionArns               string            `yaml:"BaseLayerVersionArns"`
+	EnrichmentConfig                   string            `yaml:"EnrichmentConfig"`
+	GeoIPASNDatabase                   string            `yaml:"GeoIPASNDatabase"`
+	GeoIPCountryDatabase               string            `yaml:"GeoIPCountryDatabase"`
+	HTTPDestinationHeaders             map[string]string `yaml:"HTTPDestinationHeaders"`
+	HTTPDestinationURL                 string            `yaml:"HTTPDestinationURL"`
+	KafkaDestinationBrokers            []string          `yaml:"KafkaDestinationBrokers"`
+	KafkaDestinationTLS                bool              `yaml:"KafkaDestinationTLS"`
+	KafkaDestinationTopic              string            `yaml:"KafkaDestinationTopic"`
+	LoadBalancerSecurityGroupCidr      string            `yaml:"LoadBalancerSecurityGroupCidr"`
+	LogProcessorDestinations           []string          `yaml:"LogProcessorDestinations"`
+	LogProcessorFanOutBufferSize       int               `yaml:"LogProcessorFanOutBufferSize"`
+	LogProcessorLambdaMemorySize       int               `yaml:"LogProcessorLambdaMemorySize"`
+	LogProcessorLambdaSQSReadBatchSize string            `yaml:"LogProcessorLambdaSQSReadBatchSize"`
+	LogProcessorRequiredDestinations   []string          `yaml:"LogProcessorRequiredDestinations"`
+	MaskingConfig                      string            `yaml:"MaskingConfig"`
+	MaskingHashKey                     string            `yaml:"MaskingHashKey"`
+	ParquetLogTypes                    []string          `yaml:"ParquetLogTypes"`
+	PipLayer                           []string          `yaml:"PipLayer"`
+	KvTableBillingMode                 string            `yaml:"KvTableBillingMode"`
+	PythonLayerVersionArn              string            `yaml:"PythonLayerVersionArn"`
+	SecurityGroupID                    string            `yaml:"SecurityGroupID"`
+	StickyClassifierLines              int               `yaml:"StickyClassifierLines"`
+	StoreDeadLetters                   bool              `yaml:"StoreDeadLetters"`
+	SubnetOneID                        string            `yaml:"SubnetOneID"`
+	SubnetTwoID                        string            `yaml:"SubnetTwoID"`
+	SubnetOneIPRange                   string            `yaml:"SubnetOneIPRange"`
+	SubnetTwoIPRange                   string            `yaml:"SubnetTwoIPRange"`
+	VpcID                              string            `yaml:"VpcID"`
 }
 
 type Monitoring struct {
@@ -20,9 +20,11 @@ package deploy
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -536,27 +538,85 @@ func deployDashboardStack(bucket string) error {
 }
 
 func deployLogAnalysisStack(settings *PantherConfig, outputs map[string]string) error {
-	_, err := deployTemplate(cfnstacks.LogAnalysisTemplate, outputs["SourceBucket"], cfnstacks.LogAnalysis, map[string]string{
+	enrichmentBucket, err := s3URLBucket(settings.Infra.EnrichmentConfig)
+	if err != nil {
+		return fmt.Errorf("invalid EnrichmentConfig: %v", err)
+	}
+	maskingBucket, err := s3URLBucket(settings.Infra.MaskingConfig)
+	if err != nil {
+		return fmt.Errorf("invalid MaskingConfig: %v", err)
+	}
+	fanOutBufferSize := settings.Infra.LogProcessorFanOutBufferSize
+	if fanOutBufferSize <= 0 {
+		fanOutBufferSize = 1000
+	}
+	destinations := settings.Infra.LogProcessorDestinations
+	if len(destinations) == 0 {
+		destinations = []string{"s3"}
+	}
+	requiredDestinations := settings.Infra.LogProcessorRequiredDestinations
+	if requiredDestinations == nil {
+		requiredDestinations = []string{"s3"}
+	}
+	httpHeaders := make([]string, 0, len(settings.Infra.HTTPDestinationHeaders))
+	for name, value := range settings.Infra.HTTPDestinationHeaders {
+		httpHeaders = append(httpHeaders, name+":"+value)
+	}
+	sort.Strings(httpHeaders)
+	_, err = deployTemplate(cfnstacks.LogAnalysisTemplate, outputs["SourceBucket"], cfnstacks.LogAnalysis, map[string]string{
 		"AlarmTopicArn":                      outputs["AlarmTopicArn"],
 		"AthenaResultsBucket":                outputs["AthenaResultsBucket"],
 		"AthenaWorkGroup":                    outputs["AthenaWorkGroup"],
 		"CloudWatchLogRetentionDays":         strconv.Itoa(settings.Monitoring.CloudWatchLogRetentionDays),
 		"CustomResourceVersion":              customResourceVersion(),
 		"Debug":                              strconv.FormatBool(settings.Monitoring.Debug),
+		"EnrichmentBucket":                   enrichmentBucket,
+		"EnrichmentConfig":                   settings.Infra.EnrichmentConfig,
+		"GeoIPASNDatabase":                   settings.Infra.GeoIPASNDatabase,
+		"GeoIPCountryDatabase":               settings.Infra.GeoIPCountryDatabase,
+		"HTTPDestinationHeaders":             strings.Join(httpHeaders, ","),
+		"HTTPDestinationURL":                 settings.Infra.HTTPDestinationURL,
 		"InputDataBucket":                    outputs["InputDataBucket"],
 		"InputDataTopicArn":                  outputs["InputDataTopicArn"],
+		"KafkaDestinationBrokers":            strings.Join(settings.Infra.KafkaDestinationBrokers, ","),
+		"KafkaDestinationTLS":                strconv.FormatBool(settings.Infra.KafkaDestinationTLS),
+		"KafkaDestinationTopic":              settings.Infra.KafkaDestinationTopic,
 		"LayerVersionArns":                   settings.Infra.BaseLayerVersionArns,
+		"LogProcessorDestinations":           strings.Join(destinations, ","),
+		"LogProcessorFanOutBufferSize":       strconv.Itoa(fanOutBufferSize),
 		"LogProcessorLambdaMemorySize":       strconv.Itoa(settings.Infra.LogProcessorLambdaMemorySize),
 		"LogProcessorLambdaSQSReadBatchSize": settings.Infra.LogProcessorLambdaSQSReadBatchSize,
+		"LogProcessorRequiredDestinations":   strings.Join(requiredDestinations, ","),
+		"MaskingBucket":                      maskingBucket,
+		"MaskingConfig":                      settings.Infra.MaskingConfig,
+		"MaskingHashKey":                     settings.Infra.MaskingHashKey,
+		"ParquetLogTypes":                    strings.Join(settings.Infra.ParquetLogTypes, ","),
 		"ProcessedDataBucket":                outputs["ProcessedDataBucket"],
 		"ProcessedDataTopicArn":              outputs["ProcessedDataTopicArn"],
 		"PythonLayerVersionArn":              outputs["PythonLayerVersionArn"],
 		"SqsKeyId":                           outputs["QueueEncryptionKeyId"],
+		"StickyClassifierLines":              strconv.Itoa(settings.Infra.StickyClassifierLines),
+		"StoreDeadLetters":                   strconv.FormatBool(settings.Infra.StoreDeadLetters),
 		"TracingMode":                        settings.Monitoring.TracingMode,
 	})
 	return err
 }
 
+// s3URLBucket returns the bucket name of an S3 URL (s3://bucket/key), empty URLs return an empty bucket
+func s3URLBucket(s3URL string) (string, error) {
+	if s3URL == "" {
+		return "", nil
+	}
+	u, err := url.Parse(s3URL)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "s3" || u.Host == "" {
+		return "", fmt.Errorf("%q is not an S3 URL", s3URL)
+	}
+	return u.Host, nil
+}
+
 func deployOnboardStack(settings *PantherConfig, outputs map[string]string) error {
 	var err error
 	if settings.Setup.OnboardSelf {